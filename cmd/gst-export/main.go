@@ -0,0 +1,84 @@
+// gst-export writes a company's monthly GSTR-1 and GSTR-3B in the GST portal's JSON schema,
+// then reconciles the returned tax to the GST ledger accounts. It exits non-zero when the
+// return does not reconcile or has data issues (invalid GSTINs, lines without HSN codes),
+// so the files are not uploaded unchecked.
+//
+// Usage: go run ./cmd/gst-export -company 1000 -period 2026-02 [-out dir]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"accounting-agent/internal/core"
+	"accounting-agent/internal/db"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	companyCode := flag.String("company", "1000", "company code")
+	period := flag.String("period", time.Now().AddDate(0, -1, 0).Format("2006-01"), "return month, YYYY-MM")
+	outDir := flag.String("out", ".", "directory to write the JSON files to")
+	flag.Parse()
+
+	month, err := time.Parse("2006-01", *period)
+	if err != nil {
+		log.Fatalf("invalid -period %q (expected YYYY-MM)", *period)
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx)
+	if err != nil {
+		log.Fatalf("database: %v", err)
+	}
+	defer pool.Close()
+
+	svc := core.NewGSTReturnService(pool, core.NewRuleEngine(pool))
+	ret, err := svc.GetReturn(ctx, *companyCode, month.Year(), int(month.Month()))
+	if err != nil {
+		log.Fatalf("build GST return: %v", err)
+	}
+
+	writeJSON(filepath.Join(*outDir, fmt.Sprintf("GSTR1_%s_%s.json", ret.GSTIN, ret.Period)), ret.GSTR1)
+	writeJSON(filepath.Join(*outDir, fmt.Sprintf("GSTR3B_%s_%s.json", ret.GSTIN, ret.Period)), ret.GSTR3B)
+
+	fmt.Printf("\nReconciliation %s to %s\n", ret.FromDate, ret.ToDate)
+	fmt.Printf("%-7s %-5s %-8s %14s %14s %14s\n", "", "", "Account", "Return", "Ledger", "Difference")
+	for _, l := range ret.Reconciliation {
+		flagged := ""
+		if !l.Difference.IsZero() {
+			flagged = "  MISMATCH"
+		}
+		fmt.Printf("%-7s %-5s %-8s %14s %14s %14s%s\n", l.Direction, l.Component, l.AccountCode,
+			l.ReturnTax.StringFixed(2), l.LedgerTax.StringFixed(2), l.Difference.StringFixed(2), flagged)
+	}
+	for _, issue := range ret.Issues {
+		fmt.Printf("ISSUE: %s\n", issue)
+	}
+
+	if !ret.Reconciled || len(ret.Issues) > 0 {
+		fmt.Println("\nGST return needs attention before filing.")
+		os.Exit(1)
+	}
+	fmt.Println("\nGST return reconciled to the ledger.")
+}
+
+func writeJSON(path string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("encode %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Fatalf("write %s: %v", path, err)
+	}
+	log.Printf("wrote %s", path)
+}
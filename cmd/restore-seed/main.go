@@ -1,5 +1,7 @@
 // restore-seed is a one-shot tool to restore the live database seed data.
 // Run it when the chart of accounts or company data has been accidentally wiped.
+// Seed accounts are re-created or reactivated; accounts added since are kept — retire
+// them with the chart of accounts API instead.
 //
 // Usage: go run ./cmd/restore-seed
 package main
@@ -45,16 +47,6 @@ func main() {
 		log.Fatalf("Failed to clear journal data: %v", err)
 	}
 
-	log.Println("Removing test accounts...")
-	_, err = tx.Exec(ctx, `
-		DELETE FROM accounts WHERE company_id IN (
-			SELECT id FROM companies WHERE company_code = '1000'
-		);
-	`)
-	if err != nil {
-		log.Fatalf("Failed to delete test accounts: %v", err)
-	}
-
 	log.Println("Restoring company...")
 	_, err = tx.Exec(ctx, `
 		INSERT INTO companies (company_code, name, base_currency)
@@ -92,7 +84,17 @@ func main() {
 		WHERE c.company_code = '1000'
 		ON CONFLICT (company_id, code) DO UPDATE
 		  SET name = EXCLUDED.name,
-		      type = EXCLUDED.type;
+		      type = EXCLUDED.type,
+		      is_active = true,
+		      posting_blocked = false;
+
+		UPDATE accounts a SET is_cash = true
+		FROM companies c
+		WHERE c.id = a.company_id AND c.company_code = '1000' AND a.code IN ('1000', '1100');
+
+		UPDATE accounts a SET manual_posting_blocked = true
+		FROM companies c
+		WHERE c.id = a.company_id AND c.company_code = '1000' AND a.code IN ('1200', '1400', '2000');
 	`)
 	if err != nil {
 		log.Fatalf("Failed to restore accounts: %v", err)
@@ -28,12 +28,28 @@ func main() {
 	docService := core.NewDocumentService(pool)
 	ledger := core.NewLedger(pool, docService)
 	ruleEngine := core.NewRuleEngine(pool)
-	orderService := core.NewOrderService(pool, ruleEngine)
+	taxEngine := core.NewTaxEngine(pool)
+	orderService := core.NewOrderService(pool, ruleEngine, taxEngine)
 	inventoryService := core.NewInventoryService(pool, ruleEngine)
 	reportingService := core.NewReportingService(pool)
 	userService := core.NewUserService(pool)
 	vendorService := core.NewVendorService(pool)
-	purchaseOrderService := core.NewPurchaseOrderService(pool)
+	purchaseOrderService := core.NewPurchaseOrderService(pool, ruleEngine, taxEngine)
+	replenishmentService := core.NewReplenishmentService(pool)
+	uomService := core.NewUoMService(pool)
+	landedCostService := core.NewLandedCostService(pool, ruleEngine)
+	vendorBillService := core.NewVendorBillService(pool, ruleEngine, taxEngine)
+	paymentRunService := core.NewPaymentRunService(pool, ruleEngine)
+	purchaseReturnService := core.NewPurchaseReturnService(pool, ruleEngine)
+	tdsService := core.NewTDSService(pool)
+	gstReturnService := core.NewGSTReturnService(pool, ruleEngine)
+	// No IRP (GSP) credentials are configured: IRNs come from the local stub and are not
+	// registered with the government.
+	eInvoiceService := core.NewEInvoiceService(pool, orderService, core.NewStubIRPClient())
+	accountService := core.NewAccountService(pool)
+	journalService := core.NewJournalService(pool)
+	budgetService := core.NewBudgetService(pool)
+	dimensionService := core.NewDimensionService(pool)
 
 	apiKey := os.Getenv("OPENAI_API_KEY")
 	if apiKey == "" {
@@ -41,7 +57,7 @@ func main() {
 	}
 	agent := ai.NewAgent(apiKey)
 
-	svc := app.NewAppService(pool, ledger, docService, orderService, inventoryService, reportingService, userService, vendorService, purchaseOrderService, agent)
+	svc := app.NewAppService(pool, ledger, docService, orderService, inventoryService, reportingService, userService, vendorService, purchaseOrderService, replenishmentService, uomService, landedCostService, vendorBillService, paymentRunService, purchaseReturnService, tdsService, taxEngine, gstReturnService, eInvoiceService, accountService, journalService, budgetService, dimensionService, agent)
 
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
@@ -51,7 +51,7 @@ func main() {
 `
 
 	fmt.Printf("INTERPRETING EVENT: %s\n", event)
-	response, err := agent.InterpretEvent(ctx, event, chartOfAccounts, documentTypes, company)
+	response, err := agent.InterpretEvent(ctx, event, chartOfAccounts, documentTypes, "(none)", company)
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
@@ -7,14 +7,24 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"accounting-agent/internal/app"
 	"accounting-agent/internal/core"
 )
 
 // Run executes a one-shot CLI command and exits.
-// args is os.Args[1:] — the first element is the subcommand name.
+// args is os.Args[1:] — the first element is the subcommand name. The report commands
+// accept --format csv|xlsx|pdf|text and write the report to stdout.
 func Run(ctx context.Context, svc app.ApplicationService, args []string) {
+	args, format := extractFormat(args)
+	if len(args) == 0 {
+		log.Fatal("Usage: app <command> [args] [--format csv|xlsx|pdf|text]")
+	}
+	if format != "" && !core.IsReportFormat(format) {
+		log.Fatalf("Unknown format %q: use csv, xlsx, pdf or text", format)
+	}
+
 	company, err := svc.LoadDefaultCompany(ctx)
 	if err != nil {
 		log.Fatalf("Failed to load company: %v", err)
@@ -59,14 +69,139 @@ func Run(ctx context.Context, svc app.ApplicationService, args []string) {
 		fmt.Println("Transaction Committed.")
 
 	case "bal", "balances":
+		if format != "" {
+			writeReport(svc.ExportTrialBalance(ctx, company.CompanyCode, format))
+			return
+		}
 		result, err := svc.GetTrialBalance(ctx, company.CompanyCode)
 		if err != nil {
 			log.Fatalf("Failed to get balances: %v", err)
 		}
 		printTrialBalance(result)
 
+	case "pl":
+		now := time.Now()
+		year, month := now.Year(), int(now.Month())
+		if len(args) > 1 {
+			t, err := time.Parse("2006-01", args[1])
+			if err != nil {
+				log.Fatal("Usage: app pl [YYYY-MM] [--format csv|xlsx|pdf|text]")
+			}
+			year, month = t.Year(), int(t.Month())
+		}
+		writeReport(svc.ExportProfitAndLoss(ctx, company.CompanyCode, year, month, formatOrText(format)))
+
+	case "bs":
+		asOfDate := ""
+		if len(args) > 1 {
+			asOfDate = args[1]
+		}
+		writeReport(svc.ExportBalanceSheet(ctx, company.CompanyCode, asOfDate, formatOrText(format)))
+
+	case "statement", "stmt":
+		if len(args) < 2 {
+			log.Fatal("Usage: app statement <account> [from] [to] [--format csv|xlsx|pdf|text]")
+		}
+		var from, to string
+		if len(args) > 2 {
+			from = args[2]
+		}
+		if len(args) > 3 {
+			to = args[3]
+		}
+		writeReport(svc.ExportAccountStatement(ctx, company.CompanyCode, args[1], from, to, formatOrText(format)))
+
+	case "budget-import":
+		if len(args) < 3 {
+			log.Fatal("Usage: app budget-import <budget> <file.csv> [--replace]")
+		}
+		replace := len(args) > 3 && args[3] == "--replace"
+		f, err := os.Open(args[2])
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", args[2], err)
+		}
+		defer f.Close()
+		n, err := svc.ImportBudgetCSV(ctx, company.CompanyCode, args[1], f, replace)
+		if err != nil {
+			log.Fatalf("Budget import failed: %v", err)
+		}
+		fmt.Printf("Imported %d budget lines into %s.\n", n, strings.ToUpper(args[1]))
+
+	case "budget-vs-actual", "bva":
+		req := app.BudgetVsActualRequest{CompanyCode: company.CompanyCode}
+		if len(args) > 1 {
+			req.Period = args[1]
+		}
+		if len(args) > 2 {
+			req.BudgetCode = args[2]
+		}
+		report, err := svc.GetBudgetVsActual(ctx, req)
+		if err != nil {
+			log.Fatalf("Failed to get budget vs actual: %v", err)
+		}
+		printBudgetVsActual(report)
+
+	case "dimensions", "dims":
+		dims, err := svc.ListDimensions(ctx, company.CompanyCode)
+		if err != nil {
+			log.Fatalf("Failed to list dimensions: %v", err)
+		}
+		printDimensions(dims)
+
+	case "pl-by":
+		if len(args) < 2 {
+			log.Fatal("Usage: app pl-by <dimension> [period] [DIMENSION:VALUE,...]")
+		}
+		req := app.DimensionReportRequest{CompanyCode: company.CompanyCode, Breakdown: args[1]}
+		if len(args) > 2 {
+			req.Period = args[2]
+		}
+		if len(args) > 3 {
+			req.Filter = args[3]
+		}
+		report, err := svc.GetDimensionProfitAndLoss(ctx, req)
+		if err != nil {
+			log.Fatalf("Failed to get P&L by dimension: %v", err)
+		}
+		printDimensionPL(report)
+
 	default:
-		log.Fatalf("Unknown command: %s\nAvailable: propose, validate, commit, bal", args[0])
+		log.Fatalf("Unknown command: %s\nAvailable: propose, validate, commit, bal, pl, bs, statement, budget-import, budget-vs-actual, dimensions, pl-by", args[0])
+	}
+}
+
+// extractFormat removes a --format value (as "--format x" or "--format=x") from args.
+func extractFormat(args []string) ([]string, string) {
+	var rest []string
+	format := ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case strings.HasPrefix(args[i], "--format="):
+			format = strings.TrimPrefix(args[i], "--format=")
+		case args[i] == "--format" && i+1 < len(args):
+			format = args[i+1]
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, format
+}
+
+func formatOrText(format string) string {
+	if format == "" {
+		return core.ReportFormatText
+	}
+	return format
+}
+
+// writeReport writes an exported report to stdout.
+func writeReport(file *core.ReportFile, err error) {
+	if err != nil {
+		log.Fatalf("Failed to export report: %v", err)
+	}
+	if _, err := os.Stdout.Write(file.Content); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
 	}
 }
 
@@ -84,3 +219,95 @@ func printTrialBalance(result *app.TrialBalanceResult) {
 	}
 	fmt.Println(strings.Repeat("=", 62))
 }
+
+func printBudgetVsActual(report *core.BudgetVsActualReport) {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 96))
+	fmt.Printf("  BUDGET VS ACTUAL — %s (%s, FY %s)\n", report.BudgetName, report.BudgetCode, report.FinancialYear)
+	fmt.Printf("  Company  : %s\n", report.CompanyCode)
+	fmt.Printf("  Period   : %s (%s to %s), threshold %s%%\n",
+		report.Period.Label, report.Period.FromDate, report.Period.ToDate, report.ThresholdPct.String())
+	fmt.Println(strings.Repeat("=", 96))
+	fmt.Printf("  %-10s %-28s %14s %14s %14s %9s %-8s\n", "CODE", "NAME", "BUDGET", "ACTUAL", "VARIANCE", "VAR %", "STATUS")
+	fmt.Println(strings.Repeat("-", 96))
+	row := func(l core.BudgetVsActualLine) {
+		pct := "—"
+		if l.VariancePct != nil {
+			pct = l.VariancePct.StringFixed(2)
+		}
+		fmt.Printf("  %-10s %-28s %14s %14s %14s %9s %-8s\n", l.AccountCode, l.AccountName,
+			l.Budget.StringFixed(2), l.Actual.StringFixed(2), l.Variance.StringFixed(2), pct, l.Status)
+	}
+	for _, l := range report.Lines {
+		row(l)
+	}
+	fmt.Println(strings.Repeat("-", 96))
+	row(report.Revenue)
+	row(report.Expenses)
+	row(report.NetIncome)
+	fmt.Println(strings.Repeat("=", 96))
+}
+
+func printDimensions(dims []core.Dimension) {
+	fmt.Println()
+	for _, d := range dims {
+		status := ""
+		if !d.IsActive {
+			status = " (inactive)"
+		}
+		fmt.Printf("  %s — %s%s\n", d.Code, d.Name, status)
+		for _, v := range d.Values {
+			status := ""
+			if !v.IsActive {
+				status = " (inactive)"
+			}
+			fmt.Printf("      %-20s %s%s\n", v.Code, v.Name, status)
+		}
+	}
+}
+
+func printDimensionPL(report *core.DimensionPLReport) {
+	width := 44 + 15*(len(report.Columns)+1)
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  PROFIT & LOSS BY %s\n", report.Breakdown)
+	fmt.Printf("  Company  : %s\n", report.CompanyCode)
+	fmt.Printf("  Period   : %s (%s to %s)\n", report.Period.Label, report.Period.FromDate, report.Period.ToDate)
+	if len(report.Filter) > 0 {
+		filter := make([]string, len(report.Filter))
+		for i, f := range report.Filter {
+			filter[i] = f.Dimension + ":" + f.Value
+		}
+		fmt.Printf("  Filter   : %s\n", strings.Join(filter, ", "))
+	}
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  %-10s %-30s", "CODE", "NAME")
+	for _, c := range report.Columns {
+		label := c.Code
+		if label == "" {
+			label = c.Name
+		}
+		fmt.Printf(" %14s", label)
+	}
+	fmt.Printf(" %14s\n", "TOTAL")
+	fmt.Println(strings.Repeat("-", width))
+	row := func(l core.DimensionReportLine) {
+		fmt.Printf("  %-10s %-30s", l.AccountCode, l.AccountName)
+		for _, a := range l.Amounts {
+			fmt.Printf(" %14s", a.StringFixed(2))
+		}
+		fmt.Printf(" %14s\n", l.Total.StringFixed(2))
+	}
+	for _, l := range report.Revenue {
+		row(l)
+	}
+	row(report.TotalRevenue)
+	fmt.Println(strings.Repeat("-", width))
+	for _, l := range report.Expenses {
+		row(l)
+	}
+	row(report.TotalExpenses)
+	fmt.Println(strings.Repeat("-", width))
+	row(report.NetIncome)
+	fmt.Println(strings.Repeat("=", width))
+}
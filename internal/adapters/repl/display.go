@@ -114,12 +114,12 @@ func printOrderDetail(o *core.SalesOrder) {
 	fmt.Printf("  Date:      %s\n", o.OrderDate)
 	fmt.Printf("  Currency:  %s\n", o.Currency)
 	fmt.Println(strings.Repeat("-", 60))
-	fmt.Printf("  %-5s %-25s %8s %12s %12s\n", "LINE", "PRODUCT", "QTY", "UNIT PRICE", "TOTAL")
+	fmt.Printf("  %-5s %-20s %8s %-4s %12s %12s\n", "LINE", "PRODUCT", "QTY", "UNIT", "UNIT PRICE", "TOTAL")
 	fmt.Println(strings.Repeat("-", 60))
 	for _, l := range o.Lines {
-		fmt.Printf("  %-5d %-25s %8s %12s %12s\n",
+		fmt.Printf("  %-5d %-20s %8s %-4s %12s %12s\n",
 			l.LineNumber, l.ProductName,
-			l.Quantity.StringFixed(2),
+			l.Quantity.StringFixed(2), l.Unit,
 			l.UnitPrice.StringFixed(2),
 			l.LineTotalTransaction.StringFixed(2),
 		)
@@ -174,6 +174,41 @@ func printStockLevels(result *app.StockResult) {
 	fmt.Println(strings.Repeat("=", 80))
 }
 
+func printReplenishmentSuggestions(companyCode string, result *app.ReplenishmentSuggestionsResult) {
+	const width = 100
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  REPLENISHMENT SUGGESTIONS — Company %s\n", companyCode)
+	fmt.Println(strings.Repeat("=", width))
+	if len(result.Suggestions) == 0 {
+		fmt.Println("  Nothing is at or below its reorder point.")
+		fmt.Println(strings.Repeat("=", width))
+		return
+	}
+	fmt.Printf("  %-8s %-20s %-6s %10s %10s %10s %10s %10s %-8s %-10s\n",
+		"CODE", "PRODUCT", "WH", "AVAILABLE", "ON ORDER", "REORDER@", "SUGGEST", "UNIT COST", "VENDOR", "EXPECTED")
+	fmt.Println(strings.Repeat("-", width))
+	for _, sg := range result.Suggestions {
+		vendor := "-"
+		if sg.VendorCode != nil {
+			vendor = *sg.VendorCode
+		}
+		fmt.Printf("  %-8s %-20s %-6s %10s %10s %10s %10s %10s %-8s %-10s\n",
+			sg.ProductCode,
+			sg.ProductName,
+			sg.WarehouseCode,
+			sg.Available.StringFixed(2),
+			sg.OnOrder.StringFixed(2),
+			sg.ReorderPoint.StringFixed(2),
+			sg.SuggestedQty.StringFixed(2),
+			sg.UnitCost.StringFixed(2),
+			vendor,
+			sg.ExpectedDate,
+		)
+	}
+	fmt.Println(strings.Repeat("=", width))
+}
+
 func printStatement(result *app.AccountStatementResult) {
 	fmt.Println()
 	fmt.Println(strings.Repeat("=", 90))
@@ -273,6 +308,302 @@ func printBS(report *core.BSReport) {
 	fmt.Println(strings.Repeat("=", width))
 }
 
+func printCashFlow(report *core.CashFlowReport) {
+	const width = 62
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  CASH FLOW — %s  %s to %s (%s)\n", report.CompanyCode, report.FromDate, report.ToDate, strings.ToLower(report.Method))
+	fmt.Println(strings.Repeat("=", width))
+
+	printLines := func(prefix string, lines []core.CashFlowLine) {
+		for _, l := range lines {
+			fmt.Printf("  %-44s %15s\n", prefix+l.Name, l.Amount.StringFixed(2))
+		}
+	}
+	printSection := func(title string, section core.CashFlowSection) {
+		fmt.Printf("  %s\n", title)
+		fmt.Println(strings.Repeat("-", width))
+		printLines("", section.Lines)
+		if len(section.Lines) == 0 {
+			fmt.Println("  (none)")
+		}
+		fmt.Printf("  %-44s %15s\n", "NET CASH FROM "+title, section.Total.StringFixed(2))
+		fmt.Println()
+	}
+
+	fmt.Println("  OPERATING")
+	fmt.Println(strings.Repeat("-", width))
+	if report.Method == core.CashFlowMethodIndirect {
+		fmt.Printf("  %-44s %15s\n", "Net income", report.NetIncome.StringFixed(2))
+		printLines("Add back: ", report.Adjustments)
+	}
+	printLines("", report.Operating.Lines)
+	fmt.Printf("  %-44s %15s\n", "NET CASH FROM OPERATING", report.Operating.Total.StringFixed(2))
+	fmt.Println()
+	printSection("INVESTING", report.Investing)
+	printSection("FINANCING", report.Financing)
+
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  %-44s %15s\n", "Opening cash", report.OpeningCash.StringFixed(2))
+	fmt.Printf("  %-44s %15s\n", "Net change in cash", report.NetChange.StringFixed(2))
+	fmt.Printf("  %-44s %15s\n", "Closing cash", report.ClosingCash.StringFixed(2))
+	reconciled := "YES"
+	if !report.IsReconciled {
+		reconciled = "NO *** OPENING + NET CHANGE != CLOSING ***"
+	}
+	fmt.Printf("  RECONCILED: %s\n", reconciled)
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printInventoryReconciliation(rec core.InventoryReconciliation, width int) {
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  RECONCILIATION vs account %s as of %s\n", rec.InventoryAccountCode, rec.AsOfDate)
+	fmt.Printf("  %-40s %15s\n", "Stock value", rec.StockValue.StringFixed(2))
+	fmt.Printf("  %-40s %15s\n", "Ledger balance", rec.LedgerBalance.StringFixed(2))
+	status := "YES"
+	if !rec.IsReconciled {
+		status = "NO *** DIFFERENCE " + rec.Difference.StringFixed(2) + " ***"
+	}
+	fmt.Printf("  RECONCILED: %s\n", status)
+}
+
+func printInventoryValuation(report *core.InventoryValuationReport) {
+	const width = 90
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  INVENTORY VALUATION — %s  as of %s\n", report.CompanyCode, report.AsOfDate)
+	fmt.Println(strings.Repeat("=", width))
+	if len(report.Lines) == 0 {
+		fmt.Println("  No stock on hand at this date.")
+	} else {
+		fmt.Printf("  %-8s %-24s %-8s %12s %14s %16s\n", "CODE", "PRODUCT", "WH", "QTY", "UNIT COST", "VALUE")
+		fmt.Println(strings.Repeat("-", width))
+		for _, l := range report.Lines {
+			fmt.Printf("  %-8s %-24s %-8s %12s %14s %16s\n",
+				l.ProductCode, l.ProductName, l.WarehouseCode,
+				l.Quantity.StringFixed(2), l.UnitCost.StringFixed(2), l.Value.StringFixed(2))
+		}
+		fmt.Println(strings.Repeat("-", width))
+		fmt.Printf("  %-42s %12s %14s %16s\n", "TOTAL", report.TotalQuantity.StringFixed(2), "", report.TotalValue.StringFixed(2))
+	}
+	printInventoryReconciliation(report.Reconciliation, width)
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printStockMovementLedger(report *core.StockMovementLedger) {
+	const width = 100
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  STOCK MOVEMENTS — %s %s\n", report.ProductCode, report.ProductName)
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  %-12s %-10s %-8s %-16s %10s %12s %10s %14s\n",
+		"DATE", "TYPE", "WH", "REFERENCE", "QTY", "VALUE", "BAL QTY", "BAL VALUE")
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  %-50s %10s %12s %10s %14s\n", "OPENING", "", "",
+		report.OpeningQty.StringFixed(2), report.OpeningValue.StringFixed(2))
+	for _, l := range report.Lines {
+		fmt.Printf("  %-12s %-10s %-8s %-16s %10s %12s %10s %14s\n",
+			l.MovementDate, l.MovementType, l.WarehouseCode, l.Reference,
+			l.Quantity.StringFixed(2), l.Value.StringFixed(2),
+			l.RunningQty.StringFixed(2), l.RunningValue.StringFixed(2))
+	}
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  %-50s %10s %12s %10s %14s\n", "CLOSING", "", "",
+		report.ClosingQty.StringFixed(2), report.ClosingValue.StringFixed(2))
+	printInventoryReconciliation(report.Reconciliation, width)
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printLots(result *app.LotListResult) {
+	const width = 90
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  LOTS ON HAND — Company %s\n", result.CompanyCode)
+	fmt.Println(strings.Repeat("=", width))
+	if len(result.Lots) == 0 {
+		fmt.Println("  No lot-tracked stock on hand.")
+		fmt.Println(strings.Repeat("=", width))
+		return
+	}
+	fmt.Printf("  %-8s %-20s %-6s %-16s %-10s %-10s %10s %4s\n",
+		"CODE", "PRODUCT", "WH", "LOT", "RECEIVED", "EXPIRY", "QTY", "EXP")
+	fmt.Println(strings.Repeat("-", width))
+	for _, l := range result.Lots {
+		expiry, expired := "-", ""
+		if l.ExpiryDate != nil {
+			expiry = *l.ExpiryDate
+		}
+		if l.IsExpired {
+			expired = "*"
+		}
+		fmt.Printf("  %-8s %-20s %-6s %-16s %-10s %-10s %10s %4s\n",
+			l.ProductCode, l.ProductName, l.WarehouseCode, l.LotNumber,
+			l.ReceivedDate, expiry, l.QtyOnHand.StringFixed(2), expired)
+	}
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printProductUnits(pu *core.ProductUnits) {
+	const width = 60
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  UNITS — %s %s\n", pu.ProductCode, pu.ProductName)
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  Stock unit:     %s\n", pu.StockUnit)
+	fmt.Printf("  Purchase unit:  %s\n", pu.PurchaseUoM)
+	fmt.Printf("  Sales unit:     %s\n", pu.SalesUoM)
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  %-10s %-25s %18s\n", "UNIT", "NAME", "STOCK UNITS EACH")
+	fmt.Println(strings.Repeat("-", width))
+	for _, c := range pu.Conversions {
+		fmt.Printf("  %-10s %-25s %18s\n", c.UoMCode, c.UoMName, c.Factor.String())
+	}
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printLandedCostVouchers(result *app.LandedCostVouchersResult) {
+	const width = 96
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  LANDED COST VOUCHERS — %s\n", result.CompanyCode)
+	fmt.Println(strings.Repeat("=", width))
+	if len(result.Vouchers) == 0 {
+		fmt.Println("  No landed cost vouchers.")
+		fmt.Println(strings.Repeat("=", width))
+		return
+	}
+	fmt.Printf("  %-4s %-16s %-10s %-10s %-8s %14s %14s %14s\n",
+		"ID", "NUMBER", "DATE", "VENDOR", "METHOD", "TOTAL", "CAPITALISED", "EXPENSED")
+	fmt.Println(strings.Repeat("-", width))
+	for _, v := range result.Vouchers {
+		number, vendor := "", ""
+		if v.VoucherNumber != nil {
+			number = *v.VoucherNumber
+		}
+		if v.VendorCode != nil {
+			vendor = *v.VendorCode
+		}
+		fmt.Printf("  %-4d %-16s %-10s %-10s %-8s %14s %14s %14s\n",
+			v.ID, number, v.VoucherDate, vendor, v.AllocationMethod,
+			v.TotalAmount.StringFixed(2), v.CapitalizedAmount.StringFixed(2), v.ExpensedAmount.StringFixed(2))
+	}
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printLandedCostVoucher(v *core.LandedCostVoucher) {
+	const width = 100
+	number := ""
+	if v.VoucherNumber != nil {
+		number = *v.VoucherNumber
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  LANDED COST VOUCHER %s  (%s, by %s, CR %s)\n", number, v.VoucherDate, v.AllocationMethod, v.CreditAccountCode)
+	fmt.Println(strings.Repeat("=", width))
+	for _, c := range v.Charges {
+		fmt.Printf("  %-40s %14s\n", c.Description, c.Amount.StringFixed(2))
+	}
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  %-14s %-8s %-6s %-10s %10s %12s %12s %12s %12s\n",
+		"PO", "PRODUCT", "WH", "RECEIVED", "QTY", "BASIS", "ALLOCATED", "CAPITALISED", "EXPENSED")
+	for _, a := range v.Allocations {
+		fmt.Printf("  %-14s %-8s %-6s %-10s %10s %12s %12s %12s %12s\n",
+			a.PONumber, a.ProductCode, a.WarehouseCode, a.ReceiptDate, a.ReceiptQty.StringFixed(2), a.Basis.StringFixed(2),
+			a.AllocatedAmount.StringFixed(2), a.CapitalizedAmount.StringFixed(2), a.ExpensedAmount.StringFixed(2))
+	}
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  Total %s = %s capitalised to inventory + %s expensed to COGS\n",
+		v.TotalAmount.StringFixed(2), v.CapitalizedAmount.StringFixed(2), v.ExpensedAmount.StringFixed(2))
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printLotTraceEntries(entries []core.LotTraceEntry) {
+	for _, e := range entries {
+		fmt.Printf("    %-12s %-6s %-16s %-16s %-8s %-20s %10s\n",
+			e.MovementDate, e.WarehouseCode, e.LotNumber, e.Reference, e.PartyCode, e.PartyName, e.Quantity.StringFixed(2))
+	}
+}
+
+func printLotTrace(trace *core.LotTrace) {
+	const width = 100
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  LOT TRACE — %s %s  lot %s\n", trace.ProductCode, trace.ProductName, trace.LotNumber)
+	if trace.ExpiryDate != nil {
+		fmt.Printf("  Expiry: %s\n", *trace.ExpiryDate)
+	}
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  RECEIVED FROM (%s units)\n", trace.TotalReceived.StringFixed(2))
+	printLotTraceEntries(trace.Receipts)
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  SHIPPED TO (%s units)\n", trace.TotalShipped.StringFixed(2))
+	if len(trace.Shipments) == 0 {
+		fmt.Println("    No shipments from this lot.")
+	}
+	printLotTraceEntries(trace.Shipments)
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("  On hand: %s\n", trace.QtyOnHand.StringFixed(2))
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printOrderLotTrace(trace *core.OrderLotTrace) {
+	const width = 100
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  ORDER LOT TRACE — %s  %s %s\n", trace.OrderNumber, trace.CustomerCode, trace.CustomerName)
+	fmt.Println(strings.Repeat("=", width))
+	if len(trace.Lines) == 0 {
+		fmt.Println("  No lot-tracked products were shipped on this order.")
+		fmt.Println(strings.Repeat("=", width))
+		return
+	}
+	for _, l := range trace.Lines {
+		expiry := "-"
+		if l.ExpiryDate != nil {
+			expiry = *l.ExpiryDate
+		}
+		fmt.Printf("  %-8s %-20s lot %-16s exp %-10s shipped %s × %s\n",
+			l.ProductCode, l.ProductName, l.LotNumber, expiry, l.ShippedDate, l.QtyShipped.StringFixed(2))
+		if len(l.Sources) == 0 {
+			fmt.Println("    (no receipt found for this lot)")
+		}
+		printLotTraceEntries(l.Sources)
+	}
+	fmt.Println(strings.Repeat("=", width))
+}
+
+func printStockAgeing(report *core.StockAgeingReport) {
+	const width = 100
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", width))
+	fmt.Printf("  STOCK AGEING — %s  as of %s  (slow-moving > %d days)\n",
+		report.CompanyCode, report.AsOfDate, report.SlowMovingDays)
+	fmt.Println(strings.Repeat("=", width))
+	if len(report.Lines) == 0 {
+		fmt.Println("  No stock on hand at this date.")
+	} else {
+		fmt.Printf("  %-8s %-20s %-6s %9s %9s %9s %9s %12s %6s %4s\n",
+			"CODE", "PRODUCT", "WH", "0-30", "31-60", "61-90", ">90", "VALUE", "IDLE", "SLOW")
+		fmt.Println(strings.Repeat("-", width))
+		for _, l := range report.Lines {
+			slow := ""
+			if l.IsSlowMoving {
+				slow = "*"
+			}
+			fmt.Printf("  %-8s %-20s %-6s %9s %9s %9s %9s %12s %6d %4s\n",
+				l.ProductCode, l.ProductName, l.WarehouseCode,
+				l.Age0To30.StringFixed(2), l.Age31To60.StringFixed(2),
+				l.Age61To90.StringFixed(2), l.AgeOver90.StringFixed(2),
+				l.Value.StringFixed(2), l.DaysSinceIssue, slow)
+		}
+		fmt.Println(strings.Repeat("-", width))
+		fmt.Printf("  %-40s %15s\n", "TOTAL VALUE", report.TotalValue.StringFixed(2))
+		fmt.Printf("  %-40s %15s\n", "SLOW-MOVING VALUE", report.SlowMovingValue.StringFixed(2))
+	}
+	printInventoryReconciliation(report.Reconciliation, width)
+	fmt.Println(strings.Repeat("=", width))
+}
+
 func printHelp() {
 	fmt.Println()
 	fmt.Println("ACCOUNTING AGENT — COMMANDS")
@@ -284,6 +615,7 @@ func printHelp() {
 	fmt.Println("  /statement <acct> [from-date] [to-date]      Account statement with running balance")
 	fmt.Println("  /pl [year] [month]                           Profit & Loss report")
 	fmt.Println("  /bs [as-of-date]                             Balance Sheet")
+	fmt.Println("  /cashflow [from-date] [to-date] [direct]     Cash flow statement (indirect by default)")
 	fmt.Println("  /refresh                                     Refresh materialized reporting views")
 	fmt.Println()
 	fmt.Println("  MASTER DATA")
@@ -302,6 +634,15 @@ func printHelp() {
 	fmt.Println("  /warehouses [company-code]       List warehouses")
 	fmt.Println("  /stock      [company-code]       View stock levels (on hand / reserved / available)")
 	fmt.Println("  /receive <product> <qty> <cost>  Receive stock → DR Inventory, CR AP (default)")
+	fmt.Println("  /reorder                         Products at or below reorder point (incl. open POs)")
+	fmt.Println("  /valuation [as-of-date]          Inventory valuation reconciled to the ledger")
+	fmt.Println("  /movements <product> [from] [to] Product movement ledger with running qty and value")
+	fmt.Println("  /ageing [as-of-date] [days]      Stock ageing and slow-moving items (default 90 days)")
+	fmt.Println("  /lots [product]                  Lots / serials on hand with expiry (* = expired)")
+	fmt.Println("  /trace <product> <lot>           Forward trace: lot → supplier POs and customers")
+	fmt.Println("  /trace <order-ref>               Backward trace: order → lots → supplier POs")
+	fmt.Println("  /units <product>                 Stock unit, default PO/sales units and conversions")
+	fmt.Println("  /landed-costs [voucher-id]       Landed cost vouchers, or one voucher's allocation")
 	fmt.Println()
 	fmt.Println("  SESSION")
 	fmt.Println("  /help                            Show this help")
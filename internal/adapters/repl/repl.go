@@ -160,6 +160,13 @@ func Run(ctx context.Context, svc app.ApplicationService, reader *bufio.Reader)
 			}
 			printStockLevels(result)
 
+		case "reorder":
+			result, err := svc.GetReplenishmentSuggestions(ctx, company.CompanyCode)
+			if err != nil {
+				return err
+			}
+			printReplenishmentSuggestions(company.CompanyCode, result)
+
 		case "receive":
 			// Usage: /receive <product-code> <qty> <unit-cost> [credit-account]
 			if len(args) < 3 {
@@ -195,6 +202,121 @@ func Run(ctx context.Context, svc app.ApplicationService, reader *bufio.Reader)
 			fmt.Printf("Received %s units of %s @ %s. DR 1400 Inventory, CR %s.\n",
 				qty.String(), productCode, unitCost.String(), creditAccount)
 
+		case "valuation":
+			// Usage: /valuation [as-of-date]
+			asOfDate := ""
+			if len(args) >= 1 {
+				asOfDate = args[0]
+			}
+			report, err := svc.GetInventoryValuation(ctx, company.CompanyCode, asOfDate)
+			if err != nil {
+				return err
+			}
+			printInventoryValuation(report)
+
+		case "movements":
+			// Usage: /movements <product-code> [from-date] [to-date]
+			if len(args) < 1 {
+				fmt.Println("Usage: /movements <product-code> [from-date] [to-date]")
+				fmt.Println("  from-date and to-date are optional YYYY-MM-DD.")
+				return nil
+			}
+			productCode := strings.ToUpper(args[0])
+			fromDate, toDate := "", ""
+			if len(args) >= 2 {
+				fromDate = args[1]
+			}
+			if len(args) >= 3 {
+				toDate = args[2]
+			}
+			report, err := svc.GetStockMovementLedger(ctx, company.CompanyCode, productCode, "", fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			printStockMovementLedger(report)
+
+		case "ageing", "aging":
+			// Usage: /ageing [as-of-date] [slow-moving-days]
+			asOfDate, slowDays := "", 0
+			if len(args) >= 1 {
+				asOfDate = args[0]
+			}
+			if len(args) >= 2 {
+				if d, err := strconv.Atoi(args[1]); err == nil {
+					slowDays = d
+				}
+			}
+			report, err := svc.GetStockAgeing(ctx, company.CompanyCode, asOfDate, slowDays)
+			if err != nil {
+				return err
+			}
+			printStockAgeing(report)
+
+		case "lots":
+			// Usage: /lots [product-code]
+			productCode := ""
+			if len(args) >= 1 {
+				productCode = strings.ToUpper(args[0])
+			}
+			result, err := svc.ListLots(ctx, company.CompanyCode, productCode)
+			if err != nil {
+				return err
+			}
+			printLots(result)
+
+		case "trace":
+			// Usage: /trace <product-code> <lot-number>  |  /trace <order-ref>
+			switch len(args) {
+			case 1:
+				trace, err := svc.GetOrderLotTrace(ctx, args[0], company.CompanyCode)
+				if err != nil {
+					return err
+				}
+				printOrderLotTrace(trace)
+			case 2:
+				trace, err := svc.GetLotTrace(ctx, company.CompanyCode, strings.ToUpper(args[0]), args[1])
+				if err != nil {
+					return err
+				}
+				printLotTrace(trace)
+			default:
+				fmt.Println("Usage: /trace <product-code> <lot-number>   Forward: lot → customers")
+				fmt.Println("       /trace <order-ref>                   Backward: order → lots → supplier POs")
+			}
+
+		case "units":
+			// Usage: /units <product-code>
+			if len(args) < 1 {
+				fmt.Println("Usage: /units <product-code>")
+				return nil
+			}
+			units, err := svc.GetProductUnits(ctx, company.CompanyCode, strings.ToUpper(args[0]))
+			if err != nil {
+				return err
+			}
+			printProductUnits(units)
+
+		case "landed-costs":
+			// Usage: /landed-costs [voucher-id]
+			if len(args) >= 1 {
+				id, err := strconv.Atoi(args[0])
+				if err != nil {
+					fmt.Println("Usage: /landed-costs [voucher-id]")
+					return nil
+				}
+				result, err := svc.GetLandedCostVoucher(ctx, company.CompanyCode, id)
+				if err != nil {
+					return err
+				}
+				printLandedCostVoucher(result.Voucher)
+				return nil
+			}
+			result, err := svc.ListLandedCostVouchers(ctx, company.CompanyCode)
+			if err != nil {
+				return err
+			}
+			printLandedCostVouchers(result)
+
 		case "statement":
 			// Usage: /statement <account-code> [from-date] [to-date]
 			if len(args) < 1 {
@@ -247,6 +369,30 @@ func Run(ctx context.Context, svc app.ApplicationService, reader *bufio.Reader)
 			}
 			printBS(report)
 
+		case "cashflow":
+			// Usage: /cashflow [from-date] [to-date] [direct]
+			var dates []string
+			method := ""
+			for _, a := range args {
+				if strings.EqualFold(a, "direct") {
+					method = "DIRECT"
+				} else {
+					dates = append(dates, a)
+				}
+			}
+			fromDate, toDate := "", ""
+			if len(dates) >= 1 {
+				fromDate = dates[0]
+			}
+			if len(dates) >= 2 {
+				toDate = dates[1]
+			}
+			report, err := svc.GetCashFlow(ctx, company.CompanyCode, fromDate, toDate, method)
+			if err != nil {
+				return err
+			}
+			printCashFlow(report)
+
 		case "refresh":
 			if err := svc.RefreshViews(ctx); err != nil {
 				return err
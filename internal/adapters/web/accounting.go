@@ -1,10 +1,10 @@
 package web
 
 import (
-	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"accounting-agent/internal/app"
@@ -27,6 +27,12 @@ func (h *Handler) trialBalancePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportTrialBalance(r.Context(), d.CompanyCode, format)
+	}) {
+		return
+	}
+
 	result, err := h.svc.GetTrialBalance(r.Context(), d.CompanyCode)
 	if err != nil {
 		d.FlashMsg = "Failed to load trial balance: " + err.Error()
@@ -62,6 +68,12 @@ func (h *Handler) plReportPage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportProfitAndLoss(r.Context(), d.CompanyCode, year, month, format)
+	}) {
+		return
+	}
+
 	report, err := h.svc.GetProfitAndLoss(r.Context(), d.CompanyCode, year, month)
 	if err != nil {
 		d.FlashMsg = "Failed to load P&L: " + err.Error()
@@ -87,6 +99,12 @@ func (h *Handler) balanceSheetPage(w http.ResponseWriter, r *http.Request) {
 		asOfDate = time.Now().Format("2006-01-02")
 	}
 
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportBalanceSheet(r.Context(), d.CompanyCode, asOfDate, format)
+	}) {
+		return
+	}
+
 	report, err := h.svc.GetBalanceSheet(r.Context(), d.CompanyCode, asOfDate)
 	if err != nil {
 		d.FlashMsg = "Failed to load balance sheet: " + err.Error()
@@ -98,13 +116,126 @@ func (h *Handler) balanceSheetPage(w http.ResponseWriter, r *http.Request) {
 	_ = pages.BalanceSheet(d, report, asOfDate).Render(r.Context(), w)
 }
 
+// comparativePLPage handles GET /reports/pl/comparative.
+// Query: mode (compare | trend | periods), value (the period, month or comma-separated periods).
+func (h *Handler) comparativePLPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Comparative P&L", "pl-comparative")
+
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	mode, value, req := comparativePageRequest(r, d.CompanyCode)
+	var sections []core.ColumnarSection
+	var columns []core.ReportPeriod
+	var summary *core.ColumnarLine
+	var comparative bool
+	report, err := h.svc.GetColumnarProfitAndLoss(r.Context(), req)
+	if err != nil {
+		d.FlashMsg = "Failed to load comparative P&L: " + err.Error()
+		d.FlashKind = "error"
+	} else {
+		columns, comparative = report.Columns, report.Comparative
+		sections = []core.ColumnarSection{report.Revenue, report.Expenses}
+		summary = &report.NetIncome
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.ComparativeReport(d, "Comparative Profit & Loss", "/reports/pl/comparative", mode, value,
+		columns, comparative, sections, summary, nil).Render(r.Context(), w)
+}
+
+// comparativeBalanceSheetPage handles GET /reports/balance-sheet/comparative.
+// Query: as for comparativePLPage; each column is the balance sheet at the end of its period.
+func (h *Handler) comparativeBalanceSheetPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Comparative Balance Sheet", "bs-comparative")
+
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	mode, value, req := comparativePageRequest(r, d.CompanyCode)
+	var sections []core.ColumnarSection
+	var columns []core.ReportPeriod
+	var balanced []bool
+	var comparative bool
+	report, err := h.svc.GetColumnarBalanceSheet(r.Context(), req)
+	if err != nil {
+		d.FlashMsg = "Failed to load comparative balance sheet: " + err.Error()
+		d.FlashKind = "error"
+	} else {
+		columns, comparative = report.Columns, report.Comparative
+		sections = []core.ColumnarSection{report.Assets, report.Liabilities, report.Equity}
+		balanced = report.IsBalanced
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.ComparativeReport(d, "Comparative Balance Sheet", "/reports/balance-sheet/comparative", mode, value,
+		columns, comparative, sections, nil, balanced).Render(r.Context(), w)
+}
+
+// comparativePageRequest reads the mode/value form of the comparative report pages.
+// It defaults to comparing the current month.
+func comparativePageRequest(r *http.Request, code string) (mode, value string, req app.ColumnarReportRequest) {
+	mode, value = r.URL.Query().Get("mode"), strings.TrimSpace(r.URL.Query().Get("value"))
+	if value == "" {
+		value = time.Now().Format("2006-01")
+	}
+	req = app.ColumnarReportRequest{CompanyCode: code}
+	switch mode {
+	case "trend":
+		req.Trend = value
+	case "periods":
+		req.Periods = splitPeriods(value)
+		req.Comparative = true
+	default:
+		mode = "compare"
+		req.Compare = value
+	}
+	return mode, value, req
+}
+
+// splitPeriods splits a comma-separated list of report periods.
+func splitPeriods(s string) []string {
+	var periods []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// cashFlowPage handles GET /reports/cash-flow.
+func (h *Handler) cashFlowPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Cash Flow", "cash-flow")
+
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	report, err := h.svc.GetCashFlow(r.Context(), d.CompanyCode, q.Get("from"), q.Get("to"), q.Get("method"))
+	if err != nil {
+		d.FlashMsg = "Failed to load cash flow statement: " + err.Error()
+		d.FlashKind = "error"
+		report = &core.CashFlowReport{CompanyCode: d.CompanyCode, FromDate: q.Get("from"), ToDate: q.Get("to"),
+			Method: core.CashFlowMethodIndirect}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.CashFlow(d, report).Render(r.Context(), w)
+}
+
 // accountStatementPage handles GET /reports/statement.
-// When format=csv, streams CSV instead of HTML.
+// When format is csv, xlsx or pdf, downloads the statement instead of rendering HTML.
 func (h *Handler) accountStatementPage(w http.ResponseWriter, r *http.Request) {
 	accountCode := r.URL.Query().Get("account")
 	from := r.URL.Query().Get("from")
 	to := r.URL.Query().Get("to")
-	format := r.URL.Query().Get("format")
 
 	d := h.buildAppLayoutData(r, "Account Statement", "statement")
 
@@ -119,6 +250,12 @@ func (h *Handler) accountStatementPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportAccountStatement(r.Context(), d.CompanyCode, accountCode, from, to, format)
+	}) {
+		return
+	}
+
 	stmtResult, err := h.svc.GetAccountStatement(r.Context(), d.CompanyCode, accountCode, from, to)
 	if err != nil {
 		d.FlashMsg = "Failed to load statement: " + err.Error()
@@ -128,25 +265,6 @@ func (h *Handler) accountStatementPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if format == "csv" {
-		w.Header().Set("Content-Type", "text/csv")
-		w.Header().Set("Content-Disposition", `attachment; filename="statement-`+accountCode+`.csv"`)
-		cw := csv.NewWriter(w)
-		_ = cw.Write([]string{"Date", "Narration", "Reference", "Debit", "Credit", "Balance"})
-		for _, line := range stmtResult.Lines {
-			_ = cw.Write([]string{
-				line.PostingDate,
-				csvSafe(line.Narration),
-				csvSafe(line.Reference),
-				line.Debit.StringFixed(2),
-				line.Credit.StringFixed(2),
-				line.RunningBalance.StringFixed(2),
-			})
-		}
-		cw.Flush()
-		return
-	}
-
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	_ = pages.AccountStatement(d, stmtResult, accountCode, from, to).Render(r.Context(), w)
 }
@@ -164,27 +282,57 @@ func (h *Handler) journalEntryPage(w http.ResponseWriter, r *http.Request) {
 	_ = pages.JournalEntry(d, d.CompanyCode).Render(r.Context(), w)
 }
 
-// csvSafe prevents CSV formula injection by prefixing cells that begin with a
-// formula-triggering character with a single quote.
-func csvSafe(s string) string {
-	if len(s) == 0 {
-		return s
+// exportReport serves a report download when the request has ?format= (csv, xlsx, pdf or
+// text) and reports whether it did. export renders the report in the requested format.
+func (h *Handler) exportReport(w http.ResponseWriter, r *http.Request, export func(format string) (*core.ReportFile, error)) bool {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return false
 	}
-	switch s[0] {
-	case '=', '+', '-', '@', '\t', '\r':
-		return "'" + s
+	if !core.IsReportFormat(format) {
+		writeError(w, r, "format must be csv, xlsx, pdf or text", "BAD_REQUEST", http.StatusBadRequest)
+		return true
 	}
-	return s
+	file, err := export(format)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+	_, _ = w.Write(file.Content)
+	return true
 }
 
 // ── API handlers ──────────────────────────────────────────────────────────────
 
-// apiTrialBalance handles GET /api/companies/{code}/trial-balance.
+// apiTrialBalance handles GET /api/companies/{code}/trial-balance[?format=].
+// With filter= (DIMENSION:VALUE pairs) or by= (a dimension code) it returns the trial balance
+// by analytic dimension instead, as of as_of= (default: today).
 func (h *Handler) apiTrialBalance(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
 		return
 	}
+	if q := r.URL.Query(); q.Get("filter") != "" || q.Get("by") != "" {
+		result, err := h.svc.GetDimensionTrialBalance(r.Context(), app.DimensionReportRequest{
+			CompanyCode: code,
+			AsOfDate:    q.Get("as_of"),
+			Filter:      q.Get("filter"),
+			Breakdown:   q.Get("by"),
+		})
+		if err != nil {
+			writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, result)
+		return
+	}
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportTrialBalance(r.Context(), code, format)
+	}) {
+		return
+	}
 	result, err := h.svc.GetTrialBalance(r.Context(), code)
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
@@ -193,12 +341,18 @@ func (h *Handler) apiTrialBalance(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
-// apiAccountStatement handles GET /api/companies/{code}/accounts/{accountCode}/statement.
+// apiAccountStatement handles GET /api/companies/{code}/accounts/{accountCode}/statement[?format=].
 func (h *Handler) apiAccountStatement(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
 		return
 	}
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		q := r.URL.Query()
+		return h.svc.ExportAccountStatement(r.Context(), code, chi.URLParam(r, "accountCode"), q.Get("from"), q.Get("to"), format)
+	}) {
+		return
+	}
 	result, err := h.svc.GetAccountStatement(r.Context(),
 		code,
 		chi.URLParam(r, "accountCode"),
@@ -212,7 +366,9 @@ func (h *Handler) apiAccountStatement(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
-// apiProfitAndLoss handles GET /api/companies/{code}/reports/pl.
+// apiProfitAndLoss handles GET /api/companies/{code}/reports/pl[?format=].
+// With filter= (DIMENSION:VALUE pairs) or by= (a dimension code) it returns the P&L by
+// analytic dimension instead, over period= (default: year/month).
 func (h *Handler) apiProfitAndLoss(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -232,6 +388,30 @@ func (h *Handler) apiProfitAndLoss(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if q := r.URL.Query(); q.Get("filter") != "" || q.Get("by") != "" {
+		period := q.Get("period")
+		if period == "" {
+			period = fmt.Sprintf("%04d-%02d", year, month)
+		}
+		result, err := h.svc.GetDimensionProfitAndLoss(r.Context(), app.DimensionReportRequest{
+			CompanyCode: code,
+			Period:      period,
+			Filter:      q.Get("filter"),
+			Breakdown:   q.Get("by"),
+		})
+		if err != nil {
+			writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, result)
+		return
+	}
+
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportProfitAndLoss(r.Context(), code, year, month, format)
+	}) {
+		return
+	}
 	result, err := h.svc.GetProfitAndLoss(r.Context(), code, year, month)
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
@@ -240,12 +420,17 @@ func (h *Handler) apiProfitAndLoss(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
-// apiBalanceSheet handles GET /api/companies/{code}/reports/balance-sheet.
+// apiBalanceSheet handles GET /api/companies/{code}/reports/balance-sheet[?format=].
 func (h *Handler) apiBalanceSheet(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
 		return
 	}
+	if h.exportReport(w, r, func(format string) (*core.ReportFile, error) {
+		return h.svc.ExportBalanceSheet(r.Context(), code, r.URL.Query().Get("date"), format)
+	}) {
+		return
+	}
 	result, err := h.svc.GetBalanceSheet(r.Context(), code, r.URL.Query().Get("date"))
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
@@ -254,6 +439,205 @@ func (h *Handler) apiBalanceSheet(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
+// columnarRequest reads the column selection of the columnar report endpoints:
+// periods (comma-separated, variances from the first unless comparative=false), trend
+// (YYYY-MM) with months (default 12), or compare (default: the current month).
+func columnarRequest(r *http.Request, code string) app.ColumnarReportRequest {
+	q := r.URL.Query()
+	req := app.ColumnarReportRequest{
+		CompanyCode: code,
+		Periods:     splitPeriods(q.Get("periods")),
+		Comparative: q.Get("comparative") != "false",
+		Compare:     q.Get("compare"),
+		Trend:       q.Get("trend"),
+	}
+	if m, err := strconv.Atoi(q.Get("months")); err == nil {
+		req.TrendMonths = m
+	}
+	return req
+}
+
+// apiColumnarProfitAndLoss handles GET /api/companies/{code}/reports/pl/columns.
+// Query: see columnarRequest.
+func (h *Handler) apiColumnarProfitAndLoss(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetColumnarProfitAndLoss(r.Context(), columnarRequest(r, code))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiColumnarBalanceSheet handles GET /api/companies/{code}/reports/balance-sheet/columns.
+// Query: see columnarRequest; each column is the balance sheet at the end of its period.
+func (h *Handler) apiColumnarBalanceSheet(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetColumnarBalanceSheet(r.Context(), columnarRequest(r, code))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCashFlow handles GET /api/companies/{code}/reports/cash-flow.
+// Query: from, to (optional, YYYY-MM-DD; default the current month to date), method (INDIRECT | DIRECT).
+func (h *Handler) apiCashFlow(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	q := r.URL.Query()
+	result, err := h.svc.GetCashFlow(r.Context(), code, q.Get("from"), q.Get("to"), q.Get("method"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCashFlowMappings handles GET /api/companies/{code}/cash-flow-mappings.
+func (h *Handler) apiCashFlowMappings(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetCashFlowMappings(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiSetCashFlowMapping handles PUT /api/companies/{code}/cash-flow-mappings/{accountCode}.
+// Body: { activity: OPERATING|NON_CASH|INVESTING|FINANCING, line_name? } — an empty
+// activity reverts the account to its default.
+func (h *Handler) apiSetCashFlowMapping(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Activity string `json:"activity"`
+		LineName string `json:"line_name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetCashFlowMapping(r.Context(), code, accountCode, body.Activity, body.LineName); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"status": "updated", "account_code": accountCode})
+}
+
+// apiSetCashAccount handles PUT /api/companies/{code}/accounts/{accountCode}/cash.
+// Body: { is_cash }
+func (h *Handler) apiSetCashAccount(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		IsCash bool `json:"is_cash"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetCashAccount(r.Context(), code, accountCode, body.IsCash); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"status": "updated", "account_code": accountCode, "is_cash": body.IsCash})
+}
+
+// apiInventoryValuation handles GET /api/companies/{code}/reports/inventory-valuation.
+// Query: date (optional, YYYY-MM-DD; defaults to today).
+func (h *Handler) apiInventoryValuation(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetInventoryValuation(r.Context(), code, r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiStockMovementLedger handles GET /api/companies/{code}/reports/stock-movements.
+// Query: product (required), warehouse, from, to (optional).
+func (h *Handler) apiStockMovementLedger(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("product") == "" {
+		writeError(w, r, "product is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetStockMovementLedger(r.Context(), code,
+		q.Get("product"), q.Get("warehouse"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiStockAgeing handles GET /api/companies/{code}/reports/stock-ageing.
+// Query: date (optional), slow_days (optional, defaults to 90).
+func (h *Handler) apiStockAgeing(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	slowDays := 0
+	if v := r.URL.Query().Get("slow_days"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			slowDays = parsed
+		}
+	}
+	result, err := h.svc.GetStockAgeing(r.Context(), code, r.URL.Query().Get("date"), slowDays)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiLotTrace handles GET /api/companies/{code}/reports/lot-trace?product=&lot=.
+func (h *Handler) apiLotTrace(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	product := r.URL.Query().Get("product")
+	lot := r.URL.Query().Get("lot")
+	if product == "" || lot == "" {
+		writeError(w, r, "product and lot query parameters are required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetLotTrace(r.Context(), code, product, lot)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
 // apiRefreshViews handles POST /api/companies/{code}/reports/refresh.
 func (h *Handler) apiRefreshViews(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
@@ -277,9 +661,10 @@ type journalEntryRequest struct {
 	Currency     string `json:"currency"`
 	ExchangeRate string `json:"exchange_rate"`
 	Lines        []struct {
-		AccountCode string `json:"account_code"`
-		Debit       string `json:"debit"`
-		Credit      string `json:"credit"`
+		AccountCode string               `json:"account_code"`
+		Debit       string               `json:"debit"`
+		Credit      string               `json:"credit"`
+		Dimensions  []core.LineDimension `json:"dimensions"`
 	} `json:"lines"`
 }
 
@@ -300,6 +685,9 @@ func (h *Handler) apiPostJournalEntry(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
 		return
 	}
+	if claims := authFromContext(r.Context()); claims != nil {
+		proposal.CreatedByUserID = claims.UserID
+	}
 
 	if err := h.svc.CommitProposal(r.Context(), proposal); err != nil {
 		writeError(w, r, err.Error(), "COMMIT_FAILED", http.StatusUnprocessableEntity)
@@ -386,6 +774,7 @@ func buildProposal(code string, req journalEntryRequest) (core.Proposal, error)
 				AccountCode: l.AccountCode,
 				IsDebit:     true,
 				Amount:      debitAmt.StringFixed(2),
+				Dimensions:  l.Dimensions,
 			})
 		}
 		if creditAmt.IsPositive() {
@@ -393,6 +782,7 @@ func buildProposal(code string, req journalEntryRequest) (core.Proposal, error)
 				AccountCode: l.AccountCode,
 				IsDebit:     false,
 				Amount:      creditAmt.StringFixed(2),
+				Dimensions:  l.Dimensions,
 			})
 		}
 	}
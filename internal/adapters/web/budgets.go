@@ -0,0 +1,177 @@
+package web
+
+import (
+	"net/http"
+
+	"accounting-agent/internal/app"
+	"accounting-agent/internal/core"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// apiListCostCenters handles GET /api/companies/{code}/cost-centers.
+func (h *Handler) apiListCostCenters(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListCostCenters(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCreateCostCenter handles POST /api/companies/{code}/cost-centers.
+// Body: { code, name }
+func (h *Handler) apiCreateCostCenter(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	result, err := h.svc.CreateCostCenter(r.Context(), code, body.Code, body.Name)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result)
+}
+
+// apiListBudgets handles GET /api/companies/{code}/budgets.
+func (h *Handler) apiListBudgets(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListBudgets(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCreateBudget handles POST /api/companies/{code}/budgets.
+// Body: { code, name, financial_year, variance_threshold_pct (optional, default 10) }
+func (h *Handler) apiCreateBudget(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code                 string           `json:"code"`
+		Name                 string           `json:"name"`
+		FinancialYear        int              `json:"financial_year"`
+		VarianceThresholdPct *decimal.Decimal `json:"variance_threshold_pct"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	input := core.BudgetInput{
+		Code:                 body.Code,
+		Name:                 body.Name,
+		FinancialYear:        body.FinancialYear,
+		VarianceThresholdPct: body.VarianceThresholdPct,
+	}
+	if claims := authFromContext(r.Context()); claims != nil {
+		input.CreatedByUserID = &claims.UserID
+	}
+	result, err := h.svc.CreateBudget(r.Context(), code, input)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result)
+}
+
+// apiGetBudget handles GET /api/companies/{code}/budgets/{budgetCode}.
+func (h *Handler) apiGetBudget(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetBudget(r.Context(), code, chi.URLParam(r, "budgetCode"))
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiImportBudgetCSV handles PUT /api/companies/{code}/budgets/{budgetCode}/lines?replace=.
+// The body is CSV (see core.ParseBudgetCSV); replace=true deletes the budget's other lines.
+func (h *Handler) apiImportBudgetCSV(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	replace := r.URL.Query().Get("replace") == "true"
+	budgetCode := chi.URLParam(r, "budgetCode")
+	n, err := h.svc.ImportBudgetCSV(r.Context(), code, budgetCode, r.Body, replace)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"status": "imported", "budget_code": budgetCode, "lines": n})
+}
+
+// apiCopyBudgetFromActuals handles POST /api/companies/{code}/budgets/{budgetCode}/copy-actuals.
+// Body: { source_year, uplift_pct } — source_year is the financial year to copy (2025 =
+// FY 2025-26); the budget's lines are replaced.
+func (h *Handler) apiCopyBudgetFromActuals(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		SourceYear int             `json:"source_year"`
+		UpliftPct  decimal.Decimal `json:"uplift_pct"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	budgetCode := chi.URLParam(r, "budgetCode")
+	n, err := h.svc.CopyBudgetFromActuals(r.Context(), code, budgetCode, body.SourceYear, body.UpliftPct)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"status": "copied", "budget_code": budgetCode, "lines": n})
+}
+
+// apiBudgetVsActual handles GET /api/companies/{code}/reports/budget-vs-actual?budget=&period=&threshold=.
+// period uses the columnar report syntax on whole months (default: financial year to date);
+// budget defaults to the latest for that financial year and threshold (percent) to the budget's own.
+func (h *Handler) apiBudgetVsActual(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	q := r.URL.Query()
+	req := app.BudgetVsActualRequest{CompanyCode: code, BudgetCode: q.Get("budget"), Period: q.Get("period")}
+	if v := q.Get("threshold"); v != "" {
+		threshold, err := decimal.NewFromString(v)
+		if err != nil {
+			writeError(w, r, "threshold must be a percentage, e.g. 10", "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		req.ThresholdPct = &threshold
+	}
+	result, err := h.svc.GetBudgetVsActual(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,528 @@
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"accounting-agent/internal/app"
+	"accounting-agent/internal/core"
+	"accounting-agent/web/templates/pages"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ── Browser page handlers ─────────────────────────────────────────────────────
+
+// chartOfAccountsPage handles GET /accounting/chart-of-accounts.
+func (h *Handler) chartOfAccountsPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Chart of Accounts", "coa")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	if fe := r.URL.Query().Get("flash_error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+	if fs := r.URL.Query().Get("flash_success"); fs != "" {
+		d.FlashMsg = fs
+		d.FlashKind = "success"
+	}
+
+	asOfDate := r.URL.Query().Get("date")
+	nodes, err := h.svc.GetChartOfAccounts(r.Context(), d.CompanyCode, asOfDate)
+	if err != nil {
+		d.FlashMsg = "Failed to load chart of accounts: " + err.Error()
+		d.FlashKind = "error"
+	}
+
+	canEdit := hasRole(d.Role, []string{"FINANCE_MANAGER", "ADMIN"})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.ChartOfAccounts(d, nodes, asOfDate, canEdit).Render(r.Context(), w)
+}
+
+// accountCreateAction handles POST /accounting/chart-of-accounts/accounts.
+func (h *Handler) accountCreateAction(w http.ResponseWriter, r *http.Request) {
+	const pageURL = "/accounting/chart-of-accounts"
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, pageURL+"?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	account, err := h.svc.CreateAccount(r.Context(), app.CreateAccountRequest{
+		CompanyCode:          claims.CompanyCode,
+		Code:                 r.FormValue("code"),
+		Name:                 r.FormValue("name"),
+		Type:                 r.FormValue("type"),
+		ParentCode:           r.FormValue("parent_code"),
+		PostingBlocked:       r.FormValue("posting_blocked") == "true",
+		ManualPostingBlocked: r.FormValue("manual_posting_blocked") == "true",
+		NarrationRequired:    r.FormValue("narration_required") == "true",
+	})
+	if err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, pageURL+"?flash_success="+url.QueryEscape("Account "+account.Code+" created"), http.StatusSeeOther)
+}
+
+// accountUpdateAction handles POST /accounting/chart-of-accounts/accounts/{accountCode}.
+func (h *Handler) accountUpdateAction(w http.ResponseWriter, r *http.Request) {
+	const pageURL = "/accounting/chart-of-accounts"
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, pageURL+"?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	accountCode := chi.URLParam(r, "accountCode")
+	_, err := h.svc.UpdateAccount(r.Context(), app.UpdateAccountRequest{
+		CompanyCode:          claims.CompanyCode,
+		AccountCode:          accountCode,
+		Name:                 r.FormValue("name"),
+		PostingBlocked:       r.FormValue("posting_blocked") == "true",
+		ManualPostingBlocked: r.FormValue("manual_posting_blocked") == "true",
+		NarrationRequired:    r.FormValue("narration_required") == "true",
+	})
+	if err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, pageURL+"?flash_success="+url.QueryEscape("Account "+accountCode+" updated"), http.StatusSeeOther)
+}
+
+// accountSetActiveAction handles POST /accounting/chart-of-accounts/accounts/{accountCode}/active.
+func (h *Handler) accountSetActiveAction(w http.ResponseWriter, r *http.Request) {
+	const pageURL = "/accounting/chart-of-accounts"
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, pageURL+"?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	accountCode := chi.URLParam(r, "accountCode")
+	active := r.FormValue("active") == "true"
+	if err := h.svc.SetAccountActive(r.Context(), claims.CompanyCode, accountCode, active); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	msg := "Account " + accountCode + " deactivated"
+	if active {
+		msg = "Account " + accountCode + " reactivated"
+	}
+	http.Redirect(w, r, pageURL+"?flash_success="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+// accountGroupCreateAction handles POST /accounting/chart-of-accounts/groups.
+func (h *Handler) accountGroupCreateAction(w http.ResponseWriter, r *http.Request) {
+	const pageURL = "/accounting/chart-of-accounts"
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, pageURL+"?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	group, err := h.svc.CreateAccountGroup(r.Context(), app.CreateAccountGroupRequest{
+		CompanyCode: claims.CompanyCode,
+		Code:        r.FormValue("code"),
+		Name:        r.FormValue("name"),
+		Type:        r.FormValue("type"),
+		ParentCode:  r.FormValue("parent_code"),
+	})
+	if err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, pageURL+"?flash_success="+url.QueryEscape("Group "+group.Code+" created"), http.StatusSeeOther)
+}
+
+// accountParentAction handles POST /accounting/chart-of-accounts/parent.
+func (h *Handler) accountParentAction(w http.ResponseWriter, r *http.Request) {
+	const pageURL = "/accounting/chart-of-accounts"
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, pageURL+"?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	accountCode, parentCode := r.FormValue("account_code"), r.FormValue("parent_code")
+	if err := h.svc.SetAccountParent(r.Context(), claims.CompanyCode, accountCode, parentCode); err != nil {
+		http.Redirect(w, r, pageURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	msg := "Account " + accountCode + " moved to the top level"
+	if parentCode != "" {
+		msg = "Account " + accountCode + " moved under " + parentCode
+	}
+	http.Redirect(w, r, pageURL+"?flash_success="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+// layoutStatementPage handles GET /reports/layout-statement.
+// Query: layout (default the first layout), from, to.
+func (h *Handler) layoutStatementPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Statement Layouts", "layout-statement")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	if fe := r.URL.Query().Get("flash_error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+	if fs := r.URL.Query().Get("flash_success"); fs != "" {
+		d.FlashMsg = fs
+		d.FlashKind = "success"
+	}
+
+	q := r.URL.Query()
+	layouts, err := h.svc.GetStatementLayouts(r.Context(), d.CompanyCode)
+	if err != nil {
+		d.FlashMsg = "Failed to load statement layouts: " + err.Error()
+		d.FlashKind = "error"
+	}
+	var selected *core.StatementLayout
+	for i := range layouts {
+		if selected == nil || strings.EqualFold(layouts[i].Code, q.Get("layout")) {
+			selected = &layouts[i]
+		}
+	}
+
+	var report *core.LayoutStatement
+	if selected != nil {
+		report, err = h.svc.GetLayoutStatement(r.Context(), d.CompanyCode, selected.Code, q.Get("from"), q.Get("to"))
+		if err != nil {
+			d.FlashMsg = "Failed to load statement: " + err.Error()
+			d.FlashKind = "error"
+			report = nil
+		}
+	}
+
+	canEdit := hasRole(d.Role, []string{"FINANCE_MANAGER", "ADMIN"})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.LayoutStatement(d, layouts, selected, report, canEdit).Render(r.Context(), w)
+}
+
+// layoutMappingAction handles POST /reports/layout-statement/map — maps an account to a
+// line of the layout and returns to the statement.
+func (h *Handler) layoutMappingAction(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, "/reports/layout-statement?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+	layoutCode := r.FormValue("layout")
+	back := "/reports/layout-statement?" + url.Values{
+		"layout": {layoutCode}, "from": {r.FormValue("from")}, "to": {r.FormValue("to")},
+	}.Encode()
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, back+"&flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	accountCode := r.FormValue("account_code")
+	if err := h.svc.SetStatementLayoutAccount(r.Context(), claims.CompanyCode, layoutCode, accountCode, r.FormValue("line_code")); err != nil {
+		http.Redirect(w, r, back+"&flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, back+"&flash_success="+url.QueryEscape("Account "+accountCode+" mapped"), http.StatusSeeOther)
+}
+
+// ── REST API handlers ─────────────────────────────────────────────────────────
+
+// apiChartOfAccounts handles GET /api/companies/{code}/accounts/tree?date=.
+func (h *Handler) apiChartOfAccounts(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	nodes, err := h.svc.GetChartOfAccounts(r.Context(), code, r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, nodes)
+}
+
+// apiGetAccount handles GET /api/companies/{code}/accounts/{accountCode}.
+func (h *Handler) apiGetAccount(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	account, err := h.svc.GetAccount(r.Context(), code, chi.URLParam(r, "accountCode"))
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, account)
+}
+
+// apiCreateAccount handles POST /api/companies/{code}/accounts.
+// Body: {code, name, type, parent_code, posting_blocked, manual_posting_blocked, narration_required}.
+func (h *Handler) apiCreateAccount(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code                 string `json:"code"`
+		Name                 string `json:"name"`
+		Type                 string `json:"type"`
+		ParentCode           string `json:"parent_code"`
+		PostingBlocked       bool   `json:"posting_blocked"`
+		ManualPostingBlocked bool   `json:"manual_posting_blocked"`
+		NarrationRequired    bool   `json:"narration_required"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	account, err := h.svc.CreateAccount(r.Context(), app.CreateAccountRequest{
+		CompanyCode:          code,
+		Code:                 body.Code,
+		Name:                 body.Name,
+		Type:                 body.Type,
+		ParentCode:           body.ParentCode,
+		PostingBlocked:       body.PostingBlocked,
+		ManualPostingBlocked: body.ManualPostingBlocked,
+		NarrationRequired:    body.NarrationRequired,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, account)
+}
+
+// apiUpdateAccount handles PUT /api/companies/{code}/accounts/{accountCode}.
+// Body: {name, posting_blocked, manual_posting_blocked, narration_required}.
+func (h *Handler) apiUpdateAccount(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Name                 string `json:"name"`
+		PostingBlocked       bool   `json:"posting_blocked"`
+		ManualPostingBlocked bool   `json:"manual_posting_blocked"`
+		NarrationRequired    bool   `json:"narration_required"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	account, err := h.svc.UpdateAccount(r.Context(), app.UpdateAccountRequest{
+		CompanyCode:          code,
+		AccountCode:          chi.URLParam(r, "accountCode"),
+		Name:                 body.Name,
+		PostingBlocked:       body.PostingBlocked,
+		ManualPostingBlocked: body.ManualPostingBlocked,
+		NarrationRequired:    body.NarrationRequired,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, account)
+}
+
+// apiSetAccountActive handles PUT /api/companies/{code}/accounts/{accountCode}/active.
+// Body: {active}.
+func (h *Handler) apiSetAccountActive(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Active bool `json:"active"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetAccountActive(r.Context(), code, accountCode, body.Active); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"account_code": accountCode, "active": body.Active})
+}
+
+// apiCreateAccountGroup handles POST /api/companies/{code}/account-groups.
+// Body: {code, name, type, parent_code}.
+func (h *Handler) apiCreateAccountGroup(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code       string `json:"code"`
+		Name       string `json:"name"`
+		Type       string `json:"type"`
+		ParentCode string `json:"parent_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	group, err := h.svc.CreateAccountGroup(r.Context(), app.CreateAccountGroupRequest{
+		CompanyCode: code,
+		Code:        body.Code,
+		Name:        body.Name,
+		Type:        body.Type,
+		ParentCode:  body.ParentCode,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, group)
+}
+
+// apiSetAccountParent handles PUT /api/companies/{code}/accounts/{accountCode}/parent.
+// Body: {parent_code}; empty moves the account to the top level.
+func (h *Handler) apiSetAccountParent(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		ParentCode string `json:"parent_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetAccountParent(r.Context(), code, accountCode, body.ParentCode); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"account_code": accountCode, "parent_code": body.ParentCode})
+}
+
+// apiStatementLayouts handles GET /api/companies/{code}/statement-layouts.
+func (h *Handler) apiStatementLayouts(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	layouts, err := h.svc.GetStatementLayouts(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, layouts)
+}
+
+// apiCreateStatementLayout handles POST /api/companies/{code}/statement-layouts.
+// Body: {code, name, statement}.
+func (h *Handler) apiCreateStatementLayout(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code      string `json:"code"`
+		Name      string `json:"name"`
+		Statement string `json:"statement"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := h.svc.CreateStatementLayout(r.Context(), code, body.Code, body.Name, body.Statement); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, map[string]string{"code": strings.ToUpper(body.Code)})
+}
+
+// apiSetStatementLayoutLine handles PUT /api/companies/{code}/statement-layouts/{layout}/lines/{lineCode}.
+// Body: {parent_code, label, sort_order, normal_balance, includes_profit}.
+func (h *Handler) apiSetStatementLayoutLine(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		ParentCode     string `json:"parent_code"`
+		Label          string `json:"label"`
+		SortOrder      int    `json:"sort_order"`
+		NormalBalance  string `json:"normal_balance"`
+		IncludesProfit bool   `json:"includes_profit"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	line := core.StatementLayoutLine{
+		Code:           chi.URLParam(r, "lineCode"),
+		ParentCode:     body.ParentCode,
+		Label:          body.Label,
+		SortOrder:      body.SortOrder,
+		NormalBalance:  body.NormalBalance,
+		IncludesProfit: body.IncludesProfit,
+	}
+	if err := h.svc.SetStatementLayoutLine(r.Context(), code, chi.URLParam(r, "layout"), line); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, line)
+}
+
+// apiSetStatementLayoutAccount handles PUT /api/companies/{code}/statement-layouts/{layout}/accounts/{accountCode}.
+// Body: {line_code}; empty removes the mapping.
+func (h *Handler) apiSetStatementLayoutAccount(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		LineCode string `json:"line_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetStatementLayoutAccount(r.Context(), code, chi.URLParam(r, "layout"), accountCode, body.LineCode); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"account_code": accountCode, "line_code": body.LineCode})
+}
+
+// apiLayoutStatement handles GET /api/companies/{code}/reports/layout/{layout}?from=&to=.
+func (h *Handler) apiLayoutStatement(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	q := r.URL.Query()
+	report, err := h.svc.GetLayoutStatement(r.Context(), code, chi.URLParam(r, "layout"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, report)
+}
@@ -107,12 +107,14 @@ func sendSSE(w http.ResponseWriter, f http.Flusher, event string, data any) {
 // ── Enriched proposal types (display-only, never touches commit path) ─────────
 
 // enrichedProposalLine adds a display-only AccountName field.
-// core.ProposalLine must not be modified (strict OpenAI JSON schema).
+// core.ProposalLine must stay in step with the strict OpenAI JSON schema, so display-only
+// fields live here.
 type enrichedProposalLine struct {
-	AccountCode string `json:"account_code"`
-	AccountName string `json:"account_name"`
-	IsDebit     bool   `json:"is_debit"`
-	Amount      string `json:"amount"`
+	AccountCode string               `json:"account_code"`
+	AccountName string               `json:"account_name"`
+	IsDebit     bool                 `json:"is_debit"`
+	Amount      string               `json:"amount"`
+	Dimensions  []core.LineDimension `json:"dimensions,omitempty"`
 }
 
 type enrichedProposal struct {
@@ -136,6 +138,7 @@ func buildEnrichedProposal(p *core.Proposal, names map[string]string) enrichedPr
 			AccountName: names[l.AccountCode],
 			IsDebit:     l.IsDebit,
 			Amount:      l.Amount,
+			Dimensions:  l.Dimensions,
 		}
 	}
 	return enrichedProposal{
@@ -345,7 +348,11 @@ func (h *Handler) chatConfirm(w http.ResponseWriter, r *http.Request) {
 
 	switch action.Kind {
 	case pendingKindJournalEntry:
-		if err := h.svc.CommitProposal(r.Context(), *action.Proposal); err != nil {
+		proposal := *action.Proposal
+		if claims := authFromContext(r.Context()); claims != nil {
+			proposal.CreatedByUserID = claims.UserID
+		}
+		if err := h.svc.CommitProposal(r.Context(), proposal); err != nil {
 			writeError(w, r, "commit failed: "+err.Error(), "COMMIT_ERROR", http.StatusUnprocessableEntity)
 			return
 		}
@@ -0,0 +1,119 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiListDimensions handles GET /api/companies/{code}/dimensions.
+func (h *Handler) apiListDimensions(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListDimensions(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCreateDimension handles POST /api/companies/{code}/dimensions.
+// Body: { code, name }
+func (h *Handler) apiCreateDimension(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	result, err := h.svc.CreateDimension(r.Context(), code, body.Code, body.Name)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result)
+}
+
+// apiCreateDimensionValue handles POST /api/companies/{code}/dimensions/{dimCode}/values.
+// Body: { code, name }
+func (h *Handler) apiCreateDimensionValue(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	result, err := h.svc.CreateDimensionValue(r.Context(), code, chi.URLParam(r, "dimCode"), body.Code, body.Name)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result)
+}
+
+// apiSetDimensionValueActive handles PUT /api/companies/{code}/dimensions/{dimCode}/values/{valueCode}/active.
+// Body: { active }
+func (h *Handler) apiSetDimensionValueActive(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Active bool `json:"active"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	result, err := h.svc.SetDimensionValueActive(r.Context(), code, chi.URLParam(r, "dimCode"), chi.URLParam(r, "valueCode"), body.Active)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiListAccountDimensionRules handles GET /api/companies/{code}/dimension-rules.
+func (h *Handler) apiListAccountDimensionRules(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListAccountDimensionRules(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiRequireAccountDimension handles PUT and DELETE on
+// /api/companies/{code}/accounts/{accountCode}/dimensions/{dimCode}: PUT makes the
+// dimension mandatory on every posting to the account, DELETE makes it optional again.
+func (h *Handler) apiRequireAccountDimension(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	required := r.Method != http.MethodDelete
+	accountCode, dimCode := chi.URLParam(r, "accountCode"), chi.URLParam(r, "dimCode")
+	if err := h.svc.SetAccountDimensionRequired(r.Context(), code, accountCode, dimCode, required); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"account_code": accountCode, "dimension_code": dimCode, "required": required})
+}
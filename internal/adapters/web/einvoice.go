@@ -0,0 +1,102 @@
+package web
+
+import (
+	"net/http"
+
+	"accounting-agent/internal/app"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiSetEInvoicing handles PUT /api/companies/{code}/einvoicing.
+// Body: { enabled, address? } — address is the seller address on e-invoices and must end
+// with a 6-digit PIN code, e.g. "12 MG Road, Bengaluru 560001".
+func (h *Handler) apiSetEInvoicing(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Enabled bool   `json:"enabled"`
+		Address string `json:"address"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := h.svc.SetEInvoicing(r.Context(), code, body.Enabled, body.Address); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]any{"status": "updated", "company_code": code, "enabled": body.Enabled})
+}
+
+// apiGetEInvoice handles GET /api/companies/{code}/orders/{ref}/einvoice.
+func (h *Handler) apiGetEInvoice(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetEInvoice(r.Context(), chi.URLParam(r, "ref"), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiEInvoicePayload handles GET /api/companies/{code}/orders/{ref}/einvoice/payload.
+// Returns the INV-01 JSON without registering it.
+func (h *Handler) apiEInvoicePayload(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetEInvoicePayload(r.Context(), chi.URLParam(r, "ref"), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiGenerateEInvoice handles POST /api/companies/{code}/orders/{ref}/einvoice.
+func (h *Handler) apiGenerateEInvoice(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GenerateEInvoice(r.Context(), chi.URLParam(r, "ref"), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiCancelEInvoice handles POST /api/companies/{code}/orders/{ref}/einvoice/cancel.
+// Body: { reason_code: 1-4, remark } — 1 duplicate, 2 data entry mistake, 3 order
+// cancelled, 4 other.
+func (h *Handler) apiCancelEInvoice(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		ReasonCode int    `json:"reason_code"`
+		Remark     string `json:"remark"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	result, err := h.svc.CancelEInvoice(r.Context(), app.CancelEInvoiceRequest{
+		CompanyCode: code,
+		Ref:         chi.URLParam(r, "ref"),
+		ReasonCode:  body.ReasonCode,
+		Remark:      body.Remark,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
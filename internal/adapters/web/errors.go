@@ -2,7 +2,10 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"accounting-agent/internal/core"
 )
 
 type errorResponse struct {
@@ -29,6 +32,16 @@ func writeJSON(w http.ResponseWriter, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// writeUpdateError writes a failed master-data update: 409 when the record changed since the
+// caller read it (optimistic concurrency), 400 for every other rejection.
+func writeUpdateError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, core.ErrVersionConflict) {
+		writeError(w, r, err.Error(), "CONFLICT", http.StatusConflict)
+		return
+	}
+	writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+}
+
 // notImplemented is a stub handler that returns HTTP 501 JSON.
 func notImplemented(w http.ResponseWriter, r *http.Request) {
 	writeError(w, r, "not implemented", "NOT_IMPLEMENTED", http.StatusNotImplemented)
@@ -0,0 +1,129 @@
+package web
+
+import (
+	"net/http"
+
+	"accounting-agent/internal/app"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// apiListTaxCodes handles GET /api/companies/{code}/tax-codes?date=.
+// date (YYYY-MM-DD) picks the rates in effect; it defaults to today.
+func (h *Handler) apiListTaxCodes(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListTaxCodes(r.Context(), code, r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiSetTaxRate handles PUT /api/companies/{code}/tax-codes/{taxCode}/rates.
+// Body: { component: CGST|SGST|IGST, rate, effective_from }
+func (h *Handler) apiSetTaxRate(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Component     string `json:"component"`
+		Rate          string `json:"rate"`
+		EffectiveFrom string `json:"effective_from"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	rate, err := decimal.NewFromString(body.Rate)
+	if err != nil {
+		writeError(w, r, "invalid rate", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	taxCode := chi.URLParam(r, "taxCode")
+	if err := h.svc.SetTaxRate(r.Context(), app.SetTaxRateRequest{
+		CompanyCode:   code,
+		TaxCode:       taxCode,
+		Component:     body.Component,
+		Rate:          rate,
+		EffectiveFrom: body.EffectiveFrom,
+	}); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "updated", "tax_code": taxCode, "component": body.Component,
+		"rate": rate.String(), "effective_from": body.EffectiveFrom})
+}
+
+// apiSetCompanyGST handles PUT /api/companies/{code}/gst-registration.
+// Body: { state_code?, gstin? } — the state code is taken from the GSTIN when omitted.
+func (h *Handler) apiSetCompanyGST(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		StateCode string `json:"state_code"`
+		GSTIN     string `json:"gstin"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := h.svc.SetCompanyGST(r.Context(), code, body.StateCode, body.GSTIN); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "updated", "company_code": code})
+}
+
+// apiSetProductTax handles PUT /api/companies/{code}/products/{productCode}/tax.
+// Body: { hsn_code?, tax_code? } — empty values clear them.
+func (h *Handler) apiSetProductTax(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		HSNCode string `json:"hsn_code"`
+		TaxCode string `json:"tax_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	product, err := h.svc.SetProductTax(r.Context(), code, chi.URLParam(r, "productCode"), body.HSNCode, body.TaxCode)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, product)
+}
+
+// apiGSTReturn handles GET /api/companies/{code}/reports/gst-return?period=&form=.
+// period is YYYY-MM (default last month). form=gstr1 or form=gstr3b returns just that
+// return in the GST portal's JSON schema; otherwise both are returned with the
+// reconciliation to the GST ledger accounts and any data issues.
+func (h *Handler) apiGSTReturn(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetGSTReturn(r.Context(), code, r.URL.Query().Get("period"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	switch r.URL.Query().Get("form") {
+	case "":
+		writeJSON(w, result)
+	case "gstr1":
+		writeJSON(w, result.GSTR1)
+	case "gstr3b":
+		writeJSON(w, result.GSTR3B)
+	default:
+		writeError(w, r, "form must be gstr1 or gstr3b", "BAD_REQUEST", http.StatusBadRequest)
+	}
+}
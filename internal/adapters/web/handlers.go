@@ -81,12 +81,32 @@ func NewHandler(svc app.ApplicationService, allowedOrigins, jwtSecret string) ht
 		// WF4 accounting screens
 		r.Get("/reports/trial-balance", h.trialBalancePage)
 		r.Get("/reports/pl", h.plReportPage)
+		r.Get("/reports/pl/comparative", h.comparativePLPage)
 		r.Get("/reports/balance-sheet", h.balanceSheetPage)
+		r.Get("/reports/balance-sheet/comparative", h.comparativeBalanceSheetPage)
+		r.Get("/reports/cash-flow", h.cashFlowPage)
 		r.Get("/reports/statement", h.accountStatementPage)
+		r.Get("/reports/layout-statement", h.layoutStatementPage)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/reports/layout-statement/map", h.layoutMappingAction)
 		r.Get("/accounting/journal-entry", h.journalEntryPage)
+		r.Get("/accounting/journal-register", h.journalRegisterPage)
+		r.Get("/accounting/journal-entries/{id}", h.journalEntryDetailPage)
+		r.Get("/reports/day-book", h.dayBookPage)
+		r.Get("/reports/general-ledger", h.generalLedgerPage)
+		r.Get("/accounting/chart-of-accounts", h.chartOfAccountsPage)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/accounting/chart-of-accounts/accounts", h.accountCreateAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/accounting/chart-of-accounts/accounts/{accountCode}", h.accountUpdateAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/accounting/chart-of-accounts/accounts/{accountCode}/active", h.accountSetActiveAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/accounting/chart-of-accounts/groups", h.accountGroupCreateAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/accounting/chart-of-accounts/parent", h.accountParentAction)
 		// WD0 — Sales / Inventory pages
 		r.Get("/sales/customers", h.customersListPage)
-		r.Get("/sales/customers/{code}", notImplementedPage) // detail — WD0 follow-on
+		r.Get("/sales/customers/new", h.customerCreatePage)
+		r.Post("/sales/customers/new", h.customerCreateAction)
+		r.Get("/sales/customers/{code}", h.customerDetailPage)
+		r.Post("/sales/customers/{code}", h.customerUpdateAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/sales/customers/{code}/active", h.customerSetActiveAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/sales/customers/{code}/merge", h.customerMergeAction)
 		r.Get("/sales/orders", h.ordersListPage)
 		r.Get("/sales/orders/new", h.orderWizardPage)
 		r.Post("/sales/orders/new", h.orderCreateAction)
@@ -97,10 +117,17 @@ func NewHandler(svc app.ApplicationService, allowedOrigins, jwtSecret string) ht
 		r.Get("/purchases/vendors", h.vendorsListPage)
 		r.Get("/purchases/vendors/new", h.vendorCreatePage)
 		r.Post("/purchases/vendors/new", h.vendorCreateAction)
+		r.Get("/purchases/vendors/{code}", h.vendorDetailPage)
+		r.Post("/purchases/vendors/{code}", h.vendorUpdateAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/purchases/vendors/{code}/active", h.vendorSetActiveAction)
+		r.With(h.RequireRoleBrowser("FINANCE_MANAGER", "ADMIN")).Post("/purchases/vendors/{code}/merge", h.vendorMergeAction)
 		r.Get("/purchases/orders", h.purchaseOrdersListPage)
 		r.Get("/purchases/orders/new", h.poWizardPage)
 		r.Post("/purchases/orders/new", h.poCreateAction)
 		r.Get("/purchases/orders/{id}", h.poDetailPage)
+		r.Get("/purchases/bills", h.vendorBillsListPage)
+		r.Get("/purchases/payment-runs", h.paymentRunsListPage)
+		r.Get("/purchases/payment-runs/{id}", h.paymentRunDetailPage)
 		r.Get("/settings/rules", notImplementedPage)
 		// Settings — user management (ADMIN only)
 		r.With(h.RequireRoleBrowser("ADMIN")).Get("/settings/users", h.usersPage)
@@ -138,13 +165,61 @@ func NewHandler(svc app.ApplicationService, allowedOrigins, jwtSecret string) ht
 			r.Get("/api/companies/{code}/trial-balance", h.apiTrialBalance)
 			r.Get("/api/companies/{code}/accounts/{accountCode}/statement", h.apiAccountStatement)
 			r.Get("/api/companies/{code}/reports/pl", h.apiProfitAndLoss)
+			r.Get("/api/companies/{code}/reports/pl/columns", h.apiColumnarProfitAndLoss)
 			r.Get("/api/companies/{code}/reports/balance-sheet", h.apiBalanceSheet)
+			r.Get("/api/companies/{code}/reports/balance-sheet/columns", h.apiColumnarBalanceSheet)
+			r.Get("/api/companies/{code}/reports/cash-flow", h.apiCashFlow)
+			r.Get("/api/companies/{code}/reports/general-ledger", h.apiGeneralLedger)
+			r.Get("/api/companies/{code}/cash-flow-mappings", h.apiCashFlowMappings)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/cash-flow-mappings/{accountCode}", h.apiSetCashFlowMapping)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}/cash", h.apiSetCashAccount)
+			r.Get("/api/companies/{code}/accounts/tree", h.apiChartOfAccounts)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/accounts", h.apiCreateAccount)
+			r.Get("/api/companies/{code}/accounts/{accountCode}", h.apiGetAccount)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}", h.apiUpdateAccount)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}/active", h.apiSetAccountActive)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/account-groups", h.apiCreateAccountGroup)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}/parent", h.apiSetAccountParent)
+			r.Get("/api/companies/{code}/statement-layouts", h.apiStatementLayouts)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/statement-layouts", h.apiCreateStatementLayout)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/statement-layouts/{layout}/lines/{lineCode}", h.apiSetStatementLayoutLine)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/statement-layouts/{layout}/accounts/{accountCode}", h.apiSetStatementLayoutAccount)
+			r.Get("/api/companies/{code}/reports/layout/{layout}", h.apiLayoutStatement)
+			r.Get("/api/companies/{code}/reports/inventory-valuation", h.apiInventoryValuation)
+			r.Get("/api/companies/{code}/reports/stock-movements", h.apiStockMovementLedger)
+			r.Get("/api/companies/{code}/reports/stock-ageing", h.apiStockAgeing)
+			r.Get("/api/companies/{code}/reports/lot-trace", h.apiLotTrace)
+			r.Get("/api/companies/{code}/reports/tds-register", h.apiTDSRegister)
+			r.Get("/api/companies/{code}/reports/gst-return", h.apiGSTReturn)
 			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/reports/refresh", h.apiRefreshViews)
+			r.Get("/api/companies/{code}/cost-centers", h.apiListCostCenters)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/cost-centers", h.apiCreateCostCenter)
+			r.Get("/api/companies/{code}/budgets", h.apiListBudgets)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/budgets", h.apiCreateBudget)
+			r.Get("/api/companies/{code}/budgets/{budgetCode}", h.apiGetBudget)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/budgets/{budgetCode}/lines", h.apiImportBudgetCSV)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/budgets/{budgetCode}/copy-actuals", h.apiCopyBudgetFromActuals)
+			r.Get("/api/companies/{code}/reports/budget-vs-actual", h.apiBudgetVsActual)
+			r.Get("/api/companies/{code}/dimensions", h.apiListDimensions)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/dimensions", h.apiCreateDimension)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/dimensions/{dimCode}/values", h.apiCreateDimensionValue)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/dimensions/{dimCode}/values/{valueCode}/active", h.apiSetDimensionValueActive)
+			r.Get("/api/companies/{code}/dimension-rules", h.apiListAccountDimensionRules)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}/dimensions/{dimCode}", h.apiRequireAccountDimension)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Delete("/api/companies/{code}/accounts/{accountCode}/dimensions/{dimCode}", h.apiRequireAccountDimension)
+			r.Get("/api/companies/{code}/journal-entries", h.apiJournalRegister)
+			r.Get("/api/companies/{code}/journal-entries/day-book", h.apiDayBook)
+			r.Get("/api/companies/{code}/journal-entries/{id}", h.apiGetJournalEntry)
 			r.Post("/api/companies/{code}/journal-entries", h.apiPostJournalEntry)
 			r.Post("/api/companies/{code}/journal-entries/validate", h.apiValidateJournalEntry)
 
 			// ── Sales (WD0) ───────────────────────────────────────────────────────
 			r.Get("/api/companies/{code}/customers", h.apiListCustomers)
+			r.Post("/api/companies/{code}/customers", h.apiCreateCustomer)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/customers/merge", h.apiMergeCustomers)
+			r.Get("/api/companies/{code}/customers/{customerCode}", h.apiGetCustomer)
+			r.Put("/api/companies/{code}/customers/{customerCode}", h.apiUpdateCustomer)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/customers/{customerCode}/active", h.apiSetCustomerActive)
 			r.Get("/api/companies/{code}/orders", h.apiListOrders)
 			r.Post("/api/companies/{code}/orders", h.apiCreateOrder)
 			r.Get("/api/companies/{code}/orders/{ref}", h.apiGetOrder)
@@ -152,9 +227,23 @@ func NewHandler(svc app.ApplicationService, allowedOrigins, jwtSecret string) ht
 			r.Post("/api/companies/{code}/orders/{ref}/ship", h.apiShipOrder)
 			r.Post("/api/companies/{code}/orders/{ref}/invoice", h.apiInvoiceOrder)
 			r.Post("/api/companies/{code}/orders/{ref}/payment", h.apiPaymentOrder)
+			r.Get("/api/companies/{code}/orders/{ref}/lot-trace", h.apiOrderLotTrace)
+			r.Get("/api/companies/{code}/orders/{ref}/einvoice", h.apiGetEInvoice)
+			r.Get("/api/companies/{code}/orders/{ref}/einvoice/payload", h.apiEInvoicePayload)
+			r.Post("/api/companies/{code}/orders/{ref}/einvoice", h.apiGenerateEInvoice)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/orders/{ref}/einvoice/cancel", h.apiCancelEInvoice)
 
 			// ── Inventory (WD0) ───────────────────────────────────────────────────
 			r.Get("/api/companies/{code}/products", h.apiListProducts)
+			r.Put("/api/companies/{code}/products/{productCode}/tracking", h.apiSetProductTracking)
+			r.Put("/api/companies/{code}/products/{productCode}/weight", h.apiSetProductWeight)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/products/{productCode}/tax", h.apiSetProductTax)
+			r.Get("/api/companies/{code}/lots", h.apiListLots)
+			r.Get("/api/companies/{code}/uoms", h.apiListUoMs)
+			r.Post("/api/companies/{code}/uoms", h.apiCreateUoM)
+			r.Get("/api/companies/{code}/products/{productCode}/units", h.apiGetProductUnits)
+			r.Put("/api/companies/{code}/products/{productCode}/units", h.apiSetDefaultUoMs)
+			r.Put("/api/companies/{code}/products/{productCode}/units/{uomCode}", h.apiSetUoMConversion)
 			r.Get("/api/companies/{code}/warehouses", notImplemented)
 			r.Get("/api/companies/{code}/stock", notImplemented)
 			r.Post("/api/companies/{code}/stock/receive", notImplemented)
@@ -162,14 +251,52 @@ func NewHandler(svc app.ApplicationService, allowedOrigins, jwtSecret string) ht
 			// ── Purchases (WD1) ──────────────────────────────────────────────────
 			r.Get("/api/companies/{code}/vendors", h.apiListVendors)
 			r.Post("/api/companies/{code}/vendors", h.apiCreateVendor)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/vendors/merge", h.apiMergeVendors)
 			r.Get("/api/companies/{code}/vendors/{vendorCode}", h.apiGetVendor)
+			r.Put("/api/companies/{code}/vendors/{vendorCode}", h.apiUpdateVendor)
+			r.Get("/api/companies/{code}/vendors/{vendorCode}/tds-status", h.apiVendorTDSStatus)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/vendors/{vendorCode}/active", h.apiSetVendorActive)
+			r.Get("/api/companies/{code}/tds-sections", h.apiListTDSSections)
+			r.Get("/api/companies/{code}/tax-codes", h.apiListTaxCodes)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/tax-codes/{taxCode}/rates", h.apiSetTaxRate)
+			r.With(h.RequireRole("ADMIN")).Put("/api/companies/{code}/gst-registration", h.apiSetCompanyGST)
+			r.With(h.RequireRole("ADMIN")).Put("/api/companies/{code}/einvoicing", h.apiSetEInvoicing)
 			r.Get("/api/companies/{code}/purchase-orders", h.apiListPurchaseOrders)
 			r.Post("/api/companies/{code}/purchase-orders", h.apiCreatePurchaseOrder)
 			r.Get("/api/companies/{code}/purchase-orders/{id}", h.apiGetPurchaseOrder)
 			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/purchase-orders/{id}/approve", h.apiApprovePO)
 			r.Post("/api/companies/{code}/purchase-orders/{id}/receive", h.apiReceivePO)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/purchase-orders/{id}/short-close", h.apiShortClosePO)
+			r.Post("/api/companies/{code}/purchase-orders/{id}/amend", h.apiAmendPO)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/purchase-orders/{id}/cancel", h.apiCancelPO)
+			r.Get("/api/companies/{code}/purchase-orders/{id}/revisions", h.apiGetPORevisions)
 			r.Post("/api/companies/{code}/purchase-orders/{id}/invoice", h.apiInvoicePO)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/purchase-orders/{id}/release-block", h.apiReleasePOBlock)
 			r.Post("/api/companies/{code}/purchase-orders/{id}/pay", h.apiPayPO)
+			r.Post("/api/companies/{code}/purchase-orders/{id}/returns", h.apiCreatePurchaseReturn)
+			r.Get("/api/companies/{code}/purchase-returns", h.apiListPurchaseReturns)
+			r.Get("/api/companies/{code}/purchase-returns/{id}", h.apiGetPurchaseReturn)
+			r.Get("/api/companies/{code}/match-tolerances", h.apiGetMatchTolerance)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/match-tolerances", h.apiSetMatchTolerance)
+			r.Get("/api/companies/{code}/reorder-policies", h.apiListReorderPolicies)
+			r.Post("/api/companies/{code}/reorder-policies", h.apiSetReorderPolicy)
+			r.Get("/api/companies/{code}/replenishment/suggestions", h.apiReplenishmentSuggestions)
+			r.Post("/api/companies/{code}/replenishment/purchase-orders", h.apiCreateReplenishmentPOs)
+			r.Get("/api/companies/{code}/landed-cost-vouchers", h.apiListLandedCostVouchers)
+			r.Post("/api/companies/{code}/landed-cost-vouchers", h.apiCreateLandedCostVoucher)
+			r.Get("/api/companies/{code}/landed-cost-vouchers/{id}", h.apiGetLandedCostVoucher)
+			r.Get("/api/companies/{code}/vendor-bills", h.apiListVendorBills)
+			r.Post("/api/companies/{code}/vendor-bills", h.apiCreateVendorBill)
+			r.Get("/api/companies/{code}/vendor-bills/{id}", h.apiGetVendorBill)
+			r.Post("/api/companies/{code}/vendor-bills/{id}/pay", h.apiPayVendorBill)
+			r.Get("/api/companies/{code}/payment-runs", h.apiListPaymentRuns)
+			r.Post("/api/companies/{code}/payment-runs", h.apiCreatePaymentRun)
+			r.Get("/api/companies/{code}/payment-runs/{id}", h.apiGetPaymentRun)
+			r.Put("/api/companies/{code}/payment-runs/{id}/items/{itemID}", h.apiSetPaymentRunItem)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/payment-runs/{id}/approve", h.apiApprovePaymentRun)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Post("/api/companies/{code}/payment-runs/{id}/post", h.apiPostPaymentRun)
+			r.Get("/api/companies/{code}/payment-runs/{id}/export", h.apiExportPaymentRun)
+			r.With(h.RequireRole("FINANCE_MANAGER", "ADMIN")).Put("/api/companies/{code}/accounts/{accountCode}/bank-details", h.apiSetHouseBankDetails)
 
 			// ── Users (ADMIN only) ────────────────────────────────────────────────
 			r.With(h.RequireRole("ADMIN")).Get("/api/companies/{code}/users", h.apiListUsers)
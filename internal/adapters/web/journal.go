@@ -0,0 +1,212 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"accounting-agent/internal/core"
+	"accounting-agent/web/templates/pages"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// journalFilterFromQuery parses the journal register query parameters:
+// from, to, doc_type, account, min_amount, max_amount, reference, created_by, page, page_size.
+func journalFilterFromQuery(q url.Values) (core.JournalFilter, error) {
+	f := core.JournalFilter{
+		FromDate:     q.Get("from"),
+		ToDate:       q.Get("to"),
+		DocumentType: q.Get("doc_type"),
+		AccountCode:  q.Get("account"),
+		Reference:    q.Get("reference"),
+		CreatedBy:    q.Get("created_by"),
+	}
+	for _, p := range []struct {
+		name string
+		dst  **decimal.Decimal
+	}{{"min_amount", &f.MinAmount}, {"max_amount", &f.MaxAmount}} {
+		if s := q.Get(p.name); s != "" {
+			v, err := decimal.NewFromString(s)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s %q", p.name, s)
+			}
+			*p.dst = &v
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"page", &f.Page}, {"page_size", &f.PageSize}} {
+		if s := q.Get(p.name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 1 {
+				return f, fmt.Errorf("invalid %s %q", p.name, s)
+			}
+			*p.dst = v
+		}
+	}
+	return f, nil
+}
+
+// ── Browser page handlers ─────────────────────────────────────────────────────
+
+// journalRegisterPage handles GET /accounting/journal-register.
+func (h *Handler) journalRegisterPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Journal Register", "journal-register")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := journalFilterFromQuery(r.URL.Query())
+	var reg *core.JournalRegister
+	if err == nil {
+		reg, err = h.svc.GetJournalRegister(r.Context(), d.CompanyCode, filter)
+	}
+	if err != nil {
+		d.FlashMsg = "Failed to load journal register: " + err.Error()
+		d.FlashKind = "error"
+		reg = &core.JournalRegister{CompanyCode: d.CompanyCode, Page: 1, PageSize: core.DefaultJournalPageSize}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.JournalRegister(d, reg, filter).Render(r.Context(), w)
+}
+
+// journalEntryDetailPage handles GET /accounting/journal-entries/{id}.
+func (h *Handler) journalEntryDetailPage(w http.ResponseWriter, r *http.Request) {
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid journal entry ID", http.StatusBadRequest)
+		return
+	}
+
+	d := h.buildAppLayoutData(r, "Journal Entry", "journal-register")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	entry, err := h.svc.GetJournalEntry(r.Context(), d.CompanyCode, entryID)
+	if err != nil {
+		d.FlashMsg = "Journal entry not found: " + err.Error()
+		d.FlashKind = "error"
+		entry = nil
+	} else {
+		d.Title = fmt.Sprintf("Journal Entry %d", entryID)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.JournalEntryDetail(d, entry).Render(r.Context(), w)
+}
+
+// dayBookPage handles GET /reports/day-book.
+func (h *Handler) dayBookPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Day Book", "day-book")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	book, err := h.svc.GetDayBook(r.Context(), d.CompanyCode, r.URL.Query().Get("date"))
+	if err != nil {
+		d.FlashMsg = "Failed to load day book: " + err.Error()
+		d.FlashKind = "error"
+		book = &core.DayBook{CompanyCode: d.CompanyCode, Date: r.URL.Query().Get("date")}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.DayBook(d, book).Render(r.Context(), w)
+}
+
+// generalLedgerPage handles GET /reports/general-ledger.
+func (h *Handler) generalLedgerPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "General Ledger", "general-ledger")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	gl, err := h.svc.GetGeneralLedger(r.Context(), d.CompanyCode, from, to)
+	if err != nil {
+		d.FlashMsg = "Failed to load general ledger: " + err.Error()
+		d.FlashKind = "error"
+		gl = &core.GeneralLedger{CompanyCode: d.CompanyCode, FromDate: from, ToDate: to}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.GeneralLedger(d, gl).Render(r.Context(), w)
+}
+
+// ── API handlers ──────────────────────────────────────────────────────────────
+
+// apiJournalRegister handles GET /api/companies/{code}/journal-entries.
+// Query: from, to, doc_type, account, min_amount, max_amount, reference, created_by, page, page_size.
+func (h *Handler) apiJournalRegister(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	filter, err := journalFilterFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	reg, err := h.svc.GetJournalRegister(r.Context(), code, filter)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, reg)
+}
+
+// apiGetJournalEntry handles GET /api/companies/{code}/journal-entries/{id}.
+func (h *Handler) apiGetJournalEntry(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid journal entry ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	entry, err := h.svc.GetJournalEntry(r.Context(), code, entryID)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, entry)
+}
+
+// apiDayBook handles GET /api/companies/{code}/journal-entries/day-book?date=YYYY-MM-DD.
+func (h *Handler) apiDayBook(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	book, err := h.svc.GetDayBook(r.Context(), code, r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, book)
+}
+
+// apiGeneralLedger handles GET /api/companies/{code}/reports/general-ledger?from=&to=.
+func (h *Handler) apiGeneralLedger(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	gl, err := h.svc.GetGeneralLedger(r.Context(), code, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, gl)
+}
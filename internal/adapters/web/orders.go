@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"accounting-agent/internal/app"
+	"accounting-agent/internal/core"
 	"accounting-agent/web/templates/pages"
 
 	"github.com/go-chi/chi/v5"
@@ -24,6 +26,15 @@ func (h *Handler) customersListPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fe := r.URL.Query().Get("flash_error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+	if fs := r.URL.Query().Get("flash_success"); fs != "" {
+		d.FlashMsg = fs
+		d.FlashKind = "success"
+	}
+
 	result, err := h.svc.ListCustomers(r.Context(), d.CompanyCode)
 	if err != nil {
 		d.FlashMsg = "Failed to load customers: " + err.Error()
@@ -35,6 +46,206 @@ func (h *Handler) customersListPage(w http.ResponseWriter, r *http.Request) {
 	_ = pages.CustomersList(d, result).Render(r.Context(), w)
 }
 
+// customerCreatePage handles GET /sales/customers/new.
+func (h *Handler) customerCreatePage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "New Customer", "customers")
+
+	if fe := r.URL.Query().Get("error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.CustomerForm(d).Render(r.Context(), w)
+}
+
+// customerCreateAction handles POST /sales/customers/new — HTML form submission.
+func (h *Handler) customerCreateAction(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, "/sales/customers/new?error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/sales/customers?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	req := app.CreateCustomerRequest{
+		CompanyCode: claims.CompanyCode,
+		Code:        r.FormValue("code"),
+		Name:        r.FormValue("name"),
+		Email:       r.FormValue("email"),
+		Phone:       r.FormValue("phone"),
+		Address:     r.FormValue("address"),
+	}
+	if cl := r.FormValue("credit_limit"); cl != "" {
+		if d, err := decimal.NewFromString(cl); err == nil {
+			req.CreditLimit = d
+		}
+	}
+	if pt := r.FormValue("payment_terms_days"); pt != "" {
+		if n, err := strconv.Atoi(pt); err == nil && n > 0 {
+			req.PaymentTermsDays = n
+		}
+	}
+
+	if req.Code == "" || req.Name == "" {
+		http.Redirect(w, r, "/sales/customers/new?error=code+and+name+are+required", http.StatusSeeOther)
+		return
+	}
+
+	if _, err := h.svc.CreateCustomer(r.Context(), req); err != nil {
+		http.Redirect(w, r, "/sales/customers/new?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/sales/customers?flash_success=Customer+"+url.QueryEscape(req.Code)+"+created", http.StatusSeeOther)
+}
+
+// customerDetailPage handles GET /sales/customers/{code} — edit form, status and merge.
+func (h *Handler) customerDetailPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Customer", "customers")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	if fe := r.URL.Query().Get("flash_error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+	if fs := r.URL.Query().Get("flash_success"); fs != "" {
+		d.FlashMsg = fs
+		d.FlashKind = "success"
+	}
+
+	result, err := h.svc.GetCustomer(r.Context(), d.CompanyCode, chi.URLParam(r, "code"))
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_ = pages.CustomerDetail(d, nil, "", nil).Render(r.Context(), w)
+		return
+	}
+	c := result.Customer
+	d.Title = c.Name
+
+	// Active customers are the merge candidates; a merged customer's survivor is usually among them.
+	var candidates []pages.MergeCandidate
+	mergedInto := ""
+	if c.MergedIntoID != nil {
+		mergedInto = fmt.Sprintf("#%d", *c.MergedIntoID)
+	}
+	if active, err := h.svc.ListCustomers(r.Context(), d.CompanyCode); err == nil {
+		for _, o := range active.Customers {
+			if c.MergedIntoID != nil && o.ID == *c.MergedIntoID {
+				mergedInto = o.Code
+			}
+			if o.ID != c.ID {
+				candidates = append(candidates, pages.MergeCandidate{Code: o.Code, Name: o.Name})
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.CustomerDetail(d, c, mergedInto, candidates).Render(r.Context(), w)
+}
+
+// customerUpdateAction handles POST /sales/customers/{code} — HTML form submission.
+func (h *Handler) customerUpdateAction(w http.ResponseWriter, r *http.Request) {
+	customerCode := chi.URLParam(r, "code")
+	detailURL := "/sales/customers/" + url.PathEscape(customerCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/sales/customers?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	creditLimit, err := decimal.NewFromString(r.FormValue("credit_limit"))
+	if err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+credit+limit", http.StatusSeeOther)
+		return
+	}
+	version, _ := strconv.Atoi(r.FormValue("version"))
+	paymentTerms, _ := strconv.Atoi(r.FormValue("payment_terms_days"))
+	_, err = h.svc.UpdateCustomer(r.Context(), app.UpdateCustomerRequest{
+		CompanyCode:      claims.CompanyCode,
+		Code:             customerCode,
+		Version:          version,
+		Name:             r.FormValue("name"),
+		Email:            r.FormValue("email"),
+		Phone:            r.FormValue("phone"),
+		Address:          r.FormValue("address"),
+		CreditLimit:      creditLimit,
+		PaymentTermsDays: paymentTerms,
+	})
+	if err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, detailURL+"?flash_success=Customer+updated", http.StatusSeeOther)
+}
+
+// customerSetActiveAction handles POST /sales/customers/{code}/active — deactivate or reactivate.
+func (h *Handler) customerSetActiveAction(w http.ResponseWriter, r *http.Request) {
+	customerCode := chi.URLParam(r, "code")
+	detailURL := "/sales/customers/" + url.PathEscape(customerCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/sales/customers?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	active := r.FormValue("active") == "true"
+	if _, err := h.svc.SetCustomerActive(r.Context(), claims.CompanyCode, customerCode, active); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	msg := "Customer+deactivated"
+	if active {
+		msg = "Customer+reactivated"
+	}
+	http.Redirect(w, r, detailURL+"?flash_success="+msg, http.StatusSeeOther)
+}
+
+// customerMergeAction handles POST /sales/customers/{code}/merge — merges this customer into survivor_code.
+func (h *Handler) customerMergeAction(w http.ResponseWriter, r *http.Request) {
+	customerCode := chi.URLParam(r, "code")
+	detailURL := "/sales/customers/" + url.PathEscape(customerCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/sales/customers?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	survivor := r.FormValue("survivor_code")
+	if _, err := h.svc.MergeCustomers(r.Context(), claims.CompanyCode, customerCode, survivor); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/sales/customers/"+url.PathEscape(survivor)+
+		"?flash_success="+url.QueryEscape("Customer "+customerCode+" merged into "+survivor), http.StatusSeeOther)
+}
+
 // productsListPage handles GET /inventory/products.
 func (h *Handler) productsListPage(w http.ResponseWriter, r *http.Request) {
 	d := h.buildAppLayoutData(r, "Products", "products")
@@ -243,6 +454,167 @@ func (h *Handler) apiListCustomers(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result.Customers)
 }
 
+// apiCreateCustomer handles POST /api/companies/{code}/customers.
+// Body: { code, name, email?, phone?, address?, credit_limit?, payment_terms_days? }
+func (h *Handler) apiCreateCustomer(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		Code             string          `json:"code"`
+		Name             string          `json:"name"`
+		Email            string          `json:"email"`
+		Phone            string          `json:"phone"`
+		Address          string          `json:"address"`
+		CreditLimit      decimal.Decimal `json:"credit_limit"`
+		PaymentTermsDays int             `json:"payment_terms_days"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Code == "" || body.Name == "" {
+		writeError(w, r, "code and name are required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.CreateCustomer(r.Context(), app.CreateCustomerRequest{
+		CompanyCode:      code,
+		Code:             body.Code,
+		Name:             body.Name,
+		Email:            body.Email,
+		Phone:            body.Phone,
+		Address:          body.Address,
+		CreditLimit:      body.CreditLimit,
+		PaymentTermsDays: body.PaymentTermsDays,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result.Customer)
+}
+
+// apiGetCustomer handles GET /api/companies/{code}/customers/{customerCode}.
+// Inactive and merged customers are returned too.
+func (h *Handler) apiGetCustomer(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetCustomer(r.Context(), code, chi.URLParam(r, "customerCode"))
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result.Customer)
+}
+
+// apiUpdateCustomer handles PUT /api/companies/{code}/customers/{customerCode}.
+// Body: { version, name, email?, phone?, address?, credit_limit?, payment_terms_days?, state_code?, gstin? }
+// Every editable field is replaced. Returns 409 if version is no longer current.
+func (h *Handler) apiUpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		Version          int             `json:"version"`
+		Name             string          `json:"name"`
+		Email            string          `json:"email"`
+		Phone            string          `json:"phone"`
+		Address          string          `json:"address"`
+		CreditLimit      decimal.Decimal `json:"credit_limit"`
+		PaymentTermsDays int             `json:"payment_terms_days"`
+		StateCode        string          `json:"state_code"`
+		GSTIN            string          `json:"gstin"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Version <= 0 {
+		writeError(w, r, "version is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.UpdateCustomer(r.Context(), app.UpdateCustomerRequest{
+		CompanyCode:      code,
+		Code:             chi.URLParam(r, "customerCode"),
+		Version:          body.Version,
+		Name:             body.Name,
+		Email:            body.Email,
+		Phone:            body.Phone,
+		Address:          body.Address,
+		CreditLimit:      body.CreditLimit,
+		PaymentTermsDays: body.PaymentTermsDays,
+		StateCode:        body.StateCode,
+		GSTIN:            body.GSTIN,
+	})
+	if err != nil {
+		writeUpdateError(w, r, err)
+		return
+	}
+	writeJSON(w, result.Customer)
+}
+
+// apiSetCustomerActive handles PUT /api/companies/{code}/customers/{customerCode}/active.
+// Body: { active }
+func (h *Handler) apiSetCustomerActive(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		Active *bool `json:"active"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Active == nil {
+		writeError(w, r, "active is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.SetCustomerActive(r.Context(), code, chi.URLParam(r, "customerCode"), *body.Active)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Customer)
+}
+
+// apiMergeCustomers handles POST /api/companies/{code}/customers/merge.
+// Body: { duplicate_code, survivor_code }
+func (h *Handler) apiMergeCustomers(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		DuplicateCode string `json:"duplicate_code"`
+		SurvivorCode  string `json:"survivor_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.DuplicateCode == "" || body.SurvivorCode == "" {
+		writeError(w, r, "duplicate_code and survivor_code are required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.MergeCustomers(r.Context(), code, body.DuplicateCode, body.SurvivorCode)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Merge)
+}
+
 // apiListProducts handles GET /api/companies/{code}/products.
 func (h *Handler) apiListProducts(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
@@ -257,6 +629,179 @@ func (h *Handler) apiListProducts(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result.Products)
 }
 
+// apiSetProductTracking handles PUT /api/companies/{code}/products/{productCode}/tracking.
+// Body: { tracking_mode: NONE|LOT|SERIAL, lot_pick_strategy?: FEFO|FIFO }
+func (h *Handler) apiSetProductTracking(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		TrackingMode    string `json:"tracking_mode"`
+		LotPickStrategy string `json:"lot_pick_strategy"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.TrackingMode == "" {
+		writeError(w, r, "tracking_mode is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	productCode := chi.URLParam(r, "productCode")
+	if err := h.svc.SetProductTracking(r.Context(), code, productCode, body.TrackingMode, body.LotPickStrategy); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "updated", "product_code": productCode})
+}
+
+// apiSetProductWeight handles PUT /api/companies/{code}/products/{productCode}/weight.
+// Body: {"unit_weight": "2.5"} — weight of one stock unit, used for landed cost allocation by weight.
+func (h *Handler) apiSetProductWeight(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		UnitWeight string `json:"unit_weight"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	unitWeight, err := decimal.NewFromString(body.UnitWeight)
+	if err != nil {
+		writeError(w, r, "invalid unit_weight", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	productCode := chi.URLParam(r, "productCode")
+	if err := h.svc.SetProductWeight(r.Context(), code, productCode, unitWeight); err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "updated", "product_code": productCode, "unit_weight": unitWeight.String()})
+}
+
+// apiListLots handles GET /api/companies/{code}/lots?product=.
+func (h *Handler) apiListLots(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListLots(r.Context(), code, r.URL.Query().Get("product"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Lots)
+}
+
+// apiListUoMs handles GET /api/companies/{code}/uoms.
+func (h *Handler) apiListUoMs(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListUoMs(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Units)
+}
+
+// apiCreateUoM handles POST /api/companies/{code}/uoms.
+func (h *Handler) apiCreateUoM(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Code == "" {
+		writeError(w, r, "code is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	unit, err := h.svc.CreateUoM(r.Context(), code, body.Code, body.Name)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, unit)
+}
+
+// apiGetProductUnits handles GET /api/companies/{code}/products/{productCode}/units.
+func (h *Handler) apiGetProductUnits(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	units, err := h.svc.GetProductUnits(r.Context(), code, chi.URLParam(r, "productCode"))
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, units)
+}
+
+// apiSetDefaultUoMs handles PUT /api/companies/{code}/products/{productCode}/units.
+// Body: {"purchase_uom": "CTN", "sales_uom": ""} — empty resets to the stock unit.
+func (h *Handler) apiSetDefaultUoMs(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		PurchaseUoM string `json:"purchase_uom"`
+		SalesUoM    string `json:"sales_uom"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	units, err := h.svc.SetDefaultUoMs(r.Context(), app.SetDefaultUoMsRequest{
+		CompanyCode: code,
+		ProductCode: chi.URLParam(r, "productCode"),
+		PurchaseUoM: body.PurchaseUoM,
+		SalesUoM:    body.SalesUoM,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, units)
+}
+
+// apiSetUoMConversion handles PUT /api/companies/{code}/products/{productCode}/units/{uomCode}.
+// Body: {"factor": "24"} — the number of stock units in one uomCode.
+func (h *Handler) apiSetUoMConversion(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		Factor string `json:"factor"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	factor, err := decimal.NewFromString(body.Factor)
+	if err != nil {
+		writeError(w, r, "invalid factor", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	units, err := h.svc.SetUoMConversion(r.Context(), code, chi.URLParam(r, "productCode"), chi.URLParam(r, "uomCode"), factor)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, units)
+}
+
 // apiListOrders handles GET /api/companies/{code}/orders.
 func (h *Handler) apiListOrders(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
@@ -292,7 +837,7 @@ func (h *Handler) apiGetOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiCreateOrder handles POST /api/companies/{code}/orders.
-// Body: { customer_code, order_date?, currency?, notes?, lines: [{product_code, quantity, unit_price?}] }
+// Body: { customer_code, order_date?, currency?, notes?, lines: [{product_code, quantity, unit_price?, tax_code?, dimensions?}] }
 func (h *Handler) apiCreateOrder(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -305,9 +850,12 @@ func (h *Handler) apiCreateOrder(w http.ResponseWriter, r *http.Request) {
 		Currency     string `json:"currency"`
 		Notes        string `json:"notes"`
 		Lines        []struct {
-			ProductCode string `json:"product_code"`
-			Quantity    string `json:"quantity"`
-			UnitPrice   string `json:"unit_price"`
+			ProductCode string               `json:"product_code"`
+			Quantity    string               `json:"quantity"`
+			Unit        string               `json:"unit"`
+			UnitPrice   string               `json:"unit_price"`
+			TaxCode     string               `json:"tax_code"`
+			Dimensions  []core.LineDimension `json:"dimensions"`
 		} `json:"lines"`
 	}
 	if !decodeJSON(w, r, &body) {
@@ -341,7 +889,10 @@ func (h *Handler) apiCreateOrder(w http.ResponseWriter, r *http.Request) {
 		req.Lines = append(req.Lines, app.OrderLineInput{
 			ProductCode: l.ProductCode,
 			Quantity:    qty,
+			Unit:        l.Unit,
 			UnitPrice:   price,
+			TaxCode:     l.TaxCode,
+			Dimensions:  l.Dimensions,
 		})
 	}
 
@@ -376,7 +927,28 @@ func (h *Handler) apiShipOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ref := chi.URLParam(r, "ref")
-	result, err := h.svc.ShipOrder(r.Context(), ref, code)
+
+	var body struct {
+		Picks []struct {
+			ProductCode string `json:"product_code"`
+			LotNumber   string `json:"lot_number"`
+			Quantity    string `json:"quantity"`
+		} `json:"picks"`
+	}
+	// Best-effort decode; without picks, lot-tracked products ship by FEFO/FIFO.
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	var picks []app.LotPickInput
+	for i, p := range body.Picks {
+		qty, err := decimal.NewFromString(p.Quantity)
+		if err != nil || !qty.IsPositive() {
+			writeError(w, r, fmt.Sprintf("pick %d: invalid quantity", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		picks = append(picks, app.LotPickInput{ProductCode: p.ProductCode, LotNumber: p.LotNumber, Quantity: qty})
+	}
+
+	result, err := h.svc.ShipOrderWithPicks(r.Context(), ref, code, picks)
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
 		return
@@ -384,6 +956,20 @@ func (h *Handler) apiShipOrder(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result.Order)
 }
 
+// apiOrderLotTrace handles GET /api/companies/{code}/orders/{ref}/lot-trace.
+func (h *Handler) apiOrderLotTrace(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetOrderLotTrace(r.Context(), chi.URLParam(r, "ref"), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
 // apiInvoiceOrder handles POST /api/companies/{code}/orders/{ref}/invoice.
 func (h *Handler) apiInvoiceOrder(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
@@ -0,0 +1,272 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"accounting-agent/internal/app"
+	"accounting-agent/web/templates/pages"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ── Browser page handlers ─────────────────────────────────────────────────────
+
+// paymentRunsListPage handles GET /purchases/payment-runs.
+func (h *Handler) paymentRunsListPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Payment Runs", "payment-runs")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	statusFilter := r.URL.Query().Get("status")
+
+	result, err := h.svc.ListPaymentRuns(r.Context(), d.CompanyCode, statusFilter)
+	if err != nil {
+		d.FlashMsg = "Failed to load payment runs: " + err.Error()
+		d.FlashKind = "error"
+		result = &app.PaymentRunsResult{}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.PaymentRunsList(d, result, statusFilter).Render(r.Context(), w)
+}
+
+// paymentRunDetailPage handles GET /purchases/payment-runs/{id}.
+func (h *Handler) paymentRunDetailPage(w http.ResponseWriter, r *http.Request) {
+	runID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid payment run ID", http.StatusBadRequest)
+		return
+	}
+
+	d := h.buildAppLayoutData(r, "Payment Run", "payment-runs")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.svc.GetPaymentRun(r.Context(), d.CompanyCode, runID)
+	if err != nil {
+		d.FlashMsg = "Payment run not found: " + err.Error()
+		d.FlashKind = "error"
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = pages.PaymentRunDetail(d, nil, d.CompanyCode).Render(r.Context(), w)
+		return
+	}
+
+	d.Title = fmt.Sprintf("Payment Run %d", runID)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.PaymentRunDetail(d, result.Run, d.CompanyCode).Render(r.Context(), w)
+}
+
+// ── API handlers ──────────────────────────────────────────────────────────────
+
+// paymentRunID parses the {id} URL parameter, writing a 400 on failure.
+func paymentRunID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	runID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid payment run ID", "BAD_REQUEST", http.StatusBadRequest)
+		return 0, false
+	}
+	return runID, true
+}
+
+// apiListPaymentRuns handles GET /api/companies/{code}/payment-runs?status=.
+func (h *Handler) apiListPaymentRuns(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListPaymentRuns(r.Context(), code, r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Runs)
+}
+
+// apiCreatePaymentRun handles POST /api/companies/{code}/payment-runs.
+// Body: { due_by, payment_date?, bank_account_code?, vendor_code? }
+func (h *Handler) apiCreatePaymentRun(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		DueBy           string `json:"due_by"`
+		PaymentDate     string `json:"payment_date"`
+		BankAccountCode string `json:"bank_account_code"`
+		VendorCode      string `json:"vendor_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.DueBy == "" {
+		writeError(w, r, "due_by is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	req := app.CreatePaymentRunRequest{
+		CompanyCode:     code,
+		DueBy:           body.DueBy,
+		PaymentDate:     body.PaymentDate,
+		BankAccountCode: body.BankAccountCode,
+		VendorCode:      body.VendorCode,
+	}
+	if claims := authFromContext(r.Context()); claims != nil {
+		req.CreatedBy = claims.Username
+	}
+	result, err := h.svc.CreatePaymentRun(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Run)
+}
+
+// apiGetPaymentRun handles GET /api/companies/{code}/payment-runs/{id}.
+func (h *Handler) apiGetPaymentRun(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	runID, ok := paymentRunID(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.svc.GetPaymentRun(r.Context(), code, runID)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result.Run)
+}
+
+// apiSetPaymentRunItem handles PUT /api/companies/{code}/payment-runs/{id}/items/{itemID}.
+// Body: { excluded }
+func (h *Handler) apiSetPaymentRunItem(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	runID, ok := paymentRunID(w, r)
+	if !ok {
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemID"))
+	if err != nil {
+		writeError(w, r, "invalid item ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Excluded bool `json:"excluded"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	result, err := h.svc.SetPaymentRunItemExcluded(r.Context(), code, runID, itemID, body.Excluded)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Run)
+}
+
+// apiApprovePaymentRun handles POST /api/companies/{code}/payment-runs/{id}/approve.
+func (h *Handler) apiApprovePaymentRun(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	runID, ok := paymentRunID(w, r)
+	if !ok {
+		return
+	}
+	approvedBy := ""
+	if claims := authFromContext(r.Context()); claims != nil {
+		approvedBy = claims.Username
+	}
+	result, err := h.svc.ApprovePaymentRun(r.Context(), code, runID, approvedBy)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Run)
+}
+
+// apiPostPaymentRun handles POST /api/companies/{code}/payment-runs/{id}/post.
+func (h *Handler) apiPostPaymentRun(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	runID, ok := paymentRunID(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.svc.PostPaymentRun(r.Context(), code, runID)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Run)
+}
+
+// apiExportPaymentRun handles GET /api/companies/{code}/payment-runs/{id}/export?format=pain001|csv.
+// Responds with the bank file as an attachment.
+func (h *Handler) apiExportPaymentRun(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	runID, ok := paymentRunID(w, r)
+	if !ok {
+		return
+	}
+	file, err := h.svc.ExportPaymentRun(r.Context(), code, runID, r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+	_, _ = w.Write(file.Content)
+}
+
+// apiSetHouseBankDetails handles PUT /api/companies/{code}/accounts/{accountCode}/bank-details.
+// Body: { bank_account_number, bank_code? }
+func (h *Handler) apiSetHouseBankDetails(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		BankAccountNumber string `json:"bank_account_number"`
+		BankCode          string `json:"bank_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.BankAccountNumber == "" {
+		writeError(w, r, "bank_account_number is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	accountCode := chi.URLParam(r, "accountCode")
+	if err := h.svc.SetHouseBankDetails(r.Context(), code, accountCode, body.BankAccountNumber, body.BankCode); err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{
+		"account_code":        accountCode,
+		"bank_account_number": body.BankAccountNumber,
+		"bank_code":           body.BankCode,
+	})
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiListTDSSections handles GET /api/companies/{code}/tds-sections.
+func (h *Handler) apiListTDSSections(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListTDSSections(r.Context())
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Sections)
+}
+
+// apiVendorTDSStatus handles GET /api/companies/{code}/vendors/{vendorCode}/tds-status?date=.
+// date (YYYY-MM-DD) picks the financial year; it defaults to today.
+func (h *Handler) apiVendorTDSStatus(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetVendorTDSStatus(r.Context(), code, chi.URLParam(r, "vendorCode"), r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// apiTDSRegister handles GET /api/companies/{code}/reports/tds-register?fy=&quarter=.
+// fy is the year the financial year starts in (2026 = FY 2026-27) and quarter is 1–4
+// (1 = April–June); both default to the current quarter.
+func (h *Handler) apiTDSRegister(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	now := time.Now()
+	fy := core.FinancialYear(now)
+	quarter := (int(now.Month())+8)%12/3 + 1
+	if v := r.URL.Query().Get("fy"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, r, "fy must be a year, e.g. 2026", "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		fy = parsed
+	}
+	if v := r.URL.Query().Get("quarter"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, r, "quarter must be 1-4", "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		quarter = parsed
+	}
+	result, err := h.svc.GetTDSRegister(r.Context(), code, fy, quarter)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
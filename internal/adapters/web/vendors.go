@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"accounting-agent/internal/app"
+	"accounting-agent/internal/core"
 	"accounting-agent/web/templates/pages"
 
 	"github.com/go-chi/chi/v5"
@@ -94,6 +95,14 @@ func (h *Handler) vendorCreateAction(w http.ResponseWriter, r *http.Request) {
 		PaymentTermsDays:          paymentTerms,
 		APAccountCode:             apAccountCode,
 		DefaultExpenseAccountCode: r.FormValue("default_expense_account_code"),
+		BankAccountName:           r.FormValue("bank_account_name"),
+		BankAccountNumber:         r.FormValue("bank_account_number"),
+		BankCode:                  r.FormValue("bank_code"),
+		Currency:                  r.FormValue("currency"),
+		PAN:                       r.FormValue("pan"),
+		TDSSectionCode:            r.FormValue("tds_section_code"),
+		StateCode:                 r.FormValue("state_code"),
+		GSTIN:                     r.FormValue("gstin"),
 	}
 
 	if req.Code == "" || req.Name == "" {
@@ -110,6 +119,153 @@ func (h *Handler) vendorCreateAction(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/purchases/vendors?flash_success=Vendor+"+url.QueryEscape(req.Code)+"+created", http.StatusSeeOther)
 }
 
+// vendorDetailPage handles GET /purchases/vendors/{code} — edit form, status and merge.
+func (h *Handler) vendorDetailPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Vendor", "vendors")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	if fe := r.URL.Query().Get("flash_error"); fe != "" {
+		d.FlashMsg = fe
+		d.FlashKind = "error"
+	}
+	if fs := r.URL.Query().Get("flash_success"); fs != "" {
+		d.FlashMsg = fs
+		d.FlashKind = "success"
+	}
+
+	result, err := h.svc.GetVendor(r.Context(), d.CompanyCode, chi.URLParam(r, "code"))
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_ = pages.VendorDetail(d, nil, "", nil).Render(r.Context(), w)
+		return
+	}
+	v := result.Vendor
+	d.Title = v.Name
+
+	// Active vendors are the merge candidates; a merged vendor's survivor is usually among them.
+	var candidates []pages.MergeCandidate
+	mergedInto := ""
+	if v.MergedIntoID != nil {
+		mergedInto = fmt.Sprintf("#%d", *v.MergedIntoID)
+	}
+	if active, err := h.svc.ListVendors(r.Context(), d.CompanyCode); err == nil {
+		for _, o := range active.Vendors {
+			if v.MergedIntoID != nil && o.ID == *v.MergedIntoID {
+				mergedInto = o.Code
+			}
+			if o.ID != v.ID {
+				candidates = append(candidates, pages.MergeCandidate{Code: o.Code, Name: o.Name})
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.VendorDetail(d, v, mergedInto, candidates).Render(r.Context(), w)
+}
+
+// vendorUpdateAction handles POST /purchases/vendors/{code} — HTML form submission.
+func (h *Handler) vendorUpdateAction(w http.ResponseWriter, r *http.Request) {
+	vendorCode := chi.URLParam(r, "code")
+	detailURL := "/purchases/vendors/" + url.PathEscape(vendorCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/purchases/vendors?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	version, _ := strconv.Atoi(r.FormValue("version"))
+	paymentTerms, _ := strconv.Atoi(r.FormValue("payment_terms_days"))
+	_, err := h.svc.UpdateVendor(r.Context(), app.UpdateVendorRequest{
+		CompanyCode:               claims.CompanyCode,
+		Code:                      vendorCode,
+		Version:                   version,
+		Name:                      r.FormValue("name"),
+		ContactPerson:             r.FormValue("contact_person"),
+		Email:                     r.FormValue("email"),
+		Phone:                     r.FormValue("phone"),
+		Address:                   r.FormValue("address"),
+		PaymentTermsDays:          paymentTerms,
+		APAccountCode:             r.FormValue("ap_account_code"),
+		DefaultExpenseAccountCode: r.FormValue("default_expense_account_code"),
+		BankAccountName:           r.FormValue("bank_account_name"),
+		BankAccountNumber:         r.FormValue("bank_account_number"),
+		BankCode:                  r.FormValue("bank_code"),
+		Currency:                  r.FormValue("currency"),
+		PAN:                       r.FormValue("pan"),
+		TDSSectionCode:            r.FormValue("tds_section_code"),
+		StateCode:                 r.FormValue("state_code"),
+		GSTIN:                     r.FormValue("gstin"),
+	})
+	if err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, detailURL+"?flash_success=Vendor+updated", http.StatusSeeOther)
+}
+
+// vendorSetActiveAction handles POST /purchases/vendors/{code}/active — deactivate or reactivate.
+func (h *Handler) vendorSetActiveAction(w http.ResponseWriter, r *http.Request) {
+	vendorCode := chi.URLParam(r, "code")
+	detailURL := "/purchases/vendors/" + url.PathEscape(vendorCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/purchases/vendors?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	active := r.FormValue("active") == "true"
+	if _, err := h.svc.SetVendorActive(r.Context(), claims.CompanyCode, vendorCode, active); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	msg := "Vendor+deactivated"
+	if active {
+		msg = "Vendor+reactivated"
+	}
+	http.Redirect(w, r, detailURL+"?flash_success="+msg, http.StatusSeeOther)
+}
+
+// vendorMergeAction handles POST /purchases/vendors/{code}/merge — merges this vendor into survivor_code.
+func (h *Handler) vendorMergeAction(w http.ResponseWriter, r *http.Request) {
+	vendorCode := chi.URLParam(r, "code")
+	detailURL := "/purchases/vendors/" + url.PathEscape(vendorCode)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error=invalid+form", http.StatusSeeOther)
+		return
+	}
+
+	claims := authFromContext(r.Context())
+	if claims == nil || claims.CompanyCode == "" {
+		http.Redirect(w, r, "/purchases/vendors?flash_error=company+not+found", http.StatusSeeOther)
+		return
+	}
+
+	survivor := r.FormValue("survivor_code")
+	if _, err := h.svc.MergeVendors(r.Context(), claims.CompanyCode, vendorCode, survivor); err != nil {
+		http.Redirect(w, r, detailURL+"?flash_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/purchases/vendors/"+url.PathEscape(survivor)+
+		"?flash_success="+url.QueryEscape("Vendor "+vendorCode+" merged into "+survivor), http.StatusSeeOther)
+}
+
 // purchaseOrdersListPage handles GET /purchases/orders.
 func (h *Handler) purchaseOrdersListPage(w http.ResponseWriter, r *http.Request) {
 	d := h.buildAppLayoutData(r, "Purchase Orders", "purchase-orders")
@@ -185,8 +341,14 @@ func (h *Handler) poCreateAction(w http.ResponseWriter, r *http.Request) {
 		CompanyCode: claims.CompanyCode,
 		VendorCode:  r.FormValue("vendor_code"),
 		PODate:      poDate,
+		Currency:    r.FormValue("currency"),
 		Notes:       r.FormValue("notes"),
 	}
+	if rate := r.FormValue("exchange_rate"); rate != "" {
+		if d, err := decimal.NewFromString(rate); err == nil {
+			req.ExchangeRate = d
+		}
+	}
 
 	if req.VendorCode == "" {
 		http.Redirect(w, r, "/purchases/orders/new?error=vendor+is+required", http.StatusSeeOther)
@@ -271,7 +433,7 @@ func (h *Handler) poDetailPage(w http.ResponseWriter, r *http.Request) {
 		d.FlashMsg = "Purchase order not found: " + err.Error()
 		d.FlashKind = "error"
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		_ = pages.PODetail(d, nil, d.CompanyCode).Render(r.Context(), w)
+		_ = pages.PODetail(d, nil, nil, d.CompanyCode).Render(r.Context(), w)
 		return
 	}
 
@@ -279,8 +441,13 @@ func (h *Handler) poDetailPage(w http.ResponseWriter, r *http.Request) {
 		d.Title = "PO " + *result.PurchaseOrder.PONumber
 	}
 
+	var revisions []core.PurchaseOrderRevision
+	if rev, err := h.svc.GetPurchaseOrderRevisions(r.Context(), d.CompanyCode, poID); err == nil {
+		revisions = rev.Revisions
+	}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	_ = pages.PODetail(d, result.PurchaseOrder, d.CompanyCode).Render(r.Context(), w)
+	_ = pages.PODetail(d, result.PurchaseOrder, revisions, d.CompanyCode).Render(r.Context(), w)
 }
 
 // ── API handlers ──────────────────────────────────────────────────────────────
@@ -300,7 +467,8 @@ func (h *Handler) apiListVendors(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiCreateVendor handles POST /api/companies/{code}/vendors.
-// Body: { code, name, contact_person?, email?, phone?, address?, payment_terms_days?, ap_account_code?, default_expense_account_code? }
+// Body: { code, name, contact_person?, email?, phone?, address?, payment_terms_days?, ap_account_code?,
+// default_expense_account_code?, bank_account_name?, bank_account_number?, bank_code?, state_code?, gstin? }
 func (h *Handler) apiCreateVendor(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -317,6 +485,14 @@ func (h *Handler) apiCreateVendor(w http.ResponseWriter, r *http.Request) {
 		PaymentTermsDays          int    `json:"payment_terms_days"`
 		APAccountCode             string `json:"ap_account_code"`
 		DefaultExpenseAccountCode string `json:"default_expense_account_code"`
+		BankAccountName           string `json:"bank_account_name"`
+		BankAccountNumber         string `json:"bank_account_number"`
+		BankCode                  string `json:"bank_code"`
+		Currency                  string `json:"currency"`
+		PAN                       string `json:"pan"`
+		TDSSectionCode            string `json:"tds_section_code"`
+		StateCode                 string `json:"state_code"`
+		GSTIN                     string `json:"gstin"`
 	}
 	if !decodeJSON(w, r, &body) {
 		return
@@ -342,6 +518,14 @@ func (h *Handler) apiCreateVendor(w http.ResponseWriter, r *http.Request) {
 		PaymentTermsDays:          body.PaymentTermsDays,
 		APAccountCode:             body.APAccountCode,
 		DefaultExpenseAccountCode: body.DefaultExpenseAccountCode,
+		BankAccountName:           body.BankAccountName,
+		BankAccountNumber:         body.BankAccountNumber,
+		BankCode:                  body.BankCode,
+		Currency:                  body.Currency,
+		PAN:                       body.PAN,
+		TDSSectionCode:            body.TDSSectionCode,
+		StateCode:                 body.StateCode,
+		GSTIN:                     body.GSTIN,
 	})
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
@@ -352,6 +536,7 @@ func (h *Handler) apiCreateVendor(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiGetVendor handles GET /api/companies/{code}/vendors/{vendorCode}.
+// Inactive and merged vendors are returned too.
 func (h *Handler) apiGetVendor(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -359,18 +544,130 @@ func (h *Handler) apiGetVendor(w http.ResponseWriter, r *http.Request) {
 	}
 	vendorCode := chi.URLParam(r, "vendorCode")
 
-	result, err := h.svc.ListVendors(r.Context(), code)
+	result, err := h.svc.GetVendor(r.Context(), code, vendorCode)
 	if err != nil {
-		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		writeError(w, r, "vendor "+vendorCode+" not found", "NOT_FOUND", http.StatusNotFound)
 		return
 	}
-	for _, v := range result.Vendors {
-		if v.Code == vendorCode {
-			writeJSON(w, v)
-			return
-		}
+	writeJSON(w, result.Vendor)
+}
+
+// apiUpdateVendor handles PUT /api/companies/{code}/vendors/{vendorCode}.
+// Body: the apiCreateVendor fields except code, plus version. Every editable field is replaced. Returns 409 if version is no longer current.
+func (h *Handler) apiUpdateVendor(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		Version                   int    `json:"version"`
+		Name                      string `json:"name"`
+		ContactPerson             string `json:"contact_person"`
+		Email                     string `json:"email"`
+		Phone                     string `json:"phone"`
+		Address                   string `json:"address"`
+		PaymentTermsDays          int    `json:"payment_terms_days"`
+		APAccountCode             string `json:"ap_account_code"`
+		DefaultExpenseAccountCode string `json:"default_expense_account_code"`
+		BankAccountName           string `json:"bank_account_name"`
+		BankAccountNumber         string `json:"bank_account_number"`
+		BankCode                  string `json:"bank_code"`
+		Currency                  string `json:"currency"`
+		PAN                       string `json:"pan"`
+		TDSSectionCode            string `json:"tds_section_code"`
+		StateCode                 string `json:"state_code"`
+		GSTIN                     string `json:"gstin"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Version <= 0 {
+		writeError(w, r, "version is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.UpdateVendor(r.Context(), app.UpdateVendorRequest{
+		CompanyCode:               code,
+		Code:                      chi.URLParam(r, "vendorCode"),
+		Version:                   body.Version,
+		Name:                      body.Name,
+		ContactPerson:             body.ContactPerson,
+		Email:                     body.Email,
+		Phone:                     body.Phone,
+		Address:                   body.Address,
+		PaymentTermsDays:          body.PaymentTermsDays,
+		APAccountCode:             body.APAccountCode,
+		DefaultExpenseAccountCode: body.DefaultExpenseAccountCode,
+		BankAccountName:           body.BankAccountName,
+		BankAccountNumber:         body.BankAccountNumber,
+		BankCode:                  body.BankCode,
+		Currency:                  body.Currency,
+		PAN:                       body.PAN,
+		TDSSectionCode:            body.TDSSectionCode,
+		StateCode:                 body.StateCode,
+		GSTIN:                     body.GSTIN,
+	})
+	if err != nil {
+		writeUpdateError(w, r, err)
+		return
 	}
-	writeError(w, r, "vendor "+vendorCode+" not found", "NOT_FOUND", http.StatusNotFound)
+	writeJSON(w, result.Vendor)
+}
+
+// apiSetVendorActive handles PUT /api/companies/{code}/vendors/{vendorCode}/active.
+// Body: { active }
+func (h *Handler) apiSetVendorActive(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		Active *bool `json:"active"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Active == nil {
+		writeError(w, r, "active is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.SetVendorActive(r.Context(), code, chi.URLParam(r, "vendorCode"), *body.Active)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Vendor)
+}
+
+// apiMergeVendors handles POST /api/companies/{code}/vendors/merge.
+// Body: { duplicate_code, survivor_code }
+func (h *Handler) apiMergeVendors(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		DuplicateCode string `json:"duplicate_code"`
+		SurvivorCode  string `json:"survivor_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.DuplicateCode == "" || body.SurvivorCode == "" {
+		writeError(w, r, "duplicate_code and survivor_code are required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.MergeVendors(r.Context(), code, body.DuplicateCode, body.SurvivorCode)
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Merge)
 }
 
 // apiListPurchaseOrders handles GET /api/companies/{code}/purchase-orders.
@@ -389,7 +686,8 @@ func (h *Handler) apiListPurchaseOrders(w http.ResponseWriter, r *http.Request)
 }
 
 // apiCreatePurchaseOrder handles POST /api/companies/{code}/purchase-orders.
-// Body: { vendor_code, po_date?, notes?, lines: [{product_code?, description, quantity, unit_cost, expense_account_code?}] }
+// Body: { vendor_code, po_date?, currency?, exchange_rate?, notes?, lines: [{product_code?, description, quantity, unit_cost, expense_account_code?, tax_code?}] }
+// currency defaults to the vendor's currency; exchange_rate is required for a foreign currency.
 func (h *Handler) apiCreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -397,15 +695,20 @@ func (h *Handler) apiCreatePurchaseOrder(w http.ResponseWriter, r *http.Request)
 	}
 
 	var body struct {
-		VendorCode string `json:"vendor_code"`
-		PODate     string `json:"po_date"`
-		Notes      string `json:"notes"`
-		Lines      []struct {
-			ProductCode        string `json:"product_code"`
-			Description        string `json:"description"`
-			Quantity           string `json:"quantity"`
-			UnitCost           string `json:"unit_cost"`
-			ExpenseAccountCode string `json:"expense_account_code"`
+		VendorCode   string `json:"vendor_code"`
+		PODate       string `json:"po_date"`
+		Currency     string `json:"currency"`
+		ExchangeRate string `json:"exchange_rate"`
+		Notes        string `json:"notes"`
+		Lines        []struct {
+			ProductCode        string               `json:"product_code"`
+			Description        string               `json:"description"`
+			Quantity           string               `json:"quantity"`
+			Unit               string               `json:"unit"`
+			UnitCost           string               `json:"unit_cost"`
+			ExpenseAccountCode string               `json:"expense_account_code"`
+			TaxCode            string               `json:"tax_code"`
+			Dimensions         []core.LineDimension `json:"dimensions"`
 		} `json:"lines"`
 	}
 	if !decodeJSON(w, r, &body) {
@@ -425,8 +728,17 @@ func (h *Handler) apiCreatePurchaseOrder(w http.ResponseWriter, r *http.Request)
 		CompanyCode: code,
 		VendorCode:  body.VendorCode,
 		PODate:      body.PODate,
+		Currency:    body.Currency,
 		Notes:       body.Notes,
 	}
+	if body.ExchangeRate != "" {
+		rate, err := decimal.NewFromString(body.ExchangeRate)
+		if err != nil {
+			writeError(w, r, "invalid exchange_rate", "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		req.ExchangeRate = rate
+	}
 
 	for i, l := range body.Lines {
 		qty, err := decimal.NewFromString(l.Quantity)
@@ -439,8 +751,11 @@ func (h *Handler) apiCreatePurchaseOrder(w http.ResponseWriter, r *http.Request)
 			ProductCode:        l.ProductCode,
 			Description:        l.Description,
 			Quantity:           qty,
+			Unit:               l.Unit,
 			UnitCost:           cost,
 			ExpenseAccountCode: l.ExpenseAccountCode,
+			TaxCode:            l.TaxCode,
+			Dimensions:         l.Dimensions,
 		})
 	}
 
@@ -512,6 +827,11 @@ func (h *Handler) apiReceivePO(w http.ResponseWriter, r *http.Request) {
 		Lines         []struct {
 			POLineID    int    `json:"po_line_id"`
 			QtyReceived string `json:"qty_received"`
+			Lots        []struct {
+				LotNumber  string `json:"lot_number"`
+				ExpiryDate string `json:"expiry_date"`
+				Quantity   string `json:"quantity"`
+			} `json:"lots"`
 		} `json:"lines"`
 	}
 	if !decodeJSON(w, r, &body) {
@@ -540,10 +860,20 @@ func (h *Handler) apiReceivePO(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, fmt.Sprintf("line %d: invalid qty_received", i+1), "BAD_REQUEST", http.StatusBadRequest)
 			return
 		}
-		req.Lines = append(req.Lines, app.ReceivedLineInput{
+		line := app.ReceivedLineInput{
 			POLineID:    l.POLineID,
 			QtyReceived: qty,
-		})
+		}
+		for _, lot := range l.Lots {
+			// Quantity may be omitted for serials and single-lot receipts.
+			lotQty, _ := decimal.NewFromString(lot.Quantity)
+			line.Lots = append(line.Lots, app.LotInput{
+				LotNumber:  lot.LotNumber,
+				ExpiryDate: lot.ExpiryDate,
+				Quantity:   lotQty,
+			})
+		}
+		req.Lines = append(req.Lines, line)
 	}
 
 	result, err := h.svc.ReceivePurchaseOrder(r.Context(), req)
@@ -555,7 +885,8 @@ func (h *Handler) apiReceivePO(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiInvoicePO handles POST /api/companies/{code}/purchase-orders/{id}/invoice.
-// Body: { invoice_number, invoice_date, invoice_amount }
+// Body: { invoice_number, invoice_date, invoice_amount?, lines?: [{po_line_id, quantity, unit_price}] }
+// invoice_amount is required when lines are omitted.
 func (h *Handler) apiInvoicePO(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
@@ -572,6 +903,11 @@ func (h *Handler) apiInvoicePO(w http.ResponseWriter, r *http.Request) {
 		InvoiceNumber string `json:"invoice_number"`
 		InvoiceDate   string `json:"invoice_date"`
 		InvoiceAmount string `json:"invoice_amount"`
+		Lines         []struct {
+			POLineID  int    `json:"po_line_id"`
+			Quantity  string `json:"quantity"`
+			UnitPrice string `json:"unit_price"`
+		} `json:"lines"`
 	}
 	if !decodeJSON(w, r, &body) {
 		return
@@ -588,19 +924,37 @@ func (h *Handler) apiInvoicePO(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	invoiceAmount, err := decimal.NewFromString(body.InvoiceAmount)
-	if err != nil || invoiceAmount.IsZero() {
-		writeError(w, r, "invalid invoice_amount", "BAD_REQUEST", http.StatusBadRequest)
-		return
+	invoiceAmount := decimal.Zero
+	if body.InvoiceAmount != "" || len(body.Lines) == 0 {
+		invoiceAmount, err = decimal.NewFromString(body.InvoiceAmount)
+		if err != nil || (invoiceAmount.IsZero() && len(body.Lines) == 0) {
+			writeError(w, r, "invalid invoice_amount", "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
 	}
 
-	result, err := h.svc.RecordVendorInvoice(r.Context(), app.VendorInvoiceRequest{
+	req := app.VendorInvoiceRequest{
 		CompanyCode:   code,
 		POID:          poID,
 		InvoiceNumber: body.InvoiceNumber,
 		InvoiceDate:   invoiceDate,
 		InvoiceAmount: invoiceAmount,
-	})
+	}
+	for i, l := range body.Lines {
+		qty, err := decimal.NewFromString(l.Quantity)
+		if err != nil || !qty.IsPositive() {
+			writeError(w, r, fmt.Sprintf("line %d: invalid quantity", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		price, err := decimal.NewFromString(l.UnitPrice)
+		if err != nil {
+			writeError(w, r, fmt.Sprintf("line %d: invalid unit_price", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		req.Lines = append(req.Lines, app.VendorInvoiceLineInput{POLineID: l.POLineID, Quantity: qty, UnitPrice: price})
+	}
+
+	result, err := h.svc.RecordVendorInvoice(r.Context(), req)
 	if err != nil {
 		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
 		return
@@ -613,49 +967,759 @@ func (h *Handler) apiInvoicePO(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, response{PurchaseOrder: result.PurchaseOrder, Warning: result.Warning})
 }
 
-// apiPayPO handles POST /api/companies/{code}/purchase-orders/{id}/pay.
-// Body: { bank_account_code?, payment_date? }
-func (h *Handler) apiPayPO(w http.ResponseWriter, r *http.Request) {
+// apiShortClosePO handles POST /api/companies/{code}/purchase-orders/{id}/short-close.
+// Closes a PARTIALLY_RECEIVED PO, cancelling the balance not yet delivered.
+func (h *Handler) apiShortClosePO(w http.ResponseWriter, r *http.Request) {
 	code := companyCode(r)
 	if !h.requireCompanyAccess(w, r, code) {
 		return
 	}
-	idStr := chi.URLParam(r, "id")
-	poID, err := strconv.Atoi(idStr)
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
 		return
 	}
 
 	var body struct {
-		BankAccountCode string `json:"bank_account_code"`
-		PaymentDate     string `json:"payment_date"`
+		Reason string `json:"reason"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
 	}
-	// Best-effort decode; both fields have defaults.
-	_ = json.NewDecoder(r.Body).Decode(&body)
 
-	bankCode := body.BankAccountCode
-	if bankCode == "" {
-		bankCode = "1000"
+	closedBy := ""
+	if claims := authFromContext(r.Context()); claims != nil {
+		closedBy = claims.Username
+	}
+	result, err := h.svc.ShortClosePurchaseOrder(r.Context(), code, poID, body.Reason, closedBy)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
 	}
+	writeJSON(w, result.PurchaseOrder)
+}
 
-	paymentDate := time.Now()
-	if body.PaymentDate != "" {
-		if pd, err := time.Parse("2006-01-02", body.PaymentDate); err == nil {
-			paymentDate = pd
+// apiAmendPO handles POST /api/companies/{code}/purchase-orders/{id}/amend.
+// Body: { expected_delivery_date?, reason?, require_reapproval?,
+// lines?: [{po_line_id?, product_code?, description?, quantity, unit?, unit_cost, expense_account_code?, tax_code?}] }
+// Omitting lines keeps them unchanged. Amendments by users who cannot approve POs always
+// return the PO to DRAFT for re-approval.
+func (h *Handler) apiAmendPO(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		ExpectedDeliveryDate string `json:"expected_delivery_date"`
+		Reason               string `json:"reason"`
+		RequireReapproval    bool   `json:"require_reapproval"`
+		Lines                []struct {
+			POLineID           int                  `json:"po_line_id"`
+			ProductCode        string               `json:"product_code"`
+			Description        string               `json:"description"`
+			Quantity           string               `json:"quantity"`
+			Unit               string               `json:"unit"`
+			UnitCost           string               `json:"unit_cost"`
+			ExpenseAccountCode string               `json:"expense_account_code"`
+			TaxCode            string               `json:"tax_code"`
+			Dimensions         []core.LineDimension `json:"dimensions"`
+		} `json:"lines"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	req := app.AmendPurchaseOrderRequest{
+		CompanyCode:          code,
+		POID:                 poID,
+		ExpectedDeliveryDate: body.ExpectedDeliveryDate,
+		Reason:               body.Reason,
+		RequireReapproval:    body.RequireReapproval,
+	}
+	if claims := authFromContext(r.Context()); claims != nil {
+		req.AmendedBy = claims.Username
+		if !hasRole(claims.Role, []string{"FINANCE_MANAGER", "ADMIN"}) {
+			req.RequireReapproval = true
+		}
+	}
+	if body.Lines != nil {
+		req.Lines = make([]app.POAmendLineInput, len(body.Lines))
+		for i, l := range body.Lines {
+			qty, err := decimal.NewFromString(l.Quantity)
+			if err != nil {
+				writeError(w, r, fmt.Sprintf("line %d: invalid quantity", i+1), "BAD_REQUEST", http.StatusBadRequest)
+				return
+			}
+			cost, err := decimal.NewFromString(l.UnitCost)
+			if err != nil {
+				writeError(w, r, fmt.Sprintf("line %d: invalid unit_cost", i+1), "BAD_REQUEST", http.StatusBadRequest)
+				return
+			}
+			req.Lines[i] = app.POAmendLineInput{
+				POLineID:           l.POLineID,
+				ProductCode:        l.ProductCode,
+				Description:        l.Description,
+				Quantity:           qty,
+				Unit:               l.Unit,
+				UnitCost:           cost,
+				ExpenseAccountCode: l.ExpenseAccountCode,
+				TaxCode:            l.TaxCode,
+				Dimensions:         l.Dimensions,
+			}
 		}
 	}
 
-	result, err := h.svc.PayVendor(r.Context(), app.PayVendorRequest{
-		CompanyCode:     code,
-		POID:            poID,
-		BankAccountCode: bankCode,
-		PaymentDate:     paymentDate,
-	})
+	result, err := h.svc.AmendPurchaseOrder(r.Context(), req)
 	if err != nil {
-		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
 		return
 	}
 	writeJSON(w, result.PurchaseOrder)
 }
 
+// apiCancelPO handles POST /api/companies/{code}/purchase-orders/{id}/cancel.
+// Cancels a DRAFT or APPROVED PO on which nothing has been received.
+func (h *Handler) apiCancelPO(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	cancelledBy := ""
+	if claims := authFromContext(r.Context()); claims != nil {
+		cancelledBy = claims.Username
+	}
+	result, err := h.svc.CancelPurchaseOrder(r.Context(), code, poID, body.Reason, cancelledBy)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.PurchaseOrder)
+}
+
+// apiGetPORevisions handles GET /api/companies/{code}/purchase-orders/{id}/revisions.
+func (h *Handler) apiGetPORevisions(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetPurchaseOrderRevisions(r.Context(), code, poID)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Revisions)
+}
+
+// apiReleasePOBlock handles POST /api/companies/{code}/purchase-orders/{id}/release-block.
+// Accepts the invoice variances on a payment-blocked PO and posts them.
+func (h *Handler) apiReleasePOBlock(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.ReleasePaymentBlock(r.Context(), code, poID)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.PurchaseOrder)
+}
+
+// apiGetMatchTolerance handles GET /api/companies/{code}/match-tolerances.
+func (h *Handler) apiGetMatchTolerance(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetMatchTolerance(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Tolerance)
+}
+
+// apiSetMatchTolerance handles PUT /api/companies/{code}/match-tolerances.
+// Body: { price_tolerance_pct, qty_tolerance_pct, amount_tolerance, variance_treatment? }
+func (h *Handler) apiSetMatchTolerance(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	var body struct {
+		PriceTolerancePct string `json:"price_tolerance_pct"`
+		QtyTolerancePct   string `json:"qty_tolerance_pct"`
+		AmountTolerance   string `json:"amount_tolerance"`
+		VarianceTreatment string `json:"variance_treatment"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	values := make([]decimal.Decimal, 3)
+	for i, f := range []struct{ name, value string }{
+		{"price_tolerance_pct", body.PriceTolerancePct},
+		{"qty_tolerance_pct", body.QtyTolerancePct},
+		{"amount_tolerance", body.AmountTolerance},
+	} {
+		if f.value == "" {
+			continue
+		}
+		v, err := decimal.NewFromString(f.value)
+		if err != nil {
+			writeError(w, r, "invalid "+f.name, "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		values[i] = v
+	}
+	result, err := h.svc.SetMatchTolerance(r.Context(), app.SetMatchToleranceRequest{
+		CompanyCode:       code,
+		PriceTolerancePct: values[0],
+		QtyTolerancePct:   values[1],
+		AmountTolerance:   values[2],
+		VarianceTreatment: body.VarianceTreatment,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Tolerance)
+}
+
+// apiPayPO handles POST /api/companies/{code}/purchase-orders/{id}/pay.
+// Body: { bank_account_code?, payment_date? }
+func (h *Handler) apiPayPO(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	idStr := chi.URLParam(r, "id")
+	poID, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		BankAccountCode string `json:"bank_account_code"`
+		PaymentDate     string `json:"payment_date"`
+	}
+	// Best-effort decode; both fields have defaults.
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	bankCode := body.BankAccountCode
+	if bankCode == "" {
+		bankCode = "1000"
+	}
+
+	paymentDate := time.Now()
+	if body.PaymentDate != "" {
+		if pd, err := time.Parse("2006-01-02", body.PaymentDate); err == nil {
+			paymentDate = pd
+		}
+	}
+
+	result, err := h.svc.PayVendor(r.Context(), app.PayVendorRequest{
+		CompanyCode:     code,
+		POID:            poID,
+		BankAccountCode: bankCode,
+		PaymentDate:     paymentDate,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.PurchaseOrder)
+}
+
+// apiListReorderPolicies handles GET /api/companies/{code}/reorder-policies.
+func (h *Handler) apiListReorderPolicies(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListReorderPolicies(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Policies)
+}
+
+// apiSetReorderPolicy handles POST /api/companies/{code}/reorder-policies.
+// Body: { product_code, warehouse_code?, reorder_point, reorder_qty, lead_time_days?, preferred_vendor_code? }
+func (h *Handler) apiSetReorderPolicy(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		ProductCode         string `json:"product_code"`
+		WarehouseCode       string `json:"warehouse_code"`
+		ReorderPoint        string `json:"reorder_point"`
+		ReorderQty          string `json:"reorder_qty"`
+		LeadTimeDays        int    `json:"lead_time_days"`
+		PreferredVendorCode string `json:"preferred_vendor_code"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if body.ProductCode == "" {
+		writeError(w, r, "product_code is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	reorderPoint, err := decimal.NewFromString(body.ReorderPoint)
+	if err != nil {
+		writeError(w, r, "invalid reorder_point", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	reorderQty, err := decimal.NewFromString(body.ReorderQty)
+	if err != nil {
+		writeError(w, r, "invalid reorder_qty", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.SetReorderPolicy(r.Context(), app.SetReorderPolicyRequest{
+		CompanyCode:         code,
+		ProductCode:         body.ProductCode,
+		WarehouseCode:       body.WarehouseCode,
+		ReorderPoint:        reorderPoint,
+		ReorderQty:          reorderQty,
+		LeadTimeDays:        body.LeadTimeDays,
+		PreferredVendorCode: body.PreferredVendorCode,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result.Policy)
+}
+
+// apiReplenishmentSuggestions handles GET /api/companies/{code}/replenishment/suggestions.
+func (h *Handler) apiReplenishmentSuggestions(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.GetReplenishmentSuggestions(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Suggestions)
+}
+
+// apiCreateReplenishmentPOs handles POST /api/companies/{code}/replenishment/purchase-orders.
+// Body: { po_date?, product_codes? }
+func (h *Handler) apiCreateReplenishmentPOs(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		PODate       string   `json:"po_date"`
+		ProductCodes []string `json:"product_codes"`
+	}
+	// Best-effort decode; an empty body replenishes everything as of today.
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	result, err := h.svc.CreateReplenishmentPOs(r.Context(), app.CreateReplenishmentPOsRequest{
+		CompanyCode:  code,
+		PODate:       body.PODate,
+		ProductCodes: body.ProductCodes,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, map[string]any{
+		"purchase_orders": result.PurchaseOrders,
+		"skipped":         result.Skipped,
+	})
+}
+
+// apiListLandedCostVouchers handles GET /api/companies/{code}/landed-cost-vouchers.
+func (h *Handler) apiListLandedCostVouchers(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListLandedCostVouchers(r.Context(), code)
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Vouchers)
+}
+
+// apiGetLandedCostVoucher handles GET /api/companies/{code}/landed-cost-vouchers/{id}.
+func (h *Handler) apiGetLandedCostVoucher(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	voucherID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid landed cost voucher ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetLandedCostVoucher(r.Context(), code, voucherID)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result.Voucher)
+}
+
+// apiCreateLandedCostVoucher handles POST /api/companies/{code}/landed-cost-vouchers.
+// Body: { po_numbers?, receipt_movement_ids?, charges: [{description, amount}],
+// allocation_method?: VALUE|QUANTITY|WEIGHT, vendor_code?, credit_account_code?, voucher_date?, notes? }
+func (h *Handler) apiCreateLandedCostVoucher(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		PONumbers          []string `json:"po_numbers"`
+		ReceiptMovementIDs []int    `json:"receipt_movement_ids"`
+		Charges            []struct {
+			Description string `json:"description"`
+			Amount      string `json:"amount"`
+		} `json:"charges"`
+		AllocationMethod  string `json:"allocation_method"`
+		VendorCode        string `json:"vendor_code"`
+		CreditAccountCode string `json:"credit_account_code"`
+		VoucherDate       string `json:"voucher_date"`
+		Notes             string `json:"notes"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if len(body.Charges) == 0 {
+		writeError(w, r, "at least one charge is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	charges := make([]app.LandedCostChargeInput, len(body.Charges))
+	for i, c := range body.Charges {
+		amount, err := decimal.NewFromString(c.Amount)
+		if err != nil {
+			writeError(w, r, fmt.Sprintf("charge %d: invalid amount", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		charges[i] = app.LandedCostChargeInput{Description: c.Description, Amount: amount}
+	}
+
+	result, err := h.svc.CreateLandedCostVoucher(r.Context(), app.CreateLandedCostVoucherRequest{
+		CompanyCode:        code,
+		VoucherDate:        body.VoucherDate,
+		VendorCode:         body.VendorCode,
+		CreditAccountCode:  body.CreditAccountCode,
+		AllocationMethod:   body.AllocationMethod,
+		PONumbers:          body.PONumbers,
+		ReceiptMovementIDs: body.ReceiptMovementIDs,
+		Charges:            charges,
+		Notes:              body.Notes,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result.Voucher)
+}
+
+// apiListPurchaseReturns handles GET /api/companies/{code}/purchase-returns?status=&vendor_code=.
+func (h *Handler) apiListPurchaseReturns(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	q := r.URL.Query()
+	result, err := h.svc.ListPurchaseReturns(r.Context(), code, q.Get("status"), q.Get("vendor_code"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Returns)
+}
+
+// apiGetPurchaseReturn handles GET /api/companies/{code}/purchase-returns/{id}.
+func (h *Handler) apiGetPurchaseReturn(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	returnID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase return ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetPurchaseReturn(r.Context(), code, returnID)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result.Return)
+}
+
+// apiCreatePurchaseReturn handles POST /api/companies/{code}/purchase-orders/{id}/returns.
+// Body: { warehouse_code?, return_date?, reason?, lines: [{po_line_id, quantity}] }
+func (h *Handler) apiCreatePurchaseReturn(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	poID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid purchase order ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		WarehouseCode string `json:"warehouse_code"`
+		ReturnDate    string `json:"return_date"`
+		Reason        string `json:"reason"`
+		Lines         []struct {
+			POLineID int    `json:"po_line_id"`
+			Quantity string `json:"quantity"`
+		} `json:"lines"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if len(body.Lines) == 0 {
+		writeError(w, r, "at least one line is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	lines := make([]app.PurchaseReturnLineInput, len(body.Lines))
+	for i, l := range body.Lines {
+		qty, err := decimal.NewFromString(l.Quantity)
+		if err != nil || !qty.IsPositive() {
+			writeError(w, r, fmt.Sprintf("line %d: invalid quantity", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		lines[i] = app.PurchaseReturnLineInput{POLineID: l.POLineID, Quantity: qty}
+	}
+
+	result, err := h.svc.CreatePurchaseReturn(r.Context(), app.CreatePurchaseReturnRequest{
+		CompanyCode:   code,
+		POID:          poID,
+		WarehouseCode: body.WarehouseCode,
+		ReturnDate:    body.ReturnDate,
+		Reason:        body.Reason,
+		Lines:         lines,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result.Return)
+}
+
+// vendorBillsListPage handles GET /purchases/bills.
+func (h *Handler) vendorBillsListPage(w http.ResponseWriter, r *http.Request) {
+	d := h.buildAppLayoutData(r, "Vendor Bills", "vendor-bills")
+	if d.CompanyCode == "" {
+		http.Error(w, "Company not resolved — please log in again", http.StatusUnauthorized)
+		return
+	}
+
+	statusFilter := r.URL.Query().Get("status")
+
+	result, err := h.svc.ListVendorBills(r.Context(), d.CompanyCode, statusFilter)
+	if err != nil {
+		d.FlashMsg = "Failed to load vendor bills: " + err.Error()
+		d.FlashKind = "error"
+		result = &app.VendorBillsResult{}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pages.VendorBillsList(d, result, statusFilter).Render(r.Context(), w)
+}
+
+// apiListVendorBills handles GET /api/companies/{code}/vendor-bills?status=.
+func (h *Handler) apiListVendorBills(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	result, err := h.svc.ListVendorBills(r.Context(), code, r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Bills)
+}
+
+// apiGetVendorBill handles GET /api/companies/{code}/vendor-bills/{id}.
+func (h *Handler) apiGetVendorBill(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	billID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid vendor bill ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	result, err := h.svc.GetVendorBill(r.Context(), code, billID)
+	if err != nil {
+		writeError(w, r, err.Error(), "NOT_FOUND", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result.Bill)
+}
+
+// apiCreateVendorBill handles POST /api/companies/{code}/vendor-bills.
+// Body: { vendor_code, bill_number, bill_date?, due_date?, notes?,
+// lines: [{description?, quantity?, unit_cost, expense_account_code?, product_code?, tax_code?}] }
+func (h *Handler) apiCreateVendorBill(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+
+	var body struct {
+		VendorCode string `json:"vendor_code"`
+		BillNumber string `json:"bill_number"`
+		BillDate   string `json:"bill_date"`
+		DueDate    string `json:"due_date"`
+		Notes      string `json:"notes"`
+		Lines      []struct {
+			Description        string `json:"description"`
+			Quantity           string `json:"quantity"`
+			UnitCost           string `json:"unit_cost"`
+			ExpenseAccountCode string `json:"expense_account_code"`
+			TaxCode            string `json:"tax_code"`
+			ProductCode        string `json:"product_code"`
+		} `json:"lines"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if len(body.Lines) == 0 {
+		writeError(w, r, "at least one line is required", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	lines := make([]app.VendorBillLineInput, len(body.Lines))
+	for i, l := range body.Lines {
+		qty := decimal.NewFromInt(1)
+		if l.Quantity != "" {
+			var err error
+			if qty, err = decimal.NewFromString(l.Quantity); err != nil {
+				writeError(w, r, fmt.Sprintf("line %d: invalid quantity", i+1), "BAD_REQUEST", http.StatusBadRequest)
+				return
+			}
+		}
+		unitCost, err := decimal.NewFromString(l.UnitCost)
+		if err != nil {
+			writeError(w, r, fmt.Sprintf("line %d: invalid unit_cost", i+1), "BAD_REQUEST", http.StatusBadRequest)
+			return
+		}
+		lines[i] = app.VendorBillLineInput{
+			ProductCode:        l.ProductCode,
+			Description:        l.Description,
+			Quantity:           qty,
+			UnitCost:           unitCost,
+			ExpenseAccountCode: l.ExpenseAccountCode,
+			TaxCode:            l.TaxCode,
+		}
+	}
+
+	result, err := h.svc.CreateVendorBill(r.Context(), app.CreateVendorBillRequest{
+		CompanyCode: code,
+		VendorCode:  body.VendorCode,
+		BillNumber:  body.BillNumber,
+		BillDate:    body.BillDate,
+		DueDate:     body.DueDate,
+		Notes:       body.Notes,
+		Lines:       lines,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, result.Bill)
+}
+
+// apiPayVendorBill handles POST /api/companies/{code}/vendor-bills/{id}/pay.
+// Body: { bank_account_code?, payment_date? } — defaults to 1000 and today.
+func (h *Handler) apiPayVendorBill(w http.ResponseWriter, r *http.Request) {
+	code := companyCode(r)
+	if !h.requireCompanyAccess(w, r, code) {
+		return
+	}
+	billID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, "invalid vendor bill ID", "BAD_REQUEST", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		BankAccountCode string `json:"bank_account_code"`
+		PaymentDate     string `json:"payment_date"`
+	}
+	// Best-effort decode; both fields have defaults.
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	bankCode := body.BankAccountCode
+	if bankCode == "" {
+		bankCode = "1000"
+	}
+	paymentDate := time.Now()
+	if body.PaymentDate != "" {
+		if pd, err := time.Parse("2006-01-02", body.PaymentDate); err == nil {
+			paymentDate = pd
+		}
+	}
+
+	result, err := h.svc.PayVendorBill(r.Context(), app.PayVendorBillRequest{
+		CompanyCode:     code,
+		BillID:          billID,
+		BankAccountCode: bankCode,
+		PaymentDate:     paymentDate,
+	})
+	if err != nil {
+		writeError(w, r, err.Error(), "INTERNAL_ERROR", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result.Bill)
+}
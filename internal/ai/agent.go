@@ -58,7 +58,8 @@ type AgentService interface {
 	// InterpretEvent interprets a natural language event as a double-entry journal entry proposal.
 	// This path uses structured output (Responses API JSON schema mode) and must remain untouched
 	// until InterpretDomainAction has been stable across ≥2 domain phases with write tools.
-	InterpretEvent(ctx context.Context, naturalLanguage string, chartOfAccounts string, documentTypes string, company *core.Company) (*core.AgentResponse, error)
+	// dimensions lists the company's analytic dimensions and values the lines may be tagged with.
+	InterpretEvent(ctx context.Context, naturalLanguage string, chartOfAccounts string, documentTypes string, dimensions string, company *core.Company) (*core.AgentResponse, error)
 
 	// InterpretDomainAction routes a natural language input through the agentic tool loop.
 	// The agent calls read tools autonomously to gather context, then either proposes a write
@@ -81,7 +82,7 @@ func NewAgent(apiKey string) *Agent {
 	return &Agent{client: &client}
 }
 
-func (a *Agent) InterpretEvent(ctx context.Context, naturalLanguage string, chartOfAccounts string, documentTypes string, company *core.Company) (*core.AgentResponse, error) {
+func (a *Agent) InterpretEvent(ctx context.Context, naturalLanguage string, chartOfAccounts string, documentTypes string, dimensions string, company *core.Company) (*core.AgentResponse, error) {
 	prompt := fmt.Sprintf(`You are an expert accountant operating within a multi-currency, multi-company ledger system.
 Your goal is to interpret a business event described in natural language and propose a double-entry journal entry.
 You MUST use the provided Chart of Accounts and Document Types.
@@ -108,6 +109,12 @@ DOCUMENT TYPE SELECTION:
 1. Analyze the user's text. If they are talking about selling a product or service, set the type to 'SI' (Sales Invoice). If they are buying supplies or services, set it to 'PI' (Purchase Invoice). Otherwise, default to 'JE' (Journal Entry).
 2. You MUST select a valid DocumentTypeCode from the list provided below.
 
+ANALYTIC DIMENSIONS:
+1. Tag each line with the cost center, project, branch or other dimension the event names or clearly implies (e.g. "marketing team" → the marketing cost center, "for the Acme rollout" → that project).
+2. Use ONLY dimension and value codes from the Analytic Dimensions list below; at most one value per dimension on a line.
+3. Accounts marked [requires ...] in the Chart of Accounts MUST carry those dimensions on every line. If the event does not say which value applies, ask for it as a clarification.
+4. Otherwise leave dimensions as an empty array — do not guess.
+
 CLARIFICATIONS:
 If the user does not provide enough clues to confidently determine the Document Type, or if critical financial information (like amounts, parties, or intent) is missing, do NOT guess. Instead, set is_clarification_request to true, and provide a clarification message asking the user to specify the missing details (e.g., 'Please specify if this is a Sales Invoice, Purchase Invoice, or Journal Entry, and what the amount was.').
 
@@ -122,7 +129,10 @@ Document Types:
 Chart of Accounts:
 %s
 
-Event: %s`, company.CompanyCode, company.Name, company.BaseCurrency, company.BaseCurrency, time.Now().Format("2006-01-02"), documentTypes, chartOfAccounts, naturalLanguage)
+Analytic Dimensions:
+%s
+
+Event: %s`, company.CompanyCode, company.Name, company.BaseCurrency, company.BaseCurrency, time.Now().Format("2006-01-02"), documentTypes, chartOfAccounts, dimensions, naturalLanguage)
 
 	// Enforce a hard timeout on the OpenAI API call.
 	// Without this, a slow or unresponsive API will block the REPL indefinitely.
@@ -531,7 +541,7 @@ func proposalSchema() map[string]any {
 				"items": map[string]any{
 					"type":                 "object",
 					"additionalProperties": false,
-					"required":             []string{"account_code", "is_debit", "amount"},
+					"required":             []string{"account_code", "is_debit", "amount", "dimensions"},
 					"properties": map[string]any{
 						"account_code": map[string]any{
 							"type":        "string",
@@ -545,6 +555,25 @@ func proposalSchema() map[string]any {
 							"type":        "string",
 							"description": "Positive monetary amount as a string, in TransactionCurrency.",
 						},
+						"dimensions": map[string]any{
+							"type":        "array",
+							"description": "Analytic dimension values for this line; empty when none apply.",
+							"items": map[string]any{
+								"type":                 "object",
+								"additionalProperties": false,
+								"required":             []string{"dimension", "value"},
+								"properties": map[string]any{
+									"dimension": map[string]any{
+										"type":        "string",
+										"description": "Dimension code from Analytic Dimensions, e.g. COST_CENTER or PROJECT.",
+									},
+									"value": map[string]any{
+										"type":        "string",
+										"description": "Code of one of that dimension's values.",
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -18,16 +19,30 @@ import (
 )
 
 type appService struct {
-	pool                 *pgxpool.Pool
-	ledger               *core.Ledger
-	docService           core.DocumentService
-	orderService         core.OrderService
-	inventoryService     core.InventoryService
-	reportingService     core.ReportingService
-	userService          core.UserService
-	vendorService        core.VendorService
-	purchaseOrderService core.PurchaseOrderService
-	agent                *ai.Agent
+	pool                  *pgxpool.Pool
+	ledger                *core.Ledger
+	docService            core.DocumentService
+	orderService          core.OrderService
+	inventoryService      core.InventoryService
+	reportingService      core.ReportingService
+	userService           core.UserService
+	vendorService         core.VendorService
+	purchaseOrderService  core.PurchaseOrderService
+	replenishmentService  core.ReplenishmentService
+	uomService            core.UoMService
+	landedCostService     core.LandedCostService
+	vendorBillService     core.VendorBillService
+	paymentRunService     core.PaymentRunService
+	purchaseReturnService core.PurchaseReturnService
+	tdsService            core.TDSService
+	taxEngine             core.TaxEngine
+	gstReturnService      core.GSTReturnService
+	eInvoiceService       core.EInvoiceService
+	accountService        core.AccountService
+	journalService        core.JournalService
+	budgetService         core.BudgetService
+	dimensionService      core.DimensionService
+	agent                 *ai.Agent
 }
 
 // NewAppService constructs an appService that satisfies ApplicationService.
@@ -41,19 +56,47 @@ func NewAppService(
 	userService core.UserService,
 	vendorService core.VendorService,
 	purchaseOrderService core.PurchaseOrderService,
+	replenishmentService core.ReplenishmentService,
+	uomService core.UoMService,
+	landedCostService core.LandedCostService,
+	vendorBillService core.VendorBillService,
+	paymentRunService core.PaymentRunService,
+	purchaseReturnService core.PurchaseReturnService,
+	tdsService core.TDSService,
+	taxEngine core.TaxEngine,
+	gstReturnService core.GSTReturnService,
+	eInvoiceService core.EInvoiceService,
+	accountService core.AccountService,
+	journalService core.JournalService,
+	budgetService core.BudgetService,
+	dimensionService core.DimensionService,
 	agent *ai.Agent,
 ) ApplicationService {
 	return &appService{
-		pool:                 pool,
-		ledger:               ledger,
-		docService:           docService,
-		orderService:         orderService,
-		inventoryService:     inventoryService,
-		reportingService:     reportingService,
-		userService:          userService,
-		vendorService:        vendorService,
-		purchaseOrderService: purchaseOrderService,
-		agent:                agent,
+		pool:                  pool,
+		ledger:                ledger,
+		docService:            docService,
+		orderService:          orderService,
+		inventoryService:      inventoryService,
+		reportingService:      reportingService,
+		userService:           userService,
+		vendorService:         vendorService,
+		purchaseOrderService:  purchaseOrderService,
+		replenishmentService:  replenishmentService,
+		uomService:            uomService,
+		landedCostService:     landedCostService,
+		vendorBillService:     vendorBillService,
+		paymentRunService:     paymentRunService,
+		purchaseReturnService: purchaseReturnService,
+		tdsService:            tdsService,
+		taxEngine:             taxEngine,
+		gstReturnService:      gstReturnService,
+		eInvoiceService:       eInvoiceService,
+		accountService:        accountService,
+		journalService:        journalService,
+		budgetService:         budgetService,
+		dimensionService:      dimensionService,
+		agent:                 agent,
 	}
 }
 
@@ -109,6 +152,71 @@ func (s *appService) ListCustomers(ctx context.Context, companyCode string) (*Cu
 	return &CustomerListResult{Customers: customers}, nil
 }
 
+// GetCustomer returns one customer by code, including inactive and merged customers.
+func (s *appService) GetCustomer(ctx context.Context, companyCode, customerCode string) (*CustomerResult, error) {
+	customer, err := s.orderService.GetCustomer(ctx, companyCode, customerCode)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerResult{Customer: customer}, nil
+}
+
+// CreateCustomer creates a new customer record for the given company.
+func (s *appService) CreateCustomer(ctx context.Context, req CreateCustomerRequest) (*CustomerResult, error) {
+	if req.Code == "" || req.Name == "" {
+		return nil, fmt.Errorf("customer code and name are required")
+	}
+	if req.CreditLimit.IsNegative() {
+		return nil, fmt.Errorf("credit limit cannot be negative")
+	}
+	paymentTerms := req.PaymentTermsDays
+	if paymentTerms == 0 {
+		paymentTerms = 30
+	}
+	customer, err := s.orderService.CreateCustomer(ctx, req.CompanyCode, req.Code, req.Name,
+		req.Email, req.Phone, req.Address, req.CreditLimit, paymentTerms)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerResult{Customer: customer}, nil
+}
+
+// UpdateCustomer replaces a customer's editable fields, guarded by req.Version.
+func (s *appService) UpdateCustomer(ctx context.Context, req UpdateCustomerRequest) (*CustomerResult, error) {
+	customer, err := s.orderService.UpdateCustomer(ctx, req.CompanyCode, req.Code, req.Version, core.CustomerInput{
+		Name:             req.Name,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		Address:          req.Address,
+		CreditLimit:      req.CreditLimit,
+		PaymentTermsDays: req.PaymentTermsDays,
+		StateCode:        req.StateCode,
+		GSTIN:            req.GSTIN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerResult{Customer: customer}, nil
+}
+
+// SetCustomerActive deactivates or reactivates a customer.
+func (s *appService) SetCustomerActive(ctx context.Context, companyCode, customerCode string, active bool) (*CustomerResult, error) {
+	customer, err := s.orderService.SetCustomerActive(ctx, companyCode, customerCode, active)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerResult{Customer: customer}, nil
+}
+
+// MergeCustomers merges the duplicate customer into the survivor.
+func (s *appService) MergeCustomers(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error) {
+	merge, err := s.orderService.MergeCustomers(ctx, companyCode, duplicateCode, survivorCode)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Merge: merge}, nil
+}
+
 // ListProducts returns all active products for a company.
 func (s *appService) ListProducts(ctx context.Context, companyCode string) (*ProductListResult, error) {
 	products, err := s.orderService.GetProducts(ctx, companyCode)
@@ -143,7 +251,10 @@ func (s *appService) CreateOrder(ctx context.Context, req CreateOrderRequest) (*
 		lines[i] = core.OrderLineInput{
 			ProductCode: l.ProductCode,
 			Quantity:    l.Quantity,
+			Unit:        l.Unit,
 			UnitPrice:   l.UnitPrice,
+			TaxCode:     l.TaxCode,
+			Dimensions:  l.Dimensions,
 		}
 	}
 
@@ -191,6 +302,23 @@ func (s *appService) ShipOrder(ctx context.Context, ref, companyCode string) (*O
 	return &OrderResult{Order: order}, nil
 }
 
+// ShipOrderWithPicks ships a CONFIRMED order drawing lot-tracked products from the given lots.
+func (s *appService) ShipOrderWithPicks(ctx context.Context, ref, companyCode string, picks []LotPickInput) (*OrderResult, error) {
+	order, err := s.resolveOrder(ctx, ref, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	domainPicks := make([]core.LotPick, len(picks))
+	for i, p := range picks {
+		domainPicks[i] = core.LotPick{ProductCode: p.ProductCode, LotNumber: p.LotNumber, Quantity: p.Quantity}
+	}
+	order, err = s.orderService.ShipOrderWithPicks(ctx, order.ID, domainPicks, s.inventoryService, s.ledger, s.docService)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderResult{Order: order}, nil
+}
+
 // InvoiceOrder transitions a SHIPPED order to INVOICED, posting the sales invoice journal entry.
 func (s *appService) InvoiceOrder(ctx context.Context, ref, companyCode string) (*OrderResult, error) {
 	order, err := s.resolveOrder(ctx, ref, companyCode)
@@ -260,8 +388,77 @@ func (s *appService) ReceiveStock(ctx context.Context, req ReceiveStockRequest)
 		movementDate = time.Now().Format("2006-01-02")
 	}
 
-	return s.inventoryService.ReceiveStock(ctx, req.CompanyCode, warehouseCode, req.ProductCode,
-		req.Qty, req.UnitCost, movementDate, creditAccount, nil, s.ledger, s.docService)
+	return s.inventoryService.ReceiveStockLots(ctx, req.CompanyCode, warehouseCode, req.ProductCode,
+		req.Qty, req.UnitCost, toCoreLots(req.Lots), movementDate, creditAccount, nil, s.ledger, s.docService)
+}
+
+// toCoreLots converts request lots to domain lots; nil stays nil for untracked products.
+func toCoreLots(lots []LotInput) []core.LotInput {
+	if len(lots) == 0 {
+		return nil
+	}
+	out := make([]core.LotInput, len(lots))
+	for i, l := range lots {
+		out[i] = core.LotInput{LotNumber: l.LotNumber, ExpiryDate: l.ExpiryDate, Quantity: l.Quantity}
+	}
+	return out
+}
+
+// ListLots returns lots with stock on hand, optionally filtered to one product.
+func (s *appService) ListLots(ctx context.Context, companyCode, productCode string) (*LotListResult, error) {
+	lots, err := s.inventoryService.GetLots(ctx, companyCode, productCode)
+	if err != nil {
+		return nil, err
+	}
+	return &LotListResult{CompanyCode: companyCode, Lots: lots}, nil
+}
+
+// SetProductTracking sets a product's tracking mode and lot pick strategy.
+func (s *appService) SetProductTracking(ctx context.Context, companyCode, productCode, trackingMode, pickStrategy string) error {
+	return s.inventoryService.SetProductTracking(ctx, companyCode, productCode, trackingMode, pickStrategy)
+}
+
+// ListUoMs returns the company's unit-of-measure master.
+func (s *appService) ListUoMs(ctx context.Context, companyCode string) (*UoMListResult, error) {
+	units, err := s.uomService.GetUoMs(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &UoMListResult{CompanyCode: companyCode, Units: units}, nil
+}
+
+// CreateUoM adds a unit of measure to the company's master.
+func (s *appService) CreateUoM(ctx context.Context, companyCode, code, name string) (*core.UnitOfMeasure, error) {
+	return s.uomService.CreateUoM(ctx, companyCode, code, name)
+}
+
+// GetProductUnits returns a product's stock unit, default line units and conversions.
+func (s *appService) GetProductUnits(ctx context.Context, companyCode, productCode string) (*core.ProductUnits, error) {
+	return s.uomService.GetProductUnits(ctx, companyCode, productCode)
+}
+
+// SetUoMConversion creates or updates a product's conversion factor for a unit.
+func (s *appService) SetUoMConversion(ctx context.Context, companyCode, productCode, uomCode string, factor decimal.Decimal) (*core.ProductUnits, error) {
+	return s.uomService.SetConversion(ctx, companyCode, productCode, uomCode, factor)
+}
+
+// SetDefaultUoMs sets a product's default purchase and sales units.
+func (s *appService) SetDefaultUoMs(ctx context.Context, req SetDefaultUoMsRequest) (*core.ProductUnits, error) {
+	return s.uomService.SetDefaultUoMs(ctx, req.CompanyCode, req.ProductCode, req.PurchaseUoM, req.SalesUoM)
+}
+
+// GetLotTrace traces a lot forward from supplier receipts to customer shipments.
+func (s *appService) GetLotTrace(ctx context.Context, companyCode, productCode, lotNumber string) (*core.LotTrace, error) {
+	return s.reportingService.GetLotTrace(ctx, companyCode, productCode, lotNumber)
+}
+
+// GetOrderLotTrace traces a sales order backward to the lots shipped and their supplier POs.
+func (s *appService) GetOrderLotTrace(ctx context.Context, ref, companyCode string) (*core.OrderLotTrace, error) {
+	order, err := s.resolveOrder(ctx, ref, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportingService.GetOrderLotTrace(ctx, companyCode, order.ID)
 }
 
 // GetAccountStatement returns a chronological account statement with running balance.
@@ -295,11 +492,378 @@ func (s *appService) GetBalanceSheet(ctx context.Context, companyCode, asOfDate
 	return s.reportingService.GetBalanceSheet(ctx, companyCode, asOfDate)
 }
 
+// ExportTrialBalance renders the trial balance in an export format.
+func (s *appService) ExportTrialBalance(ctx context.Context, companyCode, format string) (*core.ReportFile, error) {
+	result, err := s.GetTrialBalance(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	h := core.ReportHeader{CompanyCode: companyCode, CompanyName: result.CompanyName, Currency: result.Currency, GeneratedAt: time.Now()}
+	return core.ExportReport(core.TrialBalanceTable(h, result.Accounts), format)
+}
+
+// ExportProfitAndLoss renders the P&L for a month in an export format.
+func (s *appService) ExportProfitAndLoss(ctx context.Context, companyCode string, year, month int, format string) (*core.ReportFile, error) {
+	h, err := s.reportHeader(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.reportingService.GetProfitAndLoss(ctx, companyCode, year, month)
+	if err != nil {
+		return nil, err
+	}
+	return core.ExportReport(core.PLTable(h, report), format)
+}
+
+// ExportBalanceSheet renders the Balance Sheet in an export format.
+func (s *appService) ExportBalanceSheet(ctx context.Context, companyCode, asOfDate, format string) (*core.ReportFile, error) {
+	h, err := s.reportHeader(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.reportingService.GetBalanceSheet(ctx, companyCode, asOfDate)
+	if err != nil {
+		return nil, err
+	}
+	return core.ExportReport(core.BSTable(h, report), format)
+}
+
+// ExportAccountStatement renders an account statement in an export format.
+func (s *appService) ExportAccountStatement(ctx context.Context, companyCode, accountCode, fromDate, toDate, format string) (*core.ReportFile, error) {
+	h, err := s.reportHeader(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.reportingService.GetAccountStatement(ctx, companyCode, accountCode, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	return core.ExportReport(core.StatementTable(h, accountCode, fromDate, toDate, lines), format)
+}
+
+// reportHeader returns the company header printed on exported reports.
+func (s *appService) reportHeader(ctx context.Context, companyCode string) (core.ReportHeader, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return core.ReportHeader{}, err
+	}
+	return core.ReportHeader{
+		CompanyCode: company.CompanyCode,
+		CompanyName: company.Name,
+		Currency:    company.BaseCurrency,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// GetColumnarProfitAndLoss returns the P&L for the selected periods.
+func (s *appService) GetColumnarProfitAndLoss(ctx context.Context, req ColumnarReportRequest) (*core.ColumnarPLReport, error) {
+	periods, comparative, err := resolveReportColumns(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportingService.GetColumnarProfitAndLoss(ctx, req.CompanyCode, periods, comparative)
+}
+
+// GetColumnarBalanceSheet returns the Balance Sheet at the end of the selected periods.
+func (s *appService) GetColumnarBalanceSheet(ctx context.Context, req ColumnarReportRequest) (*core.ColumnarBSReport, error) {
+	periods, comparative, err := resolveReportColumns(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportingService.GetColumnarBalanceSheet(ctx, req.CompanyCode, periods, comparative)
+}
+
+// resolveReportColumns turns a ColumnarReportRequest into report periods, and whether the
+// report carries variances.
+func resolveReportColumns(req ColumnarReportRequest) ([]core.ReportPeriod, bool, error) {
+	switch {
+	case len(req.Periods) > 0:
+		periods := make([]core.ReportPeriod, len(req.Periods))
+		for i, p := range req.Periods {
+			period, err := core.ParseReportPeriod(p)
+			if err != nil {
+				return nil, false, err
+			}
+			periods[i] = period
+		}
+		return periods, req.Comparative, nil
+	case req.Trend != "":
+		month, err := time.Parse("2006-01", req.Trend)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid trend month %q (expected YYYY-MM)", req.Trend)
+		}
+		n := req.TrendMonths
+		if n <= 0 {
+			n = 12
+		}
+		return core.TrendPeriods(month, n), false, nil
+	}
+	compare := req.Compare
+	if compare == "" {
+		compare = time.Now().Format("2006-01")
+	}
+	period, err := core.ParseReportPeriod(compare)
+	if err != nil {
+		return nil, false, err
+	}
+	periods, err := core.ComparativePeriods(period)
+	return periods, true, err
+}
+
 // RefreshViews refreshes all materialized reporting views.
 func (s *appService) RefreshViews(ctx context.Context) error {
 	return s.reportingService.RefreshViews(ctx)
 }
 
+// GetInventoryValuation returns the stock valuation as of the given date.
+func (s *appService) GetInventoryValuation(ctx context.Context, companyCode, asOfDate string) (*core.InventoryValuationReport, error) {
+	return s.reportingService.GetInventoryValuation(ctx, companyCode, asOfDate)
+}
+
+// GetStockMovementLedger returns the movement history for a single product.
+func (s *appService) GetStockMovementLedger(ctx context.Context, companyCode, productCode, warehouseCode, fromDate, toDate string) (*core.StockMovementLedger, error) {
+	return s.reportingService.GetStockMovementLedger(ctx, companyCode, productCode, warehouseCode, fromDate, toDate)
+}
+
+// GetStockAgeing returns the stock ageing and slow-moving report.
+func (s *appService) GetStockAgeing(ctx context.Context, companyCode, asOfDate string, slowMovingDays int) (*core.StockAgeingReport, error) {
+	return s.reportingService.GetStockAgeing(ctx, companyCode, asOfDate, slowMovingDays)
+}
+
+// GetCashFlow returns the statement of cash flows for a date range.
+func (s *appService) GetCashFlow(ctx context.Context, companyCode, fromDate, toDate, method string) (*core.CashFlowReport, error) {
+	return s.reportingService.GetCashFlow(ctx, companyCode, fromDate, toDate, method)
+}
+
+// GetCashFlowMappings returns the cash flow classification of every account.
+func (s *appService) GetCashFlowMappings(ctx context.Context, companyCode string) ([]core.CashFlowMapping, error) {
+	return s.reportingService.GetCashFlowMappings(ctx, companyCode)
+}
+
+// SetCashFlowMapping maps an account to a cash flow activity and line.
+func (s *appService) SetCashFlowMapping(ctx context.Context, companyCode, accountCode, activity, lineName string) error {
+	return s.reportingService.SetCashFlowMapping(ctx, companyCode, accountCode, activity, lineName)
+}
+
+// SetCashAccount flags or unflags an asset account as cash or bank.
+func (s *appService) SetCashAccount(ctx context.Context, companyCode, accountCode string, isCash bool) error {
+	return s.reportingService.SetCashAccount(ctx, companyCode, accountCode, isCash)
+}
+
+// GetChartOfAccounts returns the account tree with balances and group rollups.
+func (s *appService) GetChartOfAccounts(ctx context.Context, companyCode, asOfDate string) ([]core.AccountNode, error) {
+	return s.accountService.GetChartOfAccounts(ctx, companyCode, asOfDate)
+}
+
+// GetAccount returns one account.
+func (s *appService) GetAccount(ctx context.Context, companyCode, accountCode string) (*core.Account, error) {
+	return s.accountService.GetAccount(ctx, companyCode, accountCode)
+}
+
+// CreateAccount adds a postable account to the chart of accounts.
+func (s *appService) CreateAccount(ctx context.Context, req CreateAccountRequest) (*core.Account, error) {
+	accountType, err := core.ParseAccountType(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	return s.accountService.CreateAccount(ctx, req.CompanyCode, core.AccountInput{
+		Code:       req.Code,
+		Name:       req.Name,
+		Type:       accountType,
+		ParentCode: req.ParentCode,
+		AccountControls: core.AccountControls{
+			PostingBlocked:       req.PostingBlocked,
+			ManualPostingBlocked: req.ManualPostingBlocked,
+			NarrationRequired:    req.NarrationRequired,
+		},
+	})
+}
+
+// UpdateAccount renames an account and replaces its posting controls.
+func (s *appService) UpdateAccount(ctx context.Context, req UpdateAccountRequest) (*core.Account, error) {
+	return s.accountService.UpdateAccount(ctx, req.CompanyCode, req.AccountCode, req.Name, core.AccountControls{
+		PostingBlocked:       req.PostingBlocked,
+		ManualPostingBlocked: req.ManualPostingBlocked,
+		NarrationRequired:    req.NarrationRequired,
+	})
+}
+
+// SetAccountActive deactivates or reactivates an account.
+func (s *appService) SetAccountActive(ctx context.Context, companyCode, accountCode string, active bool) error {
+	return s.accountService.SetAccountActive(ctx, companyCode, accountCode, active)
+}
+
+// CreateAccountGroup adds a group account to the chart of accounts.
+func (s *appService) CreateAccountGroup(ctx context.Context, req CreateAccountGroupRequest) (*core.Account, error) {
+	accountType, err := core.ParseAccountType(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	return s.accountService.CreateAccountGroup(ctx, req.CompanyCode, req.Code, req.Name, accountType, req.ParentCode)
+}
+
+// SetAccountParent moves an account under a group, or to the top level.
+func (s *appService) SetAccountParent(ctx context.Context, companyCode, accountCode, parentCode string) error {
+	return s.accountService.SetAccountParent(ctx, companyCode, accountCode, parentCode)
+}
+
+// GetStatementLayouts returns the company's statement layouts.
+func (s *appService) GetStatementLayouts(ctx context.Context, companyCode string) ([]core.StatementLayout, error) {
+	return s.reportingService.GetStatementLayouts(ctx, companyCode)
+}
+
+// CreateStatementLayout adds an empty statement layout.
+func (s *appService) CreateStatementLayout(ctx context.Context, companyCode, code, name, statement string) error {
+	return s.reportingService.CreateStatementLayout(ctx, companyCode, code, name, statement)
+}
+
+// SetStatementLayoutLine creates or updates a statement layout line.
+func (s *appService) SetStatementLayoutLine(ctx context.Context, companyCode, layoutCode string, line core.StatementLayoutLine) error {
+	return s.reportingService.SetStatementLayoutLine(ctx, companyCode, layoutCode, line)
+}
+
+// SetStatementLayoutAccount maps an account to a statement layout line.
+func (s *appService) SetStatementLayoutAccount(ctx context.Context, companyCode, layoutCode, accountCode, lineCode string) error {
+	return s.reportingService.SetStatementLayoutAccount(ctx, companyCode, layoutCode, accountCode, lineCode)
+}
+
+// GetLayoutStatement returns a balance sheet or P&L in a statement layout.
+func (s *appService) GetLayoutStatement(ctx context.Context, companyCode, layoutCode, fromDate, toDate string) (*core.LayoutStatement, error) {
+	return s.reportingService.GetLayoutStatement(ctx, companyCode, layoutCode, fromDate, toDate)
+}
+
+// GetJournalRegister returns one page of the journal register.
+func (s *appService) GetJournalRegister(ctx context.Context, companyCode string, filter core.JournalFilter) (*core.JournalRegister, error) {
+	return s.journalService.GetJournalRegister(ctx, companyCode, filter)
+}
+
+// GetJournalEntry returns one posted journal entry.
+func (s *appService) GetJournalEntry(ctx context.Context, companyCode string, entryID int) (*core.JournalEntry, error) {
+	return s.journalService.GetJournalEntry(ctx, companyCode, entryID)
+}
+
+// GetDayBook returns the entries posted on a date.
+func (s *appService) GetDayBook(ctx context.Context, companyCode, date string) (*core.DayBook, error) {
+	return s.journalService.GetDayBook(ctx, companyCode, date)
+}
+
+// GetGeneralLedger returns the general ledger for a date range.
+func (s *appService) GetGeneralLedger(ctx context.Context, companyCode, fromDate, toDate string) (*core.GeneralLedger, error) {
+	return s.journalService.GetGeneralLedger(ctx, companyCode, fromDate, toDate)
+}
+
+// ListCostCenters returns the company's cost centers.
+func (s *appService) ListCostCenters(ctx context.Context, companyCode string) ([]core.CostCenter, error) {
+	return s.budgetService.ListCostCenters(ctx, companyCode)
+}
+
+// CreateCostCenter adds a cost center.
+func (s *appService) CreateCostCenter(ctx context.Context, companyCode, code, name string) (*core.CostCenter, error) {
+	return s.budgetService.CreateCostCenter(ctx, companyCode, code, name)
+}
+
+// ListBudgets returns the company's budget versions.
+func (s *appService) ListBudgets(ctx context.Context, companyCode string) ([]core.Budget, error) {
+	return s.budgetService.ListBudgets(ctx, companyCode)
+}
+
+// CreateBudget adds an empty budget version.
+func (s *appService) CreateBudget(ctx context.Context, companyCode string, input core.BudgetInput) (*core.Budget, error) {
+	return s.budgetService.CreateBudget(ctx, companyCode, input)
+}
+
+// GetBudget returns a budget version with its lines.
+func (s *appService) GetBudget(ctx context.Context, companyCode, budgetCode string) (*core.Budget, error) {
+	return s.budgetService.GetBudget(ctx, companyCode, budgetCode)
+}
+
+// ImportBudgetCSV parses budget lines from CSV and writes them to the budget.
+func (s *appService) ImportBudgetCSV(ctx context.Context, companyCode, budgetCode string, r io.Reader, replace bool) (int, error) {
+	lines, err := core.ParseBudgetCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	return s.budgetService.SetBudgetLines(ctx, companyCode, budgetCode, lines, replace)
+}
+
+// CopyBudgetFromActuals fills a budget from a prior year's actuals with an uplift.
+func (s *appService) CopyBudgetFromActuals(ctx context.Context, companyCode, budgetCode string, sourceYear int, upliftPct decimal.Decimal) (int, error) {
+	return s.budgetService.CopyBudgetFromActuals(ctx, companyCode, budgetCode, sourceYear, upliftPct)
+}
+
+// GetBudgetVsActual returns the budget-vs-actual report for the requested period.
+func (s *appService) GetBudgetVsActual(ctx context.Context, req BudgetVsActualRequest) (*core.BudgetVsActualReport, error) {
+	var period core.ReportPeriod
+	if req.Period == "" {
+		now := time.Now()
+		from := time.Date(core.FinancialYear(now), time.April, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		period = core.NewReportPeriod(from, to)
+	} else {
+		var err error
+		if period, err = core.ParseReportPeriod(req.Period); err != nil {
+			return nil, err
+		}
+	}
+	return s.budgetService.GetBudgetVsActual(ctx, req.CompanyCode, req.BudgetCode, period, req.ThresholdPct)
+}
+
+// ListDimensions returns the company's analytic dimensions with their values.
+func (s *appService) ListDimensions(ctx context.Context, companyCode string) ([]core.Dimension, error) {
+	return s.dimensionService.ListDimensions(ctx, companyCode)
+}
+
+// CreateDimension adds a user-defined dimension.
+func (s *appService) CreateDimension(ctx context.Context, companyCode, code, name string) (*core.Dimension, error) {
+	return s.dimensionService.CreateDimension(ctx, companyCode, code, name)
+}
+
+// CreateDimensionValue adds a value to a dimension.
+func (s *appService) CreateDimensionValue(ctx context.Context, companyCode, dimensionCode, code, name string) (*core.DimensionValue, error) {
+	return s.dimensionService.CreateDimensionValue(ctx, companyCode, dimensionCode, code, name)
+}
+
+// SetDimensionValueActive activates or retires a dimension value.
+func (s *appService) SetDimensionValueActive(ctx context.Context, companyCode, dimensionCode, valueCode string, active bool) (*core.DimensionValue, error) {
+	return s.dimensionService.SetDimensionValueActive(ctx, companyCode, dimensionCode, valueCode, active)
+}
+
+// ListAccountDimensionRules returns the dimensions required per account.
+func (s *appService) ListAccountDimensionRules(ctx context.Context, companyCode string) ([]core.AccountDimensionRule, error) {
+	return s.dimensionService.ListAccountDimensionRules(ctx, companyCode)
+}
+
+// SetAccountDimensionRequired makes a dimension mandatory or optional on an account.
+func (s *appService) SetAccountDimensionRequired(ctx context.Context, companyCode, accountCode, dimensionCode string, required bool) error {
+	return s.dimensionService.SetAccountDimensionRequired(ctx, companyCode, accountCode, dimensionCode, required)
+}
+
+// GetDimensionProfitAndLoss returns the P&L filtered and broken down by dimension.
+func (s *appService) GetDimensionProfitAndLoss(ctx context.Context, req DimensionReportRequest) (*core.DimensionPLReport, error) {
+	filter, err := core.ParseDimensionFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	var period core.ReportPeriod
+	if req.Period == "" {
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		period = core.NewReportPeriod(from, from.AddDate(0, 1, -1))
+	} else if period, err = core.ParseReportPeriod(req.Period); err != nil {
+		return nil, err
+	}
+	return s.dimensionService.GetDimensionProfitAndLoss(ctx, req.CompanyCode, period, filter, req.Breakdown)
+}
+
+// GetDimensionTrialBalance returns the trial balance filtered and broken down by dimension.
+func (s *appService) GetDimensionTrialBalance(ctx context.Context, req DimensionReportRequest) (*core.DimensionTrialBalance, error) {
+	filter, err := core.ParseDimensionFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.dimensionService.GetDimensionTrialBalance(ctx, req.CompanyCode, req.AsOfDate, filter, req.Breakdown)
+}
+
 // InterpretEvent sends a natural language event description to the AI agent and returns
 // either a Proposal or a clarification request.
 func (s *appService) InterpretEvent(ctx context.Context, text, companyCode string) (*AIResult, error) {
@@ -318,7 +882,12 @@ func (s *appService) InterpretEvent(ctx context.Context, text, companyCode strin
 		return nil, fmt.Errorf("failed to fetch company: %w", err)
 	}
 
-	response, err := s.agent.InterpretEvent(ctx, text, coa, documentTypes, company)
+	dimensions, err := s.fetchDimensions(ctx, companyCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dimensions: %w", err)
+	}
+
+	response, err := s.agent.InterpretEvent(ctx, text, coa, documentTypes, dimensions, company)
 	if err != nil {
 		return nil, err
 	}
@@ -408,6 +977,14 @@ func (s *appService) ExecuteWriteTool(ctx context.Context, companyCode, toolName
 			Address:                   strArg("address"),
 			APAccountCode:             strArg("ap_account_code"),
 			DefaultExpenseAccountCode: strArg("default_expense_account_code"),
+			BankAccountName:           strArg("bank_account_name"),
+			BankAccountNumber:         strArg("bank_account_number"),
+			BankCode:                  strArg("bank_code"),
+			Currency:                  strArg("currency"),
+			PAN:                       strArg("pan"),
+			TDSSectionCode:            strArg("tds_section_code"),
+			StateCode:                 strArg("state_code"),
+			GSTIN:                     strArg("gstin"),
 		}
 		if pt, ok := args["payment_terms_days"].(float64); ok {
 			req.PaymentTermsDays = int(pt)
@@ -423,57 +1000,248 @@ func (s *appService) ExecuteWriteTool(ctx context.Context, companyCode, toolName
 		})
 		return string(b), nil
 
-	case "create_purchase_order":
-		// Parse nested lines via JSON round-trip.
-		type lineIn struct {
-			ProductCode        string  `json:"product_code"`
-			Description        string  `json:"description"`
-			Quantity           float64 `json:"quantity"`
-			UnitCost           float64 `json:"unit_cost"`
-			ExpenseAccountCode string  `json:"expense_account_code"`
+	case "update_vendor":
+		// Fields the AI did not supply keep their current values.
+		current, err := s.GetVendor(ctx, companyCode, strArg("vendor_code"))
+		if err != nil {
+			return "", err
 		}
-		type poIn struct {
-			VendorCode string  `json:"vendor_code"`
-			PODate     string  `json:"po_date"`
-			Notes      string  `json:"notes"`
-			Lines      []lineIn `json:"lines"`
+		v := current.Vendor
+		strOr := func(key string, cur *string) string {
+			if val, ok := args[key].(string); ok {
+				return val
+			}
+			if cur != nil {
+				return *cur
+			}
+			return ""
 		}
-		raw, _ := json.Marshal(args)
-		var inp poIn
-		if err := json.Unmarshal(raw, &inp); err != nil {
-			return "", fmt.Errorf("invalid create_purchase_order args: %w", err)
+		req := UpdateVendorRequest{
+			CompanyCode:               companyCode,
+			Code:                      v.Code,
+			Version:                   intArg("version"),
+			Name:                      strOr("name", &v.Name),
+			ContactPerson:             strOr("contact_person", v.ContactPerson),
+			Email:                     strOr("email", v.Email),
+			Phone:                     strOr("phone", v.Phone),
+			Address:                   strOr("address", v.Address),
+			PaymentTermsDays:          v.PaymentTermsDays,
+			APAccountCode:             strOr("ap_account_code", &v.APAccountCode),
+			DefaultExpenseAccountCode: strOr("default_expense_account_code", v.DefaultExpenseAccountCode),
+			BankAccountName:           strOr("bank_account_name", v.BankAccountName),
+			BankAccountNumber:         strOr("bank_account_number", v.BankAccountNumber),
+			BankCode:                  strOr("bank_code", v.BankCode),
+			Currency:                  strOr("currency", v.Currency),
+			PAN:                       strOr("pan", v.PAN),
+			TDSSectionCode:            strOr("tds_section_code", v.TDSSectionCode),
+			StateCode:                 strOr("state_code", v.StateCode),
+			GSTIN:                     strOr("gstin", v.GSTIN),
 		}
-		lines := make([]POLineInput, len(inp.Lines))
-		for i, l := range inp.Lines {
-			lines[i] = POLineInput{
-				ProductCode:        l.ProductCode,
+		if pt, ok := args["payment_terms_days"].(float64); ok {
+			req.PaymentTermsDays = int(pt)
+		}
+		result, err := s.UpdateVendor(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Vendor updated.",
+			"code":    result.Vendor.Code,
+			"version": result.Vendor.Version,
+		})
+		return string(b), nil
+
+	case "set_vendor_active":
+		active, _ := args["active"].(bool)
+		result, err := s.SetVendorActive(ctx, companyCode, strArg("vendor_code"), active)
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":   "Vendor status updated.",
+			"code":      result.Vendor.Code,
+			"is_active": result.Vendor.IsActive,
+		})
+		return string(b), nil
+
+	case "merge_vendors":
+		result, err := s.MergeVendors(ctx, companyCode, strArg("duplicate_vendor_code"), strArg("survivor_vendor_code"))
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":   fmt.Sprintf("Vendor %s merged into %s.", result.Merge.DuplicateCode, result.Merge.SurvivorCode),
+			"repointed": result.Merge.Repointed,
+		})
+		return string(b), nil
+
+	case "create_customer":
+		req := CreateCustomerRequest{
+			CompanyCode: companyCode,
+			Code:        strArg("code"),
+			Name:        strArg("name"),
+			Email:       strArg("email"),
+			Phone:       strArg("phone"),
+			Address:     strArg("address"),
+		}
+		if cl, ok := args["credit_limit"].(float64); ok {
+			req.CreditLimit = decimal.NewFromFloat(cl)
+		}
+		if pt, ok := args["payment_terms_days"].(float64); ok {
+			req.PaymentTermsDays = int(pt)
+		}
+		result, err := s.CreateCustomer(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Customer created.",
+			"code":    result.Customer.Code,
+			"name":    result.Customer.Name,
+		})
+		return string(b), nil
+
+	case "update_customer":
+		// Fields the AI did not supply keep their current values.
+		current, err := s.GetCustomer(ctx, companyCode, strArg("customer_code"))
+		if err != nil {
+			return "", err
+		}
+		c := current.Customer
+		strOr := func(key, cur string) string {
+			if val, ok := args[key].(string); ok {
+				return val
+			}
+			return cur
+		}
+		req := UpdateCustomerRequest{
+			CompanyCode:      companyCode,
+			Code:             c.Code,
+			Version:          intArg("version"),
+			Name:             strOr("name", c.Name),
+			Email:            strOr("email", c.Email),
+			Phone:            strOr("phone", c.Phone),
+			Address:          strOr("address", c.Address),
+			CreditLimit:      c.CreditLimit,
+			PaymentTermsDays: c.PaymentTermsDays,
+		}
+		if c.StateCode != nil {
+			req.StateCode = *c.StateCode
+		}
+		if c.GSTIN != nil {
+			req.GSTIN = *c.GSTIN
+		}
+		req.StateCode = strOr("state_code", req.StateCode)
+		req.GSTIN = strOr("gstin", req.GSTIN)
+		if cl, ok := args["credit_limit"].(float64); ok {
+			req.CreditLimit = decimal.NewFromFloat(cl)
+		}
+		if pt, ok := args["payment_terms_days"].(float64); ok {
+			req.PaymentTermsDays = int(pt)
+		}
+		result, err := s.UpdateCustomer(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Customer updated.",
+			"code":    result.Customer.Code,
+			"version": result.Customer.Version,
+		})
+		return string(b), nil
+
+	case "set_customer_active":
+		active, _ := args["active"].(bool)
+		result, err := s.SetCustomerActive(ctx, companyCode, strArg("customer_code"), active)
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":   "Customer status updated.",
+			"code":      result.Customer.Code,
+			"is_active": result.Customer.IsActive,
+		})
+		return string(b), nil
+
+	case "merge_customers":
+		result, err := s.MergeCustomers(ctx, companyCode, strArg("duplicate_customer_code"), strArg("survivor_customer_code"))
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":   fmt.Sprintf("Customer %s merged into %s.", result.Merge.DuplicateCode, result.Merge.SurvivorCode),
+			"repointed": result.Merge.Repointed,
+		})
+		return string(b), nil
+
+	case "create_purchase_order":
+		// Parse nested lines via JSON round-trip.
+		type lineIn struct {
+			ProductCode        string               `json:"product_code"`
+			Description        string               `json:"description"`
+			Quantity           float64              `json:"quantity"`
+			Unit               string               `json:"unit"`
+			UnitCost           float64              `json:"unit_cost"`
+			ExpenseAccountCode string               `json:"expense_account_code"`
+			TaxCode            string               `json:"tax_code"`
+			Dimensions         []core.LineDimension `json:"dimensions"`
+		}
+		type poIn struct {
+			VendorCode   string   `json:"vendor_code"`
+			PODate       string   `json:"po_date"`
+			Currency     string   `json:"currency"`
+			ExchangeRate float64  `json:"exchange_rate"`
+			Notes        string   `json:"notes"`
+			Lines        []lineIn `json:"lines"`
+		}
+		raw, _ := json.Marshal(args)
+		var inp poIn
+		if err := json.Unmarshal(raw, &inp); err != nil {
+			return "", fmt.Errorf("invalid create_purchase_order args: %w", err)
+		}
+		lines := make([]POLineInput, len(inp.Lines))
+		for i, l := range inp.Lines {
+			lines[i] = POLineInput{
+				ProductCode:        l.ProductCode,
 				Description:        l.Description,
 				Quantity:           decimal.NewFromFloat(l.Quantity),
+				Unit:               l.Unit,
 				UnitCost:           decimal.NewFromFloat(l.UnitCost),
 				ExpenseAccountCode: l.ExpenseAccountCode,
+				TaxCode:            l.TaxCode,
+				Dimensions:         l.Dimensions,
 			}
 		}
 		result, err := s.CreatePurchaseOrder(ctx, CreatePurchaseOrderRequest{
-			CompanyCode: companyCode,
-			VendorCode:  inp.VendorCode,
-			PODate:      inp.PODate,
-			Notes:       inp.Notes,
-			Lines:       lines,
+			CompanyCode:  companyCode,
+			VendorCode:   inp.VendorCode,
+			PODate:       inp.PODate,
+			Currency:     inp.Currency,
+			ExchangeRate: decimal.NewFromFloat(inp.ExchangeRate),
+			Notes:        inp.Notes,
+			Lines:        lines,
 		})
 		if err != nil {
 			return "", err
 		}
 		b, _ := json.Marshal(map[string]any{
-			"message": "Purchase order created as DRAFT.",
-			"po_id":   result.PurchaseOrder.ID,
-			"status":  result.PurchaseOrder.Status,
+			"message":  "Purchase order created as DRAFT.",
+			"po_id":    result.PurchaseOrder.ID,
+			"status":   result.PurchaseOrder.Status,
+			"currency": result.PurchaseOrder.Currency,
 		})
 		return string(b), nil
 
 	case "receive_po":
+		type lotIn struct {
+			LotNumber  string  `json:"lot_number"`
+			ExpiryDate string  `json:"expiry_date"`
+			Quantity   float64 `json:"quantity"`
+		}
 		type lineIn struct {
 			POLineID    int     `json:"po_line_id"`
 			QtyReceived float64 `json:"qty_received"`
+			Lots        []lotIn `json:"lots"`
 		}
 		type receiveIn struct {
 			POID          int      `json:"po_id"`
@@ -491,6 +1259,13 @@ func (s *appService) ExecuteWriteTool(ctx context.Context, companyCode, toolName
 				POLineID:    l.POLineID,
 				QtyReceived: decimal.NewFromFloat(l.QtyReceived),
 			}
+			for _, lot := range l.Lots {
+				lines[i].Lots = append(lines[i].Lots, LotInput{
+					LotNumber:  lot.LotNumber,
+					ExpiryDate: lot.ExpiryDate,
+					Quantity:   decimal.NewFromFloat(lot.Quantity),
+				})
+			}
 		}
 		result, err := s.ReceivePurchaseOrder(ctx, ReceivePORequest{
 			CompanyCode:   companyCode,
@@ -508,36 +1283,136 @@ func (s *appService) ExecuteWriteTool(ctx context.Context, companyCode, toolName
 		})
 		return string(b), nil
 
-	case "record_vendor_invoice":
-		amtStr := strArg("invoice_amount")
-		amt, err := decimal.NewFromString(amtStr)
+	case "short_close_po":
+		result, err := s.ShortClosePurchaseOrder(ctx, companyCode, intArg("po_id"), strArg("reason"), "")
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Purchase order short-closed.",
+			"status":  result.PurchaseOrder.Status,
+		})
+		return string(b), nil
+
+	case "amend_po":
+		type lineIn struct {
+			POLineID    int     `json:"po_line_id"`
+			ProductCode string  `json:"product_code"`
+			Description string  `json:"description"`
+			Quantity    float64 `json:"quantity"`
+			UnitCost    float64 `json:"unit_cost"`
+		}
+		type amendIn struct {
+			POID                 int      `json:"po_id"`
+			ExpectedDeliveryDate string   `json:"expected_delivery_date"`
+			Lines                []lineIn `json:"lines"`
+			Reason               string   `json:"reason"`
+			RequireReapproval    bool     `json:"require_reapproval"`
+		}
+		raw, _ := json.Marshal(args)
+		var inp amendIn
+		if err := json.Unmarshal(raw, &inp); err != nil {
+			return "", fmt.Errorf("invalid amend_po args: %w", err)
+		}
+		req := AmendPurchaseOrderRequest{
+			CompanyCode:          companyCode,
+			POID:                 inp.POID,
+			ExpectedDeliveryDate: inp.ExpectedDeliveryDate,
+			Reason:               inp.Reason,
+			RequireReapproval:    inp.RequireReapproval,
+		}
+		if inp.Lines != nil {
+			req.Lines = make([]POAmendLineInput, len(inp.Lines))
+			for i, l := range inp.Lines {
+				req.Lines[i] = POAmendLineInput{
+					POLineID:    l.POLineID,
+					ProductCode: l.ProductCode,
+					Description: l.Description,
+					Quantity:    decimal.NewFromFloat(l.Quantity),
+					UnitCost:    decimal.NewFromFloat(l.UnitCost),
+				}
+			}
+		}
+		result, err := s.AmendPurchaseOrder(ctx, req)
 		if err != nil {
-			// try float fallback
-			if f, ok := args["invoice_amount"].(float64); ok {
-				amt = decimal.NewFromFloat(f)
-			} else {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":  "Purchase order amended.",
+			"revision": result.PurchaseOrder.Revision,
+			"status":   result.PurchaseOrder.Status,
+			"total":    result.PurchaseOrder.TotalTransaction.StringFixed(2),
+		})
+		return string(b), nil
+
+	case "cancel_po":
+		result, err := s.CancelPurchaseOrder(ctx, companyCode, intArg("po_id"), strArg("reason"), "")
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Purchase order cancelled.",
+			"status":  result.PurchaseOrder.Status,
+		})
+		return string(b), nil
+
+	case "record_vendor_invoice":
+		amt := decimal.Zero
+		if amtStr := strArg("invoice_amount"); amtStr != "" {
+			var err error
+			amt, err = decimal.NewFromString(amtStr)
+			if err != nil {
 				return "", fmt.Errorf("invalid invoice_amount: %q", amtStr)
 			}
+		} else if f, ok := args["invoice_amount"].(float64); ok {
+			amt = decimal.NewFromFloat(f)
 		}
 		invoiceDate, err := time.Parse("2006-01-02", strArg("invoice_date"))
 		if err != nil {
 			return "", fmt.Errorf("invalid invoice_date: %w", err)
 		}
+		type invoiceLineIn struct {
+			POLineID  int     `json:"po_line_id"`
+			Quantity  float64 `json:"quantity"`
+			UnitPrice float64 `json:"unit_price"`
+		}
+		var lineArgs struct {
+			Lines []invoiceLineIn `json:"lines"`
+		}
+		raw, _ := json.Marshal(args)
+		if err := json.Unmarshal(raw, &lineArgs); err != nil {
+			return "", fmt.Errorf("invalid record_vendor_invoice lines: %w", err)
+		}
+		lines := make([]VendorInvoiceLineInput, len(lineArgs.Lines))
+		for i, l := range lineArgs.Lines {
+			lines[i] = VendorInvoiceLineInput{
+				POLineID:  l.POLineID,
+				Quantity:  decimal.NewFromFloat(l.Quantity),
+				UnitPrice: decimal.NewFromFloat(l.UnitPrice),
+			}
+		}
 		result, err := s.RecordVendorInvoice(ctx, VendorInvoiceRequest{
 			CompanyCode:   companyCode,
 			POID:          intArg("po_id"),
 			InvoiceNumber: strArg("invoice_number"),
 			InvoiceDate:   invoiceDate,
 			InvoiceAmount: amt,
+			Lines:         lines,
 		})
 		if err != nil {
 			return "", err
 		}
+		po := result.PurchaseOrder
 		msg := "Vendor invoice recorded. PI document: " + result.PIDocumentNumber
 		if result.Warning != "" {
 			msg += " Warning: " + result.Warning
 		}
-		b, _ := json.Marshal(map[string]any{"message": msg, "status": result.PurchaseOrder.Status})
+		b, _ := json.Marshal(map[string]any{
+			"message":         msg,
+			"status":          po.Status,
+			"payment_blocked": po.PaymentBlocked,
+			"variance_amount": po.VarianceAmount.StringFixed(2),
+		})
 		return string(b), nil
 
 	case "pay_vendor":
@@ -560,6 +1435,231 @@ func (s *appService) ExecuteWriteTool(ctx context.Context, companyCode, toolName
 		})
 		return string(b), nil
 
+	case "create_vendor_bill":
+		type lineIn struct {
+			Description        string  `json:"description"`
+			Amount             float64 `json:"amount"`
+			Quantity           float64 `json:"quantity"`
+			UnitCost           float64 `json:"unit_cost"`
+			ExpenseAccountCode string  `json:"expense_account_code"`
+			ProductCode        string  `json:"product_code"`
+			TaxCode            string  `json:"tax_code"`
+		}
+		type billIn struct {
+			VendorCode string   `json:"vendor_code"`
+			BillNumber string   `json:"bill_number"`
+			BillDate   string   `json:"bill_date"`
+			DueDate    string   `json:"due_date"`
+			Notes      string   `json:"notes"`
+			Lines      []lineIn `json:"lines"`
+		}
+		raw, _ := json.Marshal(args)
+		var inp billIn
+		if err := json.Unmarshal(raw, &inp); err != nil {
+			return "", fmt.Errorf("invalid create_vendor_bill args: %w", err)
+		}
+		lines := make([]VendorBillLineInput, len(inp.Lines))
+		for i, l := range inp.Lines {
+			lines[i] = VendorBillLineInput{
+				ProductCode:        l.ProductCode,
+				Description:        l.Description,
+				Quantity:           decimal.NewFromFloat(l.Quantity),
+				UnitCost:           decimal.NewFromFloat(l.UnitCost),
+				ExpenseAccountCode: l.ExpenseAccountCode,
+				TaxCode:            l.TaxCode,
+			}
+			if l.Amount != 0 && l.UnitCost == 0 {
+				lines[i].Quantity = decimal.NewFromInt(1)
+				lines[i].UnitCost = decimal.NewFromFloat(l.Amount)
+			}
+		}
+		result, err := s.CreateVendorBill(ctx, CreateVendorBillRequest{
+			CompanyCode: companyCode,
+			VendorCode:  inp.VendorCode,
+			BillNumber:  inp.BillNumber,
+			BillDate:    inp.BillDate,
+			DueDate:     inp.DueDate,
+			Notes:       inp.Notes,
+			Lines:       lines,
+		})
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":            "Vendor bill posted.",
+			"bill_id":            result.Bill.ID,
+			"pi_document_number": result.Bill.PIDocumentNumber,
+			"total_amount":       result.Bill.TotalAmount.StringFixed(2),
+			"due_date":           result.Bill.DueDate,
+		})
+		return string(b), nil
+
+	case "pay_vendor_bill":
+		paymentDate, err := time.Parse("2006-01-02", strArg("payment_date"))
+		if err != nil {
+			return "", fmt.Errorf("invalid payment_date: %w", err)
+		}
+		result, err := s.PayVendorBill(ctx, PayVendorBillRequest{
+			CompanyCode:     companyCode,
+			BillID:          intArg("bill_id"),
+			BankAccountCode: strArg("bank_account_code"),
+			PaymentDate:     paymentDate,
+		})
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message": "Vendor bill payment posted.",
+			"status":  result.Bill.Status,
+		})
+		return string(b), nil
+
+	case "create_payment_run":
+		result, err := s.CreatePaymentRun(ctx, CreatePaymentRunRequest{
+			CompanyCode:     companyCode,
+			DueBy:           strArg("due_by"),
+			PaymentDate:     strArg("payment_date"),
+			BankAccountCode: strArg("bank_account_code"),
+			VendorCode:      strArg("vendor_code"),
+		})
+		if err != nil {
+			return "", err
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":      "Payment run created as DRAFT. Review and approve it on the Payment Runs page.",
+			"run_id":       result.Run.ID,
+			"item_count":   len(result.Run.Items),
+			"total_amount": result.Run.TotalAmount.StringFixed(2),
+		})
+		return string(b), nil
+
+	case "create_purchase_return":
+		type lineIn struct {
+			POLineID int     `json:"po_line_id"`
+			Quantity float64 `json:"quantity"`
+		}
+		type returnIn struct {
+			POID          int      `json:"po_id"`
+			WarehouseCode string   `json:"warehouse_code"`
+			ReturnDate    string   `json:"return_date"`
+			Reason        string   `json:"reason"`
+			Lines         []lineIn `json:"lines"`
+		}
+		raw, _ := json.Marshal(args)
+		var inp returnIn
+		if err := json.Unmarshal(raw, &inp); err != nil {
+			return "", fmt.Errorf("invalid create_purchase_return args: %w", err)
+		}
+		lines := make([]PurchaseReturnLineInput, len(inp.Lines))
+		for i, l := range inp.Lines {
+			lines[i] = PurchaseReturnLineInput{POLineID: l.POLineID, Quantity: decimal.NewFromFloat(l.Quantity)}
+		}
+		result, err := s.CreatePurchaseReturn(ctx, CreatePurchaseReturnRequest{
+			CompanyCode:   companyCode,
+			POID:          inp.POID,
+			WarehouseCode: inp.WarehouseCode,
+			ReturnDate:    inp.ReturnDate,
+			Reason:        inp.Reason,
+			Lines:         lines,
+		})
+		if err != nil {
+			return "", err
+		}
+		m := map[string]any{
+			"message":   "Goods returned. The debit note will be offset against the next payment to the vendor.",
+			"return_id": result.Return.ID,
+			"amount":    result.Return.Amount.StringFixed(2),
+		}
+		if result.Return.DebitNoteNumber != nil {
+			m["debit_note_number"] = *result.Return.DebitNoteNumber
+		}
+		b, _ := json.Marshal(m)
+		return string(b), nil
+
+	case "create_replenishment_pos":
+		var productCodes []string
+		if raw, ok := args["product_codes"].([]any); ok {
+			for _, v := range raw {
+				if code, ok := v.(string); ok && code != "" {
+					productCodes = append(productCodes, code)
+				}
+			}
+		}
+		result, err := s.CreateReplenishmentPOs(ctx, CreateReplenishmentPOsRequest{
+			CompanyCode:  companyCode,
+			PODate:       strArg("po_date"),
+			ProductCodes: productCodes,
+		})
+		if err != nil {
+			return "", err
+		}
+		poIDs := make([]int, len(result.PurchaseOrders))
+		for i, po := range result.PurchaseOrders {
+			poIDs[i] = po.ID
+		}
+		skipped := make([]string, len(result.Skipped))
+		for i, sg := range result.Skipped {
+			skipped[i] = sg.ProductCode
+		}
+		msg := fmt.Sprintf("%d replenishment purchase order(s) created as DRAFT.", len(poIDs))
+		if len(poIDs) == 0 {
+			msg = "Nothing to replenish: no products with a known vendor are below their reorder point."
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":          msg,
+			"po_ids":           poIDs,
+			"skipped_products": skipped,
+		})
+		return string(b), nil
+
+	case "create_landed_cost_voucher":
+		type chargeIn struct {
+			Description string  `json:"description"`
+			Amount      float64 `json:"amount"`
+		}
+		type voucherIn struct {
+			PONumbers         []string   `json:"po_numbers"`
+			Charges           []chargeIn `json:"charges"`
+			AllocationMethod  string     `json:"allocation_method"`
+			VendorCode        string     `json:"vendor_code"`
+			CreditAccountCode string     `json:"credit_account_code"`
+			VoucherDate       string     `json:"voucher_date"`
+		}
+		raw, _ := json.Marshal(args)
+		var inp voucherIn
+		if err := json.Unmarshal(raw, &inp); err != nil {
+			return "", fmt.Errorf("invalid create_landed_cost_voucher args: %w", err)
+		}
+		charges := make([]LandedCostChargeInput, len(inp.Charges))
+		for i, c := range inp.Charges {
+			charges[i] = LandedCostChargeInput{Description: c.Description, Amount: decimal.NewFromFloat(c.Amount)}
+		}
+		result, err := s.CreateLandedCostVoucher(ctx, CreateLandedCostVoucherRequest{
+			CompanyCode:       companyCode,
+			VoucherDate:       inp.VoucherDate,
+			VendorCode:        inp.VendorCode,
+			CreditAccountCode: inp.CreditAccountCode,
+			AllocationMethod:  inp.AllocationMethod,
+			PONumbers:         inp.PONumbers,
+			Charges:           charges,
+		})
+		if err != nil {
+			return "", err
+		}
+		v := result.Voucher
+		voucherNumber := ""
+		if v.VoucherNumber != nil {
+			voucherNumber = *v.VoucherNumber
+		}
+		b, _ := json.Marshal(map[string]any{
+			"message":            fmt.Sprintf("Landed cost voucher %s posted.", voucherNumber),
+			"voucher_id":         v.ID,
+			"total_amount":       v.TotalAmount.StringFixed(2),
+			"capitalized_amount": v.CapitalizedAmount.StringFixed(2),
+			"expensed_amount":    v.ExpensedAmount.StringFixed(2),
+		})
+		return string(b), nil
+
 	default:
 		return "", fmt.Errorf("unknown write tool: %q", toolName)
 	}
@@ -594,6 +1694,14 @@ func (s *appService) CreateVendor(ctx context.Context, req CreateVendorRequest)
 		PaymentTermsDays:          req.PaymentTermsDays,
 		APAccountCode:             req.APAccountCode,
 		DefaultExpenseAccountCode: req.DefaultExpenseAccountCode,
+		BankAccountName:           req.BankAccountName,
+		BankAccountNumber:         req.BankAccountNumber,
+		BankCode:                  req.BankCode,
+		Currency:                  req.Currency,
+		PAN:                       req.PAN,
+		TDSSectionCode:            req.TDSSectionCode,
+		StateCode:                 req.StateCode,
+		GSTIN:                     req.GSTIN,
 	})
 	if err != nil {
 		return nil, err
@@ -601,24 +1709,93 @@ func (s *appService) CreateVendor(ctx context.Context, req CreateVendorRequest)
 	return &VendorResult{Vendor: vendor}, nil
 }
 
-// GetPurchaseOrder returns a single purchase order by its internal ID, validating company ownership.
-func (s *appService) GetPurchaseOrder(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error) {
+// GetVendor returns one vendor by code, including inactive and merged vendors.
+func (s *appService) GetVendor(ctx context.Context, companyCode, vendorCode string) (*VendorResult, error) {
 	company, err := s.fetchCompany(ctx, companyCode)
 	if err != nil {
 		return nil, err
 	}
-	po, err := s.purchaseOrderService.GetPO(ctx, poID)
+	vendor, err := s.vendorService.GetVendorByCode(ctx, company.ID, vendorCode)
 	if err != nil {
 		return nil, err
 	}
-	if po.CompanyID != company.ID {
-		return nil, fmt.Errorf("purchase order %d not found", poID)
-	}
-	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+	return &VendorResult{Vendor: vendor}, nil
 }
 
-// ListPurchaseOrders returns purchase orders for a company, optionally filtered by status.
-func (s *appService) ListPurchaseOrders(ctx context.Context, companyCode, status string) (*PurchaseOrdersResult, error) {
+// UpdateVendor replaces a vendor's editable fields, guarded by req.Version.
+func (s *appService) UpdateVendor(ctx context.Context, req UpdateVendorRequest) (*VendorResult, error) {
+	company, err := s.fetchCompany(ctx, req.CompanyCode)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := s.vendorService.UpdateVendor(ctx, company.ID, req.Code, req.Version, core.VendorInput{
+		Name:                      req.Name,
+		ContactPerson:             req.ContactPerson,
+		Email:                     req.Email,
+		Phone:                     req.Phone,
+		Address:                   req.Address,
+		PaymentTermsDays:          req.PaymentTermsDays,
+		APAccountCode:             req.APAccountCode,
+		DefaultExpenseAccountCode: req.DefaultExpenseAccountCode,
+		BankAccountName:           req.BankAccountName,
+		BankAccountNumber:         req.BankAccountNumber,
+		BankCode:                  req.BankCode,
+		Currency:                  req.Currency,
+		PAN:                       req.PAN,
+		TDSSectionCode:            req.TDSSectionCode,
+		StateCode:                 req.StateCode,
+		GSTIN:                     req.GSTIN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VendorResult{Vendor: vendor}, nil
+}
+
+// SetVendorActive deactivates or reactivates a vendor.
+func (s *appService) SetVendorActive(ctx context.Context, companyCode, vendorCode string, active bool) (*VendorResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := s.vendorService.SetVendorActive(ctx, company.ID, vendorCode, active)
+	if err != nil {
+		return nil, err
+	}
+	return &VendorResult{Vendor: vendor}, nil
+}
+
+// MergeVendors merges the duplicate vendor into the survivor.
+func (s *appService) MergeVendors(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	merge, err := s.vendorService.MergeVendors(ctx, company.ID, duplicateCode, survivorCode)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Merge: merge}, nil
+}
+
+// GetPurchaseOrder returns a single purchase order by its internal ID, validating company ownership.
+func (s *appService) GetPurchaseOrder(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	po, err := s.purchaseOrderService.GetPO(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	if po.CompanyID != company.ID {
+		return nil, fmt.Errorf("purchase order %d not found", poID)
+	}
+	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+}
+
+// ListPurchaseOrders returns purchase orders for a company, optionally filtered by status.
+func (s *appService) ListPurchaseOrders(ctx context.Context, companyCode, status string) (*PurchaseOrdersResult, error) {
 	company, err := s.fetchCompany(ctx, companyCode)
 	if err != nil {
 		return nil, err
@@ -652,12 +1829,16 @@ func (s *appService) CreatePurchaseOrder(ctx context.Context, req CreatePurchase
 			ProductCode:        l.ProductCode,
 			Description:        l.Description,
 			Quantity:           l.Quantity,
+			Unit:               l.Unit,
 			UnitCost:           l.UnitCost,
 			ExpenseAccountCode: l.ExpenseAccountCode,
+			TaxCode:            l.TaxCode,
+			Dimensions:         l.Dimensions,
 		})
 	}
 
-	po, err := s.purchaseOrderService.CreatePO(ctx, company.ID, vendor.ID, poDate, lines, req.Notes)
+	po, err := s.purchaseOrderService.CreatePO(ctx, company.ID, vendor.ID, req.Currency, req.ExchangeRate,
+		poDate, lines, req.Notes)
 	if err != nil {
 		return nil, err
 	}
@@ -680,104 +1861,1523 @@ func (s *appService) ApprovePurchaseOrder(ctx context.Context, companyCode strin
 	return &PurchaseOrderResult{PurchaseOrder: po}, nil
 }
 
-// buildToolRegistry constructs the ToolRegistry for Phase 7.5 with 5 read tools:
-// search_accounts, search_customers, search_products, get_stock_levels, get_warehouses.
-// Tool handlers are closures that capture the pool and companyCode.
-func (s *appService) buildToolRegistry(ctx context.Context, companyCode string) *ai.ToolRegistry {
-	registry := ai.NewToolRegistry()
+// ListReorderPolicies returns all active reorder policies for a company.
+func (s *appService) ListReorderPolicies(ctx context.Context, companyCode string) (*ReorderPoliciesResult, error) {
+	policies, err := s.replenishmentService.GetReorderPolicies(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &ReorderPoliciesResult{Policies: policies}, nil
+}
+
+// SetReorderPolicy creates or updates a reorder policy for a product in a warehouse.
+func (s *appService) SetReorderPolicy(ctx context.Context, req SetReorderPolicyRequest) (*ReorderPolicyResult, error) {
+	policy, err := s.replenishmentService.SetReorderPolicy(ctx, req.CompanyCode, core.ReorderPolicyInput{
+		ProductCode:         req.ProductCode,
+		WarehouseCode:       req.WarehouseCode,
+		ReorderPoint:        req.ReorderPoint,
+		ReorderQty:          req.ReorderQty,
+		LeadTimeDays:        req.LeadTimeDays,
+		PreferredVendorCode: req.PreferredVendorCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReorderPolicyResult{Policy: policy}, nil
+}
+
+// GetReplenishmentSuggestions returns products at or below their reorder point.
+func (s *appService) GetReplenishmentSuggestions(ctx context.Context, companyCode string) (*ReplenishmentSuggestionsResult, error) {
+	suggestions, err := s.replenishmentService.GetSuggestions(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplenishmentSuggestionsResult{Suggestions: suggestions}, nil
+}
+
+// CreateReplenishmentPOs raises DRAFT purchase orders, one per vendor, for current suggestions.
+func (s *appService) CreateReplenishmentPOs(ctx context.Context, req CreateReplenishmentPOsRequest) (*ReplenishmentRunResult, error) {
+	poDate := time.Now()
+	if req.PODate != "" {
+		d, err := time.Parse("2006-01-02", req.PODate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid po_date %q: %w", req.PODate, err)
+		}
+		poDate = d
+	}
+	run, err := s.replenishmentService.CreateReplenishmentPOs(ctx, req.CompanyCode, poDate, req.ProductCodes, s.purchaseOrderService)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplenishmentRunResult{PurchaseOrders: run.PurchaseOrders, Skipped: run.Skipped}, nil
+}
+
+// ListLandedCostVouchers returns all landed cost vouchers for a company.
+func (s *appService) ListLandedCostVouchers(ctx context.Context, companyCode string) (*LandedCostVouchersResult, error) {
+	vouchers, err := s.landedCostService.GetVouchers(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &LandedCostVouchersResult{CompanyCode: companyCode, Vouchers: vouchers}, nil
+}
+
+// GetLandedCostVoucher returns one landed cost voucher with charges and allocations.
+func (s *appService) GetLandedCostVoucher(ctx context.Context, companyCode string, voucherID int) (*LandedCostVoucherResult, error) {
+	voucher, err := s.landedCostService.GetVoucher(ctx, companyCode, voucherID)
+	if err != nil {
+		return nil, err
+	}
+	return &LandedCostVoucherResult{Voucher: voucher}, nil
+}
+
+// CreateLandedCostVoucher posts a landed cost voucher against goods receipts.
+func (s *appService) CreateLandedCostVoucher(ctx context.Context, req CreateLandedCostVoucherRequest) (*LandedCostVoucherResult, error) {
+	voucherDate := req.VoucherDate
+	if voucherDate == "" {
+		voucherDate = time.Now().Format("2006-01-02")
+	}
+	creditAccount := req.CreditAccountCode
+	if creditAccount == "" && req.VendorCode == "" {
+		creditAccount = "2000"
+	}
+	charges := make([]core.LandedCostChargeInput, len(req.Charges))
+	for i, c := range req.Charges {
+		charges[i] = core.LandedCostChargeInput{Description: c.Description, Amount: c.Amount}
+	}
+	voucher, err := s.landedCostService.CreateVoucher(ctx, req.CompanyCode, core.LandedCostVoucherInput{
+		VoucherDate:        voucherDate,
+		VendorCode:         req.VendorCode,
+		CreditAccountCode:  creditAccount,
+		AllocationMethod:   req.AllocationMethod,
+		PONumbers:          req.PONumbers,
+		ReceiptMovementIDs: req.ReceiptMovementIDs,
+		Charges:            charges,
+		Notes:              req.Notes,
+	}, s.ledger)
+	if err != nil {
+		return nil, err
+	}
+	return &LandedCostVoucherResult{Voucher: voucher}, nil
+}
+
+// SetProductWeight sets the weight of one stock unit of a product.
+func (s *appService) SetProductWeight(ctx context.Context, companyCode, productCode string, unitWeight decimal.Decimal) error {
+	return s.landedCostService.SetProductWeight(ctx, companyCode, productCode, unitWeight)
+}
+
+// ListVendorBills returns a company's vendor bills, optionally filtered by status.
+func (s *appService) ListVendorBills(ctx context.Context, companyCode, status string) (*VendorBillsResult, error) {
+	bills, err := s.vendorBillService.GetBills(ctx, companyCode, status)
+	if err != nil {
+		return nil, err
+	}
+	return &VendorBillsResult{CompanyCode: companyCode, Bills: bills}, nil
+}
+
+// GetVendorBill returns one vendor bill with its lines.
+func (s *appService) GetVendorBill(ctx context.Context, companyCode string, billID int) (*VendorBillResult, error) {
+	bill, err := s.vendorBillService.GetBill(ctx, companyCode, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &VendorBillResult{Bill: bill}, nil
+}
+
+// CreateVendorBill posts a vendor bill without a purchase order.
+func (s *appService) CreateVendorBill(ctx context.Context, req CreateVendorBillRequest) (*VendorBillResult, error) {
+	billDate := req.BillDate
+	if billDate == "" {
+		billDate = time.Now().Format("2006-01-02")
+	}
+	lines := make([]core.VendorBillLineInput, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = core.VendorBillLineInput{
+			ProductCode:        l.ProductCode,
+			Description:        l.Description,
+			Quantity:           l.Quantity,
+			UnitCost:           l.UnitCost,
+			ExpenseAccountCode: l.ExpenseAccountCode,
+			TaxCode:            l.TaxCode,
+		}
+	}
+	bill, err := s.vendorBillService.CreateBill(ctx, req.CompanyCode, core.VendorBillInput{
+		VendorCode: req.VendorCode,
+		BillNumber: req.BillNumber,
+		BillDate:   billDate,
+		DueDate:    req.DueDate,
+		Notes:      req.Notes,
+		Lines:      lines,
+	}, s.ledger, s.docService)
+	if err != nil {
+		return nil, err
+	}
+	return &VendorBillResult{Bill: bill}, nil
+}
+
+// PayVendorBill records payment of a POSTED vendor bill.
+func (s *appService) PayVendorBill(ctx context.Context, req PayVendorBillRequest) (*VendorBillResult, error) {
+	if err := s.vendorBillService.PayBill(
+		ctx, req.CompanyCode, req.BillID, req.BankAccountCode, req.PaymentDate, s.ledger,
+	); err != nil {
+		return nil, err
+	}
+	return s.GetVendorBill(ctx, req.CompanyCode, req.BillID)
+}
+
+// ListPaymentRuns returns a company's payment runs, newest first, optionally filtered by status.
+func (s *appService) ListPaymentRuns(ctx context.Context, companyCode, status string) (*PaymentRunsResult, error) {
+	runs, err := s.paymentRunService.GetRuns(ctx, companyCode, status)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentRunsResult{CompanyCode: companyCode, Runs: runs}, nil
+}
+
+// GetPaymentRun returns one payment run with its items.
+func (s *appService) GetPaymentRun(ctx context.Context, companyCode string, runID int) (*PaymentRunResult, error) {
+	run, err := s.paymentRunService.GetRun(ctx, companyCode, runID)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentRunResult{Run: run}, nil
+}
+
+// CreatePaymentRun proposes the open AP items due by req.DueBy as a DRAFT payment run.
+func (s *appService) CreatePaymentRun(ctx context.Context, req CreatePaymentRunRequest) (*PaymentRunResult, error) {
+	bankAccount := req.BankAccountCode
+	if bankAccount == "" {
+		bankAccount = "1100"
+	}
+	run, err := s.paymentRunService.CreateRun(ctx, req.CompanyCode, core.PaymentRunInput{
+		DueBy:           req.DueBy,
+		PaymentDate:     req.PaymentDate,
+		BankAccountCode: bankAccount,
+		VendorCode:      req.VendorCode,
+		CreatedBy:       req.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentRunResult{Run: run}, nil
+}
+
+// SetPaymentRunItemExcluded excludes an item from, or re-includes it in, a DRAFT payment run.
+func (s *appService) SetPaymentRunItemExcluded(ctx context.Context, companyCode string, runID, itemID int, excluded bool) (*PaymentRunResult, error) {
+	run, err := s.paymentRunService.SetItemExcluded(ctx, companyCode, runID, itemID, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentRunResult{Run: run}, nil
+}
+
+// ApprovePaymentRun approves a DRAFT payment run.
+func (s *appService) ApprovePaymentRun(ctx context.Context, companyCode string, runID int, approvedBy string) (*PaymentRunResult, error) {
+	if err := s.paymentRunService.ApproveRun(ctx, companyCode, runID, approvedBy); err != nil {
+		return nil, err
+	}
+	return s.GetPaymentRun(ctx, companyCode, runID)
+}
+
+// PostPaymentRun posts an APPROVED payment run: one payment entry per vendor.
+func (s *appService) PostPaymentRun(ctx context.Context, companyCode string, runID int) (*PaymentRunResult, error) {
+	if err := s.paymentRunService.PostRun(ctx, companyCode, runID, s.ledger); err != nil {
+		return nil, err
+	}
+	return s.GetPaymentRun(ctx, companyCode, runID)
+}
+
+// ExportPaymentRun builds the bank payment file for an APPROVED or POSTED run.
+func (s *appService) ExportPaymentRun(ctx context.Context, companyCode string, runID int, format string) (*core.PaymentFile, error) {
+	return s.paymentRunService.ExportRun(ctx, companyCode, runID, format)
+}
+
+// SetHouseBankDetails records the bank account number and bank code behind a bank GL account.
+func (s *appService) SetHouseBankDetails(ctx context.Context, companyCode, accountCode, accountNumber, bankCode string) error {
+	return s.paymentRunService.SetHouseBankDetails(ctx, companyCode, accountCode, accountNumber, bankCode)
+}
+
+// ListPurchaseReturns returns a company's purchase returns, optionally filtered by status and vendor.
+func (s *appService) ListPurchaseReturns(ctx context.Context, companyCode, status, vendorCode string) (*PurchaseReturnsResult, error) {
+	returns, err := s.purchaseReturnService.GetReturns(ctx, companyCode, status, vendorCode)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseReturnsResult{CompanyCode: companyCode, Returns: returns}, nil
+}
+
+// GetPurchaseReturn returns one purchase return with its lines and applications.
+func (s *appService) GetPurchaseReturn(ctx context.Context, companyCode string, returnID int) (*PurchaseReturnResult, error) {
+	ret, err := s.purchaseReturnService.GetReturn(ctx, companyCode, returnID)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseReturnResult{Return: ret}, nil
+}
+
+// CreatePurchaseReturn returns received goods to the vendor and raises a debit note.
+func (s *appService) CreatePurchaseReturn(ctx context.Context, req CreatePurchaseReturnRequest) (*PurchaseReturnResult, error) {
+	warehouseCode := req.WarehouseCode
+	if warehouseCode == "" {
+		wh, err := s.inventoryService.GetDefaultWarehouse(ctx, req.CompanyCode)
+		if err != nil {
+			return nil, fmt.Errorf("no active warehouse found: %w", err)
+		}
+		warehouseCode = wh.Code
+	}
+	lines := make([]core.PurchaseReturnLineInput, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = core.PurchaseReturnLineInput{POLineID: l.POLineID, Quantity: l.Quantity}
+	}
+	ret, err := s.purchaseReturnService.CreateReturn(ctx, req.CompanyCode, core.PurchaseReturnInput{
+		POID:          req.POID,
+		WarehouseCode: warehouseCode,
+		ReturnDate:    req.ReturnDate,
+		Reason:        req.Reason,
+		Lines:         lines,
+	}, s.ledger)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseReturnResult{Return: ret}, nil
+}
+
+// ListTDSSections returns the active TDS sections.
+func (s *appService) ListTDSSections(ctx context.Context) (*TDSSectionsResult, error) {
+	sections, err := s.tdsService.GetSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &TDSSectionsResult{Sections: sections}, nil
+}
+
+// GetVendorTDSStatus returns a vendor's fiscal-year TDS position as of asOfDate (default today).
+func (s *appService) GetVendorTDSStatus(ctx context.Context, companyCode, vendorCode, asOfDate string) (*core.TDSVendorStatus, error) {
+	asOf := time.Now()
+	if asOfDate != "" {
+		t, err := time.Parse("2006-01-02", asOfDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", asOfDate)
+		}
+		asOf = t
+	}
+	return s.tdsService.GetVendorTDSStatus(ctx, companyCode, vendorCode, asOf)
+}
+
+// GetTDSRegister returns the quarterly TDS register.
+func (s *appService) GetTDSRegister(ctx context.Context, companyCode string, fyStartYear, quarter int) (*core.TDSRegister, error) {
+	return s.tdsService.GetRegister(ctx, companyCode, fyStartYear, quarter)
+}
+
+// ListTaxCodes returns the GST codes and their rates as of asOfDate (default today).
+func (s *appService) ListTaxCodes(ctx context.Context, companyCode, asOfDate string) (*TaxCodesResult, error) {
+	asOf := time.Now()
+	if asOfDate != "" {
+		t, err := time.Parse("2006-01-02", asOfDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", asOfDate)
+		}
+		asOf = t
+	}
+	codes, err := s.taxEngine.GetTaxCodes(ctx, companyCode, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &TaxCodesResult{AsOf: asOf.Format("2006-01-02"), TaxCodes: codes}, nil
+}
+
+// SetTaxRate changes a GST component rate from its effective date.
+func (s *appService) SetTaxRate(ctx context.Context, req SetTaxRateRequest) error {
+	from, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		return fmt.Errorf("invalid effective date %q (expected YYYY-MM-DD)", req.EffectiveFrom)
+	}
+	return s.taxEngine.SetTaxRate(ctx, req.CompanyCode, req.TaxCode, req.Component, req.Rate, from)
+}
+
+// SetCompanyGST sets the company's GST registration.
+func (s *appService) SetCompanyGST(ctx context.Context, companyCode, stateCode, gstin string) error {
+	return s.taxEngine.SetCompanyGST(ctx, companyCode, stateCode, gstin)
+}
+
+// SetProductTax sets a product's HSN/SAC code and default GST code.
+func (s *appService) SetProductTax(ctx context.Context, companyCode, productCode, hsnCode, taxCode string) (*core.Product, error) {
+	return s.orderService.SetProductTax(ctx, companyCode, productCode, hsnCode, taxCode)
+}
+
+// GetGSTReturn returns GSTR-1 and GSTR-3B for period (YYYY-MM; empty = last month).
+func (s *appService) GetGSTReturn(ctx context.Context, companyCode, period string) (*core.GSTReturn, error) {
+	month := time.Now().AddDate(0, -1, 0)
+	if period != "" {
+		t, err := time.Parse("2006-01", period)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period %q (expected YYYY-MM)", period)
+		}
+		month = t
+	}
+	return s.gstReturnService.GetReturn(ctx, companyCode, month.Year(), int(month.Month()))
+}
+
+// SetEInvoicing turns e-invoicing on or off and sets the seller address.
+func (s *appService) SetEInvoicing(ctx context.Context, companyCode string, enabled bool, address string) error {
+	return s.eInvoiceService.SetEInvoicing(ctx, companyCode, enabled, address)
+}
+
+// GetEInvoicePayload returns the INV-01 payload of an invoiced order without registering it.
+func (s *appService) GetEInvoicePayload(ctx context.Context, ref, companyCode string) (*core.EInvoicePayload, error) {
+	order, err := s.resolveOrder(ctx, ref, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.eInvoiceService.BuildPayload(ctx, companyCode, order.ID)
+}
+
+// GenerateEInvoice registers an invoiced order with the IRP.
+func (s *appService) GenerateEInvoice(ctx context.Context, ref, companyCode string) (*core.EInvoice, error) {
+	order, err := s.resolveOrder(ctx, ref, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.eInvoiceService.GenerateIRN(ctx, companyCode, order.ID)
+}
+
+// GetEInvoice returns an order's latest e-invoice.
+func (s *appService) GetEInvoice(ctx context.Context, ref, companyCode string) (*core.EInvoice, error) {
+	order, err := s.resolveOrder(ctx, ref, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.eInvoiceService.GetEInvoice(ctx, companyCode, order.ID)
+}
+
+// CancelEInvoice cancels an order's IRN, reversing its invoice and reopening the order for invoicing.
+func (s *appService) CancelEInvoice(ctx context.Context, req CancelEInvoiceRequest) (*core.EInvoice, error) {
+	order, err := s.resolveOrder(ctx, req.Ref, req.CompanyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.eInvoiceService.CancelEInvoice(ctx, req.CompanyCode, order.ID, req.ReasonCode, req.Remark, s.ledger)
+}
+
+// buildToolRegistry constructs the ToolRegistry for Phase 7.5 with 5 read tools:
+// search_accounts, search_customers, search_products, get_stock_levels, get_warehouses.
+// Tool handlers are closures that capture the pool and companyCode.
+func (s *appService) buildToolRegistry(ctx context.Context, companyCode string) *ai.ToolRegistry {
+	registry := ai.NewToolRegistry()
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "search_accounts",
+		Description: "Search the chart of accounts by name or code. Returns top matching accounts with code, name, type, and current balance.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search text to match against account name or code (e.g. 'accounts receivable', '1200', 'sales revenue').",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			query, _ := params["query"].(string)
+			return s.searchAccounts(hctx, companyCode, query)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "search_customers",
+		Description: "Search the customer master by name or code. Returns matching customers with code, name, and contact information.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search text to match against customer name or code (e.g. 'Acme', 'C001').",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			query, _ := params["query"].(string)
+			return s.searchCustomers(hctx, companyCode, query)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "search_products",
+		Description: "Search the product catalogue by name or code. Returns matching products with code, name, and unit price.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search text to match against product name or code (e.g. 'Widget', 'P001').",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			query, _ := params["query"].(string)
+			return s.searchProducts(hctx, companyCode, query)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_product_units",
+		Description: "Get a product's stock unit, default purchase and sales units, and the conversion factor (stock units per unit) for every unit it can be bought or sold in.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"product_code": map[string]any{
+					"type":        "string",
+					"description": "Product code (e.g. 'P001').",
+				},
+			},
+			"required": []string{"product_code"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			productCode, _ := params["product_code"].(string)
+			return s.getProductUnitsJSON(hctx, companyCode, productCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_stock_levels",
+		Description: "Get current inventory stock levels. Optionally filter by product code or warehouse code.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"product_code": map[string]any{
+					"type":        "string",
+					"description": "Filter to a specific product code (optional).",
+				},
+				"warehouse_code": map[string]any{
+					"type":        "string",
+					"description": "Filter to a specific warehouse code (optional).",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			productCode, _ := params["product_code"].(string)
+			warehouseCode, _ := params["warehouse_code"].(string)
+			return s.getStockLevels(hctx, companyCode, productCode, warehouseCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_warehouses",
+		Description: "Get all active warehouses for the company.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]any{},
+			"required":             []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			return s.getWarehousesJSON(hctx, companyCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_account_balance",
+		Description: "Get the current balance for a specific account code. Returns the net debit position (positive = net debit, negative = net credit).",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"account_code": map[string]any{
+					"type":        "string",
+					"description": "The account code to query (e.g. '1200', '4000').",
+				},
+			},
+			"required": []string{"account_code"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			accountCode, _ := params["account_code"].(string)
+			return s.getAccountBalanceJSON(hctx, companyCode, accountCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_cash_flow",
+		Description: "Get the statement of cash flows for a date range: cash from operating, investing and financing activities, opening and closing cash. The indirect method starts from net income and adds back non-cash items such as cost of goods sold; the direct method lists cash received and paid by counter-account.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"from_date": map[string]any{
+					"type":        "string",
+					"description": "Start date YYYY-MM-DD (optional; defaults to the first day of to_date's month).",
+				},
+				"to_date": map[string]any{
+					"type":        "string",
+					"description": "End date YYYY-MM-DD (optional; defaults to today).",
+				},
+				"method": map[string]any{
+					"type":        "string",
+					"enum":        []string{"INDIRECT", "DIRECT"},
+					"description": "INDIRECT (default) or DIRECT.",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			fromDate, _ := params["from_date"].(string)
+			toDate, _ := params["to_date"].(string)
+			method, _ := params["method"].(string)
+			return s.getCashFlowJSON(hctx, companyCode, fromDate, toDate, method)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_comparative_report",
+		Description: "Get the P&L or balance sheet side by side for several periods. compare gives one period beside the prior period and the same period last year, with variance and variance %; trend gives the 12 months ending with a month; periods lists any periods. Periods are YYYY, YYYY-Qn, YYYY-MM or YYYY-MM-DD..YYYY-MM-DD. Whole months come from the reporting views — refresh them for very recent postings.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"statement": map[string]any{
+					"type":        "string",
+					"enum":        []string{"PL", "BALANCE_SHEET"},
+					"description": "PL (default) or BALANCE_SHEET (as of the end of each period).",
+				},
+				"compare": map[string]any{
+					"type":        "string",
+					"description": "Period to compare with the prior period and the same period last year (default: the current month).",
+				},
+				"trend": map[string]any{
+					"type":        "string",
+					"description": "Month YYYY-MM: report the 12 months ending with it instead.",
+				},
+				"periods": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Explicit list of periods, one column each; variances are taken from the first.",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			req := ColumnarReportRequest{CompanyCode: companyCode}
+			req.Compare, _ = params["compare"].(string)
+			req.Trend, _ = params["trend"].(string)
+			if list, ok := params["periods"].([]any); ok {
+				for _, p := range list {
+					if ps, ok := p.(string); ok {
+						req.Periods = append(req.Periods, ps)
+					}
+				}
+				req.Comparative = true
+			}
+			statement, _ := params["statement"].(string)
+			return s.getComparativeReportJSON(hctx, req, statement)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_budget_vs_actual",
+		Description: "Compare the budget with actual revenue and expenses for whole months, per account: budget, actual, variance (actual − budget), variance %, status OVER/UNDER/ON_TRACK against the budget's variance threshold, and whether the variance is favourable. Use it for questions like 'are we over budget on marketing this quarter?' — find the account by name in the lines. Periods are YYYY, YYYY-Qn (calendar quarters: 2026-Q2 is April–June), YYYY-MM or YYYY-MM-DD..YYYY-MM-DD on month boundaries. Actuals come from the reporting views — refresh them for very recent postings.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"period": map[string]any{
+					"type":        "string",
+					"description": "Period to compare (default: the current financial year to the end of this month).",
+				},
+				"budget_code": map[string]any{
+					"type":        "string",
+					"description": "Budget version code (default: the latest budget for the period's financial year).",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			req := BudgetVsActualRequest{CompanyCode: companyCode}
+			req.Period, _ = params["period"].(string)
+			req.BudgetCode, _ = params["budget_code"].(string)
+			return s.getBudgetVsActualJSON(hctx, req)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_dimensions",
+		Description: "List the company's analytic dimensions (COST_CENTER, PROJECT, BRANCH and user-defined ones) with their value codes, and the dimensions each account requires on every posting. Use it before proposing orders or purchase orders whose lines should carry a cost center, project or branch.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]any{},
+			"required":             []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			return s.getDimensionsJSON(hctx, companyCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_dimension_pl",
+		Description: "Profit and loss restricted to journal lines tagged with given dimension values and/or broken down with one column per value of a dimension. Use it for questions like 'what did project P-101 cost this quarter?' or 'expenses by cost center in June'. Periods are YYYY, YYYY-Qn (calendar quarters), YYYY-MM or YYYY-MM-DD..YYYY-MM-DD.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"period": map[string]any{
+					"type":        "string",
+					"description": "Reporting period (default: the current month).",
+				},
+				"filter": map[string]any{
+					"type":        "string",
+					"description": "Comma-separated DIMENSION:VALUE pairs every line must carry, e.g. 'PROJECT:P-101' (optional).",
+				},
+				"breakdown": map[string]any{
+					"type":        "string",
+					"description": "Dimension code to show one column per value of, e.g. 'COST_CENTER' (optional).",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			req := DimensionReportRequest{CompanyCode: companyCode}
+			req.Period, _ = params["period"].(string)
+			req.Filter, _ = params["filter"].(string)
+			req.Breakdown, _ = params["breakdown"].(string)
+			return s.getDimensionPLJSON(hctx, req)
+		},
+	})
+
+	// Phase 11 vendor tools
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_vendors",
+		Description: "List all active vendors for the company. Returns vendor code, name, contact, payment terms, and AP account.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]any{},
+			"required":             []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			return s.getVendorsJSON(hctx, companyCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "search_vendors",
+		Description: "Search vendors by name or code using partial match. Returns matching active vendors.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Search text to match against vendor name or code (e.g. 'Acme', 'V001').",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			query, _ := params["query"].(string)
+			return s.searchVendors(hctx, companyCode, query)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_vendor_info",
+		Description: "Get full details for a specific vendor by vendor code, including contact, address, payment terms, and AP account.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"vendor_code": map[string]any{
+					"type":        "string",
+					"description": "The vendor code (e.g. 'V001').",
+				},
+			},
+			"required": []string{"vendor_code"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			vendorCode, _ := params["vendor_code"].(string)
+			return s.getVendorInfoJSON(hctx, companyCode, vendorCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "create_vendor",
+		Description: "Propose creating a new vendor. The user must confirm before the vendor is saved. Requires at least a code and name.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"code": map[string]any{
+					"type":        "string",
+					"description": "Unique vendor code (e.g. 'V004').",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Full vendor name.",
+				},
+				"contact_person": map[string]any{
+					"type":        "string",
+					"description": "Name of the primary contact person (optional).",
+				},
+				"email": map[string]any{
+					"type":        "string",
+					"description": "Vendor contact email (optional).",
+				},
+				"phone": map[string]any{
+					"type":        "string",
+					"description": "Vendor phone number (optional).",
+				},
+				"address": map[string]any{
+					"type":        "string",
+					"description": "Vendor mailing address (optional).",
+				},
+				"payment_terms_days": map[string]any{
+					"type":        "integer",
+					"description": "Payment due in N days (default 30).",
+				},
+				"ap_account_code": map[string]any{
+					"type":        "string",
+					"description": "Accounts Payable account code (default '2000').",
+				},
+				"default_expense_account_code": map[string]any{
+					"type":        "string",
+					"description": "Default expense account code for this vendor's invoices (optional).",
+				},
+				"bank_account_name": map[string]any{
+					"type":        "string",
+					"description": "Beneficiary name on the vendor's bank account (optional; used in payment files).",
+				},
+				"bank_account_number": map[string]any{
+					"type":        "string",
+					"description": "Vendor bank account number or IBAN (optional; required before the vendor can be paid in a payment run export).",
+				},
+				"bank_code": map[string]any{
+					"type":        "string",
+					"description": "Vendor bank BIC or local clearing code such as IFSC (optional).",
+				},
+				"currency": map[string]any{
+					"type":        "string",
+					"description": "Default purchasing currency, 3-letter ISO code such as 'USD' (optional; defaults to the company base currency).",
+				},
+				"pan": map[string]any{
+					"type":        "string",
+					"description": "Vendor PAN, 10 characters such as 'AAACT1234F' (optional; without it TDS is withheld at the higher no-PAN rate).",
+				},
+				"tds_section_code": map[string]any{
+					"type":        "string",
+					"description": "TDS section withheld on this vendor's payments, e.g. '194C-OTH' or '194J-PROF' (optional; omit for no TDS).",
+				},
+				"state_code": map[string]any{
+					"type":        "string",
+					"description": "Two-digit GST state code, e.g. '27' for Maharashtra (optional; taken from the GSTIN when omitted).",
+				},
+				"gstin": map[string]any{
+					"type":        "string",
+					"description": "Vendor GSTIN, 15 characters such as '27AAPFU0939F1ZV' (optional).",
+				},
+			},
+			"required": []string{"code", "name"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	// Master data maintenance tools
+	registry.Register(ai.ToolDefinition{
+		Name:        "update_vendor",
+		Description: "Propose changes to an existing vendor. Only the fields supplied are changed. Requires the vendor's current version from get_vendor_info; the update is rejected if someone else changed the vendor since.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"vendor_code": map[string]any{
+					"type":        "string",
+					"description": "The vendor code (e.g. 'V001'). Codes cannot be changed.",
+				},
+				"version": map[string]any{
+					"type":        "integer",
+					"description": "The vendor's current version, as returned by get_vendor_info.",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "New vendor name.",
+				},
+				"contact_person": map[string]any{
+					"type":        "string",
+					"description": "New contact person.",
+				},
+				"email": map[string]any{
+					"type":        "string",
+					"description": "New contact email.",
+				},
+				"phone": map[string]any{
+					"type":        "string",
+					"description": "New phone number.",
+				},
+				"address": map[string]any{
+					"type":        "string",
+					"description": "New mailing address.",
+				},
+				"payment_terms_days": map[string]any{
+					"type":        "integer",
+					"description": "New payment terms in days.",
+				},
+				"ap_account_code": map[string]any{
+					"type":        "string",
+					"description": "New Accounts Payable account code.",
+				},
+				"default_expense_account_code": map[string]any{
+					"type":        "string",
+					"description": "New default expense account code.",
+				},
+				"bank_account_name": map[string]any{
+					"type":        "string",
+					"description": "New beneficiary name on the vendor's bank account.",
+				},
+				"bank_account_number": map[string]any{
+					"type":        "string",
+					"description": "New bank account number or IBAN.",
+				},
+				"bank_code": map[string]any{
+					"type":        "string",
+					"description": "New BIC or local clearing code (e.g. IFSC).",
+				},
+				"currency": map[string]any{
+					"type":        "string",
+					"description": "New default purchasing currency, 3-letter ISO code.",
+				},
+				"pan": map[string]any{
+					"type":        "string",
+					"description": "New PAN.",
+				},
+				"tds_section_code": map[string]any{
+					"type":        "string",
+					"description": "New TDS section code; an empty string stops TDS on this vendor.",
+				},
+				"state_code": map[string]any{
+					"type":        "string",
+					"description": "New two-digit GST state code.",
+				},
+				"gstin": map[string]any{
+					"type":        "string",
+					"description": "New GSTIN; an empty string clears it.",
+				},
+			},
+			"required": []string{"vendor_code", "version"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "set_vendor_active",
+		Description: "Propose deactivating or reactivating a vendor. Inactive vendors cannot be used on new purchase orders or vendor bills; existing documents are unaffected.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"vendor_code": map[string]any{
+					"type":        "string",
+					"description": "The vendor code (e.g. 'V001').",
+				},
+				"active": map[string]any{
+					"type":        "boolean",
+					"description": "false to deactivate, true to reactivate.",
+				},
+			},
+			"required": []string{"vendor_code", "active"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "merge_vendors",
+		Description: "Propose merging a duplicate vendor into a surviving vendor. All purchase orders, bills, returns, landed cost vouchers, reorder policies and payment run items move to the survivor and the duplicate is deactivated. Both vendors must use the same AP account. Cannot be undone.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"duplicate_vendor_code": map[string]any{
+					"type":        "string",
+					"description": "Code of the duplicate vendor to merge away.",
+				},
+				"survivor_vendor_code": map[string]any{
+					"type":        "string",
+					"description": "Code of the vendor that is kept.",
+				},
+			},
+			"required": []string{"duplicate_vendor_code", "survivor_vendor_code"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_customer_info",
+		Description: "Get full details for a specific customer by customer code, including contact details, credit limit, payment terms, status and version.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"customer_code": map[string]any{
+					"type":        "string",
+					"description": "The customer code (e.g. 'C001').",
+				},
+			},
+			"required": []string{"customer_code"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			customerCode, _ := params["customer_code"].(string)
+			return s.getCustomerInfoJSON(hctx, companyCode, customerCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "create_customer",
+		Description: "Propose creating a new customer. The user must confirm before the customer is saved. Requires at least a code and name.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"code": map[string]any{
+					"type":        "string",
+					"description": "Unique customer code (e.g. 'C010').",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Customer name.",
+				},
+				"email": map[string]any{
+					"type":        "string",
+					"description": "Billing email.",
+				},
+				"phone": map[string]any{
+					"type":        "string",
+					"description": "Phone number.",
+				},
+				"address": map[string]any{
+					"type":        "string",
+					"description": "Billing address.",
+				},
+				"credit_limit": map[string]any{
+					"type":        "number",
+					"description": "Credit limit in the company base currency.",
+				},
+				"payment_terms_days": map[string]any{
+					"type":        "integer",
+					"description": "Payment terms in days.",
+				},
+			},
+			"required": []string{"code", "name"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "update_customer",
+		Description: "Propose changes to an existing customer. Only the fields supplied are changed. Requires the customer's current version from get_customer_info; the update is rejected if someone else changed the customer since.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"customer_code": map[string]any{
+					"type":        "string",
+					"description": "The customer code (e.g. 'C001'). Codes cannot be changed.",
+				},
+				"version": map[string]any{
+					"type":        "integer",
+					"description": "The customer's current version, as returned by get_customer_info.",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Customer name.",
+				},
+				"email": map[string]any{
+					"type":        "string",
+					"description": "Billing email.",
+				},
+				"phone": map[string]any{
+					"type":        "string",
+					"description": "Phone number.",
+				},
+				"address": map[string]any{
+					"type":        "string",
+					"description": "Billing address.",
+				},
+				"credit_limit": map[string]any{
+					"type":        "number",
+					"description": "Credit limit in the company base currency.",
+				},
+				"payment_terms_days": map[string]any{
+					"type":        "integer",
+					"description": "Payment terms in days.",
+				},
+				"state_code": map[string]any{
+					"type":        "string",
+					"description": "Two-digit GST state code; decides CGST+SGST (same state as the company) or IGST on sales.",
+				},
+				"gstin": map[string]any{
+					"type":        "string",
+					"description": "Customer GSTIN; an empty string clears it.",
+				},
+			},
+			"required": []string{"customer_code", "version"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "set_customer_active",
+		Description: "Propose deactivating or reactivating a customer. Inactive customers cannot be used on new sales orders; existing orders are unaffected.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"customer_code": map[string]any{
+					"type":        "string",
+					"description": "The customer code (e.g. 'C001').",
+				},
+				"active": map[string]any{
+					"type":        "boolean",
+					"description": "false to deactivate, true to reactivate.",
+				},
+			},
+			"required": []string{"customer_code", "active"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "merge_customers",
+		Description: "Propose merging a duplicate customer into a surviving customer. All sales orders, including open invoices, move to the survivor and the duplicate is deactivated. Cannot be undone.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"duplicate_customer_code": map[string]any{
+					"type":        "string",
+					"description": "Code of the duplicate customer to merge away.",
+				},
+				"survivor_customer_code": map[string]any{
+					"type":        "string",
+					"description": "Code of the customer that is kept.",
+				},
+			},
+			"required": []string{"duplicate_customer_code", "survivor_customer_code"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	// Phase 12 purchase order tools
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_purchase_orders",
+		Description: "List purchase orders for the company. Optionally filter by status: DRAFT, APPROVED, PARTIALLY_RECEIVED, RECEIVED, INVOICED, PAID, CANCELLED. Empty status returns all orders.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"status": map[string]any{
+					"type":        "string",
+					"description": "Filter by PO status (optional). One of: DRAFT, APPROVED, PARTIALLY_RECEIVED, RECEIVED, INVOICED, PAID, CANCELLED.",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			status, _ := params["status"].(string)
+			return s.getPurchaseOrdersJSON(hctx, companyCode, status)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_open_pos",
+		Description: "List all open (DRAFT, APPROVED or PARTIALLY_RECEIVED) purchase orders for the company — orders not yet fully received, invoiced, or paid. Each PO includes its lines with ordered, received and outstanding quantities.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]any{},
+			"required":             []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			return s.getOpenPOsJSON(hctx, companyCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "create_purchase_order",
+		Description: "Propose creating a new purchase order for a vendor. The user must confirm before the PO is saved. Requires vendor code, PO date, and at least one line item. The PO is raised in the vendor's default currency unless another currency is given; a foreign-currency PO needs the exchange rate to the company base currency.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"vendor_code": map[string]any{
+					"type":        "string",
+					"description": "Vendor code (e.g. 'V001').",
+				},
+				"po_date": map[string]any{
+					"type":        "string",
+					"description": "Purchase order date in YYYY-MM-DD format.",
+				},
+				"currency": map[string]any{
+					"type":        "string",
+					"description": "PO currency, 3-letter ISO code (optional; defaults to the vendor's currency, then the company base currency).",
+				},
+				"exchange_rate": map[string]any{
+					"type":        "number",
+					"description": "Base-currency units per unit of the PO currency, e.g. 83.25 for USD in an INR company. Required when the PO currency is not the base currency.",
+				},
+				"notes": map[string]any{
+					"type":        "string",
+					"description": "Optional notes or instructions for the PO.",
+				},
+				"lines": map[string]any{
+					"type":        "array",
+					"description": "List of PO line items. At least one required.",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"product_code": map[string]any{
+								"type":        "string",
+								"description": "Product code if ordering a catalogued product (optional).",
+							},
+							"description": map[string]any{
+								"type":        "string",
+								"description": "Line item description.",
+							},
+							"quantity": map[string]any{
+								"type":        "number",
+								"description": "Quantity ordered, in the line unit.",
+							},
+							"unit": map[string]any{
+								"type":        "string",
+								"description": "Unit of measure for the line (e.g. 'CTN'). Optional; defaults to the product's purchase unit. Must have a conversion to the product's stock unit — see get_product_units.",
+							},
+							"unit_cost": map[string]any{
+								"type":        "number",
+								"description": "Unit cost in the PO currency, per line unit.",
+							},
+							"expense_account_code": map[string]any{
+								"type":        "string",
+								"description": "Expense account code for non-inventory lines (optional).",
+							},
+							"tax_code": map[string]any{
+								"type":        "string",
+								"description": "GST code such as 'GST18' (optional; defaults to the product's tax code). See get_tax_codes.",
+							},
+							"dimensions": map[string]any{
+								"type":        "array",
+								"description": "Analytic dimensions of the line, e.g. the cost center or project the purchase is for (optional). See get_dimensions.",
+								"items": map[string]any{
+									"type":                 "object",
+									"additionalProperties": false,
+									"properties": map[string]any{
+										"dimension": map[string]any{"type": "string", "description": "Dimension code, e.g. 'COST_CENTER' or 'PROJECT'."},
+										"value":     map[string]any{"type": "string", "description": "Code of one of the dimension's values."},
+									},
+									"required": []string{"dimension", "value"},
+								},
+							},
+						},
+						"required": []string{"description", "quantity", "unit_cost"},
+					},
+				},
+			},
+			"required": []string{"vendor_code", "po_date", "lines"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "approve_po",
+		Description: "Propose approving a DRAFT purchase order. Assigns a gapless PO number on approval. The user must confirm before the action is executed.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the purchase order to approve.",
+				},
+			},
+			"required": []string{"po_id"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	// Phase 13 goods receipt tools
+	registry.Register(ai.ToolDefinition{
+		Name:        "check_stock_availability",
+		Description: "Check current inventory stock levels for products, optionally filtered by an APPROVED purchase order. Returns on-hand, reserved, and available quantities per product/warehouse, plus PO line details when a po_id is provided.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Optional: purchase order ID to show stock levels for products in that PO.",
+				},
+				"product_code": map[string]any{
+					"type":        "string",
+					"description": "Optional: filter to a specific product code.",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			poID := 0
+			if v, ok := params["po_id"].(float64); ok {
+				poID = int(v)
+			}
+			productCode, _ := params["product_code"].(string)
+			return s.checkStockAvailabilityJSON(hctx, companyCode, poID, productCode)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "receive_po",
+		Description: "Propose recording goods/services received against an APPROVED or PARTIALLY_RECEIVED purchase order. Lines may be received in several deliveries, up to the outstanding quantity; the PO stays PARTIALLY_RECEIVED until every line is in. Updates inventory stock levels and creates the DR Inventory / CR AP accounting entry. The user must confirm before the receipt is posted.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the approved purchase order to receive against.",
+				},
+				"warehouse_code": map[string]any{
+					"type":        "string",
+					"description": "Warehouse code to receive goods into (optional; defaults to the company's default warehouse).",
+				},
+				"lines": map[string]any{
+					"type":        "array",
+					"description": "Lines to receive. At least one required.",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"po_line_id": map[string]any{
+								"type":        "integer",
+								"description": "Internal ID of the purchase order line being received.",
+							},
+							"qty_received": map[string]any{
+								"type":        "number",
+								"description": "Quantity received on this line.",
+							},
+							"lots": map[string]any{
+								"type":        "array",
+								"description": "Required for lot- or serial-tracked products: lot/serial numbers received, quantities summing to qty_received.",
+								"items": map[string]any{
+									"type":                 "object",
+									"additionalProperties": false,
+									"properties": map[string]any{
+										"lot_number":  map[string]any{"type": "string", "description": "Lot or serial number."},
+										"expiry_date": map[string]any{"type": "string", "description": "Optional expiry date in YYYY-MM-DD format."},
+										"quantity":    map[string]any{"type": "number", "description": "Units in this lot (1 for serials)."},
+									},
+									"required": []string{"lot_number"},
+								},
+							},
+						},
+						"required": []string{"po_line_id", "qty_received"},
+					},
+				},
+			},
+			"required": []string{"po_id", "lines"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "short_close_po",
+		Description: "Propose short-closing a PARTIALLY_RECEIVED purchase order: the undelivered balance is cancelled and the PO moves to RECEIVED so it can be invoiced for what arrived. The user must confirm before the PO is closed.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the partially received purchase order.",
+				},
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Optional reason, e.g. 'Vendor cannot supply the balance'.",
+				},
+			},
+			"required": []string{"po_id"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "search_accounts",
-		Description: "Search the chart of accounts by name or code. Returns top matching accounts with code, name, type, and current balance.",
-		IsReadTool:  true,
+		Name:        "amend_po",
+		Description: "Propose amending an APPROVED purchase order: change line quantities or unit costs, add or remove lines, or change the expected delivery date. Each amendment creates a new PO revision recorded in the revision history. Use get_open_pos first for po_line_id values. Pass lines only when changing them, and then pass every line the PO should keep: existing lines omitted from the list are removed, lines without po_line_id are added. The user must confirm before the PO is amended.",
+		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"query": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the APPROVED purchase order.",
+				},
+				"expected_delivery_date": map[string]any{
 					"type":        "string",
-					"description": "Search text to match against account name or code (e.g. 'accounts receivable', '1200', 'sales revenue').",
+					"description": "New expected delivery date in YYYY-MM-DD format (optional).",
+				},
+				"lines": map[string]any{
+					"type":        "array",
+					"description": "Complete amended line set (optional; omit to keep the lines unchanged).",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"po_line_id":   map[string]any{"type": "integer", "description": "Existing PO line ID; omit for a new line."},
+							"product_code": map[string]any{"type": "string", "description": "Product code (new lines only)."},
+							"description":  map[string]any{"type": "string", "description": "Line description."},
+							"quantity":     map[string]any{"type": "number", "description": "Ordered quantity."},
+							"unit_cost":    map[string]any{"type": "number", "description": "Unit cost."},
+						},
+						"required": []string{"quantity", "unit_cost"},
+					},
+				},
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Reason for the amendment (optional).",
+				},
+				"require_reapproval": map[string]any{
+					"type":        "boolean",
+					"description": "Return the PO to DRAFT until it is approved again (optional, default false).",
 				},
 			},
-			"required": []string{"query"},
+			"required": []string{"po_id"},
 		},
-		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			query, _ := params["query"].(string)
-			return s.searchAccounts(hctx, companyCode, query)
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "cancel_po",
+		Description: "Propose cancelling a DRAFT or APPROVED purchase order on which nothing has been received. The user must confirm before the PO is cancelled.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the purchase order.",
+				},
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Optional reason, e.g. 'Ordered from another vendor'.",
+				},
+			},
+			"required": []string{"po_id"},
 		},
+		Handler: nil, // write tool — no autonomous execution
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "search_customers",
-		Description: "Search the customer master by name or code. Returns matching customers with code, name, and contact information.",
+		Name:        "get_po_revisions",
+		Description: "Get a purchase order's revision history: creation, approvals, amendments (with each changed field, old and new values), cancellation and short-close, with reason, user and time.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"query": map[string]any{
-					"type":        "string",
-					"description": "Search text to match against customer name or code (e.g. 'Acme', 'C001').",
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the purchase order.",
 				},
 			},
-			"required": []string{"query"},
+			"required": []string{"po_id"},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			query, _ := params["query"].(string)
-			return s.searchCustomers(hctx, companyCode, query)
+			poID, _ := params["po_id"].(float64)
+			return s.getPORevisionsJSON(hctx, companyCode, int(poID))
 		},
 	})
 
+	// Phase 14 vendor invoice + payment tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "search_products",
-		Description: "Search the product catalogue by name or code. Returns matching products with code, name, and unit price.",
+		Name:        "get_ap_balance",
+		Description: "Get the current Accounts Payable balance for the company and optionally for a specific vendor. Returns total outstanding AP across invoiced purchase orders and unpaid vendor bills, less open vendor debit notes.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"query": map[string]any{
+				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "Search text to match against product name or code (e.g. 'Widget', 'P001').",
+					"description": "Optional: filter to a specific vendor code to see AP balance for that vendor only.",
 				},
 			},
-			"required": []string{"query"},
+			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			query, _ := params["query"].(string)
-			return s.searchProducts(hctx, companyCode, query)
+			vendorCode, _ := params["vendor_code"].(string)
+			return s.getAPBalanceJSON(hctx, companyCode, vendorCode)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_stock_levels",
-		Description: "Get current inventory stock levels. Optionally filter by product code or warehouse code.",
+		Name:        "get_vendor_payment_history",
+		Description: "Get payment history for a vendor: paid purchase orders and vendor bills with invoice numbers, amounts, and payment dates.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"product_code": map[string]any{
-					"type":        "string",
-					"description": "Filter to a specific product code (optional).",
-				},
-				"warehouse_code": map[string]any{
+				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "Filter to a specific warehouse code (optional).",
+					"description": "The vendor code to retrieve payment history for (e.g. 'V001').",
 				},
 			},
-			"required": []string{},
+			"required": []string{"vendor_code"},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			productCode, _ := params["product_code"].(string)
-			warehouseCode, _ := params["warehouse_code"].(string)
-			return s.getStockLevels(hctx, companyCode, productCode, warehouseCode)
+			vendorCode, _ := params["vendor_code"].(string)
+			return s.getVendorPaymentHistoryJSON(hctx, companyCode, vendorCode)
 		},
 	})
 
+	// TDS tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_warehouses",
-		Description: "Get all active warehouses for the company.",
+		Name:        "get_tds_sections",
+		Description: "List the TDS sections a vendor can be assigned to (e.g. 194C-OTH contractors, 194J-PROF professional fees), with rate, no-PAN rate, and single-payment and annual thresholds.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
@@ -786,143 +3386,200 @@ func (s *appService) buildToolRegistry(ctx context.Context, companyCode string)
 			"required":             []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			return s.getWarehousesJSON(hctx, companyCode)
+			return s.getTDSSectionsJSON(hctx)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_account_balance",
-		Description: "Get the current balance for a specific account code. Returns the net debit position (positive = net debit, negative = net credit).",
+		Name:        "get_tds_status",
+		Description: "Get a vendor's TDS position for the Indian financial year (April–March): payments so far, TDS withheld, the rate that applies and whether the annual threshold has been crossed. Use before paying a TDS vendor to explain the withholding.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"account_code": map[string]any{
+				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "The account code to query (e.g. '1200', '4000').",
+					"description": "The vendor code (e.g. 'V001').",
+				},
+				"as_of_date": map[string]any{
+					"type":        "string",
+					"description": "Any date in the financial year, YYYY-MM-DD (optional; defaults to today).",
 				},
 			},
-			"required": []string{"account_code"},
+			"required": []string{"vendor_code"},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			accountCode, _ := params["account_code"].(string)
-			return s.getAccountBalanceJSON(hctx, companyCode, accountCode)
+			vendorCode, _ := params["vendor_code"].(string)
+			asOfDate, _ := params["as_of_date"].(string)
+			return s.getTDSStatusJSON(hctx, companyCode, vendorCode, asOfDate)
 		},
 	})
 
-	// Phase 11 vendor tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_vendors",
-		Description: "List all active vendors for the company. Returns vendor code, name, contact, payment terms, and AP account.",
+		Name:        "get_tds_register",
+		Description: "Get the quarterly TDS register: every payment TDS was withheld on in the quarter (vendor, PAN, section, gross, TDS, payment document) with totals per section. Quarter 1 is April–June, quarter 4 is January–March.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
-			"properties":           map[string]any{},
-			"required":             []string{},
+			"properties": map[string]any{
+				"financial_year": map[string]any{
+					"type":        "integer",
+					"description": "Year the financial year starts in, e.g. 2026 for FY 2026-27.",
+				},
+				"quarter": map[string]any{
+					"type":        "integer",
+					"description": "Quarter of the financial year, 1 to 4.",
+				},
+			},
+			"required": []string{"financial_year", "quarter"},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			return s.getVendorsJSON(hctx, companyCode)
+			fy, _ := params["financial_year"].(float64)
+			quarter, _ := params["quarter"].(float64)
+			return s.getTDSRegisterJSON(hctx, companyCode, int(fy), int(quarter))
 		},
 	})
 
+	// GST tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "search_vendors",
-		Description: "Search vendors by name or code using partial match. Returns matching active vendors.",
+		Name:        "get_tax_codes",
+		Description: "List the company's GST codes (e.g. GST5, GST18) with their CGST, SGST and IGST rates in effect on a date. A supply within the company's state is taxed CGST + SGST, an inter-state supply IGST.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"query": map[string]any{
+				"as_of_date": map[string]any{
 					"type":        "string",
-					"description": "Search text to match against vendor name or code (e.g. 'Acme', 'V001').",
+					"description": "Date the rates apply on, YYYY-MM-DD (optional; defaults to today).",
 				},
 			},
-			"required": []string{"query"},
+			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			query, _ := params["query"].(string)
-			return s.searchVendors(hctx, companyCode, query)
+			asOfDate, _ := params["as_of_date"].(string)
+			return s.getTaxCodesJSON(hctx, companyCode, asOfDate)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_vendor_info",
-		Description: "Get full details for a specific vendor by vendor code, including contact, address, payment terms, and AP account.",
+		Name:        "get_gst_return",
+		Description: "Summarise the monthly GST returns: GSTR-1 outward supplies (B2B invoices, B2C totals, HSN summary) and GSTR-3B (output tax, input tax credit), with the reconciliation of the returned tax to the GST ledger accounts and any data issues such as invalid GSTINs or missing HSN codes.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"vendor_code": map[string]any{
+				"period": map[string]any{
 					"type":        "string",
-					"description": "The vendor code (e.g. 'V001').",
+					"description": "Return month, YYYY-MM (optional; defaults to last month).",
 				},
 			},
-			"required": []string{"vendor_code"},
+			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			vendorCode, _ := params["vendor_code"].(string)
-			return s.getVendorInfoJSON(hctx, companyCode, vendorCode)
+			period, _ := params["period"].(string)
+			return s.getGSTReturnJSON(hctx, companyCode, period)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "create_vendor",
-		Description: "Propose creating a new vendor. The user must confirm before the vendor is saved. Requires at least a code and name.",
-		IsReadTool:  false, // write tool — requires human confirmation
+		Name:        "get_einvoice",
+		Description: "Show the e-invoice (IRN) of an invoiced sales order: IRN, acknowledgement number and date, status (GENERATED or CANCELLED) with the cancellation reason, and the invoice value reported to the IRP.",
+		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"code": map[string]any{
+				"order_ref": map[string]any{
 					"type":        "string",
-					"description": "Unique vendor code (e.g. 'V004').",
+					"description": "Order number (e.g. SO-2026-00001) or numeric order ID.",
 				},
-				"name": map[string]any{
-					"type":        "string",
-					"description": "Full vendor name.",
+			},
+			"required": []string{"order_ref"},
+		},
+		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			ref, _ := params["order_ref"].(string)
+			return s.getEInvoiceJSON(hctx, companyCode, ref)
+		},
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "record_vendor_invoice",
+		Description: "Propose recording a vendor invoice against a RECEIVED purchase order. Each invoice line is three-way matched against the PO line and the quantity received; differences within the company's tolerances are posted to purchase price variance (or inventory), and any line outside tolerance blocks the PO for payment. Creates a PI document number and transitions PO to INVOICED. The user must confirm before the action is executed.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the RECEIVED purchase order to invoice.",
 				},
-				"contact_person": map[string]any{
+				"invoice_number": map[string]any{
 					"type":        "string",
-					"description": "Name of the primary contact person (optional).",
+					"description": "Vendor's invoice number as printed on the bill.",
 				},
-				"email": map[string]any{
+				"invoice_date": map[string]any{
 					"type":        "string",
-					"description": "Vendor contact email (optional).",
+					"description": "Invoice date in YYYY-MM-DD format.",
 				},
-				"phone": map[string]any{
-					"type":        "string",
-					"description": "Vendor phone number (optional).",
+				"invoice_amount": map[string]any{
+					"type":        "number",
+					"description": "Total invoice amount in base currency. Required when lines are omitted (header-level match); otherwise must equal the sum of the lines if given.",
 				},
-				"address": map[string]any{
-					"type":        "string",
-					"description": "Vendor mailing address (optional).",
+				"lines": map[string]any{
+					"type":        "array",
+					"description": "Invoice lines as billed by the vendor, one per PO line. Omit to match on invoice_amount alone.",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"po_line_id": map[string]any{"type": "integer", "description": "Internal ID of the purchase order line being billed."},
+							"quantity":   map[string]any{"type": "number", "description": "Quantity billed, in the PO line's unit."},
+							"unit_price": map[string]any{"type": "number", "description": "Unit price billed, in base currency."},
+						},
+						"required": []string{"po_line_id", "quantity", "unit_price"},
+					},
 				},
-				"payment_terms_days": map[string]any{
+			},
+			"required": []string{"po_id", "invoice_number", "invoice_date"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	registry.Register(ai.ToolDefinition{
+		Name:        "pay_vendor",
+		Description: "Propose paying a vendor for an INVOICED purchase order. Posts DR AP / CR Bank and transitions PO to PAID; open debit notes from purchase returns to the vendor are offset first, reducing the amount paid from the bank. The user must confirm before the payment is posted.",
+		IsReadTool:  false, // write tool — requires human confirmation
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"po_id": map[string]any{
 					"type":        "integer",
-					"description": "Payment due in N days (default 30).",
+					"description": "Internal ID of the INVOICED purchase order to pay.",
 				},
-				"ap_account_code": map[string]any{
+				"bank_account_code": map[string]any{
 					"type":        "string",
-					"description": "Accounts Payable account code (default '2000').",
+					"description": "Account code of the bank account to pay from (e.g. '1100').",
 				},
-				"default_expense_account_code": map[string]any{
+				"payment_date": map[string]any{
 					"type":        "string",
-					"description": "Default expense account code for this vendor's invoices (optional).",
+					"description": "Payment date in YYYY-MM-DD format.",
 				},
 			},
-			"required": []string{"code", "name"},
+			"required": []string{"po_id", "bank_account_code", "payment_date"},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
 
-	// Phase 12 purchase order tools
+	// Vendor bills — AP documents without a purchase order
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_purchase_orders",
-		Description: "List purchase orders for the company. Optionally filter by status: DRAFT, APPROVED, RECEIVED, INVOICED, PAID. Empty status returns all orders.",
+		Name:        "get_vendor_bills",
+		Description: "List vendor bills (AP invoices entered without a purchase order, e.g. utilities or professional fees). Optionally filter by status: POSTED (unpaid) or PAID.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
@@ -930,35 +3587,20 @@ func (s *appService) buildToolRegistry(ctx context.Context, companyCode string)
 			"properties": map[string]any{
 				"status": map[string]any{
 					"type":        "string",
-					"description": "Filter by PO status (optional). One of: DRAFT, APPROVED, RECEIVED, INVOICED, PAID.",
+					"description": "Filter by bill status (optional). One of: POSTED, PAID.",
 				},
 			},
 			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
 			status, _ := params["status"].(string)
-			return s.getPurchaseOrdersJSON(hctx, companyCode, status)
-		},
-	})
-
-	registry.Register(ai.ToolDefinition{
-		Name:        "get_open_pos",
-		Description: "List all open (DRAFT or APPROVED) purchase orders for the company — orders not yet received, invoiced, or paid.",
-		IsReadTool:  true,
-		InputSchema: map[string]any{
-			"type":                 "object",
-			"additionalProperties": false,
-			"properties":           map[string]any{},
-			"required":             []string{},
-		},
-		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			return s.getOpenPOsJSON(hctx, companyCode)
+			return s.getVendorBillsJSON(hctx, companyCode, status)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "create_purchase_order",
-		Description: "Propose creating a new purchase order for a vendor. The user must confirm before the PO is saved. Requires vendor code, PO date, and at least one line item.",
+		Name:        "create_vendor_bill",
+		Description: "Propose recording a vendor bill that has no purchase order (utilities, rent, professional fees, ad-hoc purchases). Posts DR expense per line / CR the vendor's AP account, assigns a PI document number, and sets the due date from the vendor's payment terms. The user must confirm before the bill is posted.",
 		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
@@ -966,237 +3608,312 @@ func (s *appService) buildToolRegistry(ctx context.Context, companyCode string)
 			"properties": map[string]any{
 				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "Vendor code (e.g. 'V001').",
+					"description": "Vendor code (e.g. 'V001').",
+				},
+				"bill_number": map[string]any{
+					"type":        "string",
+					"description": "The vendor's invoice number.",
+				},
+				"bill_date": map[string]any{
+					"type":        "string",
+					"description": "Bill date in YYYY-MM-DD format (optional; defaults to today).",
 				},
-				"po_date": map[string]any{
+				"due_date": map[string]any{
 					"type":        "string",
-					"description": "Purchase order date in YYYY-MM-DD format.",
+					"description": "Optional due date in YYYY-MM-DD format; defaults to bill date plus the vendor's payment terms.",
 				},
 				"notes": map[string]any{
 					"type":        "string",
-					"description": "Optional notes or instructions for the PO.",
+					"description": "Optional notes.",
 				},
 				"lines": map[string]any{
 					"type":        "array",
-					"description": "List of PO line items. At least one required.",
+					"description": "Bill lines. At least one required.",
 					"items": map[string]any{
 						"type":                 "object",
 						"additionalProperties": false,
 						"properties": map[string]any{
-							"product_code": map[string]any{
-								"type":        "string",
-								"description": "Product code if ordering a catalogued product (optional).",
-							},
 							"description": map[string]any{
 								"type":        "string",
-								"description": "Line item description.",
+								"description": "Line description (defaults to the product name when product_code is given).",
+							},
+							"amount": map[string]any{
+								"type":        "number",
+								"description": "Line amount. Use instead of quantity × unit_cost for a single charge.",
 							},
 							"quantity": map[string]any{
 								"type":        "number",
-								"description": "Quantity ordered.",
+								"description": "Quantity (optional; defaults to 1).",
 							},
 							"unit_cost": map[string]any{
 								"type":        "number",
-								"description": "Unit cost in the PO currency.",
+								"description": "Cost per unit.",
 							},
 							"expense_account_code": map[string]any{
 								"type":        "string",
-								"description": "Expense account code for non-inventory lines (optional).",
+								"description": "Expense account to debit (optional; defaults to the vendor's default expense account).",
+							},
+							"product_code": map[string]any{
+								"type":        "string",
+								"description": "Optional product the cost relates to. Bills do not move stock.",
+							},
+							"tax_code": map[string]any{
+								"type":        "string",
+								"description": "GST code such as 'GST18' (optional; defaults to the product's tax code). Input GST is added to the bill total.",
 							},
 						},
-						"required": []string{"description", "quantity", "unit_cost"},
+						"required": []string{},
 					},
 				},
 			},
-			"required": []string{"vendor_code", "po_date", "lines"},
+			"required": []string{"vendor_code", "bill_number", "lines"},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "approve_po",
-		Description: "Propose approving a DRAFT purchase order. Assigns a gapless PO number on approval. The user must confirm before the action is executed.",
+		Name:        "pay_vendor_bill",
+		Description: "Propose paying an unpaid (POSTED) vendor bill. Posts DR AP / CR Bank and marks the bill PAID. The user must confirm before the payment is posted.",
 		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"po_id": map[string]any{
+				"bill_id": map[string]any{
 					"type":        "integer",
-					"description": "Internal ID of the purchase order to approve.",
+					"description": "Internal ID of the vendor bill to pay.",
+				},
+				"bank_account_code": map[string]any{
+					"type":        "string",
+					"description": "Account code of the bank account to pay from (e.g. '1100').",
+				},
+				"payment_date": map[string]any{
+					"type":        "string",
+					"description": "Payment date in YYYY-MM-DD format.",
 				},
 			},
-			"required": []string{"po_id"},
+			"required": []string{"bill_id", "bank_account_code", "payment_date"},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
 
-	// Phase 13 goods receipt tools
+	// Payment run tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "check_stock_availability",
-		Description: "Check current inventory stock levels for products, optionally filtered by an APPROVED purchase order. Returns on-hand, reserved, and available quantities per product/warehouse, plus PO line details when a po_id is provided.",
+		Name:        "get_payment_runs",
+		Description: "List batch vendor payment runs with their status (DRAFT, APPROVED, POSTED), due-by date, bank account and total. Pass run_id to get one run with its items (vendor, document, due date, amount, excluded flag).",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"po_id": map[string]any{
-					"type":        "integer",
-					"description": "Optional: purchase order ID to show stock levels for products in that PO.",
-				},
-				"product_code": map[string]any{
+				"status": map[string]any{
 					"type":        "string",
-					"description": "Optional: filter to a specific product code.",
+					"description": "Filter by run status (optional). One of: DRAFT, APPROVED, POSTED.",
+				},
+				"run_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of one payment run to show with its items (optional).",
 				},
 			},
 			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			poID := 0
-			if v, ok := params["po_id"].(float64); ok {
-				poID = int(v)
-			}
-			productCode, _ := params["product_code"].(string)
-			return s.checkStockAvailabilityJSON(hctx, companyCode, poID, productCode)
+			status, _ := params["status"].(string)
+			runID, _ := params["run_id"].(float64)
+			return s.getPaymentRunsJSON(hctx, companyCode, status, int(runID))
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "receive_po",
-		Description: "Propose recording goods/services received against an APPROVED purchase order. Updates inventory stock levels and creates the DR Inventory / CR AP accounting entry. The user must confirm before the receipt is posted.",
+		Name:        "create_payment_run",
+		Description: "Propose a DRAFT batch payment run: selects every open AP item (invoiced purchase orders and unpaid vendor bills) due on or before due_by, optionally for one vendor. Runs pay in the company base currency; foreign-currency purchase orders are paid individually with pay_vendor. Items can then be reviewed and excluded; a FINANCE_MANAGER approves and posts the run on the Payment Runs page. The user must confirm before the run is created.",
 		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"po_id": map[string]any{
-					"type":        "integer",
-					"description": "Internal ID of the approved purchase order to receive against.",
+				"due_by": map[string]any{
+					"type":        "string",
+					"description": "Include items due on or before this date (YYYY-MM-DD).",
 				},
-				"warehouse_code": map[string]any{
+				"payment_date": map[string]any{
 					"type":        "string",
-					"description": "Warehouse code to receive goods into (optional; defaults to the company's default warehouse).",
+					"description": "Payment date in YYYY-MM-DD format (optional; defaults to due_by).",
 				},
-				"lines": map[string]any{
-					"type":        "array",
-					"description": "Lines to receive. At least one required.",
-					"items": map[string]any{
-						"type":                 "object",
-						"additionalProperties": false,
-						"properties": map[string]any{
-							"po_line_id": map[string]any{
-								"type":        "integer",
-								"description": "Internal ID of the purchase order line being received.",
-							},
-							"qty_received": map[string]any{
-								"type":        "number",
-								"description": "Quantity received on this line.",
-							},
-						},
-						"required": []string{"po_line_id", "qty_received"},
-					},
+				"bank_account_code": map[string]any{
+					"type":        "string",
+					"description": "Account code of the bank account to pay from (default '1100').",
+				},
+				"vendor_code": map[string]any{
+					"type":        "string",
+					"description": "Limit the run to one vendor (optional).",
 				},
 			},
-			"required": []string{"po_id", "lines"},
+			"required": []string{"due_by"},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
 
-	// Phase 14 vendor invoice + payment tools
+	// Purchase return tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_ap_balance",
-		Description: "Get the current Accounts Payable balance for the company and optionally for a specific vendor. Returns total outstanding AP and per-vendor breakdown.",
+		Name:        "get_purchase_returns",
+		Description: "List goods returned to vendors and the debit notes they raised: PO, vendor, return date, debit note amount and the open balance not yet offset against payments. Status OPEN means the debit note still has a balance; APPLIED means it has been fully offset. Pass return_id to get one return with its lines and offsets.",
 		IsReadTool:  true,
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
+				"status": map[string]any{
+					"type":        "string",
+					"description": "Filter by debit note status (optional). One of: OPEN, APPLIED.",
+				},
 				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "Optional: filter to a specific vendor code to see AP balance for that vendor only.",
+					"description": "Filter to one vendor (optional).",
+				},
+				"return_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of one purchase return to show with its lines (optional).",
 				},
 			},
 			"required": []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
+			status, _ := params["status"].(string)
 			vendorCode, _ := params["vendor_code"].(string)
-			return s.getAPBalanceJSON(hctx, companyCode, vendorCode)
+			returnID, _ := params["return_id"].(float64)
+			return s.getPurchaseReturnsJSON(hctx, companyCode, status, vendorCode, int(returnID))
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "get_vendor_payment_history",
-		Description: "Get payment history for a vendor: list of paid purchase orders with invoice numbers, amounts, and payment dates.",
-		IsReadTool:  true,
+		Name:        "create_purchase_return",
+		Description: "Return received goods on a base-currency purchase order to the vendor. Stock leaves the warehouse at the cost it was received at, AP is reduced, and a debit note is raised that is offset against the next payments to the vendor (pay_vendor or a payment run). Use get_purchase_orders first to find po_line_id values and received quantities. The user must confirm before goods are returned.",
+		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"vendor_code": map[string]any{
+				"po_id": map[string]any{
+					"type":        "integer",
+					"description": "Internal ID of the received purchase order.",
+				},
+				"warehouse_code": map[string]any{
 					"type":        "string",
-					"description": "The vendor code to retrieve payment history for (e.g. 'V001').",
+					"description": "Warehouse the goods are returned from (optional; defaults to the company's default warehouse).",
+				},
+				"return_date": map[string]any{
+					"type":        "string",
+					"description": "Return date in YYYY-MM-DD format (optional; defaults to today).",
+				},
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Reason for the return, e.g. 'damaged in transit' (optional).",
+				},
+				"lines": map[string]any{
+					"type":        "array",
+					"description": "PO lines being returned.",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"po_line_id": map[string]any{"type": "integer", "description": "PO line ID."},
+							"quantity":   map[string]any{"type": "number", "description": "Quantity returned, in the PO line's unit."},
+						},
+						"required": []string{"po_line_id", "quantity"},
+					},
 				},
 			},
-			"required": []string{"vendor_code"},
+			"required": []string{"po_id", "lines"},
+		},
+		Handler: nil, // write tool — no autonomous execution
+	})
+
+	// Replenishment tools
+	registry.Register(ai.ToolDefinition{
+		Name:        "get_replenishment_suggestions",
+		Description: "List products whose projected stock (on hand - reserved + open PO quantity not yet received) is at or below the reorder point. Returns suggested order quantity, unit cost, vendor and expected arrival date per product/warehouse.",
+		IsReadTool:  true,
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]any{},
+			"required":             []string{},
 		},
 		Handler: func(hctx context.Context, params map[string]any) (string, error) {
-			vendorCode, _ := params["vendor_code"].(string)
-			return s.getVendorPaymentHistoryJSON(hctx, companyCode, vendorCode)
+			return s.getReplenishmentSuggestionsJSON(hctx, companyCode)
 		},
 	})
 
 	registry.Register(ai.ToolDefinition{
-		Name:        "record_vendor_invoice",
-		Description: "Propose recording a vendor invoice against a RECEIVED purchase order. Creates a PI document number and transitions PO to INVOICED. The user must confirm before the action is executed.",
+		Name:        "create_replenishment_pos",
+		Description: "Propose raising DRAFT purchase orders for everything below its reorder point, grouped into one PO per vendor. Products without a preferred or previous vendor are skipped. The user must confirm before the POs are created.",
 		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"po_id": map[string]any{
-					"type":        "integer",
-					"description": "Internal ID of the RECEIVED purchase order to invoice.",
-				},
-				"invoice_number": map[string]any{
-					"type":        "string",
-					"description": "Vendor's invoice number as printed on the bill.",
+				"product_codes": map[string]any{
+					"type":        "array",
+					"description": "Optional: restrict to these product codes. Omit to replenish every product below its reorder point.",
+					"items":       map[string]any{"type": "string"},
 				},
-				"invoice_date": map[string]any{
+				"po_date": map[string]any{
 					"type":        "string",
-					"description": "Invoice date in YYYY-MM-DD format.",
-				},
-				"invoice_amount": map[string]any{
-					"type":        "number",
-					"description": "Total invoice amount in base currency. If it differs from the PO total by more than 5%, a warning is produced.",
+					"description": "PO date in YYYY-MM-DD format (optional; defaults to today).",
 				},
 			},
-			"required": []string{"po_id", "invoice_number", "invoice_date", "invoice_amount"},
+			"required": []string{},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
 
+	// Landed cost tools
 	registry.Register(ai.ToolDefinition{
-		Name:        "pay_vendor",
-		Description: "Propose paying a vendor for an INVOICED purchase order. Posts DR AP / CR Bank and transitions PO to PAID. The user must confirm before the payment is posted.",
+		Name:        "create_landed_cost_voucher",
+		Description: "Propose a landed cost voucher: freight, customs duty, insurance or similar charges for an import shipment, allocated over the goods receipts of one or more purchase orders. The share of stock still on hand raises its inventory unit cost; the share already shipped is expensed to COGS. The user must confirm before it is posted.",
 		IsReadTool:  false, // write tool — requires human confirmation
 		InputSchema: map[string]any{
 			"type":                 "object",
 			"additionalProperties": false,
 			"properties": map[string]any{
-				"po_id": map[string]any{
-					"type":        "integer",
-					"description": "Internal ID of the INVOICED purchase order to pay.",
+				"po_numbers": map[string]any{
+					"type":        "array",
+					"description": "PO numbers whose goods receipts carry the cost (e.g. ['PO-2026-00003']).",
+					"items":       map[string]any{"type": "string"},
 				},
-				"bank_account_code": map[string]any{
+				"charges": map[string]any{
+					"type":        "array",
+					"description": "Cost components on the voucher.",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]any{
+							"description": map[string]any{"type": "string", "description": "e.g. 'Sea freight', 'Customs duty'."},
+							"amount":      map[string]any{"type": "number", "description": "Charge amount in base currency."},
+						},
+						"required": []string{"description", "amount"},
+					},
+				},
+				"allocation_method": map[string]any{
 					"type":        "string",
-					"description": "Account code of the bank account to pay from (e.g. '1100').",
+					"enum":        []string{"VALUE", "QUANTITY", "WEIGHT"},
+					"description": "How to spread the charges over receipts (default VALUE). WEIGHT needs product unit weights.",
 				},
-				"payment_date": map[string]any{
+				"vendor_code": map[string]any{
 					"type":        "string",
-					"description": "Payment date in YYYY-MM-DD format.",
+					"description": "Optional: freight forwarder / customs agent vendor code. Its AP account is credited.",
+				},
+				"credit_account_code": map[string]any{
+					"type":        "string",
+					"description": "Optional: account to credit. Defaults to the vendor's AP account, else 2000.",
+				},
+				"voucher_date": map[string]any{
+					"type":        "string",
+					"description": "Voucher date in YYYY-MM-DD format (optional; defaults to today).",
 				},
 			},
-			"required": []string{"po_id", "bank_account_code", "payment_date"},
+			"required": []string{"po_numbers", "charges"},
 		},
 		Handler: nil, // write tool — no autonomous execution
 	})
@@ -1210,8 +3927,13 @@ func (s *appService) RecordVendorInvoice(ctx context.Context, req VendorInvoiceR
 	if err != nil {
 		return nil, err
 	}
+	lines := make([]core.VendorInvoiceLineInput, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = core.VendorInvoiceLineInput{POLineID: l.POLineID, Quantity: l.Quantity, UnitPrice: l.UnitPrice}
+	}
 	warning, err := s.purchaseOrderService.RecordVendorInvoice(
-		ctx, company.ID, req.POID, req.InvoiceNumber, req.InvoiceDate, req.InvoiceAmount, s.docService,
+		ctx, company.ID, req.POID, req.InvoiceNumber, req.InvoiceDate, req.InvoiceAmount, lines,
+		s.ledger, s.docService,
 	)
 	if err != nil {
 		return nil, err
@@ -1227,6 +3949,53 @@ func (s *appService) RecordVendorInvoice(ctx context.Context, req VendorInvoiceR
 	return &VendorInvoiceResult{PurchaseOrder: po, PIDocumentNumber: piDocNum, Warning: warning}, nil
 }
 
+// ReleasePaymentBlock accepts the variances on a payment-blocked PO and clears the block.
+func (s *appService) ReleasePaymentBlock(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purchaseOrderService.ReleasePaymentBlock(ctx, company.ID, poID, s.ledger); err != nil {
+		return nil, err
+	}
+	po, err := s.purchaseOrderService.GetPO(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+}
+
+// GetMatchTolerance returns the company's three-way match tolerances.
+func (s *appService) GetMatchTolerance(ctx context.Context, companyCode string) (*MatchToleranceResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	tol, err := s.purchaseOrderService.GetMatchTolerance(ctx, company.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &MatchToleranceResult{Tolerance: tol}, nil
+}
+
+// SetMatchTolerance creates or replaces the company's three-way match tolerances.
+func (s *appService) SetMatchTolerance(ctx context.Context, req SetMatchToleranceRequest) (*MatchToleranceResult, error) {
+	company, err := s.fetchCompany(ctx, req.CompanyCode)
+	if err != nil {
+		return nil, err
+	}
+	tol, err := s.purchaseOrderService.SetMatchTolerance(ctx, company.ID, core.MatchTolerance{
+		PriceTolerancePct: req.PriceTolerancePct,
+		QtyTolerancePct:   req.QtyTolerancePct,
+		AmountTolerance:   req.AmountTolerance,
+		VarianceTreatment: req.VarianceTreatment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MatchToleranceResult{Tolerance: tol}, nil
+}
+
 // PayVendor records payment against an INVOICED PO.
 func (s *appService) PayVendor(ctx context.Context, req PayVendorRequest) (*PaymentResult, error) {
 	if err := s.purchaseOrderService.PayVendor(
@@ -1275,19 +4044,100 @@ func (s *appService) ReceivePurchaseOrder(ctx context.Context, req ReceivePORequ
 		domainLines[i] = core.ReceivedLine{
 			POLineID:    l.POLineID,
 			QtyReceived: l.QtyReceived,
+			Lots:        toCoreLots(l.Lots),
+		}
+	}
+
+	if err := s.purchaseOrderService.ReceivePO(ctx, req.POID, warehouseCode, req.CompanyCode,
+		domainLines, apAccountCode, s.ledger, s.docService, s.inventoryService); err != nil {
+		return nil, err
+	}
+
+	po, err := s.purchaseOrderService.GetPO(ctx, req.POID)
+	if err != nil {
+		return nil, err
+	}
+	return &POReceiptResult{PurchaseOrder: po, LinesReceived: len(req.Lines)}, nil
+}
+
+// ShortClosePurchaseOrder closes a PARTIALLY_RECEIVED PO, cancelling the undelivered balance.
+func (s *appService) ShortClosePurchaseOrder(ctx context.Context, companyCode string, poID int, reason, closedBy string) (*PurchaseOrderResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purchaseOrderService.ShortClosePO(ctx, company.ID, poID, reason, closedBy); err != nil {
+		return nil, err
+	}
+	po, err := s.purchaseOrderService.GetPO(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+}
+
+// AmendPurchaseOrder changes an APPROVED PO's lines and/or expected delivery date.
+func (s *appService) AmendPurchaseOrder(ctx context.Context, req AmendPurchaseOrderRequest) (*PurchaseOrderResult, error) {
+	company, err := s.fetchCompany(ctx, req.CompanyCode)
+	if err != nil {
+		return nil, err
+	}
+	amendment := core.PurchaseOrderAmendment{
+		ExpectedDeliveryDate: req.ExpectedDeliveryDate,
+		Reason:               req.Reason,
+		RequireReapproval:    req.RequireReapproval,
+		AmendedBy:            req.AmendedBy,
+	}
+	if req.Lines != nil {
+		amendment.Lines = make([]core.PurchaseOrderAmendLine, len(req.Lines))
+		for i, l := range req.Lines {
+			amendment.Lines[i] = core.PurchaseOrderAmendLine{
+				POLineID:           l.POLineID,
+				ProductCode:        l.ProductCode,
+				Description:        l.Description,
+				Quantity:           l.Quantity,
+				Unit:               l.Unit,
+				UnitCost:           l.UnitCost,
+				ExpenseAccountCode: l.ExpenseAccountCode,
+				TaxCode:            l.TaxCode,
+				Dimensions:         l.Dimensions,
+			}
 		}
 	}
+	po, err := s.purchaseOrderService.AmendPO(ctx, company.ID, req.POID, amendment)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+}
+
+// CancelPurchaseOrder cancels a DRAFT or APPROVED PO on which nothing has been received.
+func (s *appService) CancelPurchaseOrder(ctx context.Context, companyCode string, poID int, reason, cancelledBy string) (*PurchaseOrderResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purchaseOrderService.CancelPO(ctx, company.ID, poID, reason, cancelledBy); err != nil {
+		return nil, err
+	}
+	po, err := s.purchaseOrderService.GetPO(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseOrderResult{PurchaseOrder: po}, nil
+}
 
-	if err := s.purchaseOrderService.ReceivePO(ctx, req.POID, warehouseCode, req.CompanyCode,
-		domainLines, apAccountCode, s.ledger, s.docService, s.inventoryService); err != nil {
+// GetPurchaseOrderRevisions returns a PO's revision history, oldest first.
+func (s *appService) GetPurchaseOrderRevisions(ctx context.Context, companyCode string, poID int) (*PORevisionsResult, error) {
+	company, err := s.fetchCompany(ctx, companyCode)
+	if err != nil {
 		return nil, err
 	}
-
-	po, err := s.purchaseOrderService.GetPO(ctx, req.POID)
+	revisions, err := s.purchaseOrderService.GetPORevisions(ctx, company.ID, poID)
 	if err != nil {
 		return nil, err
 	}
-	return &POReceiptResult{PurchaseOrder: po, LinesReceived: len(req.Lines)}, nil
+	return &PORevisionsResult{POID: poID, Revisions: revisions}, nil
 }
 
 // checkStockAvailabilityJSON returns current stock levels, optionally scoped to a PO's products.
@@ -1312,6 +4162,7 @@ func (s *appService) checkStockAvailabilityJSON(ctx context.Context, companyCode
 				"line_number": l.LineNumber,
 				"description": l.Description,
 				"quantity":    l.Quantity.String(),
+				"unit":        l.Unit,
 				"unit_cost":   l.UnitCost.String(),
 			}
 			if l.ProductCode != nil {
@@ -1357,31 +4208,50 @@ func (s *appService) checkStockAvailabilityJSON(ctx context.Context, companyCode
 
 // getAPBalanceJSON returns outstanding AP balance, optionally filtered by vendor.
 func (s *appService) getAPBalanceJSON(ctx context.Context, companyCode, vendorCode string) (string, error) {
-	// AP balance = sum of POs in INVOICED status (not yet PAID)
+	// AP balance = POs in INVOICED status (not yet PAID) plus POSTED vendor bills,
+	// less the open balance of vendor debit notes, in base currency
 	query := `
-		SELECT COALESCE(SUM(
-			CASE WHEN po.invoice_amount IS NOT NULL THEN po.invoice_amount ELSE po.total_base END
-		), 0),
-		COUNT(*)
-		FROM purchase_orders po
-		JOIN vendors v ON v.id = po.vendor_id
-		JOIN companies c ON c.id = po.company_id
-		WHERE c.company_code = $1 AND po.status = 'INVOICED'`
+		SELECT COALESCE(SUM(open_ap.amount), 0),
+		       COUNT(*) FILTER (WHERE open_ap.source = 'PO'),
+		       COUNT(*) FILTER (WHERE open_ap.source = 'BILL'),
+		       COUNT(*) FILTER (WHERE open_ap.source = 'DEBIT_NOTE')
+		FROM (
+		    SELECT 'PO' AS source, v.code AS vendor_code,
+		           COALESCE(po.invoice_amount, po.total_transaction) * po.exchange_rate AS amount
+		    FROM purchase_orders po
+		    JOIN vendors v ON v.id = po.vendor_id
+		    JOIN companies c ON c.id = po.company_id
+		    WHERE c.company_code = $1 AND po.status = 'INVOICED'
+		    UNION ALL
+		    SELECT 'BILL', v.code, vb.total_amount
+		    FROM vendor_bills vb
+		    JOIN vendors v ON v.id = vb.vendor_id
+		    JOIN companies c ON c.id = vb.company_id
+		    WHERE c.company_code = $1 AND vb.status = 'POSTED'
+		    UNION ALL
+		    SELECT 'DEBIT_NOTE', v.code, -(pr.amount - pr.applied_amount)
+		    FROM purchase_returns pr
+		    JOIN vendors v ON v.id = pr.vendor_id
+		    JOIN companies c ON c.id = pr.company_id
+		    WHERE c.company_code = $1 AND pr.status = 'OPEN'
+		) open_ap`
 	args := []any{companyCode}
 	if vendorCode != "" {
-		query += " AND v.code = $2"
+		query += " WHERE open_ap.vendor_code = $2"
 		args = append(args, vendorCode)
 	}
 
 	var totalAP decimal.Decimal
-	var count int
-	if err := s.pool.QueryRow(ctx, query, args...).Scan(&totalAP, &count); err != nil {
+	var poCount, billCount, debitNoteCount int
+	if err := s.pool.QueryRow(ctx, query, args...).Scan(&totalAP, &poCount, &billCount, &debitNoteCount); err != nil {
 		return "", fmt.Errorf("get AP balance: %w", err)
 	}
 
 	result := map[string]any{
-		"total_ap_outstanding": totalAP.StringFixed(2),
-		"invoiced_po_count":    count,
+		"total_ap_outstanding":  totalAP.StringFixed(2),
+		"invoiced_po_count":     poCount,
+		"open_bill_count":       billCount,
+		"open_debit_note_count": debitNoteCount,
 	}
 	if vendorCode != "" {
 		result["vendor_code"] = vendorCode
@@ -1393,13 +4263,20 @@ func (s *appService) getAPBalanceJSON(ctx context.Context, companyCode, vendorCo
 // getVendorPaymentHistoryJSON returns payment history for a vendor.
 func (s *appService) getVendorPaymentHistoryJSON(ctx context.Context, companyCode, vendorCode string) (string, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT po.id, po.po_number, po.invoice_number, po.invoice_date::text,
+		SELECT 'PO' AS source, po.id, po.po_number, po.invoice_number, po.invoice_date::text,
 		       po.invoice_amount, po.total_base, po.paid_at, po.pi_document_number
 		FROM purchase_orders po
 		JOIN vendors v ON v.id = po.vendor_id
 		JOIN companies c ON c.id = po.company_id
 		WHERE c.company_code = $1 AND v.code = $2 AND po.status = 'PAID'
-		ORDER BY po.paid_at DESC`,
+		UNION ALL
+		SELECT 'BILL', vb.id, NULL, vb.bill_number, vb.bill_date::text,
+		       vb.total_amount, NULL, vb.paid_at, vb.pi_document_number
+		FROM vendor_bills vb
+		JOIN vendors v ON v.id = vb.vendor_id
+		JOIN companies c ON c.id = vb.company_id
+		WHERE c.company_code = $1 AND v.code = $2 AND vb.status = 'PAID'
+		ORDER BY paid_at DESC`,
 		companyCode, vendorCode,
 	)
 	if err != nil {
@@ -1408,12 +4285,14 @@ func (s *appService) getVendorPaymentHistoryJSON(ctx context.Context, companyCod
 	defer rows.Close()
 
 	type paymentRecord struct {
-		POID             int     `json:"po_id"`
-		PONumber         *string `json:"po_number"`
+		Source           string  `json:"source"` // PO | BILL
+		POID             int     `json:"po_id,omitempty"`
+		BillID           int     `json:"bill_id,omitempty"`
+		PONumber         *string `json:"po_number,omitempty"`
 		InvoiceNumber    *string `json:"invoice_number"`
 		InvoiceDate      *string `json:"invoice_date"`
 		InvoiceAmount    *string `json:"invoice_amount"`
-		POTotal          string  `json:"po_total"`
+		POTotal          *string `json:"po_total,omitempty"`
 		PaidAt           *string `json:"paid_at"`
 		PIDocumentNumber *string `json:"pi_document_number"`
 	}
@@ -1421,16 +4300,24 @@ func (s *appService) getVendorPaymentHistoryJSON(ctx context.Context, companyCod
 	var payments []paymentRecord
 	for rows.Next() {
 		var pr paymentRecord
-		var totalBase decimal.Decimal
-		var invoiceAmount *decimal.Decimal
+		var id int
+		var totalBase, invoiceAmount *decimal.Decimal
 		var paidAt *time.Time
 		if err := rows.Scan(
-			&pr.POID, &pr.PONumber, &pr.InvoiceNumber, &pr.InvoiceDate,
+			&pr.Source, &id, &pr.PONumber, &pr.InvoiceNumber, &pr.InvoiceDate,
 			&invoiceAmount, &totalBase, &paidAt, &pr.PIDocumentNumber,
 		); err != nil {
 			return "", fmt.Errorf("scan payment record: %w", err)
 		}
-		pr.POTotal = totalBase.StringFixed(2)
+		if pr.Source == "BILL" {
+			pr.BillID = id
+		} else {
+			pr.POID = id
+		}
+		if totalBase != nil {
+			s := totalBase.StringFixed(2)
+			pr.POTotal = &s
+		}
 		if invoiceAmount != nil {
 			s := invoiceAmount.StringFixed(2)
 			pr.InvoiceAmount = &s
@@ -1446,21 +4333,24 @@ func (s *appService) getVendorPaymentHistoryJSON(ctx context.Context, companyCod
 	}
 
 	result := map[string]any{
-		"vendor_code":      vendorCode,
-		"payment_count":    len(payments),
-		"payment_history":  payments,
+		"vendor_code":     vendorCode,
+		"payment_count":   len(payments),
+		"payment_history": payments,
 	}
 	data, _ := json.Marshal(result)
 	return string(data), nil
 }
 
 // CommitProposal validates and posts an AI-generated proposal to the ledger.
+// The entry is manual, so control accounts are refused.
 func (s *appService) CommitProposal(ctx context.Context, proposal core.Proposal) error {
+	proposal.Manual = true
 	return s.ledger.Commit(ctx, proposal)
 }
 
 // ValidateProposal validates a proposal without committing it.
 func (s *appService) ValidateProposal(ctx context.Context, proposal core.Proposal) error {
+	proposal.Manual = true
 	return s.ledger.Validate(ctx, proposal)
 }
 
@@ -1516,12 +4406,22 @@ func (s *appService) fetchCompany(ctx context.Context, companyCode string) (*cor
 }
 
 // fetchCoA returns the chart of accounts for a company as a formatted string for the AI prompt.
+// Only accounts a manual entry may post to are listed: groups, inactive and blocked accounts
+// and control accounts (AR, AP, inventory) are left out.
 func (s *appService) fetchCoA(ctx context.Context, companyCode string) (string, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT a.code, a.name, a.type
+		SELECT a.code, a.name, a.type, a.narration_required,
+		       COALESCE((SELECT string_agg(d.code, ', ' ORDER BY d.code)
+		                 FROM account_dimension_rules r
+		                 JOIN dimensions d ON d.id = r.dimension_id
+		                 WHERE r.account_id = a.id AND d.is_active), '')
 		FROM accounts a
 		JOIN companies c ON c.id = a.company_id
 		WHERE c.company_code = $1
+		  AND NOT a.is_group
+		  AND a.is_active
+		  AND NOT a.posting_blocked
+		  AND NOT a.manual_posting_blocked
 		ORDER BY a.code
 	`, companyCode)
 	if err != nil {
@@ -1531,11 +4431,48 @@ func (s *appService) fetchCoA(ctx context.Context, companyCode string) (string,
 
 	var lines []string
 	for rows.Next() {
-		var code, name, accType string
-		if err := rows.Scan(&code, &name, &accType); err != nil {
+		var code, name, accType, requiredDims string
+		var narrationRequired bool
+		if err := rows.Scan(&code, &name, &accType, &narrationRequired, &requiredDims); err != nil {
 			return "", err
 		}
-		lines = append(lines, fmt.Sprintf("- %s %s (%s)", code, name, accType))
+		line := fmt.Sprintf("- %s %s (%s)", code, name, accType)
+		if narrationRequired {
+			line += " [summary required]"
+		}
+		if requiredDims != "" {
+			line += " [requires " + requiredDims + "]"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// fetchDimensions returns the company's active analytic dimensions and their active values
+// as a formatted string for the AI prompt.
+func (s *appService) fetchDimensions(ctx context.Context, companyCode string) (string, error) {
+	dims, err := s.dimensionService.ListDimensions(ctx, companyCode)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, d := range dims {
+		if !d.IsActive {
+			continue
+		}
+		var values []string
+		for _, v := range d.Values {
+			if v.IsActive {
+				values = append(values, fmt.Sprintf("%s (%s)", v.Code, v.Name))
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s: %s", d.Code, d.Name, strings.Join(values, ", ")))
+	}
+	if len(lines) == 0 {
+		return "(none)", nil
 	}
 	return strings.Join(lines, "\n"), nil
 }
@@ -1604,6 +4541,7 @@ func (s *appService) searchCustomers(ctx context.Context, companyCode, query str
 		FROM customers cu
 		JOIN companies c ON c.id = cu.company_id
 		WHERE c.company_code = $1
+		  AND cu.is_active = true
 		  AND (cu.name ILIKE '%' || $2 || '%' OR cu.code ILIKE '%' || $2 || '%')
 		ORDER BY cu.code
 		LIMIT 10
@@ -1637,10 +4575,30 @@ func (s *appService) searchCustomers(ctx context.Context, companyCode, query str
 	return string(data), nil
 }
 
+// getCustomerInfoJSON returns full customer details by code as JSON.
+func (s *appService) getCustomerInfoJSON(ctx context.Context, companyCode, customerCode string) (string, error) {
+	c, err := s.orderService.GetCustomer(ctx, companyCode, customerCode)
+	if err != nil {
+		return fmt.Sprintf(`{"error":"customer %q not found"}`, customerCode), nil
+	}
+	data, _ := json.Marshal(map[string]any{
+		"code":               c.Code,
+		"name":               c.Name,
+		"email":              c.Email,
+		"phone":              c.Phone,
+		"address":            c.Address,
+		"credit_limit":       c.CreditLimit.StringFixed(2),
+		"payment_terms_days": c.PaymentTermsDays,
+		"is_active":          c.IsActive,
+		"version":            c.Version,
+	})
+	return string(data), nil
+}
+
 // searchProducts queries products by name or code using ILIKE and returns JSON.
 func (s *appService) searchProducts(ctx context.Context, companyCode, query string) (string, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT p.code, p.name, p.unit_price
+		SELECT p.code, p.name, p.unit_price, p.unit
 		FROM products p
 		JOIN companies c ON c.id = p.company_id
 		WHERE c.company_code = $1
@@ -1657,12 +4615,13 @@ func (s *appService) searchProducts(ctx context.Context, companyCode, query stri
 		Code      string `json:"code"`
 		Name      string `json:"name"`
 		UnitPrice string `json:"unit_price"`
+		Unit      string `json:"unit"`
 	}
 	var results []row
 	for rows.Next() {
 		var r row
 		var unitPrice decimal.Decimal
-		if err := rows.Scan(&r.Code, &r.Name, &unitPrice); err != nil {
+		if err := rows.Scan(&r.Code, &r.Name, &unitPrice, &r.Unit); err != nil {
 			return "", err
 		}
 		r.UnitPrice = unitPrice.String()
@@ -1836,7 +4795,14 @@ func (s *appService) getVendorInfoJSON(ctx context.Context, companyCode, vendorC
 		PaymentTermsDays          int     `json:"payment_terms_days"`
 		APAccountCode             string  `json:"ap_account_code"`
 		DefaultExpenseAccountCode *string `json:"default_expense_account_code,omitempty"`
+		BankAccountName           *string `json:"bank_account_name,omitempty"`
+		BankAccountNumber         *string `json:"bank_account_number,omitempty"`
+		BankCode                  *string `json:"bank_code,omitempty"`
+		Currency                  *string `json:"currency,omitempty"`
+		PAN                       *string `json:"pan,omitempty"`
+		TDSSectionCode            *string `json:"tds_section_code,omitempty"`
 		IsActive                  bool    `json:"is_active"`
+		Version                   int     `json:"version"`
 	}
 	data, _ := json.Marshal(out{
 		Code:                      v.Code,
@@ -1848,7 +4814,14 @@ func (s *appService) getVendorInfoJSON(ctx context.Context, companyCode, vendorC
 		PaymentTermsDays:          v.PaymentTermsDays,
 		APAccountCode:             v.APAccountCode,
 		DefaultExpenseAccountCode: v.DefaultExpenseAccountCode,
+		BankAccountName:           v.BankAccountName,
+		BankAccountNumber:         v.BankAccountNumber,
+		BankCode:                  v.BankCode,
+		Currency:                  v.Currency,
+		PAN:                       v.PAN,
+		TDSSectionCode:            v.TDSSectionCode,
 		IsActive:                  v.IsActive,
+		Version:                   v.Version,
 	})
 	return string(data), nil
 }
@@ -1872,6 +4845,9 @@ func vendorsToJSON(vendors []core.Vendor) []map[string]any {
 		if v.ContactPerson != nil {
 			m["contact_person"] = *v.ContactPerson
 		}
+		if v.Currency != nil {
+			m["currency"] = *v.Currency
+		}
 		out[i] = m
 	}
 	return out
@@ -1894,7 +4870,37 @@ func (s *appService) getPurchaseOrdersJSON(ctx context.Context, companyCode, sta
 	return string(data), nil
 }
 
-// getOpenPOsJSON returns DRAFT and APPROVED purchase orders for the company as JSON.
+// getPORevisionsJSON returns a purchase order's revision history as JSON.
+func (s *appService) getPORevisionsJSON(ctx context.Context, companyCode string, poID int) (string, error) {
+	result, err := s.GetPurchaseOrderRevisions(ctx, companyCode, poID)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Revisions) == 0 {
+		return fmt.Sprintf(`{"po_id":%d,"revisions":[],"note":"No revision history found."}`, poID), nil
+	}
+	revisions := make([]map[string]any, len(result.Revisions))
+	for i, r := range result.Revisions {
+		m := map[string]any{
+			"revision":   r.Revision,
+			"action":     r.Action,
+			"changes":    r.Changes,
+			"changed_at": r.ChangedAt.Format("2006-01-02 15:04"),
+		}
+		if r.Reason != nil {
+			m["reason"] = *r.Reason
+		}
+		if r.ChangedBy != nil {
+			m["changed_by"] = *r.ChangedBy
+		}
+		revisions[i] = m
+	}
+	data, _ := json.Marshal(map[string]any{"po_id": poID, "revisions": revisions})
+	return string(data), nil
+}
+
+// getOpenPOsJSON returns DRAFT, APPROVED and PARTIALLY_RECEIVED purchase orders for the
+// company as JSON, with per-line outstanding quantities.
 func (s *appService) getOpenPOsJSON(ctx context.Context, companyCode string) (string, error) {
 	company, err := s.fetchCompany(ctx, companyCode)
 	if err != nil {
@@ -1902,12 +4908,18 @@ func (s *appService) getOpenPOsJSON(ctx context.Context, companyCode string) (st
 	}
 
 	var allOpen []core.PurchaseOrder
-	for _, st := range []string{"DRAFT", "APPROVED"} {
+	for _, st := range []string{"DRAFT", "APPROVED", "PARTIALLY_RECEIVED"} {
 		orders, err := s.purchaseOrderService.GetPOs(ctx, company.ID, st)
 		if err != nil {
 			return "", err
 		}
-		allOpen = append(allOpen, orders...)
+		for _, o := range orders {
+			po, err := s.purchaseOrderService.GetPO(ctx, o.ID)
+			if err != nil {
+				return "", err
+			}
+			allOpen = append(allOpen, *po)
+		}
 	}
 
 	if len(allOpen) == 0 {
@@ -1917,6 +4929,648 @@ func (s *appService) getOpenPOsJSON(ctx context.Context, companyCode string) (st
 	return string(data), nil
 }
 
+// getVendorBillsJSON returns vendor bills for the company as JSON, optionally filtered by status.
+func (s *appService) getVendorBillsJSON(ctx context.Context, companyCode, status string) (string, error) {
+	bills, err := s.vendorBillService.GetBills(ctx, companyCode, status)
+	if err != nil {
+		return "", err
+	}
+	if len(bills) == 0 {
+		return `{"vendor_bills":[],"note":"No vendor bills found."}`, nil
+	}
+	out := make([]map[string]any, len(bills))
+	for i, b := range bills {
+		m := map[string]any{
+			"id":           b.ID,
+			"vendor_code":  b.VendorCode,
+			"vendor_name":  b.VendorName,
+			"bill_number":  b.BillNumber,
+			"bill_date":    b.BillDate,
+			"due_date":     b.DueDate,
+			"status":       b.Status,
+			"total_amount": b.TotalAmount.StringFixed(2),
+		}
+		if b.PIDocumentNumber != nil {
+			m["pi_document_number"] = *b.PIDocumentNumber
+		}
+		out[i] = m
+	}
+	data, _ := json.Marshal(map[string]any{"vendor_bills": out})
+	return string(data), nil
+}
+
+// getPaymentRunsJSON returns payment run headers, or one run with its items when runID is set.
+func (s *appService) getPaymentRunsJSON(ctx context.Context, companyCode, status string, runID int) (string, error) {
+	if runID != 0 {
+		run, err := s.paymentRunService.GetRun(ctx, companyCode, runID)
+		if err != nil {
+			return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+		}
+		m := paymentRunToJSON(*run)
+		items := make([]map[string]any, len(run.Items))
+		for i, it := range run.Items {
+			item := map[string]any{
+				"id":                 it.ID,
+				"vendor_code":        it.VendorCode,
+				"vendor_name":        it.VendorName,
+				"source":             it.Source,
+				"document_reference": it.DocumentReference,
+				"due_date":           it.DueDate,
+				"currency":           it.Currency,
+				"amount":             it.Amount.StringFixed(2),
+				"excluded":           it.Excluded,
+			}
+			if it.JournalDocumentNumber != nil {
+				item["payment_document_number"] = *it.JournalDocumentNumber
+			}
+			items[i] = item
+		}
+		m["items"] = items
+		data, _ := json.Marshal(m)
+		return string(data), nil
+	}
+
+	runs, err := s.paymentRunService.GetRuns(ctx, companyCode, status)
+	if err != nil {
+		return "", err
+	}
+	if len(runs) == 0 {
+		return `{"payment_runs":[],"note":"No payment runs found."}`, nil
+	}
+	out := make([]map[string]any, len(runs))
+	for i, r := range runs {
+		out[i] = paymentRunToJSON(r)
+	}
+	data, _ := json.Marshal(map[string]any{"payment_runs": out})
+	return string(data), nil
+}
+
+// getTDSSectionsJSON returns the active TDS sections as JSON.
+func (s *appService) getTDSSectionsJSON(ctx context.Context) (string, error) {
+	sections, err := s.tdsService.GetSections(ctx)
+	if err != nil {
+		return "", err
+	}
+	out := make([]map[string]any, len(sections))
+	for i, sec := range sections {
+		out[i] = map[string]any{
+			"code":                     sec.Code,
+			"section":                  sec.Section,
+			"description":              sec.Description,
+			"rate_pct":                 sec.Rate.String(),
+			"no_pan_rate_pct":          sec.NoPANRate.String(),
+			"single_payment_threshold": sec.SinglePaymentThreshold.StringFixed(2),
+			"annual_threshold":         sec.AnnualThreshold.StringFixed(2),
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"tds_sections": out})
+	return string(data), nil
+}
+
+// getTaxCodesJSON returns the company's GST codes and rates as JSON.
+// getGSTReturnJSON summarises the month's GST returns as JSON.
+func (s *appService) getGSTReturnJSON(ctx context.Context, companyCode, period string) (string, error) {
+	ret, err := s.GetGSTReturn(ctx, companyCode, period)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	b2bInvoices := 0
+	for _, b := range ret.GSTR1.B2B {
+		b2bInvoices += len(b.Invoices)
+	}
+	reconciliation := make([]map[string]any, len(ret.Reconciliation))
+	for i, l := range ret.Reconciliation {
+		reconciliation[i] = map[string]any{
+			"direction":  l.Direction,
+			"component":  l.Component,
+			"account":    l.AccountCode,
+			"return_tax": l.ReturnTax.StringFixed(2),
+			"ledger_tax": l.LedgerTax.StringFixed(2),
+			"difference": l.Difference.StringFixed(2),
+		}
+	}
+	out := ret.GSTR3B.SupDetails.Taxable
+	itc := ret.GSTR3B.ITCElg.Net
+	data, _ := json.Marshal(map[string]any{
+		"gstin":          ret.GSTIN,
+		"period":         ret.Period,
+		"from":           ret.FromDate,
+		"to":             ret.ToDate,
+		"b2b_recipients": len(ret.GSTR1.B2B),
+		"b2b_invoices":   b2bInvoices,
+		"b2cs_rows":      len(ret.GSTR1.B2CS),
+		"hsn_rows":       len(ret.GSTR1.HSN.Data),
+		"outward": map[string]any{
+			"taxable": out.TaxableValue, "igst": out.IGST, "cgst": out.CGST, "sgst": out.SGST,
+			"nil_rated": ret.GSTR3B.SupDetails.NilRated.TaxableValue,
+		},
+		"input_tax_credit": map[string]any{"igst": itc.IGST, "cgst": itc.CGST, "sgst": itc.SGST},
+		"reconciliation":   reconciliation,
+		"reconciled":       ret.Reconciled,
+		"issues":           ret.Issues,
+	})
+	return string(data), nil
+}
+
+func (s *appService) getComparativeReportJSON(ctx context.Context, req ColumnarReportRequest, statement string) (string, error) {
+	line := func(l core.ColumnarLine) map[string]any {
+		out := map[string]any{"name": l.Name}
+		if l.Code != "" {
+			out["code"] = l.Code
+		}
+		amounts := make([]string, len(l.Amounts))
+		for i, a := range l.Amounts {
+			amounts[i] = a.StringFixed(2)
+		}
+		out["amounts"] = amounts
+		if l.Variances != nil {
+			variances := make([]string, len(l.Variances))
+			pcts := make([]any, len(l.VariancePcts))
+			for i, v := range l.Variances {
+				variances[i] = v.StringFixed(2)
+				if l.VariancePcts[i] != nil {
+					pcts[i] = l.VariancePcts[i].StringFixed(2)
+				}
+			}
+			out["variances"] = variances
+			out["variance_pcts"] = pcts
+		}
+		return out
+	}
+	section := func(sec core.ColumnarSection) map[string]any {
+		var lines []map[string]any
+		for _, l := range sec.Lines {
+			if !allZero(l.Amounts) {
+				lines = append(lines, line(l))
+			}
+		}
+		return map[string]any{"lines": lines, "total": line(sec.Total)}
+	}
+	columns := func(ps []core.ReportPeriod) []map[string]string {
+		out := make([]map[string]string, len(ps))
+		for i, p := range ps {
+			out[i] = map[string]string{"label": p.Label, "from": p.FromDate, "to": p.ToDate}
+		}
+		return out
+	}
+
+	var result map[string]any
+	if strings.EqualFold(statement, "BALANCE_SHEET") {
+		report, err := s.GetColumnarBalanceSheet(ctx, req)
+		if err != nil {
+			return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+		}
+		result = map[string]any{
+			"columns":     columns(report.Columns),
+			"assets":      section(report.Assets),
+			"liabilities": section(report.Liabilities),
+			"equity":      section(report.Equity),
+			"is_balanced": report.IsBalanced,
+		}
+	} else {
+		report, err := s.GetColumnarProfitAndLoss(ctx, req)
+		if err != nil {
+			return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+		}
+		result = map[string]any{
+			"columns":    columns(report.Columns),
+			"revenue":    section(report.Revenue),
+			"expenses":   section(report.Expenses),
+			"net_income": line(report.NetIncome),
+		}
+	}
+	data, _ := json.Marshal(result)
+	return string(data), nil
+}
+
+// allZero reports whether every amount is zero.
+func allZero(amounts []decimal.Decimal) bool {
+	for _, a := range amounts {
+		if !a.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *appService) getBudgetVsActualJSON(ctx context.Context, req BudgetVsActualRequest) (string, error) {
+	report, err := s.GetBudgetVsActual(ctx, req)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	line := func(l core.BudgetVsActualLine) map[string]any {
+		out := map[string]any{
+			"name":       l.AccountName,
+			"budget":     l.Budget.StringFixed(2),
+			"actual":     l.Actual.StringFixed(2),
+			"variance":   l.Variance.StringFixed(2),
+			"status":     l.Status,
+			"favourable": l.Favourable,
+		}
+		if l.AccountCode != "" {
+			out["code"] = l.AccountCode
+			out["type"] = l.AccountType
+		}
+		if l.VariancePct != nil {
+			out["variance_pct"] = l.VariancePct.StringFixed(2)
+		}
+		return out
+	}
+	lines := make([]map[string]any, len(report.Lines))
+	for i, l := range report.Lines {
+		lines[i] = line(l)
+	}
+	data, _ := json.Marshal(map[string]any{
+		"budget_code":    report.BudgetCode,
+		"budget_name":    report.BudgetName,
+		"financial_year": report.FinancialYear,
+		"period":         map[string]string{"label": report.Period.Label, "from": report.Period.FromDate, "to": report.Period.ToDate},
+		"threshold_pct":  report.ThresholdPct.StringFixed(2),
+		"lines":          lines,
+		"revenue":        line(report.Revenue),
+		"expenses":       line(report.Expenses),
+		"net_income":     line(report.NetIncome),
+	})
+	return string(data), nil
+}
+
+func (s *appService) getDimensionsJSON(ctx context.Context, companyCode string) (string, error) {
+	dims, err := s.ListDimensions(ctx, companyCode)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	rules, err := s.ListAccountDimensionRules(ctx, companyCode)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	dimensions := make([]map[string]any, 0, len(dims))
+	for _, d := range dims {
+		if !d.IsActive {
+			continue
+		}
+		values := make([]map[string]string, 0, len(d.Values))
+		for _, v := range d.Values {
+			if v.IsActive {
+				values = append(values, map[string]string{"code": v.Code, "name": v.Name})
+			}
+		}
+		dimensions = append(dimensions, map[string]any{"code": d.Code, "name": d.Name, "values": values})
+	}
+	required := make(map[string][]string)
+	for _, r := range rules {
+		required[r.AccountCode] = append(required[r.AccountCode], r.DimensionCode)
+	}
+	data, _ := json.Marshal(map[string]any{
+		"dimensions":                     dimensions,
+		"required_dimensions_by_account": required,
+	})
+	return string(data), nil
+}
+
+func (s *appService) getDimensionPLJSON(ctx context.Context, req DimensionReportRequest) (string, error) {
+	report, err := s.GetDimensionProfitAndLoss(ctx, req)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	line := func(l core.DimensionReportLine) map[string]any {
+		out := map[string]any{"account": strings.TrimSpace(l.AccountCode + " " + l.AccountName), "total": l.Total.StringFixed(2)}
+		if len(report.Columns) > 0 {
+			amounts := make(map[string]string, len(report.Columns))
+			for i, c := range report.Columns {
+				key := c.Code
+				if key == "" {
+					key = c.Name
+				}
+				amounts[key] = l.Amounts[i].StringFixed(2)
+			}
+			out["by_"+strings.ToLower(report.Breakdown)] = amounts
+		}
+		return out
+	}
+	lines := func(ls []core.DimensionReportLine) []map[string]any {
+		out := make([]map[string]any, len(ls))
+		for i, l := range ls {
+			out[i] = line(l)
+		}
+		return out
+	}
+	data, _ := json.Marshal(map[string]any{
+		"period":         map[string]string{"label": report.Period.Label, "from": report.Period.FromDate, "to": report.Period.ToDate},
+		"filter":         report.Filter,
+		"breakdown":      report.Breakdown,
+		"revenue":        lines(report.Revenue),
+		"expenses":       lines(report.Expenses),
+		"total_revenue":  line(report.TotalRevenue),
+		"total_expenses": line(report.TotalExpenses),
+		"net_income":     line(report.NetIncome),
+	})
+	return string(data), nil
+}
+
+func (s *appService) getCashFlowJSON(ctx context.Context, companyCode, fromDate, toDate, method string) (string, error) {
+	report, err := s.GetCashFlow(ctx, companyCode, fromDate, toDate, method)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	lines := func(ls []core.CashFlowLine) []map[string]any {
+		out := make([]map[string]any, len(ls))
+		for i, l := range ls {
+			out[i] = map[string]any{"line": l.Name, "accounts": l.Accounts, "amount": l.Amount.StringFixed(2)}
+			if report.Method == core.CashFlowMethodDirect {
+				out[i]["inflows"] = l.Inflows.StringFixed(2)
+				out[i]["outflows"] = l.Outflows.StringFixed(2)
+			}
+		}
+		return out
+	}
+	result := map[string]any{
+		"from":         report.FromDate,
+		"to":           report.ToDate,
+		"method":       report.Method,
+		"operating":    map[string]any{"lines": lines(report.Operating.Lines), "total": report.Operating.Total.StringFixed(2)},
+		"investing":    map[string]any{"lines": lines(report.Investing.Lines), "total": report.Investing.Total.StringFixed(2)},
+		"financing":    map[string]any{"lines": lines(report.Financing.Lines), "total": report.Financing.Total.StringFixed(2)},
+		"net_change":   report.NetChange.StringFixed(2),
+		"opening_cash": report.OpeningCash.StringFixed(2),
+		"closing_cash": report.ClosingCash.StringFixed(2),
+		"reconciled":   report.IsReconciled,
+	}
+	if report.Method == core.CashFlowMethodIndirect {
+		result["net_income"] = report.NetIncome.StringFixed(2)
+		result["non_cash_adjustments"] = lines(report.Adjustments)
+	}
+	data, _ := json.Marshal(result)
+	return string(data), nil
+}
+
+func (s *appService) getEInvoiceJSON(ctx context.Context, companyCode, ref string) (string, error) {
+	ei, err := s.GetEInvoice(ctx, ref, companyCode)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	result := map[string]any{
+		"order_number":   ei.OrderNumber,
+		"invoice_number": ei.InvoiceNumber,
+		"irn":            ei.IRN,
+		"ack_number":     ei.AckNumber,
+		"ack_date":       ei.AckDate.Format(time.RFC3339),
+		"status":         ei.Status,
+		"buyer_gstin":    ei.Payload.BuyerDtls.Gstin,
+		"invoice_value":  ei.Payload.ValDtls.TotInvVal,
+	}
+	if ei.CancelledAt != nil {
+		result["cancelled_at"] = ei.CancelledAt.Format(time.RFC3339)
+		result["cancel_reason_code"] = ei.CancelReasonCode
+		result["cancel_remark"] = ei.CancelRemark
+	}
+	data, _ := json.Marshal(result)
+	return string(data), nil
+}
+
+func (s *appService) getTaxCodesJSON(ctx context.Context, companyCode, asOfDate string) (string, error) {
+	result, err := s.ListTaxCodes(ctx, companyCode, asOfDate)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	out := make([]map[string]any, len(result.TaxCodes))
+	for i, tc := range result.TaxCodes {
+		rates := map[string]string{}
+		for _, r := range tc.Rates {
+			rates[strings.ToLower(r.Component)+"_pct"] = r.Rate.String()
+		}
+		out[i] = map[string]any{
+			"code":        tc.Code,
+			"description": tc.Description,
+			"rates":       rates,
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"as_of": result.AsOf, "tax_codes": out})
+	return string(data), nil
+}
+
+// getTDSStatusJSON returns a vendor's fiscal-year TDS position as JSON.
+func (s *appService) getTDSStatusJSON(ctx context.Context, companyCode, vendorCode, asOfDate string) (string, error) {
+	st, err := s.GetVendorTDSStatus(ctx, companyCode, vendorCode, asOfDate)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	m := map[string]any{
+		"vendor_code":              st.VendorCode,
+		"vendor_name":              st.VendorName,
+		"section_code":             st.SectionCode,
+		"financial_year":           st.FinancialYear,
+		"rate_pct":                 st.Rate.String(),
+		"single_payment_threshold": st.SinglePaymentThreshold.StringFixed(2),
+		"annual_threshold":         st.AnnualThreshold.StringFixed(2),
+		"cumulative_paid":          st.CumulativePaid.StringFixed(2),
+		"tds_deducted":             st.TDSDeducted.StringFixed(2),
+		"annual_threshold_crossed": st.AnnualThresholdCrossed,
+		"payments":                 st.Payments,
+	}
+	if st.PAN != nil {
+		m["pan"] = *st.PAN
+	} else {
+		m["note"] = "Vendor has no PAN on file; TDS is withheld at the no-PAN rate."
+	}
+	data, _ := json.Marshal(m)
+	return string(data), nil
+}
+
+// getTDSRegisterJSON returns the quarterly TDS register as JSON.
+func (s *appService) getTDSRegisterJSON(ctx context.Context, companyCode string, fyStartYear, quarter int) (string, error) {
+	reg, err := s.GetTDSRegister(ctx, companyCode, fyStartYear, quarter)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+	}
+	deductions := make([]map[string]any, len(reg.Deductions))
+	for i, d := range reg.Deductions {
+		m := map[string]any{
+			"vendor_code":    d.VendorCode,
+			"vendor_name":    d.VendorName,
+			"section_code":   d.SectionCode,
+			"payment_date":   d.PaymentDate,
+			"gross_amount":   d.GrossAmount.StringFixed(2),
+			"taxable_amount": d.TaxableAmount.StringFixed(2),
+			"rate_pct":       d.Rate.String(),
+			"tds_amount":     d.TDSAmount.StringFixed(2),
+		}
+		if d.PAN != nil {
+			m["pan"] = *d.PAN
+		}
+		if d.JournalDocumentNumber != nil {
+			m["payment_document_number"] = *d.JournalDocumentNumber
+		}
+		deductions[i] = m
+	}
+	sections := make([]map[string]any, len(reg.Sections))
+	for i, t := range reg.Sections {
+		sections[i] = map[string]any{
+			"section_code": t.SectionCode,
+			"section":      t.Section,
+			"deductions":   t.Deductions,
+			"gross_amount": t.GrossAmount.StringFixed(2),
+			"tds_amount":   t.TDSAmount.StringFixed(2),
+		}
+	}
+	data, _ := json.Marshal(map[string]any{
+		"financial_year": reg.FinancialYear,
+		"quarter":        reg.Quarter,
+		"from_date":      reg.FromDate,
+		"to_date":        reg.ToDate,
+		"deductions":     deductions,
+		"sections":       sections,
+		"total_gross":    reg.TotalGross.StringFixed(2),
+		"total_tds":      reg.TotalTDS.StringFixed(2),
+	})
+	return string(data), nil
+}
+
+// getPurchaseReturnsJSON returns purchase returns as JSON, or one return with its lines when returnID is set.
+func (s *appService) getPurchaseReturnsJSON(ctx context.Context, companyCode, status, vendorCode string, returnID int) (string, error) {
+	if returnID != 0 {
+		ret, err := s.purchaseReturnService.GetReturn(ctx, companyCode, returnID)
+		if err != nil {
+			return fmt.Sprintf(`{"error":%q}`, err.Error()), nil
+		}
+		m := purchaseReturnToJSON(*ret)
+		lines := make([]map[string]any, len(ret.Lines))
+		for i, l := range ret.Lines {
+			lines[i] = map[string]any{
+				"po_line_id":   l.POLineID,
+				"line_number":  l.LineNumber,
+				"product_code": l.ProductCode,
+				"description":  l.Description,
+				"quantity":     l.Quantity.String(),
+				"unit_cost":    l.UnitCost.String(),
+				"line_amount":  l.LineAmount.StringFixed(2),
+			}
+		}
+		m["lines"] = lines
+		offsets := make([]map[string]any, len(ret.Applications))
+		for i, a := range ret.Applications {
+			o := map[string]any{
+				"amount":     a.Amount.StringFixed(2),
+				"applied_at": a.AppliedAt.Format("2006-01-02"),
+			}
+			if a.POID != nil {
+				o["po_id"] = *a.POID
+			}
+			if a.PaymentRunID != nil {
+				o["payment_run_id"] = *a.PaymentRunID
+			}
+			offsets[i] = o
+		}
+		m["applications"] = offsets
+		data, _ := json.Marshal(m)
+		return string(data), nil
+	}
+
+	returns, err := s.purchaseReturnService.GetReturns(ctx, companyCode, status, vendorCode)
+	if err != nil {
+		return "", err
+	}
+	if len(returns) == 0 {
+		return `{"purchase_returns":[],"note":"No purchase returns found."}`, nil
+	}
+	out := make([]map[string]any, len(returns))
+	for i, r := range returns {
+		out[i] = purchaseReturnToJSON(r)
+	}
+	data, _ := json.Marshal(map[string]any{"purchase_returns": out})
+	return string(data), nil
+}
+
+// purchaseReturnToJSON converts a purchase return header to a JSON-friendly map.
+func purchaseReturnToJSON(r core.PurchaseReturn) map[string]any {
+	m := map[string]any{
+		"id":             r.ID,
+		"po_id":          r.POID,
+		"vendor_code":    r.VendorCode,
+		"vendor_name":    r.VendorName,
+		"warehouse_code": r.WarehouseCode,
+		"return_date":    r.ReturnDate,
+		"status":         r.Status,
+		"amount":         r.Amount.StringFixed(2),
+		"open_amount":    r.OpenAmount().StringFixed(2),
+	}
+	if r.PONumber != nil {
+		m["po_number"] = *r.PONumber
+	}
+	if r.Reason != nil {
+		m["reason"] = *r.Reason
+	}
+	if r.DebitNoteNumber != nil {
+		m["debit_note_number"] = *r.DebitNoteNumber
+	}
+	return m
+}
+
+// paymentRunToJSON converts a payment run header to a JSON-friendly map.
+func paymentRunToJSON(r core.PaymentRun) map[string]any {
+	m := map[string]any{
+		"id":                r.ID,
+		"status":            r.Status,
+		"due_by":            r.DueBy,
+		"payment_date":      r.PaymentDate,
+		"bank_account_code": r.BankAccountCode,
+		"total_amount":      r.TotalAmount.StringFixed(2),
+	}
+	if r.VendorCode != nil {
+		m["vendor_code"] = *r.VendorCode
+	}
+	if r.ApprovedBy != nil {
+		m["approved_by"] = *r.ApprovedBy
+	}
+	return m
+}
+
+// getProductUnitsJSON returns a product's stock unit, default line units and conversions as JSON.
+func (s *appService) getProductUnitsJSON(ctx context.Context, companyCode, productCode string) (string, error) {
+	units, err := s.uomService.GetProductUnits(ctx, companyCode, productCode)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(units)
+	return string(data), nil
+}
+
+// getReplenishmentSuggestionsJSON returns current replenishment suggestions as JSON.
+func (s *appService) getReplenishmentSuggestionsJSON(ctx context.Context, companyCode string) (string, error) {
+	result, err := s.GetReplenishmentSuggestions(ctx, companyCode)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Suggestions) == 0 {
+		return `{"suggestions":[],"note":"No products are at or below their reorder point."}`, nil
+	}
+	out := make([]map[string]any, len(result.Suggestions))
+	for i, sg := range result.Suggestions {
+		m := map[string]any{
+			"product_code":   sg.ProductCode,
+			"product_name":   sg.ProductName,
+			"warehouse_code": sg.WarehouseCode,
+			"available":      sg.Available.String(),
+			"on_order":       sg.OnOrder.String(),
+			"projected":      sg.Projected.String(),
+			"reorder_point":  sg.ReorderPoint.String(),
+			"suggested_qty":  sg.SuggestedQty.String(),
+			"unit_cost":      sg.UnitCost.StringFixed(2),
+			"line_value":     sg.LineValue.StringFixed(2),
+			"lead_time_days": sg.LeadTimeDays,
+			"expected_date":  sg.ExpectedDate,
+		}
+		if sg.VendorCode != nil {
+			m["vendor_code"] = *sg.VendorCode
+			m["vendor_name"] = *sg.VendorName
+		} else {
+			m["note"] = "No preferred or previous vendor; set a reorder policy vendor before raising a PO."
+		}
+		out[i] = m
+	}
+	data, _ := json.Marshal(map[string]any{"suggestions": out})
+	return string(data), nil
+}
+
 // purchaseOrdersToJSON converts a slice of PurchaseOrder to a JSON-friendly format.
 func purchaseOrdersToJSON(orders []core.PurchaseOrder) []map[string]any {
 	out := make([]map[string]any, len(orders))
@@ -1928,6 +5582,7 @@ func purchaseOrdersToJSON(orders []core.PurchaseOrder) []map[string]any {
 			"status":            po.Status,
 			"po_date":           po.PODate,
 			"currency":          po.Currency,
+			"exchange_rate":     po.ExchangeRate.String(),
 			"total_transaction": po.TotalTransaction.String(),
 			"total_base":        po.TotalBase.String(),
 		}
@@ -1940,6 +5595,41 @@ func purchaseOrdersToJSON(orders []core.PurchaseOrder) []map[string]any {
 		if po.Notes != nil {
 			m["notes"] = *po.Notes
 		}
+		if po.MatchStatus != nil {
+			m["match_status"] = *po.MatchStatus
+			m["payment_blocked"] = po.PaymentBlocked
+		}
+		if po.ShortClosedAt != nil {
+			m["short_closed"] = true
+		}
+		if po.Revision > 0 {
+			m["revision"] = po.Revision
+		}
+		if po.CancelReason != nil {
+			m["cancel_reason"] = *po.CancelReason
+		}
+		if len(po.Lines) > 0 {
+			lines := make([]map[string]any, len(po.Lines))
+			for j, l := range po.Lines {
+				lines[j] = map[string]any{
+					"po_line_id":           l.ID,
+					"line_number":          l.LineNumber,
+					"description":          l.Description,
+					"quantity":             l.Quantity.String(),
+					"unit":                 l.Unit,
+					"unit_cost":            l.UnitCost.String(),
+					"received_quantity":    l.ReceivedQuantity.String(),
+					"outstanding_quantity": l.OutstandingQuantity().String(),
+				}
+				if l.ReturnedQuantity.IsPositive() {
+					lines[j]["returned_quantity"] = l.ReturnedQuantity.String()
+				}
+				if l.ProductCode != nil {
+					lines[j]["product_code"] = *l.ProductCode
+				}
+			}
+			m["lines"] = lines
+		}
 		out[i] = m
 	}
 	return out
@@ -3,6 +3,8 @@ package app
 import (
 	"time"
 
+	"accounting-agent/internal/core"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -21,7 +23,10 @@ type CreateOrderRequest struct {
 type OrderLineInput struct {
 	ProductCode string
 	Quantity    decimal.Decimal
-	UnitPrice   decimal.Decimal // zero means "use product default"
+	Unit        string               // optional; defaults to the product's sales unit
+	UnitPrice   decimal.Decimal      // zero means "use product default"
+	TaxCode     string               // optional; defaults to the product's GST code
+	Dimensions  []core.LineDimension // optional; posted with the line's revenue
 }
 
 // CreateVendorRequest is the input for creating a new vendor.
@@ -36,15 +41,77 @@ type CreateVendorRequest struct {
 	PaymentTermsDays          int
 	APAccountCode             string
 	DefaultExpenseAccountCode string
+	BankAccountName           string
+	BankAccountNumber         string
+	BankCode                  string
+	Currency                  string // optional default purchasing currency
+	PAN                       string // optional permanent account number
+	TDSSectionCode            string // optional; TDS is withheld on payments under this section
+	StateCode                 string // optional GST state code; taken from GSTIN when empty
+	GSTIN                     string // optional
+}
+
+// UpdateVendorRequest is the input for updating a vendor. Every editable field is replaced;
+// Version must be the version the caller read, or the update fails with a conflict.
+type UpdateVendorRequest struct {
+	CompanyCode               string
+	Code                      string
+	Version                   int
+	Name                      string
+	ContactPerson             string
+	Email                     string
+	Phone                     string
+	Address                   string
+	PaymentTermsDays          int
+	APAccountCode             string
+	DefaultExpenseAccountCode string
+	BankAccountName           string
+	BankAccountNumber         string
+	BankCode                  string
+	Currency                  string
+	PAN                       string
+	TDSSectionCode            string
+	StateCode                 string
+	GSTIN                     string
+}
+
+// CreateCustomerRequest is the input for creating a new customer.
+type CreateCustomerRequest struct {
+	CompanyCode      string
+	Code             string
+	Name             string
+	Email            string
+	Phone            string
+	Address          string
+	CreditLimit      decimal.Decimal
+	PaymentTermsDays int // 0 defaults to 30
+}
+
+// UpdateCustomerRequest is the input for updating a customer. Every editable field is
+// replaced; Version must be the version the caller read, or the update fails with a conflict.
+type UpdateCustomerRequest struct {
+	CompanyCode      string
+	Code             string
+	Version          int
+	Name             string
+	Email            string
+	Phone            string
+	Address          string
+	CreditLimit      decimal.Decimal
+	PaymentTermsDays int
+	StateCode        string // GST state code; taken from GSTIN when empty
+	GSTIN            string
 }
 
 // CreatePurchaseOrderRequest is the input for creating a new purchase order.
 type CreatePurchaseOrderRequest struct {
-	CompanyCode          string
-	VendorCode           string
-	PODate               string // YYYY-MM-DD
-	Notes                string
-	Lines                []POLineInput
+	CompanyCode  string
+	VendorCode   string
+	PODate       string          // YYYY-MM-DD
+	Currency     string          // optional; defaults to the vendor's currency, then base
+	ExchangeRate decimal.Decimal // base per unit of Currency; required for a foreign currency
+	Notes        string
+	Lines        []POLineInput
 }
 
 // POLineInput is a single line within a CreatePurchaseOrderRequest.
@@ -52,8 +119,37 @@ type POLineInput struct {
 	ProductCode        string
 	Description        string
 	Quantity           decimal.Decimal
+	Unit               string // optional; defaults to the product's purchase unit
+	UnitCost           decimal.Decimal
+	ExpenseAccountCode string
+	TaxCode            string               // optional; defaults to the product's GST code
+	Dimensions         []core.LineDimension // optional; posted with a service line's expense
+}
+
+// AmendPurchaseOrderRequest is the input for amending an APPROVED purchase order.
+// A nil Lines keeps the current lines; otherwise Lines is the complete new line set.
+type AmendPurchaseOrderRequest struct {
+	CompanyCode          string
+	POID                 int
+	ExpectedDeliveryDate string // YYYY-MM-DD, optional
+	Lines                []POAmendLineInput
+	Reason               string
+	RequireReapproval    bool
+	AmendedBy            string
+}
+
+// POAmendLineInput is one line of an amended purchase order. POLineID 0 adds a new line;
+// for existing lines only Quantity, UnitCost and Description are used.
+type POAmendLineInput struct {
+	POLineID           int
+	ProductCode        string
+	Description        string
+	Quantity           decimal.Decimal
+	Unit               string
 	UnitCost           decimal.Decimal
 	ExpenseAccountCode string
+	TaxCode            string               // new lines only
+	Dimensions         []core.LineDimension // new lines only
 }
 
 // ReceiveStockRequest is the input for recording a goods receipt into a warehouse.
@@ -65,13 +161,28 @@ type ReceiveStockRequest struct {
 	MovementDate      string
 	Qty               decimal.Decimal
 	UnitCost          decimal.Decimal
+	Lots              []LotInput // required for lot- or serial-tracked products
+}
+
+// LotInput is one lot (or serial number) on a goods receipt.
+type LotInput struct {
+	LotNumber  string
+	ExpiryDate string          // optional YYYY-MM-DD
+	Quantity   decimal.Decimal // optional for serials (1) and single-lot receipts (full qty)
+}
+
+// LotPickInput is an explicit lot selection when shipping an order.
+type LotPickInput struct {
+	ProductCode string
+	LotNumber   string
+	Quantity    decimal.Decimal
 }
 
 // ReceivePORequest is the input for recording goods/services received against a PO.
 type ReceivePORequest struct {
 	CompanyCode   string
 	POID          int
-	WarehouseCode string          // optional; defaults to the company's default warehouse
+	WarehouseCode string // optional; defaults to the company's default warehouse
 	Lines         []ReceivedLineInput
 }
 
@@ -79,15 +190,35 @@ type ReceivePORequest struct {
 type ReceivedLineInput struct {
 	POLineID    int
 	QtyReceived decimal.Decimal
+	Lots        []LotInput
 }
 
 // VendorInvoiceRequest is the input for recording a vendor invoice against a RECEIVED PO.
+// With no Lines the invoice is matched at header level against InvoiceAmount; with Lines,
+// InvoiceAmount is optional and must equal their sum when given.
 type VendorInvoiceRequest struct {
 	CompanyCode   string
 	POID          int
 	InvoiceNumber string
 	InvoiceDate   time.Time
 	InvoiceAmount decimal.Decimal
+	Lines         []VendorInvoiceLineInput
+}
+
+// VendorInvoiceLineInput is a single invoiced PO line, in the PO line's unit.
+type VendorInvoiceLineInput struct {
+	POLineID  int
+	Quantity  decimal.Decimal
+	UnitPrice decimal.Decimal
+}
+
+// SetMatchToleranceRequest is the input for setting a company's three-way match tolerances.
+type SetMatchToleranceRequest struct {
+	CompanyCode       string
+	PriceTolerancePct decimal.Decimal
+	QtyTolerancePct   decimal.Decimal
+	AmountTolerance   decimal.Decimal
+	VarianceTreatment string // PPV | INVENTORY; defaults to PPV
 }
 
 // PayVendorRequest is the input for recording payment against an INVOICED PO.
@@ -98,6 +229,24 @@ type PayVendorRequest struct {
 	PaymentDate     time.Time
 }
 
+// SetReorderPolicyRequest is the input for creating or updating a reorder policy.
+type SetReorderPolicyRequest struct {
+	CompanyCode         string
+	ProductCode         string
+	WarehouseCode       string // optional; defaults to the company's default warehouse
+	ReorderPoint        decimal.Decimal
+	ReorderQty          decimal.Decimal
+	LeadTimeDays        int
+	PreferredVendorCode string // optional; falls back to the vendor of the latest PO
+}
+
+// CreateReplenishmentPOsRequest is the input for raising DRAFT POs from replenishment suggestions.
+type CreateReplenishmentPOsRequest struct {
+	CompanyCode  string
+	PODate       string   // YYYY-MM-DD; defaults to today
+	ProductCodes []string // optional; restricts the run to these products
+}
+
 // CreateUserRequest is the input for creating a new user.
 type CreateUserRequest struct {
 	CompanyCode string
@@ -122,3 +271,178 @@ type UpdateUserRoleRequest struct {
 	UserID      int
 	Role        string // ACCOUNTANT | FINANCE_MANAGER | ADMIN
 }
+
+// SetDefaultUoMsRequest is the input for setting a product's default purchase and sales units.
+// An empty unit resets that default to the product's stock unit.
+type SetDefaultUoMsRequest struct {
+	CompanyCode string
+	ProductCode string
+	PurchaseUoM string
+	SalesUoM    string
+}
+
+// CreateLandedCostVoucherRequest is the input for posting a landed cost voucher.
+// Receipts are selected by PO number and/or receipt movement ID.
+// An empty CreditAccountCode uses the vendor's AP account, else 2000.
+type CreateLandedCostVoucherRequest struct {
+	CompanyCode        string
+	VoucherDate        string // YYYY-MM-DD; defaults to today
+	VendorCode         string // optional
+	CreditAccountCode  string
+	AllocationMethod   string // VALUE | QUANTITY | WEIGHT; defaults to VALUE
+	PONumbers          []string
+	ReceiptMovementIDs []int
+	Charges            []LandedCostChargeInput
+	Notes              string
+}
+
+// LandedCostChargeInput is one charge (freight, duty, insurance…) on a landed cost voucher.
+type LandedCostChargeInput struct {
+	Description string
+	Amount      decimal.Decimal
+}
+
+// CreateVendorBillRequest is the input for posting a vendor bill without a purchase order.
+// An empty DueDate is BillDate plus the vendor's payment terms.
+type CreateVendorBillRequest struct {
+	CompanyCode string
+	VendorCode  string
+	BillNumber  string // vendor's invoice reference
+	BillDate    string // YYYY-MM-DD; defaults to today
+	DueDate     string // YYYY-MM-DD; optional
+	Notes       string
+	Lines       []VendorBillLineInput
+}
+
+// VendorBillLineInput is one line on a vendor bill. ExpenseAccountCode defaults to the
+// vendor's default expense account.
+type VendorBillLineInput struct {
+	ProductCode        string // optional
+	Description        string
+	Quantity           decimal.Decimal // defaults to 1
+	UnitCost           decimal.Decimal
+	ExpenseAccountCode string
+	TaxCode            string // optional; defaults to the product's GST code
+}
+
+// PayVendorBillRequest is the input for recording payment of a vendor bill.
+type PayVendorBillRequest struct {
+	CompanyCode     string
+	BillID          int
+	BankAccountCode string
+	PaymentDate     time.Time
+}
+
+// CreatePaymentRunRequest is the selection for a new batch payment run.
+type CreatePaymentRunRequest struct {
+	CompanyCode     string
+	DueBy           string // YYYY-MM-DD
+	PaymentDate     string // YYYY-MM-DD; defaults to DueBy
+	BankAccountCode string // defaults to "1100"
+	VendorCode      string // optional
+	CreatedBy       string
+}
+
+// CreatePurchaseReturnRequest is the input for returning received goods to a vendor.
+type CreatePurchaseReturnRequest struct {
+	CompanyCode   string
+	POID          int
+	WarehouseCode string // optional; defaults to the company's default warehouse
+	ReturnDate    string // YYYY-MM-DD; defaults to today
+	Reason        string
+	Lines         []PurchaseReturnLineInput
+}
+
+// PurchaseReturnLineInput is a single line in a CreatePurchaseReturnRequest, in the PO line's unit.
+type PurchaseReturnLineInput struct {
+	POLineID int
+	Quantity decimal.Decimal
+}
+
+// SetTaxRateRequest is the input for changing a GST component rate of a tax code.
+type SetTaxRateRequest struct {
+	CompanyCode   string
+	TaxCode       string
+	Component     string // CGST | SGST | IGST
+	Rate          decimal.Decimal
+	EffectiveFrom string // YYYY-MM-DD
+}
+
+// CancelEInvoiceRequest is the input for cancelling an order's e-invoice IRN.
+type CancelEInvoiceRequest struct {
+	CompanyCode string
+	Ref         string // order number or ID
+	ReasonCode  int    // 1 duplicate, 2 data entry mistake, 3 order cancelled, 4 other
+	Remark      string
+}
+
+// ColumnarReportRequest selects the columns of a multi-period P&L or balance sheet, from the
+// first of these that is set (periods use core.ParseReportPeriod syntax):
+//   - Periods: the listed periods; with Comparative set, variances are taken from the first
+//   - Compare: one period beside the prior period and the same period last year, with variances
+//   - Trend: a month YYYY-MM and the TrendMonths (default 12) months ending with it
+//
+// With none set, the current month is compared.
+type ColumnarReportRequest struct {
+	CompanyCode string
+	Periods     []string
+	Comparative bool
+	Compare     string
+	Trend       string
+	TrendMonths int
+}
+
+// CreateAccountRequest is the input for adding a postable account to the chart of accounts.
+type CreateAccountRequest struct {
+	CompanyCode          string
+	Code                 string
+	Name                 string
+	Type                 string // asset | liability | equity | revenue | expense
+	ParentCode           string // optional parent group
+	PostingBlocked       bool
+	ManualPostingBlocked bool // control account: document workflows only
+	NarrationRequired    bool
+}
+
+// UpdateAccountRequest renames an account and replaces its posting controls.
+type UpdateAccountRequest struct {
+	CompanyCode          string
+	AccountCode          string
+	Name                 string
+	PostingBlocked       bool
+	ManualPostingBlocked bool
+	NarrationRequired    bool
+}
+
+// CreateAccountGroupRequest is the input for adding a group account to the chart of accounts.
+type CreateAccountGroupRequest struct {
+	CompanyCode string
+	Code        string
+	Name        string
+	Type        string // asset | liability | equity | revenue | expense
+	ParentCode  string // optional parent group
+}
+
+// BudgetVsActualRequest selects a budget-vs-actual report. Period uses core.ParseReportPeriod
+// syntax and must cover whole months; empty means the current financial year to the end of
+// this month. An empty BudgetCode picks the latest budget for the period's financial year,
+// and a nil ThresholdPct the budget's own variance threshold.
+type BudgetVsActualRequest struct {
+	CompanyCode  string
+	BudgetCode   string
+	Period       string
+	ThresholdPct *decimal.Decimal
+}
+
+// DimensionReportRequest selects a P&L or trial balance by analytic dimension. Filter is a
+// comma-separated list of DIMENSION:VALUE pairs (see core.ParseDimensionFilter) and
+// Breakdown a dimension to show one column per value of. For the P&L, Period uses
+// core.ParseReportPeriod syntax (empty: this month); for the trial balance AsOfDate is
+// YYYY-MM-DD (empty: today).
+type DimensionReportRequest struct {
+	CompanyCode string
+	Period      string
+	AsOfDate    string
+	Filter      string
+	Breakdown   string
+}
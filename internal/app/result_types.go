@@ -32,6 +32,16 @@ type CustomerListResult struct {
 	Customers []core.Customer
 }
 
+// CustomerResult is returned by GetCustomer, CreateCustomer, UpdateCustomer and SetCustomerActive.
+type CustomerResult struct {
+	Customer *core.Customer
+}
+
+// MergeResult is returned by MergeVendors and MergeCustomers.
+type MergeResult struct {
+	Merge *core.MergeResult
+}
+
 // ProductListResult is returned by ListProducts.
 type ProductListResult struct {
 	Products []core.Product
@@ -79,7 +89,7 @@ type VendorsResult struct {
 	Vendors []core.Vendor
 }
 
-// VendorResult is returned by CreateVendor.
+// VendorResult is returned by GetVendor, CreateVendor, UpdateVendor and SetVendorActive.
 type VendorResult struct {
 	Vendor *core.Vendor
 }
@@ -94,6 +104,12 @@ type PurchaseOrderResult struct {
 	PurchaseOrder *core.PurchaseOrder
 }
 
+// PORevisionsResult is returned by GetPurchaseOrderRevisions.
+type PORevisionsResult struct {
+	POID      int
+	Revisions []core.PurchaseOrderRevision
+}
+
 // POReceiptResult is returned by ReceivePurchaseOrder.
 type POReceiptResult struct {
 	PurchaseOrder *core.PurchaseOrder
@@ -104,15 +120,98 @@ type POReceiptResult struct {
 type VendorInvoiceResult struct {
 	PurchaseOrder    *core.PurchaseOrder
 	PIDocumentNumber string
-	// Warning is non-empty if the invoice amount deviates > 5% from the PO total.
+	// Warning is non-empty if any line is outside match tolerance and the PO is blocked for payment.
 	Warning string
 }
 
+// MatchToleranceResult is returned by GetMatchTolerance and SetMatchTolerance.
+type MatchToleranceResult struct {
+	Tolerance *core.MatchTolerance
+}
+
 // PaymentResult is returned by PayVendor.
 type PaymentResult struct {
 	PurchaseOrder *core.PurchaseOrder
 }
 
+// LotListResult is returned by ListLots.
+type LotListResult struct {
+	CompanyCode string
+	Lots        []core.InventoryLot
+}
+
+// UoMListResult is returned by ListUoMs.
+type UoMListResult struct {
+	CompanyCode string
+	Units       []core.UnitOfMeasure
+}
+
+// LandedCostVouchersResult is returned by ListLandedCostVouchers.
+type LandedCostVouchersResult struct {
+	CompanyCode string
+	Vouchers    []core.LandedCostVoucher
+}
+
+// LandedCostVoucherResult is returned by GetLandedCostVoucher and CreateLandedCostVoucher.
+type LandedCostVoucherResult struct {
+	Voucher *core.LandedCostVoucher
+}
+
+// VendorBillsResult is returned by ListVendorBills.
+type VendorBillsResult struct {
+	CompanyCode string
+	Bills       []core.VendorBill
+}
+
+// VendorBillResult is returned by GetVendorBill, CreateVendorBill and PayVendorBill.
+type VendorBillResult struct {
+	Bill *core.VendorBill
+}
+
+// PaymentRunsResult is returned by ListPaymentRuns.
+type PaymentRunsResult struct {
+	CompanyCode string
+	Runs        []core.PaymentRun
+}
+
+// PaymentRunResult is returned by the payment run lifecycle methods.
+type PaymentRunResult struct {
+	Run *core.PaymentRun
+}
+
+// PurchaseReturnsResult is returned by ListPurchaseReturns.
+type PurchaseReturnsResult struct {
+	CompanyCode string
+	Returns     []core.PurchaseReturn
+}
+
+// PurchaseReturnResult is returned by GetPurchaseReturn and CreatePurchaseReturn.
+type PurchaseReturnResult struct {
+	Return *core.PurchaseReturn
+}
+
+// ReorderPoliciesResult is returned by ListReorderPolicies.
+type ReorderPoliciesResult struct {
+	Policies []core.ReorderPolicy
+}
+
+// ReorderPolicyResult is returned by SetReorderPolicy.
+type ReorderPolicyResult struct {
+	Policy *core.ReorderPolicy
+}
+
+// ReplenishmentSuggestionsResult is returned by GetReplenishmentSuggestions.
+type ReplenishmentSuggestionsResult struct {
+	Suggestions []core.ReplenishmentSuggestion
+}
+
+// ReplenishmentRunResult is returned by CreateReplenishmentPOs.
+type ReplenishmentRunResult struct {
+	PurchaseOrders []core.PurchaseOrder
+	// Skipped lists suggestions that could not be ordered because no vendor is known.
+	Skipped []core.ReplenishmentSuggestion
+}
+
 // AIResult is returned by InterpretEvent.
 type AIResult struct {
 	Proposal             *core.Proposal
@@ -154,3 +253,14 @@ type DomainActionResult struct {
 	// The adapter should pass this to InterpretEvent.
 	EventDescription string
 }
+
+// TDSSectionsResult is returned by ListTDSSections.
+type TDSSectionsResult struct {
+	Sections []core.TDSSection
+}
+
+// TaxCodesResult is returned by ListTaxCodes.
+type TaxCodesResult struct {
+	AsOf     string // YYYY-MM-DD
+	TaxCodes []core.TaxCode
+}
@@ -2,8 +2,11 @@ package app
 
 import (
 	"context"
+	"io"
 
 	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
 )
 
 // Attachment is an uploaded file attached to an AI chat message.
@@ -23,6 +26,21 @@ type ApplicationService interface {
 	// ListCustomers returns all active customers for a company.
 	ListCustomers(ctx context.Context, companyCode string) (*CustomerListResult, error)
 
+	// GetCustomer returns one customer by code, including inactive and merged customers.
+	GetCustomer(ctx context.Context, companyCode, customerCode string) (*CustomerResult, error)
+
+	// CreateCustomer creates a new customer record for the given company.
+	CreateCustomer(ctx context.Context, req CreateCustomerRequest) (*CustomerResult, error)
+
+	// UpdateCustomer replaces a customer's editable fields, guarded by the version the caller read.
+	UpdateCustomer(ctx context.Context, req UpdateCustomerRequest) (*CustomerResult, error)
+
+	// SetCustomerActive deactivates or reactivates a customer. Inactive customers cannot take new orders.
+	SetCustomerActive(ctx context.Context, companyCode, customerCode string, active bool) (*CustomerResult, error)
+
+	// MergeCustomers repoints the duplicate customer's sales orders to the survivor and deactivates the duplicate.
+	MergeCustomers(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error)
+
 	// ListProducts returns all active products for a company.
 	ListProducts(ctx context.Context, companyCode string) (*ProductListResult, error)
 
@@ -42,6 +60,10 @@ type ApplicationService interface {
 	// ShipOrder transitions a CONFIRMED order to SHIPPED, deducting inventory and booking COGS.
 	ShipOrder(ctx context.Context, ref, companyCode string) (*OrderResult, error)
 
+	// ShipOrderWithPicks is ShipOrder with explicit lot picks for lot-tracked products,
+	// overriding the product's FEFO/FIFO strategy.
+	ShipOrderWithPicks(ctx context.Context, ref, companyCode string, picks []LotPickInput) (*OrderResult, error)
+
 	// InvoiceOrder transitions a SHIPPED order to INVOICED, posting the sales invoice journal entry.
 	InvoiceOrder(ctx context.Context, ref, companyCode string) (*OrderResult, error)
 
@@ -57,6 +79,27 @@ type ApplicationService interface {
 	// ReceiveStock records a goods receipt: increases qty_on_hand and books DR Inventory / CR creditAccount.
 	ReceiveStock(ctx context.Context, req ReceiveStockRequest) error
 
+	// ListLots returns lots with stock on hand, optionally filtered to one product.
+	ListLots(ctx context.Context, companyCode, productCode string) (*LotListResult, error)
+
+	// SetProductTracking sets a product's tracking mode (NONE, LOT, SERIAL) and lot pick strategy (FEFO, FIFO).
+	SetProductTracking(ctx context.Context, companyCode, productCode, trackingMode, pickStrategy string) error
+
+	// ListUoMs returns the company's unit-of-measure master.
+	ListUoMs(ctx context.Context, companyCode string) (*UoMListResult, error)
+
+	// CreateUoM adds a unit of measure to the company's master.
+	CreateUoM(ctx context.Context, companyCode, code, name string) (*core.UnitOfMeasure, error)
+
+	// GetProductUnits returns a product's stock unit, default PO/sales units and conversion factors.
+	GetProductUnits(ctx context.Context, companyCode, productCode string) (*core.ProductUnits, error)
+
+	// SetUoMConversion creates or updates how many stock units make up one uomCode for a product.
+	SetUoMConversion(ctx context.Context, companyCode, productCode, uomCode string, factor decimal.Decimal) (*core.ProductUnits, error)
+
+	// SetDefaultUoMs sets the units new PO and sales order lines use for a product when none is given.
+	SetDefaultUoMs(ctx context.Context, req SetDefaultUoMsRequest) (*core.ProductUnits, error)
+
 	// InterpretEvent sends a natural language event description to the AI agent and returns
 	// either a journal entry Proposal or a clarification request.
 	// This path uses structured output and must remain untouched per §16.4 of ai_agent_upgrade.md.
@@ -84,9 +127,168 @@ type ApplicationService interface {
 	// If asOfDate is empty, today's date is used.
 	GetBalanceSheet(ctx context.Context, companyCode, asOfDate string) (*core.BSReport, error)
 
+	// ExportTrialBalance renders the trial balance as a file in format: csv, xlsx, pdf or text.
+	ExportTrialBalance(ctx context.Context, companyCode, format string) (*core.ReportFile, error)
+
+	// ExportProfitAndLoss renders the P&L for a calendar month as a file in format.
+	ExportProfitAndLoss(ctx context.Context, companyCode string, year, month int, format string) (*core.ReportFile, error)
+
+	// ExportBalanceSheet renders the Balance Sheet as of a date as a file in format.
+	ExportBalanceSheet(ctx context.Context, companyCode, asOfDate, format string) (*core.ReportFile, error)
+
+	// ExportAccountStatement renders an account statement as a file in format.
+	ExportAccountStatement(ctx context.Context, companyCode, accountCode, fromDate, toDate, format string) (*core.ReportFile, error)
+
+	// GetColumnarProfitAndLoss returns the P&L with one column per selected period:
+	// comparative (this, prior, last year), a monthly trend, or a list of periods.
+	GetColumnarProfitAndLoss(ctx context.Context, req ColumnarReportRequest) (*core.ColumnarPLReport, error)
+
+	// GetColumnarBalanceSheet returns the Balance Sheet as of the end of each selected period.
+	GetColumnarBalanceSheet(ctx context.Context, req ColumnarReportRequest) (*core.ColumnarBSReport, error)
+
 	// RefreshViews refreshes all materialized reporting views.
 	RefreshViews(ctx context.Context) error
 
+	// GetInventoryValuation returns stock quantity and value per product and warehouse
+	// as of the given date, with a reconciliation against the INVENTORY account.
+	GetInventoryValuation(ctx context.Context, companyCode, asOfDate string) (*core.InventoryValuationReport, error)
+
+	// GetStockMovementLedger returns the movement history for a product with running
+	// quantity and value. warehouseCode, fromDate, and toDate are optional.
+	GetStockMovementLedger(ctx context.Context, companyCode, productCode, warehouseCode, fromDate, toDate string) (*core.StockMovementLedger, error)
+
+	// GetStockAgeing returns on-hand stock split into age buckets with slow-moving items flagged.
+	GetStockAgeing(ctx context.Context, companyCode, asOfDate string, slowMovingDays int) (*core.StockAgeingReport, error)
+
+	// GetLotTrace traces a lot forward from its supplier receipts to the customers it was shipped to.
+	GetLotTrace(ctx context.Context, companyCode, productCode, lotNumber string) (*core.LotTrace, error)
+
+	// GetOrderLotTrace traces a sales order backward to the lots shipped and their supplier POs.
+	GetOrderLotTrace(ctx context.Context, ref, companyCode string) (*core.OrderLotTrace, error)
+
+	// GetCashFlow returns the statement of cash flows for a date range by the INDIRECT
+	// (default) or DIRECT method. Empty dates default to the current month to date.
+	GetCashFlow(ctx context.Context, companyCode, fromDate, toDate, method string) (*core.CashFlowReport, error)
+
+	// GetCashFlowMappings returns the cash flow activity and line of every account.
+	GetCashFlowMappings(ctx context.Context, companyCode string) ([]core.CashFlowMapping, error)
+
+	// SetCashFlowMapping maps an account to a cash flow activity and line; an empty
+	// activity reverts it to the default.
+	SetCashFlowMapping(ctx context.Context, companyCode, accountCode, activity, lineName string) error
+
+	// SetCashAccount flags or unflags an asset account as cash or bank.
+	SetCashAccount(ctx context.Context, companyCode, accountCode string, isCash bool) error
+
+	// GetChartOfAccounts returns every account in tree order with its balance and, for
+	// groups, the rollup of its sub-accounts, as of asOfDate (empty means today).
+	GetChartOfAccounts(ctx context.Context, companyCode, asOfDate string) ([]core.AccountNode, error)
+
+	// GetAccount returns one account with its active flag and posting controls.
+	GetAccount(ctx context.Context, companyCode, accountCode string) (*core.Account, error)
+
+	// CreateAccount adds a postable account, optionally under a parent group.
+	CreateAccount(ctx context.Context, req CreateAccountRequest) (*core.Account, error)
+
+	// UpdateAccount renames an account and replaces its posting controls.
+	UpdateAccount(ctx context.Context, req UpdateAccountRequest) (*core.Account, error)
+
+	// SetAccountActive deactivates or reactivates an account. Deactivation is refused while
+	// the account has a balance, active sub-accounts or an account rule using it.
+	SetAccountActive(ctx context.Context, companyCode, accountCode string, active bool) error
+
+	// CreateAccountGroup adds a group (header) account, optionally under a parent group.
+	CreateAccountGroup(ctx context.Context, req CreateAccountGroupRequest) (*core.Account, error)
+
+	// SetAccountParent moves an account under a group of the same type; an empty
+	// parentCode moves it to the top level.
+	SetAccountParent(ctx context.Context, companyCode, accountCode, parentCode string) error
+
+	// GetStatementLayouts returns the company's statement layouts with lines and mappings.
+	GetStatementLayouts(ctx context.Context, companyCode string) ([]core.StatementLayout, error)
+
+	// CreateStatementLayout adds an empty BALANCE_SHEET or PROFIT_AND_LOSS layout.
+	CreateStatementLayout(ctx context.Context, companyCode, code, name, statement string) error
+
+	// SetStatementLayoutLine creates or updates a line of a statement layout.
+	SetStatementLayoutLine(ctx context.Context, companyCode, layoutCode string, line core.StatementLayoutLine) error
+
+	// SetStatementLayoutAccount maps an account or group to a layout line; an empty
+	// lineCode removes the mapping.
+	SetStatementLayoutAccount(ctx context.Context, companyCode, layoutCode, accountCode, lineCode string) error
+
+	// GetLayoutStatement returns the balance sheet as of toDate, or the P&L for a date
+	// range, presented in a statement layout such as Schedule III.
+	GetLayoutStatement(ctx context.Context, companyCode, layoutCode, fromDate, toDate string) (*core.LayoutStatement, error)
+
+	// GetJournalRegister returns one page of posted journal entries matching filter.
+	GetJournalRegister(ctx context.Context, companyCode string, filter core.JournalFilter) (*core.JournalRegister, error)
+
+	// GetJournalEntry returns a posted entry with its lines, linked document and reversal links.
+	GetJournalEntry(ctx context.Context, companyCode string, entryID int) (*core.JournalEntry, error)
+
+	// GetDayBook returns every entry posted on date (empty means today) with its lines.
+	GetDayBook(ctx context.Context, companyCode, date string) (*core.DayBook, error)
+
+	// GetGeneralLedger returns each account's lines between two dates with opening,
+	// running and closing balances.
+	GetGeneralLedger(ctx context.Context, companyCode, fromDate, toDate string) (*core.GeneralLedger, error)
+
+	// ListCostCenters returns the company's cost centers.
+	ListCostCenters(ctx context.Context, companyCode string) ([]core.CostCenter, error)
+
+	// CreateCostCenter adds a cost center that budget amounts can be set for.
+	CreateCostCenter(ctx context.Context, companyCode, code, name string) (*core.CostCenter, error)
+
+	// ListBudgets returns the company's budget versions with their totals.
+	ListBudgets(ctx context.Context, companyCode string) ([]core.Budget, error)
+
+	// CreateBudget adds an empty budget version for a financial year.
+	CreateBudget(ctx context.Context, companyCode string, input core.BudgetInput) (*core.Budget, error)
+
+	// GetBudget returns a budget version with its monthly lines.
+	GetBudget(ctx context.Context, companyCode, budgetCode string) (*core.Budget, error)
+
+	// ImportBudgetCSV reads budget lines from CSV (see core.ParseBudgetCSV) into a budget,
+	// replacing all existing lines when replace is set. Returns the number of lines written.
+	ImportBudgetCSV(ctx context.Context, companyCode, budgetCode string, r io.Reader, replace bool) (int, error)
+
+	// CopyBudgetFromActuals replaces a budget's lines with the revenue and expense actuals
+	// of the financial year sourceYear, increased by upliftPct percent.
+	CopyBudgetFromActuals(ctx context.Context, companyCode, budgetCode string, sourceYear int, upliftPct decimal.Decimal) (int, error)
+
+	// GetBudgetVsActual compares a budget with actuals over whole months, flagging accounts
+	// over or under budget by more than the variance threshold.
+	GetBudgetVsActual(ctx context.Context, req BudgetVsActualRequest) (*core.BudgetVsActualReport, error)
+
+	// ListDimensions returns the company's analytic dimensions (cost center, project, branch
+	// and user-defined ones) with their values.
+	ListDimensions(ctx context.Context, companyCode string) ([]core.Dimension, error)
+
+	// CreateDimension adds a user-defined dimension.
+	CreateDimension(ctx context.Context, companyCode, code, name string) (*core.Dimension, error)
+
+	// CreateDimensionValue adds a master data value to a dimension.
+	CreateDimensionValue(ctx context.Context, companyCode, dimensionCode, code, name string) (*core.DimensionValue, error)
+
+	// SetDimensionValueActive activates or retires a dimension value.
+	SetDimensionValueActive(ctx context.Context, companyCode, dimensionCode, valueCode string, active bool) (*core.DimensionValue, error)
+
+	// ListAccountDimensionRules returns the dimensions every posting to an account must carry.
+	ListAccountDimensionRules(ctx context.Context, companyCode string) ([]core.AccountDimensionRule, error)
+
+	// SetAccountDimensionRequired makes a dimension mandatory on postings to an account, or
+	// optional again.
+	SetAccountDimensionRequired(ctx context.Context, companyCode, accountCode, dimensionCode string, required bool) error
+
+	// GetDimensionProfitAndLoss returns the P&L restricted to lines carrying the filter
+	// values, optionally with one column per value of a breakdown dimension.
+	GetDimensionProfitAndLoss(ctx context.Context, req DimensionReportRequest) (*core.DimensionPLReport, error)
+
+	// GetDimensionTrialBalance returns the trial balance restricted to lines carrying the
+	// filter values, optionally with one column per value of a breakdown dimension.
+	GetDimensionTrialBalance(ctx context.Context, req DimensionReportRequest) (*core.DimensionTrialBalance, error)
+
 	// CommitProposal validates and posts an AI-generated proposal to the ledger.
 	// Must only be called after explicit user approval.
 	CommitProposal(ctx context.Context, proposal core.Proposal) error
@@ -130,6 +332,19 @@ type ApplicationService interface {
 	// CreateVendor creates a new vendor record for the given company.
 	CreateVendor(ctx context.Context, req CreateVendorRequest) (*VendorResult, error)
 
+	// GetVendor returns one vendor by code, including inactive and merged vendors.
+	GetVendor(ctx context.Context, companyCode, vendorCode string) (*VendorResult, error)
+
+	// UpdateVendor replaces a vendor's editable fields, guarded by the version the caller read.
+	UpdateVendor(ctx context.Context, req UpdateVendorRequest) (*VendorResult, error)
+
+	// SetVendorActive deactivates or reactivates a vendor. Inactive vendors cannot receive new POs or bills.
+	SetVendorActive(ctx context.Context, companyCode, vendorCode string, active bool) (*VendorResult, error)
+
+	// MergeVendors repoints the duplicate vendor's POs, bills, returns and other open items to the
+	// survivor and deactivates the duplicate.
+	MergeVendors(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error)
+
 	// GetPurchaseOrder returns a single purchase order by its internal ID.
 	GetPurchaseOrder(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error)
 
@@ -142,14 +357,165 @@ type ApplicationService interface {
 	// ApprovePurchaseOrder transitions a DRAFT PO to APPROVED, assigning a gapless PO number.
 	ApprovePurchaseOrder(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error)
 
-	// ReceivePurchaseOrder records goods and/or services received against an APPROVED PO.
+	// ReceivePurchaseOrder records goods and/or services received against an APPROVED or
+	// PARTIALLY_RECEIVED PO. The PO becomes RECEIVED once every line is fully received.
 	ReceivePurchaseOrder(ctx context.Context, req ReceivePORequest) (*POReceiptResult, error)
 
-	// RecordVendorInvoice records the vendor's invoice against a RECEIVED PO.
-	// Creates a PI document number. Returns a warning if invoice amount deviates > 5% from PO total.
+	// ShortClosePurchaseOrder closes a PARTIALLY_RECEIVED PO, cancelling the undelivered
+	// balance, and moves it to RECEIVED so it can be invoiced.
+	ShortClosePurchaseOrder(ctx context.Context, companyCode string, poID int, reason, closedBy string) (*PurchaseOrderResult, error)
+
+	// AmendPurchaseOrder changes an APPROVED PO's lines and/or expected delivery date and
+	// increments its revision; optionally returns it to DRAFT for re-approval.
+	AmendPurchaseOrder(ctx context.Context, req AmendPurchaseOrderRequest) (*PurchaseOrderResult, error)
+
+	// CancelPurchaseOrder cancels a DRAFT or APPROVED PO on which nothing has been received.
+	CancelPurchaseOrder(ctx context.Context, companyCode string, poID int, reason, cancelledBy string) (*PurchaseOrderResult, error)
+
+	// GetPurchaseOrderRevisions returns a PO's revision history (creation, approvals,
+	// amendments, cancellation, closing), oldest first.
+	GetPurchaseOrderRevisions(ctx context.Context, companyCode string, poID int) (*PORevisionsResult, error)
+
+	// RecordVendorInvoice records the vendor's invoice against a RECEIVED PO and three-way
+	// matches it against the PO and the quantity received. Creates a PI document number.
+	// Accepted variances are posted; lines outside tolerance block the PO for payment
+	// and are described in the result's Warning.
 	RecordVendorInvoice(ctx context.Context, req VendorInvoiceRequest) (*VendorInvoiceResult, error)
 
-	// PayVendor records payment against an INVOICED PO.
+	// ReleasePaymentBlock accepts the variances on a payment-blocked PO, posts them,
+	// and allows the PO to be paid.
+	ReleasePaymentBlock(ctx context.Context, companyCode string, poID int) (*PurchaseOrderResult, error)
+
+	// GetMatchTolerance returns the company's three-way match tolerances.
+	GetMatchTolerance(ctx context.Context, companyCode string) (*MatchToleranceResult, error)
+
+	// SetMatchTolerance creates or replaces the company's three-way match tolerances.
+	SetMatchTolerance(ctx context.Context, req SetMatchToleranceRequest) (*MatchToleranceResult, error)
+
+	// PayVendor records payment against an INVOICED PO that is not blocked for payment.
 	// Posts DR AP / CR Bank and transitions the PO to PAID.
 	PayVendor(ctx context.Context, req PayVendorRequest) (*PaymentResult, error)
+
+	// ListReorderPolicies returns all active reorder policies for a company.
+	ListReorderPolicies(ctx context.Context, companyCode string) (*ReorderPoliciesResult, error)
+
+	// SetReorderPolicy creates or updates the reorder point, reorder quantity, lead time
+	// and preferred vendor for a product in a warehouse.
+	SetReorderPolicy(ctx context.Context, req SetReorderPolicyRequest) (*ReorderPolicyResult, error)
+
+	// GetReplenishmentSuggestions returns products whose available stock plus open PO
+	// quantity has fallen to or below the reorder point.
+	GetReplenishmentSuggestions(ctx context.Context, companyCode string) (*ReplenishmentSuggestionsResult, error)
+
+	// CreateReplenishmentPOs raises DRAFT purchase orders, one per vendor, for the current
+	// replenishment suggestions.
+	CreateReplenishmentPOs(ctx context.Context, req CreateReplenishmentPOsRequest) (*ReplenishmentRunResult, error)
+
+	// ListLandedCostVouchers returns all landed cost vouchers for a company, newest first.
+	ListLandedCostVouchers(ctx context.Context, companyCode string) (*LandedCostVouchersResult, error)
+
+	// GetLandedCostVoucher returns one landed cost voucher with its charges and allocations.
+	GetLandedCostVoucher(ctx context.Context, companyCode string, voucherID int) (*LandedCostVoucherResult, error)
+
+	// CreateLandedCostVoucher allocates freight, duty and similar charges over goods receipts,
+	// capitalising the share still in stock and expensing the share already shipped to COGS.
+	CreateLandedCostVoucher(ctx context.Context, req CreateLandedCostVoucherRequest) (*LandedCostVoucherResult, error)
+
+	// SetProductWeight sets the weight of one stock unit of a product (used for WEIGHT allocation).
+	SetProductWeight(ctx context.Context, companyCode, productCode string, unitWeight decimal.Decimal) error
+
+	// ListVendorBills returns a company's vendor bills, newest first, optionally filtered by status.
+	ListVendorBills(ctx context.Context, companyCode, status string) (*VendorBillsResult, error)
+
+	// GetVendorBill returns one vendor bill with its lines.
+	GetVendorBill(ctx context.Context, companyCode string, billID int) (*VendorBillResult, error)
+
+	// CreateVendorBill posts a vendor bill without a purchase order: DR expense / CR vendor AP,
+	// with a PI document number and a due date from the vendor's payment terms.
+	CreateVendorBill(ctx context.Context, req CreateVendorBillRequest) (*VendorBillResult, error)
+
+	// PayVendorBill records payment of a POSTED vendor bill (DR AP / CR Bank) and marks it PAID.
+	PayVendorBill(ctx context.Context, req PayVendorBillRequest) (*VendorBillResult, error)
+
+	// ListPaymentRuns returns a company's payment runs, newest first, optionally filtered by status.
+	ListPaymentRuns(ctx context.Context, companyCode, status string) (*PaymentRunsResult, error)
+
+	// GetPaymentRun returns one payment run with its items.
+	GetPaymentRun(ctx context.Context, companyCode string, runID int) (*PaymentRunResult, error)
+
+	// CreatePaymentRun proposes every open AP item (invoiced POs and posted vendor bills) due by
+	// a date, optionally for one vendor and/or currency, as a DRAFT payment run.
+	CreatePaymentRun(ctx context.Context, req CreatePaymentRunRequest) (*PaymentRunResult, error)
+
+	// SetPaymentRunItemExcluded excludes an item from, or re-includes it in, a DRAFT payment run.
+	SetPaymentRunItemExcluded(ctx context.Context, companyCode string, runID, itemID int, excluded bool) (*PaymentRunResult, error)
+
+	// ApprovePaymentRun moves a DRAFT payment run to APPROVED.
+	ApprovePaymentRun(ctx context.Context, companyCode string, runID int, approvedBy string) (*PaymentRunResult, error)
+
+	// PostPaymentRun posts an APPROVED payment run: one DR AP / CR Bank entry per vendor,
+	// net of debit notes, and marks the paid POs and bills PAID.
+	PostPaymentRun(ctx context.Context, companyCode string, runID int) (*PaymentRunResult, error)
+
+	// ExportPaymentRun builds the bank file (pain.001 XML or CSV) for an APPROVED or POSTED run.
+	ExportPaymentRun(ctx context.Context, companyCode string, runID int, format string) (*core.PaymentFile, error)
+
+	// SetHouseBankDetails records the bank account number and bank code behind a bank GL account.
+	SetHouseBankDetails(ctx context.Context, companyCode, accountCode, accountNumber, bankCode string) error
+
+	// ListPurchaseReturns returns a company's purchase returns (debit notes), newest first,
+	// optionally filtered by status and/or vendor.
+	ListPurchaseReturns(ctx context.Context, companyCode, status, vendorCode string) (*PurchaseReturnsResult, error)
+
+	// GetPurchaseReturn returns one purchase return with its lines and debit note applications.
+	GetPurchaseReturn(ctx context.Context, companyCode string, returnID int) (*PurchaseReturnResult, error)
+
+	// CreatePurchaseReturn returns received goods on a PO to the vendor at their receipt cost
+	// (DR AP / CR Inventory) and raises a debit note offset against later vendor payments.
+	CreatePurchaseReturn(ctx context.Context, req CreatePurchaseReturnRequest) (*PurchaseReturnResult, error)
+
+	// ListTDSSections returns the active TDS sections vendors can be assigned to.
+	ListTDSSections(ctx context.Context) (*TDSSectionsResult, error)
+
+	// GetVendorTDSStatus returns a vendor's payments and TDS withheld so far in the financial
+	// year containing asOfDate (YYYY-MM-DD; empty = today), against its section's thresholds.
+	GetVendorTDSStatus(ctx context.Context, companyCode, vendorCode, asOfDate string) (*core.TDSVendorStatus, error)
+
+	// GetTDSRegister returns the TDS withheld in a quarter (1–4) of the financial year starting
+	// in fyStartYear, with per-section totals.
+	GetTDSRegister(ctx context.Context, companyCode string, fyStartYear, quarter int) (*core.TDSRegister, error)
+
+	// ListTaxCodes returns the company's GST codes with the rates in effect on asOfDate
+	// (YYYY-MM-DD; empty = today).
+	ListTaxCodes(ctx context.Context, companyCode, asOfDate string) (*TaxCodesResult, error)
+
+	// SetTaxRate sets a GST component rate of a tax code from an effective date.
+	SetTaxRate(ctx context.Context, req SetTaxRateRequest) error
+
+	// SetCompanyGST sets the company's GST state code and GSTIN.
+	SetCompanyGST(ctx context.Context, companyCode, stateCode, gstin string) error
+
+	// SetProductTax sets a product's HSN/SAC code and default GST code; empty clears them.
+	SetProductTax(ctx context.Context, companyCode, productCode, hsnCode, taxCode string) (*core.Product, error)
+
+	// GetGSTReturn returns GSTR-1 and GSTR-3B for period (YYYY-MM; empty = last month), with
+	// the reconciliation of the returned tax to the GST ledger accounts.
+	GetGSTReturn(ctx context.Context, companyCode, period string) (*core.GSTReturn, error)
+
+	// SetEInvoicing turns e-invoicing on or off for the company and sets the seller address
+	// used on e-invoices (empty = unchanged).
+	SetEInvoicing(ctx context.Context, companyCode string, enabled bool, address string) error
+
+	// GetEInvoicePayload returns the INV-01 e-invoice payload of an invoiced B2B order.
+	GetEInvoicePayload(ctx context.Context, ref, companyCode string) (*core.EInvoicePayload, error)
+
+	// GenerateEInvoice registers an invoiced B2B order with the IRP and stores its IRN.
+	GenerateEInvoice(ctx context.Context, ref, companyCode string) (*core.EInvoice, error)
+
+	// GetEInvoice returns an order's latest e-invoice.
+	GetEInvoice(ctx context.Context, ref, companyCode string) (*core.EInvoice, error)
+
+	// CancelEInvoice cancels an order's IRN within 24 hours of generation, reversing the
+	// invoice entry and moving the order back to SHIPPED for re-invoicing.
+	CancelEInvoice(ctx context.Context, req CancelEInvoiceRequest) (*core.EInvoice, error)
 }
@@ -0,0 +1,200 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/google/uuid"
+)
+
+func TestAccounts_HierarchyAndLayouts(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	accounts := core.NewAccountService(pool)
+	reporting := core.NewReportingService(pool)
+	ctx := context.Background()
+
+	if _, err := accounts.CreateAccountGroup(ctx, "1000", "CA", "Current Assets", core.Asset, ""); err != nil {
+		t.Fatalf("CreateAccountGroup: %v", err)
+	}
+	if _, err := accounts.CreateAccountGroup(ctx, "1000", "CASH", "Cash and Bank", core.Asset, "CA"); err != nil {
+		t.Fatalf("CreateAccountGroup nested: %v", err)
+	}
+	if _, err := accounts.CreateAccountGroup(ctx, "1000", "CL", "Current Liabilities", core.Liability, "CA"); err == nil {
+		t.Error("expected error nesting a liability group under an asset group")
+	}
+	if _, err := accounts.CreateAccountGroup(ctx, "1000", "CA", "Duplicate", core.Asset, ""); err == nil {
+		t.Error("expected error for a duplicate code")
+	}
+	for account, parent := range map[string]string{"1000": "CASH", "1200": "CA"} {
+		if err := accounts.SetAccountParent(ctx, "1000", account, parent); err != nil {
+			t.Fatalf("SetAccountParent %s: %v", account, err)
+		}
+	}
+	if err := accounts.SetAccountParent(ctx, "1000", "CA", "CASH"); err == nil {
+		t.Error("expected error moving a group under its own sub-group")
+	}
+	if err := accounts.SetAccountParent(ctx, "1000", "1200", "1000"); err == nil {
+		t.Error("expected error placing an account under a leaf account")
+	}
+
+	post := func(lines ...core.ProposalLine) error {
+		return ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: "2026-01-15", DocumentDate: "2026-01-15", Summary: "hierarchy test", Reasoning: "test",
+			Lines: lines,
+		})
+	}
+	if err := post(
+		core.ProposalLine{AccountCode: "1000", IsDebit: true, Amount: "700.00"},
+		core.ProposalLine{AccountCode: "3000", IsDebit: false, Amount: "700.00"},
+	); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := post(
+		core.ProposalLine{AccountCode: "1200", IsDebit: true, Amount: "500.00"},
+		core.ProposalLine{AccountCode: "4000", IsDebit: false, Amount: "500.00"},
+	); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	err := post(
+		core.ProposalLine{AccountCode: "CASH", IsDebit: true, Amount: "10.00"},
+		core.ProposalLine{AccountCode: "4000", IsDebit: false, Amount: "10.00"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "group account") {
+		t.Errorf("expected posting to a group account to be refused, got %v", err)
+	}
+
+	t.Run("chart of accounts rollups", func(t *testing.T) {
+		tree, err := accounts.GetChartOfAccounts(ctx, "1000", "2026-01-31")
+		if err != nil {
+			t.Fatalf("GetChartOfAccounts: %v", err)
+		}
+		rollups := map[string]string{}
+		for _, n := range tree {
+			rollups[n.Code] = n.Rollup.StringFixed(2)
+		}
+		if rollups["CA"] != "1200.00" || rollups["CASH"] != "700.00" || rollups["4000"] != "500.00" {
+			t.Errorf("rollups: got %v", rollups)
+		}
+	})
+
+	t.Run("balance sheet layout", func(t *testing.T) {
+		if err := reporting.CreateStatementLayout(ctx, "1000", "sch3-bs", "Schedule III", "balance_sheet"); err != nil {
+			t.Fatalf("CreateStatementLayout: %v", err)
+		}
+		for _, l := range []core.StatementLayoutLine{
+			{Code: "EL", Label: "EQUITY AND LIABILITIES", SortOrder: 10, NormalBalance: "CREDIT"},
+			{Code: "EL1", ParentCode: "EL", Label: "Shareholders' funds", IncludesProfit: true},
+			{Code: "A", Label: "ASSETS", SortOrder: 20, NormalBalance: "DEBIT"},
+			{Code: "A2", ParentCode: "A", Label: "Current assets"},
+		} {
+			if err := reporting.SetStatementLayoutLine(ctx, "1000", "SCH3-BS", l); err != nil {
+				t.Fatalf("SetStatementLayoutLine %s: %v", l.Code, err)
+			}
+		}
+		if err := reporting.SetStatementLayoutLine(ctx, "1000", "SCH3-BS", core.StatementLayoutLine{Code: "EL", ParentCode: "EL1", Label: "loop"}); err == nil {
+			t.Error("expected error placing a line under its own sub-line")
+		}
+		if err := reporting.SetStatementLayoutAccount(ctx, "1000", "SCH3-BS", "CA", "A2"); err != nil {
+			t.Fatalf("SetStatementLayoutAccount: %v", err)
+		}
+		if err := reporting.SetStatementLayoutAccount(ctx, "1000", "SCH3-BS", "4000", "EL1"); err == nil {
+			t.Error("expected error mapping a revenue account on a balance sheet layout")
+		}
+
+		r, err := reporting.GetLayoutStatement(ctx, "1000", "SCH3-BS", "", "2026-01-31")
+		if err != nil {
+			t.Fatalf("GetLayoutStatement: %v", err)
+		}
+		if r.IsBalanced || len(r.Unmapped) != 1 || r.Unmapped[0].Code != "3000" {
+			t.Errorf("expected share capital unmapped: %+v", r.Unmapped)
+		}
+		if err := reporting.SetStatementLayoutAccount(ctx, "1000", "SCH3-BS", "3000", "EL1"); err != nil {
+			t.Fatalf("SetStatementLayoutAccount: %v", err)
+		}
+		r, err = reporting.GetLayoutStatement(ctx, "1000", "SCH3-BS", "", "2026-01-31")
+		if err != nil {
+			t.Fatalf("GetLayoutStatement: %v", err)
+		}
+		amounts := map[string]string{}
+		for _, l := range r.Lines {
+			amounts[l.Code] = l.Amount.StringFixed(2)
+		}
+		if amounts["A2"] != "1200.00" || amounts["EL1"] != "1200.00" || !r.IsBalanced {
+			t.Errorf("got %v balanced=%v", amounts, r.IsBalanced)
+		}
+	})
+}
+
+func TestAccounts_PostingControls(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	accounts := core.NewAccountService(pool)
+	ctx := context.Background()
+
+	if _, err := accounts.CreateAccount(ctx, "1000", core.AccountInput{Code: "5100", Name: "Dup", Type: core.Expense}); err == nil {
+		t.Error("expected error for a duplicate code")
+	}
+	if _, err := accounts.CreateAccount(ctx, "1000", core.AccountInput{
+		Code: "5900", Name: "Suspense", Type: core.Expense,
+		AccountControls: core.AccountControls{NarrationRequired: true},
+	}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if _, err := accounts.UpdateAccount(ctx, "1000", "1200", "Trade Receivables", core.AccountControls{ManualPostingBlocked: true}); err != nil {
+		t.Fatalf("UpdateAccount: %v", err)
+	}
+	a, err := accounts.GetAccount(ctx, "1000", "1200")
+	if err != nil || a.Name != "Trade Receivables" || !a.ManualPostingBlocked || !a.IsActive {
+		t.Fatalf("GetAccount: got %+v, %v", a, err)
+	}
+
+	proposal := func(summary string, manual bool, debit string) core.Proposal {
+		return core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: "2026-01-15", DocumentDate: "2026-01-15", Summary: summary, Reasoning: "test",
+			Manual: manual,
+			Lines: []core.ProposalLine{
+				{AccountCode: debit, IsDebit: true, Amount: "100.00"},
+				{AccountCode: "4000", IsDebit: false, Amount: "100.00"},
+			},
+		}
+	}
+	if err := ledger.Commit(ctx, proposal("manual AR", true, "1200")); err == nil || !strings.Contains(err.Error(), "control account") {
+		t.Errorf("expected a manual entry to AR to be refused, got %v", err)
+	}
+	if err := ledger.Commit(ctx, proposal("invoice", false, "1200")); err != nil {
+		t.Errorf("system posting to AR: %v", err)
+	}
+	if err := ledger.Commit(ctx, proposal("", true, "5900")); err == nil || !strings.Contains(err.Error(), "narration") {
+		t.Errorf("expected a narration to be required, got %v", err)
+	}
+
+	if err := accounts.SetAccountActive(ctx, "1000", "1200", false); err == nil {
+		t.Error("expected error deactivating an account with a balance")
+	}
+	if err := accounts.SetAccountActive(ctx, "1000", "5100", false); err != nil {
+		t.Fatalf("SetAccountActive: %v", err)
+	}
+	if err := ledger.Commit(ctx, proposal("rent", false, "5100")); err == nil || !strings.Contains(err.Error(), "inactive") {
+		t.Errorf("expected posting to an inactive account to be refused, got %v", err)
+	}
+	if _, err := accounts.UpdateAccount(ctx, "1000", "5000", "COGS", core.AccountControls{PostingBlocked: true}); err != nil {
+		t.Fatalf("UpdateAccount: %v", err)
+	}
+	if err := ledger.Commit(ctx, proposal("cogs", false, "5000")); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("expected posting to a blocked account to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountNode is one account in the chart of accounts tree. Balances are in the normal
+// sign for the account type: debit-positive for assets and expenses, credit-positive for
+// liabilities, equity and revenue.
+type AccountNode struct {
+	Code       string
+	Name       string
+	Type       AccountType
+	ParentCode string // empty for a top-level account
+	IsGroup    bool
+	IsActive   bool
+	Level      int             // depth in the tree; 0 at the top
+	Balance    decimal.Decimal // the account's own postings; zero for a group
+	Rollup     decimal.Decimal // Balance plus the balances of all descendants
+	AccountControls
+}
+
+// AccountInput is a new account. ParentCode, if set, must be a group of the same type.
+type AccountInput struct {
+	Code       string
+	Name       string
+	Type       AccountType
+	ParentCode string
+	AccountControls
+}
+
+// AccountService maintains the chart of accounts: accounts and their posting controls,
+// group accounts and the placement of accounts under them. Only active leaf (non-group)
+// accounts can be posted to.
+type AccountService interface {
+	// GetAccount returns one account with its flags.
+	GetAccount(ctx context.Context, companyCode, accountCode string) (*Account, error)
+
+	// CreateAccount adds a postable (leaf) account.
+	CreateAccount(ctx context.Context, companyCode string, input AccountInput) (*Account, error)
+
+	// UpdateAccount renames an account and replaces its posting controls. The code and type
+	// cannot change once the account exists.
+	UpdateAccount(ctx context.Context, companyCode, accountCode, name string, controls AccountControls) (*Account, error)
+
+	// SetAccountActive deactivates or reactivates an account. An account cannot be
+	// deactivated while it has a balance, active sub-accounts, or an account rule using it.
+	SetAccountActive(ctx context.Context, companyCode, accountCode string, active bool) error
+
+	// GetChartOfAccounts returns every account in tree order (each group followed by its
+	// sub-accounts), with balances and rollups as of asOfDate (empty means today).
+	GetChartOfAccounts(ctx context.Context, companyCode, asOfDate string) ([]AccountNode, error)
+
+	// CreateAccountGroup adds a group account, under parentCode if it is not empty. The
+	// parent must be a group of the same type.
+	CreateAccountGroup(ctx context.Context, companyCode, code, name string, accountType AccountType, parentCode string) (*Account, error)
+
+	// SetAccountParent moves an account under the group parentCode, or to the top level
+	// when parentCode is empty. The parent must be a group of the same type and must not be
+	// the account itself or one of its descendants.
+	SetAccountParent(ctx context.Context, companyCode, accountCode, parentCode string) error
+}
@@ -0,0 +1,346 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type accountService struct {
+	pool *pgxpool.Pool
+}
+
+// NewAccountService constructs an AccountService backed by PostgreSQL.
+func NewAccountService(pool *pgxpool.Pool) AccountService {
+	return &accountService{pool: pool}
+}
+
+func (s *accountService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
+
+// queryAccountNodes returns the company's accounts with their parent codes, and the net
+// debit (debit − credit) of each account from journal lines posted in [fromDate, toDate].
+// An empty fromDate means from the beginning. Balance, Level and Rollup are not set.
+func queryAccountNodes(ctx context.Context, pool *pgxpool.Pool, companyID int, fromDate, toDate string) ([]AccountNode, map[string]decimal.Decimal, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, COALESCE(p.code, ''), a.is_group, a.is_active,
+		       a.posting_blocked, a.manual_posting_blocked, a.narration_required,
+		       COALESCE(b.net, 0)
+		FROM accounts a
+		LEFT JOIN accounts p ON p.id = a.parent_id
+		LEFT JOIN (
+		    SELECT jl.account_id, SUM(jl.debit_base) - SUM(jl.credit_base) AS net
+		    FROM journal_lines jl
+		    JOIN journal_entries je ON je.id = jl.entry_id
+		    WHERE je.company_id = $1
+		      AND ($2 = '' OR je.posting_date >= $2::date)
+		      AND je.posting_date <= $3::date
+		    GROUP BY jl.account_id
+		) b ON b.account_id = a.id
+		WHERE a.company_id = $1
+		ORDER BY a.code`, companyID, fromDate, toDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []AccountNode
+	nets := map[string]decimal.Decimal{}
+	for rows.Next() {
+		var a AccountNode
+		var net decimal.Decimal
+		if err := rows.Scan(&a.Code, &a.Name, &a.Type, &a.ParentCode, &a.IsGroup, &a.IsActive,
+			&a.PostingBlocked, &a.ManualPostingBlocked, &a.NarrationRequired, &net); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, a)
+		nets[a.Code] = net
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("account row iteration error: %w", err)
+	}
+	return accounts, nets, nil
+}
+
+// ── GetChartOfAccounts ────────────────────────────────────────────────────────
+
+func (s *accountService) GetChartOfAccounts(ctx context.Context, companyCode, asOfDate string) ([]AccountNode, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	asOf, err := parseReportDate(asOfDate)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, nets, err := queryAccountNodes(ctx, s.pool, companyID, "", asOf.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		a := &accounts[i]
+		a.Balance = nets[a.Code]
+		if !a.Type.IsDebitNormal() {
+			a.Balance = a.Balance.Neg()
+		}
+	}
+	return BuildAccountTree(accounts), nil
+}
+
+// ── Accounts ──────────────────────────────────────────────────────────────────
+
+const accountColumns = `id, company_id, code, name, type, parent_id, is_group, is_active,
+	posting_blocked, manual_posting_blocked, narration_required`
+
+func scanAccount(row pgx.Row) (*Account, error) {
+	var a Account
+	err := row.Scan(&a.ID, &a.CompanyID, &a.Code, &a.Name, &a.Type, &a.ParentID, &a.IsGroup, &a.IsActive,
+		&a.PostingBlocked, &a.ManualPostingBlocked, &a.NarrationRequired)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (s *accountService) GetAccount(ctx context.Context, companyCode, accountCode string) (*Account, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	a, err := scanAccount(s.pool.QueryRow(ctx,
+		"SELECT "+accountColumns+" FROM accounts WHERE company_id = $1 AND code = $2",
+		companyID, accountCode,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account %s not found", accountCode)
+		}
+		return nil, fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+	return a, nil
+}
+
+func (s *accountService) CreateAccount(ctx context.Context, companyCode string, input AccountInput) (*Account, error) {
+	return s.insertAccount(ctx, companyCode, input, false)
+}
+
+// insertAccount creates a leaf or group account. Groups carry no posting controls.
+func (s *accountService) insertAccount(ctx context.Context, companyCode string, input AccountInput, isGroup bool) (*Account, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	code, name := strings.TrimSpace(input.Code), strings.TrimSpace(input.Name)
+	if code == "" || name == "" {
+		return nil, fmt.Errorf("account code and name are required")
+	}
+	accountType, err := ParseAccountType(string(input.Type))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Account{CompanyID: companyID, Code: code, Name: name, Type: accountType, IsGroup: isGroup, IsActive: true}
+	if !isGroup {
+		a.AccountControls = input.AccountControls
+	}
+	if input.ParentCode != "" {
+		parentID, err := s.groupAccount(ctx, companyID, input.ParentCode, accountType)
+		if err != nil {
+			return nil, err
+		}
+		a.ParentID = &parentID
+	}
+
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO accounts (company_id, code, name, type, is_group, parent_id,
+		                      posting_blocked, manual_posting_blocked, narration_required)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (company_id, code) DO NOTHING
+		RETURNING id`,
+		companyID, code, name, string(accountType), isGroup, a.ParentID,
+		a.PostingBlocked, a.ManualPostingBlocked, a.NarrationRequired,
+	).Scan(&a.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account %s already exists", code)
+		}
+		return nil, fmt.Errorf("failed to create account %s: %w", code, err)
+	}
+	return a, nil
+}
+
+func (s *accountService) UpdateAccount(ctx context.Context, companyCode, accountCode, name string, controls AccountControls) (*Account, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("account name is required")
+	}
+
+	// Posting controls are meaningless on a group, which cannot be posted to at all.
+	a, err := scanAccount(s.pool.QueryRow(ctx, `
+		UPDATE accounts SET name = $3,
+		       posting_blocked        = $4 AND NOT is_group,
+		       manual_posting_blocked = $5 AND NOT is_group,
+		       narration_required     = $6 AND NOT is_group
+		WHERE company_id = $1 AND code = $2
+		RETURNING `+accountColumns,
+		companyID, accountCode, name, controls.PostingBlocked, controls.ManualPostingBlocked, controls.NarrationRequired,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account %s not found", accountCode)
+		}
+		return nil, fmt.Errorf("failed to update account %s: %w", accountCode, err)
+	}
+	return a, nil
+}
+
+func (s *accountService) SetAccountActive(ctx context.Context, companyCode, accountCode string, active bool) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+
+	var accountID int
+	err = s.pool.QueryRow(ctx,
+		"SELECT id FROM accounts WHERE company_id = $1 AND code = $2", companyID, accountCode,
+	).Scan(&accountID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("account %s not found", accountCode)
+		}
+		return fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+
+	if !active {
+		var balance decimal.Decimal
+		var activeChildren, rules int
+		err = s.pool.QueryRow(ctx, `
+			SELECT
+			    (SELECT COALESCE(SUM(debit_base) - SUM(credit_base), 0) FROM journal_lines WHERE account_id = $1),
+			    (SELECT count(*) FROM accounts WHERE parent_id = $1 AND is_active),
+			    (SELECT count(*) FROM account_rules
+			     WHERE company_id = $2 AND account_code = $3
+			       AND (effective_to IS NULL OR effective_to >= CURRENT_DATE))`,
+			accountID, companyID, accountCode,
+		).Scan(&balance, &activeChildren, &rules)
+		if err != nil {
+			return fmt.Errorf("failed to check account %s: %w", accountCode, err)
+		}
+		switch {
+		case !balance.IsZero():
+			return fmt.Errorf("account %s has a balance of %s: clear it before deactivating", accountCode, balance.StringFixed(2))
+		case activeChildren > 0:
+			return fmt.Errorf("account %s has %d active sub-accounts: move or deactivate them first", accountCode, activeChildren)
+		case rules > 0:
+			return fmt.Errorf("account %s is used by an account rule: point the rule at another account first", accountCode)
+		}
+	}
+
+	if _, err := s.pool.Exec(ctx,
+		"UPDATE accounts SET is_active = $1 WHERE id = $2", active, accountID,
+	); err != nil {
+		return fmt.Errorf("failed to set active status of account %s: %w", accountCode, err)
+	}
+	return nil
+}
+
+// ── Groups ────────────────────────────────────────────────────────────────────
+
+// groupAccount returns the id of parentCode after checking that it is a group of the given type.
+func (s *accountService) groupAccount(ctx context.Context, companyID int, parentCode string, accountType AccountType) (int, error) {
+	var id int
+	var isGroup bool
+	var parentType AccountType
+	err := s.pool.QueryRow(ctx,
+		"SELECT id, is_group, type FROM accounts WHERE company_id = $1 AND code = $2",
+		companyID, parentCode,
+	).Scan(&id, &isGroup, &parentType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("parent account %s not found", parentCode)
+		}
+		return 0, fmt.Errorf("failed to fetch parent account %s: %w", parentCode, err)
+	}
+	if !isGroup {
+		return 0, fmt.Errorf("parent account %s is not a group account", parentCode)
+	}
+	if parentType != accountType {
+		return 0, fmt.Errorf("parent account %s is a %s group; cannot hold a %s account", parentCode, parentType, accountType)
+	}
+	return id, nil
+}
+
+func (s *accountService) CreateAccountGroup(ctx context.Context, companyCode, code, name string, accountType AccountType, parentCode string) (*Account, error) {
+	return s.insertAccount(ctx, companyCode, AccountInput{Code: code, Name: name, Type: accountType, ParentCode: parentCode}, true)
+}
+
+func (s *accountService) SetAccountParent(ctx context.Context, companyCode, accountCode, parentCode string) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+
+	var accountID int
+	var accountType AccountType
+	err = s.pool.QueryRow(ctx,
+		"SELECT id, type FROM accounts WHERE company_id = $1 AND code = $2",
+		companyID, accountCode,
+	).Scan(&accountID, &accountType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("account %s not found", accountCode)
+		}
+		return fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+
+	var parentID *int
+	if parentCode != "" {
+		id, err := s.groupAccount(ctx, companyID, parentCode, accountType)
+		if err != nil {
+			return err
+		}
+		// The new parent must not sit beneath the account being moved.
+		var cycle bool
+		if err := s.pool.QueryRow(ctx, `
+			WITH RECURSIVE ancestors AS (
+			    SELECT id, parent_id FROM accounts WHERE id = $1
+			    UNION
+			    SELECT a.id, a.parent_id FROM accounts a JOIN ancestors an ON a.id = an.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`,
+			id, accountID,
+		).Scan(&cycle); err != nil {
+			return fmt.Errorf("failed to check account hierarchy: %w", err)
+		}
+		if cycle {
+			return fmt.Errorf("account %s cannot be moved under itself or one of its sub-accounts", accountCode)
+		}
+		parentID = &id
+	}
+
+	if _, err := s.pool.Exec(ctx,
+		"UPDATE accounts SET parent_id = $1 WHERE id = $2", parentID, accountID,
+	); err != nil {
+		return fmt.Errorf("failed to set parent of account %s: %w", accountCode, err)
+	}
+	return nil
+}
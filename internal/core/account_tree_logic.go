@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseAccountType normalises an account type name.
+func ParseAccountType(s string) (AccountType, error) {
+	t := AccountType(strings.ToLower(strings.TrimSpace(s)))
+	switch t {
+	case Asset, Liability, Equity, Revenue, Expense:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid account type %q: expected asset, liability, equity, revenue or expense", s)
+}
+
+// IsDebitNormal reports whether accounts of type t carry debit balances.
+func (t AccountType) IsDebitNormal() bool {
+	return t == Asset || t == Expense
+}
+
+// BuildAccountTree orders accounts depth-first, children by code under their parent, and
+// sets each node's Level and Rollup. Accounts whose parent is missing are placed at the top.
+func BuildAccountTree(accounts []AccountNode) []AccountNode {
+	known := make(map[string]bool, len(accounts))
+	for _, a := range accounts {
+		known[a.Code] = true
+	}
+	children := map[string][]AccountNode{}
+	for _, a := range accounts {
+		parent := a.ParentCode
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], a)
+	}
+	for _, c := range children {
+		sort.Slice(c, func(i, j int) bool { return c[i].Code < c[j].Code })
+	}
+
+	out := make([]AccountNode, 0, len(accounts))
+	var walk func(parent string, level int)
+	walk = func(parent string, level int) {
+		for _, a := range children[parent] {
+			i := len(out)
+			a.Level = level
+			out = append(out, a)
+			walk(a.Code, level+1)
+			// Rollup is the node's own balance plus every node added beneath it.
+			out[i].Rollup = out[i].Balance
+			for _, d := range out[i+1:] {
+				out[i].Rollup = out[i].Rollup.Add(d.Balance)
+			}
+		}
+	}
+	walk("", 0)
+	return out
+}
+
+// CheckAccountPosting reports whether a journal line of proposal may post to accountCode,
+// given the account's active flag and posting controls.
+func CheckAccountPosting(accountCode string, isActive bool, controls AccountControls, proposal Proposal) error {
+	switch {
+	case !isActive:
+		return fmt.Errorf("account %s is inactive", accountCode)
+	case controls.PostingBlocked:
+		return fmt.Errorf("account %s is blocked for posting", accountCode)
+	case controls.ManualPostingBlocked && proposal.Manual:
+		return fmt.Errorf("account %s is a control account: it is posted only by document workflows (orders, receipts, bills, payments), not by manual journal entries", accountCode)
+	case controls.NarrationRequired && strings.TrimSpace(proposal.Summary) == "":
+		return fmt.Errorf("account %s requires a narration: add a summary to the entry", accountCode)
+	}
+	return nil
+}
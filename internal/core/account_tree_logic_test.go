@@ -0,0 +1,43 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+)
+
+func TestCheckAccountPosting(t *testing.T) {
+	manual := core.Proposal{Summary: "Office rent", Manual: true}
+	system := core.Proposal{Summary: "Invoice SO-1"}
+
+	tests := []struct {
+		name     string
+		active   bool
+		controls core.AccountControls
+		proposal core.Proposal
+		wantErr  string
+	}{
+		{"open account", true, core.AccountControls{}, manual, ""},
+		{"inactive", false, core.AccountControls{}, system, "inactive"},
+		{"blocked for system postings too", true, core.AccountControls{PostingBlocked: true}, system, "blocked"},
+		{"control account, manual entry", true, core.AccountControls{ManualPostingBlocked: true}, manual, "control account"},
+		{"control account, system entry", true, core.AccountControls{ManualPostingBlocked: true}, system, ""},
+		{"narration required and given", true, core.AccountControls{NarrationRequired: true}, manual, ""},
+		{"narration required and blank", true, core.AccountControls{NarrationRequired: true}, core.Proposal{Summary: "  "}, "narration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := core.CheckAccountPosting("1200", tt.active, tt.controls, tt.proposal)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
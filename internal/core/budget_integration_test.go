@@ -0,0 +1,147 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestBudget_ImportCopyAndVariance(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	reporting := core.NewReportingService(pool)
+	budgets := core.NewBudgetService(pool)
+	ctx := context.Background()
+
+	post := func(date, debit, credit, amount string) {
+		t.Helper()
+		if err := ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: date, DocumentDate: date, Summary: "budget test", Reasoning: "test",
+			Lines: []core.ProposalLine{
+				{AccountCode: debit, IsDebit: true, Amount: amount},
+				{AccountCode: credit, IsDebit: false, Amount: amount},
+			},
+		}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	// FY 2025-26 actuals, to copy from.
+	post("2025-04-10", "1000", "4000", "1000.00")
+	post("2025-04-12", "5100", "1000", "200.00")
+	// FY 2026-27 Q1 (April–June 2026) actuals.
+	post("2026-04-10", "1000", "4000", "1300.00")
+	post("2026-05-05", "5100", "1000", "450.00")
+	post("2026-06-05", "5000", "1000", "90.00")
+	if err := reporting.RefreshViews(ctx); err != nil {
+		t.Fatalf("RefreshViews failed: %v", err)
+	}
+
+	if _, err := budgets.CreateCostCenter(ctx, "1000", "mkt", "Marketing"); err != nil {
+		t.Fatalf("CreateCostCenter: %v", err)
+	}
+	if _, err := budgets.CreateCostCenter(ctx, "1000", "MKT", ""); err == nil {
+		t.Error("expected error for duplicate cost center")
+	}
+	if _, err := budgets.CreateBudget(ctx, "1000", core.BudgetInput{Code: "orig", FinancialYear: 2026}); err != nil {
+		t.Fatalf("CreateBudget: %v", err)
+	}
+
+	t.Run("import rejects months outside the financial year", func(t *testing.T) {
+		lines, err := core.ParseBudgetCSV(strings.NewReader("account_code,month,amount\n5100,2026-03,100\n"))
+		if err != nil {
+			t.Fatalf("ParseBudgetCSV: %v", err)
+		}
+		if _, err := budgets.SetBudgetLines(ctx, "1000", "ORIG", lines, false); err == nil {
+			t.Error("expected error for March 2026 in FY 2026-27")
+		}
+	})
+
+	t.Run("copy from prior-year actuals with uplift", func(t *testing.T) {
+		n, err := budgets.CopyBudgetFromActuals(ctx, "1000", "ORIG", 2025, decimal.NewFromInt(10))
+		if err != nil {
+			t.Fatalf("CopyBudgetFromActuals: %v", err)
+		}
+		if n != 2 {
+			t.Fatalf("copied %d lines, want 2", n)
+		}
+		b, err := budgets.GetBudget(ctx, "1000", "ORIG")
+		if err != nil {
+			t.Fatalf("GetBudget: %v", err)
+		}
+		for _, l := range b.Lines {
+			if l.Year != 2026 || l.Month != 4 {
+				t.Errorf("line not moved to April 2026: %+v", l)
+			}
+			want := map[string]string{"4000": "1100.00", "5100": "220.00"}[l.AccountCode]
+			if l.Amount.StringFixed(2) != want {
+				t.Errorf("account %s: got %s, want %s", l.AccountCode, l.Amount.StringFixed(2), want)
+			}
+		}
+	})
+
+	t.Run("budget vs actual for a quarter", func(t *testing.T) {
+		lines, err := core.ParseBudgetCSV(strings.NewReader(
+			"account_code,cost_center,2026-05,2026-06\n5100,MKT,100,100\n5100,,50,\n"))
+		if err != nil {
+			t.Fatalf("ParseBudgetCSV: %v", err)
+		}
+		if _, err := budgets.SetBudgetLines(ctx, "1000", "ORIG", lines, false); err != nil {
+			t.Fatalf("SetBudgetLines: %v", err)
+		}
+
+		period, _ := core.ParseReportPeriod("2026-04-05..2026-06-30")
+		if _, err := budgets.GetBudgetVsActual(ctx, "1000", "", period, nil); err == nil {
+			t.Error("expected error for a period that does not start on the 1st")
+		}
+		period, _ = core.ParseReportPeriod("2026-Q2")
+		report, err := budgets.GetBudgetVsActual(ctx, "1000", "", period, nil)
+		if err != nil {
+			t.Fatalf("GetBudgetVsActual: %v", err)
+		}
+		if report.BudgetCode != "ORIG" || !report.ThresholdPct.Equal(decimal.NewFromInt(10)) {
+			t.Errorf("expected the ORIG budget with its 10%% threshold: got %s %s", report.BudgetCode, report.ThresholdPct)
+		}
+		byCode := map[string]core.BudgetVsActualLine{}
+		for _, l := range report.Lines {
+			byCode[l.AccountCode] = l
+		}
+		// Operating expense: 220 + 100 + 100 + 50 budgeted across cost centers, 450 spent.
+		opex := byCode["5100"]
+		if opex.Budget.StringFixed(2) != "470.00" || opex.Actual.StringFixed(2) != "450.00" || opex.Status != core.BudgetStatusOnTrack {
+			t.Errorf("5100: got %+v", opex)
+		}
+		// Revenue 1300 against 1100 is 18.18% over, which is favourable.
+		rev := byCode["4000"]
+		if rev.Status != core.BudgetStatusOver || !rev.Favourable || rev.VariancePct.StringFixed(2) != "18.18" {
+			t.Errorf("4000: got %+v", rev)
+		}
+		// COGS was not budgeted but is listed as over budget.
+		if cogs, ok := byCode["5000"]; !ok || cogs.Status != core.BudgetStatusOver || cogs.VariancePct != nil {
+			t.Errorf("5000: got %+v (listed %v)", cogs, ok)
+		}
+		if report.NetIncome.Budget.StringFixed(2) != "630.00" || report.NetIncome.Actual.StringFixed(2) != "760.00" {
+			t.Errorf("net income: got %+v", report.NetIncome)
+		}
+
+		tight := decimal.NewFromInt(1)
+		report, err = budgets.GetBudgetVsActual(ctx, "1000", "ORIG", period, &tight)
+		if err != nil {
+			t.Fatalf("GetBudgetVsActual: %v", err)
+		}
+		for _, l := range report.Lines {
+			if l.AccountCode == "5100" && l.Status != core.BudgetStatusUnder {
+				t.Errorf("5100 at a 1%% threshold: got %s", l.Status)
+			}
+		}
+	})
+}
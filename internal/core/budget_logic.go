@@ -0,0 +1,168 @@
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ParseBudgetCSV reads budget lines from CSV with a header row. account_code is required
+// and cost_center optional. Amounts are given either in long form, with a month (YYYY-MM)
+// and an amount column per row, or in wide form, with one column per month headed YYYY-MM;
+// blank wide cells are skipped. A month may appear only once per account and cost center.
+func ParseBudgetCSV(r io.Reader) ([]BudgetLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("budget CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budget CSV header: %w", err)
+	}
+
+	accountCol, costCenterCol, monthCol, amountCol := -1, -1, -1, -1
+	type monthColumn struct {
+		col         int
+		year, month int
+	}
+	var monthCols []monthColumn
+	for i, h := range header {
+		switch name := strings.ToLower(strings.TrimSpace(h)); name {
+		case "account_code", "account":
+			accountCol = i
+		case "cost_center", "cost_center_code":
+			costCenterCol = i
+		case "month":
+			monthCol = i
+		case "amount":
+			amountCol = i
+		default:
+			m, err := time.Parse("2006-01", name)
+			if err != nil {
+				return nil, fmt.Errorf("unknown budget CSV column %q", h)
+			}
+			monthCols = append(monthCols, monthColumn{i, m.Year(), int(m.Month())})
+		}
+	}
+	if accountCol < 0 {
+		return nil, fmt.Errorf("budget CSV needs an account_code column")
+	}
+	long := monthCol >= 0 || amountCol >= 0
+	if long && (monthCol < 0 || amountCol < 0 || len(monthCols) > 0) {
+		return nil, fmt.Errorf("budget CSV needs either month and amount columns or one YYYY-MM column per month")
+	}
+	if !long && len(monthCols) == 0 {
+		return nil, fmt.Errorf("budget CSV has no amount columns")
+	}
+
+	type lineKey struct {
+		account, costCenter string
+		year, month         int
+	}
+	seen := map[lineKey]bool{}
+	var lines []BudgetLine
+	add := func(lineNo int, account, costCenter string, year, month int, cell string) error {
+		amount, err := decimal.NewFromString(strings.TrimSpace(cell))
+		if err != nil {
+			return fmt.Errorf("line %d: invalid amount %q", lineNo, cell)
+		}
+		key := lineKey{account, costCenter, year, month}
+		if seen[key] {
+			return fmt.Errorf("line %d: account %s has more than one amount for %04d-%02d", lineNo, account, year, month)
+		}
+		seen[key] = true
+		lines = append(lines, BudgetLine{AccountCode: account, CostCenterCode: costCenter, Year: year, Month: month, Amount: amount})
+		return nil
+	}
+
+	for lineNo := 2; ; lineNo++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("line %d: expected %d fields", lineNo, len(header))
+			}
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		account := strings.TrimSpace(record[accountCol])
+		if account == "" {
+			return nil, fmt.Errorf("line %d: account_code is required", lineNo)
+		}
+		costCenter := ""
+		if costCenterCol >= 0 {
+			costCenter = strings.TrimSpace(record[costCenterCol])
+		}
+
+		if long {
+			m, err := time.Parse("2006-01", strings.TrimSpace(record[monthCol]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid month %q (expected YYYY-MM)", lineNo, record[monthCol])
+			}
+			if err := add(lineNo, account, costCenter, m.Year(), int(m.Month()), record[amountCol]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, mc := range monthCols {
+			if strings.TrimSpace(record[mc.col]) == "" {
+				continue
+			}
+			if err := add(lineNo, account, costCenter, mc.year, mc.month, record[mc.col]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("budget CSV has no amounts")
+	}
+	return lines, nil
+}
+
+// inFinancialYear reports whether a calendar month falls in the financial year fy.
+func inFinancialYear(fy, year, month int) bool {
+	return FinancialYear(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)) == fy
+}
+
+// NewBudgetVsActualLine compares budget and actual, both in the account's normal sign,
+// against a variance threshold in percent. A line with no budget is over (or under) budget
+// as soon as it has any positive (or negative) actual.
+func NewBudgetVsActualLine(code, name string, accType AccountType, budget, actual, thresholdPct decimal.Decimal) BudgetVsActualLine {
+	line := BudgetVsActualLine{
+		AccountCode: code,
+		AccountName: name,
+		AccountType: accType,
+		Budget:      budget,
+		Actual:      actual,
+		Variance:    actual.Sub(budget),
+		Status:      BudgetStatusOnTrack,
+	}
+	outside := !line.Variance.IsZero()
+	if !budget.IsZero() {
+		pct := line.Variance.Div(budget.Abs()).Mul(decimal.NewFromInt(100)).Round(2)
+		line.VariancePct = &pct
+		outside = pct.Abs().GreaterThan(thresholdPct)
+	}
+	if outside {
+		if line.Variance.IsPositive() {
+			line.Status = BudgetStatusOver
+		} else {
+			line.Status = BudgetStatusUnder
+		}
+	}
+	// Spending less, or earning more, than budgeted is favourable.
+	if accType == Expense {
+		line.Favourable = !line.Variance.IsPositive()
+	} else {
+		line.Favourable = !line.Variance.IsNegative()
+	}
+	return line
+}
@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseBudgetCSV(t *testing.T) {
+	t.Run("long form", func(t *testing.T) {
+		lines, err := core.ParseBudgetCSV(strings.NewReader(
+			"account_code,cost_center,month,amount\n" +
+				"5100,MKT,2026-04,1200.50\n" +
+				"5100,,2026-05,800\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines, want 2", len(lines))
+		}
+		l := lines[0]
+		if l.AccountCode != "5100" || l.CostCenterCode != "MKT" || l.Year != 2026 || l.Month != 4 ||
+			!l.Amount.Equal(decimal.RequireFromString("1200.50")) {
+			t.Errorf("first line: got %+v", l)
+		}
+		if lines[1].CostCenterCode != "" || lines[1].Month != 5 {
+			t.Errorf("second line: got %+v", lines[1])
+		}
+	})
+
+	t.Run("wide form skips blank cells", func(t *testing.T) {
+		lines, err := core.ParseBudgetCSV(strings.NewReader(
+			"Account,2026-04,2026-05,2027-03\n" +
+				"4000,10000,,12000\n" +
+				"5100,500,500,500\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(lines) != 5 {
+			t.Fatalf("got %d lines, want 5", len(lines))
+		}
+		if lines[1].AccountCode != "4000" || lines[1].Year != 2027 || lines[1].Month != 3 {
+			t.Errorf("blank May cell should be skipped: got %+v", lines[1])
+		}
+	})
+
+	bad := map[string]string{
+		"empty":          "",
+		"no account":     "month,amount\n2026-04,1\n",
+		"unknown column": "account_code,month,amount,note\n5100,2026-04,1,x\n",
+		"mixed forms":    "account_code,month,amount,2026-05\n5100,2026-04,1,2\n",
+		"bad month":      "account_code,month,amount\n5100,April,1\n",
+		"bad amount":     "account_code,2026-04\n5100,1.2.3\n",
+		"duplicate":      "account_code,month,amount\n5100,2026-04,1\n5100,2026-04,2\n",
+		"short row":      "account_code,month,amount\n5100,2026-04\n",
+		"no amounts":     "account_code,2026-04\n5100,\n",
+	}
+	for name, in := range bad {
+		if _, err := core.ParseBudgetCSV(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewBudgetVsActualLine(t *testing.T) {
+	d := decimal.RequireFromString
+	ten := d("10")
+	cases := []struct {
+		name           string
+		accType        core.AccountType
+		budget, actual string
+		status         string
+		favourable     bool
+		pct            string // empty when VariancePct is nil
+	}{
+		{"expense within threshold", core.Expense, "1000", "1080", core.BudgetStatusOnTrack, false, "8"},
+		{"expense over", core.Expense, "1000", "1150", core.BudgetStatusOver, false, "15"},
+		{"expense under", core.Expense, "1000", "700", core.BudgetStatusUnder, true, "-30"},
+		{"revenue over is favourable", core.Revenue, "5000", "6000", core.BudgetStatusOver, true, "20"},
+		{"revenue short", core.Revenue, "5000", "4000", core.BudgetStatusUnder, false, "-20"},
+		{"unbudgeted spend", core.Expense, "0", "50", core.BudgetStatusOver, false, ""},
+		{"exactly at threshold", core.Expense, "1000", "1100", core.BudgetStatusOnTrack, false, "10"},
+	}
+	for _, c := range cases {
+		line := core.NewBudgetVsActualLine("X", "X", c.accType, d(c.budget), d(c.actual), ten)
+		if line.Status != c.status || line.Favourable != c.favourable {
+			t.Errorf("%s: got status %s favourable %v", c.name, line.Status, line.Favourable)
+		}
+		if !line.Variance.Equal(d(c.actual).Sub(d(c.budget))) {
+			t.Errorf("%s: variance %s", c.name, line.Variance)
+		}
+		switch {
+		case c.pct == "" && line.VariancePct != nil:
+			t.Errorf("%s: expected no variance %%, got %s", c.name, line.VariancePct)
+		case c.pct != "" && (line.VariancePct == nil || !line.VariancePct.Equal(d(c.pct))):
+			t.Errorf("%s: variance %% got %v, want %s", c.name, line.VariancePct, c.pct)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultBudgetVarianceThresholdPct is the variance tolerance of a new budget when none is
+// given: actuals within 10% of the budget are on track.
+var DefaultBudgetVarianceThresholdPct = decimal.NewFromInt(10)
+
+// Budget statuses of a budget-vs-actual line.
+const (
+	BudgetStatusOnTrack = "ON_TRACK"
+	BudgetStatusOver    = "OVER"
+	BudgetStatusUnder   = "UNDER"
+)
+
+// CostCenter is a responsibility unit of a company (a department or site) that budget
+// amounts can be set for: a value of the COST_CENTER dimension.
+type CostCenter struct {
+	ID       int
+	Code     string
+	Name     string
+	IsActive bool
+}
+
+// Budget is a named budget version for one financial year. Lines is only filled by GetBudget.
+type Budget struct {
+	ID                   int
+	Code                 string
+	Name                 string
+	FinancialYear        int             // year the financial year starts in (2026 = FY 2026-27)
+	VarianceThresholdPct decimal.Decimal // default tolerance of the budget-vs-actual report
+	Total                decimal.Decimal // sum of all lines
+	CreatedAt            time.Time
+	Lines                []BudgetLine
+}
+
+// BudgetInput is a new budget version. A nil VarianceThresholdPct means
+// DefaultBudgetVarianceThresholdPct.
+type BudgetInput struct {
+	Code                 string
+	Name                 string
+	FinancialYear        int
+	VarianceThresholdPct *decimal.Decimal
+	CreatedByUserID      *int
+}
+
+// BudgetLine is the budget of one account, optionally for one cost center, in one calendar
+// month. Amount is in the account's normal sign: debit-positive for expenses and assets,
+// credit-positive for revenue, liabilities and equity.
+type BudgetLine struct {
+	AccountCode    string
+	AccountName    string
+	CostCenterCode string // empty for the account as a whole
+	Year           int
+	Month          int
+	Amount         decimal.Decimal
+}
+
+// BudgetVsActualLine compares an account's budget with its actuals over the report period,
+// both in the account's normal sign. Variance is Actual − Budget and VariancePct that
+// variance as a percentage of |Budget| (nil when the budget is zero). Status is OVER or
+// UNDER when the actual differs from the budget by more than the threshold, ON_TRACK
+// otherwise. Favourable is true when the variance helps profit: revenue above budget, or
+// spending below it.
+type BudgetVsActualLine struct {
+	AccountCode string
+	AccountName string
+	AccountType AccountType
+	Budget      decimal.Decimal
+	Actual      decimal.Decimal
+	Variance    decimal.Decimal
+	VariancePct *decimal.Decimal
+	Status      string
+	Favourable  bool
+}
+
+// BudgetVsActualReport compares a budget with the actuals of whole calendar months. Lines
+// lists every account with a budget in the period, and every revenue and expense account
+// with actuals. The totals use the same threshold; NetIncome is revenue less expenses.
+type BudgetVsActualReport struct {
+	CompanyCode   string
+	BudgetCode    string
+	BudgetName    string
+	FinancialYear string // e.g. 2026-27
+	Period        ReportPeriod
+	ThresholdPct  decimal.Decimal
+	Lines         []BudgetVsActualLine
+	Revenue       BudgetVsActualLine
+	Expenses      BudgetVsActualLine
+	NetIncome     BudgetVsActualLine
+}
+
+// BudgetService maintains cost centers and budget versions and compares budgets with
+// actuals from mv_account_period_balances.
+type BudgetService interface {
+	// ListCostCenters returns the company's cost centers, ordered by code.
+	ListCostCenters(ctx context.Context, companyCode string) ([]CostCenter, error)
+
+	// CreateCostCenter adds a cost center, a value of the COST_CENTER dimension.
+	CreateCostCenter(ctx context.Context, companyCode, code, name string) (*CostCenter, error)
+
+	// ListBudgets returns the company's budget versions with their totals, latest
+	// financial year first.
+	ListBudgets(ctx context.Context, companyCode string) ([]Budget, error)
+
+	// CreateBudget adds an empty budget version.
+	CreateBudget(ctx context.Context, companyCode string, input BudgetInput) (*Budget, error)
+
+	// GetBudget returns a budget version with its lines, ordered by account, cost center
+	// and month.
+	GetBudget(ctx context.Context, companyCode, budgetCode string) (*Budget, error)
+
+	// SetBudgetLines writes budget amounts, replacing the amount of an existing line for the
+	// same account, cost center and month. With replace set, all other lines of the budget
+	// are deleted first. Every month must fall in the budget's financial year, accounts must
+	// be postable and cost centers active. Returns the number of lines written.
+	SetBudgetLines(ctx context.Context, companyCode, budgetCode string, lines []BudgetLine, replace bool) (int, error)
+
+	// CopyBudgetFromActuals replaces the lines of a budget with the revenue and expense
+	// actuals of the financial year sourceYear, month by month, increased by upliftPct
+	// percent (negative to decrease). Actuals are read from mv_account_period_balances, so
+	// call RefreshViews first for postings since the last refresh. Returns the number of
+	// lines written.
+	CopyBudgetFromActuals(ctx context.Context, companyCode, budgetCode string, sourceYear int, upliftPct decimal.Decimal) (int, error)
+
+	// GetBudgetVsActual compares a budget with actuals over a period of whole calendar
+	// months, read from mv_account_period_balances. An empty budgetCode picks the latest
+	// budget for the financial year the period starts in; a nil thresholdPct uses the
+	// budget's own threshold.
+	GetBudgetVsActual(ctx context.Context, companyCode, budgetCode string, period ReportPeriod, thresholdPct *decimal.Decimal) (*BudgetVsActualReport, error)
+}
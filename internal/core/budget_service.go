@@ -0,0 +1,471 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type budgetService struct {
+	pool *pgxpool.Pool
+}
+
+// NewBudgetService constructs a BudgetService backed by PostgreSQL.
+func NewBudgetService(pool *pgxpool.Pool) BudgetService {
+	return &budgetService{pool: pool}
+}
+
+func (s *budgetService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
+
+// ── Cost centers ──────────────────────────────────────────────────────────────
+
+func (s *budgetService) ListCostCenters(ctx context.Context, companyCode string) ([]CostCenter, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT v.id, v.code, v.name, v.is_active
+		FROM dimension_values v
+		JOIN dimensions d ON d.id = v.dimension_id
+		WHERE d.company_id = $1 AND d.code = $2
+		ORDER BY v.code`, companyID, DimensionCostCenter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cost centers: %w", err)
+	}
+	defer rows.Close()
+
+	var centers []CostCenter
+	for rows.Next() {
+		var c CostCenter
+		if err := rows.Scan(&c.ID, &c.Code, &c.Name, &c.IsActive); err != nil {
+			return nil, fmt.Errorf("failed to scan cost center: %w", err)
+		}
+		centers = append(centers, c)
+	}
+	return centers, rows.Err()
+}
+
+func (s *budgetService) CreateCostCenter(ctx context.Context, companyCode, code, name string) (*CostCenter, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("cost center code is required")
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = code
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureSystemDimensions(ctx, s.pool, companyID); err != nil {
+		return nil, err
+	}
+
+	c := CostCenter{Code: code, Name: name, IsActive: true}
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO dimension_values (dimension_id, code, name)
+		SELECT id, $3, $4 FROM dimensions WHERE company_id = $1 AND code = $2
+		ON CONFLICT (dimension_id, code) DO NOTHING
+		RETURNING id`, companyID, DimensionCostCenter, code, name).Scan(&c.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("cost center %s already exists", code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create cost center: %w", err)
+	}
+	return &c, nil
+}
+
+// ── Budget versions ───────────────────────────────────────────────────────────
+
+const budgetColumns = `b.id, b.code, b.name, b.financial_year, b.variance_threshold_pct, b.created_at,
+	COALESCE((SELECT SUM(amount) FROM budget_lines WHERE budget_id = b.id), 0)`
+
+func scanBudget(row pgx.Row) (*Budget, error) {
+	var b Budget
+	if err := row.Scan(&b.ID, &b.Code, &b.Name, &b.FinancialYear, &b.VarianceThresholdPct, &b.CreatedAt, &b.Total); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// budgetByCode fetches a budget version, without its lines.
+func (s *budgetService) budgetByCode(ctx context.Context, companyID int, budgetCode string) (*Budget, error) {
+	b, err := scanBudget(s.pool.QueryRow(ctx,
+		"SELECT "+budgetColumns+" FROM budget_versions b WHERE b.company_id = $1 AND b.code = $2",
+		companyID, strings.ToUpper(strings.TrimSpace(budgetCode))))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("budget %s not found", budgetCode)
+		}
+		return nil, fmt.Errorf("failed to fetch budget %s: %w", budgetCode, err)
+	}
+	return b, nil
+}
+
+func (s *budgetService) ListBudgets(ctx context.Context, companyCode string) ([]Budget, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx, "SELECT "+budgetColumns+` FROM budget_versions b
+		WHERE b.company_id = $1 ORDER BY b.financial_year DESC, b.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []Budget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, *b)
+	}
+	return budgets, rows.Err()
+}
+
+func (s *budgetService) CreateBudget(ctx context.Context, companyCode string, input BudgetInput) (*Budget, error) {
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	if code == "" {
+		return nil, fmt.Errorf("budget code is required")
+	}
+	if input.FinancialYear < 2000 || input.FinancialYear > 2100 {
+		return nil, fmt.Errorf("invalid financial year %d (expected the year it starts in, e.g. 2026)", input.FinancialYear)
+	}
+	threshold := DefaultBudgetVarianceThresholdPct
+	if input.VarianceThresholdPct != nil {
+		threshold = *input.VarianceThresholdPct
+	}
+	if threshold.IsNegative() {
+		return nil, fmt.Errorf("variance threshold cannot be negative")
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = fmt.Sprintf("%s FY %s", code, FinancialYearLabel(input.FinancialYear))
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	b := Budget{Code: code, Name: name, FinancialYear: input.FinancialYear, VarianceThresholdPct: threshold}
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO budget_versions (company_id, code, name, financial_year, variance_threshold_pct, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5::numeric, $6)
+		ON CONFLICT (company_id, code) DO NOTHING
+		RETURNING id, created_at`,
+		companyID, code, name, input.FinancialYear, threshold, input.CreatedByUserID,
+	).Scan(&b.ID, &b.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("budget %s already exists", code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create budget: %w", err)
+	}
+	return &b, nil
+}
+
+func (s *budgetService) GetBudget(ctx context.Context, companyCode, budgetCode string) (*Budget, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.budgetByCode(ctx, companyID, budgetCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, COALESCE(cc.code, ''), bl.year, bl.month, bl.amount
+		FROM budget_lines bl
+		JOIN accounts a ON a.id = bl.account_id
+		LEFT JOIN dimension_values cc ON cc.id = bl.cost_center_id
+		WHERE bl.budget_id = $1
+		ORDER BY a.code, COALESCE(cc.code, ''), bl.year, bl.month`, b.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budget lines: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l BudgetLine
+		if err := rows.Scan(&l.AccountCode, &l.AccountName, &l.CostCenterCode, &l.Year, &l.Month, &l.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan budget line: %w", err)
+		}
+		b.Lines = append(b.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("budget line iteration error: %w", err)
+	}
+	return b, nil
+}
+
+// ── Budget lines ──────────────────────────────────────────────────────────────
+
+func (s *budgetService) SetBudgetLines(ctx context.Context, companyCode, budgetCode string, lines []BudgetLine, replace bool) (int, error) {
+	if len(lines) == 0 {
+		return 0, fmt.Errorf("at least one budget line is required")
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return 0, err
+	}
+	budget, err := s.budgetByCode(ctx, companyID, budgetCode)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	accountIDs := map[string]int{}
+	costCenterIDs := map[string]int{}
+	for i, l := range lines {
+		if !inFinancialYear(budget.FinancialYear, l.Year, l.Month) {
+			return 0, fmt.Errorf("line %d: %04d-%02d is outside financial year %s",
+				i+1, l.Year, l.Month, FinancialYearLabel(budget.FinancialYear))
+		}
+		if _, ok := accountIDs[l.AccountCode]; !ok {
+			var id int
+			var isGroup, isActive bool
+			err := tx.QueryRow(ctx,
+				"SELECT id, is_group, is_active FROM accounts WHERE company_id = $1 AND code = $2",
+				companyID, l.AccountCode).Scan(&id, &isGroup, &isActive)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, fmt.Errorf("line %d: account %s not found", i+1, l.AccountCode)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("fetch account %s: %w", l.AccountCode, err)
+			}
+			if isGroup || !isActive {
+				return 0, fmt.Errorf("line %d: account %s is a group or inactive account and cannot be budgeted", i+1, l.AccountCode)
+			}
+			accountIDs[l.AccountCode] = id
+		}
+		if _, ok := costCenterIDs[l.CostCenterCode]; !ok && l.CostCenterCode != "" {
+			var id int
+			var isActive bool
+			err := tx.QueryRow(ctx, `
+				SELECT v.id, v.is_active
+				FROM dimension_values v
+				JOIN dimensions d ON d.id = v.dimension_id
+				WHERE d.company_id = $1 AND d.code = $2 AND v.code = $3`,
+				companyID, DimensionCostCenter, strings.ToUpper(l.CostCenterCode)).Scan(&id, &isActive)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, fmt.Errorf("line %d: cost center %s not found", i+1, l.CostCenterCode)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("fetch cost center %s: %w", l.CostCenterCode, err)
+			}
+			if !isActive {
+				return 0, fmt.Errorf("line %d: cost center %s is inactive", i+1, l.CostCenterCode)
+			}
+			costCenterIDs[l.CostCenterCode] = id
+		}
+	}
+
+	if replace {
+		if _, err := tx.Exec(ctx, "DELETE FROM budget_lines WHERE budget_id = $1", budget.ID); err != nil {
+			return 0, fmt.Errorf("clear budget lines: %w", err)
+		}
+	}
+	for _, l := range lines {
+		var costCenterID *int
+		if id, ok := costCenterIDs[l.CostCenterCode]; ok {
+			costCenterID = &id
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO budget_lines (budget_id, account_id, cost_center_id, year, month, amount)
+			VALUES ($1, $2, $3, $4, $5, $6::numeric)
+			ON CONFLICT (budget_id, account_id, COALESCE(cost_center_id, 0), year, month)
+			DO UPDATE SET amount = EXCLUDED.amount`,
+			budget.ID, accountIDs[l.AccountCode], costCenterID, l.Year, l.Month, l.Amount.Round(2),
+		); err != nil {
+			return 0, fmt.Errorf("write budget line for account %s: %w", l.AccountCode, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit budget lines: %w", err)
+	}
+	return len(lines), nil
+}
+
+func (s *budgetService) CopyBudgetFromActuals(ctx context.Context, companyCode, budgetCode string, sourceYear int, upliftPct decimal.Decimal) (int, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return 0, err
+	}
+	budget, err := s.budgetByCode(ctx, companyID, budgetCode)
+	if err != nil {
+		return 0, err
+	}
+	if upliftPct.LessThanOrEqual(decimal.NewFromInt(-100)) {
+		return 0, fmt.Errorf("uplift must be greater than -100%%")
+	}
+	factor := decimal.NewFromInt(1).Add(upliftPct.Div(decimal.NewFromInt(100)))
+	sourceStart := time.Date(sourceYear, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM budget_lines WHERE budget_id = $1", budget.ID); err != nil {
+		return 0, fmt.Errorf("clear budget lines: %w", err)
+	}
+	// Actuals are in the debit-positive sign of the view; revenue is credit-normal.
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO budget_lines (budget_id, account_id, cost_center_id, year, month, amount)
+		SELECT $1, m.account_id, NULL, m.year + $4, m.month,
+		       ROUND(CASE WHEN m.account_type = 'revenue' THEN -m.net_balance ELSE m.net_balance END * $5::numeric, 2)
+		FROM mv_account_period_balances m
+		JOIN accounts a ON a.id = m.account_id
+		WHERE m.company_id = $2
+		  AND m.account_type IN ('revenue', 'expense')
+		  AND NOT a.is_group
+		  AND make_date(m.year, m.month, 1) BETWEEN $3::date AND ($3::date + INTERVAL '11 months')
+		  AND m.net_balance <> 0`,
+		budget.ID, companyID, sourceStart.Format("2006-01-02"), budget.FinancialYear-sourceYear, factor,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("copy actuals of FY %s: %w", FinancialYearLabel(sourceYear), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit budget lines: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ── Budget vs actual ──────────────────────────────────────────────────────────
+
+func (s *budgetService) GetBudgetVsActual(ctx context.Context, companyCode, budgetCode string, period ReportPeriod, thresholdPct *decimal.Decimal) (*BudgetVsActualReport, error) {
+	from, err := time.Parse("2006-01-02", period.FromDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period start %q (expected YYYY-MM-DD)", period.FromDate)
+	}
+	to, err := time.Parse("2006-01-02", period.ToDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period end %q (expected YYYY-MM-DD)", period.ToDate)
+	}
+	if from.Day() != 1 || !isMonthEnd(to) || from.After(to) {
+		return nil, fmt.Errorf("budgets are monthly: period %s must cover whole calendar months", period.Label)
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var budget *Budget
+	if strings.TrimSpace(budgetCode) != "" {
+		if budget, err = s.budgetByCode(ctx, companyID, budgetCode); err != nil {
+			return nil, err
+		}
+	} else {
+		fy := FinancialYear(from)
+		budget, err = scanBudget(s.pool.QueryRow(ctx, "SELECT "+budgetColumns+` FROM budget_versions b
+			WHERE b.company_id = $1 AND b.financial_year = $2
+			ORDER BY b.created_at DESC, b.id DESC LIMIT 1`, companyID, fy))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("no budget for financial year %s", FinancialYearLabel(fy))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch budget: %w", err)
+		}
+	}
+	threshold := budget.VarianceThresholdPct
+	if thresholdPct != nil {
+		if thresholdPct.IsNegative() {
+			return nil, fmt.Errorf("variance threshold cannot be negative")
+		}
+		threshold = *thresholdPct
+	}
+
+	// Months are compared as year*12 + month so that ranges can cross a year end.
+	fromMonth := from.Year()*12 + int(from.Month())
+	toMonth := to.Year()*12 + int(to.Month())
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, COALESCE(b.amount, 0), COALESCE(m.net, 0)
+		FROM accounts a
+		LEFT JOIN (
+		    SELECT account_id, SUM(amount) AS amount
+		    FROM budget_lines
+		    WHERE budget_id = $2 AND year * 12 + month BETWEEN $3 AND $4
+		    GROUP BY account_id
+		) b ON b.account_id = a.id
+		LEFT JOIN (
+		    SELECT account_id, SUM(net_balance) AS net
+		    FROM mv_account_period_balances
+		    WHERE company_id = $1 AND year * 12 + month BETWEEN $3 AND $4
+		    GROUP BY account_id
+		) m ON m.account_id = a.id
+		WHERE a.company_id = $1
+		  AND (b.account_id IS NOT NULL
+		       OR (m.account_id IS NOT NULL AND a.type IN ('revenue', 'expense')))
+		ORDER BY CASE a.type WHEN 'revenue' THEN 0 WHEN 'expense' THEN 1 ELSE 2 END, a.code`,
+		companyID, budget.ID, fromMonth, toMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budget vs actual: %w", err)
+	}
+	defer rows.Close()
+
+	report := &BudgetVsActualReport{
+		CompanyCode:   companyCode,
+		BudgetCode:    budget.Code,
+		BudgetName:    budget.Name,
+		FinancialYear: FinancialYearLabel(budget.FinancialYear),
+		Period:        period,
+		ThresholdPct:  threshold,
+	}
+	var revBudget, revActual, expBudget, expActual decimal.Decimal
+	for rows.Next() {
+		var code, name string
+		var accType AccountType
+		var amount, net decimal.Decimal
+		if err := rows.Scan(&code, &name, &accType, &amount, &net); err != nil {
+			return nil, fmt.Errorf("failed to scan budget vs actual row: %w", err)
+		}
+		actual := net
+		if !accType.IsDebitNormal() {
+			actual = net.Neg()
+		}
+		report.Lines = append(report.Lines, NewBudgetVsActualLine(code, name, accType, amount, actual, threshold))
+		switch accType {
+		case Revenue:
+			revBudget, revActual = revBudget.Add(amount), revActual.Add(actual)
+		case Expense:
+			expBudget, expActual = expBudget.Add(amount), expActual.Add(actual)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("budget vs actual row iteration error: %w", err)
+	}
+
+	report.Revenue = NewBudgetVsActualLine("", "Total Revenue", Revenue, revBudget, revActual, threshold)
+	report.Expenses = NewBudgetVsActualLine("", "Total Expenses", Expense, expBudget, expActual, threshold)
+	report.NetIncome = NewBudgetVsActualLine("", "Net Income", Revenue,
+		revBudget.Sub(expBudget), revActual.Sub(expActual), threshold)
+	return report, nil
+}
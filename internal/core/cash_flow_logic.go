@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// CashFlowMovement is the period movement of one non-cash account, the input to
+// BuildIndirectCashFlow and BuildDirectCashFlow.
+type CashFlowMovement struct {
+	AccountCode string
+	AccountName string
+	AccountType string // asset | liability | equity | revenue | expense
+	Activity    string // mapped activity; empty when unmapped
+	LineName    string // mapped line; empty to report under the account name
+
+	// Indirect method: Net is debits less credits. NonCashAccount is set when the movement
+	// comes from entries that post to a NON_CASH P&L account and touch no cash account; it
+	// is then added back under that account instead of being a working capital change.
+	Net            decimal.Decimal
+	NonCashAccount string
+
+	// Direct method: cash received from (positive) and paid to (negative) the account,
+	// from the entries that post to a cash account.
+	Inflows  decimal.Decimal
+	Outflows decimal.Decimal
+}
+
+// ParseCashFlowActivity normalises a cash flow activity name.
+func ParseCashFlowActivity(activity string) (string, error) {
+	a := strings.ToUpper(strings.TrimSpace(activity))
+	switch a {
+	case CashFlowOperating, CashFlowNonCash, CashFlowInvesting, CashFlowFinancing:
+		return a, nil
+	}
+	return "", fmt.Errorf("invalid cash flow activity %q: expected OPERATING, NON_CASH, INVESTING or FINANCING", activity)
+}
+
+// DefaultCashFlowActivity is the activity of an unmapped account: FINANCING for equity,
+// OPERATING otherwise.
+func DefaultCashFlowActivity(accountType string) string {
+	if accountType == "equity" {
+		return CashFlowFinancing
+	}
+	return CashFlowOperating
+}
+
+func (m CashFlowMovement) activity() string {
+	if m.Activity != "" {
+		return m.Activity
+	}
+	return DefaultCashFlowActivity(m.AccountType)
+}
+
+func (m CashFlowMovement) lineName() string {
+	if m.LineName != "" {
+		return m.LineName
+	}
+	return m.AccountName
+}
+
+// cashFlowLines accumulates statement lines by name in order of first appearance.
+type cashFlowLines struct {
+	lines []CashFlowLine
+	index map[string]int
+}
+
+func (c *cashFlowLines) add(name, account string, amount, inflows, outflows decimal.Decimal) {
+	if c.index == nil {
+		c.index = map[string]int{}
+	}
+	i, ok := c.index[name]
+	if !ok {
+		i = len(c.lines)
+		c.index[name] = i
+		c.lines = append(c.lines, CashFlowLine{Name: name})
+	}
+	l := &c.lines[i]
+	l.Amount = l.Amount.Add(amount)
+	l.Inflows = l.Inflows.Add(inflows)
+	l.Outflows = l.Outflows.Add(outflows)
+	for _, a := range l.Accounts {
+		if a == account {
+			return
+		}
+	}
+	l.Accounts = append(l.Accounts, account)
+}
+
+// result returns the lines with any cash effect, and their total.
+func (c *cashFlowLines) result() ([]CashFlowLine, decimal.Decimal) {
+	var out []CashFlowLine
+	total := decimal.Zero
+	for _, l := range c.lines {
+		if l.Amount.IsZero() && l.Inflows.IsZero() && l.Outflows.IsZero() {
+			continue
+		}
+		out = append(out, l)
+		total = total.Add(l.Amount)
+	}
+	return out, total
+}
+
+// BuildIndirectCashFlow builds the indirect-method statement: net income, plus the non-cash
+// adjustments, plus the change in every other non-cash balance-sheet account by activity.
+// Because each journal entry balances, the sections add up to the change in cash.
+// Company, dates and cash balances are left for the caller.
+func BuildIndirectCashFlow(movements []CashFlowMovement) *CashFlowReport {
+	report := &CashFlowReport{Method: CashFlowMethodIndirect}
+	labels := map[string]string{}
+	for _, m := range movements {
+		labels[m.AccountCode] = m.lineName()
+	}
+
+	var adjustments cashFlowLines
+	sections := map[string]*cashFlowLines{
+		CashFlowOperating: {}, CashFlowInvesting: {}, CashFlowFinancing: {},
+	}
+	for _, m := range movements {
+		effect := m.Net.Neg() // a debit to a non-cash account is a use of cash
+		switch {
+		case m.AccountType == "revenue" || m.AccountType == "expense":
+			report.NetIncome = report.NetIncome.Add(effect)
+		case m.NonCashAccount != "":
+			name := labels[m.NonCashAccount]
+			if name == "" {
+				name = m.NonCashAccount
+			}
+			adjustments.add(name, m.NonCashAccount, effect, decimal.Zero, decimal.Zero)
+		case m.activity() == CashFlowNonCash:
+			adjustments.add(m.lineName(), m.AccountCode, effect, decimal.Zero, decimal.Zero)
+		default:
+			sections[m.activity()].add(m.lineName(), m.AccountCode, effect, decimal.Zero, decimal.Zero)
+		}
+	}
+
+	var adjTotal decimal.Decimal
+	report.Adjustments, adjTotal = adjustments.result()
+	report.Operating = CashFlowSection{Activity: CashFlowOperating}
+	report.Operating.Lines, report.Operating.Total = sections[CashFlowOperating].result()
+	report.Operating.Total = report.Operating.Total.Add(report.NetIncome).Add(adjTotal)
+	report.Investing = CashFlowSection{Activity: CashFlowInvesting}
+	report.Investing.Lines, report.Investing.Total = sections[CashFlowInvesting].result()
+	report.Financing = CashFlowSection{Activity: CashFlowFinancing}
+	report.Financing.Lines, report.Financing.Total = sections[CashFlowFinancing].result()
+	report.NetChange = report.Operating.Total.Add(report.Investing.Total).Add(report.Financing.Total)
+	return report
+}
+
+// BuildDirectCashFlow builds the direct-method statement: the cash received from and paid
+// to each counter-account, by activity. NON_CASH accounts that do move cash are reported
+// as operating. Company, dates and cash balances are left for the caller.
+func BuildDirectCashFlow(movements []CashFlowMovement) *CashFlowReport {
+	report := &CashFlowReport{Method: CashFlowMethodDirect}
+	sections := map[string]*cashFlowLines{
+		CashFlowOperating: {}, CashFlowInvesting: {}, CashFlowFinancing: {},
+	}
+	for _, m := range movements {
+		activity := m.activity()
+		if activity == CashFlowNonCash {
+			activity = CashFlowOperating
+		}
+		sections[activity].add(m.lineName(), m.AccountCode, m.Inflows.Add(m.Outflows), m.Inflows, m.Outflows)
+	}
+
+	report.Operating = CashFlowSection{Activity: CashFlowOperating}
+	report.Operating.Lines, report.Operating.Total = sections[CashFlowOperating].result()
+	report.Investing = CashFlowSection{Activity: CashFlowInvesting}
+	report.Investing.Lines, report.Investing.Total = sections[CashFlowInvesting].result()
+	report.Financing = CashFlowSection{Activity: CashFlowFinancing}
+	report.Financing.Lines, report.Financing.Total = sections[CashFlowFinancing].result()
+	report.NetChange = report.Operating.Total.Add(report.Investing.Total).Add(report.Financing.Total)
+	return report
+}
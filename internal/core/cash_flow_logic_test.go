@@ -0,0 +1,119 @@
+package core_test
+
+import (
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+// cashFlowPeriod is one month of activity, expressed as the movements of its non-cash accounts:
+//
+//	capital paid in 10000 · inventory bought on credit 4000 · sale on credit 5000 + GST 900
+//	goods shipped at cost 2500 · customer pays 3000 · vendor paid 1000
+//	furniture bought 1500 · rent paid 500
+//
+// Cash rises by 10000 − 1000 + 3000 − 1500 − 500 = 10000.
+func cashFlowPeriod() (indirect, direct []core.CashFlowMovement) {
+	d := decimal.RequireFromString
+	indirect = []core.CashFlowMovement{
+		{AccountCode: "1200", AccountName: "Accounts Receivable", AccountType: "asset", Activity: "OPERATING", LineName: "Trade receivables", Net: d("2900")},
+		{AccountCode: "1300", AccountName: "Furniture", AccountType: "asset", Activity: "INVESTING", LineName: "Purchase of fixed assets", Net: d("1500")},
+		{AccountCode: "1400", AccountName: "Inventory", AccountType: "asset", Activity: "OPERATING", LineName: "Inventories", Net: d("4000")},
+		{AccountCode: "1400", AccountName: "Inventory", AccountType: "asset", Activity: "OPERATING", LineName: "Inventories", Net: d("-2500"), NonCashAccount: "5000"},
+		{AccountCode: "2000", AccountName: "Accounts Payable", AccountType: "liability", Activity: "OPERATING", LineName: "Trade payables", Net: d("-3000")},
+		{AccountCode: "2310", AccountName: "Output CGST", AccountType: "liability", Activity: "OPERATING", LineName: "Duties and taxes", Net: d("-450")},
+		{AccountCode: "2320", AccountName: "Output SGST", AccountType: "liability", Activity: "OPERATING", LineName: "Duties and taxes", Net: d("-450")},
+		{AccountCode: "3000", AccountName: "Owner Capital", AccountType: "equity", Net: d("-10000")},
+		{AccountCode: "4000", AccountName: "Sales Revenue", AccountType: "revenue", Net: d("-5000")},
+		{AccountCode: "5000", AccountName: "Cost of Goods Sold", AccountType: "expense", Activity: "NON_CASH", LineName: "Cost of goods sold", Net: d("2500"), NonCashAccount: "5000"},
+		{AccountCode: "5100", AccountName: "Rent", AccountType: "expense", Net: d("500")},
+	}
+	direct = []core.CashFlowMovement{
+		{AccountCode: "1200", AccountName: "Accounts Receivable", AccountType: "asset", Activity: "OPERATING", LineName: "Trade receivables", Inflows: d("3000")},
+		{AccountCode: "1300", AccountName: "Furniture", AccountType: "asset", Activity: "INVESTING", LineName: "Purchase of fixed assets", Outflows: d("-1500")},
+		{AccountCode: "2000", AccountName: "Accounts Payable", AccountType: "liability", Activity: "OPERATING", LineName: "Trade payables", Outflows: d("-1000")},
+		{AccountCode: "3000", AccountName: "Owner Capital", AccountType: "equity", Inflows: d("10000")},
+		{AccountCode: "5100", AccountName: "Rent", AccountType: "expense", Outflows: d("-500")},
+	}
+	return indirect, direct
+}
+
+func cashFlowLine(lines []core.CashFlowLine, name string) (core.CashFlowLine, bool) {
+	for _, l := range lines {
+		if l.Name == name {
+			return l, true
+		}
+	}
+	return core.CashFlowLine{}, false
+}
+
+func TestBuildIndirectCashFlow(t *testing.T) {
+	movements, _ := cashFlowPeriod()
+	r := core.BuildIndirectCashFlow(movements)
+
+	if r.Method != core.CashFlowMethodIndirect {
+		t.Errorf("Method: got %s", r.Method)
+	}
+	if r.NetIncome.StringFixed(2) != "2000.00" {
+		t.Errorf("NetIncome: got %s, want 2000.00", r.NetIncome.StringFixed(2))
+	}
+	if len(r.Adjustments) != 1 || r.Adjustments[0].Name != "Cost of goods sold" ||
+		r.Adjustments[0].Amount.StringFixed(2) != "2500.00" || r.Adjustments[0].Accounts[0] != "5000" {
+		t.Errorf("Adjustments: got %+v", r.Adjustments)
+	}
+	if l, _ := cashFlowLine(r.Operating.Lines, "Inventories"); l.Amount.StringFixed(2) != "-4000.00" {
+		t.Errorf("Inventories should exclude goods shipped: got %s", l.Amount)
+	}
+	taxes, ok := cashFlowLine(r.Operating.Lines, "Duties and taxes")
+	if !ok || taxes.Amount.StringFixed(2) != "900.00" || len(taxes.Accounts) != 2 {
+		t.Errorf("Duties and taxes should combine both GST accounts: got %+v", taxes)
+	}
+
+	for name, c := range map[string]struct {
+		got  decimal.Decimal
+		want string
+	}{
+		"Operating": {r.Operating.Total, "1500.00"},
+		"Investing": {r.Investing.Total, "-1500.00"},
+		"Financing": {r.Financing.Total, "10000.00"},
+		"NetChange": {r.NetChange, "10000.00"},
+	} {
+		if c.got.StringFixed(2) != c.want {
+			t.Errorf("%s: got %s, want %s", name, c.got.StringFixed(2), c.want)
+		}
+	}
+	// Unmapped equity defaults to financing under the account name.
+	if _, ok := cashFlowLine(r.Financing.Lines, "Owner Capital"); !ok {
+		t.Errorf("expected Owner Capital under financing, got %+v", r.Financing.Lines)
+	}
+}
+
+func TestBuildDirectCashFlow(t *testing.T) {
+	_, movements := cashFlowPeriod()
+	r := core.BuildDirectCashFlow(movements)
+
+	if r.Operating.Total.StringFixed(2) != "1500.00" {
+		t.Errorf("Operating: got %s, want 1500.00 (same as the indirect method)", r.Operating.Total.StringFixed(2))
+	}
+	if r.NetChange.StringFixed(2) != "10000.00" {
+		t.Errorf("NetChange: got %s, want 10000.00", r.NetChange.StringFixed(2))
+	}
+	rent, ok := cashFlowLine(r.Operating.Lines, "Rent")
+	if !ok || rent.Outflows.StringFixed(2) != "-500.00" || !rent.Inflows.IsZero() {
+		t.Errorf("Rent: got %+v", rent)
+	}
+	if len(r.Adjustments) != 0 || !r.NetIncome.IsZero() {
+		t.Errorf("direct method has no net income or adjustments: %s %+v", r.NetIncome, r.Adjustments)
+	}
+}
+
+func TestParseCashFlowActivity(t *testing.T) {
+	if a, err := core.ParseCashFlowActivity(" non_cash "); err != nil || a != core.CashFlowNonCash {
+		t.Errorf("ParseCashFlowActivity: got %q, %v", a, err)
+	}
+	if _, err := core.ParseCashFlowActivity("TRADING"); err == nil {
+		t.Error("expected error for unknown activity")
+	}
+}
@@ -0,0 +1,158 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/google/uuid"
+)
+
+func TestDimensions_RulesPostingAndReports(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	dims := core.NewDimensionService(pool)
+	ctx := context.Background()
+
+	for _, v := range []struct{ dim, code, name string }{
+		{core.DimensionCostCenter, "mkt", "Marketing"},
+		{core.DimensionCostCenter, "OPS", "Operations"},
+		{core.DimensionProject, "P-101", "Website"},
+	} {
+		if _, err := dims.CreateDimensionValue(ctx, "1000", v.dim, v.code, v.name); err != nil {
+			t.Fatalf("CreateDimensionValue %s %s: %v", v.dim, v.code, err)
+		}
+	}
+	if _, err := dims.CreateDimensionValue(ctx, "1000", core.DimensionCostCenter, "MKT", ""); err == nil {
+		t.Error("expected error for duplicate value")
+	}
+	if _, err := dims.CreateDimension(ctx, "1000", "region", "Region"); err != nil {
+		t.Fatalf("CreateDimension: %v", err)
+	}
+	list, err := dims.ListDimensions(ctx, "1000")
+	if err != nil {
+		t.Fatalf("ListDimensions: %v", err)
+	}
+	if len(list) != 4 || list[0].Code != core.DimensionCostCenter || len(list[0].Values) != 2 || list[3].Code != "REGION" {
+		t.Fatalf("unexpected dimensions: %+v", list)
+	}
+
+	if err := dims.SetAccountDimensionRequired(ctx, "1000", "5100", core.DimensionCostCenter, true); err != nil {
+		t.Fatalf("SetAccountDimensionRequired: %v", err)
+	}
+
+	post := func(key, debit, credit, amount string, debitDims []core.LineDimension) error {
+		return ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: key, TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: "2026-06-10", DocumentDate: "2026-06-10", Summary: "dimension test", Reasoning: "test",
+			Lines: []core.ProposalLine{
+				{AccountCode: debit, IsDebit: true, Amount: amount, Dimensions: debitDims},
+				{AccountCode: credit, IsDebit: false, Amount: amount},
+			},
+		})
+	}
+
+	t.Run("required dimension is enforced", func(t *testing.T) {
+		err := post(uuid.NewString(), "5100", "1000", "100.00", nil)
+		if err == nil || !strings.Contains(err.Error(), "requires dimension COST_CENTER") {
+			t.Fatalf("expected required-dimension error, got %v", err)
+		}
+		err = post(uuid.NewString(), "5100", "1000", "100.00", []core.LineDimension{{Dimension: "COST_CENTER", Value: "NOPE"}})
+		if err == nil {
+			t.Fatal("expected error for an unknown cost center")
+		}
+	})
+
+	mktKey := uuid.NewString()
+	if err := post(mktKey, "5100", "1000", "300.00", []core.LineDimension{
+		{Dimension: "cost_center", Value: "mkt"}, {Dimension: "PROJECT", Value: "P-101"},
+	}); err != nil {
+		t.Fatalf("Commit MKT: %v", err)
+	}
+	if err := post(uuid.NewString(), "5100", "1000", "200.00", []core.LineDimension{{Dimension: "COST_CENTER", Value: "OPS"}}); err != nil {
+		t.Fatalf("Commit OPS: %v", err)
+	}
+	if err := post(uuid.NewString(), "1000", "4000", "1000.00", nil); err != nil {
+		t.Fatalf("Commit revenue: %v", err)
+	}
+
+	period, _ := core.ParseReportPeriod("2026-06")
+
+	t.Run("P&L broken down by cost center", func(t *testing.T) {
+		report, err := dims.GetDimensionProfitAndLoss(ctx, "1000", period, nil, "cost_center")
+		if err != nil {
+			t.Fatalf("GetDimensionProfitAndLoss: %v", err)
+		}
+		// MKT, OPS and the untagged column.
+		if len(report.Columns) != 3 || report.Columns[0].Code != "MKT" || report.Columns[2].Code != "" {
+			t.Fatalf("unexpected columns: %+v", report.Columns)
+		}
+		amounts := report.TotalExpenses.Amounts
+		if amounts[0].StringFixed(2) != "300.00" || amounts[1].StringFixed(2) != "200.00" || !amounts[2].IsZero() {
+			t.Errorf("expenses by cost center: got %v", amounts)
+		}
+		if report.NetIncome.Total.StringFixed(2) != "500.00" || report.NetIncome.Amounts[2].StringFixed(2) != "1000.00" {
+			t.Errorf("net income: got %s, untagged %s", report.NetIncome.Total, report.NetIncome.Amounts[2])
+		}
+	})
+
+	t.Run("P&L filtered by project", func(t *testing.T) {
+		report, err := dims.GetDimensionProfitAndLoss(ctx, "1000", period, []core.LineDimension{{Dimension: "PROJECT", Value: "P-101"}}, "")
+		if err != nil {
+			t.Fatalf("GetDimensionProfitAndLoss: %v", err)
+		}
+		if len(report.Revenue) != 0 || len(report.Expenses) != 1 || report.TotalExpenses.Total.StringFixed(2) != "300.00" {
+			t.Errorf("expected only the P-101 expense: %+v", report)
+		}
+		if _, err := dims.GetDimensionProfitAndLoss(ctx, "1000", period, []core.LineDimension{{Dimension: "PROJECT", Value: "NOPE"}}, ""); err == nil {
+			t.Error("expected error for an unknown filter value")
+		}
+	})
+
+	t.Run("reversal carries dimensions and nets to zero", func(t *testing.T) {
+		var entryID int
+		if err := pool.QueryRow(ctx, "SELECT id FROM journal_entries WHERE idempotency_key = $1", mktKey).Scan(&entryID); err != nil {
+			t.Fatalf("fetch entry: %v", err)
+		}
+		if err := ledger.Reverse(ctx, entryID, "wrong cost center"); err != nil {
+			t.Fatalf("Reverse: %v", err)
+		}
+		tb, err := dims.GetDimensionTrialBalance(ctx, "1000", "2026-12-31", []core.LineDimension{{Dimension: "COST_CENTER", Value: "MKT"}}, "")
+		if err != nil {
+			t.Fatalf("GetDimensionTrialBalance: %v", err)
+		}
+		for _, a := range tb.Accounts {
+			if !a.Total.IsZero() {
+				t.Errorf("account %s: MKT balance %s after reversal, want 0", a.AccountCode, a.Total)
+			}
+		}
+	})
+
+	t.Run("retired values cannot be used", func(t *testing.T) {
+		if _, err := dims.SetDimensionValueActive(ctx, "1000", core.DimensionCostCenter, "OPS", false); err != nil {
+			t.Fatalf("SetDimensionValueActive: %v", err)
+		}
+		if err := post(uuid.NewString(), "5100", "1000", "50.00", []core.LineDimension{{Dimension: "COST_CENTER", Value: "OPS"}}); err == nil {
+			t.Error("expected error for a retired cost center")
+		}
+	})
+
+	t.Run("rule can be lifted", func(t *testing.T) {
+		if err := dims.SetAccountDimensionRequired(ctx, "1000", "5100", core.DimensionCostCenter, false); err != nil {
+			t.Fatalf("SetAccountDimensionRequired: %v", err)
+		}
+		rules, err := dims.ListAccountDimensionRules(ctx, "1000")
+		if err != nil || len(rules) != 0 {
+			t.Fatalf("expected no rules, got %+v, %v", rules, err)
+		}
+		if err := post(uuid.NewString(), "5100", "1000", "50.00", nil); err != nil {
+			t.Errorf("untagged posting should pass without the rule: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NormalizeLineDimensions trims and upper-cases dimension and value codes and drops entries
+// without a value, so that an AI-proposed line may leave a dimension blank. The result is
+// never nil, so that it stores as an empty JSON array.
+func NormalizeLineDimensions(dims []LineDimension) []LineDimension {
+	out := make([]LineDimension, 0, len(dims))
+	for _, d := range dims {
+		d.Dimension = strings.ToUpper(strings.TrimSpace(d.Dimension))
+		d.Value = strings.ToUpper(strings.TrimSpace(d.Value))
+		if d.Dimension == "" || d.Value == "" || strings.EqualFold(d.Value, "null") {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// ValidateLineDimensions checks that a line names each dimension at most once and gives
+// it a value.
+func ValidateLineDimensions(dims []LineDimension) error {
+	seen := make(map[string]bool, len(dims))
+	for _, d := range dims {
+		code := strings.ToUpper(strings.TrimSpace(d.Dimension))
+		if code == "" {
+			return fmt.Errorf("dimension code is required")
+		}
+		if strings.TrimSpace(d.Value) == "" {
+			return fmt.Errorf("dimension %s needs a value", code)
+		}
+		if seen[code] {
+			return fmt.Errorf("dimension %s is given more than once", code)
+		}
+		seen[code] = true
+	}
+	return nil
+}
+
+// MissingDimensions returns the required dimension codes a line does not carry, in the
+// order of required.
+func MissingDimensions(required []string, dims []LineDimension) []string {
+	have := make(map[string]bool, len(dims))
+	for _, d := range dims {
+		have[strings.ToUpper(d.Dimension)] = true
+	}
+	var missing []string
+	for _, code := range required {
+		if !have[strings.ToUpper(code)] {
+			missing = append(missing, code)
+		}
+	}
+	return missing
+}
+
+// DimensionKey returns a canonical key for a set of line dimensions, independent of their
+// order, for grouping lines that carry the same dimensions.
+func DimensionKey(dims []LineDimension) string {
+	parts := make([]string, len(dims))
+	for i, d := range dims {
+		parts[i] = strings.ToUpper(d.Dimension) + "=" + strings.ToUpper(d.Value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// ParseDimensionFilter parses a report filter of comma-separated DIMENSION:VALUE pairs,
+// e.g. "COST_CENTER:MKT,PROJECT:P-101" ("=" may separate the pair as well). An empty
+// string is no filter.
+func ParseDimensionFilter(s string) ([]LineDimension, error) {
+	var dims []LineDimension
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		dim, value, ok := strings.Cut(part, ":")
+		if !ok {
+			dim, value, ok = strings.Cut(part, "=")
+		}
+		if !ok || strings.TrimSpace(dim) == "" || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid dimension filter %q: expected DIMENSION:VALUE", part)
+		}
+		dims = append(dims, LineDimension{Dimension: dim, Value: value})
+	}
+	dims = NormalizeLineDimensions(dims)
+	if err := ValidateLineDimensions(dims); err != nil {
+		return nil, fmt.Errorf("invalid dimension filter: %w", err)
+	}
+	return dims, nil
+}
@@ -0,0 +1,82 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"accounting-agent/internal/core"
+)
+
+func TestNormalizeLineDimensions(t *testing.T) {
+	got := core.NormalizeLineDimensions([]core.LineDimension{
+		{Dimension: " cost_center ", Value: "mkt"},
+		{Dimension: "PROJECT", Value: ""},
+		{Dimension: "BRANCH", Value: "null"},
+		{Dimension: "", Value: "X"},
+	})
+	want := []core.LineDimension{{Dimension: "COST_CENTER", Value: "MKT"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if empty := core.NormalizeLineDimensions(nil); empty == nil || len(empty) != 0 {
+		t.Errorf("expected a non-nil empty slice, got %#v", empty)
+	}
+}
+
+func TestValidateLineDimensions(t *testing.T) {
+	ok := []core.LineDimension{{Dimension: "COST_CENTER", Value: "MKT"}, {Dimension: "PROJECT", Value: "P-101"}}
+	if err := core.ValidateLineDimensions(ok); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	bad := map[string][]core.LineDimension{
+		"missing code":  {{Dimension: " ", Value: "MKT"}},
+		"missing value": {{Dimension: "PROJECT", Value: ""}},
+		"duplicate":     {{Dimension: "PROJECT", Value: "P-1"}, {Dimension: "project", Value: "P-2"}},
+	}
+	for name, dims := range bad {
+		if err := core.ValidateLineDimensions(dims); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestMissingDimensions(t *testing.T) {
+	dims := []core.LineDimension{{Dimension: "PROJECT", Value: "P-101"}}
+	got := core.MissingDimensions([]string{"COST_CENTER", "PROJECT", "BRANCH"}, dims)
+	if !reflect.DeepEqual(got, []string{"COST_CENTER", "BRANCH"}) {
+		t.Errorf("got %v", got)
+	}
+	if got := core.MissingDimensions([]string{"PROJECT"}, dims); len(got) != 0 {
+		t.Errorf("expected nothing missing, got %v", got)
+	}
+}
+
+func TestDimensionKey(t *testing.T) {
+	a := core.DimensionKey([]core.LineDimension{{Dimension: "PROJECT", Value: "P-101"}, {Dimension: "COST_CENTER", Value: "MKT"}})
+	b := core.DimensionKey([]core.LineDimension{{Dimension: "cost_center", Value: "mkt"}, {Dimension: "PROJECT", Value: "P-101"}})
+	if a != b || a != "COST_CENTER=MKT;PROJECT=P-101" {
+		t.Errorf("keys differ or are not canonical: %q vs %q", a, b)
+	}
+	if core.DimensionKey(nil) != "" {
+		t.Error("expected an empty key for no dimensions")
+	}
+}
+
+func TestParseDimensionFilter(t *testing.T) {
+	got, err := core.ParseDimensionFilter(" cost_center:mkt , PROJECT=P-101,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []core.LineDimension{{Dimension: "COST_CENTER", Value: "MKT"}, {Dimension: "PROJECT", Value: "P-101"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got, err := core.ParseDimensionFilter(""); err != nil || len(got) != 0 {
+		t.Errorf("empty filter: got %+v, %v", got, err)
+	}
+	for _, s := range []string{"COST_CENTER", "COST_CENTER:", ":MKT", "PROJECT:A,PROJECT:B"} {
+		if _, err := core.ParseDimensionFilter(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// System dimensions, created for every company. Companies may add their own.
+const (
+	DimensionCostCenter = "COST_CENTER"
+	DimensionProject    = "PROJECT"
+	DimensionBranch     = "BRANCH"
+)
+
+// systemDimensions are the system dimensions with their default names, in display order.
+var systemDimensions = []struct{ Code, Name string }{
+	{DimensionCostCenter, "Cost Center"},
+	{DimensionProject, "Project"},
+	{DimensionBranch, "Branch"},
+}
+
+// Dimension is an analytic reporting axis of a company (cost center, project, branch or a
+// user-defined one). Values is filled by ListDimensions.
+type Dimension struct {
+	ID       int              `json:"id"`
+	Code     string           `json:"code"`
+	Name     string           `json:"name"`
+	IsSystem bool             `json:"is_system"`
+	IsActive bool             `json:"is_active"`
+	Values   []DimensionValue `json:"values"`
+}
+
+// DimensionValue is one master data value of a dimension, e.g. cost center MKT.
+type DimensionValue struct {
+	ID            int    `json:"id"`
+	DimensionCode string `json:"dimension_code"`
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	IsActive      bool   `json:"is_active"`
+}
+
+// LineDimension tags a journal, order or PO line with a value of one dimension, both by code.
+type LineDimension struct {
+	Dimension string `json:"dimension" jsonschema_description:"Dimension code from the provided Analytic Dimensions, e.g. COST_CENTER or PROJECT"`
+	Value     string `json:"value" jsonschema_description:"Code of one of that dimension's values"`
+}
+
+// AccountDimensionRule makes a dimension mandatory on every posting to an account.
+type AccountDimensionRule struct {
+	AccountCode   string `json:"account_code"`
+	AccountName   string `json:"account_name"`
+	DimensionCode string `json:"dimension_code"`
+}
+
+// DimensionColumn is one column of a dimension breakdown: a value of the breakdown
+// dimension, or the lines not tagged with that dimension (empty Code).
+type DimensionColumn struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// DimensionReportLine is one account of a dimension report. Amounts has one entry per
+// report column (none without a breakdown); Total is their sum.
+type DimensionReportLine struct {
+	AccountCode string            `json:"account_code"`
+	AccountName string            `json:"account_name"`
+	AccountType AccountType       `json:"account_type"`
+	Amounts     []decimal.Decimal `json:"amounts,omitempty"`
+	Total       decimal.Decimal   `json:"total"`
+}
+
+// DimensionPLReport is the P&L restricted to journal lines carrying every Filter value,
+// optionally broken down into one column per value of the Breakdown dimension. Revenue and
+// expenses are positive in their normal sign; the totals rows carry a column per breakdown
+// value like the account lines.
+type DimensionPLReport struct {
+	CompanyCode   string                `json:"company_code"`
+	Period        ReportPeriod          `json:"period"`
+	Filter        []LineDimension       `json:"filter"`
+	Breakdown     string                `json:"breakdown,omitempty"`
+	Columns       []DimensionColumn     `json:"columns,omitempty"`
+	Revenue       []DimensionReportLine `json:"revenue"`
+	Expenses      []DimensionReportLine `json:"expenses"`
+	TotalRevenue  DimensionReportLine   `json:"total_revenue"`
+	TotalExpenses DimensionReportLine   `json:"total_expenses"`
+	NetIncome     DimensionReportLine   `json:"net_income"`
+}
+
+// DimensionTrialBalance is the trial balance as of a date restricted to journal lines
+// carrying every Filter value, optionally broken down by the Breakdown dimension. Balances
+// are net debit (debit positive, credit negative). Only accounts with postings are listed.
+type DimensionTrialBalance struct {
+	CompanyCode string                `json:"company_code"`
+	AsOfDate    string                `json:"as_of_date"`
+	Filter      []LineDimension       `json:"filter"`
+	Breakdown   string                `json:"breakdown,omitempty"`
+	Columns     []DimensionColumn     `json:"columns,omitempty"`
+	Accounts    []DimensionReportLine `json:"accounts"`
+	Net         DimensionReportLine   `json:"net"`
+}
+
+// DimensionService maintains analytic dimensions, their values and the dimensions
+// required per account, and reports the P&L and trial balance by dimension. The ledger
+// enforces the rules when posting.
+type DimensionService interface {
+	// ListDimensions returns the company's dimensions with their values, system
+	// dimensions first.
+	ListDimensions(ctx context.Context, companyCode string) ([]Dimension, error)
+
+	// CreateDimension adds a user-defined dimension.
+	CreateDimension(ctx context.Context, companyCode, code, name string) (*Dimension, error)
+
+	// CreateDimensionValue adds a value to a dimension.
+	CreateDimensionValue(ctx context.Context, companyCode, dimensionCode, code, name string) (*DimensionValue, error)
+
+	// SetDimensionValueActive activates or retires a value. Retired values stay on posted
+	// lines but cannot be used on new ones.
+	SetDimensionValueActive(ctx context.Context, companyCode, dimensionCode, valueCode string, active bool) (*DimensionValue, error)
+
+	// ListAccountDimensionRules returns the dimensions required per account, ordered by
+	// account code.
+	ListAccountDimensionRules(ctx context.Context, companyCode string) ([]AccountDimensionRule, error)
+
+	// SetAccountDimensionRequired makes a dimension mandatory on postings to an account,
+	// or optional again.
+	SetAccountDimensionRequired(ctx context.Context, companyCode, accountCode, dimensionCode string, required bool) error
+
+	// GetDimensionProfitAndLoss returns the P&L over period for the journal lines carrying
+	// every filter value, broken down by the breakdown dimension unless it is empty.
+	GetDimensionProfitAndLoss(ctx context.Context, companyCode string, period ReportPeriod, filter []LineDimension, breakdown string) (*DimensionPLReport, error)
+
+	// GetDimensionTrialBalance returns the trial balance as of asOfDate (YYYY-MM-DD) for the
+	// journal lines carrying every filter value, broken down by the breakdown dimension
+	// unless it is empty.
+	GetDimensionTrialBalance(ctx context.Context, companyCode, asOfDate string, filter []LineDimension, breakdown string) (*DimensionTrialBalance, error)
+}
@@ -0,0 +1,651 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type dimensionService struct {
+	pool *pgxpool.Pool
+}
+
+// NewDimensionService constructs a DimensionService backed by PostgreSQL.
+func NewDimensionService(pool *pgxpool.Pool) DimensionService {
+	return &dimensionService{pool: pool}
+}
+
+func (s *dimensionService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
+
+// pgxExecer is satisfied by *pgxpool.Pool and pgx.Tx.
+type pgxExecer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// ensureSystemDimensions creates the system dimensions of a company that does not have
+// them yet — companies registered after migration 049.
+func ensureSystemDimensions(ctx context.Context, q pgxExecer, companyID int) error {
+	codes := make([]string, len(systemDimensions))
+	names := make([]string, len(systemDimensions))
+	for i, d := range systemDimensions {
+		codes[i], names[i] = d.Code, d.Name
+	}
+	if _, err := q.Exec(ctx, `
+		INSERT INTO dimensions (company_id, code, name, is_system)
+		SELECT $1, d.code, d.name, true
+		FROM unnest($2::text[], $3::text[]) AS d(code, name)
+		ON CONFLICT (company_id, code) DO NOTHING`, companyID, codes, names,
+	); err != nil {
+		return fmt.Errorf("create system dimensions: %w", err)
+	}
+	return nil
+}
+
+// dimensionID returns the id of a company's dimension by code.
+func (s *dimensionService) dimensionID(ctx context.Context, companyID int, code string) (int, error) {
+	if err := ensureSystemDimensions(ctx, s.pool, companyID); err != nil {
+		return 0, err
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	var id int
+	err := s.pool.QueryRow(ctx,
+		"SELECT id FROM dimensions WHERE company_id = $1 AND code = $2", companyID, code).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("dimension %s not found", code)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("fetch dimension %s: %w", code, err)
+	}
+	return id, nil
+}
+
+// ── Master data ───────────────────────────────────────────────────────────────
+
+func (s *dimensionService) ListDimensions(ctx context.Context, companyCode string) ([]Dimension, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSystemDimensions(ctx, s.pool, companyID); err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT d.id, d.code, d.name, d.is_system, d.is_active,
+		       v.id, v.code, v.name, v.is_active
+		FROM dimensions d
+		LEFT JOIN dimension_values v ON v.dimension_id = d.id
+		WHERE d.company_id = $1
+		ORDER BY NOT d.is_system, d.id, v.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dimensions: %w", err)
+	}
+	defer rows.Close()
+
+	var dims []Dimension
+	for rows.Next() {
+		var d Dimension
+		var valueID *int
+		var valueCode, valueName *string
+		var valueActive *bool
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name, &d.IsSystem, &d.IsActive,
+			&valueID, &valueCode, &valueName, &valueActive); err != nil {
+			return nil, fmt.Errorf("failed to scan dimension: %w", err)
+		}
+		if n := len(dims); n == 0 || dims[n-1].ID != d.ID {
+			d.Values = []DimensionValue{}
+			dims = append(dims, d)
+		}
+		if valueID != nil {
+			last := &dims[len(dims)-1]
+			last.Values = append(last.Values, DimensionValue{
+				ID: *valueID, DimensionCode: d.Code, Code: *valueCode, Name: *valueName, IsActive: *valueActive,
+			})
+		}
+	}
+	return dims, rows.Err()
+}
+
+func (s *dimensionService) CreateDimension(ctx context.Context, companyCode, code, name string) (*Dimension, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("dimension code is required")
+	}
+	if len(code) > 20 {
+		return nil, fmt.Errorf("dimension code %s is longer than 20 characters", code)
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = code
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSystemDimensions(ctx, s.pool, companyID); err != nil {
+		return nil, err
+	}
+
+	d := Dimension{Code: code, Name: name, IsActive: true, Values: []DimensionValue{}}
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO dimensions (company_id, code, name) VALUES ($1, $2, $3)
+		ON CONFLICT (company_id, code) DO NOTHING
+		RETURNING id`, companyID, code, name).Scan(&d.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("dimension %s already exists", code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create dimension: %w", err)
+	}
+	return &d, nil
+}
+
+func (s *dimensionService) CreateDimensionValue(ctx context.Context, companyCode, dimensionCode, code, name string) (*DimensionValue, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("dimension value code is required")
+	}
+	if len(code) > 20 {
+		return nil, fmt.Errorf("dimension value code %s is longer than 20 characters", code)
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = code
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	dimID, err := s.dimensionID(ctx, companyID, dimensionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	v := DimensionValue{DimensionCode: strings.ToUpper(strings.TrimSpace(dimensionCode)), Code: code, Name: name, IsActive: true}
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO dimension_values (dimension_id, code, name) VALUES ($1, $2, $3)
+		ON CONFLICT (dimension_id, code) DO NOTHING
+		RETURNING id`, dimID, code, name).Scan(&v.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s %s already exists", v.DimensionCode, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create dimension value: %w", err)
+	}
+	return &v, nil
+}
+
+func (s *dimensionService) SetDimensionValueActive(ctx context.Context, companyCode, dimensionCode, valueCode string, active bool) (*DimensionValue, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	dimID, err := s.dimensionID(ctx, companyID, dimensionCode)
+	if err != nil {
+		return nil, err
+	}
+	v := DimensionValue{DimensionCode: strings.ToUpper(strings.TrimSpace(dimensionCode))}
+	err = s.pool.QueryRow(ctx, `
+		UPDATE dimension_values SET is_active = $3
+		WHERE dimension_id = $1 AND code = $2
+		RETURNING id, code, name, is_active`,
+		dimID, strings.ToUpper(strings.TrimSpace(valueCode)), active,
+	).Scan(&v.ID, &v.Code, &v.Name, &v.IsActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s %s not found", v.DimensionCode, valueCode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update dimension value: %w", err)
+	}
+	return &v, nil
+}
+
+// ── Account rules ─────────────────────────────────────────────────────────────
+
+func (s *dimensionService) ListAccountDimensionRules(ctx context.Context, companyCode string) ([]AccountDimensionRule, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, d.code
+		FROM account_dimension_rules r
+		JOIN accounts a   ON a.id = r.account_id
+		JOIN dimensions d ON d.id = r.dimension_id
+		WHERE a.company_id = $1
+		ORDER BY a.code, d.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account dimension rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []AccountDimensionRule
+	for rows.Next() {
+		var r AccountDimensionRule
+		if err := rows.Scan(&r.AccountCode, &r.AccountName, &r.DimensionCode); err != nil {
+			return nil, fmt.Errorf("failed to scan account dimension rule: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func (s *dimensionService) SetAccountDimensionRequired(ctx context.Context, companyCode, accountCode, dimensionCode string, required bool) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	dimID, err := s.dimensionID(ctx, companyID, dimensionCode)
+	if err != nil {
+		return err
+	}
+	var accountID int
+	var isGroup bool
+	err = s.pool.QueryRow(ctx,
+		"SELECT id, is_group FROM accounts WHERE company_id = $1 AND code = $2",
+		companyID, accountCode).Scan(&accountID, &isGroup)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("account %s not found", accountCode)
+	}
+	if err != nil {
+		return fmt.Errorf("fetch account %s: %w", accountCode, err)
+	}
+	if isGroup {
+		return fmt.Errorf("account %s is a group account: set the rule on its sub-accounts", accountCode)
+	}
+
+	if required {
+		_, err = s.pool.Exec(ctx, `
+			INSERT INTO account_dimension_rules (account_id, dimension_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`, accountID, dimID)
+	} else {
+		_, err = s.pool.Exec(ctx,
+			"DELETE FROM account_dimension_rules WHERE account_id = $1 AND dimension_id = $2", accountID, dimID)
+	}
+	if err != nil {
+		return fmt.Errorf("update dimension rule for account %s: %w", accountCode, err)
+	}
+	return nil
+}
+
+// ── Ledger support ────────────────────────────────────────────────────────────
+
+// resolvedDimension is a line dimension resolved to its dimension and value ids.
+type resolvedDimension struct {
+	dimensionID int
+	valueID     int
+}
+
+// resolveLineDimensionsTx resolves the dimensions of a journal line being posted to an
+// account, refusing unknown or inactive dimensions and values and lines that miss a
+// dimension the account requires.
+func resolveLineDimensionsTx(ctx context.Context, tx pgx.Tx, companyID, accountID int, accountCode string, dims []LineDimension) ([]resolvedDimension, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT d.code
+		FROM account_dimension_rules r
+		JOIN dimensions d ON d.id = r.dimension_id
+		WHERE r.account_id = $1 AND d.is_active
+		ORDER BY d.code`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch dimension rules for account %s: %w", accountCode, err)
+	}
+	required, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("fetch dimension rules for account %s: %w", accountCode, err)
+	}
+	if missing := MissingDimensions(required, dims); len(missing) > 0 {
+		return nil, fmt.Errorf("account %s requires dimension %s on every posting", accountCode, strings.Join(missing, ", "))
+	}
+
+	resolved := make([]resolvedDimension, 0, len(dims))
+	for _, d := range dims {
+		dimCode := strings.ToUpper(strings.TrimSpace(d.Dimension))
+		valueCode := strings.ToUpper(strings.TrimSpace(d.Value))
+		var r resolvedDimension
+		var dimActive bool
+		var valueID *int
+		var valueActive *bool
+		err := tx.QueryRow(ctx, `
+			SELECT d.id, d.is_active, v.id, v.is_active
+			FROM dimensions d
+			LEFT JOIN dimension_values v ON v.dimension_id = d.id AND v.code = $3
+			WHERE d.company_id = $1 AND d.code = $2`,
+			companyID, dimCode, valueCode,
+		).Scan(&r.dimensionID, &dimActive, &valueID, &valueActive)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account %s: dimension %s not found", accountCode, dimCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch dimension %s: %w", dimCode, err)
+		}
+		switch {
+		case !dimActive:
+			return nil, fmt.Errorf("account %s: dimension %s is inactive", accountCode, dimCode)
+		case valueID == nil:
+			return nil, fmt.Errorf("account %s: %s %s not found", accountCode, dimCode, valueCode)
+		case !*valueActive:
+			return nil, fmt.Errorf("account %s: %s %s is inactive", accountCode, dimCode, valueCode)
+		}
+		r.valueID = *valueID
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// insertLineDimensionsTx tags a journal line with its resolved dimensions.
+func insertLineDimensionsTx(ctx context.Context, tx pgx.Tx, lineID int, dims []resolvedDimension) error {
+	for _, d := range dims {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO journal_line_dimensions (journal_line_id, dimension_id, value_id)
+			VALUES ($1, $2, $3)`, lineID, d.dimensionID, d.valueID,
+		); err != nil {
+			return fmt.Errorf("failed to insert journal line dimension: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateLineDimensionsTx checks the dimensions of an order or PO line against the
+// company's master data, as resolveLineDimensionsTx does at posting time (without the
+// account rules, which apply to the journal lines).
+func validateLineDimensionsTx(ctx context.Context, tx pgx.Tx, companyID int, dims []LineDimension) error {
+	if err := ValidateLineDimensions(dims); err != nil {
+		return err
+	}
+	for _, d := range dims {
+		var dimActive bool
+		var valueActive *bool
+		err := tx.QueryRow(ctx, `
+			SELECT d.is_active, v.is_active
+			FROM dimensions d
+			LEFT JOIN dimension_values v ON v.dimension_id = d.id AND v.code = $3
+			WHERE d.company_id = $1 AND d.code = $2`,
+			companyID, d.Dimension, d.Value,
+		).Scan(&dimActive, &valueActive)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("dimension %s not found", d.Dimension)
+		}
+		if err != nil {
+			return fmt.Errorf("fetch dimension %s: %w", d.Dimension, err)
+		}
+		switch {
+		case !dimActive:
+			return fmt.Errorf("dimension %s is inactive", d.Dimension)
+		case valueActive == nil:
+			return fmt.Errorf("%s %s not found", d.Dimension, d.Value)
+		case !*valueActive:
+			return fmt.Errorf("%s %s is inactive", d.Dimension, d.Value)
+		}
+	}
+	return nil
+}
+
+// ── Reports ───────────────────────────────────────────────────────────────────
+
+// dimensionBalance is the net debit of an account's journal lines carrying one value of
+// the breakdown dimension (valueID 0: lines without it).
+type dimensionBalance struct {
+	code, name string
+	accType    AccountType
+	valueID    int
+	net        decimal.Decimal
+}
+
+// resolveDimensionFilter returns the value ids of a report filter.
+func (s *dimensionService) resolveDimensionFilter(ctx context.Context, companyID int, filter []LineDimension) ([]int, error) {
+	ids := make([]int, 0, len(filter))
+	for _, f := range filter {
+		var id int
+		err := s.pool.QueryRow(ctx, `
+			SELECT v.id
+			FROM dimension_values v
+			JOIN dimensions d ON d.id = v.dimension_id
+			WHERE d.company_id = $1 AND d.code = $2 AND v.code = $3`,
+			companyID, f.Dimension, f.Value).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("filter: %s %s not found", f.Dimension, f.Value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s %s: %w", f.Dimension, f.Value, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// prepareDimensionReport resolves the company, the filter and the breakdown dimension
+// (id 0 when breakdown is empty).
+func (s *dimensionService) prepareDimensionReport(ctx context.Context, companyCode string, filter []LineDimension, breakdown string) (companyID int, filterIDs []int, breakdownID int, err error) {
+	if companyID, err = s.resolveCompanyID(ctx, companyCode); err != nil {
+		return 0, nil, 0, err
+	}
+	filter = NormalizeLineDimensions(filter)
+	if err = ValidateLineDimensions(filter); err != nil {
+		return 0, nil, 0, fmt.Errorf("filter: %w", err)
+	}
+	if filterIDs, err = s.resolveDimensionFilter(ctx, companyID, filter); err != nil {
+		return 0, nil, 0, err
+	}
+	if strings.TrimSpace(breakdown) != "" {
+		if breakdownID, err = s.dimensionID(ctx, companyID, breakdown); err != nil {
+			return 0, nil, 0, err
+		}
+	}
+	return companyID, filterIDs, breakdownID, nil
+}
+
+// queryDimensionBalances sums journal lines of the given account types whose entry matches
+// dateCond ($5 onwards are dateArgs) and that carry every filter value, per account and
+// breakdown value.
+func (s *dimensionService) queryDimensionBalances(ctx context.Context, companyID int, filterIDs []int, breakdownID int,
+	types []string, dateCond string, dateArgs ...any) ([]dimensionBalance, error) {
+
+	args := append([]any{companyID, filterIDs, breakdownID, types}, dateArgs...)
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, COALESCE(bd.value_id, 0),
+		       SUM(jl.debit_base) - SUM(jl.credit_base)
+		FROM journal_lines jl
+		JOIN journal_entries je ON je.id = jl.entry_id
+		JOIN accounts a         ON a.id = jl.account_id
+		LEFT JOIN journal_line_dimensions bd ON bd.journal_line_id = jl.id AND bd.dimension_id = $3
+		WHERE je.company_id = $1
+		  AND a.type = ANY($4)
+		  AND `+dateCond+`
+		  AND (SELECT count(*) FROM journal_line_dimensions f
+		       WHERE f.journal_line_id = jl.id AND f.value_id = ANY($2)) = cardinality($2::int[])
+		GROUP BY a.code, a.name, a.type, bd.value_id
+		ORDER BY a.code`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dimension balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []dimensionBalance
+	for rows.Next() {
+		var b dimensionBalance
+		if err := rows.Scan(&b.code, &b.name, &b.accType, &b.valueID, &b.net); err != nil {
+			return nil, fmt.Errorf("failed to scan dimension balance: %w", err)
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+// dimensionColumns returns the breakdown columns for balances — the values with postings
+// by code, then the untagged lines — and the column index of each value id.
+func (s *dimensionService) dimensionColumns(ctx context.Context, breakdownID int, balances []dimensionBalance) ([]DimensionColumn, map[int]int, error) {
+	if breakdownID == 0 {
+		return nil, nil, nil
+	}
+	used := map[int]bool{}
+	for _, b := range balances {
+		used[b.valueID] = true
+	}
+	rows, err := s.pool.Query(ctx,
+		"SELECT id, code, name FROM dimension_values WHERE dimension_id = $1 ORDER BY code", breakdownID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query dimension values: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []DimensionColumn
+	index := map[int]int{}
+	for rows.Next() {
+		var id int
+		var c DimensionColumn
+		if err := rows.Scan(&id, &c.Code, &c.Name); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan dimension value: %w", err)
+		}
+		if used[id] {
+			index[id] = len(columns)
+			columns = append(columns, c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if used[0] {
+		index[0] = len(columns)
+		columns = append(columns, DimensionColumn{Code: "", Name: "(none)"})
+	}
+	return columns, index, nil
+}
+
+// dimensionLines groups balances into one line per account, in the order given, negated
+// for credit-normal accounts when normalSign is set.
+func dimensionLines(balances []dimensionBalance, index map[int]int, columns int, normalSign bool) []DimensionReportLine {
+	var lines []DimensionReportLine
+	for _, b := range balances {
+		if n := len(lines); n == 0 || lines[n-1].AccountCode != b.code {
+			line := DimensionReportLine{AccountCode: b.code, AccountName: b.name, AccountType: b.accType}
+			if columns > 0 {
+				line.Amounts = make([]decimal.Decimal, columns)
+			}
+			lines = append(lines, line)
+		}
+		amount := b.net
+		if normalSign && !b.accType.IsDebitNormal() {
+			amount = amount.Neg()
+		}
+		line := &lines[len(lines)-1]
+		if columns > 0 {
+			line.Amounts[index[b.valueID]] = line.Amounts[index[b.valueID]].Add(amount)
+		}
+		line.Total = line.Total.Add(amount)
+	}
+	return lines
+}
+
+// sumDimensionLines adds up lines column by column into a totals line.
+func sumDimensionLines(name string, lines []DimensionReportLine, columns int) DimensionReportLine {
+	total := DimensionReportLine{AccountName: name}
+	if columns > 0 {
+		total.Amounts = make([]decimal.Decimal, columns)
+	}
+	for _, l := range lines {
+		for i, a := range l.Amounts {
+			total.Amounts[i] = total.Amounts[i].Add(a)
+		}
+		total.Total = total.Total.Add(l.Total)
+	}
+	return total
+}
+
+func (s *dimensionService) GetDimensionProfitAndLoss(ctx context.Context, companyCode string, period ReportPeriod, filter []LineDimension, breakdown string) (*DimensionPLReport, error) {
+	if _, err := time.Parse("2006-01-02", period.FromDate); err != nil {
+		return nil, fmt.Errorf("invalid period start %q", period.FromDate)
+	}
+	if _, err := time.Parse("2006-01-02", period.ToDate); err != nil {
+		return nil, fmt.Errorf("invalid period end %q", period.ToDate)
+	}
+	companyID, filterIDs, breakdownID, err := s.prepareDimensionReport(ctx, companyCode, filter, breakdown)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.queryDimensionBalances(ctx, companyID, filterIDs, breakdownID,
+		[]string{string(Revenue), string(Expense)},
+		"je.posting_date BETWEEN $5 AND $6", period.FromDate, period.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	columns, index, err := s.dimensionColumns(ctx, breakdownID, balances)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DimensionPLReport{
+		CompanyCode: companyCode,
+		Period:      period,
+		Filter:      NormalizeLineDimensions(filter),
+		Breakdown:   strings.ToUpper(strings.TrimSpace(breakdown)),
+		Columns:     columns,
+	}
+	for _, l := range dimensionLines(balances, index, len(columns), true) {
+		if l.AccountType == Revenue {
+			report.Revenue = append(report.Revenue, l)
+		} else {
+			report.Expenses = append(report.Expenses, l)
+		}
+	}
+	report.TotalRevenue = sumDimensionLines("Total Revenue", report.Revenue, len(columns))
+	report.TotalExpenses = sumDimensionLines("Total Expenses", report.Expenses, len(columns))
+	report.NetIncome = DimensionReportLine{
+		AccountName: "Net Income",
+		Total:       report.TotalRevenue.Total.Sub(report.TotalExpenses.Total),
+	}
+	if len(columns) > 0 {
+		report.NetIncome.Amounts = make([]decimal.Decimal, len(columns))
+		for i := range columns {
+			report.NetIncome.Amounts[i] = report.TotalRevenue.Amounts[i].Sub(report.TotalExpenses.Amounts[i])
+		}
+	}
+	return report, nil
+}
+
+func (s *dimensionService) GetDimensionTrialBalance(ctx context.Context, companyCode, asOfDate string, filter []LineDimension, breakdown string) (*DimensionTrialBalance, error) {
+	if asOfDate == "" {
+		asOfDate = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", asOfDate); err != nil {
+		return nil, fmt.Errorf("invalid as-of date %q (expected YYYY-MM-DD)", asOfDate)
+	}
+	companyID, filterIDs, breakdownID, err := s.prepareDimensionReport(ctx, companyCode, filter, breakdown)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.queryDimensionBalances(ctx, companyID, filterIDs, breakdownID,
+		[]string{string(Asset), string(Liability), string(Equity), string(Revenue), string(Expense)},
+		"je.posting_date <= $5", asOfDate)
+	if err != nil {
+		return nil, err
+	}
+	columns, index, err := s.dimensionColumns(ctx, breakdownID, balances)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := dimensionLines(balances, index, len(columns), false)
+	return &DimensionTrialBalance{
+		CompanyCode: companyCode,
+		AsOfDate:    asOfDate,
+		Filter:      NormalizeLineDimensions(filter),
+		Breakdown:   strings.ToUpper(strings.TrimSpace(breakdown)),
+		Columns:     columns,
+		Accounts:    accounts,
+		Net:         sumDimensionLines("Net", accounts, len(columns)),
+	}, nil
+}
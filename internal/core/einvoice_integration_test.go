@@ -0,0 +1,180 @@
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEInvoice_GenerateAndCancel(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedGST(t, ctx, pool)
+	seedInventoryTestData(t, ctx, pool)
+	invSvc := core.NewInventoryService(pool, core.NewRuleEngine(pool))
+	if err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001", decimal.NewFromInt(100), decimal.NewFromInt(300),
+		"2026-01-15", "2000", nil, ledger, docSvc); err != nil {
+		t.Fatalf("ReceiveStock: %v", err)
+	}
+
+	if _, err := pool.Exec(ctx, `
+		UPDATE customers SET state_code = '29', gstin = '29AAGCB1286Q1Z0', address = '1 Church Street, Bengaluru 560025'
+		WHERE company_id = 1 AND code = 'C001';
+		UPDATE customers SET state_code = '27' WHERE company_id = 1 AND code = 'C002';
+	`); err != nil {
+		t.Fatalf("set customer GST details: %v", err)
+	}
+	if _, err := orderSvc.SetProductTax(ctx, "1000", "P001", "8471", "GST18"); err != nil {
+		t.Fatalf("SetProductTax: %v", err)
+	}
+
+	svc := core.NewEInvoiceService(pool, orderSvc, core.NewStubIRPClient())
+
+	invoice := func(t *testing.T, customer string) *core.SalesOrder {
+		t.Helper()
+		order, err := orderSvc.CreateOrder(ctx, "1000", customer, "INR", decimal.NewFromInt(1), "2026-02-01",
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(10)}}, "e-invoice order")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, invSvc); err != nil {
+			t.Fatalf("ConfirmOrder: %v", err)
+		}
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docSvc); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+		order, err = orderSvc.InvoiceOrder(ctx, order.ID, ledger, docSvc)
+		if err != nil {
+			t.Fatalf("InvoiceOrder: %v", err)
+		}
+		return order
+	}
+	order := invoice(t, "C001")
+
+	t.Run("DisabledCompany_Rejected", func(t *testing.T) {
+		if _, err := svc.GenerateIRN(ctx, "1000", order.ID); err == nil {
+			t.Error("expected error generating an IRN before the company address is set")
+		}
+		if err := svc.SetEInvoicing(ctx, "1000", false, "12 MG Road, Bengaluru 560001"); err != nil {
+			t.Fatalf("SetEInvoicing: %v", err)
+		}
+		if _, err := svc.GenerateIRN(ctx, "1000", order.ID); err == nil {
+			t.Error("expected error generating an IRN with e-invoicing off")
+		}
+		if err := svc.SetEInvoicing(ctx, "1000", true, ""); err != nil {
+			t.Fatalf("SetEInvoicing: %v", err)
+		}
+	})
+
+	t.Run("Payload", func(t *testing.T) {
+		p, err := svc.BuildPayload(ctx, "1000", order.ID)
+		if err != nil {
+			t.Fatalf("BuildPayload: %v", err)
+		}
+		if p.SellerDtls.Pin != 560001 || p.BuyerDtls.Pin != 560025 || p.BuyerDtls.Pos != "29" {
+			t.Errorf("unexpected parties: %+v / %+v", p.SellerDtls, p.BuyerDtls)
+		}
+		if got := decimal.Decimal(p.ValDtls.TotInvVal).StringFixed(2); got != "5900.00" {
+			t.Errorf("TotInvVal: got %s, want 5900.00", got)
+		}
+	})
+
+	var irn string
+	t.Run("Generate", func(t *testing.T) {
+		ei, err := svc.GenerateIRN(ctx, "1000", order.ID)
+		if err != nil {
+			t.Fatalf("GenerateIRN: %v", err)
+		}
+		if ei.Status != "GENERATED" || len(ei.IRN) != 64 || ei.AckNumber == "" {
+			t.Errorf("unexpected e-invoice: %+v", ei)
+		}
+		irn = ei.IRN
+		if _, err := svc.GenerateIRN(ctx, "1000", order.ID); err == nil {
+			t.Error("expected error generating a second IRN for the invoice")
+		}
+	})
+
+	t.Run("ReverseRefused", func(t *testing.T) {
+		var entryID int
+		if err := pool.QueryRow(ctx, "SELECT id FROM journal_entries WHERE idempotency_key = $1",
+			fmt.Sprintf("invoice-order-%d", order.ID)).Scan(&entryID); err != nil {
+			t.Fatalf("find invoice entry: %v", err)
+		}
+		err := ledger.Reverse(ctx, entryID, "mistake")
+		if err == nil || !strings.Contains(err.Error(), irn) {
+			t.Errorf("expected reversal refused for e-invoiced entry, got %v", err)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		if _, err := svc.CancelEInvoice(ctx, "1000", order.ID, 2, "", ledger); err == nil {
+			t.Error("expected error cancelling without a remark")
+		}
+		ei, err := svc.CancelEInvoice(ctx, "1000", order.ID, 2, "wrong quantity", ledger)
+		if err != nil {
+			t.Fatalf("CancelEInvoice: %v", err)
+		}
+		if ei.Status != "CANCELLED" || ei.CancelledAt == nil {
+			t.Errorf("unexpected e-invoice after cancel: %+v", ei)
+		}
+		cancelled, err := orderSvc.GetOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+		if cancelled.Status != "SHIPPED" || cancelled.InvoiceDocumentID != nil {
+			t.Errorf("expected order back in SHIPPED without an invoice, got %s / %v", cancelled.Status, cancelled.InvoiceDocumentID)
+		}
+		bm := gstBalances(t, ctx, ledger)
+		if bm["1200"] != "0.00" || bm["2310"] != "0.00" || bm["2320"] != "0.00" {
+			t.Errorf("expected AR and GST payable reversed, got %s / %s / %s", bm["1200"], bm["2310"], bm["2320"])
+		}
+		// The goods are still shipped: 10 × 300 stays in COGS and out of inventory.
+		if bm["5000"] != "3000.00" || bm["1400"] != "27000.00" {
+			t.Errorf("expected COGS 3000.00 and inventory 27000.00 to stand, got %s / %s", bm["5000"], bm["1400"])
+		}
+		if onHand, _ := getStockInfo(t, ctx, invSvc, "1000", "P001"); !onHand.Equal(decimal.NewFromInt(90)) {
+			t.Errorf("expected 90 on hand, got %s", onHand)
+		}
+	})
+
+	t.Run("Reinvoice", func(t *testing.T) {
+		reinvoiced, err := orderSvc.InvoiceOrder(ctx, order.ID, ledger, docSvc)
+		if err != nil {
+			t.Fatalf("InvoiceOrder after cancellation: %v", err)
+		}
+		if reinvoiced.Status != "INVOICED" || reinvoiced.InvoiceDocumentID == nil || *reinvoiced.InvoiceDocumentID == *order.InvoiceDocumentID {
+			t.Errorf("expected a new invoice document, got %s / %v", reinvoiced.Status, reinvoiced.InvoiceDocumentID)
+		}
+		ei, err := svc.GenerateIRN(ctx, "1000", order.ID)
+		if err != nil {
+			t.Fatalf("GenerateIRN for the re-invoice: %v", err)
+		}
+		if ei.IRN == irn || ei.InvoiceNumber != ei.Payload.DocDtls.No {
+			t.Errorf("expected a new IRN for the re-invoice, got %+v", ei)
+		}
+		var cancelledNumber string
+		if err := pool.QueryRow(ctx, `
+			SELECT d.document_number FROM einvoices ei JOIN documents d ON d.id = ei.invoice_document_id
+			WHERE ei.irn = $1`, irn).Scan(&cancelledNumber); err != nil {
+			t.Fatalf("fetch cancelled e-invoice: %v", err)
+		}
+		if cancelledNumber == ei.InvoiceNumber {
+			t.Errorf("cancelled and new e-invoice share invoice number %s", cancelledNumber)
+		}
+		bm := gstBalances(t, ctx, ledger)
+		if bm["1200"] != "5900.00" || bm["5000"] != "3000.00" {
+			t.Errorf("expected AR 5900.00 and COGS 3000.00 after re-invoicing, got %s / %s", bm["1200"], bm["5000"])
+		}
+	})
+
+	t.Run("B2C_Rejected", func(t *testing.T) {
+		b2c := invoice(t, "C002")
+		if _, err := svc.GenerateIRN(ctx, "1000", b2c.ID); err == nil {
+			t.Error("expected error e-invoicing a customer without a GSTIN")
+		}
+	})
+}
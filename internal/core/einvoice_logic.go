@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var pinPattern = regexp.MustCompile(`\b[1-9][0-9]{5}\b`)
+
+// ParseIndianAddress splits a free-text address such as "12 MG Road, Bengaluru 560001" into
+// the INV-01 address line, location and PIN code: the PIN is the last 6-digit number and the
+// location the last comma-separated part once the PIN is removed.
+func ParseIndianAddress(address string) (addr1, loc string, pin int, err error) {
+	matches := pinPattern.FindAllStringIndex(address, -1)
+	if len(matches) == 0 {
+		return "", "", 0, fmt.Errorf("address %q has no 6-digit PIN code", address)
+	}
+	m := matches[len(matches)-1]
+	pin, _ = strconv.Atoi(address[m[0]:m[1]])
+	rest := strings.TrimSpace(address[:m[0]] + address[m[1]:])
+
+	var parts []string
+	for _, p := range strings.Split(rest, ",") {
+		if p = strings.Trim(strings.TrimSpace(p), "-"); p != "" {
+			parts = append(parts, strings.TrimSpace(p))
+		}
+	}
+	if len(parts) == 0 {
+		return "", "", 0, fmt.Errorf("address %q has no location", address)
+	}
+	loc = parts[len(parts)-1]
+	addr1 = strings.Join(parts, ", ")
+	if len(parts) > 1 {
+		addr1 = strings.Join(parts[:len(parts)-1], ", ")
+	}
+	if len(addr1) > 100 {
+		addr1 = addr1[:100]
+	}
+	if len(loc) < 3 {
+		return "", "", 0, fmt.Errorf("address %q: location %q is too short", address, loc)
+	}
+	return addr1, loc, pin, nil
+}
+
+// BuildEInvoicePayload builds the INV-01 payload of an invoiced sales order. Amounts are
+// converted to INR at the order's exchange rate. It reports every missing HSN code at once.
+func BuildEInvoicePayload(seller, buyer EInvoiceParty, order *SalesOrder, invoiceNumber string, invoiceDate time.Time) (*EInvoicePayload, error) {
+	rate := order.ExchangeRate
+	base := func(d decimal.Decimal) decimal.Decimal { return d.Mul(rate).Round(2) }
+
+	p := &EInvoicePayload{
+		Version:    "1.1",
+		TranDtls:   EInvoiceTran{TaxSch: "GST", SupTyp: "B2B", RegRev: "N", IgstOnIntra: "N"},
+		DocDtls:    EInvoiceDoc{Typ: "INV", No: invoiceNumber, Dt: invoiceDate.Format("02/01/2006")},
+		SellerDtls: seller,
+		BuyerDtls:  buyer,
+	}
+	var missing []string
+	var assVal, igst, cgst, sgst decimal.Decimal
+	for i, l := range order.Lines {
+		hsn := ""
+		if l.HSNCode != nil {
+			hsn = *l.HSNCode
+		}
+		if hsn == "" {
+			missing = append(missing, fmt.Sprintf("line %d (%s) has no HSN/SAC code", l.LineNumber, l.ProductCode))
+		}
+		var gstRate, li, lc, ls decimal.Decimal
+		for _, t := range l.Taxes {
+			gstRate = gstRate.Add(t.Rate)
+			switch t.Component {
+			case GSTComponentIGST:
+				li = li.Add(t.TaxAmount)
+			case GSTComponentCGST:
+				lc = lc.Add(t.TaxAmount)
+			case GSTComponentSGST:
+				ls = ls.Add(t.TaxAmount)
+			}
+		}
+		li, lc, ls = base(li), base(lc), base(ls)
+		assAmt := base(l.LineTotalTransaction)
+		isService := "N"
+		if strings.HasPrefix(hsn, "99") {
+			isService = "Y"
+		}
+		p.ItemList = append(p.ItemList, EInvoiceItem{
+			SlNo:       strconv.Itoa(i + 1),
+			PrdDesc:    l.ProductName,
+			IsServc:    isService,
+			HsnCd:      hsn,
+			Qty:        GSTAmount(l.Quantity),
+			Unit:       strings.ToUpper(l.Unit),
+			UnitPrice:  GSTAmount(base(l.UnitPrice)),
+			TotAmt:     GSTAmount(assAmt),
+			AssAmt:     GSTAmount(assAmt),
+			GstRt:      GSTAmount(gstRate),
+			IgstAmt:    GSTAmount(li),
+			CgstAmt:    GSTAmount(lc),
+			SgstAmt:    GSTAmount(ls),
+			TotItemVal: GSTAmount(assAmt.Add(li).Add(lc).Add(ls)),
+		})
+		assVal = assVal.Add(assAmt)
+		igst, cgst, sgst = igst.Add(li), cgst.Add(lc), sgst.Add(ls)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("e-invoice for %s: %s", invoiceNumber, strings.Join(missing, "; "))
+	}
+	p.ValDtls = EInvoiceValues{
+		AssVal:    GSTAmount(assVal),
+		CgstVal:   GSTAmount(cgst),
+		SgstVal:   GSTAmount(sgst),
+		IgstVal:   GSTAmount(igst),
+		TotInvVal: GSTAmount(assVal.Add(igst).Add(cgst).Add(sgst)),
+	}
+	return p, nil
+}
@@ -0,0 +1,178 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseIndianAddress(t *testing.T) {
+	cases := []struct {
+		address   string
+		addr1     string
+		loc       string
+		pin       int
+		wantError bool
+	}{
+		{"12 MG Road, Bengaluru 560001", "12 MG Road", "Bengaluru", 560001, false},
+		{"Plot 4, Sector 62, Noida - 201309", "Plot 4, Sector 62", "Noida", 201309, false},
+		{"Andheri East 400069", "Andheri East", "Andheri East", 400069, false},
+		{"Unit 110001, Connaught Place, New Delhi 110001", "Unit 110001, Connaught Place", "New Delhi", 110001, false},
+		{"12 MG Road, Bengaluru", "", "", 0, true},
+		{"560001", "", "", 0, true},
+	}
+	for _, c := range cases {
+		addr1, loc, pin, err := core.ParseIndianAddress(c.address)
+		if c.wantError {
+			if err == nil {
+				t.Errorf("ParseIndianAddress(%q): expected error", c.address)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIndianAddress(%q): %v", c.address, err)
+			continue
+		}
+		if addr1 != c.addr1 || loc != c.loc || pin != c.pin {
+			t.Errorf("ParseIndianAddress(%q) = %q, %q, %d; want %q, %q, %d",
+				c.address, addr1, loc, pin, c.addr1, c.loc, c.pin)
+		}
+	}
+}
+
+func eInvoiceOrder() *core.SalesOrder {
+	d := decimal.RequireFromString
+	hsn, sac := "8471", "9983"
+	return &core.SalesOrder{
+		ExchangeRate: d("1"),
+		Lines: []core.SalesOrderLine{
+			{LineNumber: 1, ProductCode: "P001", ProductName: "Widget", Quantity: d("2"), Unit: "unit",
+				UnitPrice: d("500"), LineTotalTransaction: d("1000"), HSNCode: &hsn,
+				Taxes: []core.LineTax{
+					{Component: core.GSTComponentCGST, Rate: d("9"), TaxAmount: d("90")},
+					{Component: core.GSTComponentSGST, Rate: d("9"), TaxAmount: d("90")},
+				}},
+			{LineNumber: 2, ProductCode: "S001", ProductName: "Consulting", Quantity: d("1"), Unit: "hour",
+				UnitPrice: d("500"), LineTotalTransaction: d("500"), HSNCode: &sac,
+				Taxes: []core.LineTax{
+					{Component: core.GSTComponentCGST, Rate: d("6"), TaxAmount: d("30")},
+					{Component: core.GSTComponentSGST, Rate: d("6"), TaxAmount: d("30")},
+				}},
+		},
+	}
+}
+
+func TestBuildEInvoicePayload(t *testing.T) {
+	seller := core.EInvoiceParty{Gstin: "29AAPFU0939F1ZR", LglNm: "Seller", Addr1: "12 MG Road", Loc: "Bengaluru", Pin: 560001, Stcd: "29"}
+	buyer := core.EInvoiceParty{Gstin: "29AAGCB1286Q1Z0", LglNm: "Buyer", Pos: "29", Addr1: "1 Church St", Loc: "Bengaluru", Pin: 560025, Stcd: "29"}
+	date := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	p, err := core.BuildEInvoicePayload(seller, buyer, eInvoiceOrder(), "SI-1", date)
+	if err != nil {
+		t.Fatalf("BuildEInvoicePayload: %v", err)
+	}
+	if p.DocDtls.Dt != "03/02/2026" || p.TranDtls.SupTyp != "B2B" {
+		t.Errorf("doc details: %+v %+v", p.DocDtls, p.TranDtls)
+	}
+	if len(p.ItemList) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(p.ItemList))
+	}
+	if p.ItemList[0].IsServc != "N" || p.ItemList[1].IsServc != "Y" {
+		t.Errorf("IsServc: got %s, %s", p.ItemList[0].IsServc, p.ItemList[1].IsServc)
+	}
+	if got := decimal.Decimal(p.ItemList[0].GstRt).String(); got != "18" {
+		t.Errorf("line 1 GstRt: got %s, want 18", got)
+	}
+	if got := decimal.Decimal(p.ItemList[0].TotItemVal).StringFixed(2); got != "1180.00" {
+		t.Errorf("line 1 TotItemVal: got %s, want 1180.00", got)
+	}
+	v := p.ValDtls
+	for name, c := range map[string]struct {
+		got  core.GSTAmount
+		want string
+	}{
+		"AssVal":    {v.AssVal, "1500.00"},
+		"CgstVal":   {v.CgstVal, "120.00"},
+		"SgstVal":   {v.SgstVal, "120.00"},
+		"IgstVal":   {v.IgstVal, "0.00"},
+		"TotInvVal": {v.TotInvVal, "1740.00"},
+	} {
+		if got := decimal.Decimal(c.got).StringFixed(2); got != c.want {
+			t.Errorf("%s: got %s, want %s", name, got, c.want)
+		}
+	}
+}
+
+func TestBuildEInvoicePayload_ForeignCurrency(t *testing.T) {
+	order := eInvoiceOrder()
+	order.ExchangeRate = decimal.RequireFromString("83.25")
+	p, err := core.BuildEInvoicePayload(core.EInvoiceParty{}, core.EInvoiceParty{}, order, "SI-2", time.Now())
+	if err != nil {
+		t.Fatalf("BuildEInvoicePayload: %v", err)
+	}
+	if got := decimal.Decimal(p.ValDtls.AssVal).StringFixed(2); got != "124875.00" {
+		t.Errorf("AssVal: got %s, want 124875.00", got)
+	}
+}
+
+func TestBuildEInvoicePayload_MissingHSN(t *testing.T) {
+	order := eInvoiceOrder()
+	order.Lines[0].HSNCode = nil
+	empty := ""
+	order.Lines[1].HSNCode = &empty
+	_, err := core.BuildEInvoicePayload(core.EInvoiceParty{}, core.EInvoiceParty{}, order, "SI-3", time.Now())
+	if err == nil {
+		t.Fatal("expected error for lines without HSN codes")
+	}
+	if !strings.Contains(err.Error(), "P001") || !strings.Contains(err.Error(), "S001") {
+		t.Errorf("expected both lines reported, got %v", err)
+	}
+}
+
+func TestStubIRPClient(t *testing.T) {
+	ctx := context.Background()
+	seller := core.EInvoiceParty{Gstin: "29AAPFU0939F1ZR"}
+	payload, err := core.BuildEInvoicePayload(seller, core.EInvoiceParty{}, eInvoiceOrder(), "SI-1",
+		time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BuildEInvoicePayload: %v", err)
+	}
+
+	first, err := core.NewStubIRPClient().GenerateIRN(ctx, payload)
+	if err != nil {
+		t.Fatalf("GenerateIRN: %v", err)
+	}
+	if len(first.IRN) != 64 {
+		t.Errorf("IRN should be 64 hex characters, got %q", first.IRN)
+	}
+	if strings.Count(first.SignedQRCode, ".") != 2 {
+		t.Errorf("signed QR code is not JWS-shaped: %q", first.SignedQRCode)
+	}
+
+	irp := core.NewStubIRPClient()
+	second, err := irp.GenerateIRN(ctx, payload)
+	if err != nil {
+		t.Fatalf("GenerateIRN: %v", err)
+	}
+	if second.IRN != first.IRN {
+		t.Errorf("IRN should depend only on GSTIN, year and document: %s vs %s", first.IRN, second.IRN)
+	}
+	if _, err := irp.GenerateIRN(ctx, payload); err == nil {
+		t.Error("expected duplicate IRN error")
+	}
+
+	if _, err := irp.CancelIRN(ctx, seller.Gstin, second.IRN, 5, "wrong"); err == nil {
+		t.Error("expected invalid reason code error")
+	}
+	if _, err := irp.CancelIRN(ctx, seller.Gstin, second.IRN, 2, "wrong rate"); err != nil {
+		t.Fatalf("CancelIRN: %v", err)
+	}
+	if _, err := irp.CancelIRN(ctx, seller.Gstin, second.IRN, 2, "wrong rate"); err == nil {
+		t.Error("expected already-cancelled error")
+	}
+}
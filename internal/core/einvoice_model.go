@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// EInvoicePayload is an invoice in the INV-01 e-invoice schema (version 1.1) submitted to
+// the Invoice Registration Portal. Amounts are in INR.
+type EInvoicePayload struct {
+	Version    string         `json:"Version"`
+	TranDtls   EInvoiceTran   `json:"TranDtls"`
+	DocDtls    EInvoiceDoc    `json:"DocDtls"`
+	SellerDtls EInvoiceParty  `json:"SellerDtls"`
+	BuyerDtls  EInvoiceParty  `json:"BuyerDtls"`
+	ItemList   []EInvoiceItem `json:"ItemList"`
+	ValDtls    EInvoiceValues `json:"ValDtls"`
+}
+
+// EInvoiceTran holds the transaction details: tax scheme and supply type.
+type EInvoiceTran struct {
+	TaxSch      string `json:"TaxSch"` // GST
+	SupTyp      string `json:"SupTyp"` // B2B
+	RegRev      string `json:"RegRev"` // reverse charge, Y/N
+	IgstOnIntra string `json:"IgstOnIntra"`
+}
+
+// EInvoiceDoc identifies the invoice.
+type EInvoiceDoc struct {
+	Typ string `json:"Typ"` // INV
+	No  string `json:"No"`
+	Dt  string `json:"Dt"` // DD/MM/YYYY
+}
+
+// EInvoiceParty is the seller or buyer. Pos (place of supply) is set for the buyer only.
+type EInvoiceParty struct {
+	Gstin string `json:"Gstin"`
+	LglNm string `json:"LglNm"`
+	Pos   string `json:"Pos,omitempty"`
+	Addr1 string `json:"Addr1"`
+	Loc   string `json:"Loc"`
+	Pin   int    `json:"Pin"`
+	Stcd  string `json:"Stcd"`
+	Ph    string `json:"Ph,omitempty"`
+	Em    string `json:"Em,omitempty"`
+}
+
+// EInvoiceItem is one invoice line.
+type EInvoiceItem struct {
+	SlNo       string    `json:"SlNo"`
+	PrdDesc    string    `json:"PrdDesc"`
+	IsServc    string    `json:"IsServc"` // Y for SAC (99xx) codes
+	HsnCd      string    `json:"HsnCd"`
+	Qty        GSTAmount `json:"Qty"`
+	Unit       string    `json:"Unit"`
+	UnitPrice  GSTAmount `json:"UnitPrice"`
+	TotAmt     GSTAmount `json:"TotAmt"`
+	Discount   GSTAmount `json:"Discount"`
+	AssAmt     GSTAmount `json:"AssAmt"` // taxable value
+	GstRt      GSTAmount `json:"GstRt"`
+	IgstAmt    GSTAmount `json:"IgstAmt"`
+	CgstAmt    GSTAmount `json:"CgstAmt"`
+	SgstAmt    GSTAmount `json:"SgstAmt"`
+	CesRt      GSTAmount `json:"CesRt"`
+	CesAmt     GSTAmount `json:"CesAmt"`
+	TotItemVal GSTAmount `json:"TotItemVal"`
+}
+
+// EInvoiceValues holds the invoice totals.
+type EInvoiceValues struct {
+	AssVal    GSTAmount `json:"AssVal"`
+	CgstVal   GSTAmount `json:"CgstVal"`
+	SgstVal   GSTAmount `json:"SgstVal"`
+	IgstVal   GSTAmount `json:"IgstVal"`
+	CesVal    GSTAmount `json:"CesVal"`
+	Discount  GSTAmount `json:"Discount"`
+	OthChrg   GSTAmount `json:"OthChrg"`
+	RndOffAmt GSTAmount `json:"RndOffAmt"`
+	TotInvVal GSTAmount `json:"TotInvVal"`
+}
+
+// IRPAck is the IRP's response to a registered invoice.
+type IRPAck struct {
+	IRN           string
+	AckNumber     string
+	AckDate       time.Time
+	SignedInvoice string // JWS of the invoice, signed by the IRP
+	SignedQRCode  string // JWS printed as the invoice QR code
+}
+
+// IRPClient registers and cancels e-invoices with an Invoice Registration Portal.
+type IRPClient interface {
+	// GenerateIRN registers the invoice and returns its IRN and signed data.
+	GenerateIRN(ctx context.Context, payload *EInvoicePayload) (*IRPAck, error)
+
+	// CancelIRN cancels an IRN for the seller gstin with a reason code (1 duplicate, 2 data
+	// entry mistake, 3 order cancelled, 4 other), returning the cancellation time.
+	CancelIRN(ctx context.Context, gstin, irn string, reasonCode int, remark string) (time.Time, error)
+}
+
+// EInvoice is the IRN registration of a sales invoice.
+type EInvoice struct {
+	ID               int
+	OrderID          int
+	OrderNumber      string
+	InvoiceNumber    string
+	IRN              string
+	AckNumber        string
+	AckDate          time.Time
+	SignedInvoice    string
+	SignedQRCode     string
+	Payload          EInvoicePayload
+	Status           string // GENERATED | CANCELLED
+	CancelReasonCode *int
+	CancelRemark     *string
+	CancelledAt      *time.Time
+	CreatedAt        time.Time
+}
+
+// EInvoiceCancelWindow is how long after its acknowledgement the IRP accepts an IRN
+// cancellation. Later corrections need a credit note.
+const EInvoiceCancelWindow = 24 * time.Hour
+
+// EInvoiceService builds INV-01 payloads for B2B sales invoices and keeps the IRN returned
+// by the IRP.
+type EInvoiceService interface {
+	// SetEInvoicing turns e-invoicing on or off for a company (on once its aggregate
+	// turnover exceeds the notified threshold) and sets the seller address printed on the
+	// payload; an empty address leaves it unchanged.
+	SetEInvoicing(ctx context.Context, companyCode string, enabled bool, address string) error
+
+	// BuildPayload returns the INV-01 payload of an INVOICED or PAID order to a customer
+	// with a GSTIN. Every line needs an HSN/SAC code, and both addresses a 6-digit PIN.
+	BuildPayload(ctx context.Context, companyCode string, orderID int) (*EInvoicePayload, error)
+
+	// GenerateIRN registers the order's invoice with the IRP and stores the IRN. Fails if
+	// e-invoicing is off for the company or the order already has a live IRN.
+	GenerateIRN(ctx context.Context, companyCode string, orderID int) (*EInvoice, error)
+
+	// GetEInvoice returns the order's latest e-invoice.
+	GetEInvoice(ctx context.Context, companyCode string, orderID int) (*EInvoice, error)
+
+	// CancelEInvoice cancels the order's IRN at the IRP (INVOICED orders only, within
+	// EInvoiceCancelWindow of the acknowledgement), reverses the invoice journal entry and
+	// moves the order back to SHIPPED so it can be invoiced again; the shipment stands.
+	CancelEInvoice(ctx context.Context, companyCode string, orderID, reasonCode int, remark string, ledger *Ledger) (*EInvoice, error)
+}
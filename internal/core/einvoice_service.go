@@ -0,0 +1,284 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type eInvoiceService struct {
+	pool         *pgxpool.Pool
+	orderService OrderService
+	irp          IRPClient
+}
+
+// NewEInvoiceService constructs an EInvoiceService that registers invoices with irp.
+func NewEInvoiceService(pool *pgxpool.Pool, orderService OrderService, irp IRPClient) EInvoiceService {
+	return &eInvoiceService{pool: pool, orderService: orderService, irp: irp}
+}
+
+// SetEInvoicing turns e-invoicing on or off and optionally sets the seller address.
+func (s *eInvoiceService) SetEInvoicing(ctx context.Context, companyCode string, enabled bool, address string) error {
+	address = strings.TrimSpace(address)
+	if address != "" {
+		if _, _, _, err := ParseIndianAddress(address); err != nil {
+			return err
+		}
+	}
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE companies
+		SET einvoice_enabled = $2, address = COALESCE(NULLIF($3, ''), address)
+		WHERE company_code = $1`,
+		companyCode, enabled, address,
+	)
+	if err != nil {
+		return fmt.Errorf("update e-invoicing for company %s: %w", companyCode, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("company %s not found", companyCode)
+	}
+	return nil
+}
+
+// BuildPayload returns the INV-01 payload of an invoiced order.
+func (s *eInvoiceService) BuildPayload(ctx context.Context, companyCode string, orderID int) (*EInvoicePayload, error) {
+	payload, _, err := s.buildPayload(ctx, companyCode, orderID)
+	return payload, err
+}
+
+// buildPayload loads the order, seller and buyer and builds the payload. It also reports
+// whether e-invoicing is enabled for the company.
+func (s *eInvoiceService) buildPayload(ctx context.Context, companyCode string, orderID int) (*EInvoicePayload, bool, error) {
+	var companyID int
+	var name string
+	var gstin, stateCode, address *string
+	var enabled bool
+	if err := s.pool.QueryRow(ctx, `
+		SELECT id, name, gstin, state_code, address, einvoice_enabled
+		FROM companies WHERE company_code = $1`, companyCode,
+	).Scan(&companyID, &name, &gstin, &stateCode, &address, &enabled); err != nil {
+		return nil, false, fmt.Errorf("company %s not found: %w", companyCode, err)
+	}
+	if gstin == nil || stateCode == nil {
+		return nil, false, fmt.Errorf("company %s has no GST registration", companyCode)
+	}
+	if address == nil {
+		return nil, false, fmt.Errorf("company %s has no address: set it with the e-invoicing settings", companyCode)
+	}
+
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, false, err
+	}
+	if order.CompanyID != companyID {
+		return nil, false, fmt.Errorf("order %d not found in company %s", orderID, companyCode)
+	}
+	if order.Status != "INVOICED" && order.Status != "PAID" {
+		return nil, false, fmt.Errorf("order %s is %s: only invoiced orders can be e-invoiced", order.OrderNumber, order.Status)
+	}
+
+	var invoiceNumber string
+	var custGSTIN, custState *string
+	var custAddress, phone, email string
+	if err := s.pool.QueryRow(ctx, `
+		SELECT d.document_number, c.gstin, c.state_code,
+		       COALESCE(c.address, ''), COALESCE(c.phone, ''), COALESCE(c.email, '')
+		FROM sales_orders so
+		JOIN documents d ON d.id = so.invoice_document_id
+		JOIN customers c ON c.id = so.customer_id
+		WHERE so.id = $1`, orderID,
+	).Scan(&invoiceNumber, &custGSTIN, &custState, &custAddress, &phone, &email); err != nil {
+		return nil, false, fmt.Errorf("fetch invoice of order %s: %w", order.OrderNumber, err)
+	}
+	if custGSTIN == nil || custState == nil {
+		return nil, false, fmt.Errorf("customer %s has no GSTIN: e-invoices are for B2B supplies only", order.CustomerCode)
+	}
+
+	sellerAddr, sellerLoc, sellerPin, err := ParseIndianAddress(*address)
+	if err != nil {
+		return nil, false, fmt.Errorf("company %s: %w", companyCode, err)
+	}
+	buyerAddr, buyerLoc, buyerPin, err := ParseIndianAddress(custAddress)
+	if err != nil {
+		return nil, false, fmt.Errorf("customer %s: %w", order.CustomerCode, err)
+	}
+	seller := EInvoiceParty{Gstin: *gstin, LglNm: name, Addr1: sellerAddr, Loc: sellerLoc, Pin: sellerPin, Stcd: *stateCode}
+	buyer := EInvoiceParty{Gstin: *custGSTIN, LglNm: order.CustomerName, Pos: *custState,
+		Addr1: buyerAddr, Loc: buyerLoc, Pin: buyerPin, Stcd: *custState, Em: email}
+	// The IRP accepts 6–12 digit phone numbers only.
+	if digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone); len(digits) >= 6 && len(digits) <= 12 {
+		buyer.Ph = digits
+	}
+
+	payload, err := BuildEInvoicePayload(seller, buyer, order, invoiceNumber, *order.InvoicedAt)
+	if err != nil {
+		return nil, false, err
+	}
+	return payload, enabled, nil
+}
+
+// GenerateIRN registers the invoice with the IRP and stores the acknowledgement.
+func (s *eInvoiceService) GenerateIRN(ctx context.Context, companyCode string, orderID int) (*EInvoice, error) {
+	payload, enabled, err := s.buildPayload(ctx, companyCode, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, fmt.Errorf("e-invoicing is not enabled for company %s", companyCode)
+	}
+	var existing string
+	err = s.pool.QueryRow(ctx,
+		"SELECT irn FROM einvoices WHERE order_id = $1 AND status = 'GENERATED'", orderID,
+	).Scan(&existing)
+	if err == nil {
+		return nil, fmt.Errorf("invoice %s already has IRN %s", payload.DocDtls.No, existing)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("check existing IRN: %w", err)
+	}
+
+	ack, err := s.irp.GenerateIRN(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("generate IRN for invoice %s: %w", payload.DocDtls.No, err)
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode e-invoice payload: %w", err)
+	}
+	// If this insert fails the IRN exists at the IRP but not here; the IRP returns the same
+	// IRN for the document number, so it can be recovered from there.
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO einvoices (company_id, order_id, invoice_document_id, irn, ack_number, ack_date, signed_invoice, signed_qr_code, payload)
+		SELECT company_id, id, invoice_document_id, $2, $3, $4, $5, $6, $7 FROM sales_orders WHERE id = $1`,
+		orderID, ack.IRN, ack.AckNumber, ack.AckDate, ack.SignedInvoice, ack.SignedQRCode, payloadJSON,
+	); err != nil {
+		return nil, fmt.Errorf("store IRN %s for invoice %s: %w", ack.IRN, payload.DocDtls.No, err)
+	}
+	return s.GetEInvoice(ctx, companyCode, orderID)
+}
+
+const eInvoiceSelect = `
+	SELECT ei.id, ei.order_id, so.order_number, d.document_number, ei.irn, ei.ack_number, ei.ack_date,
+	       ei.signed_invoice, ei.signed_qr_code, ei.payload, ei.status,
+	       ei.cancel_reason_code, ei.cancel_remark, ei.cancelled_at, ei.created_at
+	FROM einvoices ei
+	JOIN sales_orders so ON so.id = ei.order_id
+	JOIN companies c     ON c.id = ei.company_id
+	JOIN documents d     ON d.id = ei.invoice_document_id`
+
+// GetEInvoice returns the order's most recent e-invoice.
+func (s *eInvoiceService) GetEInvoice(ctx context.Context, companyCode string, orderID int) (*EInvoice, error) {
+	var e EInvoice
+	var payload []byte
+	var reasonCode *int16
+	err := s.pool.QueryRow(ctx, eInvoiceSelect+`
+		WHERE c.company_code = $1 AND ei.order_id = $2
+		ORDER BY ei.id DESC
+		LIMIT 1`, companyCode, orderID,
+	).Scan(&e.ID, &e.OrderID, &e.OrderNumber, &e.InvoiceNumber, &e.IRN, &e.AckNumber, &e.AckDate,
+		&e.SignedInvoice, &e.SignedQRCode, &payload, &e.Status,
+		&reasonCode, &e.CancelRemark, &e.CancelledAt, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("order %d has no e-invoice", orderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch e-invoice of order %d: %w", orderID, err)
+	}
+	if err := json.Unmarshal(payload, &e.Payload); err != nil {
+		return nil, fmt.Errorf("decode e-invoice payload of order %d: %w", orderID, err)
+	}
+	if reasonCode != nil {
+		code := int(*reasonCode)
+		e.CancelReasonCode = &code
+	}
+	return &e, nil
+}
+
+// CancelEInvoice cancels the IRN, reverses the invoice entry and moves the order back to
+// SHIPPED in one transaction, so the order can be invoiced again; the shipment's COGS and
+// stock postings stand. The IRP is called last, so a refusal there leaves everything unchanged.
+func (s *eInvoiceService) CancelEInvoice(ctx context.Context, companyCode string, orderID, reasonCode int, remark string, ledger *Ledger) (*EInvoice, error) {
+	if reasonCode < 1 || reasonCode > 4 {
+		return nil, fmt.Errorf("invalid cancel reason code %d: 1 duplicate, 2 data entry mistake, 3 order cancelled, 4 other", reasonCode)
+	}
+	remark = strings.TrimSpace(remark)
+	if remark == "" {
+		return nil, fmt.Errorf("a cancellation remark is required")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin e-invoice cancel tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var einvoiceID, invoiceDocID int
+	var irn, gstin, status, orderNumber string
+	var ackDate time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT ei.id, ei.invoice_document_id, ei.irn, ei.ack_date, c.gstin, so.status, so.order_number
+		FROM einvoices ei
+		JOIN sales_orders so ON so.id = ei.order_id
+		JOIN companies c     ON c.id = ei.company_id
+		WHERE c.company_code = $1 AND ei.order_id = $2 AND ei.status = 'GENERATED'
+		FOR UPDATE OF ei, so`, companyCode, orderID,
+	).Scan(&einvoiceID, &invoiceDocID, &irn, &ackDate, &gstin, &status, &orderNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("order %d has no live e-invoice", orderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch e-invoice of order %d: %w", orderID, err)
+	}
+	if status != "INVOICED" {
+		return nil, fmt.Errorf("order %s is %s: only unpaid invoices can be cancelled", orderNumber, status)
+	}
+	if time.Since(ackDate) > EInvoiceCancelWindow {
+		return nil, fmt.Errorf("IRN %s was generated more than 24 hours ago and can no longer be cancelled: issue a credit note instead", irn)
+	}
+
+	var entryID int
+	if err := tx.QueryRow(ctx, `
+		SELECT je.id
+		FROM journal_entries je
+		JOIN documents d ON d.company_id = je.company_id AND d.document_number = je.reference_id
+		WHERE d.id = $1 AND je.reference_type = 'DOCUMENT'`, invoiceDocID,
+	).Scan(&entryID); err != nil {
+		return nil, fmt.Errorf("find invoice entry of order %s: %w", orderNumber, err)
+	}
+	if err := ledger.reverseInTx(ctx, tx, entryID,
+		fmt.Sprintf("E-invoice IRN %s cancelled (reason %d): %s", irn, reasonCode, remark)); err != nil {
+		return nil, fmt.Errorf("reverse invoice of order %s: %w", orderNumber, err)
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE sales_orders SET status = 'SHIPPED', invoiced_at = NULL, invoice_document_id = NULL WHERE id = $1", orderID,
+	); err != nil {
+		return nil, fmt.Errorf("reopen order %s for invoicing: %w", orderNumber, err)
+	}
+
+	cancelledAt, err := s.irp.CancelIRN(ctx, gstin, irn, reasonCode, remark)
+	if err != nil {
+		return nil, fmt.Errorf("cancel IRN %s: %w", irn, err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE einvoices
+		SET status = 'CANCELLED', cancel_reason_code = $2, cancel_remark = $3, cancelled_at = $4
+		WHERE id = $1`, einvoiceID, reasonCode, remark, cancelledAt,
+	); err != nil {
+		return nil, fmt.Errorf("mark IRN %s cancelled: %w", irn, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit e-invoice cancel: %w", err)
+	}
+	return s.GetEInvoice(ctx, companyCode, orderID)
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// GSTReturnPeriod returns the MMYYYY return period of a calendar month.
+func GSTReturnPeriod(year, month int) string {
+	return fmt.Sprintf("%02d%04d", month, year)
+}
+
+// gstDate converts YYYY-MM-DD to the DD-MM-YYYY the GST portal expects.
+func gstDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("02-01-2006")
+}
+
+// BuildGSTR1 builds GSTR-1 from a month's taxed sales invoice lines, ordered by invoice.
+// Lines with a customer GSTIN go to B2B, the rest to B2CS; a supply is inter-state when
+// the place of supply differs from the state in the supplier's gstin. It returns the
+// problems a filer must fix: invalid recipient GSTINs and lines without an HSN code.
+// The tree issues no sales credit notes, so CDNR is always empty.
+func BuildGSTR1(gstin, period string, lines []GSTOutwardLine) (GSTR1, []string) {
+	ret := GSTR1{GSTIN: gstin, FP: period, B2B: []GSTR1B2B{}, B2CS: []GSTR1B2CS{}, CDNR: []GSTR1CDNR{},
+		HSN: GSTR1HSNTable{Data: []GSTR1HSN{}}}
+	var issues []string
+	supplierState := ""
+	if len(gstin) >= 2 {
+		supplierState = gstin[:2]
+	}
+
+	type rateTotals struct {
+		taxable, igst, cgst, sgst decimal.Decimal
+	}
+	add := func(t *rateTotals, l GSTOutwardLine) {
+		t.taxable = t.taxable.Add(l.TaxableValue)
+		t.igst = t.igst.Add(l.IGST)
+		t.cgst = t.cgst.Add(l.CGST)
+		t.sgst = t.sgst.Add(l.SGST)
+	}
+
+	// B2B: recipient → invoices in line order → per-rate items.
+	b2bIndex := map[string]int{}
+	type invoiceKey struct{ ctin, number string }
+	invoiceIndex := map[invoiceKey]int{}
+	itemTotals := map[invoiceKey]map[string]*rateTotals{}
+	itemOrder := map[invoiceKey][]decimal.Decimal{}
+	checkedGSTIN := map[string]bool{}
+
+	// B2CS and HSN keep first-seen order and are sorted at the end.
+	type b2csKey struct{ supplyType, pos, rate string }
+	b2cs := map[b2csKey]*rateTotals{}
+	b2csRate := map[b2csKey]decimal.Decimal{}
+	type hsnKey struct{ hsn, uqc string }
+	hsn := map[hsnKey]*GSTR1HSN{}
+	hsnTotals := map[hsnKey]*rateTotals{}
+	hsnQty := map[hsnKey]decimal.Decimal{}
+	missingHSN := map[string]bool{}
+
+	for _, l := range lines {
+		if l.CustomerGSTIN != "" {
+			if !checkedGSTIN[l.CustomerGSTIN] {
+				checkedGSTIN[l.CustomerGSTIN] = true
+				if err := ValidateGSTIN(l.CustomerGSTIN); err != nil {
+					issues = append(issues, fmt.Sprintf("customer %s: %v", l.CustomerCode, err))
+				}
+			}
+			bi, ok := b2bIndex[l.CustomerGSTIN]
+			if !ok {
+				bi = len(ret.B2B)
+				b2bIndex[l.CustomerGSTIN] = bi
+				ret.B2B = append(ret.B2B, GSTR1B2B{CTIN: l.CustomerGSTIN})
+			}
+			k := invoiceKey{l.CustomerGSTIN, l.InvoiceNumber}
+			if _, ok := invoiceIndex[k]; !ok {
+				invoiceIndex[k] = len(ret.B2B[bi].Invoices)
+				ret.B2B[bi].Invoices = append(ret.B2B[bi].Invoices, GSTR1Invoice{
+					Number:        l.InvoiceNumber,
+					Date:          gstDate(l.InvoiceDate),
+					Value:         GSTAmount(l.InvoiceValue),
+					PlaceOfSupply: l.PlaceOfSupply,
+					ReverseCharge: "N",
+					InvoiceType:   "R",
+				})
+				itemTotals[k] = map[string]*rateTotals{}
+			}
+			rk := l.Rate.String()
+			t, ok := itemTotals[k][rk]
+			if !ok {
+				t = &rateTotals{}
+				itemTotals[k][rk] = t
+				itemOrder[k] = append(itemOrder[k], l.Rate)
+			}
+			add(t, l)
+		} else {
+			supplyType := "INTRA"
+			if l.PlaceOfSupply != supplierState {
+				supplyType = "INTER"
+			}
+			k := b2csKey{supplyType, l.PlaceOfSupply, l.Rate.String()}
+			if _, ok := b2cs[k]; !ok {
+				b2cs[k] = &rateTotals{}
+				b2csRate[k] = l.Rate
+			}
+			add(b2cs[k], l)
+		}
+
+		if l.HSNCode == "" && !missingHSN[l.InvoiceNumber] {
+			missingHSN[l.InvoiceNumber] = true
+			issues = append(issues, fmt.Sprintf("invoice %s: line without HSN/SAC code", l.InvoiceNumber))
+		}
+		hk := hsnKey{l.HSNCode, strings.ToUpper(l.Unit)}
+		if _, ok := hsn[hk]; !ok {
+			hsn[hk] = &GSTR1HSN{HSNCode: hk.hsn, Description: l.Description, UQC: hk.uqc}
+			hsnTotals[hk] = &rateTotals{}
+		}
+		add(hsnTotals[hk], l)
+		hsnQty[hk] = hsnQty[hk].Add(l.Quantity)
+	}
+
+	for bi := range ret.B2B {
+		b := &ret.B2B[bi]
+		for ii := range b.Invoices {
+			inv := &b.Invoices[ii]
+			k := invoiceKey{b.CTIN, inv.Number}
+			for n, rate := range itemOrder[k] {
+				t := itemTotals[k][rate.String()]
+				inv.Items = append(inv.Items, GSTR1Item{Num: n + 1, Detail: GSTR1ItemDetail{
+					TaxableValue: GSTAmount(t.taxable), Rate: GSTAmount(rate),
+					IGST: GSTAmount(t.igst), CGST: GSTAmount(t.cgst), SGST: GSTAmount(t.sgst),
+				}})
+			}
+		}
+	}
+	sort.Slice(ret.B2B, func(i, j int) bool { return ret.B2B[i].CTIN < ret.B2B[j].CTIN })
+
+	for k, t := range b2cs {
+		ret.B2CS = append(ret.B2CS, GSTR1B2CS{
+			SupplyType: k.supplyType, PlaceOfSupply: k.pos, Type: "OE", Rate: GSTAmount(b2csRate[k]),
+			TaxableValue: GSTAmount(t.taxable), IGST: GSTAmount(t.igst), CGST: GSTAmount(t.cgst), SGST: GSTAmount(t.sgst),
+		})
+	}
+	sort.Slice(ret.B2CS, func(i, j int) bool {
+		a, b := ret.B2CS[i], ret.B2CS[j]
+		if a.PlaceOfSupply != b.PlaceOfSupply {
+			return a.PlaceOfSupply < b.PlaceOfSupply
+		}
+		return decimal.Decimal(a.Rate).LessThan(decimal.Decimal(b.Rate))
+	})
+
+	keys := make([]hsnKey, 0, len(hsn))
+	for k := range hsn {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].hsn != keys[j].hsn {
+			return keys[i].hsn < keys[j].hsn
+		}
+		return keys[i].uqc < keys[j].uqc
+	})
+	for n, k := range keys {
+		h, t := hsn[k], hsnTotals[k]
+		h.Num = n + 1
+		h.Quantity = GSTAmount(hsnQty[k])
+		h.TaxableValue = GSTAmount(t.taxable)
+		h.IGST, h.CGST, h.SGST = GSTAmount(t.igst), GSTAmount(t.cgst), GSTAmount(t.sgst)
+		h.Value = GSTAmount(t.taxable.Add(t.igst).Add(t.cgst).Add(t.sgst))
+		ret.HSN.Data = append(ret.HSN.Data, *h)
+	}
+	return ret, issues
+}
+
+// BuildGSTR3B builds GSTR-3B from a month's taxed sales invoice lines and purchase input
+// tax. Zero-rated lines are reported as nil-rated supplies; all input tax is eligible ITC
+// of type OTH with no reversals.
+func BuildGSTR3B(gstin, period string, lines []GSTOutwardLine, inputs []GSTInputTax) GSTR3B {
+	supplierState := ""
+	if len(gstin) >= 2 {
+		supplierState = gstin[:2]
+	}
+	var taxable, nilRated, igst, cgst, sgst decimal.Decimal
+	interState := map[string]*GSTR3BInterState{}
+	var interPOS []string
+	for _, l := range lines {
+		if l.Rate.IsZero() {
+			nilRated = nilRated.Add(l.TaxableValue)
+			continue
+		}
+		taxable = taxable.Add(l.TaxableValue)
+		igst = igst.Add(l.IGST)
+		cgst = cgst.Add(l.CGST)
+		sgst = sgst.Add(l.SGST)
+		if l.CustomerGSTIN == "" && l.PlaceOfSupply != supplierState {
+			s, ok := interState[l.PlaceOfSupply]
+			if !ok {
+				s = &GSTR3BInterState{PlaceOfSupply: l.PlaceOfSupply}
+				interState[l.PlaceOfSupply] = s
+				interPOS = append(interPOS, l.PlaceOfSupply)
+			}
+			s.TaxableValue = GSTAmount(decimal.Decimal(s.TaxableValue).Add(l.TaxableValue))
+			s.IGST = GSTAmount(decimal.Decimal(s.IGST).Add(l.IGST))
+		}
+	}
+
+	var itc GSTInputTax
+	for _, in := range inputs {
+		itc.IGST = itc.IGST.Add(in.IGST)
+		itc.CGST = itc.CGST.Add(in.CGST)
+		itc.SGST = itc.SGST.Add(in.SGST)
+	}
+	other := GSTR3BITC{Type: "OTH", IGST: GSTAmount(itc.IGST), CGST: GSTAmount(itc.CGST), SGST: GSTAmount(itc.SGST)}
+	zero := GSTAmount(decimal.Zero)
+	available := []GSTR3BITC{}
+	for _, ty := range []string{"IMPG", "IMPS", "ISRC", "ISD"} {
+		available = append(available, GSTR3BITC{Type: ty, IGST: zero, CGST: zero, SGST: zero, Cess: zero})
+	}
+	available = append(available, other)
+	net := other
+	net.Type = ""
+
+	sort.Strings(interPOS)
+	unregistered := make([]GSTR3BInterState, 0, len(interPOS))
+	for _, pos := range interPOS {
+		unregistered = append(unregistered, *interState[pos])
+	}
+
+	return GSTR3B{
+		GSTIN:     gstin,
+		RetPeriod: period,
+		SupDetails: GSTR3BSupDetails{
+			Taxable:  GSTR3BTax{TaxableValue: GSTAmount(taxable), IGST: GSTAmount(igst), CGST: GSTAmount(cgst), SGST: GSTAmount(sgst)},
+			NilRated: GSTR3BTax{TaxableValue: GSTAmount(nilRated)},
+		},
+		InterSup: GSTR3BInterSup{Unregistered: unregistered},
+		ITCElg:   GSTR3BITCEligible{Available: available, Net: net},
+	}
+}
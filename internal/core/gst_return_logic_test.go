@@ -0,0 +1,112 @@
+package core_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func gstLines() []core.GSTOutwardLine {
+	d := decimal.RequireFromString
+	return []core.GSTOutwardLine{
+		// Registered customer in the supplier's state: two rates on one invoice.
+		{InvoiceNumber: "SI-1", InvoiceDate: "2026-02-03", InvoiceValue: d("1740"), CustomerCode: "C001",
+			CustomerGSTIN: "29AAPFU0939F1ZR", PlaceOfSupply: "29", HSNCode: "8471", Description: "Widget", Unit: "unit",
+			Quantity: d("2"), Rate: d("18"), TaxableValue: d("1000"), CGST: d("90"), SGST: d("90")},
+		{InvoiceNumber: "SI-1", InvoiceDate: "2026-02-03", InvoiceValue: d("1740"), CustomerCode: "C001",
+			CustomerGSTIN: "29AAPFU0939F1ZR", PlaceOfSupply: "29", HSNCode: "9983", Description: "Consulting", Unit: "hour",
+			Quantity: d("1"), Rate: d("12"), TaxableValue: d("500"), CGST: d("30"), SGST: d("30")},
+		// Unregistered customers: one inter-state, one intra-state.
+		{InvoiceNumber: "SI-2", InvoiceDate: "2026-02-10", InvoiceValue: d("1180"), CustomerCode: "C002",
+			PlaceOfSupply: "27", HSNCode: "8471", Description: "Widget", Unit: "unit",
+			Quantity: d("2"), Rate: d("18"), TaxableValue: d("1000"), IGST: d("180")},
+		{InvoiceNumber: "SI-3", InvoiceDate: "2026-02-11", InvoiceValue: d("590"), CustomerCode: "C003",
+			PlaceOfSupply: "29", Description: "Widget", Unit: "unit",
+			Quantity: d("1"), Rate: d("18"), TaxableValue: d("500"), CGST: d("45"), SGST: d("45")},
+	}
+}
+
+func TestBuildGSTR1(t *testing.T) {
+	ret, issues := core.BuildGSTR1("29AAPFU0939F1ZR", core.GSTReturnPeriod(2026, 2), gstLines())
+
+	if ret.FP != "022026" {
+		t.Errorf("expected period 022026, got %s", ret.FP)
+	}
+	if len(ret.B2B) != 1 || len(ret.B2B[0].Invoices) != 1 {
+		t.Fatalf("expected one B2B invoice, got %+v", ret.B2B)
+	}
+	inv := ret.B2B[0].Invoices[0]
+	if inv.Date != "03-02-2026" || len(inv.Items) != 2 {
+		t.Errorf("expected invoice dated 03-02-2026 with two rate items, got %s with %d", inv.Date, len(inv.Items))
+	}
+	if len(ret.B2CS) != 2 {
+		t.Fatalf("expected B2CS rows for places of supply 27 and 29, got %+v", ret.B2CS)
+	}
+	if ret.B2CS[0].PlaceOfSupply != "27" || ret.B2CS[0].SupplyType != "INTER" || ret.B2CS[1].SupplyType != "INTRA" {
+		t.Errorf("unexpected B2CS rows: %+v", ret.B2CS)
+	}
+	if len(ret.CDNR) != 0 {
+		t.Errorf("expected no credit notes, got %+v", ret.CDNR)
+	}
+
+	// HSN rows: "" (missing), 8471, 9983 — the two 8471 lines are combined.
+	if len(ret.HSN.Data) != 3 {
+		t.Fatalf("expected 3 HSN rows, got %+v", ret.HSN.Data)
+	}
+	h := ret.HSN.Data[1]
+	if h.HSNCode != "8471" || !decimal.Decimal(h.Quantity).Equal(decimal.NewFromInt(4)) ||
+		!decimal.Decimal(h.Value).Equal(decimal.NewFromInt(2360)) {
+		t.Errorf("unexpected 8471 summary: %+v", h)
+	}
+
+	if len(issues) != 1 || !strings.Contains(issues[0], "SI-3") {
+		t.Errorf("expected one missing-HSN issue for SI-3, got %v", issues)
+	}
+}
+
+func TestBuildGSTR1_InvalidRecipientGSTIN(t *testing.T) {
+	lines := gstLines()[:1]
+	lines[0].CustomerGSTIN = "29AAPFU0939F1ZX"
+	_, issues := core.BuildGSTR1("29AAPFU0939F1ZR", "022026", lines)
+	if len(issues) != 1 || !strings.Contains(issues[0], "C001") {
+		t.Errorf("expected an invalid GSTIN issue for C001, got %v", issues)
+	}
+}
+
+func TestBuildGSTR3B(t *testing.T) {
+	inputs := []core.GSTInputTax{
+		{IGST: decimal.NewFromInt(360)},
+		{CGST: decimal.NewFromInt(45), SGST: decimal.NewFromInt(45)},
+	}
+	ret := core.BuildGSTR3B("29AAPFU0939F1ZR", "022026", gstLines(), inputs)
+
+	out := ret.SupDetails.Taxable
+	if !decimal.Decimal(out.TaxableValue).Equal(decimal.NewFromInt(3000)) ||
+		!decimal.Decimal(out.IGST).Equal(decimal.NewFromInt(180)) ||
+		!decimal.Decimal(out.CGST).Equal(decimal.NewFromInt(165)) {
+		t.Errorf("unexpected outward supplies: %+v", out)
+	}
+	if len(ret.InterSup.Unregistered) != 1 || ret.InterSup.Unregistered[0].PlaceOfSupply != "27" {
+		t.Errorf("expected one unregistered inter-state row for 27, got %+v", ret.InterSup.Unregistered)
+	}
+	if n := len(ret.ITCElg.Available); n != 5 || ret.ITCElg.Available[n-1].Type != "OTH" {
+		t.Fatalf("expected five ITC rows ending with OTH, got %+v", ret.ITCElg.Available)
+	}
+	if !decimal.Decimal(ret.ITCElg.Net.IGST).Equal(decimal.NewFromInt(360)) ||
+		!decimal.Decimal(ret.ITCElg.Net.SGST).Equal(decimal.NewFromInt(45)) {
+		t.Errorf("unexpected net ITC: %+v", ret.ITCElg.Net)
+	}
+
+	// Amounts are JSON numbers, as the portal schema requires.
+	data, err := json.Marshal(ret.SupDetails.Taxable)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"txval":3000.00`) {
+		t.Errorf("expected numeric txval, got %s", data)
+	}
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// GSTAmount is a rupee amount or rate in a GST return. The government JSON schema expects
+// numbers, not the quoted strings decimal.Decimal marshals to.
+type GSTAmount decimal.Decimal
+
+// MarshalJSON writes the amount as an unquoted number with 2 decimal places.
+func (a GSTAmount) MarshalJSON() ([]byte, error) {
+	return []byte(decimal.Decimal(a).StringFixed(2)), nil
+}
+
+// UnmarshalJSON reads a number or a quoted number.
+func (a *GSTAmount) UnmarshalJSON(b []byte) error {
+	var d decimal.Decimal
+	if err := d.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	*a = GSTAmount(d)
+	return nil
+}
+
+// GSTR1 is the outward supplies return in the GST portal's offline-tool JSON schema.
+type GSTR1 struct {
+	GSTIN string        `json:"gstin"`
+	FP    string        `json:"fp"` // return period, MMYYYY
+	B2B   []GSTR1B2B    `json:"b2b"`
+	B2CS  []GSTR1B2CS   `json:"b2cs"`
+	CDNR  []GSTR1CDNR   `json:"cdnr"`
+	HSN   GSTR1HSNTable `json:"hsn"`
+}
+
+// GSTR1B2B groups the invoices issued to one registered recipient.
+type GSTR1B2B struct {
+	CTIN     string         `json:"ctin"` // recipient GSTIN
+	Invoices []GSTR1Invoice `json:"inv"`
+}
+
+// GSTR1Invoice is one B2B invoice; its items are totals per tax rate.
+type GSTR1Invoice struct {
+	Number        string      `json:"inum"`
+	Date          string      `json:"idt"` // DD-MM-YYYY
+	Value         GSTAmount   `json:"val"` // invoice value including tax
+	PlaceOfSupply string      `json:"pos"` // recipient state code
+	ReverseCharge string      `json:"rchrg"`
+	InvoiceType   string      `json:"inv_typ"`
+	Items         []GSTR1Item `json:"itms"`
+}
+
+// GSTR1Item is the per-rate line of an invoice or note.
+type GSTR1Item struct {
+	Num    int             `json:"num"`
+	Detail GSTR1ItemDetail `json:"itm_det"`
+}
+
+// GSTR1ItemDetail holds the taxable value and tax of one rate.
+type GSTR1ItemDetail struct {
+	TaxableValue GSTAmount `json:"txval"`
+	Rate         GSTAmount `json:"rt"`
+	IGST         GSTAmount `json:"iamt"`
+	CGST         GSTAmount `json:"camt"`
+	SGST         GSTAmount `json:"samt"`
+	Cess         GSTAmount `json:"csamt"`
+}
+
+// GSTR1B2CS totals supplies to unregistered recipients by place of supply and rate.
+// Large inter-state invoices (B2CL) are not split out.
+type GSTR1B2CS struct {
+	SupplyType    string    `json:"sply_ty"` // INTRA | INTER
+	PlaceOfSupply string    `json:"pos"`
+	Type          string    `json:"typ"` // OE (other than e-commerce)
+	Rate          GSTAmount `json:"rt"`
+	TaxableValue  GSTAmount `json:"txval"`
+	IGST          GSTAmount `json:"iamt"`
+	CGST          GSTAmount `json:"camt"`
+	SGST          GSTAmount `json:"samt"`
+	Cess          GSTAmount `json:"csamt"`
+}
+
+// GSTR1CDNR groups the credit/debit notes issued to one registered recipient.
+type GSTR1CDNR struct {
+	CTIN  string      `json:"ctin"`
+	Notes []GSTR1Note `json:"nt"`
+}
+
+// GSTR1Note is one credit or debit note to a registered recipient.
+type GSTR1Note struct {
+	NoteType      string      `json:"ntty"` // C | D
+	Number        string      `json:"nt_num"`
+	Date          string      `json:"nt_dt"` // DD-MM-YYYY
+	Value         GSTAmount   `json:"val"`
+	PlaceOfSupply string      `json:"pos"`
+	ReverseCharge string      `json:"rchrg"`
+	InvoiceType   string      `json:"inv_typ"`
+	Items         []GSTR1Item `json:"itms"`
+}
+
+// GSTR1HSNTable is the HSN-wise summary of outward supplies.
+type GSTR1HSNTable struct {
+	Data []GSTR1HSN `json:"data"`
+}
+
+// GSTR1HSN totals outward supplies of one HSN/SAC code and unit.
+type GSTR1HSN struct {
+	Num          int       `json:"num"`
+	HSNCode      string    `json:"hsn_sc"`
+	Description  string    `json:"desc"`
+	UQC          string    `json:"uqc"` // unit quantity code
+	Quantity     GSTAmount `json:"qty"`
+	Value        GSTAmount `json:"val"`
+	TaxableValue GSTAmount `json:"txval"`
+	IGST         GSTAmount `json:"iamt"`
+	CGST         GSTAmount `json:"camt"`
+	SGST         GSTAmount `json:"samt"`
+	Cess         GSTAmount `json:"csamt"`
+}
+
+// GSTR3B is the monthly summary return in the GST portal's JSON schema.
+type GSTR3B struct {
+	GSTIN      string            `json:"gstin"`
+	RetPeriod  string            `json:"ret_period"` // MMYYYY
+	SupDetails GSTR3BSupDetails  `json:"sup_details"`
+	InterSup   GSTR3BInterSup    `json:"inter_sup"`
+	ITCElg     GSTR3BITCEligible `json:"itc_elg"`
+}
+
+// GSTR3BSupDetails is table 3.1: outward supplies.
+type GSTR3BSupDetails struct {
+	Taxable  GSTR3BTax `json:"osup_det"`      // taxable outward supplies
+	NilRated GSTR3BTax `json:"osup_nil_exmp"` // nil-rated and exempt
+}
+
+// GSTR3BTax is a taxable value with tax per component.
+type GSTR3BTax struct {
+	TaxableValue GSTAmount `json:"txval"`
+	IGST         GSTAmount `json:"iamt"`
+	CGST         GSTAmount `json:"camt"`
+	SGST         GSTAmount `json:"samt"`
+	Cess         GSTAmount `json:"csamt"`
+}
+
+// GSTR3BInterSup is table 3.2: inter-state supplies to unregistered persons.
+type GSTR3BInterSup struct {
+	Unregistered []GSTR3BInterState `json:"unreg_details"`
+}
+
+// GSTR3BInterState totals inter-state supplies to one place of supply.
+type GSTR3BInterState struct {
+	PlaceOfSupply string    `json:"pos"`
+	TaxableValue  GSTAmount `json:"txval"`
+	IGST          GSTAmount `json:"iamt"`
+}
+
+// GSTR3BITCEligible is table 4: eligible input tax credit.
+type GSTR3BITCEligible struct {
+	Available []GSTR3BITC `json:"itc_avl"`
+	Net       GSTR3BITC   `json:"itc_net"`
+}
+
+// GSTR3BITC is input tax credit per component; Type is the ITC category (OTH = all other).
+type GSTR3BITC struct {
+	Type string    `json:"ty,omitempty"`
+	IGST GSTAmount `json:"iamt"`
+	CGST GSTAmount `json:"camt"`
+	SGST GSTAmount `json:"samt"`
+	Cess GSTAmount `json:"csamt"`
+}
+
+// GSTOutwardLine is one taxed sales invoice line, in base currency, as read for GSTR-1.
+type GSTOutwardLine struct {
+	InvoiceNumber string
+	InvoiceDate   string // YYYY-MM-DD
+	InvoiceValue  decimal.Decimal
+	CustomerCode  string
+	CustomerGSTIN string // empty for unregistered customers
+	PlaceOfSupply string // customer state code
+	HSNCode       string
+	Description   string
+	Unit          string
+	Quantity      decimal.Decimal
+	Rate          decimal.Decimal // total GST rate, e.g. 18
+	TaxableValue  decimal.Decimal
+	IGST          decimal.Decimal
+	CGST          decimal.Decimal
+	SGST          decimal.Decimal
+}
+
+// GSTInputTax is the input tax on one purchase document (PO vendor invoice or vendor bill);
+// negative for the reversal on a purchase return's debit note.
+type GSTInputTax struct {
+	IGST decimal.Decimal
+	CGST decimal.Decimal
+	SGST decimal.Decimal
+}
+
+// GSTReconciliationLine compares one GST component in the return with the period movement
+// of its GL account.
+type GSTReconciliationLine struct {
+	Direction   string // OUTPUT (tax payable) | INPUT (input tax credit)
+	Component   string // CGST | SGST | IGST
+	AccountCode string
+	ReturnTax   decimal.Decimal
+	LedgerTax   decimal.Decimal // credits less debits for OUTPUT, debits less credits for INPUT
+	Difference  decimal.Decimal // ReturnTax − LedgerTax
+}
+
+// GSTReturn is a company's GSTR-1 and GSTR-3B for one month, with the reconciliation of the
+// returned tax to the GST ledger accounts and any data problems found while building them.
+type GSTReturn struct {
+	CompanyCode    string
+	GSTIN          string
+	Period         string // MMYYYY
+	FromDate       string
+	ToDate         string
+	GSTR1          GSTR1
+	GSTR3B         GSTR3B
+	Reconciliation []GSTReconciliationLine
+	Reconciled     bool     // every Difference is zero
+	Issues         []string // invalid or missing GSTINs, lines without HSN codes
+}
+
+// GSTReturnService builds the monthly GST returns from the tax recorded on documents.
+type GSTReturnService interface {
+	// GetReturn returns GSTR-1 and GSTR-3B for a calendar month. Sales count by invoice
+	// date, purchases by vendor invoice or bill date. Fails if the company has no GSTIN.
+	GetReturn(ctx context.Context, companyCode string, year, month int) (*GSTReturn, error)
+}
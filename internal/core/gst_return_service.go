@@ -0,0 +1,220 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type gstReturnService struct {
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+}
+
+// NewGSTReturnService constructs a GSTReturnService.
+func NewGSTReturnService(pool *pgxpool.Pool, ruleEngine RuleEngine) GSTReturnService {
+	return &gstReturnService{pool: pool, ruleEngine: ruleEngine}
+}
+
+// GetReturn builds GSTR-1 and GSTR-3B for a month and reconciles them to the GST accounts.
+func (s *gstReturnService) GetReturn(ctx context.Context, companyCode string, year, month int) (*GSTReturn, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("invalid month %d: expected 1-12", month)
+	}
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+
+	var companyID int
+	var gstin *string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, gstin FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID, &gstin); err != nil {
+		return nil, fmt.Errorf("company %s not found: %w", companyCode, err)
+	}
+	if gstin == nil {
+		return nil, fmt.Errorf("company %s has no GSTIN: set its GST registration before filing returns", companyCode)
+	}
+
+	lines, err := s.fetchOutwardLines(ctx, companyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := s.fetchInputTax(ctx, companyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	period := GSTReturnPeriod(year, month)
+	ret := &GSTReturn{
+		CompanyCode: companyCode,
+		GSTIN:       *gstin,
+		Period:      period,
+		FromDate:    from.Format("2006-01-02"),
+		ToDate:      to.Format("2006-01-02"),
+	}
+	if err := ValidateGSTIN(*gstin); err != nil {
+		ret.Issues = append(ret.Issues, fmt.Sprintf("company %s: %v", companyCode, err))
+	}
+	var issues []string
+	ret.GSTR1, issues = BuildGSTR1(*gstin, period, lines)
+	ret.Issues = append(ret.Issues, issues...)
+	ret.GSTR3B = BuildGSTR3B(*gstin, period, lines, inputs)
+
+	if err := s.reconcile(ctx, ret, companyID, from, to); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// fetchOutwardLines reads the taxed lines of sales orders invoiced in [from, to], converted
+// to base currency at the order's rate, with the GST components pivoted into columns.
+func (s *gstReturnService) fetchOutwardLines(ctx context.Context, companyID int, from, to time.Time) ([]GSTOutwardLine, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT d.document_number, so.invoiced_at::date::text,
+		       ROUND(so.total_transaction * so.exchange_rate, 2),
+		       c.code, COALESCE(c.gstin, ''), COALESCE(c.state_code, ''),
+		       COALESCE(p.hsn_code, ''), p.name, COALESCE(sol.uom, p.unit), sol.quantity,
+		       SUM(lt.rate),
+		       ROUND(sol.line_total_transaction * so.exchange_rate, 2),
+		       ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'IGST'), 0) * so.exchange_rate, 2),
+		       ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'CGST'), 0) * so.exchange_rate, 2),
+		       ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'SGST'), 0) * so.exchange_rate, 2)
+		FROM sales_order_lines sol
+		JOIN sales_orders so ON so.id = sol.order_id
+		JOIN customers c     ON c.id = so.customer_id
+		JOIN products p      ON p.id = sol.product_id
+		JOIN documents d     ON d.id = so.invoice_document_id
+		JOIN line_taxes lt   ON lt.sales_order_line_id = sol.id
+		WHERE so.company_id = $1 AND so.invoiced_at::date BETWEEN $2 AND $3 AND so.status <> 'CANCELLED'
+		GROUP BY sol.id, so.id, d.document_number, c.id, p.id
+		ORDER BY so.invoiced_at, d.document_number, sol.line_number`,
+		companyID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query GST outward supplies: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []GSTOutwardLine
+	for rows.Next() {
+		var l GSTOutwardLine
+		if err := rows.Scan(&l.InvoiceNumber, &l.InvoiceDate, &l.InvoiceValue,
+			&l.CustomerCode, &l.CustomerGSTIN, &l.PlaceOfSupply,
+			&l.HSNCode, &l.Description, &l.Unit, &l.Quantity,
+			&l.Rate, &l.TaxableValue, &l.IGST, &l.CGST, &l.SGST); err != nil {
+			return nil, fmt.Errorf("scan GST outward supply: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate GST outward supplies: %w", err)
+	}
+	return lines, nil
+}
+
+// fetchInputTax reads the input GST on PO vendor invoices dated in [from, to], converted at
+// the PO rate, and on vendor bills dated in [from, to], less the input GST reversed by
+// purchase returns dated in [from, to], one entry per document.
+func (s *gstReturnService) fetchInputTax(ctx context.Context, companyID int, from, to time.Time) ([]GSTInputTax, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'IGST'), 0) * po.exchange_rate, 2),
+		       ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'CGST'), 0) * po.exchange_rate, 2),
+		       ROUND(COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'SGST'), 0) * po.exchange_rate, 2)
+		FROM line_taxes lt
+		JOIN vendor_invoice_lines vil ON vil.id = lt.vendor_invoice_line_id
+		JOIN purchase_orders po       ON po.id = vil.po_id
+		WHERE po.company_id = $1 AND po.invoice_date BETWEEN $2 AND $3
+		GROUP BY po.id
+		UNION ALL
+		SELECT COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'IGST'), 0),
+		       COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'CGST'), 0),
+		       COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'SGST'), 0)
+		FROM line_taxes lt
+		JOIN vendor_bill_lines vbl ON vbl.id = lt.vendor_bill_line_id
+		JOIN vendor_bills vb       ON vb.id = vbl.bill_id
+		WHERE vb.company_id = $1 AND vb.bill_date BETWEEN $2 AND $3
+		GROUP BY vb.id
+		UNION ALL
+		SELECT -COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'IGST'), 0),
+		       -COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'CGST'), 0),
+		       -COALESCE(SUM(lt.tax_amount) FILTER (WHERE lt.component = 'SGST'), 0)
+		FROM line_taxes lt
+		JOIN purchase_return_lines prl ON prl.id = lt.purchase_return_line_id
+		JOIN purchase_returns pr       ON pr.id = prl.return_id
+		WHERE pr.company_id = $1 AND pr.return_date BETWEEN $2 AND $3
+		GROUP BY pr.id`,
+		companyID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query GST input tax: %w", err)
+	}
+	defer rows.Close()
+
+	var inputs []GSTInputTax
+	for rows.Next() {
+		var in GSTInputTax
+		if err := rows.Scan(&in.IGST, &in.CGST, &in.SGST); err != nil {
+			return nil, fmt.Errorf("scan GST input tax: %w", err)
+		}
+		inputs = append(inputs, in)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate GST input tax: %w", err)
+	}
+	return inputs, nil
+}
+
+// reconcile compares the return's output tax and ITC per component with the period
+// movement of the GST_OUTPUT_* and GST_INPUT_* accounts, and sets Reconciled.
+func (s *gstReturnService) reconcile(ctx context.Context, ret *GSTReturn, companyID int, from, to time.Time) error {
+	out := ret.GSTR3B.SupDetails.Taxable
+	itc := ret.GSTR3B.ITCElg.Net
+	returned := map[bool]map[string]GSTAmount{
+		false: {GSTComponentCGST: out.CGST, GSTComponentSGST: out.SGST, GSTComponentIGST: out.IGST},
+		true:  {GSTComponentCGST: itc.CGST, GSTComponentSGST: itc.SGST, GSTComponentIGST: itc.IGST},
+	}
+
+	ret.Reconciled = true
+	for _, input := range []bool{false, true} {
+		direction := "OUTPUT"
+		if input {
+			direction = "INPUT"
+		}
+		for _, c := range []string{GSTComponentCGST, GSTComponentSGST, GSTComponentIGST} {
+			account, err := s.ruleEngine.ResolveAccount(ctx, companyID, gstRuleType(c, input))
+			if err != nil {
+				return fmt.Errorf("resolve %s account: %w", gstRuleType(c, input), err)
+			}
+			var movement decimal.Decimal // debits less credits
+			if err := s.pool.QueryRow(ctx, `
+				SELECT COALESCE(SUM(jl.debit_base - jl.credit_base), 0)
+				FROM journal_lines jl
+				JOIN journal_entries je ON je.id = jl.entry_id
+				JOIN accounts a         ON a.id = jl.account_id
+				WHERE je.company_id = $1 AND a.code = $2 AND je.posting_date BETWEEN $3 AND $4`,
+				companyID, account, from, to,
+			).Scan(&movement); err != nil {
+				return fmt.Errorf("fetch %s movement: %w", account, err)
+			}
+			if !input {
+				movement = movement.Neg()
+			}
+			line := GSTReconciliationLine{
+				Direction:   direction,
+				Component:   c,
+				AccountCode: account,
+				ReturnTax:   decimal.Decimal(returned[input][c]),
+				LedgerTax:   movement,
+			}
+			line.Difference = line.ReturnTax.Sub(line.LedgerTax)
+			if !line.Difference.IsZero() {
+				ret.Reconciled = false
+			}
+			ret.Reconciliation = append(ret.Reconciliation, line)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"accounting-agent/internal/core"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 )
 
@@ -13,6 +14,17 @@ import (
 func setupInventoryTestDB(t *testing.T) (core.OrderService, core.InventoryService, *core.Ledger, core.DocumentService, context.Context) {
 	t.Helper()
 	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	seedInventoryTestData(t, ctx, pool)
+
+	ruleEngine := core.NewRuleEngine(pool)
+	invSvc := core.NewInventoryService(pool, ruleEngine)
+	return orderSvc, invSvc, ledger, docSvc, ctx
+}
+
+// seedInventoryTestData seeds the accounts, document types, warehouse, inventory items,
+// and account rules required by InventoryService on top of the order test seed.
+func seedInventoryTestData(t *testing.T, ctx context.Context, pool *pgxpool.Pool) {
+	t.Helper()
 
 	// Seed additional accounts needed for inventory tests
 	_, err := pool.Exec(ctx, `
@@ -52,10 +64,6 @@ func setupInventoryTestDB(t *testing.T) (core.OrderService, core.InventoryServic
 	if err != nil {
 		t.Fatalf("Failed to seed inventory test data: %v", err)
 	}
-
-	ruleEngine := core.NewRuleEngine(pool)
-	invSvc := core.NewInventoryService(pool, ruleEngine)
-	return orderSvc, invSvc, ledger, docSvc, ctx
 }
 
 // getStockInfo is a helper to fetch qty_on_hand and qty_reserved for a product.
@@ -0,0 +1,191 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestInventory_LotTracking(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedInventoryTestData(t, ctx, pool)
+
+	invSvc := core.NewInventoryService(pool, core.NewRuleEngine(pool))
+	reportSvc := core.NewReportingService(pool)
+
+	today := time.Now()
+	date := func(days int) string { return today.AddDate(0, 0, days).Format("2006-01-02") }
+
+	if err := invSvc.SetProductTracking(ctx, "1000", "P001", "LOT", "FEFO"); err != nil {
+		t.Fatalf("SetProductTracking: %v", err)
+	}
+
+	t.Run("ReceiveWithoutLots_Fails", func(t *testing.T) {
+		err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001",
+			decimal.NewFromInt(5), decimal.NewFromInt(100), date(0), "2000", nil, ledger, docSvc)
+		if err == nil {
+			t.Error("expected error receiving a lot-tracked product without lots, got nil")
+		}
+	})
+
+	t.Run("ReceiveLotsMismatch_Fails", func(t *testing.T) {
+		err := invSvc.ReceiveStockLots(ctx, "1000", "MAIN", "P001",
+			decimal.NewFromInt(5), decimal.NewFromInt(100),
+			[]core.LotInput{{LotNumber: "X1", Quantity: decimal.NewFromInt(4)}},
+			date(0), "2000", nil, ledger, docSvc)
+		if err == nil {
+			t.Error("expected error when lot quantities do not sum to the receipt, got nil")
+		}
+	})
+
+	// Lot A is already expired; C expires before B, so FEFO takes C first.
+	err := invSvc.ReceiveStockLots(ctx, "1000", "MAIN", "P001",
+		decimal.NewFromInt(10), decimal.NewFromInt(100),
+		[]core.LotInput{
+			{LotNumber: "LOT-A", ExpiryDate: date(-10), Quantity: decimal.NewFromInt(3)},
+			{LotNumber: "LOT-B", ExpiryDate: date(30), Quantity: decimal.NewFromInt(4)},
+			{LotNumber: "LOT-C", ExpiryDate: date(10), Quantity: decimal.NewFromInt(3)},
+		},
+		date(-20), "2000", nil, ledger, docSvc)
+	if err != nil {
+		t.Fatalf("ReceiveStockLots: %v", err)
+	}
+
+	t.Run("ChangeTrackingWithStock_Fails", func(t *testing.T) {
+		if err := invSvc.SetProductTracking(ctx, "1000", "P001", "NONE", ""); err == nil {
+			t.Error("expected error changing tracking mode with stock on hand, got nil")
+		}
+	})
+
+	t.Run("GetLots", func(t *testing.T) {
+		lots, err := invSvc.GetLots(ctx, "1000", "P001")
+		if err != nil {
+			t.Fatalf("GetLots: %v", err)
+		}
+		if len(lots) != 3 {
+			t.Fatalf("expected 3 lots, got %d", len(lots))
+		}
+		// Ordered by expiry: A (expired), C, B
+		if lots[0].LotNumber != "LOT-A" || !lots[0].IsExpired {
+			t.Errorf("expected first lot LOT-A flagged expired, got %s expired=%v", lots[0].LotNumber, lots[0].IsExpired)
+		}
+	})
+
+	createConfirmed := func(t *testing.T, qty int64) *core.SalesOrder {
+		t.Helper()
+		order, err := orderSvc.CreateOrder(ctx, "1000", "C001", "INR", decimal.NewFromInt(1), date(0),
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(qty)}}, "")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		order, err = orderSvc.ConfirmOrder(ctx, order.ID, docSvc, invSvc)
+		if err != nil {
+			t.Fatalf("ConfirmOrder: %v", err)
+		}
+		return order
+	}
+
+	t.Run("Ship_FEFO_SkipsExpiredLot", func(t *testing.T) {
+		fefoOrder := createConfirmed(t, 5)
+		if _, err := orderSvc.ShipOrder(ctx, fefoOrder.ID, invSvc, ledger, docSvc); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+
+		lots, err := invSvc.GetLots(ctx, "1000", "P001")
+		if err != nil {
+			t.Fatalf("GetLots: %v", err)
+		}
+		got := map[string]string{}
+		for _, l := range lots {
+			got[l.LotNumber] = l.QtyOnHand.String()
+		}
+		// C (3) fully consumed, then 2 from B; expired A untouched.
+		if got["LOT-A"] != "3" || got["LOT-B"] != "2" || got["LOT-C"] != "" {
+			t.Errorf("unexpected lot balances after FEFO shipment: %v", got)
+		}
+	})
+
+	t.Run("Ship_ExplicitPickOfExpiredLot_Fails", func(t *testing.T) {
+		order := createConfirmed(t, 2)
+		_, err := orderSvc.ShipOrderWithPicks(ctx, order.ID,
+			[]core.LotPick{{ProductCode: "P001", LotNumber: "LOT-A", Quantity: decimal.NewFromInt(2)}},
+			invSvc, ledger, docSvc)
+		if err == nil {
+			t.Fatal("expected error shipping from an expired lot, got nil")
+		}
+
+		// Only 2 unexpired units remain (LOT-B); an explicit pick of B succeeds.
+		if _, err := orderSvc.ShipOrderWithPicks(ctx, order.ID,
+			[]core.LotPick{{ProductCode: "P001", LotNumber: "LOT-B", Quantity: decimal.NewFromInt(2)}},
+			invSvc, ledger, docSvc); err != nil {
+			t.Fatalf("ShipOrderWithPicks LOT-B: %v", err)
+		}
+	})
+
+	t.Run("Ship_OnlyExpiredStockLeft_Fails", func(t *testing.T) {
+		order := createConfirmed(t, 1)
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docSvc); err == nil {
+			t.Error("expected error when only expired lots remain, got nil")
+		}
+	})
+
+	t.Run("ForwardTrace", func(t *testing.T) {
+		trace, err := reportSvc.GetLotTrace(ctx, "1000", "P001", "LOT-C")
+		if err != nil {
+			t.Fatalf("GetLotTrace: %v", err)
+		}
+		if !trace.TotalReceived.Equal(decimal.NewFromInt(3)) || !trace.TotalShipped.Equal(decimal.NewFromInt(3)) {
+			t.Errorf("expected 3 received and 3 shipped, got %s / %s", trace.TotalReceived, trace.TotalShipped)
+		}
+		if len(trace.Shipments) != 1 || trace.Shipments[0].PartyCode != "C001" {
+			t.Errorf("expected one shipment to C001, got %+v", trace.Shipments)
+		}
+	})
+
+	t.Run("BackwardTrace", func(t *testing.T) {
+		// Its own product, lots, receipt and shipment: 5 units drawn from TR-1 (3) and TR-2 (2).
+		if err := invSvc.SetProductTracking(ctx, "1000", "P003", "LOT", "FEFO"); err != nil {
+			t.Fatalf("SetProductTracking: %v", err)
+		}
+		if err := invSvc.ReceiveStockLots(ctx, "1000", "MAIN", "P003",
+			decimal.NewFromInt(7), decimal.NewFromInt(400),
+			[]core.LotInput{
+				{LotNumber: "TR-1", ExpiryDate: date(20), Quantity: decimal.NewFromInt(3)},
+				{LotNumber: "TR-2", ExpiryDate: date(40), Quantity: decimal.NewFromInt(4)},
+			},
+			date(-5), "2000", nil, ledger, docSvc); err != nil {
+			t.Fatalf("ReceiveStockLots: %v", err)
+		}
+		order, err := orderSvc.CreateOrder(ctx, "1000", "C001", "INR", decimal.NewFromInt(1), date(0),
+			[]core.OrderLineInput{{ProductCode: "P003", Quantity: decimal.NewFromInt(5)}}, "")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, invSvc); err != nil {
+			t.Fatalf("ConfirmOrder: %v", err)
+		}
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docSvc); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+
+		trace, err := reportSvc.GetOrderLotTrace(ctx, "1000", order.ID)
+		if err != nil {
+			t.Fatalf("GetOrderLotTrace: %v", err)
+		}
+		if trace.CustomerCode != "C001" {
+			t.Errorf("expected customer C001, got %s", trace.CustomerCode)
+		}
+		if len(trace.Lines) != 2 {
+			t.Fatalf("expected 2 shipped lots, got %d", len(trace.Lines))
+		}
+		for _, l := range trace.Lines {
+			if len(l.Sources) != 1 {
+				t.Errorf("lot %s: expected 1 receipt source, got %d", l.LotNumber, len(l.Sources))
+			}
+		}
+	})
+}
@@ -27,3 +27,34 @@ type StockLevel struct {
 	Available     decimal.Decimal // = OnHand - Reserved
 	UnitCost      decimal.Decimal // weighted average purchase cost
 }
+
+// InventoryLot is one lot (or serial number) of a lot-tracked product in a warehouse.
+type InventoryLot struct {
+	ID            int
+	ProductCode   string
+	ProductName   string
+	WarehouseCode string
+	LotNumber     string
+	ExpiryDate    *string // YYYY-MM-DD; nil if the lot does not expire
+	ReceivedDate  string  // YYYY-MM-DD of the first receipt into this lot
+	QtyOnHand     decimal.Decimal
+	UnitCost      decimal.Decimal
+	IsExpired     bool // expiry date is before today; expired lots cannot be shipped
+}
+
+// LotInput captures one lot (or serial number) on a goods receipt.
+// For SERIAL products Quantity may be left zero and is treated as 1.
+type LotInput struct {
+	LotNumber  string
+	ExpiryDate string // optional YYYY-MM-DD
+	Quantity   decimal.Decimal
+}
+
+// LotPick is an explicit lot selection for a shipment, overriding FEFO/FIFO.
+// Picks for a product must cover the full quantity of that product on the order.
+// Quantity is in the product's stock unit.
+type LotPick struct {
+	ProductCode string
+	LotNumber   string
+	Quantity    decimal.Decimal
+}
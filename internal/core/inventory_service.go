@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -23,6 +24,23 @@ type InventoryService interface {
 	ReceiveStock(ctx context.Context, companyCode, warehouseCode, productCode string,
 		qty, unitCost decimal.Decimal, movementDate, creditAccountCode string,
 		poLineID *int, ledger *Ledger, docService DocumentService) error
+	// ReceiveStockLots is ReceiveStock for lot- or serial-tracked products: lots must sum to qty
+	// and each lot is recorded in inventory_lots. Untracked products must pass no lots.
+	ReceiveStockLots(ctx context.Context, companyCode, warehouseCode, productCode string,
+		qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+		poLineID *int, ledger *Ledger, docService DocumentService) error
+	// ReceiveStockInCurrency is ReceiveStockLots for a receipt priced in another currency:
+	// unitCost is in currency, stock is valued at unitCost × exchangeRate (base currency) and
+	// the journal entry is posted in currency at exchangeRate. An empty currency is the base.
+	ReceiveStockInCurrency(ctx context.Context, companyCode, warehouseCode, productCode string,
+		qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+		currency string, exchangeRate decimal.Decimal,
+		poLineID *int, ledger *Ledger, docService DocumentService) error
+	// GetLots returns lots with stock on hand, optionally filtered to one product.
+	GetLots(ctx context.Context, companyCode, productCode string) ([]InventoryLot, error)
+	// SetProductTracking sets a product's tracking mode (NONE, LOT, SERIAL) and the lot pick
+	// strategy (FEFO, FIFO) used on shipment when no explicit pick is given.
+	SetProductTracking(ctx context.Context, companyCode, productCode, trackingMode, pickStrategy string) error
 
 	// TX-scoped operations: work within a caller-provided transaction.
 	// Used by OrderService to keep inventory changes atomic with order state transitions.
@@ -32,10 +50,18 @@ type InventoryService interface {
 	ReserveStockTx(ctx context.Context, tx pgx.Tx, companyID, orderID int, lines []SalesOrderLine) error
 	// ReleaseReservationTx releases soft-locked stock when an order is cancelled.
 	ReleaseReservationTx(ctx context.Context, tx pgx.Tx, orderID int) error
+	// ReceiveStockTx is ReceiveStockInCurrency within the caller's transaction.
+	// Used by PurchaseOrderService to keep a goods receipt atomic with the PO line's received quantity.
+	ReceiveStockTx(ctx context.Context, tx pgx.Tx, companyCode, warehouseCode, productCode string,
+		qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+		currency string, exchangeRate decimal.Decimal,
+		poLineID *int, ledger *Ledger, docService DocumentService) error
 	// ShipStockTx deducts physical stock and books COGS when an order is shipped.
 	// The COGS journal entry is committed atomically within the provided TX via Ledger.CommitInTx.
+	// Lot-tracked products consume lots via picks if given, else by the product's FEFO/FIFO strategy.
+	// Expired lots are never shipped.
 	ShipStockTx(ctx context.Context, tx pgx.Tx, companyID, orderID int, lines []SalesOrderLine,
-		ledger *Ledger, docService DocumentService) error
+		picks []LotPick, ledger *Ledger, docService DocumentService) error
 }
 
 type inventoryService struct {
@@ -155,13 +181,29 @@ func (s *inventoryService) GetStockLevels(ctx context.Context, companyCode strin
 func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, warehouseCode, productCode string,
 	qty, unitCost decimal.Decimal, movementDate, creditAccountCode string,
 	poLineID *int, ledger *Ledger, docService DocumentService) error {
+	return s.ReceiveStockLots(ctx, companyCode, warehouseCode, productCode, qty, unitCost, nil,
+		movementDate, creditAccountCode, poLineID, ledger, docService)
+}
 
-	if qty.IsNegative() || qty.IsZero() {
-		return fmt.Errorf("receive quantity must be positive, got %s", qty)
-	}
-	if unitCost.IsNegative() {
-		return fmt.Errorf("unit cost cannot be negative, got %s", unitCost)
-	}
+// ReceiveStockLots records a goods receipt split across lots. For products with tracking_mode
+// NONE, lots must be empty and the receipt behaves exactly like ReceiveStock. For LOT and
+// SERIAL products, lots are required, must sum to qty, and each one writes its own RECEIPT
+// movement (linked via lot_id); the single journal entry covers the full receipt.
+func (s *inventoryService) ReceiveStockLots(ctx context.Context, companyCode, warehouseCode, productCode string,
+	qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+	poLineID *int, ledger *Ledger, docService DocumentService) error {
+	return s.ReceiveStockInCurrency(ctx, companyCode, warehouseCode, productCode, qty, unitCost, lots,
+		movementDate, creditAccountCode, "", decimal.Zero, poLineID, ledger, docService)
+}
+
+// ReceiveStockInCurrency records a goods receipt priced in a transaction currency. The
+// weighted average cost, movements and lots use the base-currency unit cost
+// (unitCost × exchangeRate); the journal entry keeps the transaction amount, so AP is
+// booked in the currency the vendor will be paid in.
+func (s *inventoryService) ReceiveStockInCurrency(ctx context.Context, companyCode, warehouseCode, productCode string,
+	qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+	currency string, exchangeRate decimal.Decimal,
+	poLineID *int, ledger *Ledger, docService DocumentService) error {
 
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -169,6 +211,33 @@ func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, wareho
 	}
 	defer tx.Rollback(ctx)
 
+	if err := s.ReceiveStockTx(ctx, tx, companyCode, warehouseCode, productCode, qty, unitCost, lots,
+		movementDate, creditAccountCode, currency, exchangeRate, poLineID, ledger, docService); err != nil {
+		return err
+	}
+
+	// Single commit: inventory write + journal entry land together or not at all.
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit goods receipt: %w", err)
+	}
+
+	return nil
+}
+
+// ReceiveStockTx records a goods receipt within the caller's transaction; see
+// ReceiveStockInCurrency. The caller commits.
+func (s *inventoryService) ReceiveStockTx(ctx context.Context, tx pgx.Tx, companyCode, warehouseCode, productCode string,
+	qty, unitCost decimal.Decimal, lots []LotInput, movementDate, creditAccountCode string,
+	currency string, exchangeRate decimal.Decimal,
+	poLineID *int, ledger *Ledger, docService DocumentService) error {
+
+	if qty.IsNegative() || qty.IsZero() {
+		return fmt.Errorf("receive quantity must be positive, got %s", qty)
+	}
+	if unitCost.IsNegative() {
+		return fmt.Errorf("unit cost cannot be negative, got %s", unitCost)
+	}
+
 	// Resolve company
 	var companyID int
 	var baseCurrency string
@@ -179,6 +248,16 @@ func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, wareho
 		return fmt.Errorf("failed to resolve company: %w", err)
 	}
 
+	// Stock is always valued in base currency.
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" || currency == baseCurrency {
+		currency, exchangeRate = baseCurrency, decimal.NewFromInt(1)
+	} else if !exchangeRate.IsPositive() {
+		return fmt.Errorf("exchange rate to %s is required for a receipt in %s", baseCurrency, currency)
+	}
+	transactionTotal := qty.Mul(unitCost)
+	unitCost = unitCost.Mul(exchangeRate)
+
 	// Resolve inventory account via rule engine
 	inventoryAccount, err := s.ruleEngine.ResolveAccount(ctx, companyID, "INVENTORY")
 	if err != nil {
@@ -207,16 +286,22 @@ func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, wareho
 
 	// Resolve product
 	var productID int
+	var trackingMode string
 	if err := tx.QueryRow(ctx,
-		"SELECT id FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
+		"SELECT id, tracking_mode FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
 		companyID, productCode,
-	).Scan(&productID); err != nil {
+	).Scan(&productID, &trackingMode); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return fmt.Errorf("product %s not found for company %s", productCode, companyCode)
 		}
 		return fmt.Errorf("failed to resolve product: %w", err)
 	}
 
+	lots, err = normalizeReceiptLots(productCode, trackingMode, qty, lots)
+	if err != nil {
+		return err
+	}
+
 	// Lock inventory_item row (create if it doesn't exist yet)
 	var itemID int
 	var oldQty, oldCost decimal.Decimal
@@ -258,23 +343,48 @@ func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, wareho
 		return fmt.Errorf("failed to update inventory item: %w", err)
 	}
 
-	// Insert movement record
+	// Insert movement record(s): one per lot for tracked products, otherwise one for the receipt.
 	totalCost := qty.Mul(unitCost)
 	parsedDate, _ := time.Parse("2006-01-02", movementDate)
 	if parsedDate.IsZero() {
 		parsedDate = time.Now()
 	}
+	receiptDate := parsedDate.Format("2006-01-02")
+
 	var movementID int
-	err = tx.QueryRow(ctx, `
-		INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id)
-		VALUES ($1, $2, 'RECEIPT', $3, $4, $5, $6, $7, $8)
-		RETURNING id
-	`, companyID, itemID, qty, unitCost, totalCost, parsedDate.Format("2006-01-02"),
-		fmt.Sprintf("Goods receipt: %s × %s units @ %s", productCode, qty.String(), unitCost.String()),
-		poLineID,
-	).Scan(&movementID)
-	if err != nil {
-		return fmt.Errorf("failed to insert inventory movement: %w", err)
+	if len(lots) == 0 {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id)
+			VALUES ($1, $2, 'RECEIPT', $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, companyID, itemID, qty, unitCost, totalCost, receiptDate,
+			fmt.Sprintf("Goods receipt: %s × %s units @ %s", productCode, qty.String(), unitCost.String()),
+			poLineID,
+		).Scan(&movementID)
+		if err != nil {
+			return fmt.Errorf("failed to insert inventory movement: %w", err)
+		}
+	}
+	for i, lot := range lots {
+		lotID, err := s.receiveLotTx(ctx, tx, companyID, itemID, trackingMode, lot, unitCost, receiptDate)
+		if err != nil {
+			return err
+		}
+		var lotMovementID int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id, lot_id)
+			VALUES ($1, $2, 'RECEIPT', $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`, companyID, itemID, lot.Quantity, unitCost, lot.Quantity.Mul(unitCost), receiptDate,
+			fmt.Sprintf("Goods receipt: %s × %s units @ %s (lot %s)", productCode, lot.Quantity.String(), unitCost.String(), lot.LotNumber),
+			poLineID, lotID,
+		).Scan(&lotMovementID)
+		if err != nil {
+			return fmt.Errorf("failed to insert inventory movement for lot %s: %w", lot.LotNumber, err)
+		}
+		if i == 0 {
+			movementID = lotMovementID
+		}
 	}
 
 	// Book accounting entry inside the same tx: DR Inventory / CR creditAccount.
@@ -283,29 +393,222 @@ func (s *inventoryService) ReceiveStock(ctx context.Context, companyCode, wareho
 		DocumentTypeCode:    "GR",
 		CompanyCode:         companyCode,
 		IdempotencyKey:      fmt.Sprintf("goods-receipt-mv-%d", movementID),
-		TransactionCurrency: baseCurrency,
-		ExchangeRate:        "1",
+		TransactionCurrency: currency,
+		ExchangeRate:        exchangeRate.String(),
 		Summary:             fmt.Sprintf("Goods Receipt: %s units of %s @ %s", qty.String(), productCode, unitCost.String()),
 		PostingDate:         movementDate,
 		DocumentDate:        movementDate,
 		Confidence:          1.0,
 		Reasoning:           fmt.Sprintf("Inventory receipt for product %s, %s units at unit cost %s.", productCode, qty.String(), unitCost.String()),
 		Lines: []ProposalLine{
-			{AccountCode: inventoryAccount, IsDebit: true, Amount: totalCost.String()},
-			{AccountCode: creditAccountCode, IsDebit: false, Amount: totalCost.String()},
+			{AccountCode: inventoryAccount, IsDebit: true, Amount: transactionTotal.String()},
+			{AccountCode: creditAccountCode, IsDebit: false, Amount: transactionTotal.String()},
 		},
 	}
 
 	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
 		return fmt.Errorf("failed to book goods receipt journal entry: %w", err)
 	}
+	return nil
+}
 
-	// Single commit: inventory write + journal entry land together or not at all.
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit goods receipt: %w", err)
+// normalizeReceiptLots validates the lots supplied on a receipt against the product's tracking
+// mode. SERIAL lots default to quantity 1; a single LOT entry without quantity takes the full qty.
+func normalizeReceiptLots(productCode, trackingMode string, qty decimal.Decimal, lots []LotInput) ([]LotInput, error) {
+	if trackingMode == "NONE" {
+		if len(lots) > 0 {
+			return nil, fmt.Errorf("product %s is not lot-tracked; lot numbers are not accepted", productCode)
+		}
+		return nil, nil
+	}
+	if len(lots) == 0 {
+		return nil, fmt.Errorf("product %s is %s-tracked: lot numbers are required on receipt", productCode, trackingMode)
 	}
 
-	return nil
+	out := make([]LotInput, len(lots))
+	seen := make(map[string]bool, len(lots))
+	var total decimal.Decimal
+	for i, lot := range lots {
+		lot.LotNumber = strings.TrimSpace(lot.LotNumber)
+		if lot.LotNumber == "" {
+			return nil, fmt.Errorf("lot %d: lot number is required", i+1)
+		}
+		if seen[lot.LotNumber] {
+			return nil, fmt.Errorf("lot %s appears more than once on the receipt", lot.LotNumber)
+		}
+		seen[lot.LotNumber] = true
+
+		if lot.Quantity.IsZero() {
+			if trackingMode == "SERIAL" {
+				lot.Quantity = decimal.NewFromInt(1)
+			} else if len(lots) == 1 {
+				lot.Quantity = qty
+			}
+		}
+		if !lot.Quantity.IsPositive() {
+			return nil, fmt.Errorf("lot %s: quantity must be positive", lot.LotNumber)
+		}
+		if trackingMode == "SERIAL" && !lot.Quantity.Equal(decimal.NewFromInt(1)) {
+			return nil, fmt.Errorf("serial %s: serial-tracked units must have quantity 1", lot.LotNumber)
+		}
+		if lot.ExpiryDate != "" {
+			if _, err := time.Parse("2006-01-02", lot.ExpiryDate); err != nil {
+				return nil, fmt.Errorf("lot %s: invalid expiry date %q (expected YYYY-MM-DD)", lot.LotNumber, lot.ExpiryDate)
+			}
+		}
+		total = total.Add(lot.Quantity)
+		out[i] = lot
+	}
+	if !total.Equal(qty) {
+		return nil, fmt.Errorf("lot quantities total %s but %s units are being received", total.String(), qty.String())
+	}
+	return out, nil
+}
+
+// receiveLotTx adds a receipt to a lot, creating it on first receipt. The lot's unit cost is
+// kept as a weighted average for trace purposes; valuation still uses inventory_items.unit_cost.
+func (s *inventoryService) receiveLotTx(ctx context.Context, tx pgx.Tx, companyID, itemID int, trackingMode string,
+	lot LotInput, unitCost decimal.Decimal, receiptDate string) (int, error) {
+
+	var expiry *string
+	if lot.ExpiryDate != "" {
+		expiry = &lot.ExpiryDate
+	}
+
+	// The upsert takes the row lock for the rest of the transaction.
+	var lotID int
+	var oldQty, oldCost decimal.Decimal
+	var oldExpiry *time.Time
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO inventory_lots (company_id, inventory_item_id, lot_number, expiry_date, received_date, qty_on_hand, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, 0, 0)
+		ON CONFLICT (inventory_item_id, lot_number) DO UPDATE SET updated_at = NOW()
+		RETURNING id, qty_on_hand, unit_cost, expiry_date
+	`, companyID, itemID, lot.LotNumber, expiry, receiptDate).Scan(&lotID, &oldQty, &oldCost, &oldExpiry); err != nil {
+		return 0, fmt.Errorf("failed to upsert lot %s: %w", lot.LotNumber, err)
+	}
+
+	if trackingMode == "SERIAL" && oldQty.IsPositive() {
+		return 0, fmt.Errorf("serial %s is already in stock", lot.LotNumber)
+	}
+	if expiry != nil && oldExpiry != nil && oldExpiry.Format("2006-01-02") != *expiry {
+		return 0, fmt.Errorf("lot %s is already recorded with expiry %s, not %s",
+			lot.LotNumber, oldExpiry.Format("2006-01-02"), *expiry)
+	}
+
+	newQty := oldQty.Add(lot.Quantity)
+	newCost := oldQty.Mul(oldCost).Add(lot.Quantity.Mul(unitCost)).Div(newQty)
+	if _, err := tx.Exec(ctx, `
+		UPDATE inventory_lots
+		SET qty_on_hand = $1, unit_cost = $2, expiry_date = COALESCE(expiry_date, $3), updated_at = NOW()
+		WHERE id = $4
+	`, newQty, newCost, expiry, lotID); err != nil {
+		return 0, fmt.Errorf("failed to update lot %s: %w", lot.LotNumber, err)
+	}
+	return lotID, nil
+}
+
+// GetLots returns lots with stock on hand, ordered by product, warehouse and expiry.
+func (s *inventoryService) GetLots(ctx context.Context, companyCode, productCode string) ([]InventoryLot, error) {
+	var companyID int
+	if err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("company code %s not found", companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve company: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT l.id, p.code, p.name, w.code, l.lot_number,
+		       TO_CHAR(l.expiry_date, 'YYYY-MM-DD'), TO_CHAR(l.received_date, 'YYYY-MM-DD'),
+		       l.qty_on_hand, l.unit_cost,
+		       COALESCE(l.expiry_date < CURRENT_DATE, false)
+		FROM inventory_lots l
+		JOIN inventory_items ii ON ii.id = l.inventory_item_id
+		JOIN products p         ON p.id = ii.product_id
+		JOIN warehouses w       ON w.id = ii.warehouse_id
+		WHERE l.company_id = $1
+		  AND l.qty_on_hand > 0
+		  AND ($2 = '' OR p.code = $2)
+		ORDER BY p.code, w.code, l.expiry_date NULLS LAST, l.received_date, l.lot_number
+	`, companyID, productCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []InventoryLot
+	for rows.Next() {
+		var l InventoryLot
+		if err := rows.Scan(&l.ID, &l.ProductCode, &l.ProductName, &l.WarehouseCode, &l.LotNumber,
+			&l.ExpiryDate, &l.ReceivedDate, &l.QtyOnHand, &l.UnitCost, &l.IsExpired); err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// SetProductTracking switches a product between NONE, LOT and SERIAL tracking.
+// Tracking cannot be changed while the product has stock on hand, because existing
+// stock would have no lots to consume.
+func (s *inventoryService) SetProductTracking(ctx context.Context, companyCode, productCode, trackingMode, pickStrategy string) error {
+	trackingMode = strings.ToUpper(trackingMode)
+	pickStrategy = strings.ToUpper(pickStrategy)
+	if pickStrategy == "" {
+		pickStrategy = "FEFO"
+	}
+	switch trackingMode {
+	case "NONE", "LOT", "SERIAL":
+	default:
+		return fmt.Errorf("invalid tracking mode %q: must be NONE, LOT or SERIAL", trackingMode)
+	}
+	if pickStrategy != "FEFO" && pickStrategy != "FIFO" {
+		return fmt.Errorf("invalid lot pick strategy %q: must be FEFO or FIFO", pickStrategy)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productID int
+	var currentMode string
+	if err := tx.QueryRow(ctx, `
+		SELECT p.id, p.tracking_mode
+		FROM products p
+		JOIN companies c ON c.id = p.company_id
+		WHERE c.company_code = $1 AND p.code = $2
+		FOR UPDATE OF p
+	`, companyCode, productCode).Scan(&productID, &currentMode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("product %s not found for company %s", productCode, companyCode)
+		}
+		return fmt.Errorf("failed to resolve product: %w", err)
+	}
+
+	if currentMode != trackingMode {
+		var onHand decimal.Decimal
+		if err := tx.QueryRow(ctx,
+			"SELECT COALESCE(SUM(qty_on_hand), 0) FROM inventory_items WHERE product_id = $1",
+			productID,
+		).Scan(&onHand); err != nil {
+			return fmt.Errorf("failed to check stock on hand: %w", err)
+		}
+		if !onHand.IsZero() {
+			return fmt.Errorf("cannot change tracking mode of %s from %s to %s with %s units on hand",
+				productCode, currentMode, trackingMode, onHand.String())
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE products SET tracking_mode = $1, lot_pick_strategy = $2 WHERE id = $3",
+		trackingMode, pickStrategy, productID,
+	); err != nil {
+		return fmt.Errorf("failed to update product tracking: %w", err)
+	}
+	return tx.Commit(ctx)
 }
 
 // ── TX-scoped operations ──────────────────────────────────────────────────────
@@ -338,16 +641,16 @@ func (s *inventoryService) ReserveStockTx(ctx context.Context, tx pgx.Tx, compan
 		}
 
 		available := onHand.Sub(reserved)
-		if available.LessThan(line.Quantity) {
+		if available.LessThan(line.StockQuantity) {
 			return fmt.Errorf("insufficient stock for product %s: available %s, required %s",
-				line.ProductCode, available.StringFixed(4), line.Quantity.StringFixed(4))
+				line.ProductCode, available.StringFixed(4), line.StockQuantity.StringFixed(4))
 		}
 
 		// Increase reservation
 		_, err = tx.Exec(ctx, `
 			UPDATE inventory_items SET qty_reserved = qty_reserved + $1, updated_at = NOW()
 			WHERE id = $2
-		`, line.Quantity, itemID)
+		`, line.StockQuantity, itemID)
 		if err != nil {
 			return fmt.Errorf("failed to reserve stock for product %s: %w", line.ProductCode, err)
 		}
@@ -356,7 +659,7 @@ func (s *inventoryService) ReserveStockTx(ctx context.Context, tx pgx.Tx, compan
 		_, err = tx.Exec(ctx, `
 			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, order_id, movement_date, notes)
 			VALUES ($1, $2, 'RESERVATION', $3, 0, 0, $4, CURRENT_DATE, $5)
-		`, companyID, itemID, line.Quantity, orderID,
+		`, companyID, itemID, line.StockQuantity, orderID,
 			fmt.Sprintf("Stock reserved for order ID %d, product %s", orderID, line.ProductCode),
 		)
 		if err != nil {
@@ -425,33 +728,47 @@ func (s *inventoryService) ReleaseReservationTx(ctx context.Context, tx pgx.Tx,
 // ShipStockTx deducts physical stock and books COGS within the caller's TX.
 // The COGS journal entry is committed atomically via Ledger.CommitInTx.
 // Service products (no inventory_item) are silently skipped.
+// Lot-tracked products write one SHIPMENT movement per lot consumed; COGS is still
+// valued at the item's weighted average cost.
 func (s *inventoryService) ShipStockTx(ctx context.Context, tx pgx.Tx, companyID, orderID int, lines []SalesOrderLine,
-	ledger *Ledger, docService DocumentService) error {
+	picks []LotPick, ledger *Ledger, docService DocumentService) error {
 
 	type shipLine struct {
-		itemID    int
-		quantity  decimal.Decimal
-		unitCost  decimal.Decimal
-		lineCOGS  decimal.Decimal
+		itemID      int
+		quantity    decimal.Decimal
+		unitCost    decimal.Decimal
+		lineCOGS    decimal.Decimal
 		productCode string
+		lots        []lotDraw
 	}
 	var toShip []shipLine
 	var totalCOGS decimal.Decimal
 
+	// Explicit picks are queued per product and drawn down line by line.
+	picksByProduct := make(map[string][]LotPick)
+	for _, p := range picks {
+		if !p.Quantity.IsPositive() {
+			return fmt.Errorf("pick of lot %s for product %s: quantity must be positive", p.LotNumber, p.ProductCode)
+		}
+		picksByProduct[p.ProductCode] = append(picksByProduct[p.ProductCode], p)
+	}
+
 	for _, line := range lines {
 		var itemID int
 		var onHand, reserved, unitCost decimal.Decimal
+		var trackingMode, pickStrategy string
 		err := tx.QueryRow(ctx, `
-			SELECT ii.id, ii.qty_on_hand, ii.qty_reserved, ii.unit_cost
+			SELECT ii.id, ii.qty_on_hand, ii.qty_reserved, ii.unit_cost, p.tracking_mode, p.lot_pick_strategy
 			FROM inventory_items ii
 			JOIN warehouses w ON w.id = ii.warehouse_id
+			JOIN products p   ON p.id = ii.product_id
 			WHERE ii.company_id = $1
 			  AND ii.product_id = $2
 			  AND w.is_active = true
 			ORDER BY w.id
 			LIMIT 1
 			FOR UPDATE OF ii
-		`, companyID, line.ProductID).Scan(&itemID, &onHand, &reserved, &unitCost)
+		`, companyID, line.ProductID).Scan(&itemID, &onHand, &reserved, &unitCost, &trackingMode, &pickStrategy)
 		if errors.Is(err, pgx.ErrNoRows) {
 			// No inventory_item = service product, skip
 			continue
@@ -460,26 +777,43 @@ func (s *inventoryService) ShipStockTx(ctx context.Context, tx pgx.Tx, companyID
 			return fmt.Errorf("failed to lock inventory item for product %s: %w", line.ProductCode, err)
 		}
 
-		if onHand.LessThan(line.Quantity) {
+		if onHand.LessThan(line.StockQuantity) {
 			return fmt.Errorf("insufficient stock for shipment: product %s has %s on hand, need %s",
-				line.ProductCode, onHand.StringFixed(4), line.Quantity.StringFixed(4))
+				line.ProductCode, onHand.StringFixed(4), line.StockQuantity.StringFixed(4))
 		}
 
-		lineCOGS := line.Quantity.Mul(unitCost)
+		var draws []lotDraw
+		if trackingMode != "NONE" {
+			productPicks := picksByProduct[line.ProductCode]
+			if len(productPicks) > 0 {
+				draws, productPicks, err = s.drawPickedLotsTx(ctx, tx, itemID, line, productPicks)
+				picksByProduct[line.ProductCode] = productPicks
+			} else {
+				draws, err = s.drawLotsTx(ctx, tx, itemID, line, pickStrategy)
+			}
+			if err != nil {
+				return err
+			}
+		} else if len(picksByProduct[line.ProductCode]) > 0 {
+			return fmt.Errorf("product %s is not lot-tracked; lot picks are not accepted", line.ProductCode)
+		}
+
+		lineCOGS := line.StockQuantity.Mul(unitCost)
 		totalCOGS = totalCOGS.Add(lineCOGS)
 
 		// How much was actually reserved for this order (may be less if ConfirmOrder ran without inventory)
-		reservedForOrder := line.Quantity
+		reservedForOrder := line.StockQuantity
 		if reserved.LessThan(reservedForOrder) {
 			reservedForOrder = reserved
 		}
 
 		toShip = append(toShip, shipLine{
 			itemID:      itemID,
-			quantity:    line.Quantity,
+			quantity:    line.StockQuantity,
 			unitCost:    unitCost,
 			lineCOGS:    lineCOGS,
 			productCode: line.ProductCode,
+			lots:        draws,
 		})
 
 		// Deduct qty_on_hand and qty_reserved
@@ -489,22 +823,44 @@ func (s *inventoryService) ShipStockTx(ctx context.Context, tx pgx.Tx, companyID
 			    qty_reserved = GREATEST(qty_reserved - $2, 0),
 			    updated_at   = NOW()
 			WHERE id = $3
-		`, line.Quantity, reservedForOrder, itemID)
+		`, line.StockQuantity, reservedForOrder, itemID)
 		if err != nil {
 			return fmt.Errorf("failed to deduct inventory for product %s: %w", line.ProductCode, err)
 		}
 	}
 
+	for productCode, remaining := range picksByProduct {
+		if len(remaining) > 0 {
+			return fmt.Errorf("lot picks for product %s exceed the quantity on the order (lot %s left over)",
+				productCode, remaining[0].LotNumber)
+		}
+	}
+
 	// Insert SHIPMENT movement records
 	for _, sl := range toShip {
-		_, err := tx.Exec(ctx, `
-			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, order_id, movement_date, notes)
-			VALUES ($1, $2, 'SHIPMENT', $3, $4, $5, $6, CURRENT_DATE, $7)
-		`, companyID, sl.itemID, sl.quantity.Neg(), sl.unitCost, sl.lineCOGS.Neg(), orderID,
-			fmt.Sprintf("Goods shipped for order ID %d, product %s", orderID, sl.productCode),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert shipment movement for product %s: %w", sl.productCode, err)
+		if len(sl.lots) == 0 {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, order_id, movement_date, notes)
+				VALUES ($1, $2, 'SHIPMENT', $3, $4, $5, $6, CURRENT_DATE, $7)
+			`, companyID, sl.itemID, sl.quantity.Neg(), sl.unitCost, sl.lineCOGS.Neg(), orderID,
+				fmt.Sprintf("Goods shipped for order ID %d, product %s", orderID, sl.productCode),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert shipment movement for product %s: %w", sl.productCode, err)
+			}
+			continue
+		}
+		for _, d := range sl.lots {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, order_id, movement_date, notes, lot_id)
+				VALUES ($1, $2, 'SHIPMENT', $3, $4, $5, $6, CURRENT_DATE, $7, $8)
+			`, companyID, sl.itemID, d.quantity.Neg(), sl.unitCost, d.quantity.Mul(sl.unitCost).Neg(), orderID,
+				fmt.Sprintf("Goods shipped for order ID %d, product %s (lot %s)", orderID, sl.productCode, d.lotNumber),
+				d.lotID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert shipment movement for product %s lot %s: %w", sl.productCode, d.lotNumber, err)
+			}
 		}
 	}
 
@@ -550,3 +906,126 @@ func (s *inventoryService) ShipStockTx(ctx context.Context, tx pgx.Tx, companyID
 
 	return nil
 }
+
+// lotDraw is a quantity taken from one lot during shipment.
+type lotDraw struct {
+	lotID     int
+	lotNumber string
+	quantity  decimal.Decimal
+}
+
+// drawLotsTx consumes unexpired lots for one order line in FEFO or FIFO order.
+func (s *inventoryService) drawLotsTx(ctx context.Context, tx pgx.Tx, itemID int, line SalesOrderLine, pickStrategy string) ([]lotDraw, error) {
+	orderBy := "l.expiry_date NULLS LAST, l.received_date, l.id"
+	if pickStrategy == "FIFO" {
+		orderBy = "l.received_date, l.id"
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT l.id, l.lot_number, l.qty_on_hand
+		FROM inventory_lots l
+		WHERE l.inventory_item_id = $1
+		  AND l.qty_on_hand > 0
+		  AND (l.expiry_date IS NULL OR l.expiry_date >= CURRENT_DATE)
+		ORDER BY `+orderBy+`
+		FOR UPDATE
+	`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock lots for product %s: %w", line.ProductCode, err)
+	}
+
+	var draws []lotDraw
+	remaining := line.StockQuantity
+	for rows.Next() && remaining.IsPositive() {
+		var d lotDraw
+		var lotQty decimal.Decimal
+		if err := rows.Scan(&d.lotID, &d.lotNumber, &lotQty); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan lot for product %s: %w", line.ProductCode, err)
+		}
+		d.quantity = decimal.Min(lotQty, remaining)
+		remaining = remaining.Sub(d.quantity)
+		draws = append(draws, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lots for product %s: %w", line.ProductCode, err)
+	}
+
+	if remaining.IsPositive() {
+		var expired decimal.Decimal
+		if err := tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(qty_on_hand), 0) FROM inventory_lots
+			WHERE inventory_item_id = $1 AND expiry_date < CURRENT_DATE`, itemID,
+		).Scan(&expired); err != nil {
+			return nil, fmt.Errorf("failed to check expired lots for product %s: %w", line.ProductCode, err)
+		}
+		return nil, fmt.Errorf("insufficient unexpired lot stock for product %s: short by %s (%s units in expired lots are blocked)",
+			line.ProductCode, remaining.StringFixed(4), expired.StringFixed(4))
+	}
+
+	for _, d := range draws {
+		if err := deductLotTx(ctx, tx, d); err != nil {
+			return nil, err
+		}
+	}
+	return draws, nil
+}
+
+// drawPickedLotsTx consumes explicitly picked lots for one order line and returns the picks
+// left over for later lines of the same product.
+func (s *inventoryService) drawPickedLotsTx(ctx context.Context, tx pgx.Tx, itemID int, line SalesOrderLine, picks []LotPick) ([]lotDraw, []LotPick, error) {
+	var draws []lotDraw
+	remaining := line.StockQuantity
+	for remaining.IsPositive() && len(picks) > 0 {
+		p := &picks[0]
+		var d lotDraw
+		var lotQty decimal.Decimal
+		var expired bool
+		err := tx.QueryRow(ctx, `
+			SELECT id, lot_number, qty_on_hand, COALESCE(expiry_date < CURRENT_DATE, false)
+			FROM inventory_lots
+			WHERE inventory_item_id = $1 AND lot_number = $2
+			FOR UPDATE
+		`, itemID, p.LotNumber).Scan(&d.lotID, &d.lotNumber, &lotQty, &expired)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, nil, fmt.Errorf("lot %s not found for product %s", p.LotNumber, line.ProductCode)
+			}
+			return nil, nil, fmt.Errorf("failed to lock lot %s: %w", p.LotNumber, err)
+		}
+		if expired {
+			return nil, nil, fmt.Errorf("lot %s of product %s has expired and cannot be shipped", p.LotNumber, line.ProductCode)
+		}
+
+		d.quantity = decimal.Min(p.Quantity, remaining)
+		if lotQty.LessThan(d.quantity) {
+			return nil, nil, fmt.Errorf("lot %s of product %s has %s on hand, picked %s",
+				p.LotNumber, line.ProductCode, lotQty.StringFixed(4), d.quantity.StringFixed(4))
+		}
+		if err := deductLotTx(ctx, tx, d); err != nil {
+			return nil, nil, err
+		}
+		draws = append(draws, d)
+
+		remaining = remaining.Sub(d.quantity)
+		p.Quantity = p.Quantity.Sub(d.quantity)
+		if !p.Quantity.IsPositive() {
+			picks = picks[1:]
+		}
+	}
+	if remaining.IsPositive() {
+		return nil, nil, fmt.Errorf("lot picks for product %s cover %s of %s units",
+			line.ProductCode, line.StockQuantity.Sub(remaining).StringFixed(4), line.StockQuantity.StringFixed(4))
+	}
+	return draws, picks, nil
+}
+
+func deductLotTx(ctx context.Context, tx pgx.Tx, d lotDraw) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE inventory_lots SET qty_on_hand = qty_on_hand - $1, updated_at = NOW()
+		WHERE id = $2
+	`, d.quantity, d.lotID); err != nil {
+		return fmt.Errorf("failed to deduct lot %s: %w", d.lotNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stubIRPClient is an in-process IRPClient for development and tests. It derives the IRN
+// the way the IRP does (SHA-256 of seller GSTIN, financial year, document type and number)
+// and returns unsigned JWS-shaped invoice and QR data. IRNs it issues are not registered
+// with the government and are not legally valid. Its state is in memory: the einvoices
+// table, not the stub, is what prevents a second IRN for an order across restarts.
+type stubIRPClient struct {
+	mu        sync.Mutex
+	ackSeq    int64
+	issued    map[string]bool
+	cancelled map[string]bool
+}
+
+// NewStubIRPClient constructs a local IRPClient that registers nothing externally.
+func NewStubIRPClient() IRPClient {
+	return &stubIRPClient{ackSeq: time.Now().Unix() * 1000, issued: map[string]bool{}, cancelled: map[string]bool{}}
+}
+
+// GenerateIRN issues an IRN, rejecting a document number already registered.
+func (c *stubIRPClient) GenerateIRN(_ context.Context, payload *EInvoicePayload) (*IRPAck, error) {
+	docDate, err := time.Parse("02/01/2006", payload.DocDtls.Dt)
+	if err != nil {
+		return nil, fmt.Errorf("IRP: invalid document date %q", payload.DocDtls.Dt)
+	}
+	sum := sha256.Sum256([]byte(payload.SellerDtls.Gstin + FinancialYearLabel(FinancialYear(docDate)) +
+		payload.DocDtls.Typ + payload.DocDtls.No))
+	irn := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.issued[irn] {
+		return nil, fmt.Errorf("IRP: duplicate IRN for document %s", payload.DocDtls.No)
+	}
+	c.ackSeq++
+	ackDate := time.Now()
+	c.issued[irn] = true
+
+	mainHSN := ""
+	if len(payload.ItemList) > 0 {
+		mainHSN = payload.ItemList[0].HsnCd
+	}
+	qr := map[string]any{
+		"SellerGstin": payload.SellerDtls.Gstin,
+		"BuyerGstin":  payload.BuyerDtls.Gstin,
+		"DocNo":       payload.DocDtls.No,
+		"DocTyp":      payload.DocDtls.Typ,
+		"DocDt":       payload.DocDtls.Dt,
+		"TotInvVal":   payload.ValDtls.TotInvVal,
+		"ItemCnt":     len(payload.ItemList),
+		"MainHsnCode": mainHSN,
+		"Irn":         irn,
+		"IrnDt":       ackDate.Format("2006-01-02 15:04:05"),
+	}
+	return &IRPAck{
+		IRN:           irn,
+		AckNumber:     fmt.Sprintf("%d", c.ackSeq),
+		AckDate:       ackDate,
+		SignedInvoice: unsignedJWS(map[string]any{"data": payload}),
+		SignedQRCode:  unsignedJWS(map[string]any{"data": qr}),
+	}, nil
+}
+
+// CancelIRN cancels an IRN once. The caller checks EInvoiceCancelWindow.
+func (c *stubIRPClient) CancelIRN(_ context.Context, _, irn string, reasonCode int, _ string) (time.Time, error) {
+	if reasonCode < 1 || reasonCode > 4 {
+		return time.Time{}, fmt.Errorf("IRP: invalid cancel reason code %d", reasonCode)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancelled[irn] {
+		return time.Time{}, fmt.Errorf("IRP: IRN %s is already cancelled", irn)
+	}
+	c.cancelled[irn] = true
+	return time.Now(), nil
+}
+
+// unsignedJWS encodes claims as a JWS with the "none" algorithm, the shape of the IRP's
+// signed invoice and QR code without a signature.
+func unsignedJWS(claims any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	body, _ := json.Marshal(claims)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(header) + "." + enc.EncodeToString(body) + "."
+}
@@ -0,0 +1,130 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestJournal_RegisterDayBookAndLedger(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	journal := core.NewJournalService(pool)
+	ctx := context.Background()
+
+	post := func(date, summary, debit, credit, amount string) {
+		t.Helper()
+		if err := ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: date, DocumentDate: date, Summary: summary, Reasoning: "test",
+			Lines: []core.ProposalLine{
+				{AccountCode: debit, IsDebit: true, Amount: amount},
+				{AccountCode: credit, IsDebit: false, Amount: amount},
+			},
+		}); err != nil {
+			t.Fatalf("Commit %s: %v", summary, err)
+		}
+	}
+	post("2026-01-20", "capital", "1000", "3000", "1000.00")
+	post("2026-02-03", "sale", "1200", "4000", "300.00")
+	post("2026-02-03", "rent", "5100", "1000", "120.00")
+	post("2026-02-10", "big sale", "1000", "4000", "5000.00")
+
+	t.Run("register filters and pages", func(t *testing.T) {
+		reg, err := journal.GetJournalRegister(ctx, "1000", core.JournalFilter{AccountCode: "1000", PageSize: 2, Page: 2})
+		if err != nil {
+			t.Fatalf("GetJournalRegister: %v", err)
+		}
+		if reg.Total != 3 || len(reg.Entries) != 1 || reg.Entries[0].Narration != "big sale" {
+			t.Errorf("account filter page 2: got total %d, %+v", reg.Total, reg.Entries)
+		}
+		if reg.Entries[0].DocumentType != "JE" || reg.Entries[0].Reference == "" {
+			t.Errorf("expected the JE document reference: %+v", reg.Entries[0])
+		}
+
+		min, max := decimal.RequireFromString("200"), decimal.RequireFromString("1000")
+		reg, err = journal.GetJournalRegister(ctx, "1000", core.JournalFilter{MinAmount: &min, MaxAmount: &max, FromDate: "2026-02-01"})
+		if err != nil {
+			t.Fatalf("GetJournalRegister: %v", err)
+		}
+		if reg.Total != 1 || reg.Entries[0].Narration != "sale" || !reg.Entries[0].Amount.Equal(decimal.RequireFromString("300")) {
+			t.Errorf("amount filter: got %+v", reg.Entries)
+		}
+
+		reg, err = journal.GetJournalRegister(ctx, "1000", core.JournalFilter{DocumentType: "SI"})
+		if err != nil || reg.Total != 0 {
+			t.Errorf("document type filter: got %v, %v", reg, err)
+		}
+	})
+
+	t.Run("entry detail and reversal link", func(t *testing.T) {
+		reg, err := journal.GetJournalRegister(ctx, "1000", core.JournalFilter{})
+		if err != nil {
+			t.Fatalf("GetJournalRegister: %v", err)
+		}
+		id := reg.Entries[0].ID
+		if err := ledger.Reverse(ctx, id, "error"); err != nil {
+			t.Fatalf("Reverse: %v", err)
+		}
+		e, err := journal.GetJournalEntry(ctx, "1000", id)
+		if err != nil {
+			t.Fatalf("GetJournalEntry: %v", err)
+		}
+		if len(e.Lines) != 2 || e.Lines[0].AccountCode != "1000" || e.Lines[0].DebitBase != "1000.00" {
+			t.Errorf("lines: got %+v", e.Lines)
+		}
+		if e.Document == nil || e.Document.TypeCode != "JE" {
+			t.Errorf("linked document: got %+v", e.Document)
+		}
+		if e.ReversedByEntryID == nil {
+			t.Fatal("expected a reversal link")
+		}
+		rev, err := journal.GetJournalEntry(ctx, "1000", *e.ReversedByEntryID)
+		if err != nil || rev.ReversedEntryID == nil || *rev.ReversedEntryID != id {
+			t.Errorf("reversal entry: got %+v, %v", rev, err)
+		}
+		if _, err := journal.GetJournalEntry(ctx, "1000", 999999); err == nil {
+			t.Error("expected error for a missing entry")
+		}
+	})
+
+	t.Run("day book", func(t *testing.T) {
+		book, err := journal.GetDayBook(ctx, "1000", "2026-02-03")
+		if err != nil {
+			t.Fatalf("GetDayBook: %v", err)
+		}
+		if len(book.Entries) != 2 || book.TotalDebit.StringFixed(2) != "420.00" || !book.TotalDebit.Equal(book.TotalCredit) {
+			t.Errorf("got %d entries, debits %s credits %s", len(book.Entries), book.TotalDebit, book.TotalCredit)
+		}
+	})
+
+	t.Run("general ledger", func(t *testing.T) {
+		gl, err := journal.GetGeneralLedger(ctx, "1000", "2026-02-01", "2026-02-28")
+		if err != nil {
+			t.Fatalf("GetGeneralLedger: %v", err)
+		}
+		byCode := map[string]core.LedgerAccount{}
+		for _, a := range gl.Accounts {
+			byCode[a.Code] = a
+		}
+		// The January capital entry was reversed on its own date, so cash opens at zero.
+		cash := byCode["1000"]
+		if !cash.Opening.IsZero() || len(cash.Lines) != 2 || cash.Closing.StringFixed(2) != "4880.00" {
+			t.Errorf("cash: opening %s, %d lines, closing %s", cash.Opening, len(cash.Lines), cash.Closing)
+		}
+		if _, ok := byCode["3000"]; ok {
+			t.Error("capital has no balance or movements in February and should be omitted")
+		}
+		if sales := byCode["4000"]; sales.Closing.StringFixed(2) != "-5300.00" {
+			t.Errorf("sales closing: got %s", sales.Closing)
+		}
+	})
+}
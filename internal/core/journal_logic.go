@@ -0,0 +1,32 @@
+package core
+
+import "github.com/shopspring/decimal"
+
+// normalizePage applies the register defaults: page 1 and DefaultJournalPageSize, with the
+// page size capped at MaxJournalPageSize.
+func (f *JournalFilter) normalizePage() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PageSize < 1 {
+		f.PageSize = DefaultJournalPageSize
+	}
+	if f.PageSize > MaxJournalPageSize {
+		f.PageSize = MaxJournalPageSize
+	}
+}
+
+// ComputeBalances sets the running balance of each line starting from a.Opening, the
+// debit and credit totals and the closing balance.
+func (a *LedgerAccount) ComputeBalances() {
+	running := a.Opening
+	a.TotalDebit, a.TotalCredit = decimal.Zero, decimal.Zero
+	for i := range a.Lines {
+		l := &a.Lines[i]
+		running = running.Add(l.Debit).Sub(l.Credit)
+		l.Balance = running
+		a.TotalDebit = a.TotalDebit.Add(l.Debit)
+		a.TotalCredit = a.TotalCredit.Add(l.Credit)
+	}
+	a.Closing = running
+}
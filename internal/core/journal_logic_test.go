@@ -0,0 +1,41 @@
+package core_test
+
+import (
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLedgerAccount_ComputeBalances(t *testing.T) {
+	d := decimal.RequireFromString
+	a := core.LedgerAccount{
+		Code:    "1000",
+		Opening: d("500"),
+		Lines: []core.LedgerLine{
+			{EntryID: 1, Debit: d("200"), Credit: decimal.Zero},
+			{EntryID: 2, Debit: decimal.Zero, Credit: d("750")},
+			{EntryID: 3, Debit: d("25.50"), Credit: decimal.Zero},
+		},
+	}
+	a.ComputeBalances()
+
+	for i, want := range []string{"700.00", "-50.00", "-24.50"} {
+		if got := a.Lines[i].Balance.StringFixed(2); got != want {
+			t.Errorf("line %d balance: got %s, want %s", i, got, want)
+		}
+	}
+	if a.TotalDebit.StringFixed(2) != "225.50" || a.TotalCredit.StringFixed(2) != "750.00" {
+		t.Errorf("totals: got %s / %s", a.TotalDebit, a.TotalCredit)
+	}
+	if a.Closing.StringFixed(2) != "-24.50" {
+		t.Errorf("closing: got %s, want -24.50", a.Closing)
+	}
+
+	empty := core.LedgerAccount{Opening: d("10")}
+	empty.ComputeBalances()
+	if !empty.Closing.Equal(d("10")) || !empty.TotalDebit.IsZero() {
+		t.Errorf("no movements: got closing %s, debits %s", empty.Closing, empty.TotalDebit)
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// Journal register page sizes.
+const (
+	DefaultJournalPageSize = 50
+	MaxJournalPageSize     = 500
+)
+
+// JournalFilter selects entries for the journal register. Empty fields do not filter.
+type JournalFilter struct {
+	FromDate     string
+	ToDate       string
+	DocumentType string           // type code of the linked document (JE, SI, PI, …)
+	AccountCode  string           // entries with a line on this account
+	MinAmount    *decimal.Decimal // entry total: the sum of its debits in base currency
+	MaxAmount    *decimal.Decimal
+	Reference    string // part of the reference (document number), case-insensitive
+	CreatedBy    string // username of the user who posted the entry
+	Page         int    // 1-based; 0 means 1
+	PageSize     int    // 0 means DefaultJournalPageSize; at most MaxJournalPageSize
+}
+
+// JournalRegisterEntry is one entry in the journal register.
+type JournalRegisterEntry struct {
+	ID                int
+	PostingDate       string
+	DocumentDate      string
+	Narration         string
+	DocumentType      string // empty when no document is linked
+	Reference         string
+	CreatedBy         string // empty for entries posted by document workflows
+	Amount            decimal.Decimal
+	ReversedEntryID   *int
+	ReversedByEntryID *int
+}
+
+// JournalRegister is one page of the journal register in posting order. Total counts the
+// entries matching the filter across all pages.
+type JournalRegister struct {
+	CompanyCode string
+	Entries     []JournalRegisterEntry
+	Total       int
+	Page        int
+	PageSize    int
+}
+
+// DayBook lists every entry posted on a date with its lines.
+type DayBook struct {
+	CompanyCode string
+	Date        string
+	Entries     []JournalEntry
+	TotalDebit  decimal.Decimal
+	TotalCredit decimal.Decimal
+}
+
+// LedgerLine is one journal line in the general ledger. Balance is the running net debit
+// (debit − credit) of the account after the line.
+type LedgerLine struct {
+	EntryID     int
+	PostingDate string
+	Narration   string
+	Reference   string
+	Debit       decimal.Decimal
+	Credit      decimal.Decimal
+	Balance     decimal.Decimal
+}
+
+// LedgerAccount is one account in the general ledger. Opening and Closing are net debit
+// balances before FromDate and at ToDate.
+type LedgerAccount struct {
+	Code        string
+	Name        string
+	Type        AccountType
+	Opening     decimal.Decimal
+	Lines       []LedgerLine
+	TotalDebit  decimal.Decimal
+	TotalCredit decimal.Decimal
+	Closing     decimal.Decimal
+}
+
+// GeneralLedger covers every account with an opening balance or movements in the range.
+type GeneralLedger struct {
+	CompanyCode string
+	FromDate    string
+	ToDate      string
+	Accounts    []LedgerAccount
+}
+
+// JournalService lists and looks up posted journal entries.
+type JournalService interface {
+	// GetJournalRegister returns one page of the entries matching filter.
+	GetJournalRegister(ctx context.Context, companyCode string, filter JournalFilter) (*JournalRegister, error)
+
+	// GetJournalEntry returns an entry with its lines, linked document and reversal links.
+	GetJournalEntry(ctx context.Context, companyCode string, entryID int) (*JournalEntry, error)
+
+	// GetDayBook returns the entries posted on date (empty means today).
+	GetDayBook(ctx context.Context, companyCode, date string) (*DayBook, error)
+
+	// GetGeneralLedger returns every account's lines in [fromDate, toDate] with opening and
+	// closing balances. toDate defaults to today and fromDate to the first of its month.
+	GetGeneralLedger(ctx context.Context, companyCode, fromDate, toDate string) (*GeneralLedger, error)
+}
@@ -0,0 +1,351 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type journalService struct {
+	pool *pgxpool.Pool
+}
+
+// NewJournalService constructs a JournalService backed by PostgreSQL.
+func NewJournalService(pool *pgxpool.Pool) JournalService {
+	return &journalService{pool: pool}
+}
+
+func (s *journalService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
+
+// journalEntryFrom joins an entry (je) to its linked document (d), the user who posted it
+// (u), its total debits (t.amount) and the entry reversing it (rv.id).
+const journalEntryFrom = `
+	FROM journal_entries je
+	LEFT JOIN documents d ON je.reference_type = 'DOCUMENT'
+	                     AND d.company_id = je.company_id AND d.document_number = je.reference_id
+	LEFT JOIN users u ON u.id = je.created_by_user_id
+	LEFT JOIN LATERAL (
+	    SELECT COALESCE(SUM(debit_base), 0) AS amount FROM journal_lines WHERE entry_id = je.id
+	) t ON true
+	LEFT JOIN LATERAL (
+	    SELECT MIN(id) AS id FROM journal_entries WHERE reversed_entry_id = je.id
+	) rv ON true`
+
+// ── GetJournalRegister ────────────────────────────────────────────────────────
+
+func (s *journalService) GetJournalRegister(ctx context.Context, companyCode string, filter JournalFilter) (*JournalRegister, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	filter.normalizePage()
+	for _, d := range []string{filter.FromDate, filter.ToDate} {
+		if d != "" {
+			if _, err := parseReportDate(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	args := []any{companyID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := " WHERE je.company_id = $1"
+	if filter.FromDate != "" {
+		where += " AND je.posting_date >= " + arg(filter.FromDate) + "::date"
+	}
+	if filter.ToDate != "" {
+		where += " AND je.posting_date <= " + arg(filter.ToDate) + "::date"
+	}
+	if filter.DocumentType != "" {
+		where += " AND d.type_code = " + arg(strings.ToUpper(strings.TrimSpace(filter.DocumentType)))
+	}
+	if filter.AccountCode != "" {
+		where += ` AND EXISTS (
+			SELECT 1 FROM journal_lines fl JOIN accounts fa ON fa.id = fl.account_id
+			WHERE fl.entry_id = je.id AND fa.code = ` + arg(filter.AccountCode) + `)`
+	}
+	if filter.MinAmount != nil {
+		where += " AND t.amount >= " + arg(*filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		where += " AND t.amount <= " + arg(*filter.MaxAmount)
+	}
+	if filter.Reference != "" {
+		where += " AND je.reference_id ILIKE '%' || " + arg(strings.TrimSpace(filter.Reference)) + " || '%'"
+	}
+	if filter.CreatedBy != "" {
+		where += " AND u.username = " + arg(filter.CreatedBy)
+	}
+
+	reg := &JournalRegister{CompanyCode: companyCode, Page: filter.Page, PageSize: filter.PageSize}
+	if err := s.pool.QueryRow(ctx, "SELECT count(*)"+journalEntryFrom+where, args...).Scan(&reg.Total); err != nil {
+		return nil, fmt.Errorf("failed to count journal entries: %w", err)
+	}
+
+	q := `
+		SELECT je.id, je.posting_date::text, je.document_date::text, je.narration,
+		       COALESCE(d.type_code, ''), COALESCE(je.reference_id, ''), COALESCE(u.username, ''),
+		       t.amount, je.reversed_entry_id, rv.id` + journalEntryFrom + where +
+		" ORDER BY je.posting_date, je.id LIMIT " + arg(filter.PageSize) + " OFFSET " + arg((filter.Page-1)*filter.PageSize)
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal register: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e JournalRegisterEntry
+		if err := rows.Scan(&e.ID, &e.PostingDate, &e.DocumentDate, &e.Narration,
+			&e.DocumentType, &e.Reference, &e.CreatedBy,
+			&e.Amount, &e.ReversedEntryID, &e.ReversedByEntryID); err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		reg.Entries = append(reg.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("journal register row iteration error: %w", err)
+	}
+	return reg, nil
+}
+
+// ── Entries with lines ────────────────────────────────────────────────────────
+
+// queryEntries returns the entries selected by where (with args; $1 is the company id)
+// in posting order, each with its lines, linked document and reversal links.
+func (s *journalService) queryEntries(ctx context.Context, where string, args ...any) ([]JournalEntry, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT je.id, je.company_id, COALESCE(je.idempotency_key, ''),
+		       je.posting_date, je.document_date, COALESCE(je.created_at, now()),
+		       COALESCE(u.username, ''), je.narration, je.reference_type, je.reference_id,
+		       COALESCE(je.reasoning, ''), je.reversed_entry_id, rv.id,
+		       d.id, d.type_code, d.status, d.financial_year, d.branch_id, d.created_at, d.posted_at`+
+		journalEntryFrom+" WHERE je.company_id = $1 AND "+where+
+		" ORDER BY je.posting_date, je.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var docID *int
+		var docType, docStatus *string
+		var doc Document
+		var docCreated *time.Time
+		if err := rows.Scan(&e.ID, &e.CompanyID, &e.IdempotencyKey,
+			&e.PostingDate, &e.DocumentDate, &e.CreatedAt,
+			&e.CreatedBy, &e.Narration, &e.ReferenceType, &e.ReferenceID,
+			&e.Reasoning, &e.ReversedEntryID, &e.ReversedByEntryID,
+			&docID, &docType, &docStatus, &doc.FinancialYear, &doc.BranchID, &docCreated, &doc.PostedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		if docID != nil {
+			doc.ID, doc.CompanyID, doc.TypeCode = *docID, e.CompanyID, *docType
+			doc.Status, doc.DocumentNumber, doc.CreatedAt = DocumentStatus(*docStatus), e.ReferenceID, *docCreated
+			e.Document = &doc
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("journal entry row iteration error: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int, len(entries))
+	index := make(map[int]int, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+		index[e.ID] = i
+	}
+	lineRows, err := s.pool.Query(ctx, `
+		SELECT jl.id, jl.entry_id, jl.account_id, a.code, a.name,
+		       jl.transaction_currency, COALESCE(jl.exchange_rate, 1), jl.amount_transaction,
+		       jl.debit_base, jl.credit_base,
+		       (SELECT COALESCE(jsonb_agg(jsonb_build_object('dimension', d.code, 'value', v.code) ORDER BY d.code), '[]')
+		        FROM journal_line_dimensions jld
+		        JOIN dimensions d       ON d.id = jld.dimension_id
+		        JOIN dimension_values v ON v.id = jld.value_id
+		        WHERE jld.journal_line_id = jl.id)
+		FROM journal_lines jl
+		JOIN accounts a ON a.id = jl.account_id
+		WHERE jl.entry_id = ANY($1)
+		ORDER BY jl.entry_id, jl.id`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal lines: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var l JournalLine
+		var rate, amount, debit, credit decimal.Decimal
+		if err := lineRows.Scan(&l.ID, &l.EntryID, &l.AccountID, &l.AccountCode, &l.AccountName,
+			&l.TransactionCurrency, &rate, &amount, &debit, &credit, &l.Dimensions); err != nil {
+			return nil, fmt.Errorf("failed to scan journal line: %w", err)
+		}
+		l.ExchangeRate, l.AmountTransaction = rate.String(), amount.StringFixed(2)
+		l.DebitBase, l.CreditBase = debit.StringFixed(2), credit.StringFixed(2)
+		e := &entries[index[l.EntryID]]
+		e.Lines = append(e.Lines, l)
+	}
+	if err := lineRows.Err(); err != nil {
+		return nil, fmt.Errorf("journal line row iteration error: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *journalService) GetJournalEntry(ctx context.Context, companyCode string, entryID int) (*JournalEntry, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.queryEntries(ctx, "je.id = $2", companyID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("journal entry %d not found", entryID)
+	}
+	return &entries[0], nil
+}
+
+// ── GetDayBook ────────────────────────────────────────────────────────────────
+
+func (s *journalService) GetDayBook(ctx context.Context, companyCode, date string) (*DayBook, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	day, err := parseReportDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &DayBook{CompanyCode: companyCode, Date: day.Format("2006-01-02")}
+	book.Entries, err = s.queryEntries(ctx, "je.posting_date = $2::date", companyID, book.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range book.Entries {
+		for _, l := range e.Lines {
+			book.TotalDebit = book.TotalDebit.Add(decimal.RequireFromString(l.DebitBase))
+			book.TotalCredit = book.TotalCredit.Add(decimal.RequireFromString(l.CreditBase))
+		}
+	}
+	return book, nil
+}
+
+// ── GetGeneralLedger ──────────────────────────────────────────────────────────
+
+func (s *journalService) GetGeneralLedger(ctx context.Context, companyCode, fromDate, toDate string) (*GeneralLedger, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseReportDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if fromDate != "" {
+		if from, err = parseReportDate(fromDate); err != nil {
+			return nil, err
+		}
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("from date %s is after to date %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	gl := &GeneralLedger{CompanyCode: companyCode, FromDate: from.Format("2006-01-02"), ToDate: to.Format("2006-01-02")}
+
+	// Openings of every account with postings before the range.
+	accounts := map[string]*LedgerAccount{}
+	var order []string
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, COALESCE(SUM(jl.debit_base) - SUM(jl.credit_base), 0)
+		FROM accounts a
+		JOIN journal_lines jl   ON jl.account_id = a.id
+		JOIN journal_entries je ON je.id = jl.entry_id
+		WHERE a.company_id = $1 AND je.posting_date < $2::date
+		GROUP BY a.code, a.name, a.type`, companyID, gl.FromDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query opening balances: %w", err)
+	}
+	for rows.Next() {
+		a := &LedgerAccount{}
+		if err := rows.Scan(&a.Code, &a.Name, &a.Type, &a.Opening); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan opening balance: %w", err)
+		}
+		accounts[a.Code] = a
+		order = append(order, a.Code)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("opening balance row iteration error: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, je.id, je.posting_date::text, je.narration,
+		       COALESCE(je.reference_id, ''), jl.debit_base, jl.credit_base
+		FROM journal_lines jl
+		JOIN journal_entries je ON je.id = jl.entry_id
+		JOIN accounts a         ON a.id  = jl.account_id
+		WHERE je.company_id = $1 AND je.posting_date BETWEEN $2::date AND $3::date
+		ORDER BY a.code, je.posting_date, je.id, jl.id`, companyID, gl.FromDate, gl.ToDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query general ledger: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var code, name string
+		var accountType AccountType
+		var l LedgerLine
+		if err := rows.Scan(&code, &name, &accountType, &l.EntryID, &l.PostingDate, &l.Narration,
+			&l.Reference, &l.Debit, &l.Credit); err != nil {
+			return nil, fmt.Errorf("failed to scan general ledger line: %w", err)
+		}
+		a, ok := accounts[code]
+		if !ok {
+			a = &LedgerAccount{Code: code, Name: name, Type: accountType}
+			accounts[code] = a
+			order = append(order, code)
+		}
+		a.Lines = append(a.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("general ledger row iteration error: %w", err)
+	}
+
+	sort.Strings(order)
+	for _, code := range order {
+		a := accounts[code]
+		if a.Opening.IsZero() && len(a.Lines) == 0 {
+			continue
+		}
+		a.ComputeBalances()
+		gl.Accounts = append(gl.Accounts, *a)
+	}
+	return gl, nil
+}
@@ -0,0 +1,184 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLandedCost_AllocateAndRevalue(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1200', 'Accounts Receivable', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy) VALUES
+		('SO', 'Sales Order',         false, false, true,  false, 'global',     true),
+		('GI', 'Goods Issue',         true,  true,  false, false, 'sequential', false),
+		('LC', 'Landed Cost Voucher', true,  true,  false, true,  'sequential', false)
+		ON CONFLICT (code) DO NOTHING;
+
+		INSERT INTO customers (company_id, code, name, credit_limit, payment_terms_days)
+		VALUES (1, 'C001', 'Acme Corp', 100000, 30)
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO products (company_id, code, name, description, unit_price, unit, revenue_account_code)
+		VALUES (1, 'P003', 'Widget B', 'Premium widget', 1200.00, 'unit', '4000')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO account_rules (company_id, rule_type, account_code) VALUES
+		(1, 'AR', '1200')
+		ON CONFLICT DO NOTHING;
+	`)
+	if err != nil {
+		t.Fatalf("seed landed cost test data: %v", err)
+	}
+
+	companyCode := "1000"
+	lcSvc := core.NewLandedCostService(pool, core.NewRuleEngine(pool))
+	orderSvc := core.NewOrderService(pool, core.NewRuleEngine(pool), core.NewTaxEngine(pool))
+	reportSvc := core.NewReportingService(pool)
+
+	// Import shipment: 10 × P001 @ 100 (1,000) and 5 × P003 @ 400 (2,000).
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(100)},
+		{ProductCode: "P003", Description: "Widget B", Quantity: decimal.NewFromInt(5), UnitCost: decimal.NewFromInt(400)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	received := make([]core.ReceivedLine, len(po.Lines))
+	for i, l := range po.Lines {
+		received[i] = core.ReceivedLine{POLineID: l.ID, QtyReceived: l.Quantity}
+	}
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode, received, "2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+	po, err = poService.GetPO(ctx, po.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	poNumber := *po.PONumber
+
+	// Ship 4 of the 10 P001 before the freight invoice arrives.
+	order, err := orderSvc.CreateOrder(ctx, companyCode, "C001", "INR", decimal.NewFromInt(1), "2026-03-05",
+		[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(4)}}, "")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docService, invSvc); err != nil {
+		t.Fatalf("ConfirmOrder: %v", err)
+	}
+	if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docService); err != nil {
+		t.Fatalf("ShipOrder: %v", err)
+	}
+
+	freight := []core.LandedCostChargeInput{{Description: "Sea freight", Amount: decimal.NewFromInt(300)}}
+
+	t.Run("NoCharges_Fails", func(t *testing.T) {
+		_, err := lcSvc.CreateVoucher(ctx, companyCode, core.LandedCostVoucherInput{
+			VoucherDate: "2026-03-10", VendorCode: "V001", PONumbers: []string{poNumber},
+		}, ledger)
+		if err == nil {
+			t.Error("expected error for a voucher without charges, got nil")
+		}
+	})
+
+	t.Run("UnknownPO_Fails", func(t *testing.T) {
+		_, err := lcSvc.CreateVoucher(ctx, companyCode, core.LandedCostVoucherInput{
+			VoucherDate: "2026-03-10", VendorCode: "V001", PONumbers: []string{"PO-9999-99999"}, Charges: freight,
+		}, ledger)
+		if err == nil {
+			t.Error("expected error for a PO without receipts, got nil")
+		}
+	})
+
+	t.Run("WeightWithoutUnitWeights_Fails", func(t *testing.T) {
+		_, err := lcSvc.CreateVoucher(ctx, companyCode, core.LandedCostVoucherInput{
+			VoucherDate: "2026-03-10", VendorCode: "V001", AllocationMethod: "WEIGHT",
+			PONumbers: []string{poNumber}, Charges: freight,
+		}, ledger)
+		if err == nil {
+			t.Error("expected error allocating by weight with no product weights, got nil")
+		}
+	})
+
+	// 300 by value: P001 gets 100 (60 on hand, 40 shipped), P003 gets 200 (all on hand).
+	voucher, err := lcSvc.CreateVoucher(ctx, companyCode, core.LandedCostVoucherInput{
+		VoucherDate: "2026-03-10", VendorCode: "V001", AllocationMethod: "value",
+		PONumbers: []string{poNumber}, Charges: freight,
+	}, ledger)
+	if err != nil {
+		t.Fatalf("CreateVoucher: %v", err)
+	}
+
+	t.Run("Voucher_SplitsCapitalizedAndExpensed", func(t *testing.T) {
+		if voucher.VoucherNumber == nil {
+			t.Error("expected an LC voucher number after posting")
+		}
+		if !voucher.CapitalizedAmount.Equal(decimal.NewFromInt(260)) || !voucher.ExpensedAmount.Equal(decimal.NewFromInt(40)) {
+			t.Errorf("expected 260 capitalised / 40 expensed, got %s / %s", voucher.CapitalizedAmount, voucher.ExpensedAmount)
+		}
+		if len(voucher.Allocations) != 2 {
+			t.Fatalf("expected 2 allocations, got %d", len(voucher.Allocations))
+		}
+	})
+
+	t.Run("UnitCost_RaisedOnRemainingStock", func(t *testing.T) {
+		stockLevels, err := invSvc.GetStockLevels(ctx, companyCode)
+		if err != nil {
+			t.Fatalf("GetStockLevels: %v", err)
+		}
+		want := map[string]decimal.Decimal{"P001": decimal.NewFromInt(110), "P003": decimal.NewFromInt(440)}
+		for _, sl := range stockLevels {
+			if w, ok := want[sl.ProductCode]; ok && !sl.UnitCost.Equal(w) {
+				t.Errorf("%s: expected unit cost %s, got %s", sl.ProductCode, w, sl.UnitCost)
+			}
+		}
+	})
+
+	t.Run("Valuation_IncludesLandedCost", func(t *testing.T) {
+		report, err := reportSvc.GetInventoryValuation(ctx, companyCode, "2026-03-31")
+		if err != nil {
+			t.Fatalf("GetInventoryValuation: %v", err)
+		}
+		// 6 × 110 + 5 × 440
+		if !report.TotalValue.Equal(decimal.NewFromInt(2860)) {
+			t.Errorf("expected stock value 2860, got %s", report.TotalValue)
+		}
+	})
+
+	t.Run("Weight_AllocatesByUnitWeight", func(t *testing.T) {
+		if err := lcSvc.SetProductWeight(ctx, companyCode, "P001", decimal.NewFromInt(1)); err != nil {
+			t.Fatalf("SetProductWeight: %v", err)
+		}
+		if err := lcSvc.SetProductWeight(ctx, companyCode, "P003", decimal.NewFromInt(4)); err != nil {
+			t.Fatalf("SetProductWeight: %v", err)
+		}
+		// Weights 10 × 1 and 5 × 4: one third to P001, two thirds to P003.
+		v, err := lcSvc.CreateVoucher(ctx, companyCode, core.LandedCostVoucherInput{
+			VoucherDate: "2026-03-12", CreditAccountCode: "2000", AllocationMethod: "WEIGHT",
+			PONumbers: []string{poNumber},
+			Charges:   []core.LandedCostChargeInput{{Description: "Customs duty", Amount: decimal.NewFromInt(90)}},
+		}, ledger)
+		if err != nil {
+			t.Fatalf("CreateVoucher: %v", err)
+		}
+		got := map[string]string{}
+		for _, a := range v.Allocations {
+			got[a.ProductCode] = a.AllocatedAmount.StringFixed(2)
+		}
+		if got["P001"] != "30.00" || got["P003"] != "60.00" {
+			t.Errorf("unexpected weight allocation: %v", got)
+		}
+	})
+}
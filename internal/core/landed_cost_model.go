@@ -0,0 +1,71 @@
+package core
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LandedCostVoucher books freight, duty, insurance and similar charges onto goods
+// receipts. TotalAmount = CapitalizedAmount (raised onto stock still on hand)
+// + ExpensedAmount (share of stock already shipped, booked to COGS).
+type LandedCostVoucher struct {
+	ID                int
+	CompanyID         int
+	VoucherNumber     *string // LC document number; assigned on posting
+	VendorCode        *string
+	VendorName        *string
+	VoucherDate       string // YYYY-MM-DD
+	AllocationMethod  string // VALUE | QUANTITY | WEIGHT
+	CreditAccountCode string
+	TotalAmount       decimal.Decimal
+	CapitalizedAmount decimal.Decimal
+	ExpensedAmount    decimal.Decimal
+	Notes             *string
+	CreatedAt         time.Time
+	Charges           []LandedCostCharge
+	Allocations       []LandedCostAllocation
+}
+
+// LandedCostCharge is one cost component on a voucher (e.g. "Sea freight").
+type LandedCostCharge struct {
+	ID          int
+	Description string
+	Amount      decimal.Decimal
+}
+
+// LandedCostAllocation is the share of a voucher assigned to one receipt movement.
+// ReceiptQty is in the product's stock unit.
+type LandedCostAllocation struct {
+	MovementID        int
+	PONumber          string
+	ProductCode       string
+	WarehouseCode     string
+	ReceiptDate       string
+	ReceiptQty        decimal.Decimal
+	Basis             decimal.Decimal
+	AllocatedAmount   decimal.Decimal
+	CapitalizedAmount decimal.Decimal
+	ExpensedAmount    decimal.Decimal
+}
+
+// LandedCostChargeInput is one charge on a new voucher.
+type LandedCostChargeInput struct {
+	Description string
+	Amount      decimal.Decimal
+}
+
+// LandedCostVoucherInput holds the fields required to post a landed cost voucher.
+// Receipts are selected by PO number (every receipt against the PO) and/or by
+// receipt movement ID; at least one receipt must result.
+// An empty CreditAccountCode resolves to the vendor's AP account.
+type LandedCostVoucherInput struct {
+	VoucherDate        string // YYYY-MM-DD
+	VendorCode         string // optional
+	CreditAccountCode  string
+	AllocationMethod   string // VALUE | QUANTITY | WEIGHT; defaults to VALUE
+	PONumbers          []string
+	ReceiptMovementIDs []int
+	Charges            []LandedCostChargeInput
+	Notes              string
+}
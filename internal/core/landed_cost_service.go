@@ -0,0 +1,520 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// LandedCostService posts landed cost vouchers: freight, duty, insurance and similar
+// charges spread over goods receipts and added to the weighted average cost of the
+// stock they brought in.
+type LandedCostService interface {
+	// CreateVoucher allocates the voucher's charges over the selected receipts and, in one
+	// transaction, raises the unit cost of the stock still on hand, expenses the share
+	// already shipped to COGS, and posts DR Inventory / DR COGS / CR creditAccount.
+	CreateVoucher(ctx context.Context, companyCode string, input LandedCostVoucherInput, ledger *Ledger) (*LandedCostVoucher, error)
+
+	// GetVouchers returns all landed cost vouchers for a company, newest first (headers only).
+	GetVouchers(ctx context.Context, companyCode string) ([]LandedCostVoucher, error)
+
+	// GetVoucher returns one voucher with its charges and allocations.
+	GetVoucher(ctx context.Context, companyCode string, voucherID int) (*LandedCostVoucher, error)
+
+	// SetProductWeight sets the weight of one stock unit, used by WEIGHT allocation.
+	SetProductWeight(ctx context.Context, companyCode, productCode string, unitWeight decimal.Decimal) error
+}
+
+type landedCostService struct {
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+}
+
+// NewLandedCostService constructs a LandedCostService backed by PostgreSQL.
+func NewLandedCostService(pool *pgxpool.Pool, ruleEngine RuleEngine) LandedCostService {
+	return &landedCostService{pool: pool, ruleEngine: ruleEngine}
+}
+
+// landedReceipt is one goods receipt movement a voucher is spread over.
+type landedReceipt struct {
+	movementID int
+	itemID     int
+	poLineID   int
+	totalCost  decimal.Decimal
+	unitWeight decimal.Decimal
+	alloc      LandedCostAllocation
+}
+
+// ── CreateVoucher ─────────────────────────────────────────────────────────────
+
+func (s *landedCostService) CreateVoucher(ctx context.Context, companyCode string, input LandedCostVoucherInput, ledger *Ledger) (*LandedCostVoucher, error) {
+	method := strings.ToUpper(strings.TrimSpace(input.AllocationMethod))
+	if method == "" {
+		method = "VALUE"
+	}
+	if method != "VALUE" && method != "QUANTITY" && method != "WEIGHT" {
+		return nil, fmt.Errorf("invalid allocation method %q: must be VALUE, QUANTITY or WEIGHT", input.AllocationMethod)
+	}
+	if _, err := time.Parse("2006-01-02", input.VoucherDate); err != nil {
+		return nil, fmt.Errorf("invalid voucher date %q (expected YYYY-MM-DD)", input.VoucherDate)
+	}
+	if len(input.Charges) == 0 {
+		return nil, fmt.Errorf("landed cost voucher must have at least one charge")
+	}
+	total := decimal.Zero
+	for i, c := range input.Charges {
+		if strings.TrimSpace(c.Description) == "" {
+			return nil, fmt.Errorf("charge %d: description is required", i+1)
+		}
+		if !c.Amount.IsPositive() {
+			return nil, fmt.Errorf("charge %d (%s): amount must be positive", i+1, c.Description)
+		}
+		total = total.Add(c.Amount.Round(2))
+	}
+	if len(input.PONumbers) == 0 && len(input.ReceiptMovementIDs) == 0 {
+		return nil, fmt.Errorf("at least one purchase order or goods receipt is required")
+	}
+
+	var companyID int
+	var baseCurrency string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, base_currency FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID, &baseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("company %s not found", companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+
+	var vendorID *int
+	creditAccount := input.CreditAccountCode
+	if input.VendorCode != "" {
+		var id int
+		var apAccount string
+		if err := s.pool.QueryRow(ctx,
+			"SELECT id, COALESCE(ap_account_code, '2000') FROM vendors WHERE company_id = $1 AND code = $2",
+			companyID, input.VendorCode,
+		).Scan(&id, &apAccount); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("vendor %s not found", input.VendorCode)
+			}
+			return nil, fmt.Errorf("resolve vendor: %w", err)
+		}
+		vendorID = &id
+		if creditAccount == "" {
+			creditAccount = apAccount
+		}
+	}
+	if creditAccount == "" {
+		return nil, fmt.Errorf("credit account is required when no vendor is given")
+	}
+
+	inventoryAccount, err := s.ruleEngine.ResolveAccount(ctx, companyID, "INVENTORY")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve INVENTORY account: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	receipts, err := s.fetchReceiptsTx(ctx, tx, companyID, input.PONumbers, input.ReceiptMovementIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := allocateLandedCost(receipts, method, total); err != nil {
+		return nil, err
+	}
+
+	// Split each item's allocation between stock still on hand and stock already shipped.
+	// Receipts are assumed to be consumed no earlier than the rest of the item's stock, so
+	// up to qty_on_hand of the receipted quantity is still in the warehouse.
+	capitalized, expensed := decimal.Zero, decimal.Zero
+	itemOrder := []int{}
+	byItem := map[int][]*landedReceipt{}
+	for i := range receipts {
+		r := &receipts[i]
+		if _, ok := byItem[r.itemID]; !ok {
+			itemOrder = append(itemOrder, r.itemID)
+		}
+		byItem[r.itemID] = append(byItem[r.itemID], r)
+	}
+	for _, itemID := range itemOrder {
+		var onHand, unitCost decimal.Decimal
+		if err := tx.QueryRow(ctx,
+			"SELECT qty_on_hand, unit_cost FROM inventory_items WHERE id = $1 FOR UPDATE", itemID,
+		).Scan(&onHand, &unitCost); err != nil {
+			return nil, fmt.Errorf("failed to lock inventory item: %w", err)
+		}
+
+		receiptQty := decimal.Zero
+		for _, r := range byItem[itemID] {
+			receiptQty = receiptQty.Add(r.alloc.ReceiptQty)
+		}
+		ratio := decimal.Zero
+		if onHand.IsPositive() && receiptQty.IsPositive() {
+			ratio = decimal.Min(onHand, receiptQty).Div(receiptQty)
+		}
+
+		itemCapitalized := decimal.Zero
+		for _, r := range byItem[itemID] {
+			r.alloc.CapitalizedAmount = r.alloc.AllocatedAmount.Mul(ratio).Round(2)
+			r.alloc.ExpensedAmount = r.alloc.AllocatedAmount.Sub(r.alloc.CapitalizedAmount)
+			itemCapitalized = itemCapitalized.Add(r.alloc.CapitalizedAmount)
+			expensed = expensed.Add(r.alloc.ExpensedAmount)
+		}
+		capitalized = capitalized.Add(itemCapitalized)
+
+		if itemCapitalized.IsPositive() {
+			newCost := onHand.Mul(unitCost).Add(itemCapitalized).Div(onHand)
+			if _, err := tx.Exec(ctx,
+				"UPDATE inventory_items SET unit_cost = $1, updated_at = NOW() WHERE id = $2",
+				newCost, itemID,
+			); err != nil {
+				return nil, fmt.Errorf("failed to update inventory item cost: %w", err)
+			}
+		}
+	}
+
+	var notes *string
+	if input.Notes != "" {
+		notes = &input.Notes
+	}
+	var voucherID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO landed_cost_vouchers
+		    (company_id, vendor_id, voucher_date, allocation_method, credit_account_code,
+		     total_amount, capitalized_amount, expensed_amount, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		companyID, vendorID, input.VoucherDate, method, creditAccount,
+		total, capitalized, expensed, notes,
+	).Scan(&voucherID); err != nil {
+		return nil, fmt.Errorf("insert landed cost voucher: %w", err)
+	}
+
+	for _, c := range input.Charges {
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO landed_cost_charges (voucher_id, description, amount) VALUES ($1, $2, $3)",
+			voucherID, strings.TrimSpace(c.Description), c.Amount.Round(2),
+		); err != nil {
+			return nil, fmt.Errorf("insert landed cost charge: %w", err)
+		}
+	}
+
+	for _, r := range receipts {
+		a := r.alloc
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO landed_cost_allocations
+			    (voucher_id, movement_id, basis, allocated_amount, capitalized_amount, expensed_amount)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			voucherID, r.movementID, a.Basis, a.AllocatedAmount, a.CapitalizedAmount, a.ExpensedAmount,
+		); err != nil {
+			return nil, fmt.Errorf("insert landed cost allocation: %w", err)
+		}
+		if !a.CapitalizedAmount.IsPositive() {
+			continue
+		}
+		// Value-only movement: keeps movement-based valuation equal to the INVENTORY balance.
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO inventory_movements
+			    (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id)
+			VALUES ($1, $2, 'LANDED_COST', 0, 0, $3, $4, $5, $6)`,
+			companyID, r.itemID, a.CapitalizedAmount, input.VoucherDate,
+			fmt.Sprintf("Landed cost on receipt movement %d (voucher ID %d)", r.movementID, voucherID),
+			r.poLineID,
+		); err != nil {
+			return nil, fmt.Errorf("insert landed cost movement: %w", err)
+		}
+	}
+
+	lines := []ProposalLine{}
+	if capitalized.IsPositive() {
+		lines = append(lines, ProposalLine{AccountCode: inventoryAccount, IsDebit: true, Amount: capitalized.StringFixed(2)})
+	}
+	if expensed.IsPositive() {
+		cogsAccount, err := s.ruleEngine.ResolveAccount(ctx, companyID, "COGS")
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve COGS account: %w", err)
+		}
+		lines = append(lines, ProposalLine{AccountCode: cogsAccount, IsDebit: true, Amount: expensed.StringFixed(2)})
+	}
+	lines = append(lines, ProposalLine{AccountCode: creditAccount, IsDebit: false, Amount: total.StringFixed(2)})
+
+	idempotencyKey := fmt.Sprintf("landed-cost-voucher-%d", voucherID)
+	proposal := Proposal{
+		DocumentTypeCode:    "LC",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      idempotencyKey,
+		TransactionCurrency: baseCurrency,
+		ExchangeRate:        "1",
+		Summary:             fmt.Sprintf("Landed cost voucher: %s over %d receipt(s)", total.StringFixed(2), len(receipts)),
+		PostingDate:         input.VoucherDate,
+		DocumentDate:        input.VoucherDate,
+		Confidence:          1.0,
+		Reasoning: fmt.Sprintf("Landed cost allocated by %s: %s capitalised onto stock on hand, %s expensed to COGS for stock already shipped.",
+			strings.ToLower(method), capitalized.StringFixed(2), expensed.StringFixed(2)),
+		Lines: lines,
+	}
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return nil, fmt.Errorf("failed to book landed cost journal entry: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE landed_cost_vouchers
+		SET voucher_number = (SELECT reference_id FROM journal_entries WHERE idempotency_key = $1)
+		WHERE id = $2`,
+		idempotencyKey, voucherID,
+	); err != nil {
+		return nil, fmt.Errorf("set voucher number: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit landed cost voucher: %w", err)
+	}
+
+	return s.GetVoucher(ctx, companyCode, voucherID)
+}
+
+// fetchReceiptsTx loads the PO-linked RECEIPT movements selected by PO number or movement ID.
+func (s *landedCostService) fetchReceiptsTx(ctx context.Context, tx pgx.Tx, companyID int, poNumbers []string, movementIDs []int) ([]landedReceipt, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT im.id, im.inventory_item_id, im.po_line_id, po.po_number, p.code, w.code,
+		       im.movement_date::text, im.quantity, im.total_cost, p.unit_weight
+		FROM inventory_movements im
+		JOIN inventory_items ii            ON ii.id  = im.inventory_item_id
+		JOIN products p                    ON p.id   = ii.product_id
+		JOIN warehouses w                  ON w.id   = ii.warehouse_id
+		JOIN purchase_order_lines pol      ON pol.id = im.po_line_id
+		JOIN purchase_orders po            ON po.id  = pol.order_id
+		WHERE im.company_id = $1
+		  AND im.movement_type = 'RECEIPT'
+		  AND im.quantity > 0
+		  AND (im.id = ANY($2) OR po.po_number = ANY($3))
+		ORDER BY im.id`,
+		companyID, movementIDs, poNumbers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query goods receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var receipts []landedReceipt
+	foundMovements := map[int]bool{}
+	foundPOs := map[string]bool{}
+	for rows.Next() {
+		var r landedReceipt
+		if err := rows.Scan(&r.movementID, &r.itemID, &r.poLineID, &r.alloc.PONumber, &r.alloc.ProductCode,
+			&r.alloc.WarehouseCode, &r.alloc.ReceiptDate, &r.alloc.ReceiptQty, &r.totalCost, &r.unitWeight); err != nil {
+			return nil, fmt.Errorf("scan goods receipt: %w", err)
+		}
+		r.alloc.MovementID = r.movementID
+		foundMovements[r.movementID] = true
+		foundPOs[r.alloc.PONumber] = true
+		receipts = append(receipts, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate goods receipts: %w", err)
+	}
+
+	for _, id := range movementIDs {
+		if !foundMovements[id] {
+			return nil, fmt.Errorf("goods receipt movement %d not found (must be a receipt against a purchase order)", id)
+		}
+	}
+	for _, n := range poNumbers {
+		if !foundPOs[n] {
+			return nil, fmt.Errorf("no goods receipts found for purchase order %s", n)
+		}
+	}
+	if len(receipts) == 0 {
+		return nil, fmt.Errorf("no goods receipts selected")
+	}
+	return receipts, nil
+}
+
+// allocateLandedCost spreads total over receipts in proportion to the method's basis.
+// Amounts are rounded to 2 decimals; the last receipt absorbs the rounding difference.
+func allocateLandedCost(receipts []landedReceipt, method string, total decimal.Decimal) error {
+	totalBasis := decimal.Zero
+	for i := range receipts {
+		r := &receipts[i]
+		a := &r.alloc
+		switch method {
+		case "VALUE":
+			a.Basis = r.totalCost
+		case "QUANTITY":
+			a.Basis = a.ReceiptQty
+		case "WEIGHT":
+			a.Basis = a.ReceiptQty.Mul(r.unitWeight)
+		}
+		totalBasis = totalBasis.Add(a.Basis)
+	}
+	if !totalBasis.IsPositive() {
+		if method == "WEIGHT" {
+			return fmt.Errorf("cannot allocate by weight: the received products have no unit weight set")
+		}
+		return fmt.Errorf("cannot allocate by %s: the selected receipts have a zero basis", strings.ToLower(method))
+	}
+
+	remaining := total
+	for i := range receipts {
+		a := &receipts[i].alloc
+		if i == len(receipts)-1 {
+			a.AllocatedAmount = remaining
+			break
+		}
+		a.AllocatedAmount = total.Mul(a.Basis).Div(totalBasis).Round(2)
+		remaining = remaining.Sub(a.AllocatedAmount)
+	}
+	return nil
+}
+
+// ── Queries ───────────────────────────────────────────────────────────────────
+
+const landedCostVoucherSelect = `
+	SELECT lcv.id, lcv.company_id, lcv.voucher_number, v.code, v.name, lcv.voucher_date::text,
+	       lcv.allocation_method, lcv.credit_account_code, lcv.total_amount,
+	       lcv.capitalized_amount, lcv.expensed_amount, lcv.notes, lcv.created_at
+	FROM landed_cost_vouchers lcv
+	LEFT JOIN vendors v ON v.id = lcv.vendor_id`
+
+func scanLandedCostVoucher(row pgx.Row) (LandedCostVoucher, error) {
+	var v LandedCostVoucher
+	err := row.Scan(&v.ID, &v.CompanyID, &v.VoucherNumber, &v.VendorCode, &v.VendorName, &v.VoucherDate,
+		&v.AllocationMethod, &v.CreditAccountCode, &v.TotalAmount,
+		&v.CapitalizedAmount, &v.ExpensedAmount, &v.Notes, &v.CreatedAt)
+	return v, err
+}
+
+func (s *landedCostService) GetVouchers(ctx context.Context, companyCode string) ([]LandedCostVoucher, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, landedCostVoucherSelect+`
+		WHERE lcv.company_id = $1
+		ORDER BY lcv.voucher_date DESC, lcv.id DESC`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("query landed cost vouchers: %w", err)
+	}
+	defer rows.Close()
+
+	var vouchers []LandedCostVoucher
+	for rows.Next() {
+		v, err := scanLandedCostVoucher(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan landed cost voucher: %w", err)
+		}
+		vouchers = append(vouchers, v)
+	}
+	return vouchers, rows.Err()
+}
+
+func (s *landedCostService) GetVoucher(ctx context.Context, companyCode string, voucherID int) (*LandedCostVoucher, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := scanLandedCostVoucher(s.pool.QueryRow(ctx, landedCostVoucherSelect+`
+		WHERE lcv.company_id = $1 AND lcv.id = $2`, companyID, voucherID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("landed cost voucher %d not found", voucherID)
+		}
+		return nil, fmt.Errorf("fetch landed cost voucher: %w", err)
+	}
+
+	crows, err := s.pool.Query(ctx,
+		"SELECT id, description, amount FROM landed_cost_charges WHERE voucher_id = $1 ORDER BY id", voucherID)
+	if err != nil {
+		return nil, fmt.Errorf("query landed cost charges: %w", err)
+	}
+	for crows.Next() {
+		var c LandedCostCharge
+		if err := crows.Scan(&c.ID, &c.Description, &c.Amount); err != nil {
+			crows.Close()
+			return nil, fmt.Errorf("scan landed cost charge: %w", err)
+		}
+		v.Charges = append(v.Charges, c)
+	}
+	crows.Close()
+	if err := crows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate landed cost charges: %w", err)
+	}
+
+	arows, err := s.pool.Query(ctx, `
+		SELECT a.movement_id, COALESCE(po.po_number, ''), p.code, w.code, im.movement_date::text,
+		       im.quantity, a.basis, a.allocated_amount, a.capitalized_amount, a.expensed_amount
+		FROM landed_cost_allocations a
+		JOIN inventory_movements im        ON im.id  = a.movement_id
+		JOIN inventory_items ii            ON ii.id  = im.inventory_item_id
+		JOIN products p                    ON p.id   = ii.product_id
+		JOIN warehouses w                  ON w.id   = ii.warehouse_id
+		LEFT JOIN purchase_order_lines pol ON pol.id = im.po_line_id
+		LEFT JOIN purchase_orders po       ON po.id  = pol.order_id
+		WHERE a.voucher_id = $1
+		ORDER BY a.id`, voucherID)
+	if err != nil {
+		return nil, fmt.Errorf("query landed cost allocations: %w", err)
+	}
+	defer arows.Close()
+	for arows.Next() {
+		var a LandedCostAllocation
+		if err := arows.Scan(&a.MovementID, &a.PONumber, &a.ProductCode, &a.WarehouseCode, &a.ReceiptDate,
+			&a.ReceiptQty, &a.Basis, &a.AllocatedAmount, &a.CapitalizedAmount, &a.ExpensedAmount); err != nil {
+			return nil, fmt.Errorf("scan landed cost allocation: %w", err)
+		}
+		v.Allocations = append(v.Allocations, a)
+	}
+	if err := arows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate landed cost allocations: %w", err)
+	}
+	return &v, nil
+}
+
+// ── Product weight ────────────────────────────────────────────────────────────
+
+func (s *landedCostService) SetProductWeight(ctx context.Context, companyCode, productCode string, unitWeight decimal.Decimal) error {
+	if unitWeight.IsNegative() {
+		return fmt.Errorf("unit weight must not be negative, got %s", unitWeight)
+	}
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	tag, err := s.pool.Exec(ctx,
+		"UPDATE products SET unit_weight = $1 WHERE company_id = $2 AND code = $3",
+		unitWeight, companyID, productCode,
+	)
+	if err != nil {
+		return fmt.Errorf("update product weight: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("product %q not found", productCode)
+	}
+	return nil
+}
+
+func (s *landedCostService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
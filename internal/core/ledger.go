@@ -118,17 +118,17 @@ func (l *Ledger) executeCore(ctx context.Context, tx pgx.Tx, proposal Proposal,
 	var entryID int
 	if proposal.IdempotencyKey != "" {
 		err = tx.QueryRow(ctx, `
-			INSERT INTO journal_entries (company_id, narration, posting_date, document_date, reasoning, reference_type, reference_id, idempotency_key, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+			INSERT INTO journal_entries (company_id, narration, posting_date, document_date, reasoning, reference_type, reference_id, idempotency_key, created_by_user_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), NOW())
 			ON CONFLICT (idempotency_key) DO NOTHING
 			RETURNING id
-		`, companyID, proposal.Summary, proposal.PostingDate, proposal.DocumentDate, proposal.Reasoning, referenceType, documentNumber, proposal.IdempotencyKey).Scan(&entryID)
+		`, companyID, proposal.Summary, proposal.PostingDate, proposal.DocumentDate, proposal.Reasoning, referenceType, documentNumber, proposal.IdempotencyKey, proposal.CreatedByUserID).Scan(&entryID)
 	} else {
 		err = tx.QueryRow(ctx, `
-			INSERT INTO journal_entries (company_id, narration, posting_date, document_date, reasoning, reference_type, reference_id, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			INSERT INTO journal_entries (company_id, narration, posting_date, document_date, reasoning, reference_type, reference_id, created_by_user_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0), NOW())
 			RETURNING id
-		`, companyID, proposal.Summary, proposal.PostingDate, proposal.DocumentDate, proposal.Reasoning, referenceType, documentNumber).Scan(&entryID)
+		`, companyID, proposal.Summary, proposal.PostingDate, proposal.DocumentDate, proposal.Reasoning, referenceType, documentNumber, proposal.CreatedByUserID).Scan(&entryID)
 	}
 
 	if err != nil {
@@ -144,13 +144,29 @@ func (l *Ledger) executeCore(ctx context.Context, tx pgx.Tx, proposal Proposal,
 
 	for _, line := range proposal.Lines {
 		var accountID int
-		err := tx.QueryRow(ctx, "SELECT id FROM accounts WHERE company_id = $1 AND code = $2", companyID, line.AccountCode).Scan(&accountID)
+		var isGroup, isActive bool
+		var controls AccountControls
+		err := tx.QueryRow(ctx, `
+			SELECT id, is_group, is_active, posting_blocked, manual_posting_blocked, narration_required
+			FROM accounts WHERE company_id = $1 AND code = $2`, companyID, line.AccountCode,
+		).Scan(&accountID, &isGroup, &isActive, &controls.PostingBlocked, &controls.ManualPostingBlocked, &controls.NarrationRequired)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return fmt.Errorf("account code %s not found for company %s", line.AccountCode, proposal.CompanyCode)
 			}
 			return fmt.Errorf("failed to fetch account ID for code %s: %w", line.AccountCode, err)
 		}
+		// Group accounts only roll up their sub-accounts; postings go to leaf accounts.
+		if isGroup {
+			return fmt.Errorf("account %s is a group account: post to one of its sub-accounts", line.AccountCode)
+		}
+		if err := CheckAccountPosting(line.AccountCode, isActive, controls, proposal); err != nil {
+			return err
+		}
+		dims, err := resolveLineDimensionsTx(ctx, tx, companyID, accountID, line.AccountCode, line.Dimensions)
+		if err != nil {
+			return err
+		}
 
 		amt, _ := decimal.NewFromString(line.Amount)
 		baseAmt := amt.Mul(rate)
@@ -164,13 +180,18 @@ func (l *Ledger) executeCore(ctx context.Context, tx pgx.Tx, proposal Proposal,
 			creditBase = baseAmt
 		}
 
-		_, err = tx.Exec(ctx, `
+		var lineID int
+		err = tx.QueryRow(ctx, `
 			INSERT INTO journal_lines (entry_id, account_id, transaction_currency, exchange_rate, amount_transaction, debit_base, credit_base)
 			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, entryID, accountID, proposal.TransactionCurrency, proposal.ExchangeRate, line.Amount, debitBase, creditBase)
+			RETURNING id
+		`, entryID, accountID, proposal.TransactionCurrency, proposal.ExchangeRate, line.Amount, debitBase, creditBase).Scan(&lineID)
 		if err != nil {
 			return fmt.Errorf("failed to insert journal line: %w", err)
 		}
+		if err := insertLineDimensionsTx(ctx, tx, lineID, dims); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -217,6 +238,9 @@ func (l *Ledger) GetBalances(ctx context.Context, companyCode string) ([]Account
 	return balances, nil
 }
 
+// Reverse posts a compensating entry for entryID. A sales invoice entry whose invoice has a
+// live e-invoice IRN cannot be reversed here: EInvoiceService.CancelEInvoice cancels the IRN
+// and reverses the entry together.
 func (l *Ledger) Reverse(ctx context.Context, entryID int, reasoning string) error {
 	tx, err := l.pool.Begin(ctx)
 	if err != nil {
@@ -224,9 +248,35 @@ func (l *Ledger) Reverse(ctx context.Context, entryID int, reasoning string) err
 	}
 	defer tx.Rollback(ctx)
 
+	var invoiceNumber, irn string
+	err = tx.QueryRow(ctx, `
+		SELECT d.document_number, ei.irn
+		FROM journal_entries je
+		JOIN documents d    ON d.company_id = je.company_id AND d.document_number = je.reference_id
+		JOIN einvoices ei   ON ei.invoice_document_id = d.id AND ei.status = 'GENERATED'
+		WHERE je.id = $1 AND je.reference_type = 'DOCUMENT'`, entryID,
+	).Scan(&invoiceNumber, &irn)
+	if err == nil {
+		return fmt.Errorf("entry %d is sales invoice %s with e-invoice IRN %s: cancel the e-invoice instead", entryID, invoiceNumber, irn)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to check e-invoice for entry %d: %w", entryID, err)
+	}
+
+	if err := l.reverseInTx(ctx, tx, entryID, reasoning); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reversal: %w", err)
+	}
+	return nil
+}
+
+// reverseInTx inserts the compensating entry for entryID inside tx.
+func (l *Ledger) reverseInTx(ctx context.Context, tx pgx.Tx, entryID int, reasoning string) error {
 	var narration string
 	var companyID int
-	err = tx.QueryRow(ctx, "SELECT company_id, narration FROM journal_entries WHERE id = $1", entryID).Scan(&companyID, &narration)
+	err := tx.QueryRow(ctx, "SELECT company_id, narration FROM journal_entries WHERE id = $1", entryID).Scan(&companyID, &narration)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return fmt.Errorf("entry %d not found", entryID)
@@ -256,13 +306,14 @@ func (l *Ledger) Reverse(ctx context.Context, entryID int, reasoning string) err
 		return fmt.Errorf("failed to insert reversal entry: %w", err)
 	}
 
-	rows, err := tx.Query(ctx, "SELECT account_id, transaction_currency, exchange_rate, amount_transaction, debit_base, credit_base FROM journal_lines WHERE entry_id = $1", entryID)
+	rows, err := tx.Query(ctx, "SELECT id, account_id, transaction_currency, exchange_rate, amount_transaction, debit_base, credit_base FROM journal_lines WHERE entry_id = $1", entryID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch lines for entry %d: %w", entryID, err)
 	}
 	defer rows.Close()
 
 	type lineData struct {
+		id                  int
 		accountID           int
 		transactionCurrency string
 		exchangeRate        decimal.Decimal
@@ -274,7 +325,7 @@ func (l *Ledger) Reverse(ctx context.Context, entryID int, reasoning string) err
 
 	for rows.Next() {
 		var l lineData
-		if err := rows.Scan(&l.accountID, &l.transactionCurrency, &l.exchangeRate, &l.amountTransaction, &l.debitBase, &l.creditBase); err != nil {
+		if err := rows.Scan(&l.id, &l.accountID, &l.transactionCurrency, &l.exchangeRate, &l.amountTransaction, &l.debitBase, &l.creditBase); err != nil {
 			return fmt.Errorf("failed to scan line: %w", err)
 		}
 		lines = append(lines, l)
@@ -285,17 +336,22 @@ func (l *Ledger) Reverse(ctx context.Context, entryID int, reasoning string) err
 
 	for _, line := range lines {
 		// Invert debits and credits for the reversal
-		_, err := tx.Exec(ctx, `
+		var newLineID int
+		err := tx.QueryRow(ctx, `
 			INSERT INTO journal_lines (entry_id, account_id, transaction_currency, exchange_rate, amount_transaction, debit_base, credit_base)
 			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, newEntryID, line.accountID, line.transactionCurrency, line.exchangeRate.String(), line.amountTransaction, line.creditBase, line.debitBase)
+			RETURNING id
+		`, newEntryID, line.accountID, line.transactionCurrency, line.exchangeRate.String(), line.amountTransaction, line.creditBase, line.debitBase).Scan(&newLineID)
 		if err != nil {
 			return fmt.Errorf("failed to insert inverted line: %w", err)
 		}
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit reversal: %w", err)
+		// The reversal carries the original line's dimensions, so it nets out per dimension.
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO journal_line_dimensions (journal_line_id, dimension_id, value_id)
+			SELECT $1, dimension_id, value_id FROM journal_line_dimensions WHERE journal_line_id = $2
+		`, newLineID, line.id); err != nil {
+			return fmt.Errorf("failed to copy line dimensions: %w", err)
+		}
 	}
 
 	return nil
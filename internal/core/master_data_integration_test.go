@@ -0,0 +1,176 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestVendor_UpdateDeactivateMerge(t *testing.T) {
+	pool, poService, _, vendorID, ctx := setupPurchaseOrderTestDB(t)
+	defer pool.Close()
+
+	vendorSvc := core.NewVendorService(pool)
+	lines := []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(1), UnitCost: decimal.NewFromInt(10)},
+	}
+	poDate := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	v, err := vendorSvc.GetVendorByCode(ctx, 1, "V001")
+	if err != nil {
+		t.Fatalf("GetVendorByCode: %v", err)
+	}
+	updated, err := vendorSvc.UpdateVendor(ctx, 1, "V001", v.Version, core.VendorInput{
+		Name: "Test Supplier Pvt Ltd", Email: "ap@supplier.test", PaymentTermsDays: 45, APAccountCode: "2000",
+	})
+	if err != nil {
+		t.Fatalf("UpdateVendor: %v", err)
+	}
+	if updated.Name != "Test Supplier Pvt Ltd" || updated.PaymentTermsDays != 45 || updated.Version != v.Version+1 {
+		t.Errorf("expected renamed vendor on 45 days at version %d, got %q %d days version %d",
+			v.Version+1, updated.Name, updated.PaymentTermsDays, updated.Version)
+	}
+
+	t.Run("StaleVersion_Conflicts", func(t *testing.T) {
+		_, err := vendorSvc.UpdateVendor(ctx, 1, "V001", v.Version, core.VendorInput{Name: "Lost update"})
+		if !errors.Is(err, core.ErrVersionConflict) {
+			t.Errorf("expected ErrVersionConflict for a stale version, got %v", err)
+		}
+	})
+
+	// A duplicate of V001 with one PO; merging moves the PO and deactivates the duplicate.
+	dup, err := vendorSvc.CreateVendor(ctx, 1, core.VendorInput{Code: "V001-DUP", Name: "Test Supplier (dup)", APAccountCode: "2000"})
+	if err != nil {
+		t.Fatalf("CreateVendor: %v", err)
+	}
+	po, err := poService.CreatePO(ctx, 1, dup.ID, "", decimal.Zero, poDate, lines, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+
+	t.Run("InactiveVendor_BlocksPO", func(t *testing.T) {
+		if _, err := vendorSvc.SetVendorActive(ctx, 1, "V001-DUP", false); err != nil {
+			t.Fatalf("SetVendorActive(false): %v", err)
+		}
+		if _, err := poService.CreatePO(ctx, 1, dup.ID, "", decimal.Zero, poDate, lines, ""); err == nil {
+			t.Error("expected error creating a PO for an inactive vendor, got nil")
+		}
+		if _, err := vendorSvc.SetVendorActive(ctx, 1, "V001-DUP", true); err != nil {
+			t.Fatalf("SetVendorActive(true): %v", err)
+		}
+	})
+
+	result, err := vendorSvc.MergeVendors(ctx, 1, "V001-DUP", "V001")
+	if err != nil {
+		t.Fatalf("MergeVendors: %v", err)
+	}
+	if result.Repointed["purchase_orders"] != 1 {
+		t.Errorf("expected 1 purchase order repointed, got %v", result.Repointed)
+	}
+	moved, err := poService.GetPO(ctx, po.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	if moved.VendorID != vendorID {
+		t.Errorf("expected PO to belong to V001 after the merge, got vendor %d", moved.VendorID)
+	}
+	merged, err := vendorSvc.GetVendorByCode(ctx, 1, "V001-DUP")
+	if err != nil {
+		t.Fatalf("GetVendorByCode: %v", err)
+	}
+	if merged.IsActive || merged.MergedIntoID == nil || *merged.MergedIntoID != vendorID {
+		t.Errorf("expected duplicate to be inactive and merged into V001")
+	}
+
+	t.Run("MergedVendor_CannotReactivate", func(t *testing.T) {
+		if _, err := vendorSvc.SetVendorActive(ctx, 1, "V001-DUP", true); err == nil {
+			t.Error("expected error reactivating a merged vendor, got nil")
+		}
+	})
+}
+
+func TestCustomer_UpdateDeactivateMerge(t *testing.T) {
+	pool, orderSvc, _, _, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+
+	lines := []core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(1)}}
+
+	c, err := orderSvc.GetCustomer(ctx, "1000", "C002")
+	if err != nil {
+		t.Fatalf("GetCustomer: %v", err)
+	}
+	updated, err := orderSvc.UpdateCustomer(ctx, "1000", "C002", c.Version, core.CustomerInput{
+		Name: c.Name, Email: "ar@beta.in", CreditLimit: decimal.NewFromInt(75000), PaymentTermsDays: 30,
+	})
+	if err != nil {
+		t.Fatalf("UpdateCustomer: %v", err)
+	}
+	if updated.Email != "ar@beta.in" || !updated.CreditLimit.Equal(decimal.NewFromInt(75000)) {
+		t.Errorf("expected updated email and credit limit, got %q %s", updated.Email, updated.CreditLimit)
+	}
+
+	t.Run("StaleVersion_Conflicts", func(t *testing.T) {
+		_, err := orderSvc.UpdateCustomer(ctx, "1000", "C002", c.Version, core.CustomerInput{Name: "Lost update"})
+		if !errors.Is(err, core.ErrVersionConflict) {
+			t.Errorf("expected ErrVersionConflict for a stale version, got %v", err)
+		}
+	})
+
+	order, err := orderSvc.CreateOrder(ctx, "1000", "C002", "INR", decimal.NewFromInt(1), "2026-07-01", lines, "")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+
+	t.Run("InactiveCustomer_BlocksOrder", func(t *testing.T) {
+		if _, err := orderSvc.SetCustomerActive(ctx, "1000", "C002", false); err != nil {
+			t.Fatalf("SetCustomerActive(false): %v", err)
+		}
+		if _, err := orderSvc.CreateOrder(ctx, "1000", "C002", "INR", decimal.NewFromInt(1), "2026-07-01", lines, ""); err == nil {
+			t.Error("expected error creating an order for an inactive customer, got nil")
+		}
+		customers, err := orderSvc.GetCustomers(ctx, "1000")
+		if err != nil {
+			t.Fatalf("GetCustomers: %v", err)
+		}
+		for _, cu := range customers {
+			if cu.Code == "C002" {
+				t.Error("expected inactive customer to be left out of GetCustomers")
+			}
+		}
+		if _, err := orderSvc.SetCustomerActive(ctx, "1000", "C002", true); err != nil {
+			t.Fatalf("SetCustomerActive(true): %v", err)
+		}
+	})
+
+	t.Run("MergeIntoSelf_Fails", func(t *testing.T) {
+		if _, err := orderSvc.MergeCustomers(ctx, "1000", "C002", "C002"); err == nil {
+			t.Error("expected error merging a customer into itself, got nil")
+		}
+	})
+
+	result, err := orderSvc.MergeCustomers(ctx, "1000", "C002", "C001")
+	if err != nil {
+		t.Fatalf("MergeCustomers: %v", err)
+	}
+	if result.Repointed["sales_orders"] != 1 {
+		t.Errorf("expected 1 sales order repointed, got %v", result.Repointed)
+	}
+	moved, err := orderSvc.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if moved.CustomerCode != "C001" {
+		t.Errorf("expected order to belong to C001 after the merge, got %s", moved.CustomerCode)
+	}
+	merged, err := orderSvc.GetCustomer(ctx, "1000", "C002")
+	if err != nil {
+		t.Fatalf("GetCustomer: %v", err)
+	}
+	if merged.IsActive || merged.MergedIntoID == nil {
+		t.Errorf("expected C002 to be inactive and merged")
+	}
+}
@@ -1,6 +1,21 @@
 package core
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrVersionConflict is returned when an update names a master-data version that is no
+// longer current: another user changed the record after it was read.
+var ErrVersionConflict = errors.New("record was changed by another user; reload and try again")
+
+// MergeResult reports a master-data merge: the documents repointed from the duplicate to
+// the survivor, counted per table. The duplicate is left inactive.
+type MergeResult struct {
+	DuplicateCode string         `json:"duplicate_code"`
+	SurvivorCode  string         `json:"survivor_code"`
+	Repointed     map[string]int `json:"repointed"`
+}
 
 type AccountType string
 
@@ -12,12 +27,27 @@ const (
 	Expense   AccountType = "expense"
 )
 
+// Account is a chart of accounts entry. Group accounts have sub-accounts (ParentID points
+// to the group) and cannot be posted to; neither can inactive accounts.
 type Account struct {
 	ID        int         `json:"id"`
 	CompanyID int         `json:"company_id"`
 	Code      string      `json:"code"`
 	Name      string      `json:"name"`
 	Type      AccountType `json:"type"`
+	ParentID  *int        `json:"parent_id,omitempty"`
+	IsGroup   bool        `json:"is_group"`
+	IsActive  bool        `json:"is_active"`
+	AccountControls
+}
+
+// AccountControls are the posting controls on an account, enforced by the ledger on every
+// journal line. ManualPostingBlocked marks a control account (AR, AP, inventory) that only
+// document workflows may post to.
+type AccountControls struct {
+	PostingBlocked       bool `json:"posting_blocked"`
+	ManualPostingBlocked bool `json:"manual_posting_blocked"`
+	NarrationRequired    bool `json:"narration_required"`
 }
 
 type Company struct {
@@ -27,39 +57,50 @@ type Company struct {
 	BaseCurrency string `json:"base_currency"`
 }
 
+// JournalEntry is a posted entry. ReversedEntryID is the entry this one reverses;
+// ReversedByEntryID the entry that reverses this one. Document is the linked document
+// when ReferenceType is DOCUMENT.
 type JournalEntry struct {
-	ID              int           `json:"id"`
-	CompanyID       int           `json:"company_id"`
-	IdempotencyKey  string        `json:"idempotency_key,omitempty"`
-	PostingDate     time.Time     `json:"posting_date"`
-	DocumentDate    time.Time     `json:"document_date"`
-	CreatedAt       time.Time     `json:"created_at"`
-	Narration       string        `json:"narration"`
-	ReferenceType   *string       `json:"reference_type,omitempty"`
-	ReferenceID     *string       `json:"reference_id,omitempty"`
-	Reasoning       string        `json:"reasoning"`
-	ReversedEntryID *int          `json:"reversed_entry_id,omitempty"`
-	Lines           []JournalLine `json:"lines"`
+	ID                int           `json:"id"`
+	CompanyID         int           `json:"company_id"`
+	IdempotencyKey    string        `json:"idempotency_key,omitempty"`
+	PostingDate       time.Time     `json:"posting_date"`
+	DocumentDate      time.Time     `json:"document_date"`
+	CreatedAt         time.Time     `json:"created_at"`
+	CreatedBy         string        `json:"created_by,omitempty"`
+	Narration         string        `json:"narration"`
+	ReferenceType     *string       `json:"reference_type,omitempty"`
+	ReferenceID       *string       `json:"reference_id,omitempty"`
+	Reasoning         string        `json:"reasoning"`
+	ReversedEntryID   *int          `json:"reversed_entry_id,omitempty"`
+	ReversedByEntryID *int          `json:"reversed_by_entry_id,omitempty"`
+	Document          *Document     `json:"document,omitempty"`
+	Lines             []JournalLine `json:"lines"`
 }
 
 type JournalLine struct {
-	ID                  int    `json:"id"`
-	EntryID             int    `json:"entry_id"`
-	AccountID           int    `json:"account_id"`
-	TransactionCurrency string `json:"transaction_currency"`
-	ExchangeRate        string `json:"exchange_rate"`
-	AmountTransaction   string `json:"amount_transaction"`
-	DebitBase           string `json:"debit_base"`
-	CreditBase          string `json:"credit_base"`
+	ID                  int             `json:"id"`
+	EntryID             int             `json:"entry_id"`
+	AccountID           int             `json:"account_id"`
+	AccountCode         string          `json:"account_code"`
+	AccountName         string          `json:"account_name"`
+	TransactionCurrency string          `json:"transaction_currency"`
+	ExchangeRate        string          `json:"exchange_rate"`
+	AmountTransaction   string          `json:"amount_transaction"`
+	DebitBase           string          `json:"debit_base"`
+	CreditBase          string          `json:"credit_base"`
+	Dimensions          []LineDimension `json:"dimensions,omitempty"`
 }
 
 // ProposalLine represents a single debit or credit line in a journal entry proposal.
 // NOTE: Currency is a header-level field on Proposal. All lines in one entry share
 // the same TransactionCurrency and ExchangeRate (SAP model — no mixed-currency entries).
+// Dimensions tag the line for analytic reporting (cost center, project, branch, ...).
 type ProposalLine struct {
-	AccountCode string `json:"account_code" jsonschema_description:"The exact account code from the provided Chart of Accounts"`
-	IsDebit     bool   `json:"is_debit" jsonschema_description:"True if this line is a debit, false for credit"`
-	Amount      string `json:"amount" jsonschema_description:"The exact monetary amount of this single line (always positive) as a string, in the TransactionCurrency"`
+	AccountCode string          `json:"account_code" jsonschema_description:"The exact account code from the provided Chart of Accounts"`
+	IsDebit     bool            `json:"is_debit" jsonschema_description:"True if this line is a debit, false for credit"`
+	Amount      string          `json:"amount" jsonschema_description:"The exact monetary amount of this single line (always positive) as a string, in the TransactionCurrency"`
+	Dimensions  []LineDimension `json:"dimensions" jsonschema_description:"Analytic dimension values of this line; required for accounts marked with required dimensions"`
 }
 
 // Proposal is the AI-generated journal entry proposal.
@@ -76,6 +117,14 @@ type Proposal struct {
 	Confidence          float64        `json:"confidence" jsonschema_description:"Confidence score between 0.0 and 1.0"`
 	Reasoning           string         `json:"reasoning" jsonschema_description:"Explanation for the proposed journal entry"`
 	Lines               []ProposalLine `json:"lines" jsonschema_description:"List of debit and credit lines. All lines share the header TransactionCurrency and ExchangeRate."`
+
+	// Manual marks an entry keyed by a user or proposed by the AI, as opposed to one
+	// generated by a document workflow. Manual entries cannot post to control accounts.
+	// Set by the application layer, never by the AI.
+	Manual bool `json:"-"`
+
+	// CreatedByUserID is the user posting a manual entry (0 for none).
+	CreatedByUserID int `json:"-"`
 }
 
 // ClarificationRequest is returned by the AI when the user's input is ambiguous or missing critical information.
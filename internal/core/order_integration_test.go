@@ -51,7 +51,7 @@ func setupOrderTestDB(t *testing.T) (*pgxpool.Pool, core.OrderService, *core.Led
 	docSvc := core.NewDocumentService(pool)
 	ledger := core.NewLedger(pool, docSvc)
 	ruleEngine := core.NewRuleEngine(pool)
-	orderSvc := core.NewOrderService(pool, ruleEngine)
+	orderSvc := core.NewOrderService(pool, ruleEngine, core.NewTaxEngine(pool))
 
 	return pool, orderSvc, ledger, docSvc, ctx
 }
@@ -17,7 +17,26 @@ type Customer struct {
 	Address          string          `json:"address"`
 	CreditLimit      decimal.Decimal `json:"credit_limit"`
 	PaymentTermsDays int             `json:"payment_terms_days"`
+	StateCode        *string         `json:"state_code"` // GST state code; equal to the company's = intra-state supply
+	GSTIN            *string         `json:"gstin"`      // GST registration; nil = unregistered (B2C) customer
+	IsActive         bool            `json:"is_active"`
+	Version          int             `json:"version"`        // optimistic-concurrency token; incremented by every update
+	MergedIntoID     *int            `json:"merged_into_id"` // set when the customer was merged into another customer
 	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        *time.Time      `json:"updated_at"`
+}
+
+// CustomerInput holds the editable fields of a customer for UpdateCustomer.
+// A customer's code cannot change.
+type CustomerInput struct {
+	Name             string
+	Email            string
+	Phone            string
+	Address          string
+	CreditLimit      decimal.Decimal
+	PaymentTermsDays int
+	StateCode        string // optional two-digit GST state code; taken from GSTIN when empty
+	GSTIN            string // optional GST registration number
 }
 
 // Product represents a sellable item or service in the company catalog.
@@ -29,8 +48,14 @@ type Product struct {
 	Name               string          `json:"name"`
 	Description        string          `json:"description"`
 	UnitPrice          decimal.Decimal `json:"unit_price"`
-	Unit               string          `json:"unit"`
+	Unit               string          `json:"unit"` // stock unit
+	PurchaseUoM        *string         `json:"purchase_uom,omitempty"`
+	SalesUoM           *string         `json:"sales_uom,omitempty"`
 	RevenueAccountCode string          `json:"revenue_account_code"`
+	HSNCode            *string         `json:"hsn_code,omitempty"` // HSN (goods) or SAC (services) code
+	TaxCode            *string         `json:"tax_code,omitempty"` // default GST code on order lines
+	TrackingMode       string          `json:"tracking_mode"`      // NONE | LOT | SERIAL
+	LotPickStrategy    string          `json:"lot_pick_strategy"`  // FEFO | FIFO
 	IsActive           bool            `json:"is_active"`
 	CreatedAt          time.Time       `json:"created_at"`
 }
@@ -41,47 +66,64 @@ type Product struct {
 //	DRAFT → CONFIRMED → SHIPPED → INVOICED → PAID
 //	Any status → CANCELLED (only from DRAFT in Phase 2)
 type SalesOrder struct {
-	ID                int             `json:"id"`
-	CompanyID         int             `json:"company_id"`
-	OrderNumber       string          `json:"order_number"`  // assigned at CONFIRMED via DocumentService
-	CustomerID        int             `json:"customer_id"`
-	CustomerCode      string          `json:"customer_code"` // joined from customers
-	CustomerName      string          `json:"customer_name"` // joined from customers
-	Status            string          `json:"status"`
-	OrderDate         string          `json:"order_date"` // YYYY-MM-DD
-	Currency          string          `json:"currency"`
-	ExchangeRate      decimal.Decimal `json:"exchange_rate"`
-	TotalTransaction  decimal.Decimal `json:"total_transaction"`
-	TotalBase         decimal.Decimal `json:"total_base"`
-	Notes             string          `json:"notes"`
-	InvoiceDocumentID *int            `json:"invoice_document_id,omitempty"`
-	Lines             []SalesOrderLine `json:"lines"`
-	CreatedAt         time.Time       `json:"created_at"`
-	ConfirmedAt       *time.Time      `json:"confirmed_at,omitempty"`
-	ShippedAt         *time.Time      `json:"shipped_at,omitempty"`
-	InvoicedAt        *time.Time      `json:"invoiced_at,omitempty"`
-	PaidAt            *time.Time      `json:"paid_at,omitempty"`
+	ID                 int              `json:"id"`
+	CompanyID          int              `json:"company_id"`
+	OrderNumber        string           `json:"order_number"` // assigned at CONFIRMED via DocumentService
+	CustomerID         int              `json:"customer_id"`
+	CustomerCode       string           `json:"customer_code"` // joined from customers
+	CustomerName       string           `json:"customer_name"` // joined from customers
+	Status             string           `json:"status"`
+	OrderDate          string           `json:"order_date"` // YYYY-MM-DD
+	Currency           string           `json:"currency"`
+	ExchangeRate       decimal.Decimal  `json:"exchange_rate"`
+	TaxableTransaction decimal.Decimal  `json:"taxable_transaction"` // sum of line totals, before GST
+	TaxTransaction     decimal.Decimal  `json:"tax_transaction"`     // GST on the lines
+	TotalTransaction   decimal.Decimal  `json:"total_transaction"`   // taxable value plus GST
+	TotalBase          decimal.Decimal  `json:"total_base"`
+	Notes              string           `json:"notes"`
+	InvoiceDocumentID  *int             `json:"invoice_document_id,omitempty"`
+	Lines              []SalesOrderLine `json:"lines"`
+	CreatedAt          time.Time        `json:"created_at"`
+	ConfirmedAt        *time.Time       `json:"confirmed_at,omitempty"`
+	ShippedAt          *time.Time       `json:"shipped_at,omitempty"`
+	InvoicedAt         *time.Time       `json:"invoiced_at,omitempty"`
+	PaidAt             *time.Time       `json:"paid_at,omitempty"`
 }
 
 // SalesOrderLine represents one line item on a sales order.
+// Quantity and UnitPrice are in the line's Unit; stock moves use StockQuantity.
 type SalesOrderLine struct {
 	ID                   int             `json:"id"`
 	OrderID              int             `json:"order_id"`
 	LineNumber           int             `json:"line_number"`
 	ProductID            int             `json:"product_id"`
-	ProductCode          string          `json:"product_code"`          // joined from products
-	ProductName          string          `json:"product_name"`          // joined from products
-	RevenueAccountCode   string          `json:"revenue_account_code"`  // joined from products
+	ProductCode          string          `json:"product_code"`         // joined from products
+	ProductName          string          `json:"product_name"`         // joined from products
+	RevenueAccountCode   string          `json:"revenue_account_code"` // joined from products
 	Quantity             decimal.Decimal `json:"quantity"`
-	UnitPrice            decimal.Decimal `json:"unit_price"`
+	Unit                 string          `json:"unit"`
+	UnitFactor           decimal.Decimal `json:"unit_factor"`    // stock units per Unit
+	StockQuantity        decimal.Decimal `json:"stock_quantity"` // = Quantity × UnitFactor
+	UnitPrice            decimal.Decimal `json:"unit_price"`     // per Unit
 	LineTotalTransaction decimal.Decimal `json:"line_total_transaction"`
 	LineTotalBase        decimal.Decimal `json:"line_total_base"`
+	HSNCode              *string         `json:"hsn_code,omitempty"` // joined from products
+	TaxCode              *string         `json:"tax_code,omitempty"`
+	TaxAmountTransaction decimal.Decimal `json:"tax_amount_transaction"`
+	Taxes                []LineTax       `json:"taxes,omitempty"` // GST components on LineTotalTransaction
+	Dimensions           []LineDimension `json:"dimensions"`      // carried onto the invoice's revenue line
 }
 
 // OrderLineInput is used when creating a new sales order.
-// If UnitPrice is zero, the product's default unit_price is used.
+// If UnitPrice is zero, the product's default unit_price is used, scaled to Unit.
+// If Unit is empty, the product's sales unit (or stock unit) is used.
+// If TaxCode is empty, the product's default tax code (if any) is used.
+// Dimensions (cost center, project, ...) are posted with the line's revenue on invoicing.
 type OrderLineInput struct {
 	ProductCode string
 	Quantity    decimal.Decimal
+	Unit        string
 	UnitPrice   decimal.Decimal // zero means "use product default"
+	TaxCode     string
+	Dimensions  []LineDimension
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -18,16 +20,36 @@ const defaultBankAccountCode = "1100"
 type OrderService interface {
 	// Master data
 	CreateCustomer(ctx context.Context, companyCode, code, name, email, phone, address string, creditLimit decimal.Decimal, paymentTermsDays int) (*Customer, error)
+	// GetCustomers returns the company's active customers.
 	GetCustomers(ctx context.Context, companyCode string) ([]Customer, error)
+	// GetCustomer returns one customer by code, active or not.
+	GetCustomer(ctx context.Context, companyCode, code string) (*Customer, error)
+	// UpdateCustomer replaces a customer's editable fields. version must be the customer's
+	// current version; otherwise ErrVersionConflict is returned and nothing is changed.
+	UpdateCustomer(ctx context.Context, companyCode, code string, version int, input CustomerInput) (*Customer, error)
+	// SetCustomerActive deactivates or reactivates a customer. Inactive customers are
+	// rejected on new sales orders; existing orders are unaffected.
+	SetCustomerActive(ctx context.Context, companyCode, code string, active bool) (*Customer, error)
+	// MergeCustomers repoints the duplicate customer's sales orders to the survivor, then
+	// deactivates the duplicate and records the merge on it.
+	MergeCustomers(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error)
 	CreateProduct(ctx context.Context, companyCode, code, name, description string, unitPrice decimal.Decimal, unit, revenueAccountCode string) (*Product, error)
 	GetProducts(ctx context.Context, companyCode string) ([]Product, error)
+	// SetProductTax sets a product's HSN/SAC code and default GST tax code; empty clears them.
+	SetProductTax(ctx context.Context, companyCode, productCode, hsnCode, taxCode string) (*Product, error)
 
 	// Order lifecycle
+	// CreateOrder creates a DRAFT order. Lines with a tax code are charged GST at the rates in
+	// effect on orderDate; the order total is the taxable value plus GST.
 	CreateOrder(ctx context.Context, companyCode, customerCode, currency string, exchangeRate decimal.Decimal, orderDate string, lines []OrderLineInput, notes string) (*SalesOrder, error)
 	// ConfirmOrder transitions DRAFT → CONFIRMED. Pass inv=nil to skip stock reservation.
 	ConfirmOrder(ctx context.Context, orderID int, docService DocumentService, inv InventoryService) (*SalesOrder, error)
 	// ShipOrder transitions CONFIRMED → SHIPPED. Pass inv=nil to skip COGS booking.
 	ShipOrder(ctx context.Context, orderID int, inv InventoryService, ledger *Ledger, docService DocumentService) (*SalesOrder, error)
+	// ShipOrderWithPicks is ShipOrder with explicit lot picks for lot-tracked products.
+	ShipOrderWithPicks(ctx context.Context, orderID int, picks []LotPick, inv InventoryService, ledger *Ledger, docService DocumentService) (*SalesOrder, error)
+	// InvoiceOrder transitions SHIPPED → INVOICED, posting DR AR (total) / CR revenue (taxable
+	// value) / CR GST_OUTPUT_* (tax) and recording each line's GST components.
 	InvoiceOrder(ctx context.Context, orderID int, ledger *Ledger, docService DocumentService) (*SalesOrder, error)
 	RecordPayment(ctx context.Context, orderID int, bankAccountCode string, paymentDate string, ledger *Ledger) error
 	// CancelOrder transitions DRAFT → CANCELLED. Pass inv=nil to skip reservation release.
@@ -40,12 +62,13 @@ type OrderService interface {
 }
 
 type orderService struct {
-	pool        *pgxpool.Pool
-	ruleEngine  RuleEngine
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+	taxEngine  TaxEngine
 }
 
-func NewOrderService(pool *pgxpool.Pool, ruleEngine RuleEngine) OrderService {
-	return &orderService{pool: pool, ruleEngine: ruleEngine}
+func NewOrderService(pool *pgxpool.Pool, ruleEngine RuleEngine, taxEngine TaxEngine) OrderService {
+	return &orderService{pool: pool, ruleEngine: ruleEngine, taxEngine: taxEngine}
 }
 
 // resolveCompanyID looks up the internal company ID from a company code.
@@ -68,21 +91,28 @@ type pgxQuerier interface {
 
 // ── Master Data ──────────────────────────────────────────────────────────────
 
+// customerColumns is the column list scanned by scanCustomer.
+const customerColumns = `id, company_id, code, name, email, phone, address, credit_limit, payment_terms_days,
+	state_code, gstin, is_active, version, merged_into_id, created_at, updated_at`
+
+func scanCustomer(row pgx.Row) (Customer, error) {
+	var c Customer
+	err := row.Scan(&c.ID, &c.CompanyID, &c.Code, &c.Name, &c.Email, &c.Phone, &c.Address,
+		&c.CreditLimit, &c.PaymentTermsDays, &c.StateCode, &c.GSTIN, &c.IsActive, &c.Version, &c.MergedIntoID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
 func (s *orderService) CreateCustomer(ctx context.Context, companyCode, code, name, email, phone, address string, creditLimit decimal.Decimal, paymentTermsDays int) (*Customer, error) {
 	companyID, err := s.resolveCompanyID(ctx, s.pool, companyCode)
 	if err != nil {
 		return nil, err
 	}
 
-	var c Customer
-	err = s.pool.QueryRow(ctx, `
+	c, err := scanCustomer(s.pool.QueryRow(ctx, `
 		INSERT INTO customers (company_id, code, name, email, phone, address, credit_limit, payment_terms_days)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, company_id, code, name, email, phone, address, credit_limit, payment_terms_days, created_at
-	`, companyID, code, name, email, phone, address, creditLimit, paymentTermsDays).Scan(
-		&c.ID, &c.CompanyID, &c.Code, &c.Name, &c.Email, &c.Phone, &c.Address,
-		&c.CreditLimit, &c.PaymentTermsDays, &c.CreatedAt,
-	)
+		RETURNING `+customerColumns,
+		companyID, code, name, email, phone, address, creditLimit, paymentTermsDays))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create customer: %w", err)
 	}
@@ -96,9 +126,9 @@ func (s *orderService) GetCustomers(ctx context.Context, companyCode string) ([]
 	}
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, company_id, code, name, email, phone, address, credit_limit, payment_terms_days, created_at
+		SELECT `+customerColumns+`
 		FROM customers
-		WHERE company_id = $1
+		WHERE company_id = $1 AND is_active = true
 		ORDER BY code
 	`, companyID)
 	if err != nil {
@@ -108,9 +138,8 @@ func (s *orderService) GetCustomers(ctx context.Context, companyCode string) ([]
 
 	var customers []Customer
 	for rows.Next() {
-		var c Customer
-		if err := rows.Scan(&c.ID, &c.CompanyID, &c.Code, &c.Name, &c.Email, &c.Phone, &c.Address,
-			&c.CreditLimit, &c.PaymentTermsDays, &c.CreatedAt); err != nil {
+		c, err := scanCustomer(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan customer: %w", err)
 		}
 		customers = append(customers, c)
@@ -118,6 +147,190 @@ func (s *orderService) GetCustomers(ctx context.Context, companyCode string) ([]
 	return customers, nil
 }
 
+func (s *orderService) GetCustomer(ctx context.Context, companyCode, code string) (*Customer, error) {
+	companyID, err := s.resolveCompanyID(ctx, s.pool, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := scanCustomer(s.pool.QueryRow(ctx, `
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE company_id = $1 AND code = $2
+	`, companyID, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("customer code %s not found for company %s", code, companyCode)
+		}
+		return nil, fmt.Errorf("failed to get customer %s: %w", code, err)
+	}
+	return &c, nil
+}
+
+func (s *orderService) UpdateCustomer(ctx context.Context, companyCode, code string, version int, input CustomerInput) (*Customer, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("customer name is required")
+	}
+	if input.CreditLimit.IsNegative() {
+		return nil, fmt.Errorf("credit limit cannot be negative")
+	}
+	if input.PaymentTermsDays < 0 {
+		return nil, fmt.Errorf("payment terms cannot be negative")
+	}
+	stateCode, gstin, err := gstRegistration(input.StateCode, input.GSTIN)
+	if err != nil {
+		return nil, err
+	}
+	companyID, err := s.resolveCompanyID(ctx, s.pool, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := scanCustomer(s.pool.QueryRow(ctx, `
+		UPDATE customers
+		SET name = $4, email = $5, phone = $6, address = $7, credit_limit = $8, payment_terms_days = $9,
+		    state_code = $10, gstin = $11, version = version + 1, updated_at = NOW()
+		WHERE company_id = $1 AND code = $2 AND version = $3
+		RETURNING `+customerColumns,
+		companyID, code, version, input.Name, input.Email, input.Phone, input.Address,
+		input.CreditLimit, input.PaymentTermsDays, stateCode, gstin))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either the customer does not exist or its version moved on since it was read.
+		if _, getErr := s.GetCustomer(ctx, companyCode, code); getErr != nil {
+			return nil, getErr
+		}
+		return nil, fmt.Errorf("failed to update customer %s: %w", code, ErrVersionConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update customer %s: %w", code, err)
+	}
+	return &c, nil
+}
+
+func (s *orderService) SetCustomerActive(ctx context.Context, companyCode, code string, active bool) (*Customer, error) {
+	current, err := s.GetCustomer(ctx, companyCode, code)
+	if err != nil {
+		return nil, err
+	}
+	if active && current.MergedIntoID != nil {
+		return nil, fmt.Errorf("customer %s was merged into another customer and cannot be reactivated", code)
+	}
+	if current.IsActive == active {
+		return current, nil
+	}
+
+	c, err := scanCustomer(s.pool.QueryRow(ctx, `
+		UPDATE customers
+		SET is_active = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+customerColumns,
+		current.ID, active))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set customer %s active=%t: %w", code, active, err)
+	}
+	return &c, nil
+}
+
+// MergeCustomers moves the duplicate's sales orders to the survivor in one transaction and
+// leaves the duplicate inactive with merged_into_id set. Receivables follow the orders:
+// AR is carried per order, so open invoices are collected against the survivor.
+func (s *orderService) MergeCustomers(ctx context.Context, companyCode, duplicateCode, survivorCode string) (*MergeResult, error) {
+	if duplicateCode == "" || survivorCode == "" {
+		return nil, fmt.Errorf("duplicate and survivor customer codes are required")
+	}
+	if duplicateCode == survivorCode {
+		return nil, fmt.Errorf("cannot merge customer %s into itself", duplicateCode)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	companyID, err := s.resolveCompanyID(ctx, tx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE company_id = $1 AND code IN ($2, $3)
+		ORDER BY id
+		FOR UPDATE
+	`, companyID, duplicateCode, survivorCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock customers: %w", err)
+	}
+	var duplicate, survivor *Customer
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		if c.Code == duplicateCode {
+			duplicate = &c
+		} else {
+			survivor = &c
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock customers: %w", err)
+	}
+	if duplicate == nil {
+		return nil, fmt.Errorf("customer code %s not found for company %s", duplicateCode, companyCode)
+	}
+	if survivor == nil {
+		return nil, fmt.Errorf("customer code %s not found for company %s", survivorCode, companyCode)
+	}
+	if duplicate.MergedIntoID != nil {
+		return nil, fmt.Errorf("customer %s has already been merged", duplicateCode)
+	}
+	if !survivor.IsActive {
+		return nil, fmt.Errorf("surviving customer %s is inactive", survivorCode)
+	}
+
+	result := &MergeResult{DuplicateCode: duplicateCode, SurvivorCode: survivorCode, Repointed: map[string]int{}}
+	tag, err := tx.Exec(ctx, "UPDATE sales_orders SET customer_id = $2 WHERE customer_id = $1", duplicate.ID, survivor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to repoint sales orders: %w", err)
+	}
+	if n := int(tag.RowsAffected()); n > 0 {
+		result.Repointed["sales_orders"] = n
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE customers
+		SET is_active = false, merged_into_id = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1
+	`, duplicate.ID, survivor.ID); err != nil {
+		return nil, fmt.Errorf("failed to deactivate customer %s: %w", duplicateCode, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit customer merge: %w", err)
+	}
+	return result, nil
+}
+
+// productColumns is the column list scanned by scanProduct.
+const productColumns = `id, company_id, code, name, description, unit_price, unit, purchase_uom, sales_uom,
+	revenue_account_code, hsn_code, tax_code, tracking_mode, lot_pick_strategy, is_active, created_at`
+
+func scanProduct(row pgx.Row) (Product, error) {
+	var p Product
+	err := row.Scan(&p.ID, &p.CompanyID, &p.Code, &p.Name, &p.Description,
+		&p.UnitPrice, &p.Unit, &p.PurchaseUoM, &p.SalesUoM,
+		&p.RevenueAccountCode, &p.HSNCode, &p.TaxCode, &p.TrackingMode, &p.LotPickStrategy, &p.IsActive, &p.CreatedAt)
+	return p, err
+}
+
+// hsnPattern matches an HSN (goods) or SAC (services) code.
+var hsnPattern = regexp.MustCompile(`^([0-9]{4}|[0-9]{6}|[0-9]{8})$`)
+
 func (s *orderService) CreateProduct(ctx context.Context, companyCode, code, name, description string, unitPrice decimal.Decimal, unit, revenueAccountCode string) (*Product, error) {
 	companyID, err := s.resolveCompanyID(ctx, s.pool, companyCode)
 	if err != nil {
@@ -134,15 +347,21 @@ func (s *orderService) CreateProduct(ctx context.Context, companyCode, code, nam
 		return nil, fmt.Errorf("failed to verify revenue account: %w", err)
 	}
 
-	var p Product
-	err = s.pool.QueryRow(ctx, `
+	// The stock unit is registered in the unit master so conversions can be defined against it.
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO units_of_measure (company_id, code, name)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (company_id, code) DO NOTHING
+	`, companyID, unit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register unit %s: %w", unit, err)
+	}
+
+	p, err := scanProduct(s.pool.QueryRow(ctx, `
 		INSERT INTO products (company_id, code, name, description, unit_price, unit, revenue_account_code)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, company_id, code, name, description, unit_price, unit, revenue_account_code, is_active, created_at
-	`, companyID, code, name, description, unitPrice, unit, revenueAccountCode).Scan(
-		&p.ID, &p.CompanyID, &p.Code, &p.Name, &p.Description,
-		&p.UnitPrice, &p.Unit, &p.RevenueAccountCode, &p.IsActive, &p.CreatedAt,
-	)
+		RETURNING `+productColumns+`
+	`, companyID, code, name, description, unitPrice, unit, revenueAccountCode))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
@@ -156,7 +375,7 @@ func (s *orderService) GetProducts(ctx context.Context, companyCode string) ([]P
 	}
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, company_id, code, name, description, unit_price, unit, revenue_account_code, is_active, created_at
+		SELECT `+productColumns+`
 		FROM products
 		WHERE company_id = $1 AND is_active = true
 		ORDER BY code
@@ -168,9 +387,8 @@ func (s *orderService) GetProducts(ctx context.Context, companyCode string) ([]P
 
 	var products []Product
 	for rows.Next() {
-		var p Product
-		if err := rows.Scan(&p.ID, &p.CompanyID, &p.Code, &p.Name, &p.Description,
-			&p.UnitPrice, &p.Unit, &p.RevenueAccountCode, &p.IsActive, &p.CreatedAt); err != nil {
+		p, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
 		products = append(products, p)
@@ -178,12 +396,54 @@ func (s *orderService) GetProducts(ctx context.Context, companyCode string) ([]P
 	return products, nil
 }
 
+func (s *orderService) SetProductTax(ctx context.Context, companyCode, productCode, hsnCode, taxCode string) (*Product, error) {
+	companyID, err := s.resolveCompanyID(ctx, s.pool, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	hsnCode = strings.TrimSpace(hsnCode)
+	if hsnCode != "" && !hsnPattern.MatchString(hsnCode) {
+		return nil, fmt.Errorf("invalid HSN/SAC code %q: expected 4, 6 or 8 digits", hsnCode)
+	}
+	taxCode = strings.ToUpper(strings.TrimSpace(taxCode))
+	if taxCode != "" {
+		var exists bool
+		if err := s.pool.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM tax_codes WHERE company_id = $1 AND code = $2 AND is_active)",
+			companyID, taxCode,
+		).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check tax code %s: %w", taxCode, err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("tax code %s not found for company %s", taxCode, companyCode)
+		}
+	}
+
+	p, err := scanProduct(s.pool.QueryRow(ctx, `
+		UPDATE products SET hsn_code = NULLIF($3, ''), tax_code = NULLIF($4, '')
+		WHERE company_id = $1 AND code = $2
+		RETURNING `+productColumns,
+		companyID, productCode, hsnCode, taxCode))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product code %s not found for company %s", productCode, companyCode)
+		}
+		return nil, fmt.Errorf("failed to update tax of product %s: %w", productCode, err)
+	}
+	return &p, nil
+}
+
 // ── Order Lifecycle ──────────────────────────────────────────────────────────
 
 func (s *orderService) CreateOrder(ctx context.Context, companyCode, customerCode, currency string, exchangeRate decimal.Decimal, orderDate string, lines []OrderLineInput, notes string) (*SalesOrder, error) {
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("order must have at least one line")
 	}
+	taxDate, err := time.Parse("2006-01-02", orderDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order date %q (expected YYYY-MM-DD)", orderDate)
+	}
 
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -199,34 +459,45 @@ func (s *orderService) CreateOrder(ctx context.Context, companyCode, customerCod
 	// Resolve customer
 	var customerID int
 	var customerName string
-	err = tx.QueryRow(ctx, "SELECT id, name FROM customers WHERE company_id = $1 AND code = $2", companyID, customerCode).Scan(&customerID, &customerName)
+	var customerActive bool
+	var customerState *string
+	err = tx.QueryRow(ctx, "SELECT id, name, is_active, state_code FROM customers WHERE company_id = $1 AND code = $2", companyID, customerCode).Scan(&customerID, &customerName, &customerActive, &customerState)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("customer code %s not found for company %s", customerCode, companyCode)
 		}
 		return nil, fmt.Errorf("failed to resolve customer: %w", err)
 	}
+	if !customerActive {
+		return nil, fmt.Errorf("customer %s is inactive", customerCode)
+	}
 
 	// Compute order totals from lines
-	var totalTransaction decimal.Decimal
+	var taxableTransaction, taxTransaction decimal.Decimal
+	supply := newGSTSupply(s.taxEngine, tx, companyID, taxDate, "customer "+customerCode, customerState)
 	type resolvedLine struct {
 		productID            int
 		productCode          string
 		productName          string
 		revenueAccountCode   string
 		quantity             decimal.Decimal
+		uom                  string
+		uomFactor            decimal.Decimal
 		unitPrice            decimal.Decimal
 		lineTotalTransaction decimal.Decimal
 		lineTotalBase        decimal.Decimal
+		taxCode              *string
+		taxes                []LineTax
+		dimensions           []LineDimension
 	}
 	var resolved []resolvedLine
 
 	for i, input := range lines {
 		var prod Product
 		err = tx.QueryRow(ctx,
-			"SELECT id, code, name, unit_price, revenue_account_code FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
+			"SELECT id, code, name, unit_price, sales_uom, revenue_account_code, tax_code FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
 			companyID, input.ProductCode,
-		).Scan(&prod.ID, &prod.Code, &prod.Name, &prod.UnitPrice, &prod.RevenueAccountCode)
+		).Scan(&prod.ID, &prod.Code, &prod.Name, &prod.UnitPrice, &prod.SalesUoM, &prod.RevenueAccountCode, &prod.TaxCode)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return nil, fmt.Errorf("line %d: product code %s not found for company %s", i+1, input.ProductCode, companyCode)
@@ -234,14 +505,39 @@ func (s *orderService) CreateOrder(ctx context.Context, companyCode, customerCod
 			return nil, fmt.Errorf("line %d: failed to resolve product: %w", i+1, err)
 		}
 
-		price := prod.UnitPrice
+		uom := input.Unit
+		if uom == "" && prod.SalesUoM != nil {
+			uom = *prod.SalesUoM
+		}
+		uom, factor, err := resolveLineUoM(ctx, tx, prod.ID, uom)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		// The catalogue price is per stock unit; scale it to the line unit.
+		price := prod.UnitPrice.Mul(factor).Round(2)
 		if !input.UnitPrice.IsZero() {
 			price = input.UnitPrice
 		}
 
 		lineTotal := input.Quantity.Mul(price)
 		lineTotalBase := lineTotal.Mul(exchangeRate)
-		totalTransaction = totalTransaction.Add(lineTotal)
+		taxableTransaction = taxableTransaction.Add(lineTotal)
+
+		taxCode := strings.ToUpper(strings.TrimSpace(input.TaxCode))
+		if taxCode == "" && prod.TaxCode != nil {
+			taxCode = *prod.TaxCode
+		}
+		taxes, err := supply.lineTax(ctx, taxCode, lineTotal.Round(2))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		taxTransaction = taxTransaction.Add(sumTax(taxes))
+
+		dims := NormalizeLineDimensions(input.Dimensions)
+		if err := validateLineDimensionsTx(ctx, tx, companyID, dims); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
 
 		resolved = append(resolved, resolvedLine{
 			productID:            prod.ID,
@@ -249,34 +545,49 @@ func (s *orderService) CreateOrder(ctx context.Context, companyCode, customerCod
 			productName:          prod.Name,
 			revenueAccountCode:   prod.RevenueAccountCode,
 			quantity:             input.Quantity,
+			uom:                  uom,
+			uomFactor:            factor,
 			unitPrice:            price,
 			lineTotalTransaction: lineTotal,
 			lineTotalBase:        lineTotalBase,
+			taxes:                taxes,
+			dimensions:           dims,
 		})
+		if taxCode != "" {
+			resolved[len(resolved)-1].taxCode = &taxCode
+		}
 	}
 
+	totalTransaction := taxableTransaction.Add(taxTransaction)
 	totalBase := totalTransaction.Mul(exchangeRate)
 
 	// Insert order header
 	var orderID int
 	err = tx.QueryRow(ctx, `
-		INSERT INTO sales_orders (company_id, customer_id, status, order_date, currency, exchange_rate, total_transaction, total_base, notes)
-		VALUES ($1, $2, 'DRAFT', $3, $4, $5, $6, $7, $8)
+		INSERT INTO sales_orders (company_id, customer_id, status, order_date, currency, exchange_rate, total_transaction, total_base, tax_transaction, notes)
+		VALUES ($1, $2, 'DRAFT', $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
-	`, companyID, customerID, orderDate, currency, exchangeRate, totalTransaction, totalBase, notes).Scan(&orderID)
+	`, companyID, customerID, orderDate, currency, exchangeRate, totalTransaction, totalBase, taxTransaction, notes).Scan(&orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert sales order: %w", err)
 	}
 
 	// Insert order lines
 	for i, rl := range resolved {
-		_, err = tx.Exec(ctx, `
-			INSERT INTO sales_order_lines (order_id, line_number, product_id, quantity, unit_price, line_total_transaction, line_total_base)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, orderID, i+1, rl.productID, rl.quantity, rl.unitPrice, rl.lineTotalTransaction, rl.lineTotalBase)
+		var lineID int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO sales_order_lines (order_id, line_number, product_id, quantity, uom, uom_factor, unit_price, line_total_transaction, line_total_base,
+			                               tax_code, tax_amount_transaction, dimensions)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id
+		`, orderID, i+1, rl.productID, rl.quantity, rl.uom, rl.uomFactor, rl.unitPrice, rl.lineTotalTransaction, rl.lineTotalBase,
+			rl.taxCode, sumTax(rl.taxes), rl.dimensions).Scan(&lineID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert order line %d: %w", i+1, err)
 		}
+		if err := insertLineTaxesTx(ctx, tx, companyID, lineTaxSalesOrderLine, lineID, rl.taxes); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -360,6 +671,10 @@ func (s *orderService) ConfirmOrder(ctx context.Context, orderID int, docService
 }
 
 func (s *orderService) ShipOrder(ctx context.Context, orderID int, inv InventoryService, ledger *Ledger, docService DocumentService) (*SalesOrder, error) {
+	return s.ShipOrderWithPicks(ctx, orderID, nil, inv, ledger, docService)
+}
+
+func (s *orderService) ShipOrderWithPicks(ctx context.Context, orderID int, picks []LotPick, inv InventoryService, ledger *Ledger, docService DocumentService) (*SalesOrder, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -388,7 +703,7 @@ func (s *orderService) ShipOrder(ctx context.Context, orderID int, inv Inventory
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch order lines for shipment: %w", err)
 		}
-		if err := inv.ShipStockTx(ctx, tx, companyID, orderID, lines, ledger, docService); err != nil {
+		if err := inv.ShipStockTx(ctx, tx, companyID, orderID, lines, picks, ledger, docService); err != nil {
 			return nil, fmt.Errorf("inventory shipment failed: %w", err)
 		}
 	}
@@ -424,10 +739,26 @@ func (s *orderService) InvoiceOrder(ctx context.Context, orderID int, ledger *Le
 		return nil, fmt.Errorf("failed to resolve company for order %d: %w", orderID, err)
 	}
 
-	// Build accounting proposal: DR AR, CR Revenue per account.
-	revenueByAccount := make(map[string]decimal.Decimal)
+	// Build accounting proposal: DR AR, CR Revenue per account and set of line dimensions,
+	// CR output GST per component.
+	type revenueGroup struct {
+		accountCode string
+		dimensions  []LineDimension
+		amount      decimal.Decimal
+	}
+	var revenue []revenueGroup
+	revenueIndex := make(map[string]int)
+	var taxes []LineTax
 	for _, line := range order.Lines {
-		revenueByAccount[line.RevenueAccountCode] = revenueByAccount[line.RevenueAccountCode].Add(line.LineTotalTransaction)
+		key := line.RevenueAccountCode + "|" + DimensionKey(line.Dimensions)
+		i, ok := revenueIndex[key]
+		if !ok {
+			i = len(revenue)
+			revenueIndex[key] = i
+			revenue = append(revenue, revenueGroup{accountCode: line.RevenueAccountCode, dimensions: line.Dimensions})
+		}
+		revenue[i].amount = revenue[i].amount.Add(line.LineTotalTransaction)
+		taxes = append(taxes, line.Taxes...)
 	}
 
 	arAccount, err := s.ruleEngine.ResolveAccount(ctx, order.CompanyID, "AR")
@@ -441,26 +772,36 @@ func (s *orderService) InvoiceOrder(ctx context.Context, orderID int, ledger *Le
 		IsDebit:     true,
 		Amount:      order.TotalTransaction.String(),
 	})
-	for accountCode, amount := range revenueByAccount {
+	for _, r := range revenue {
 		proposalLines = append(proposalLines, ProposalLine{
-			AccountCode: accountCode,
+			AccountCode: r.accountCode,
 			IsDebit:     false,
-			Amount:      amount.String(),
+			Amount:      r.amount.String(),
+			Dimensions:  r.dimensions,
 		})
 	}
+	gstLines, err := gstProposalLines(ctx, s.ruleEngine, order.CompanyID, taxes, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve GST accounts for invoicing: %w", err)
+	}
+	proposalLines = append(proposalLines, gstLines...)
+
+	reasoning := fmt.Sprintf("Automatically generated invoice for confirmed and shipped sales order %s.", order.OrderNumber)
+	if order.TaxTransaction.IsPositive() {
+		reasoning += fmt.Sprintf(" GST of %s on taxable value %s.", order.TaxTransaction.StringFixed(2), order.TaxableTransaction.StringFixed(2))
+	}
 
 	today := time.Now().Format("2006-01-02")
 	proposal := Proposal{
 		DocumentTypeCode:    "SI",
 		CompanyCode:         companyCode,
-		IdempotencyKey:      fmt.Sprintf("invoice-order-%d", orderID),
 		TransactionCurrency: order.Currency,
 		ExchangeRate:        order.ExchangeRate.String(),
 		Summary:             fmt.Sprintf("Sales Invoice for order %s — %s", order.OrderNumber, order.CustomerName),
 		PostingDate:         today,
 		DocumentDate:        order.OrderDate,
 		Confidence:          1.0,
-		Reasoning:           fmt.Sprintf("Automatically generated invoice for confirmed and shipped sales order %s.", order.OrderNumber),
+		Reasoning:           reasoning,
 		Lines:               proposalLines,
 	}
 
@@ -471,6 +812,9 @@ func (s *orderService) InvoiceOrder(ctx context.Context, orderID int, ledger *Le
 	}
 	defer tx.Rollback(ctx)
 
+	if proposal.IdempotencyKey, err = invoiceEntryKey(ctx, tx, orderID); err != nil {
+		return nil, err
+	}
 	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
 		return nil, fmt.Errorf("failed to commit invoice journal entry for order %s: %w", order.OrderNumber, err)
 	}
@@ -483,7 +827,7 @@ func (s *orderService) InvoiceOrder(ctx context.Context, orderID int, ledger *Le
 		JOIN journal_entries je ON je.reference_id = d.document_number AND je.reference_type = 'DOCUMENT'
 		WHERE je.idempotency_key = $1
 		LIMIT 1
-	`, fmt.Sprintf("invoice-order-%d", orderID)).Scan(&invoiceDocID)
+	`, proposal.IdempotencyKey).Scan(&invoiceDocID)
 
 	if _, err = tx.Exec(ctx, `
 		UPDATE sales_orders
@@ -500,6 +844,24 @@ func (s *orderService) InvoiceOrder(ctx context.Context, orderID int, ledger *Le
 	return s.GetOrder(ctx, orderID)
 }
 
+// invoiceEntryKey returns the idempotency key of the order's next invoice entry. The first
+// invoice posts under invoice-order-<id>; an order whose e-invoice was cancelled is back in
+// SHIPPED and its re-invoice posts under invoice-order-<id>-<n>.
+func invoiceEntryKey(ctx context.Context, q pgxQuerier, orderID int) (string, error) {
+	key := fmt.Sprintf("invoice-order-%d", orderID)
+	var prior int
+	if err := q.QueryRow(ctx, `
+		SELECT count(*) FROM journal_entries
+		WHERE idempotency_key = $1 OR idempotency_key LIKE $1 || '-%'`, key,
+	).Scan(&prior); err != nil {
+		return "", fmt.Errorf("count invoices of order %d: %w", orderID, err)
+	}
+	if prior > 0 {
+		key = fmt.Sprintf("%s-%d", key, prior+1)
+	}
+	return key, nil
+}
+
 func (s *orderService) RecordPayment(ctx context.Context, orderID int, bankAccountCode string, paymentDate string, ledger *Ledger) error {
 	if bankAccountCode == "" {
 		bankAccountCode = defaultBankAccountCode
@@ -617,7 +979,7 @@ func (s *orderService) GetOrder(ctx context.Context, orderID int) (*SalesOrder,
 	err := s.pool.QueryRow(ctx, `
 		SELECT so.id, so.company_id, COALESCE(so.order_number, ''), c.code, c.name,
 		       so.status, so.order_date::text, so.currency, so.exchange_rate,
-		       so.total_transaction, so.total_base, so.notes, so.invoice_document_id,
+		       so.total_transaction, so.total_base, so.tax_transaction, so.notes, so.invoice_document_id,
 		       so.created_at, so.confirmed_at, so.shipped_at, so.invoiced_at, so.paid_at,
 		       so.customer_id
 		FROM sales_orders so
@@ -626,7 +988,7 @@ func (s *orderService) GetOrder(ctx context.Context, orderID int) (*SalesOrder,
 	`, orderID).Scan(
 		&o.ID, &o.CompanyID, &o.OrderNumber, &o.CustomerCode, &o.CustomerName,
 		&o.Status, &o.OrderDate, &o.Currency, &o.ExchangeRate,
-		&o.TotalTransaction, &o.TotalBase, &o.Notes, &o.InvoiceDocumentID,
+		&o.TotalTransaction, &o.TotalBase, &o.TaxTransaction, &o.Notes, &o.InvoiceDocumentID,
 		&o.CreatedAt, &o.ConfirmedAt, &o.ShippedAt, &o.InvoicedAt, &o.PaidAt,
 		&o.CustomerID,
 	)
@@ -636,6 +998,7 @@ func (s *orderService) GetOrder(ctx context.Context, orderID int) (*SalesOrder,
 		}
 		return nil, fmt.Errorf("failed to fetch order %d: %w", orderID, err)
 	}
+	o.TaxableTransaction = o.TotalTransaction.Sub(o.TaxTransaction)
 
 	lines, err := s.fetchOrderLines(ctx, orderID)
 	if err != nil {
@@ -675,7 +1038,7 @@ func (s *orderService) GetOrders(ctx context.Context, companyCode string, status
 	query := `
 		SELECT so.id, so.company_id, COALESCE(so.order_number, ''), c.code, c.name,
 		       so.status, so.order_date::text, so.currency, so.exchange_rate,
-		       so.total_transaction, so.total_base, so.notes, so.invoice_document_id,
+		       so.total_transaction, so.total_base, so.tax_transaction, so.notes, so.invoice_document_id,
 		       so.created_at, so.confirmed_at, so.shipped_at, so.invoiced_at, so.paid_at,
 		       so.customer_id
 		FROM sales_orders so
@@ -702,12 +1065,13 @@ func (s *orderService) GetOrders(ctx context.Context, companyCode string, status
 		if err := rows.Scan(
 			&o.ID, &o.CompanyID, &o.OrderNumber, &o.CustomerCode, &o.CustomerName,
 			&o.Status, &o.OrderDate, &o.Currency, &o.ExchangeRate,
-			&o.TotalTransaction, &o.TotalBase, &o.Notes, &o.InvoiceDocumentID,
+			&o.TotalTransaction, &o.TotalBase, &o.TaxTransaction, &o.Notes, &o.InvoiceDocumentID,
 			&o.CreatedAt, &o.ConfirmedAt, &o.ShippedAt, &o.InvoicedAt, &o.PaidAt,
 			&o.CustomerID,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan order: %w", err)
 		}
+		o.TaxableTransaction = o.TotalTransaction.Sub(o.TaxTransaction)
 		orders = append(orders, o)
 	}
 	return orders, nil
@@ -731,7 +1095,9 @@ func fetchOrderLinesQ(ctx context.Context, q pgxRowQuerier, orderID int) ([]Sale
 	rows, err := q.Query(ctx, `
 		SELECT sol.id, sol.order_id, sol.line_number,
 		       p.id, p.code, p.name, p.revenue_account_code,
-		       sol.quantity, sol.unit_price, sol.line_total_transaction, sol.line_total_base
+		       sol.quantity, COALESCE(sol.uom, p.unit), sol.uom_factor,
+		       sol.unit_price, sol.line_total_transaction, sol.line_total_base,
+		       p.hsn_code, sol.tax_code, sol.tax_amount_transaction, sol.dimensions
 		FROM sales_order_lines sol
 		JOIN products p ON p.id = sol.product_id
 		WHERE sol.order_id = $1
@@ -748,11 +1114,30 @@ func fetchOrderLinesQ(ctx context.Context, q pgxRowQuerier, orderID int) ([]Sale
 		if err := rows.Scan(
 			&l.ID, &l.OrderID, &l.LineNumber,
 			&l.ProductID, &l.ProductCode, &l.ProductName, &l.RevenueAccountCode,
-			&l.Quantity, &l.UnitPrice, &l.LineTotalTransaction, &l.LineTotalBase,
+			&l.Quantity, &l.Unit, &l.UnitFactor,
+			&l.UnitPrice, &l.LineTotalTransaction, &l.LineTotalBase,
+			&l.HSNCode, &l.TaxCode, &l.TaxAmountTransaction, &l.Dimensions,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan order line: %w", err)
 		}
+		l.StockQuantity = l.Quantity.Mul(l.UnitFactor)
 		lines = append(lines, l)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read order lines: %w", err)
+	}
+	rows.Close()
+
+	lineIDs := make([]int, len(lines))
+	for i, l := range lines {
+		lineIDs[i] = l.ID
+	}
+	taxes, err := fetchLineTaxesQ(ctx, q, lineTaxSalesOrderLine, lineIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		lines[i].Taxes = taxes[lines[i].ID]
+	}
 	return lines, nil
 }
@@ -0,0 +1,264 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentDebtor is the paying company and its house bank account.
+type PaymentDebtor struct {
+	Name          string
+	AccountNumber string // IBAN or local account number
+	BankCode      string // BIC or local clearing code, optional
+	Currency      string
+}
+
+// VendorPayment is one credit transfer in a payment file: everything a run pays to a
+// single vendor.
+type VendorPayment struct {
+	VendorCode        string
+	VendorName        string
+	BankAccountName   string
+	BankAccountNumber string
+	BankCode          string
+	Amount            decimal.Decimal
+	References        []string // documents settled, sent as unstructured remittance info
+}
+
+var (
+	bicPattern  = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// ── ISO 20022 pain.001.001.09 ─────────────────────────────────────────────────
+
+type painDocument struct {
+	XMLName xml.Name     `xml:"Document"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Init    painInitiate `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiate struct {
+	GrpHdr painGroupHeader `xml:"GrpHdr"`
+	PmtInf painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MsgId    string    `xml:"MsgId"`
+	CreDtTm  string    `xml:"CreDtTm"`
+	NbOfTxs  int       `xml:"NbOfTxs"`
+	CtrlSum  string    `xml:"CtrlSum"`
+	InitgPty painParty `xml:"InitgPty"`
+}
+
+type painPaymentInfo struct {
+	PmtInfId    string         `xml:"PmtInfId"`
+	PmtMtd      string         `xml:"PmtMtd"`
+	NbOfTxs     int            `xml:"NbOfTxs"`
+	CtrlSum     string         `xml:"CtrlSum"`
+	ReqdExctnDt painDate       `xml:"ReqdExctnDt"`
+	Dbtr        painParty      `xml:"Dbtr"`
+	DbtrAcct    painAccount    `xml:"DbtrAcct"`
+	DbtrAgt     painAgent      `xml:"DbtrAgt"`
+	Txs         []painTransfer `xml:"CdtTrfTxInf"`
+}
+
+type painDate struct {
+	Dt string `xml:"Dt"`
+}
+
+type painParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type painAccount struct {
+	Id painAccountId `xml:"Id"`
+}
+
+type painAccountId struct {
+	IBAN string     `xml:"IBAN,omitempty"`
+	Othr *painOther `xml:"Othr,omitempty"`
+}
+
+type painOther struct {
+	Id string `xml:"Id"`
+}
+
+type painAgent struct {
+	FinInstnId painFinInstn `xml:"FinInstnId"`
+}
+
+type painFinInstn struct {
+	BICFI       string         `xml:"BICFI,omitempty"`
+	ClrSysMmbId *painClrSysMmb `xml:"ClrSysMmbId,omitempty"`
+	Othr        *painOther     `xml:"Othr,omitempty"`
+}
+
+type painClrSysMmb struct {
+	MmbId string `xml:"MmbId"`
+}
+
+type painTransfer struct {
+	PmtId    painPaymentId `xml:"PmtId"`
+	Amt      painAmount    `xml:"Amt"`
+	CdtrAgt  *painAgent    `xml:"CdtrAgt,omitempty"`
+	Cdtr     painParty     `xml:"Cdtr"`
+	CdtrAcct painAccount   `xml:"CdtrAcct"`
+	RmtInf   *painRemit    `xml:"RmtInf,omitempty"`
+}
+
+type painPaymentId struct {
+	EndToEndId string `xml:"EndToEndId"`
+}
+
+type painAmount struct {
+	InstdAmt painInstructedAmount `xml:"InstdAmt"`
+}
+
+type painInstructedAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type painRemit struct {
+	Ustrd string `xml:"Ustrd"`
+}
+
+func painAccountFor(number string) painAccount {
+	n := strings.ToUpper(strings.ReplaceAll(number, " ", ""))
+	if ibanPattern.MatchString(n) {
+		return painAccount{Id: painAccountId{IBAN: n}}
+	}
+	return painAccount{Id: painAccountId{Othr: &painOther{Id: number}}}
+}
+
+// painAgentFor identifies a bank by BIC, or by clearing system member ID for local
+// codes such as IFSC. An empty code yields the schema's "not provided" placeholder.
+func painAgentFor(code string) painAgent {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	switch {
+	case c == "":
+		return painAgent{FinInstnId: painFinInstn{Othr: &painOther{Id: "NOTPROVIDED"}}}
+	case bicPattern.MatchString(c):
+		return painAgent{FinInstnId: painFinInstn{BICFI: c}}
+	default:
+		return painAgent{FinInstnId: painFinInstn{ClrSysMmbId: &painClrSysMmb{MmbId: c}}}
+	}
+}
+
+// truncate limits s to n runes, the maximum text length allowed by the schema.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// BuildPain001 renders a customer credit transfer initiation (pain.001.001.09) with one
+// payment information block and one credit transfer per vendor payment.
+func BuildPain001(msgID, executionDate string, debtor PaymentDebtor, payments []VendorPayment, createdAt time.Time) ([]byte, error) {
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("payment file has no payments")
+	}
+	if debtor.AccountNumber == "" {
+		return nil, fmt.Errorf("debtor bank account number is required")
+	}
+
+	total := decimal.Zero
+	txs := make([]painTransfer, len(payments))
+	for i, p := range payments {
+		if p.BankAccountNumber == "" {
+			return nil, fmt.Errorf("vendor %s has no bank account number", p.VendorCode)
+		}
+		if !p.Amount.IsPositive() {
+			return nil, fmt.Errorf("payment to vendor %s must be positive", p.VendorCode)
+		}
+		total = total.Add(p.Amount)
+		tx := painTransfer{
+			PmtId:    painPaymentId{EndToEndId: truncate(fmt.Sprintf("%s-%s", msgID, p.VendorCode), 35)},
+			Amt:      painAmount{InstdAmt: painInstructedAmount{Ccy: debtor.Currency, Value: p.Amount.StringFixed(2)}},
+			Cdtr:     painParty{Nm: truncate(p.BankAccountName, 140)},
+			CdtrAcct: painAccountFor(p.BankAccountNumber),
+		}
+		if p.BankCode != "" {
+			agent := painAgentFor(p.BankCode)
+			tx.CdtrAgt = &agent
+		}
+		if len(p.References) > 0 {
+			tx.RmtInf = &painRemit{Ustrd: truncate(strings.Join(p.References, ", "), 140)}
+		}
+		txs[i] = tx
+	}
+
+	doc := painDocument{
+		Xmlns: "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09",
+		Init: painInitiate{
+			GrpHdr: painGroupHeader{
+				MsgId:    truncate(msgID, 35),
+				CreDtTm:  createdAt.UTC().Format("2006-01-02T15:04:05Z"),
+				NbOfTxs:  len(txs),
+				CtrlSum:  total.StringFixed(2),
+				InitgPty: painParty{Nm: truncate(debtor.Name, 140)},
+			},
+			PmtInf: painPaymentInfo{
+				PmtInfId:    truncate(msgID+"-1", 35),
+				PmtMtd:      "TRF",
+				NbOfTxs:     len(txs),
+				CtrlSum:     total.StringFixed(2),
+				ReqdExctnDt: painDate{Dt: executionDate},
+				Dbtr:        painParty{Nm: truncate(debtor.Name, 140)},
+				DbtrAcct:    painAccountFor(debtor.AccountNumber),
+				DbtrAgt:     painAgentFor(debtor.BankCode),
+				Txs:         txs,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode pain.001: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ── CSV ───────────────────────────────────────────────────────────────────────
+
+// BuildPaymentCSV renders a generic bank upload CSV, one row per vendor payment.
+func BuildPaymentCSV(runID int, executionDate, currency string, payments []VendorPayment) ([]byte, error) {
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("payment file has no payments")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{
+		"payment_run", "payment_date", "vendor_code", "beneficiary_name", "account_number",
+		"bank_code", "currency", "amount", "reference",
+	})
+	for _, p := range payments {
+		if p.BankAccountNumber == "" {
+			return nil, fmt.Errorf("vendor %s has no bank account number", p.VendorCode)
+		}
+		_ = w.Write([]string{
+			fmt.Sprint(runID), executionDate, p.VendorCode, p.BankAccountName, p.BankAccountNumber,
+			p.BankCode, currency, p.Amount.StringFixed(2), strings.Join(p.References, "; "),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write payment CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package core_test
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func testVendorPayments() []core.VendorPayment {
+	return []core.VendorPayment{
+		{
+			VendorCode: "V001", VendorName: "Test Supplier Ltd",
+			BankAccountName: "Test Supplier Ltd", BankAccountNumber: "50100012345678", BankCode: "HDFC0001234",
+			Amount: decimal.RequireFromString("1500.50"), References: []string{"INV-1", "ELEC-04"},
+		},
+		{
+			VendorCode: "V002", VendorName: "Euro Parts GmbH",
+			BankAccountName: "Euro Parts GmbH", BankAccountNumber: "DE89 3704 0044 0532 0130 00", BankCode: "COBADEFFXXX",
+			Amount: decimal.NewFromInt(200), References: []string{"EP-77"},
+		},
+	}
+}
+
+func TestBuildPain001(t *testing.T) {
+	debtor := core.PaymentDebtor{Name: "Test Company", AccountNumber: "00112233445566", BankCode: "ICIC0000001", Currency: "INR"}
+	created := time.Date(2026, 4, 30, 9, 15, 0, 0, time.UTC)
+
+	out, err := core.BuildPain001("PR-1000-7", "2026-05-01", debtor, testVendorPayments(), created)
+	if err != nil {
+		t.Fatalf("BuildPain001: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "<?xml") {
+		t.Error("expected XML declaration")
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.09 Document"`
+		GrpHdr  struct {
+			MsgId   string `xml:"MsgId"`
+			NbOfTxs int    `xml:"NbOfTxs"`
+			CtrlSum string `xml:"CtrlSum"`
+		} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+		PmtInf struct {
+			ReqdExctnDt string `xml:"ReqdExctnDt>Dt"`
+			DbtrAcct    string `xml:"DbtrAcct>Id>Othr>Id"`
+			DbtrMmbId   string `xml:"DbtrAgt>FinInstnId>ClrSysMmbId>MmbId"`
+			Txs         []struct {
+				EndToEndId string `xml:"PmtId>EndToEndId"`
+				Amt        struct {
+					Ccy   string `xml:"Ccy,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"Amt>InstdAmt"`
+				BIC   string `xml:"CdtrAgt>FinInstnId>BICFI"`
+				MmbId string `xml:"CdtrAgt>FinInstnId>ClrSysMmbId>MmbId"`
+				IBAN  string `xml:"CdtrAcct>Id>IBAN"`
+				Othr  string `xml:"CdtrAcct>Id>Othr>Id"`
+				Ustrd string `xml:"RmtInf>Ustrd"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"CstmrCdtTrfInitn>PmtInf"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("parse generated pain.001: %v", err)
+	}
+
+	if doc.GrpHdr.MsgId != "PR-1000-7" || doc.GrpHdr.NbOfTxs != 2 || doc.GrpHdr.CtrlSum != "1700.50" {
+		t.Errorf("group header: got MsgId %q, NbOfTxs %d, CtrlSum %s", doc.GrpHdr.MsgId, doc.GrpHdr.NbOfTxs, doc.GrpHdr.CtrlSum)
+	}
+	if doc.PmtInf.ReqdExctnDt != "2026-05-01" {
+		t.Errorf("execution date: expected 2026-05-01, got %s", doc.PmtInf.ReqdExctnDt)
+	}
+	if doc.PmtInf.DbtrAcct != "00112233445566" || doc.PmtInf.DbtrMmbId != "ICIC0000001" {
+		t.Errorf("debtor account/agent: got %q / %q", doc.PmtInf.DbtrAcct, doc.PmtInf.DbtrMmbId)
+	}
+	if len(doc.PmtInf.Txs) != 2 {
+		t.Fatalf("expected 2 credit transfers, got %d", len(doc.PmtInf.Txs))
+	}
+
+	// Local account number and IFSC.
+	tx := doc.PmtInf.Txs[0]
+	if tx.Amt.Ccy != "INR" || tx.Amt.Value != "1500.50" {
+		t.Errorf("tx 1 amount: got %s %s", tx.Amt.Ccy, tx.Amt.Value)
+	}
+	if tx.Othr != "50100012345678" || tx.MmbId != "HDFC0001234" || tx.BIC != "" {
+		t.Errorf("tx 1 creditor: got account %q, member %q, BIC %q", tx.Othr, tx.MmbId, tx.BIC)
+	}
+	if tx.Ustrd != "INV-1, ELEC-04" || tx.EndToEndId != "PR-1000-7-V001" {
+		t.Errorf("tx 1 references: got Ustrd %q, EndToEndId %q", tx.Ustrd, tx.EndToEndId)
+	}
+
+	// IBAN (spaces removed) and BIC.
+	tx = doc.PmtInf.Txs[1]
+	if tx.IBAN != "DE89370400440532013000" || tx.BIC != "COBADEFFXXX" {
+		t.Errorf("tx 2 creditor: got IBAN %q, BIC %q", tx.IBAN, tx.BIC)
+	}
+
+	t.Run("MissingVendorAccount_Fails", func(t *testing.T) {
+		payments := testVendorPayments()
+		payments[1].BankAccountNumber = ""
+		if _, err := core.BuildPain001("PR-1", "2026-05-01", debtor, payments, created); err == nil {
+			t.Error("expected error for a vendor without a bank account number, got nil")
+		}
+	})
+
+	t.Run("MissingDebtorAccount_Fails", func(t *testing.T) {
+		if _, err := core.BuildPain001("PR-1", "2026-05-01", core.PaymentDebtor{Name: "Test Company", Currency: "INR"}, testVendorPayments(), created); err == nil {
+			t.Error("expected error without a debtor account number, got nil")
+		}
+	})
+}
+
+func TestBuildPaymentCSV(t *testing.T) {
+	out, err := core.BuildPaymentCSV(7, "2026-05-01", "INR", testVendorPayments())
+	if err != nil {
+		t.Fatalf("BuildPaymentCSV: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse generated CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d records", len(records))
+	}
+	want := []string{"7", "2026-05-01", "V001", "Test Supplier Ltd", "50100012345678", "HDFC0001234", "INR", "1500.50", "INV-1; ELEC-04"}
+	for i, v := range want {
+		if records[1][i] != v {
+			t.Errorf("row 1 column %s: expected %q, got %q", records[0][i], v, records[1][i])
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPaymentRun_Lifecycle(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		UPDATE vendors SET bank_account_number = '50100012345678', bank_code = 'HDFC0001234' WHERE id = 1;
+
+		INSERT INTO vendors (id, company_id, code, name, payment_terms_days, ap_account_code, bank_account_number)
+		VALUES (2, 1, 'V002', 'Second Supplier', 15, '2000', '60200098765432');
+	`)
+	if err != nil {
+		t.Fatalf("seed payment run test data: %v", err)
+	}
+
+	companyCode := "1000"
+	billSvc := core.NewVendorBillService(pool, core.NewRuleEngine(pool), core.NewTaxEngine(pool))
+	runSvc := core.NewPaymentRunService(pool, core.NewRuleEngine(pool))
+
+	bill := func(vendor, number, date string, amount int64) *core.VendorBill {
+		t.Helper()
+		b, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+			VendorCode: vendor, BillNumber: number, BillDate: date,
+			Lines: []core.VendorBillLineInput{{Description: number, UnitCost: decimal.NewFromInt(amount), ExpenseAccountCode: "5100"}},
+		}, ledger, docService)
+		if err != nil {
+			t.Fatalf("CreateBill %s: %v", number, err)
+		}
+		return b
+	}
+	billA := bill("V001", "A-1", "2026-04-01", 1000) // due 2026-05-01 (30 days)
+	billB := bill("V002", "B-1", "2026-04-01", 400)  // due 2026-04-16 (15 days)
+	bill("V002", "B-2", "2026-05-20", 250)           // due 2026-06-04 — not due
+
+	// An invoiced PO for V001, due 2026-05-01.
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(50)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+		[]core.ReceivedLine{{POLineID: po.Lines[0].ID, QtyReceived: decimal.NewFromInt(10)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+	if _, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-PR-1", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		decimal.NewFromInt(500), nil, ledger, docService); err != nil {
+		t.Fatalf("RecordVendorInvoice: %v", err)
+	}
+
+	run, err := runSvc.CreateRun(ctx, companyCode, core.PaymentRunInput{DueBy: "2026-05-01", BankAccountCode: "1100", CreatedBy: "clerk"})
+	if err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+	if run.Status != "DRAFT" || len(run.Items) != 3 {
+		t.Fatalf("expected DRAFT run with 3 items, got %s with %d", run.Status, len(run.Items))
+	}
+	if !run.TotalAmount.Equal(decimal.NewFromInt(1900)) {
+		t.Errorf("run total: expected 1900, got %s", run.TotalAmount)
+	}
+	if run.PaymentDate != "2026-05-01" {
+		t.Errorf("payment date should default to due-by, got %s", run.PaymentDate)
+	}
+
+	t.Run("ItemsOnAnOpenRun_AreNotProposedAgain", func(t *testing.T) {
+		if _, err := runSvc.CreateRun(ctx, companyCode, core.PaymentRunInput{DueBy: "2026-05-01", BankAccountCode: "1100"}); err == nil {
+			t.Error("expected error when every due item is already on an open run, got nil")
+		}
+	})
+
+	// Hold back V002's bill.
+	var itemB int
+	for _, it := range run.Items {
+		if it.BillID != nil && *it.BillID == billB.ID {
+			itemB = it.ID
+		}
+	}
+	run, err = runSvc.SetItemExcluded(ctx, companyCode, run.ID, itemB, true)
+	if err != nil {
+		t.Fatalf("SetItemExcluded: %v", err)
+	}
+	if !run.TotalAmount.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("run total after exclusion: expected 1500, got %s", run.TotalAmount)
+	}
+
+	if _, err := runSvc.ExportRun(ctx, companyCode, run.ID, core.PaymentFormatCSV); err == nil {
+		t.Error("expected error exporting a DRAFT run, got nil")
+	}
+	if err := runSvc.PostRun(ctx, companyCode, run.ID, ledger); err == nil {
+		t.Error("expected error posting a DRAFT run, got nil")
+	}
+	if err := runSvc.ApproveRun(ctx, companyCode, run.ID, "manager"); err != nil {
+		t.Fatalf("ApproveRun: %v", err)
+	}
+	if _, err := runSvc.SetItemExcluded(ctx, companyCode, run.ID, itemB, false); err == nil {
+		t.Error("expected error changing items on an APPROVED run, got nil")
+	}
+
+	t.Run("Export", func(t *testing.T) {
+		if _, err := runSvc.ExportRun(ctx, companyCode, run.ID, core.PaymentFormatPain001); err == nil {
+			t.Error("expected error exporting without house bank details, got nil")
+		}
+		if err := runSvc.SetHouseBankDetails(ctx, companyCode, "1100", "00112233445566", "ICIC0000001"); err != nil {
+			t.Fatalf("SetHouseBankDetails: %v", err)
+		}
+		file, err := runSvc.ExportRun(ctx, companyCode, run.ID, core.PaymentFormatPain001)
+		if err != nil {
+			t.Fatalf("ExportRun pain001: %v", err)
+		}
+		xmlOut := string(file.Content)
+		if strings.Count(xmlOut, "<CdtTrfTxInf>") != 1 || !strings.Contains(xmlOut, "<CtrlSum>1500.00</CtrlSum>") {
+			t.Errorf("expected one 1500.00 transfer to V001, got:\n%s", xmlOut)
+		}
+		csvFile, err := runSvc.ExportRun(ctx, companyCode, run.ID, core.PaymentFormatCSV)
+		if err != nil {
+			t.Fatalf("ExportRun csv: %v", err)
+		}
+		if !strings.Contains(string(csvFile.Content), "V001") || strings.Contains(string(csvFile.Content), "V002") {
+			t.Errorf("CSV should contain only V001:\n%s", csvFile.Content)
+		}
+	})
+
+	if err := runSvc.PostRun(ctx, companyCode, run.ID, ledger); err != nil {
+		t.Fatalf("PostRun: %v", err)
+	}
+	posted, err := runSvc.GetRun(ctx, companyCode, run.ID)
+	if err != nil {
+		t.Fatalf("GetRun: %v", err)
+	}
+	if posted.Status != "POSTED" || posted.PostedAt == nil {
+		t.Errorf("expected POSTED run, got %s", posted.Status)
+	}
+
+	// One payment entry for V001 covering the bill and the PO.
+	var entries int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM journal_entries WHERE idempotency_key LIKE $1",
+		fmt.Sprintf("payment-run-%d-vendor-%%", run.ID),
+	).Scan(&entries); err != nil {
+		t.Fatalf("count payment entries: %v", err)
+	}
+	if entries != 1 {
+		t.Errorf("expected 1 payment entry, got %d", entries)
+	}
+
+	paidA, _ := billSvc.GetBill(ctx, companyCode, billA.ID)
+	unpaidB, _ := billSvc.GetBill(ctx, companyCode, billB.ID)
+	paidPO, _ := poService.GetPO(ctx, po.ID)
+	if paidA.Status != "PAID" || paidPO.Status != "PAID" || unpaidB.Status != "POSTED" {
+		t.Errorf("expected bill A and PO PAID, bill B POSTED; got %s, %s, %s", paidA.Status, paidPO.Status, unpaidB.Status)
+	}
+
+	var ap decimal.Decimal
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(jl.debit_base) - SUM(jl.credit_base), 0)
+		FROM journal_lines jl
+		JOIN accounts a ON a.id = jl.account_id
+		WHERE a.company_id = 1 AND a.code = '2000'`,
+	).Scan(&ap); err != nil {
+		t.Fatalf("AP balance: %v", err)
+	}
+	if !ap.Equal(decimal.NewFromInt(-650)) {
+		t.Errorf("AP after run: expected -650 (bills B-1 and B-2 open), got %s", ap)
+	}
+
+	t.Run("ExcludedItem_IsProposedOnNextRun", func(t *testing.T) {
+		next, err := runSvc.CreateRun(ctx, companyCode, core.PaymentRunInput{DueBy: "2026-05-01", BankAccountCode: "1100", VendorCode: "V002"})
+		if err != nil {
+			t.Fatalf("CreateRun: %v", err)
+		}
+		if len(next.Items) != 1 || next.Items[0].BillID == nil || *next.Items[0].BillID != billB.ID {
+			t.Errorf("expected only bill B-1 on the next run, got %+v", next.Items)
+		}
+	})
+}
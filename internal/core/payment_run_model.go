@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentRun is a batch of open AP items (invoiced purchase orders and posted vendor
+// bills, less open debit notes) selected for payment together.
+// Status: DRAFT (review; items can be excluded) → APPROVED → POSTED.
+type PaymentRun struct {
+	ID              int
+	CompanyID       int
+	Status          string // DRAFT | APPROVED | POSTED
+	DueBy           string // YYYY-MM-DD — items due on or before this date were proposed
+	PaymentDate     string // YYYY-MM-DD — posting and requested execution date
+	BankAccountCode string
+	VendorCode      *string         // vendor filter, nil = all vendors
+	TotalAmount     decimal.Decimal // sum of items not excluded
+	CreatedBy       *string
+	ApprovedBy      *string
+	ApprovedAt      *time.Time
+	PostedAt        *time.Time
+	CreatedAt       time.Time
+	Items           []PaymentRunItem
+}
+
+// PaymentRunItem is one open AP document proposed on a payment run.
+// Amount is in the company's base currency, as posted to AP; Currency is the
+// document currency, which runs only propose in the base currency. Debit note items carry a
+// negative Amount: the part of the note offset against the vendor's payment.
+type PaymentRunItem struct {
+	ID                    int
+	VendorID              int
+	VendorCode            string
+	VendorName            string
+	Source                string // PO | BILL | DEBIT_NOTE
+	POID                  *int
+	BillID                *int
+	ReturnID              *int   // purchase return behind a debit note item
+	DocumentReference     string // vendor invoice / bill number, PO number, or debit note number
+	DueDate               string // YYYY-MM-DD
+	Currency              string
+	Amount                decimal.Decimal
+	Excluded              bool
+	JournalDocumentNumber *string // vendor payment entry, set when the run posts
+}
+
+// PaymentRunInput holds the selection criteria for a new payment run.
+type PaymentRunInput struct {
+	DueBy           string // YYYY-MM-DD
+	PaymentDate     string // YYYY-MM-DD, defaults to DueBy
+	BankAccountCode string
+	VendorCode      string // optional
+	CreatedBy       string
+}
+
+// PaymentFile is a bank payment file generated from an approved payment run.
+type PaymentFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Payment file formats accepted by PaymentRunService.ExportRun.
+const (
+	PaymentFormatPain001 = "pain001"
+	PaymentFormatCSV     = "csv"
+)
+
+// PaymentRunService selects, approves and posts batch vendor payments.
+type PaymentRunService interface {
+	// CreateRun proposes every open AP item due on or before input.DueBy that is not
+	// already on another unposted run, and offsets the selected vendors' open debit notes
+	// against them (never beyond what a vendor is paid). Status is DRAFT.
+	CreateRun(ctx context.Context, companyCode string, input PaymentRunInput) (*PaymentRun, error)
+
+	// GetRuns returns a company's payment runs, newest first, optionally filtered by status (headers only).
+	GetRuns(ctx context.Context, companyCode, status string) ([]PaymentRun, error)
+
+	// GetRun returns one payment run with its items.
+	GetRun(ctx context.Context, companyCode string, runID int) (*PaymentRun, error)
+
+	// SetItemExcluded excludes an item from (or re-includes it in) a DRAFT run.
+	SetItemExcluded(ctx context.Context, companyCode string, runID, itemID int, excluded bool) (*PaymentRun, error)
+
+	// ApproveRun moves a DRAFT run with at least one included item to APPROVED.
+	ApproveRun(ctx context.Context, companyCode string, runID int, approvedBy string) error
+
+	// PostRun posts one payment entry per vendor (DR AP / CR bank, net of debit notes) for
+	// an APPROVED run, marks the paid purchase orders and bills PAID, applies the debit
+	// notes, and moves the run to POSTED. For vendors with a TDS section, TDS is withheld
+	// from the vendor's payment and credited to TDS_PAYABLE.
+	PostRun(ctx context.Context, companyCode string, runID int, ledger *Ledger) error
+
+	// ExportRun builds the bank file for an APPROVED or POSTED run in the given format
+	// (PaymentFormatPain001 or PaymentFormatCSV), one payment per vendor, net of TDS.
+	ExportRun(ctx context.Context, companyCode string, runID int, format string) (*PaymentFile, error)
+
+	// SetHouseBankDetails records the bank account behind a bank GL account, used as the
+	// debtor account in payment files.
+	SetHouseBankDetails(ctx context.Context, companyCode, accountCode, accountNumber, bankCode string) error
+}
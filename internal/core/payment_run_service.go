@@ -0,0 +1,869 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type paymentRunService struct {
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+}
+
+// NewPaymentRunService constructs a PaymentRunService backed by PostgreSQL.
+func NewPaymentRunService(pool *pgxpool.Pool, ruleEngine RuleEngine) PaymentRunService {
+	return &paymentRunService{pool: pool, ruleEngine: ruleEngine}
+}
+
+// openAPItemsQuery lists a company's open AP documents with their due dates:
+// INVOICED, unblocked purchase orders (due invoice date + vendor terms), POSTED
+// vendor bills, and OPEN debit notes from purchase returns as negative amounts (due on
+// the return date). Documents already proposed on an unposted run are left out.
+// Runs pay in the company base currency: foreign-currency purchase orders are paid
+// individually with PayVendor.
+// $1 = company_id.
+const openAPItemsQuery = `
+	WITH open_items AS (
+		SELECT po.vendor_id, po.id AS po_id, NULL::int AS bill_id, NULL::int AS return_id,
+		       COALESCE(po.invoice_number, po.po_number, 'PO-' || po.id) AS document_reference,
+		       COALESCE(po.invoice_date, po.po_date) + v.payment_terms_days AS due_date,
+		       po.currency,
+		       COALESCE(NULLIF(po.invoice_amount, 0), po.total_transaction) AS amount
+		FROM purchase_orders po
+		JOIN vendors v ON v.id = po.vendor_id
+		JOIN companies c ON c.id = po.company_id
+		WHERE po.company_id = $1 AND po.status = 'INVOICED' AND NOT po.payment_blocked
+		  AND po.currency = c.base_currency
+		UNION ALL
+		SELECT vb.vendor_id, NULL::int, vb.id, NULL::int, vb.bill_number, vb.due_date, c.base_currency, vb.total_amount
+		FROM vendor_bills vb
+		JOIN companies c ON c.id = vb.company_id
+		WHERE vb.company_id = $1 AND vb.status = 'POSTED'
+		UNION ALL
+		SELECT pr.vendor_id, NULL::int, NULL::int, pr.id, COALESCE(pr.debit_note_number, 'DN-' || pr.id),
+		       pr.return_date, c.base_currency, -(pr.amount - pr.applied_amount)
+		FROM purchase_returns pr
+		JOIN companies c ON c.id = pr.company_id
+		WHERE pr.company_id = $1 AND pr.status = 'OPEN'
+	)
+	SELECT oi.vendor_id, oi.po_id, oi.bill_id, oi.return_id, oi.document_reference, oi.due_date, oi.currency, oi.amount
+	FROM open_items oi
+	WHERE NOT EXISTS (
+		SELECT 1
+		FROM payment_run_items pri
+		JOIN payment_runs pr ON pr.id = pri.run_id
+		WHERE pr.status IN ('DRAFT', 'APPROVED') AND NOT pri.excluded
+		  AND (pri.po_id = oi.po_id OR pri.bill_id = oi.bill_id OR pri.return_id = oi.return_id)
+	)`
+
+// ── CreateRun ─────────────────────────────────────────────────────────────────
+
+func (s *paymentRunService) CreateRun(ctx context.Context, companyCode string, input PaymentRunInput) (*PaymentRun, error) {
+	if _, err := time.Parse("2006-01-02", input.DueBy); err != nil {
+		return nil, fmt.Errorf("invalid due-by date %q (expected YYYY-MM-DD)", input.DueBy)
+	}
+	paymentDate := input.PaymentDate
+	if paymentDate == "" {
+		paymentDate = input.DueBy
+	}
+	if _, err := time.Parse("2006-01-02", paymentDate); err != nil {
+		return nil, fmt.Errorf("invalid payment date %q (expected YYYY-MM-DD)", paymentDate)
+	}
+	bankAccount := strings.TrimSpace(input.BankAccountCode)
+	if bankAccount == "" {
+		return nil, fmt.Errorf("bank account code is required")
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var companyID int
+	if err := tx.QueryRow(ctx,
+		"SELECT id FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("company %s not found", companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+
+	var accountExists bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM accounts WHERE company_id = $1 AND code = $2)",
+		companyID, bankAccount,
+	).Scan(&accountExists); err != nil {
+		return nil, fmt.Errorf("check bank account: %w", err)
+	}
+	if !accountExists {
+		return nil, fmt.Errorf("bank account %s not found", bankAccount)
+	}
+
+	var vendorID *int
+	if input.VendorCode != "" {
+		var id int
+		if err := tx.QueryRow(ctx,
+			"SELECT id FROM vendors WHERE company_id = $1 AND code = $2", companyID, input.VendorCode,
+		).Scan(&id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("vendor %s not found", input.VendorCode)
+			}
+			return nil, fmt.Errorf("resolve vendor: %w", err)
+		}
+		vendorID = &id
+	}
+
+	var runID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment_runs (company_id, due_by, payment_date, bank_account_code, vendor_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		companyID, input.DueBy, paymentDate, bankAccount, vendorID, nullIfEmpty(input.CreatedBy),
+	).Scan(&runID); err != nil {
+		return nil, fmt.Errorf("insert payment run: %w", err)
+	}
+
+	// Open debit notes are proposed whatever their date; capDebitNotesTx then limits
+	// them to what each vendor's payables on the run can absorb.
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO payment_run_items (run_id, vendor_id, po_id, bill_id, return_id, document_reference, due_date, currency, amount)
+		SELECT $2, ai.vendor_id, ai.po_id, ai.bill_id, ai.return_id, ai.document_reference, ai.due_date, ai.currency, ai.amount
+		FROM (`+openAPItemsQuery+`) ai
+		WHERE (ai.due_date <= $3 OR ai.return_id IS NOT NULL)
+		  AND ($4::int IS NULL OR ai.vendor_id = $4)
+		ORDER BY ai.vendor_id, ai.due_date`,
+		companyID, runID, input.DueBy, vendorID,
+	); err != nil {
+		return nil, fmt.Errorf("select open AP items: %w", err)
+	}
+	if err := capDebitNotesTx(ctx, tx, runID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM payment_run_items WHERE run_id = $1 AND return_id IS NOT NULL AND amount = 0", runID,
+	); err != nil {
+		return nil, fmt.Errorf("drop unused debit notes: %w", err)
+	}
+	var proposed int
+	if err := tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM payment_run_items WHERE run_id = $1 AND return_id IS NULL", runID,
+	).Scan(&proposed); err != nil {
+		return nil, fmt.Errorf("count payment run items: %w", err)
+	}
+	if proposed == 0 {
+		return nil, fmt.Errorf("no open AP items due by %s match the selection", input.DueBy)
+	}
+	if err := updateRunTotalTx(ctx, tx, runID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit payment run: %w", err)
+	}
+	return s.GetRun(ctx, companyCode, runID)
+}
+
+// updateRunTotalTx recomputes payment_runs.total_amount from the included items.
+func updateRunTotalTx(ctx context.Context, tx pgx.Tx, runID int) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment_runs
+		SET total_amount = (SELECT COALESCE(SUM(amount), 0) FROM payment_run_items WHERE run_id = $1 AND NOT excluded)
+		WHERE id = $1`, runID,
+	); err != nil {
+		return fmt.Errorf("update payment run total: %w", err)
+	}
+	return nil
+}
+
+// capDebitNotesTx sets each included debit note item on a run to the part of its open
+// balance that the vendor's included payables on the run can absorb, oldest first, so
+// that no vendor's payment goes negative.
+func capDebitNotesTx(ctx context.Context, tx pgx.Tx, runID int) error {
+	remaining := map[int]decimal.Decimal{}
+	rows, err := tx.Query(ctx, `
+		SELECT vendor_id, SUM(amount)
+		FROM payment_run_items
+		WHERE run_id = $1 AND return_id IS NULL AND NOT excluded
+		GROUP BY vendor_id`, runID)
+	if err != nil {
+		return fmt.Errorf("sum payables on payment run %d: %w", runID, err)
+	}
+	for rows.Next() {
+		var vendorID int
+		var payable decimal.Decimal
+		if err := rows.Scan(&vendorID, &payable); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan vendor payable: %w", err)
+		}
+		remaining[vendorID] = payable
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate vendor payables: %w", err)
+	}
+
+	type noteItem struct {
+		id       int
+		vendorID int
+		open     decimal.Decimal
+	}
+	var notes []noteItem
+	rows, err = tx.Query(ctx, `
+		SELECT pri.id, pri.vendor_id, pr.amount - pr.applied_amount
+		FROM payment_run_items pri
+		JOIN purchase_returns pr ON pr.id = pri.return_id
+		WHERE pri.run_id = $1 AND NOT pri.excluded
+		ORDER BY pri.vendor_id, pri.due_date, pri.id`, runID)
+	if err != nil {
+		return fmt.Errorf("load debit notes on payment run %d: %w", runID, err)
+	}
+	for rows.Next() {
+		var n noteItem
+		if err := rows.Scan(&n.id, &n.vendorID, &n.open); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan debit note item: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate debit note items: %w", err)
+	}
+
+	for _, n := range notes {
+		amount := decimal.Max(decimal.Min(n.open, remaining[n.vendorID]), decimal.Zero)
+		remaining[n.vendorID] = remaining[n.vendorID].Sub(amount)
+		if _, err := tx.Exec(ctx,
+			"UPDATE payment_run_items SET amount = $1 WHERE id = $2", amount.Neg(), n.id,
+		); err != nil {
+			return fmt.Errorf("update debit note item %d: %w", n.id, err)
+		}
+	}
+	return nil
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ── Review and approval ───────────────────────────────────────────────────────
+
+// lockRunTx locks a payment run row for update and returns its status.
+func lockRunTx(ctx context.Context, tx pgx.Tx, companyCode string, runID int) (string, error) {
+	var status string
+	if err := tx.QueryRow(ctx, `
+		SELECT pr.status
+		FROM payment_runs pr
+		JOIN companies c ON c.id = pr.company_id
+		WHERE pr.id = $1 AND c.company_code = $2
+		FOR UPDATE OF pr`,
+		runID, companyCode,
+	).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("payment run %d not found", runID)
+		}
+		return "", fmt.Errorf("fetch payment run %d: %w", runID, err)
+	}
+	return status, nil
+}
+
+func (s *paymentRunService) SetItemExcluded(ctx context.Context, companyCode string, runID, itemID int, excluded bool) (*PaymentRun, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status, err := lockRunTx(ctx, tx, companyCode, runID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "DRAFT" {
+		return nil, fmt.Errorf("payment run %d cannot be changed: status is %s (must be DRAFT)", runID, status)
+	}
+
+	if !excluded {
+		// The item may have been picked up by another run while it was excluded here.
+		var onOtherRun bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1
+				FROM payment_run_items mine
+				JOIN payment_run_items other
+				  ON (other.po_id = mine.po_id OR other.bill_id = mine.bill_id OR other.return_id = mine.return_id)
+				JOIN payment_runs pr ON pr.id = other.run_id
+				WHERE mine.id = $1 AND other.run_id <> mine.run_id
+				  AND pr.status IN ('DRAFT', 'APPROVED') AND NOT other.excluded
+			)`, itemID,
+		).Scan(&onOtherRun); err != nil {
+			return nil, fmt.Errorf("check payment run item %d: %w", itemID, err)
+		}
+		if onOtherRun {
+			return nil, fmt.Errorf("item %d is already proposed on another payment run", itemID)
+		}
+	}
+
+	tag, err := tx.Exec(ctx,
+		"UPDATE payment_run_items SET excluded = $1 WHERE id = $2 AND run_id = $3",
+		excluded, itemID, runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update payment run item %d: %w", itemID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("item %d not found on payment run %d", itemID, runID)
+	}
+	if err := capDebitNotesTx(ctx, tx, runID); err != nil {
+		return nil, err
+	}
+	if err := updateRunTotalTx(ctx, tx, runID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit payment run item: %w", err)
+	}
+	return s.GetRun(ctx, companyCode, runID)
+}
+
+func (s *paymentRunService) ApproveRun(ctx context.Context, companyCode string, runID int, approvedBy string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status, err := lockRunTx(ctx, tx, companyCode, runID)
+	if err != nil {
+		return err
+	}
+	if status != "DRAFT" {
+		return fmt.Errorf("payment run %d cannot be approved: status is %s (must be DRAFT)", runID, status)
+	}
+
+	var included int
+	if err := tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM payment_run_items WHERE run_id = $1 AND return_id IS NULL AND NOT excluded", runID,
+	).Scan(&included); err != nil {
+		return fmt.Errorf("count payment run items: %w", err)
+	}
+	if included == 0 {
+		return fmt.Errorf("payment run %d has no included items", runID)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment_runs SET status = 'APPROVED', approved_by = $1, approved_at = NOW()
+		WHERE id = $2`,
+		nullIfEmpty(approvedBy), runID,
+	); err != nil {
+		return fmt.Errorf("approve payment run %d: %w", runID, err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ── PostRun ───────────────────────────────────────────────────────────────────
+
+func (s *paymentRunService) PostRun(ctx context.Context, companyCode string, runID int, ledger *Ledger) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status, err := lockRunTx(ctx, tx, companyCode, runID)
+	if err != nil {
+		return err
+	}
+	if status != "APPROVED" {
+		return fmt.Errorf("payment run %d cannot be posted: status is %s (must be APPROVED)", runID, status)
+	}
+
+	var companyID int
+	var paymentDate, bankAccount, baseCurrency string
+	if err := tx.QueryRow(ctx, `
+		SELECT pr.company_id, pr.payment_date::text, pr.bank_account_code, c.base_currency
+		FROM payment_runs pr
+		JOIN companies c ON c.id = pr.company_id
+		WHERE pr.id = $1`, runID,
+	).Scan(&companyID, &paymentDate, &bankAccount, &baseCurrency); err != nil {
+		return fmt.Errorf("fetch payment run %d: %w", runID, err)
+	}
+	paymentTime, err := time.Parse("2006-01-02", paymentDate)
+	if err != nil {
+		return fmt.Errorf("parse payment date of run %d: %w", runID, err)
+	}
+
+	type postItem struct {
+		id        int
+		vendorID  int
+		vendor    string
+		poID      *int
+		billID    *int
+		returnID  *int
+		reference string
+		amount    decimal.Decimal
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT pri.id, pri.vendor_id, v.name, pri.po_id, pri.bill_id, pri.return_id, pri.document_reference, pri.amount
+		FROM payment_run_items pri
+		JOIN vendors v ON v.id = pri.vendor_id
+		WHERE pri.run_id = $1 AND NOT pri.excluded
+		ORDER BY v.code, pri.id`, runID)
+	if err != nil {
+		return fmt.Errorf("query payment run items: %w", err)
+	}
+	var items []postItem
+	for rows.Next() {
+		var it postItem
+		if err := rows.Scan(&it.id, &it.vendorID, &it.vendor, &it.poID, &it.billID, &it.returnID, &it.reference, &it.amount); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan payment run item: %w", err)
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate payment run items: %w", err)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("payment run %d has no included items", runID)
+	}
+
+	// Items arrive grouped by vendor; post one entry per vendor.
+	for start := 0; start < len(items); {
+		end := start
+		for end < len(items) && items[end].vendorID == items[start].vendorID {
+			end++
+		}
+		group := items[start:end]
+		start = end
+
+		// AP lines are netted per AP account, in first-seen order; debit notes
+		// (negative items) reduce the debit.
+		var apOrder []string
+		byAP := map[string]decimal.Decimal{}
+		total, tdsBase := decimal.Zero, decimal.Zero
+		refs := make([]string, 0, len(group))
+		for _, it := range group {
+			apAccount, err := lockPayableTx(ctx, tx, it.poID, it.billID, it.returnID, it.amount)
+			if err != nil {
+				return fmt.Errorf("item %s: %w", it.reference, err)
+			}
+			if _, ok := byAP[apAccount]; !ok {
+				apOrder = append(apOrder, apAccount)
+			}
+			byAP[apAccount] = byAP[apAccount].Add(it.amount)
+			total = total.Add(it.amount)
+			base, err := payableTDSBaseQ(ctx, tx, it.poID, it.billID, it.returnID, it.amount)
+			if err != nil {
+				return fmt.Errorf("item %s: %w", it.reference, err)
+			}
+			tdsBase = tdsBase.Add(base)
+			refs = append(refs, it.reference)
+		}
+
+		// TDS is withheld from the vendor's net payment, on its value excluding GST.
+		var tds *tdsWithholding
+		if total.IsPositive() && tdsBase.IsPositive() {
+			tds, err = vendorTDSTx(ctx, tx, companyID, group[0].vendorID, tdsBase, paymentTime, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		lines := make([]ProposalLine, 0, len(apOrder)+2)
+		for _, acc := range apOrder {
+			if amt := byAP[acc]; !amt.IsZero() {
+				lines = append(lines, ProposalLine{AccountCode: acc, IsDebit: amt.IsPositive(), Amount: amt.Abs().StringFixed(2)})
+			}
+		}
+		if bank := tds.netOf(total); bank.IsPositive() {
+			lines = append(lines, ProposalLine{AccountCode: bankAccount, IsDebit: false, Amount: bank.StringFixed(2)})
+		}
+		tdsLine, err := tds.tdsPayableLine(ctx, s.ruleEngine)
+		if err != nil {
+			return err
+		}
+		reasoning := fmt.Sprintf("Batch vendor payment for %s.", strings.Join(refs, ", "))
+		if tdsLine != nil {
+			lines = append(lines, *tdsLine)
+			reasoning += fmt.Sprintf(" TDS of %s withheld under %s.", tds.TDSAmount.StringFixed(2), tds.section.Code)
+		}
+
+		// A vendor whose debit notes cover everything due is settled without a payment entry.
+		idempotencyKey := fmt.Sprintf("payment-run-%d-vendor-%d", runID, group[0].vendorID)
+		if len(lines) > 0 {
+			proposal := Proposal{
+				DocumentTypeCode:    "JE",
+				CompanyCode:         companyCode,
+				IdempotencyKey:      idempotencyKey,
+				TransactionCurrency: baseCurrency,
+				ExchangeRate:        "1",
+				Summary:             fmt.Sprintf("Payment run %d: %s", runID, group[0].vendor),
+				PostingDate:         paymentDate,
+				DocumentDate:        paymentDate,
+				Confidence:          1.0,
+				Reasoning:           reasoning,
+				Lines:               lines,
+			}
+			if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+				return fmt.Errorf("post payment for vendor %s: %w", group[0].vendor, err)
+			}
+			if err := tds.record(ctx, tx, tdsSource{runID: &runID}, idempotencyKey); err != nil {
+				return err
+			}
+		}
+
+		for _, it := range group {
+			var err error
+			switch {
+			case it.poID != nil:
+				_, err = tx.Exec(ctx, "UPDATE purchase_orders SET status = 'PAID', paid_at = NOW() WHERE id = $1", *it.poID)
+			case it.billID != nil:
+				_, err = tx.Exec(ctx, "UPDATE vendor_bills SET status = 'PAID', paid_at = NOW() WHERE id = $1", *it.billID)
+			case it.amount.IsNegative():
+				err = applyDebitNoteTx(ctx, tx, *it.returnID, it.amount.Neg(), nil, &runID)
+			}
+			if err != nil {
+				return fmt.Errorf("mark %s paid: %w", it.reference, err)
+			}
+			if _, err := tx.Exec(ctx, `
+				UPDATE payment_run_items
+				SET journal_document_number = (SELECT reference_id FROM journal_entries WHERE idempotency_key = $1)
+				WHERE id = $2`,
+				idempotencyKey, it.id,
+			); err != nil {
+				return fmt.Errorf("set payment document number: %w", err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE payment_runs SET status = 'POSTED', posted_at = NOW() WHERE id = $1", runID,
+	); err != nil {
+		return fmt.Errorf("post payment run %d: %w", runID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit payment run: %w", err)
+	}
+	return nil
+}
+
+// lockPayableTx locks the purchase order, vendor bill or debit note behind a run item,
+// checks it is still payable (for a debit note: still open for at least the item's
+// offset), and returns the AP account it was posted to.
+func lockPayableTx(ctx context.Context, tx pgx.Tx, poID, billID, returnID *int, amount decimal.Decimal) (string, error) {
+	var status, apAccount string
+	if returnID != nil {
+		var open decimal.Decimal
+		if err := tx.QueryRow(ctx,
+			"SELECT status, ap_account_code, amount - applied_amount FROM purchase_returns WHERE id = $1 FOR UPDATE", *returnID,
+		).Scan(&status, &apAccount, &open); err != nil {
+			return "", fmt.Errorf("fetch debit note %d: %w", *returnID, err)
+		}
+		if amount.IsNegative() && (status != "OPEN" || open.LessThan(amount.Neg())) {
+			return "", fmt.Errorf("debit note %d no longer has %s open", *returnID, amount.Neg().StringFixed(2))
+		}
+		return apAccount, nil
+	}
+	if poID != nil {
+		var blocked bool
+		if err := tx.QueryRow(ctx, `
+			SELECT po.status, po.payment_blocked, COALESCE(v.ap_account_code, '2000')
+			FROM purchase_orders po
+			JOIN vendors v ON v.id = po.vendor_id
+			WHERE po.id = $1
+			FOR UPDATE OF po`, *poID,
+		).Scan(&status, &blocked, &apAccount); err != nil {
+			return "", fmt.Errorf("fetch purchase order %d: %w", *poID, err)
+		}
+		if status != "INVOICED" {
+			return "", fmt.Errorf("purchase order %d is no longer payable: status is %s", *poID, status)
+		}
+		if blocked {
+			return "", fmt.Errorf("purchase order %d is blocked for payment", *poID)
+		}
+		return apAccount, nil
+	}
+
+	if err := tx.QueryRow(ctx,
+		"SELECT status, ap_account_code FROM vendor_bills WHERE id = $1 FOR UPDATE", *billID,
+	).Scan(&status, &apAccount); err != nil {
+		return "", fmt.Errorf("fetch vendor bill %d: %w", *billID, err)
+	}
+	if status != "POSTED" {
+		return "", fmt.Errorf("vendor bill %d is no longer payable: status is %s", *billID, status)
+	}
+	return apAccount, nil
+}
+
+// ── ExportRun ─────────────────────────────────────────────────────────────────
+
+func (s *paymentRunService) ExportRun(ctx context.Context, companyCode string, runID int, format string) (*PaymentFile, error) {
+	run, err := s.GetRun(ctx, companyCode, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != "APPROVED" && run.Status != "POSTED" {
+		return nil, fmt.Errorf("payment run %d cannot be exported: status is %s (must be APPROVED or POSTED)", runID, run.Status)
+	}
+
+	debtor := PaymentDebtor{}
+	var accountNumber, bankCode *string
+	if err := s.pool.QueryRow(ctx, `
+		SELECT c.name, c.base_currency, a.bank_account_number, a.bank_code
+		FROM companies c
+		JOIN accounts a ON a.company_id = c.id AND a.code = $2
+		WHERE c.company_code = $1`,
+		companyCode, run.BankAccountCode,
+	).Scan(&debtor.Name, &debtor.Currency, &accountNumber, &bankCode); err != nil {
+		return nil, fmt.Errorf("fetch house bank for account %s: %w", run.BankAccountCode, err)
+	}
+	if accountNumber == nil || *accountNumber == "" {
+		return nil, fmt.Errorf("bank account %s has no bank account number; set its house bank details first", run.BankAccountCode)
+	}
+	debtor.AccountNumber = *accountNumber
+	if bankCode != nil {
+		debtor.BankCode = *bankCode
+	}
+
+	payments, err := s.vendorPayments(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &PaymentFile{}
+	switch format {
+	case PaymentFormatPain001, "":
+		file.Filename = fmt.Sprintf("payment-run-%d.xml", runID)
+		file.ContentType = "application/xml"
+		file.Content, err = BuildPain001(fmt.Sprintf("PR-%s-%d", companyCode, runID), run.PaymentDate, debtor, payments, time.Now())
+	case PaymentFormatCSV:
+		file.Filename = fmt.Sprintf("payment-run-%d.csv", runID)
+		file.ContentType = "text/csv"
+		file.Content, err = BuildPaymentCSV(runID, run.PaymentDate, debtor.Currency, payments)
+	default:
+		return nil, fmt.Errorf("unknown payment file format %q (expected %s or %s)", format, PaymentFormatPain001, PaymentFormatCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// vendorPayments groups a run's included items into one payment per vendor, with the
+// vendor's bank details. Amounts are net of TDS: the TDS recorded when a POSTED run was
+// posted, or what posting an APPROVED run would withhold today. Vendors settled in full
+// by debit notes are left out; every other vendor must have a bank account number.
+func (s *paymentRunService) vendorPayments(ctx context.Context, run *PaymentRun) ([]VendorPayment, error) {
+	var payments []VendorPayment
+	index := map[int]int{}
+	tdsBase := map[int]decimal.Decimal{}
+	for _, it := range run.Items {
+		if it.Excluded || it.Amount.IsZero() {
+			continue
+		}
+		i, ok := index[it.VendorID]
+		if !ok {
+			i = len(payments)
+			index[it.VendorID] = i
+			payments = append(payments, VendorPayment{VendorCode: it.VendorCode, VendorName: it.VendorName})
+		}
+		payments[i].Amount = payments[i].Amount.Add(it.Amount)
+		payments[i].References = append(payments[i].References, it.DocumentReference)
+		if run.Status != "POSTED" {
+			base, err := payableTDSBaseQ(ctx, s.pool, it.POID, it.BillID, it.ReturnID, it.Amount)
+			if err != nil {
+				return nil, err
+			}
+			tdsBase[it.VendorID] = tdsBase[it.VendorID].Add(base)
+		}
+	}
+
+	var missing []string
+	for vendorID, i := range index {
+		if !payments[i].Amount.IsPositive() {
+			continue
+		}
+		var name, number, code *string
+		if err := s.pool.QueryRow(ctx,
+			"SELECT bank_account_name, bank_account_number, bank_code FROM vendors WHERE id = $1", vendorID,
+		).Scan(&name, &number, &code); err != nil {
+			return nil, fmt.Errorf("fetch bank details for vendor %s: %w", payments[i].VendorCode, err)
+		}
+		if number == nil || *number == "" {
+			missing = append(missing, payments[i].VendorCode)
+			continue
+		}
+		tds, err := s.runTDS(ctx, run, vendorID, tdsBase[vendorID])
+		if err != nil {
+			return nil, fmt.Errorf("TDS for vendor %s: %w", payments[i].VendorCode, err)
+		}
+		payments[i].Amount = payments[i].Amount.Sub(tds)
+		payments[i].BankAccountNumber = *number
+		payments[i].BankAccountName = payments[i].VendorName
+		if name != nil && *name != "" {
+			payments[i].BankAccountName = *name
+		}
+		if code != nil {
+			payments[i].BankCode = *code
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("vendors without a bank account number: %s", strings.Join(missing, ", "))
+	}
+
+	paid := payments[:0]
+	for _, p := range payments {
+		if p.Amount.IsPositive() {
+			paid = append(paid, p)
+		}
+	}
+	return paid, nil
+}
+
+// runTDS returns the TDS withheld from a vendor's payment on a run: as recorded for a
+// POSTED run, otherwise as it would be computed on the run's payment date for a payment
+// of base excluding GST.
+func (s *paymentRunService) runTDS(ctx context.Context, run *PaymentRun, vendorID int, base decimal.Decimal) (decimal.Decimal, error) {
+	if run.Status == "POSTED" {
+		var tds decimal.Decimal
+		err := s.pool.QueryRow(ctx,
+			"SELECT COALESCE(SUM(tds_amount), 0) FROM tds_deductions WHERE run_id = $1 AND vendor_id = $2",
+			run.ID, vendorID,
+		).Scan(&tds)
+		return tds, err
+	}
+	paymentDate, err := time.Parse("2006-01-02", run.PaymentDate)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("parse payment date: %w", err)
+	}
+	if !base.IsPositive() {
+		return decimal.Zero, nil
+	}
+	w, err := vendorTDSTx(ctx, s.pool, run.CompanyID, vendorID, base, paymentDate, false)
+	if err != nil || w == nil {
+		return decimal.Zero, err
+	}
+	return w.TDSAmount, nil
+}
+
+// ── SetHouseBankDetails ───────────────────────────────────────────────────────
+
+func (s *paymentRunService) SetHouseBankDetails(ctx context.Context, companyCode, accountCode, accountNumber, bankCode string) error {
+	if strings.TrimSpace(accountNumber) == "" {
+		return fmt.Errorf("bank account number is required")
+	}
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE accounts a
+		SET bank_account_number = $3, bank_code = $4
+		FROM companies c
+		WHERE c.id = a.company_id AND c.company_code = $1 AND a.code = $2`,
+		companyCode, accountCode, strings.TrimSpace(accountNumber), nullIfEmpty(strings.TrimSpace(bankCode)),
+	)
+	if err != nil {
+		return fmt.Errorf("set house bank details for account %s: %w", accountCode, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("account %s not found", accountCode)
+	}
+	return nil
+}
+
+// ── Queries ───────────────────────────────────────────────────────────────────
+
+const paymentRunSelect = `
+	SELECT pr.id, pr.company_id, pr.status, pr.due_by::text, pr.payment_date::text, pr.bank_account_code,
+	       v.code, pr.total_amount, pr.created_by, pr.approved_by, pr.approved_at,
+	       pr.posted_at, pr.created_at
+	FROM payment_runs pr
+	JOIN companies c     ON c.id = pr.company_id
+	LEFT JOIN vendors v  ON v.id = pr.vendor_id`
+
+func scanPaymentRun(row pgx.Row) (PaymentRun, error) {
+	var r PaymentRun
+	err := row.Scan(&r.ID, &r.CompanyID, &r.Status, &r.DueBy, &r.PaymentDate, &r.BankAccountCode,
+		&r.VendorCode, &r.TotalAmount, &r.CreatedBy, &r.ApprovedBy, &r.ApprovedAt,
+		&r.PostedAt, &r.CreatedAt)
+	return r, err
+}
+
+func (s *paymentRunService) GetRuns(ctx context.Context, companyCode, status string) ([]PaymentRun, error) {
+	rows, err := s.pool.Query(ctx, paymentRunSelect+`
+		WHERE c.company_code = $1 AND ($2 = '' OR pr.status = $2)
+		ORDER BY pr.id DESC`,
+		companyCode, strings.ToUpper(status),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query payment runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []PaymentRun
+	for rows.Next() {
+		r, err := scanPaymentRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan payment run: %w", err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+func (s *paymentRunService) GetRun(ctx context.Context, companyCode string, runID int) (*PaymentRun, error) {
+	r, err := scanPaymentRun(s.pool.QueryRow(ctx, paymentRunSelect+`
+		WHERE c.company_code = $1 AND pr.id = $2`, companyCode, runID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("payment run %d not found", runID)
+		}
+		return nil, fmt.Errorf("fetch payment run: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT pri.id, pri.vendor_id, v.code, v.name, pri.po_id, pri.bill_id, pri.return_id, pri.document_reference,
+		       pri.due_date::text, pri.currency, pri.amount, pri.excluded, pri.journal_document_number
+		FROM payment_run_items pri
+		JOIN vendors v ON v.id = pri.vendor_id
+		WHERE pri.run_id = $1
+		ORDER BY v.code, pri.due_date, pri.id`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("query payment run items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var it PaymentRunItem
+		if err := rows.Scan(&it.ID, &it.VendorID, &it.VendorCode, &it.VendorName, &it.POID, &it.BillID, &it.ReturnID,
+			&it.DocumentReference, &it.DueDate, &it.Currency, &it.Amount, &it.Excluded,
+			&it.JournalDocumentNumber); err != nil {
+			return nil, fmt.Errorf("scan payment run item: %w", err)
+		}
+		switch {
+		case it.POID != nil:
+			it.Source = "PO"
+		case it.BillID != nil:
+			it.Source = "BILL"
+		default:
+			it.Source = "DEBIT_NOTE"
+		}
+		r.Items = append(r.Items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate payment run items: %w", err)
+	}
+	return &r, nil
+}
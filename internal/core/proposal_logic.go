@@ -31,6 +31,7 @@ func (p *Proposal) Normalize() {
 		if strings.TrimSpace(line.Amount) == "" || strings.ToLower(line.Amount) == "null" {
 			line.Amount = "0.00"
 		}
+		line.Dimensions = NormalizeLineDimensions(line.Dimensions)
 	}
 }
 
@@ -94,6 +95,9 @@ func (p *Proposal) Validate() error {
 		if amt.IsZero() {
 			return fmt.Errorf("amount must be > 0 for account %s", line.AccountCode)
 		}
+		if err := ValidateLineDimensions(line.Dimensions); err != nil {
+			return fmt.Errorf("account %s: %w", line.AccountCode, err)
+		}
 
 		// Base amount = transaction amount × header exchange rate
 		baseAmt := amt.Mul(rate)
@@ -0,0 +1,187 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPurchaseOrder_ThreeWayMatch(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank Account',            'asset'),
+		(1, '5400', 'Purchase Price Variance', 'expense')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO account_rules (company_id, rule_type, account_code) VALUES
+		(1, 'PURCHASE_PRICE_VARIANCE', '5400')
+		ON CONFLICT DO NOTHING;
+
+		DELETE FROM purchase_match_tolerances;
+	`)
+	if err != nil {
+		t.Fatalf("seed three-way match test data: %v", err)
+	}
+
+	companyCode := "1000"
+	today := time.Now()
+	todayStr := today.Format("2006-01-02")
+
+	// receivedPO creates, approves and receives a PO for 10 × P001 @ 100, receiving qty.
+	receivedPO := func(t *testing.T, qty int64) *core.PurchaseOrder {
+		t.Helper()
+		po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, today, []core.PurchaseOrderLineInput{
+			{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(100)},
+		}, "")
+		if err != nil {
+			t.Fatalf("CreatePO: %v", err)
+		}
+		if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+			t.Fatalf("ApprovePO: %v", err)
+		}
+		if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+			[]core.ReceivedLine{{POLineID: po.Lines[0].ID, QtyReceived: decimal.NewFromInt(qty)}},
+			"2000", ledger, docService, invSvc); err != nil {
+			t.Fatalf("ReceivePO: %v", err)
+		}
+		return po
+	}
+
+	balance := func(accountCode string) decimal.Decimal {
+		var bal decimal.Decimal
+		if err := pool.QueryRow(ctx, `
+			SELECT COALESCE(SUM(jl.debit_base) - SUM(jl.credit_base), 0)
+			FROM journal_lines jl
+			JOIN journal_entries je ON je.id = jl.entry_id
+			JOIN accounts a ON a.id = jl.account_id
+			WHERE je.company_id = 1 AND a.code = $1`,
+			accountCode,
+		).Scan(&bal); err != nil {
+			t.Fatalf("account balance %s: %v", accountCode, err)
+		}
+		return bal
+	}
+
+	t.Run("PriceWithinTolerance_PostsPPV", func(t *testing.T) {
+		po := receivedPO(t, 10)
+		apBefore, ppvBefore := balance("2000"), balance("5400")
+
+		// 10 × 104: 4% over PO price, inside the default 5% tolerance.
+		warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-PPV", today, decimal.Zero,
+			[]core.VendorInvoiceLineInput{{POLineID: po.Lines[0].ID, Quantity: decimal.NewFromInt(10), UnitPrice: decimal.NewFromInt(104)}},
+			ledger, docService)
+		if err != nil {
+			t.Fatalf("RecordVendorInvoice: %v", err)
+		}
+		if warning != "" {
+			t.Errorf("expected no warning within tolerance, got %q", warning)
+		}
+
+		got, err := poService.GetPO(ctx, po.ID)
+		if err != nil {
+			t.Fatalf("GetPO: %v", err)
+		}
+		if got.PaymentBlocked || got.MatchStatus == nil || *got.MatchStatus != "VARIANCE" {
+			t.Errorf("expected unblocked PO with match status VARIANCE, got blocked=%v status=%v", got.PaymentBlocked, got.MatchStatus)
+		}
+		if len(got.InvoiceLines) != 1 || got.InvoiceLines[0].MatchStatus != "WITHIN_TOLERANCE" {
+			t.Fatalf("expected one WITHIN_TOLERANCE invoice line, got %+v", got.InvoiceLines)
+		}
+		if !got.VarianceAmount.Equal(decimal.NewFromInt(40)) || got.VarianceDocumentNumber == nil {
+			t.Errorf("expected posted variance 40, got %s (doc %v)", got.VarianceAmount, got.VarianceDocumentNumber)
+		}
+		if d := balance("5400").Sub(ppvBefore); !d.Equal(decimal.NewFromInt(40)) {
+			t.Errorf("expected PPV debit 40, got %s", d)
+		}
+		// AP now carries the invoice (1,040), not the PO value (1,000).
+		if d := apBefore.Sub(balance("2000")); !d.Equal(decimal.NewFromInt(40)) {
+			t.Errorf("expected AP credit of 40 more, got %s", d)
+		}
+
+		if err := poService.PayVendor(ctx, po.ID, "1100", today, companyCode, ledger); err != nil {
+			t.Errorf("PayVendor: %v", err)
+		}
+	})
+
+	t.Run("QtyException_BlocksPaymentUntilReleased", func(t *testing.T) {
+		po := receivedPO(t, 8)
+
+		// Billed for 10 but only 8 received.
+		warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-QTY", today, decimal.NewFromInt(1000),
+			[]core.VendorInvoiceLineInput{{POLineID: po.Lines[0].ID, Quantity: decimal.NewFromInt(10), UnitPrice: decimal.NewFromInt(100)}},
+			ledger, docService)
+		if err != nil {
+			t.Fatalf("RecordVendorInvoice: %v", err)
+		}
+		if warning == "" {
+			t.Error("expected a payment-block warning, got empty string")
+		}
+
+		got, _ := poService.GetPO(ctx, po.ID)
+		if !got.PaymentBlocked || got.InvoiceLines[0].MatchStatus != "QTY_EXCEPTION" {
+			t.Fatalf("expected PO blocked with QTY_EXCEPTION, got blocked=%v lines=%+v", got.PaymentBlocked, got.InvoiceLines)
+		}
+		if err := poService.PayVendor(ctx, po.ID, "1100", today, companyCode, ledger); err == nil {
+			t.Error("expected error paying a blocked PO, got nil")
+		}
+
+		ppvBefore := balance("5400")
+		if err := poService.ReleasePaymentBlock(ctx, 1, po.ID, ledger); err != nil {
+			t.Fatalf("ReleasePaymentBlock: %v", err)
+		}
+		if d := balance("5400").Sub(ppvBefore); !d.Equal(decimal.NewFromInt(200)) {
+			t.Errorf("expected accepted variance 200 posted to PPV, got %s", d)
+		}
+		got, _ = poService.GetPO(ctx, po.ID)
+		if got.PaymentBlocked || got.MatchStatus == nil || *got.MatchStatus != "RELEASED" {
+			t.Errorf("expected released PO, got blocked=%v status=%v", got.PaymentBlocked, got.MatchStatus)
+		}
+		if err := poService.ReleasePaymentBlock(ctx, 1, po.ID, ledger); err == nil {
+			t.Error("expected error releasing an unblocked PO, got nil")
+		}
+		if err := poService.PayVendor(ctx, po.ID, "1100", today, companyCode, ledger); err != nil {
+			t.Errorf("PayVendor after release: %v", err)
+		}
+	})
+
+	t.Run("SetMatchTolerance_InvalidTreatment_Fails", func(t *testing.T) {
+		if _, err := poService.SetMatchTolerance(ctx, 1, core.MatchTolerance{VarianceTreatment: "WRITE_OFF"}); err == nil {
+			t.Error("expected error for unknown variance treatment, got nil")
+		}
+	})
+
+	t.Run("InventoryTreatment_RevaluesStock", func(t *testing.T) {
+		if _, err := poService.SetMatchTolerance(ctx, 1, core.MatchTolerance{
+			PriceTolerancePct: decimal.NewFromInt(5),
+			VarianceTreatment: "INVENTORY",
+		}); err != nil {
+			t.Fatalf("SetMatchTolerance: %v", err)
+		}
+		po := receivedPO(t, 10)
+		invBefore := balance("1400")
+
+		// Header-level match: 1,030 spread over the single received line (103 each).
+		if _, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-INV", today, decimal.NewFromInt(1030),
+			nil, ledger, docService); err != nil {
+			t.Fatalf("RecordVendorInvoice: %v", err)
+		}
+		if d := balance("1400").Sub(invBefore); !d.Equal(decimal.NewFromInt(30)) {
+			t.Errorf("expected inventory debit 30, got %s", d)
+		}
+
+		// 28 units on hand at 100 before the invoice; the 30 variance lifts the stock value.
+		report, err := core.NewReportingService(pool).GetInventoryValuation(ctx, companyCode, todayStr)
+		if err != nil {
+			t.Fatalf("GetInventoryValuation: %v", err)
+		}
+		if !report.TotalValue.Equal(decimal.NewFromInt(2830)) {
+			t.Errorf("expected stock value 2830, got %s", report.TotalValue)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPurchaseOrder_AmendCancel(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(50)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	lineID := po.Lines[0].ID
+
+	// Amend quantity and cost and add a line: 12 × 45 + 2 × 10 = 560.
+	amended, err := poService.AmendPO(ctx, 1, po.ID, core.PurchaseOrderAmendment{
+		Lines: []core.PurchaseOrderAmendLine{
+			{POLineID: lineID, Quantity: decimal.NewFromInt(12), UnitCost: decimal.NewFromInt(45)},
+			{ProductCode: "P001", Description: "Widget A spare", Quantity: decimal.NewFromInt(2), UnitCost: decimal.NewFromInt(10)},
+		},
+		Reason:    "vendor price change",
+		AmendedBy: "buyer",
+	})
+	if err != nil {
+		t.Fatalf("AmendPO: %v", err)
+	}
+	if amended.Revision != 1 || amended.Status != "APPROVED" || len(amended.Lines) != 2 {
+		t.Fatalf("expected APPROVED revision 1 with 2 lines, got %s revision %d with %d lines",
+			amended.Status, amended.Revision, len(amended.Lines))
+	}
+	if !amended.TotalTransaction.Equal(decimal.NewFromInt(560)) {
+		t.Errorf("expected total 560, got %s", amended.TotalTransaction)
+	}
+
+	t.Run("NoChange_Fails", func(t *testing.T) {
+		_, err := poService.AmendPO(ctx, 1, po.ID, core.PurchaseOrderAmendment{})
+		if err == nil {
+			t.Error("expected error for an amendment that changes nothing, got nil")
+		}
+	})
+
+	// An amendment requiring re-approval returns the PO to DRAFT; approving keeps its number.
+	reamended, err := poService.AmendPO(ctx, 1, po.ID, core.PurchaseOrderAmendment{
+		ExpectedDeliveryDate: "2026-06-15",
+		RequireReapproval:    true,
+	})
+	if err != nil {
+		t.Fatalf("AmendPO (re-approval): %v", err)
+	}
+	if reamended.Status != "DRAFT" || reamended.Revision != 2 {
+		t.Fatalf("expected DRAFT revision 2, got %s revision %d", reamended.Status, reamended.Revision)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO (re-approval): %v", err)
+	}
+	reapproved, err := poService.GetPO(ctx, po.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	if reapproved.Status != "APPROVED" || reapproved.PONumber == nil || amended.PONumber == nil ||
+		*reapproved.PONumber != *amended.PONumber {
+		t.Errorf("expected re-approved PO to keep its number")
+	}
+
+	t.Run("CancelReceived_Fails", func(t *testing.T) {
+		if err := poService.ReceivePO(ctx, po.ID, "MAIN", "1000",
+			[]core.ReceivedLine{{POLineID: lineID, QtyReceived: decimal.NewFromInt(1)}},
+			"2000", ledger, docService, invSvc); err != nil {
+			t.Fatalf("ReceivePO: %v", err)
+		}
+		if err := poService.CancelPO(ctx, 1, po.ID, "", "buyer"); err == nil {
+			t.Error("expected error cancelling a PO with received goods, got nil")
+		}
+	})
+
+	// A second, untouched PO can be cancelled.
+	other, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(5), UnitCost: decimal.NewFromInt(50)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, other.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	if err := poService.CancelPO(ctx, 1, other.ID, "ordered elsewhere", "buyer"); err != nil {
+		t.Fatalf("CancelPO: %v", err)
+	}
+	cancelled, err := poService.GetPO(ctx, other.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	if cancelled.Status != "CANCELLED" || cancelled.CancelReason == nil {
+		t.Errorf("expected CANCELLED with a reason, got %s", cancelled.Status)
+	}
+
+	revisions, err := poService.GetPORevisions(ctx, 1, po.ID)
+	if err != nil {
+		t.Fatalf("GetPORevisions: %v", err)
+	}
+	want := []string{"CREATED", "APPROVED", "AMENDED", "AMENDED", "APPROVED"}
+	if len(revisions) != len(want) {
+		t.Fatalf("expected %d revisions, got %d", len(want), len(revisions))
+	}
+	for i, r := range revisions {
+		if r.Action != want[i] {
+			t.Errorf("revision %d: expected %s, got %s", i, want[i], r.Action)
+		}
+	}
+	if len(revisions[2].Changes) == 0 || revisions[2].Reason == nil || *revisions[2].Reason != "vendor price change" {
+		t.Errorf("expected first amendment to record its changes and reason")
+	}
+}
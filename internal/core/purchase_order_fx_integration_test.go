@@ -0,0 +1,118 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPurchaseOrder_ForeignCurrency(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING
+	`); err != nil {
+		t.Fatalf("seed bank account: %v", err)
+	}
+	var usdVendorID int
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO vendors (company_id, code, name, payment_terms_days, ap_account_code, currency)
+		VALUES (1, 'V-USD', 'US Supplier', 30, '2000', 'USD')
+		RETURNING id`,
+	).Scan(&usdVendorID); err != nil {
+		t.Fatalf("seed USD vendor: %v", err)
+	}
+
+	companyCode := "1000"
+	poDate := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	lines := []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(5)},
+	}
+
+	t.Run("MissingRate_Fails", func(t *testing.T) {
+		if _, err := poService.CreatePO(ctx, 1, usdVendorID, "", decimal.Zero, poDate, lines, ""); err == nil {
+			t.Error("expected error for a USD purchase order without an exchange rate, got nil")
+		}
+	})
+
+	t.Run("BaseCurrencyRate_Fails", func(t *testing.T) {
+		if _, err := poService.CreatePO(ctx, 1, vendorID, "INR", decimal.NewFromInt(2), poDate, lines, ""); err == nil {
+			t.Error("expected error for a base-currency purchase order at rate 2, got nil")
+		}
+	})
+
+	// 10 × P001 @ USD 5 at 80 = USD 50 / INR 4000; the vendor's currency is the default.
+	po, err := poService.CreatePO(ctx, 1, usdVendorID, "", decimal.NewFromInt(80), poDate, lines, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if po.Currency != "USD" || !po.TotalTransaction.Equal(decimal.NewFromInt(50)) || !po.TotalBase.Equal(decimal.NewFromInt(4000)) {
+		t.Fatalf("expected USD 50 / base 4000, got %s %s / %s", po.Currency, po.TotalTransaction, po.TotalBase)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+		[]core.ReceivedLine{{POLineID: po.Lines[0].ID, QtyReceived: decimal.NewFromInt(10)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+
+	t.Run("Return_Fails", func(t *testing.T) {
+		returnSvc := core.NewPurchaseReturnService(pool, core.NewRuleEngine(pool))
+		if _, err := returnSvc.CreateReturn(ctx, companyCode, core.PurchaseReturnInput{
+			POID: po.ID, WarehouseCode: "MAIN",
+			Lines: []core.PurchaseReturnLineInput{{POLineID: po.Lines[0].ID, Quantity: decimal.NewFromInt(2)}},
+		}, ledger); err == nil {
+			t.Error("expected error returning goods on a USD purchase order, got nil")
+		}
+	})
+
+	var unitCost decimal.Decimal
+	if err := pool.QueryRow(ctx, `
+		SELECT ii.unit_cost
+		FROM inventory_items ii
+		JOIN products p ON p.id = ii.product_id
+		WHERE p.code = 'P001'`,
+	).Scan(&unitCost); err != nil {
+		t.Fatalf("query inventory cost: %v", err)
+	}
+	if !unitCost.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("inventory unit cost: expected 400 (base), got %s", unitCost)
+	}
+
+	if _, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "US-INV-1", poDate.AddDate(0, 0, 5),
+		decimal.NewFromInt(50), nil, ledger, docService); err != nil {
+		t.Fatalf("RecordVendorInvoice: %v", err)
+	}
+	if err := poService.PayVendor(ctx, po.ID, "1100", poDate.AddDate(0, 0, 20), companyCode, ledger); err != nil {
+		t.Fatalf("PayVendor: %v", err)
+	}
+
+	var bank, ap, bankTx decimal.Decimal
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE a.code = '1100'), 0),
+		       COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE a.code = '2000'), 0),
+		       COALESCE(SUM(jl.amount_transaction) FILTER (WHERE a.code = '1100' AND jl.transaction_currency = 'USD'), 0)
+		FROM journal_lines jl
+		JOIN accounts a ON a.id = jl.account_id
+		WHERE a.company_id = 1`,
+	).Scan(&bank, &ap, &bankTx); err != nil {
+		t.Fatalf("query balances: %v", err)
+	}
+	if !bank.Equal(decimal.NewFromInt(-4000)) {
+		t.Errorf("bank: expected -4000 base, got %s", bank)
+	}
+	if !bankTx.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("bank: expected a USD 50 payment line, got %s", bankTx)
+	}
+	if !ap.IsZero() {
+		t.Errorf("AP: expected 0 after payment, got %s", ap)
+	}
+}
@@ -84,7 +84,7 @@ func setupPurchaseOrderTestDB(t *testing.T) (*pgxpool.Pool, core.PurchaseOrderSe
 	}
 
 	docService := core.NewDocumentService(pool)
-	poService := core.NewPurchaseOrderService(pool)
+	poService := core.NewPurchaseOrderService(pool, core.NewRuleEngine(pool), core.NewTaxEngine(pool))
 
 	return pool, poService, docService, 1, ctx // vendorID = 1
 }
@@ -114,7 +114,7 @@ func TestPurchaseOrder_CreateAndApprove(t *testing.T) {
 			},
 		}
 
-		po, err := poService.CreatePO(ctx, companyID, vendorID, poDate, lines, "First test PO")
+		po, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, lines, "First test PO")
 		if err != nil {
 			t.Fatalf("CreatePO: %v", err)
 		}
@@ -157,7 +157,7 @@ func TestPurchaseOrder_CreateAndApprove(t *testing.T) {
 	})
 
 	t.Run("CreatePO_NoLines_Fails", func(t *testing.T) {
-		_, err := poService.CreatePO(ctx, companyID, vendorID, poDate, nil, "")
+		_, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, nil, "")
 		if err == nil {
 			t.Error("expected error for PO with no lines, got nil")
 		}
@@ -215,7 +215,7 @@ func TestPurchaseOrder_CreateAndApprove(t *testing.T) {
 
 	t.Run("GetPOs_FilteredByStatus", func(t *testing.T) {
 		// Create another DRAFT PO
-		_, err := poService.CreatePO(ctx, companyID, vendorID, poDate, []core.PurchaseOrderLineInput{
+		_, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{
 				Description: "Service charge",
 				Quantity:    decimal.NewFromInt(1),
@@ -271,7 +271,7 @@ func TestPurchaseOrder_CreateAndApprove(t *testing.T) {
 		}
 
 		// Create a PO for the other company
-		_, err := poService.CreatePO(ctx, 2, otherVendorID, poDate, []core.PurchaseOrderLineInput{
+		_, err := poService.CreatePO(ctx, 2, otherVendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{
 				Description: "Other company item",
 				Quantity:    decimal.NewFromInt(1),
@@ -319,7 +319,7 @@ func TestPurchaseOrder_ReceivePO(t *testing.T) {
 		},
 	}
 
-	po, err := poService.CreatePO(ctx, companyID, vendorID, poDate, lines, "receive test PO")
+	po, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, lines, "receive test PO")
 	if err != nil {
 		t.Fatalf("CreatePO: %v", err)
 	}
@@ -342,7 +342,7 @@ func TestPurchaseOrder_ReceivePO(t *testing.T) {
 
 	t.Run("ReceivePO_NotApproved_Fails", func(t *testing.T) {
 		// Create a DRAFT PO and try to receive it — must fail
-		draftPO, _ := poService.CreatePO(ctx, companyID, vendorID, poDate, []core.PurchaseOrderLineInput{
+		draftPO, _ := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{Description: "Test", Quantity: decimal.NewFromInt(1), UnitCost: decimal.NewFromFloat(100)},
 		}, "")
 		err := poService.ReceivePO(ctx, draftPO.ID, "MAIN", companyCode,
@@ -465,7 +465,7 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 			UnitCost:    decimal.NewFromFloat(500.00),
 		},
 	}
-	po, err := poService.CreatePO(ctx, companyID, vendorID, poDate, lines, "lifecycle test")
+	po, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, lines, "lifecycle test")
 	if err != nil {
 		t.Fatalf("CreatePO: %v", err)
 	}
@@ -490,12 +490,12 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 
 	t.Run("RecordVendorInvoice_NotReceived_Fails", func(t *testing.T) {
 		// Create a fresh DRAFT PO and try to invoice it — must fail
-		draftPO, _ := poService.CreatePO(ctx, companyID, vendorID, poDate, []core.PurchaseOrderLineInput{
+		draftPO, _ := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{Description: "Test item", Quantity: decimal.NewFromInt(1), UnitCost: decimal.NewFromFloat(100)},
 		}, "")
 		_, err := poService.RecordVendorInvoice(ctx, 1, draftPO.ID, "INV-9999",
 			time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC),
-			decimal.NewFromFloat(100), docService)
+			decimal.NewFromFloat(100), nil, ledger, docService)
 		if err == nil {
 			t.Error("expected error invoicing DRAFT PO, got nil")
 		}
@@ -506,7 +506,7 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 		invoiceAmount := decimal.NewFromFloat(10000.00) // exact match: 20×500
 
 		warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID,
-			"INV-2026-001", invoiceDate, invoiceAmount, docService)
+			"INV-2026-001", invoiceDate, invoiceAmount, nil, ledger, docService)
 		if err != nil {
 			t.Fatalf("RecordVendorInvoice: %v", err)
 		}
@@ -535,7 +535,7 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 
 	t.Run("RecordVendorInvoice_AmountDeviation_Warning", func(t *testing.T) {
 		// Create and receive a new expense-only PO to test the warning
-		po2, err := poService.CreatePO(ctx, companyID, vendorID, poDate, []core.PurchaseOrderLineInput{
+		po2, err := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{
 				Description:        "Consulting services",
 				Quantity:           decimal.NewFromInt(1),
@@ -562,7 +562,7 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 		// Invoice with 10% more than PO total — should produce a warning
 		warning, err := poService.RecordVendorInvoice(ctx, 1, po2.ID, "INV-HIGH",
 			time.Date(2026, 3, 26, 0, 0, 0, 0, time.UTC),
-			decimal.NewFromFloat(1100), docService)
+			decimal.NewFromFloat(1100), nil, ledger, docService)
 		if err != nil {
 			t.Fatalf("RecordVendorInvoice with deviation: %v", err)
 		}
@@ -574,7 +574,7 @@ func TestPurchaseOrder_FullLifecycle(t *testing.T) {
 
 	t.Run("PayVendor_NotInvoiced_Fails", func(t *testing.T) {
 		// Create and receive a new PO (RECEIVED but not INVOICED) — pay must fail
-		po3, _ := poService.CreatePO(ctx, companyID, vendorID, poDate, []core.PurchaseOrderLineInput{
+		po3, _ := poService.CreatePO(ctx, companyID, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
 			{Description: "Non-invoiced item", Quantity: decimal.NewFromInt(1), UnitCost: decimal.NewFromFloat(200)},
 		}, "")
 		_ = poService.ApprovePO(ctx, 1, po3.ID, docService)
@@ -18,26 +18,42 @@ type PurchaseOrder struct {
 	Status               string
 	PODate               string // YYYY-MM-DD
 	ExpectedDeliveryDate *string
-	Currency             string
-	ExchangeRate         decimal.Decimal
-	TotalTransaction     decimal.Decimal
+	Currency             string          // transaction currency of the PO, its invoice and payment
+	ExchangeRate         decimal.Decimal // base currency per unit of Currency, fixed at creation
+	TotalTransaction     decimal.Decimal // TaxableTransaction + TaxTransaction
 	TotalBase            decimal.Decimal
+	TaxableTransaction   decimal.Decimal
+	TaxTransaction       decimal.Decimal // estimated GST on the lines' tax codes at the PO date
 	Notes                *string
 	ApprovedAt           *time.Time
-	ReceivedAt           *time.Time
+	ReceivedAt           *time.Time // set when the last line is fully received or the PO is short-closed
+	ShortClosedAt        *time.Time
+	ShortCloseReason     *string
+	Revision             int // incremented by each amendment
+	CancelledAt          *time.Time
+	CancelReason         *string
 	// Invoice fields (set by RecordVendorInvoice)
 	InvoiceNumber    *string
-	InvoiceDate      *string // YYYY-MM-DD
-	InvoiceAmount    *decimal.Decimal
+	InvoiceDate      *string          // YYYY-MM-DD
+	InvoiceAmount    *decimal.Decimal // including GST
+	InvoiceTaxAmount decimal.Decimal  // input GST on the invoice
 	PIDocumentNumber *string
 	InvoicedAt       *time.Time
+	// Three-way match fields (set by RecordVendorInvoice / ReleasePaymentBlock)
+	MatchStatus            *string // MATCHED | VARIANCE | BLOCKED | RELEASED
+	PaymentBlocked         bool
+	PaymentBlockReason     *string
+	VarianceAmount         decimal.Decimal // invoice total minus AP booked at receipt; + = invoice higher
+	VarianceDocumentNumber *string
+	InvoiceLines           []VendorInvoiceLine
 	// Payment fields (set by PayVendor)
-	PaidAt *time.Time
-	CreatedAt            time.Time
-	Lines                []PurchaseOrderLine
+	PaidAt    *time.Time
+	CreatedAt time.Time
+	Lines     []PurchaseOrderLine
 }
 
 // PurchaseOrderLine represents a single line on a purchase order.
+// Quantity, ReceivedQuantity and UnitCost are in the line's Unit; inventory receives Quantity × UnitFactor.
 type PurchaseOrderLine struct {
 	ID                   int
 	OrderID              int
@@ -47,56 +63,211 @@ type PurchaseOrderLine struct {
 	ProductName          *string
 	Description          string
 	Quantity             decimal.Decimal
+	Unit                 string          // product stock unit when not set on the line
+	UnitFactor           decimal.Decimal // stock units per Unit
 	UnitCost             decimal.Decimal
 	LineTotalTransaction decimal.Decimal
 	LineTotalBase        decimal.Decimal
 	ExpenseAccountCode   *string
+	TaxCode              *string         // product default unless set on the line
+	TaxAmountTransaction decimal.Decimal // estimated GST; the invoice line carries the actual tax
+	ReceivedQuantity     decimal.Decimal // cumulative, goods and service lines
+	ReturnedQuantity     decimal.Decimal // cumulative, returned to the vendor on purchase returns
+	Dimensions           []LineDimension // carried onto the expense line of a service receipt
+}
+
+// ReturnableQuantity returns the received quantity not yet returned to the vendor.
+func (l PurchaseOrderLine) ReturnableQuantity() decimal.Decimal {
+	return l.ReceivedQuantity.Sub(l.ReturnedQuantity)
+}
+
+// OutstandingQuantity returns the quantity still to be received on the line.
+func (l PurchaseOrderLine) OutstandingQuantity() decimal.Decimal {
+	if out := l.Quantity.Sub(l.ReceivedQuantity); out.IsPositive() {
+		return out
+	}
+	return decimal.Zero
 }
 
 // PurchaseOrderLineInput holds the fields required to create a purchase order line.
+// Unit defaults to the product's purchase unit (or stock unit); UnitCost is per Unit.
+// TaxCode defaults to the product's tax code; empty on a service line means no GST.
+// Dimensions (cost center, project, ...) are posted with a service line's expense.
 type PurchaseOrderLineInput struct {
 	ProductCode        string
 	Description        string
 	Quantity           decimal.Decimal
+	Unit               string
+	UnitCost           decimal.Decimal
+	ExpenseAccountCode string
+	TaxCode            string
+	Dimensions         []LineDimension
+}
+
+// PurchaseOrderAmendment describes a change to an APPROVED purchase order.
+// A nil Lines leaves the lines as they are; otherwise Lines is the complete new line set:
+// existing lines not listed are removed and lines with POLineID 0 are added.
+// With RequireReapproval the PO returns to DRAFT (keeping its PO number) until approved again.
+type PurchaseOrderAmendment struct {
+	ExpectedDeliveryDate string // YYYY-MM-DD; empty keeps the current date
+	Lines                []PurchaseOrderAmendLine
+	Reason               string
+	RequireReapproval    bool
+	AmendedBy            string
+}
+
+// PurchaseOrderAmendLine is one line of an amended purchase order. For an existing line
+// (POLineID set) only Quantity, UnitCost and Description may change; an empty Description
+// keeps the current one. New lines are resolved like PurchaseOrderLineInput.
+type PurchaseOrderAmendLine struct {
+	POLineID           int
+	ProductCode        string
+	Description        string
+	Quantity           decimal.Decimal
+	Unit               string
 	UnitCost           decimal.Decimal
 	ExpenseAccountCode string
+	TaxCode            string
+	Dimensions         []LineDimension // new lines only
+}
+
+// PurchaseOrderRevision is one entry in a purchase order's audit trail.
+// Revision is the PO revision after the event; Changes describes what changed
+// (e.g. "line 2 quantity 10 → 12").
+type PurchaseOrderRevision struct {
+	ID        int
+	POID      int
+	Revision  int
+	Action    string // CREATED | APPROVED | AMENDED | CANCELLED | CLOSED
+	Changes   []string
+	Reason    *string
+	ChangedBy *string
+	ChangedAt time.Time
 }
 
 // ReceivedLine represents one PO line being received.
 type ReceivedLine struct {
 	POLineID    int             // references purchase_order_lines.id
-	QtyReceived decimal.Decimal // quantity being received on this call
+	QtyReceived decimal.Decimal // quantity being received on this call, in the PO line's unit
+	Lots        []LotInput      // required for lot- or serial-tracked products; stock-unit quantities must sum to the converted receipt
+}
+
+// VendorInvoiceLineInput is one line of a vendor invoice, matched against a PO line.
+// Quantity and UnitPrice are in the PO line's unit.
+type VendorInvoiceLineInput struct {
+	POLineID  int
+	Quantity  decimal.Decimal
+	UnitPrice decimal.Decimal
+}
+
+// VendorInvoiceLine is the three-way match result for one PO line:
+// ordered (PO) vs received (RECEIPT movements) vs invoiced.
+// VarianceAmount = LineAmount - ReceivedQty × POUnitCost = PriceVariance + QtyVariance.
+type VendorInvoiceLine struct {
+	POLineID       int
+	LineNumber     int
+	Description    string
+	OrderedQty     decimal.Decimal
+	ReceivedQty    decimal.Decimal
+	QtyInvoiced    decimal.Decimal
+	POUnitCost     decimal.Decimal
+	UnitPrice      decimal.Decimal
+	LineAmount     decimal.Decimal
+	PriceVariance  decimal.Decimal
+	QtyVariance    decimal.Decimal
+	VarianceAmount decimal.Decimal
+	MatchStatus    string          // MATCHED | WITHIN_TOLERANCE | PRICE_EXCEPTION | QTY_EXCEPTION
+	TaxAmount      decimal.Decimal // input GST on LineAmount under the PO line's tax code
+	Taxes          []LineTax
+}
+
+// MatchTolerance holds a company's three-way match rules. A line is accepted when its
+// price and quantity differences are within the percentage tolerances, or when the
+// absolute line variance does not exceed AmountTolerance.
+type MatchTolerance struct {
+	PriceTolerancePct decimal.Decimal
+	QtyTolerancePct   decimal.Decimal
+	AmountTolerance   decimal.Decimal
+	VarianceTreatment string // PPV (PURCHASE_PRICE_VARIANCE account) | INVENTORY (revalue stock on hand)
 }
 
 // PurchaseOrderService provides purchase order lifecycle operations.
 type PurchaseOrderService interface {
 	// CreatePO creates a new DRAFT purchase order with computed line totals.
-	CreatePO(ctx context.Context, companyID, vendorID int, poDate time.Time, lines []PurchaseOrderLineInput, notes string) (*PurchaseOrder, error)
+	// An empty currency uses the vendor's default currency, else the company base currency.
+	// Unit costs are in that currency; exchangeRate (base per unit of currency) is required
+	// for a foreign currency and must be zero or 1 for the base currency.
+	CreatePO(ctx context.Context, companyID, vendorID int, currency string, exchangeRate decimal.Decimal,
+		poDate time.Time, lines []PurchaseOrderLineInput, notes string) (*PurchaseOrder, error)
 
 	// ApprovePO transitions a DRAFT PO to APPROVED, assigning a gapless PO number.
+	// A PO returned to DRAFT by an amendment keeps the number it already has.
 	// companyID must match the PO's company; returns an error if they differ.
 	// It is idempotent: approving an already-APPROVED PO is a no-op.
 	ApprovePO(ctx context.Context, companyID, poID int, docService DocumentService) error
 
-	// ReceivePO records goods and/or services received against an APPROVED purchase order.
-	// For physical-goods lines (product_id set): updates inventory via InventoryService.ReceiveStock
-	// and links the movement to the PO line.
+	// AmendPO changes the lines and/or expected delivery date of an APPROVED purchase order,
+	// increments its revision and records the changes in the revision history.
+	// Returns an error if the amendment changes nothing.
+	AmendPO(ctx context.Context, companyID, poID int, amendment PurchaseOrderAmendment) (*PurchaseOrder, error)
+
+	// CancelPO cancels a DRAFT or APPROVED purchase order on which nothing has been received.
+	CancelPO(ctx context.Context, companyID, poID int, reason, cancelledBy string) error
+
+	// GetPORevisions returns a purchase order's revision history, oldest first.
+	GetPORevisions(ctx context.Context, companyID, poID int) ([]PurchaseOrderRevision, error)
+
+	// ReceivePO records goods and/or services received against an APPROVED or PARTIALLY_RECEIVED
+	// purchase order. Receipts may be repeated until every line is received; a line may not be
+	// received beyond its ordered quantity.
+	// For physical-goods lines (product_id set): updates inventory via
+	// InventoryService.ReceiveStockTx and links the movement to the PO line.
 	// For service/expense lines (expense_account_code set, no product): posts DR expense / CR AP.
-	// On completion, transitions PO status to RECEIVED.
+	// Entries are posted in the PO currency at the PO exchange rate; stock is valued in base currency.
+	// Transitions PO status to RECEIVED when all lines are fully received, else PARTIALLY_RECEIVED.
+	// The receipt is all-or-nothing: if any line fails, no quantity or entry is recorded.
 	ReceivePO(ctx context.Context, poID int, warehouseCode, companyCode string,
 		receivedLines []ReceivedLine, apAccountCode string,
 		ledger *Ledger, docService DocumentService, inv InventoryService) error
 
-	// RecordVendorInvoice records the vendor's invoice against a RECEIVED purchase order.
+	// ShortClosePO closes a PARTIALLY_RECEIVED purchase order, cancelling the undelivered
+	// balance, and moves it to RECEIVED so it can be invoiced for what was delivered.
+	ShortClosePO(ctx context.Context, companyID, poID int, reason, closedBy string) error
+
+	// RecordVendorInvoice records the vendor's invoice against a RECEIVED purchase order and
+	// three-way matches each line against the PO and the quantity received.
 	// companyID must match the PO's company; returns an error if they differ.
-	// Creates and posts a PI document (gapless number). Warns if invoiceAmount deviates
-	// more than 5% from the PO total. Transitions status to INVOICED.
-	// Returns a non-empty warning string if the amount deviation exceeds 5%.
+	// When lines is empty the invoice is matched at header level: every received line is
+	// taken as invoiced in full and invoiceAmount is spread over the lines by value.
+	// invoiceAmount and unit prices are in the PO currency and exclude GST.
+	// GST is computed per line from the PO line's tax code at the invoice date and posted
+	// as input tax credit against AP; the PO's invoice amount then includes it.
+	// Creates and posts a PI document (gapless number) and transitions status to INVOICED.
+	// Lines within tolerance have their variance posted against AP; any line outside
+	// tolerance blocks the PO for payment instead and is described in the returned warning.
 	RecordVendorInvoice(ctx context.Context, companyID, poID int, invoiceNumber string, invoiceDate time.Time,
-		invoiceAmount decimal.Decimal, docService DocumentService) (warning string, err error)
+		invoiceAmount decimal.Decimal, lines []VendorInvoiceLineInput,
+		ledger *Ledger, docService DocumentService) (warning string, err error)
+
+	// ReleasePaymentBlock accepts the variances on a payment-blocked INVOICED PO,
+	// posts them as RecordVendorInvoice would have, and clears the block.
+	ReleasePaymentBlock(ctx context.Context, companyID, poID int, ledger *Ledger) error
+
+	// GetMatchTolerance returns the company's three-way match tolerances, or the
+	// defaults (5% price, 0% quantity, no absolute allowance, PPV) if none are set.
+	GetMatchTolerance(ctx context.Context, companyID int) (*MatchTolerance, error)
+
+	// SetMatchTolerance creates or replaces the company's three-way match tolerances.
+	SetMatchTolerance(ctx context.Context, companyID int, tol MatchTolerance) (*MatchTolerance, error)
 
-	// PayVendor records payment against an INVOICED purchase order.
-	// Posts DR AP / CR Bank and transitions status to PAID.
+	// PayVendor records payment against an INVOICED purchase order that is not blocked for payment.
+	// Open debit notes from the vendor's purchase returns are offset against the payment first
+	// (oldest first); the remainder is posted DR AP / CR Bank. Transitions status to PAID.
+	// The payment is posted in the PO currency at the PO exchange rate; debit notes, which are
+	// in base currency, are only offset against base-currency purchase orders.
+	// For a vendor with a TDS section, TDS is withheld from the remainder once the section's
+	// thresholds are crossed and credited to TDS_PAYABLE; such vendors are paid in base
+	// currency only.
 	PayVendor(ctx context.Context, poID int, bankAccountCode string, paymentDate time.Time,
 		companyCode string, ledger *Ledger) error
 
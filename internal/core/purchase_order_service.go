@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -12,16 +13,22 @@ import (
 )
 
 type purchaseOrderService struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+	taxEngine  TaxEngine
 }
 
 // NewPurchaseOrderService constructs a PurchaseOrderService backed by PostgreSQL.
-func NewPurchaseOrderService(pool *pgxpool.Pool) PurchaseOrderService {
-	return &purchaseOrderService{pool: pool}
+func NewPurchaseOrderService(pool *pgxpool.Pool, ruleEngine RuleEngine, taxEngine TaxEngine) PurchaseOrderService {
+	return &purchaseOrderService{pool: pool, ruleEngine: ruleEngine, taxEngine: taxEngine}
 }
 
 // CreatePO creates a new DRAFT purchase order with computed line totals.
-func (s *purchaseOrderService) CreatePO(ctx context.Context, companyID, vendorID int, poDate time.Time, lines []PurchaseOrderLineInput, notes string) (*PurchaseOrder, error) {
+// The PO is raised in currency, defaulting to the vendor's currency and then to the
+// company base currency; line totals are converted to base at exchangeRate.
+// Lines with a tax code carry the GST estimated at poDate; the total includes it.
+func (s *purchaseOrderService) CreatePO(ctx context.Context, companyID, vendorID int, currency string, exchangeRate decimal.Decimal,
+	poDate time.Time, lines []PurchaseOrderLineInput, notes string) (*PurchaseOrder, error) {
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("purchase order must have at least one line")
 	}
@@ -32,69 +39,52 @@ func (s *purchaseOrderService) CreatePO(ctx context.Context, companyID, vendorID
 	}
 	defer tx.Rollback(ctx)
 
-	// Validate vendor belongs to this company
-	var vendorExists bool
-	if err := tx.QueryRow(ctx,
-		"SELECT EXISTS(SELECT 1 FROM vendors WHERE id = $1 AND company_id = $2 AND is_active = true)",
+	// Validate vendor belongs to this company and resolve the PO currency
+	var baseCurrency, vendorCode string
+	var vendorCurrency, vendorState *string
+	var vendorActive bool
+	if err := tx.QueryRow(ctx, `
+		SELECT c.base_currency, v.code, v.currency, v.state_code, v.is_active
+		FROM vendors v
+		JOIN companies c ON c.id = v.company_id
+		WHERE v.id = $1 AND v.company_id = $2`,
 		vendorID, companyID,
-	).Scan(&vendorExists); err != nil {
+	).Scan(&baseCurrency, &vendorCode, &vendorCurrency, &vendorState, &vendorActive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("vendor %d not found for company %d", vendorID, companyID)
+		}
 		return nil, fmt.Errorf("validate vendor: %w", err)
 	}
-	if !vendorExists {
-		return nil, fmt.Errorf("vendor %d not found for company %d", vendorID, companyID)
+	if !vendorActive {
+		return nil, fmt.Errorf("vendor %s is inactive", vendorCode)
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" && vendorCurrency != nil {
+		currency = *vendorCurrency
+	}
+	if currency == "" {
+		currency = baseCurrency
+	}
+	exchangeRate, err = resolvePOExchangeRate(currency, baseCurrency, exchangeRate)
+	if err != nil {
+		return nil, err
 	}
 
 	// Resolve lines and compute totals
-	exchangeRate := decimal.NewFromInt(1)
-	type resolvedLine struct {
-		productID          *int
-		productCode        *string
-		productName        *string
-		description        string
-		quantity           decimal.Decimal
-		unitCost           decimal.Decimal
-		lineTotalTx        decimal.Decimal
-		lineTotalBase      decimal.Decimal
-		expenseAccountCode *string
-	}
-
-	var resolved []resolvedLine
-	var totalTransaction decimal.Decimal
+	gst := newGSTSupply(s.taxEngine, tx, companyID, poDate, "vendor "+vendorCode, vendorState)
+	var resolved []resolvedPOLine
+	var totalTransaction, taxTransaction decimal.Decimal
 
 	for i, input := range lines {
-		rl := resolvedLine{
-			description: input.Description,
-			quantity:    input.Quantity,
-			unitCost:    input.UnitCost,
-		}
-
-		if input.ProductCode != "" {
-			var pid int
-			var pcode, pname string
-			err := tx.QueryRow(ctx,
-				"SELECT id, code, name FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
-				companyID, input.ProductCode,
-			).Scan(&pid, &pcode, &pname)
-			if err != nil {
-				if errors.Is(err, pgx.ErrNoRows) {
-					return nil, fmt.Errorf("line %d: product %q not found", i+1, input.ProductCode)
-				}
-				return nil, fmt.Errorf("line %d: resolve product: %w", i+1, err)
-			}
-			rl.productID = &pid
-			rl.productCode = &pcode
-			rl.productName = &pname
+		rl, err := resolvePOLineTx(ctx, tx, companyID, input, exchangeRate)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
 		}
-
-		if input.ExpenseAccountCode != "" {
-			code := input.ExpenseAccountCode
-			rl.expenseAccountCode = &code
+		if err := rl.estimateTax(ctx, gst); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
 		}
-
-		lineTotal := input.Quantity.Mul(input.UnitCost)
-		rl.lineTotalTx = lineTotal
-		rl.lineTotalBase = lineTotal.Mul(exchangeRate)
-		totalTransaction = totalTransaction.Add(lineTotal)
+		totalTransaction = totalTransaction.Add(rl.lineTotalTx).Add(rl.taxAmountTx)
+		taxTransaction = taxTransaction.Add(rl.taxAmountTx)
 		resolved = append(resolved, rl)
 	}
 
@@ -109,28 +99,25 @@ func (s *purchaseOrderService) CreatePO(ctx context.Context, companyID, vendorID
 	var poID int
 	if err := tx.QueryRow(ctx, `
 		INSERT INTO purchase_orders (company_id, vendor_id, status, po_date, currency, exchange_rate,
-		                             total_transaction, total_base, notes)
-		VALUES ($1, $2, 'DRAFT', $3, 'INR', $4, $5, $6, $7)
+		                             total_transaction, total_base, tax_transaction, notes)
+		VALUES ($1, $2, 'DRAFT', $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`,
-		companyID, vendorID, poDate.Format("2006-01-02"), exchangeRate, totalTransaction, totalBase, toNotes,
+		companyID, vendorID, poDate.Format("2006-01-02"), currency, exchangeRate, totalTransaction, totalBase, taxTransaction, toNotes,
 	).Scan(&poID); err != nil {
 		return nil, fmt.Errorf("insert purchase order: %w", err)
 	}
 
 	// Insert lines
 	for i, rl := range resolved {
-		if _, err := tx.Exec(ctx, `
-			INSERT INTO purchase_order_lines
-			            (order_id, line_number, product_id, description, quantity, unit_cost,
-			             line_total_transaction, line_total_base, expense_account_code)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-			poID, i+1, rl.productID, rl.description, rl.quantity, rl.unitCost,
-			rl.lineTotalTx, rl.lineTotalBase, rl.expenseAccountCode,
-		); err != nil {
-			return nil, fmt.Errorf("insert PO line %d: %w", i+1, err)
+		if err := insertPOLineTx(ctx, tx, poID, i+1, rl); err != nil {
+			return nil, err
 		}
 	}
 
+	if err := recordPORevisionTx(ctx, tx, poID, 0, "CREATED", nil, "", ""); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("commit purchase order: %w", err)
 	}
@@ -138,7 +125,137 @@ func (s *purchaseOrderService) CreatePO(ctx context.Context, companyID, vendorID
 	return s.GetPO(ctx, poID)
 }
 
+// resolvePOExchangeRate validates a purchase order's currency and exchange rate.
+// The base currency always has rate 1; a foreign currency needs a positive rate.
+func resolvePOExchangeRate(currency, baseCurrency string, rate decimal.Decimal) (decimal.Decimal, error) {
+	if len(currency) != 3 {
+		return decimal.Zero, fmt.Errorf("invalid currency %q: expected a 3-letter ISO code", currency)
+	}
+	one := decimal.NewFromInt(1)
+	if currency == baseCurrency {
+		if !rate.IsZero() && !rate.Equal(one) {
+			return decimal.Zero, fmt.Errorf("exchange rate for base currency %s must be 1, got %s", currency, rate)
+		}
+		return one, nil
+	}
+	if !rate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("exchange rate to %s is required for a %s purchase order", baseCurrency, currency)
+	}
+	return rate, nil
+}
+
+// resolvedPOLine is a purchase order line input resolved against the product master.
+type resolvedPOLine struct {
+	productID          *int
+	productCode        *string
+	productName        *string
+	description        string
+	quantity           decimal.Decimal
+	uom                *string
+	uomFactor          decimal.Decimal
+	unitCost           decimal.Decimal
+	lineTotalTx        decimal.Decimal
+	lineTotalBase      decimal.Decimal
+	expenseAccountCode *string
+	taxCode            *string
+	taxAmountTx        decimal.Decimal
+	dimensions         []LineDimension
+}
+
+// resolvePOLineTx resolves a line's product and unit of measure and computes its totals.
+func resolvePOLineTx(ctx context.Context, tx pgx.Tx, companyID int, input PurchaseOrderLineInput, exchangeRate decimal.Decimal) (resolvedPOLine, error) {
+	rl := resolvedPOLine{
+		description: input.Description,
+		quantity:    input.Quantity,
+		uomFactor:   decimal.NewFromInt(1),
+		unitCost:    input.UnitCost,
+	}
+
+	if input.ProductCode != "" {
+		var pid int
+		var pcode, pname string
+		var purchaseUoM, taxCode *string
+		err := tx.QueryRow(ctx,
+			"SELECT id, code, name, purchase_uom, tax_code FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
+			companyID, input.ProductCode,
+		).Scan(&pid, &pcode, &pname, &purchaseUoM, &taxCode)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return rl, fmt.Errorf("product %q not found", input.ProductCode)
+			}
+			return rl, fmt.Errorf("resolve product: %w", err)
+		}
+		rl.productID = &pid
+		rl.productCode = &pcode
+		rl.productName = &pname
+		rl.taxCode = taxCode
+
+		uom := input.Unit
+		if uom == "" && purchaseUoM != nil {
+			uom = *purchaseUoM
+		}
+		uom, factor, err := resolveLineUoM(ctx, tx, pid, uom)
+		if err != nil {
+			return rl, err
+		}
+		rl.uom = &uom
+		rl.uomFactor = factor
+	} else if input.Unit != "" {
+		// Service/expense lines carry the unit as a label only; nothing is stocked.
+		uom := input.Unit
+		rl.uom = &uom
+	}
+
+	if input.ExpenseAccountCode != "" {
+		code := input.ExpenseAccountCode
+		rl.expenseAccountCode = &code
+	}
+	if input.TaxCode != "" {
+		code := strings.ToUpper(strings.TrimSpace(input.TaxCode))
+		rl.taxCode = &code
+	}
+	rl.dimensions = NormalizeLineDimensions(input.Dimensions)
+	if err := validateLineDimensionsTx(ctx, tx, companyID, rl.dimensions); err != nil {
+		return rl, err
+	}
+
+	lineTotal := input.Quantity.Mul(input.UnitCost)
+	rl.lineTotalTx = lineTotal
+	rl.lineTotalBase = lineTotal.Mul(exchangeRate)
+	return rl, nil
+}
+
+// estimateTax sets the line's estimated GST from its tax code; none without one.
+func (rl *resolvedPOLine) estimateTax(ctx context.Context, gst *gstSupply) error {
+	rl.taxAmountTx = decimal.Zero
+	if rl.taxCode == nil {
+		return nil
+	}
+	taxes, err := gst.lineTax(ctx, *rl.taxCode, rl.lineTotalTx.Round(2))
+	if err != nil {
+		return err
+	}
+	rl.taxAmountTx = sumTax(taxes)
+	return nil
+}
+
+// insertPOLineTx inserts a resolved line on a purchase order.
+func insertPOLineTx(ctx context.Context, tx pgx.Tx, poID, lineNumber int, rl resolvedPOLine) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO purchase_order_lines
+		            (order_id, line_number, product_id, description, quantity, uom, uom_factor, unit_cost,
+		             line_total_transaction, line_total_base, expense_account_code, tax_code, tax_amount_transaction, dimensions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		poID, lineNumber, rl.productID, rl.description, rl.quantity, rl.uom, rl.uomFactor, rl.unitCost,
+		rl.lineTotalTx, rl.lineTotalBase, rl.expenseAccountCode, rl.taxCode, rl.taxAmountTx, rl.dimensions,
+	); err != nil {
+		return fmt.Errorf("insert PO line %d: %w", lineNumber, err)
+	}
+	return nil
+}
+
 // ApprovePO transitions a DRAFT PO to APPROVED, assigning a gapless PO number.
+// A PO sent back for re-approval by AmendPO keeps the number it already has.
 // companyID must match the PO's company — returns an error if they differ.
 // Approving an already-APPROVED PO is a no-op.
 func (s *purchaseOrderService) ApprovePO(ctx context.Context, companyID, poID int, docService DocumentService) error {
@@ -148,12 +265,13 @@ func (s *purchaseOrderService) ApprovePO(ctx context.Context, companyID, poID in
 	}
 	defer tx.Rollback(ctx)
 
-	var poCompanyID int
+	var poCompanyID, revision int
 	var status string
+	var existingNumber *string
 	if err := tx.QueryRow(ctx,
-		"SELECT company_id, status FROM purchase_orders WHERE id = $1 FOR UPDATE",
+		"SELECT company_id, status, po_number, revision FROM purchase_orders WHERE id = $1 FOR UPDATE",
 		poID,
-	).Scan(&poCompanyID, &status); err != nil {
+	).Scan(&poCompanyID, &status, &existingNumber, &revision); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return fmt.Errorf("purchase order %d not found", poID)
 		}
@@ -173,6 +291,24 @@ func (s *purchaseOrderService) ApprovePO(ctx context.Context, companyID, poID in
 		return fmt.Errorf("purchase order %d cannot be approved: status is %s (must be DRAFT)", poID, status)
 	}
 
+	// Re-approval after an amendment: the PO keeps its number.
+	if existingNumber != nil {
+		if _, err := tx.Exec(ctx,
+			"UPDATE purchase_orders SET status = 'APPROVED', approved_at = NOW() WHERE id = $1",
+			poID,
+		); err != nil {
+			return fmt.Errorf("approve purchase order %d: %w", poID, err)
+		}
+		if err := recordPORevisionTx(ctx, tx, poID, revision, "APPROVED",
+			[]string{fmt.Sprintf("revision %d re-approved", revision)}, "", ""); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit PO approval: %w", err)
+		}
+		return nil
+	}
+
 	// Get current financial year for the PO document
 	var financialYear int
 	if err := tx.QueryRow(ctx,
@@ -216,6 +352,11 @@ func (s *purchaseOrderService) ApprovePO(ctx context.Context, companyID, poID in
 		return fmt.Errorf("approve purchase order %d: %w", poID, err)
 	}
 
+	if err := recordPORevisionTx(ctx, tx, poID, revision, "APPROVED",
+		[]string{"PO number " + poNumber + " assigned"}, "", ""); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit PO approval: %w", err)
 	}
@@ -223,10 +364,12 @@ func (s *purchaseOrderService) ApprovePO(ctx context.Context, companyID, poID in
 	return nil
 }
 
-// ReceivePO records goods and/or services received against an APPROVED purchase order.
+// ReceivePO records goods and/or services received against an APPROVED or PARTIALLY_RECEIVED
+// purchase order. Each line's received_quantity is increased, and may not exceed the ordered quantity.
 // Physical-goods lines (with product_id) update inventory via InventoryService.ReceiveStock.
 // Service/expense lines (with expense_account_code, no product_id) post DR expense / CR AP.
-// On success, transitions PO status to RECEIVED and sets received_at.
+// On success, transitions the PO to RECEIVED (setting received_at) once every line is fully
+// received, otherwise to PARTIALLY_RECEIVED.
 func (s *purchaseOrderService) ReceivePO(ctx context.Context, poID int, warehouseCode, companyCode string,
 	receivedLines []ReceivedLine, apAccountCode string,
 	ledger *Ledger, docService DocumentService, inv InventoryService) error {
@@ -240,8 +383,8 @@ func (s *purchaseOrderService) ReceivePO(ctx context.Context, poID int, warehous
 	if err != nil {
 		return err
 	}
-	if po.Status != "APPROVED" {
-		return fmt.Errorf("purchase order %d cannot be received: status is %s (must be APPROVED)", poID, po.Status)
+	if po.Status != "APPROVED" && po.Status != "PARTIALLY_RECEIVED" {
+		return fmt.Errorf("purchase order %d cannot be received: status is %s (must be APPROVED or PARTIALLY_RECEIVED)", poID, po.Status)
 	}
 
 	// Build a map of PO lines by ID for quick lookup
@@ -253,6 +396,24 @@ func (s *purchaseOrderService) ReceivePO(ctx context.Context, poID int, warehous
 	// Resolve movement date: use today
 	movementDate := time.Now().Format("2006-01-02")
 
+	// The whole receipt is one transaction: quantities claimed, stock and journal entries
+	// posted and the PO status moved together, or none of it.
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx,
+		"SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", poID,
+	).Scan(&status); err != nil {
+		return fmt.Errorf("lock purchase order %d: %w", poID, err)
+	}
+	if status != "APPROVED" && status != "PARTIALLY_RECEIVED" {
+		return fmt.Errorf("purchase order %d cannot be received: status is %s (must be APPROVED or PARTIALLY_RECEIVED)", poID, status)
+	}
+
 	// Process each received line
 	for _, rl := range receivedLines {
 		if rl.QtyReceived.IsZero() || rl.QtyReceived.IsNegative() {
@@ -263,284 +424,1433 @@ func (s *purchaseOrderService) ReceivePO(ctx context.Context, poID int, warehous
 		if !ok {
 			return fmt.Errorf("PO line %d not found on purchase order %d", rl.POLineID, poID)
 		}
+		if pol.ProductID == nil && (pol.ExpenseAccountCode == nil || *pol.ExpenseAccountCode == "") {
+			return fmt.Errorf("PO line %d has no product or expense account code — cannot receive", pol.ID)
+		}
 
-		if pol.ProductID != nil {
-			// Physical goods line — check cumulative received qty does not exceed ordered qty.
-			var alreadyReceived decimal.Decimal
-			if err := s.pool.QueryRow(ctx, `
-				SELECT COALESCE(SUM(im.quantity), 0)
-				FROM inventory_movements im
-				WHERE im.po_line_id = $1 AND im.movement_type = 'RECEIPT'`,
-				pol.ID,
-			).Scan(&alreadyReceived); err != nil {
-				return fmt.Errorf("check received quantity for PO line %d: %w", pol.ID, err)
-			}
-			totalAfterReceipt := alreadyReceived.Add(rl.QtyReceived)
-			if totalAfterReceipt.GreaterThan(pol.Quantity) {
-				return fmt.Errorf(
-					"PO line %d: would receive %s but only %s ordered (already received %s)",
-					pol.ID, totalAfterReceipt.StringFixed(4), pol.Quantity.StringFixed(4),
-					alreadyReceived.StringFixed(4),
-				)
-			}
-
-			// Receive into inventory
-			productCode := ""
-			if pol.ProductCode != nil {
-				productCode = *pol.ProductCode
-			}
-			lineID := pol.ID
-			if err := inv.ReceiveStock(ctx, companyCode, warehouseCode, productCode,
-				rl.QtyReceived, pol.UnitCost, movementDate, apAccountCode,
-				&lineID, ledger, docService); err != nil {
-				return fmt.Errorf("receive inventory for PO line %d (product %s): %w", pol.ID, productCode, err)
-			}
-		} else if pol.ExpenseAccountCode != nil && *pol.ExpenseAccountCode != "" {
-			// Service/expense line — post DR expense / CR AP
-			lineAmount := rl.QtyReceived.Mul(pol.UnitCost)
-			var baseCurrency string
-			if err := s.pool.QueryRow(ctx,
-				"SELECT base_currency FROM companies WHERE company_code = $1", companyCode,
-			).Scan(&baseCurrency); err != nil {
-				return fmt.Errorf("resolve company currency: %w", err)
-			}
+		receivedAfter, err := addReceivedQuantityTx(ctx, tx, pol, rl.QtyReceived)
+		if err != nil {
+			return err
+		}
 
-			proposal := Proposal{
-				DocumentTypeCode:    "GR",
-				CompanyCode:         companyCode,
-				IdempotencyKey:      fmt.Sprintf("po-%d-line-%d-service-receipt", poID, pol.ID),
-				TransactionCurrency: baseCurrency,
-				ExchangeRate:        "1",
-				Summary:             fmt.Sprintf("Service receipt: %s (PO %d, line %d)", pol.Description, poID, pol.LineNumber),
-				PostingDate:         movementDate,
-				DocumentDate:        movementDate,
-				Confidence:          1.0,
-				Reasoning:           fmt.Sprintf("Service/expense line received against PO %d line %d.", poID, pol.LineNumber),
-				Lines: []ProposalLine{
-					{AccountCode: *pol.ExpenseAccountCode, IsDebit: true, Amount: lineAmount.StringFixed(2)},
-					{AccountCode: apAccountCode, IsDebit: false, Amount: lineAmount.StringFixed(2)},
-				},
-			}
-			if err := ledger.Commit(ctx, proposal); err != nil {
-				return fmt.Errorf("post service receipt journal entry for PO line %d: %w", pol.ID, err)
-			}
-		} else {
-			return fmt.Errorf("PO line %d has no product or expense account code — cannot receive", pol.ID)
+		if err := s.postLineReceiptTx(ctx, tx, po, pol, rl, receivedAfter, warehouseCode, companyCode,
+			movementDate, apAccountCode, ledger, docService, inv); err != nil {
+			return err
 		}
 	}
 
-	// Transition PO to RECEIVED
-	if _, err := s.pool.Exec(ctx, `
-		UPDATE purchase_orders
-		SET status = 'RECEIVED', received_at = NOW()
-		WHERE id = $1`,
+	// Transition PO to RECEIVED once every line is in, otherwise PARTIALLY_RECEIVED.
+	if _, err := tx.Exec(ctx, `
+		UPDATE purchase_orders po
+		SET status = CASE WHEN full.done THEN 'RECEIVED' ELSE 'PARTIALLY_RECEIVED' END,
+		    received_at = CASE WHEN full.done THEN NOW() ELSE po.received_at END
+		FROM (
+		    SELECT bool_and(received_quantity >= quantity) AS done
+		    FROM purchase_order_lines
+		    WHERE order_id = $1
+		) full
+		WHERE po.id = $1`,
 		poID,
 	); err != nil {
-		return fmt.Errorf("update PO %d status to RECEIVED: %w", poID, err)
+		return fmt.Errorf("update PO %d receipt status: %w", poID, err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit goods receipt for PO %d: %w", poID, err)
+	}
 	return nil
 }
 
-// RecordVendorInvoice records the vendor's invoice against a RECEIVED purchase order.
-// companyID must match the PO's company — returns an error if they differ.
-// Creates and posts a PI document. Warns if invoiceAmount deviates > 5% from PO total.
-// Transitions status to INVOICED. Returns a non-empty warning if amount deviation > 5%.
-func (s *purchaseOrderService) RecordVendorInvoice(ctx context.Context, companyID, poID int,
-	invoiceNumber string, invoiceDate time.Time, invoiceAmount decimal.Decimal,
-	docService DocumentService) (string, error) {
-
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return "", fmt.Errorf("begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	var poCompanyID int
-	var status string
-	var totalBase decimal.Decimal
-	if err := tx.QueryRow(ctx,
-		"SELECT company_id, status, total_base FROM purchase_orders WHERE id = $1 FOR UPDATE",
-		poID,
-	).Scan(&poCompanyID, &status, &totalBase); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return "", fmt.Errorf("purchase order %d not found", poID)
+// addReceivedQuantityTx increases a PO line's received_quantity by qty (in the line's unit),
+// failing if that would exceed the ordered quantity. Returns the new cumulative quantity.
+func addReceivedQuantityTx(ctx context.Context, tx pgx.Tx, pol PurchaseOrderLine, qty decimal.Decimal) (decimal.Decimal, error) {
+	var receivedAfter decimal.Decimal
+	err := tx.QueryRow(ctx, `
+		UPDATE purchase_order_lines
+		SET received_quantity = received_quantity + $1
+		WHERE id = $2 AND received_quantity + $1 <= quantity
+		RETURNING received_quantity`,
+		qty, pol.ID,
+	).Scan(&receivedAfter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var already decimal.Decimal
+		if err := tx.QueryRow(ctx,
+			"SELECT received_quantity FROM purchase_order_lines WHERE id = $1", pol.ID,
+		).Scan(&already); err != nil {
+			return decimal.Zero, fmt.Errorf("check received quantity for PO line %d: %w", pol.ID, err)
 		}
-		return "", fmt.Errorf("fetch purchase order %d: %w", poID, err)
+		return decimal.Zero, fmt.Errorf(
+			"PO line %d: would receive %s but only %s ordered (already received %s)",
+			pol.ID, already.Add(qty).StringFixed(4), pol.Quantity.StringFixed(4), already.StringFixed(4),
+		)
 	}
-
-	if poCompanyID != companyID {
-		return "", fmt.Errorf("purchase order %d does not belong to company %d", poID, companyID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("update received quantity for PO line %d: %w", pol.ID, err)
 	}
+	return receivedAfter, nil
+}
 
-	if status != "RECEIVED" {
-		return "", fmt.Errorf("purchase order %d cannot be invoiced: status is %s (must be RECEIVED)", poID, status)
-	}
+// postLineReceiptTx books one received PO line in the PO currency: goods into inventory
+// (DR Inventory / CR AP via ReceiveStockTx), services as DR expense / CR AP.
+// receivedAfter (the line's cumulative received quantity) keeps the service receipt
+// idempotency key unique per receipt.
+func (s *purchaseOrderService) postLineReceiptTx(ctx context.Context, tx pgx.Tx, po *PurchaseOrder, pol PurchaseOrderLine, rl ReceivedLine,
+	receivedAfter decimal.Decimal, warehouseCode, companyCode, movementDate, apAccountCode string,
+	ledger *Ledger, docService DocumentService, inv InventoryService) error {
 
-	// Check for > 5% amount deviation
-	var warning string
-	if !totalBase.IsZero() {
-		deviation := invoiceAmount.Sub(totalBase).Abs().Div(totalBase)
-		threshold := decimal.NewFromFloat(0.05)
-		if deviation.GreaterThan(threshold) {
-			pct := deviation.Mul(decimal.NewFromInt(100)).StringFixed(1)
-			warning = fmt.Sprintf("invoice amount %s deviates %s%% from PO total %s (threshold 5%%)",
-				invoiceAmount.StringFixed(2), pct, totalBase.StringFixed(2))
+	if pol.ProductID != nil {
+		// Movements are in stock units; the line is converted with the factor captured on the PO.
+		productCode := ""
+		if pol.ProductCode != nil {
+			productCode = *pol.ProductCode
 		}
+		lineID := pol.ID
+		stockQty := rl.QtyReceived.Mul(pol.UnitFactor)
+		stockUnitCost := pol.UnitCost.Div(pol.UnitFactor)
+		if err := inv.ReceiveStockTx(ctx, tx, companyCode, warehouseCode, productCode,
+			stockQty, stockUnitCost, rl.Lots, movementDate, apAccountCode,
+			po.Currency, po.ExchangeRate, &lineID, ledger, docService); err != nil {
+			return fmt.Errorf("receive inventory for PO line %d (product %s): %w", pol.ID, productCode, err)
+		}
+		return nil
 	}
 
-	financialYear := invoiceDate.Year()
-
-	// Create DRAFT PI document inside this transaction
-	var draftDocID int
-	if err := tx.QueryRow(ctx, `
-		INSERT INTO documents (company_id, type_code, status, financial_year, branch_id)
-		VALUES ($1, 'PI', 'DRAFT', $2, NULL)
-		RETURNING id`,
-		companyID, financialYear,
-	).Scan(&draftDocID); err != nil {
-		return "", fmt.Errorf("create PI document: %w", err)
+	// Service/expense line — post DR expense / CR AP
+	lineAmount := rl.QtyReceived.Mul(pol.UnitCost)
+	proposal := Proposal{
+		DocumentTypeCode:    "GR",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      fmt.Sprintf("po-%d-line-%d-service-receipt-%s", po.ID, pol.ID, receivedAfter.String()),
+		TransactionCurrency: po.Currency,
+		ExchangeRate:        po.ExchangeRate.String(),
+		Summary:             fmt.Sprintf("Service receipt: %s (PO %d, line %d)", pol.Description, po.ID, pol.LineNumber),
+		PostingDate:         movementDate,
+		DocumentDate:        movementDate,
+		Confidence:          1.0,
+		Reasoning:           fmt.Sprintf("Service/expense line received against PO %d line %d.", po.ID, pol.LineNumber),
+		Lines: []ProposalLine{
+			{AccountCode: *pol.ExpenseAccountCode, IsDebit: true, Amount: lineAmount.StringFixed(2), Dimensions: pol.Dimensions},
+			{AccountCode: apAccountCode, IsDebit: false, Amount: lineAmount.StringFixed(2)},
+		},
 	}
-
-	if err := docService.PostDocumentTx(ctx, tx, draftDocID); err != nil {
-		return "", fmt.Errorf("post PI document: %w", err)
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return fmt.Errorf("post service receipt journal entry for PO line %d: %w", pol.ID, err)
 	}
+	return nil
+}
 
-	var piDocNumber string
-	if err := tx.QueryRow(ctx,
-		"SELECT document_number FROM documents WHERE id = $1",
-		draftDocID,
-	).Scan(&piDocNumber); err != nil {
-		return "", fmt.Errorf("retrieve PI document number: %w", err)
+// ShortClosePO closes a PARTIALLY_RECEIVED purchase order: the undelivered balance is
+// cancelled and the PO moves to RECEIVED so that it can be invoiced for what arrived.
+func (s *purchaseOrderService) ShortClosePO(ctx context.Context, companyID, poID int, reason, closedBy string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	if _, err := tx.Exec(ctx, `
+	reason = strings.TrimSpace(reason)
+	var revision int
+	err = tx.QueryRow(ctx, `
 		UPDATE purchase_orders
-		SET status = 'INVOICED',
-		    invoice_number     = $1,
-		    invoice_date       = $2,
-		    invoice_amount     = $3,
-		    pi_document_number = $4,
-		    invoiced_at        = NOW()
-		WHERE id = $5`,
-		invoiceNumber, invoiceDate.Format("2006-01-02"), invoiceAmount, piDocNumber, poID,
-	); err != nil {
-		return "", fmt.Errorf("update PO %d to INVOICED: %w", poID, err)
+		SET status = 'RECEIVED', received_at = NOW(),
+		    short_closed_at = NOW(), short_close_reason = NULLIF($1, '')
+		WHERE id = $2 AND company_id = $3 AND status = 'PARTIALLY_RECEIVED'
+		RETURNING revision`,
+		reason, poID, companyID,
+	).Scan(&revision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		status, err := s.poStatusTx(ctx, tx, companyID, poID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("purchase order %d cannot be short-closed: status is %s (must be PARTIALLY_RECEIVED)", poID, status)
+	}
+	if err != nil {
+		return fmt.Errorf("short-close purchase order %d: %w", poID, err)
 	}
 
+	if err := recordPORevisionTx(ctx, tx, poID, revision, "CLOSED",
+		[]string{"undelivered balance cancelled"}, reason, closedBy); err != nil {
+		return err
+	}
 	if err := tx.Commit(ctx); err != nil {
-		return "", fmt.Errorf("commit vendor invoice: %w", err)
+		return fmt.Errorf("commit short-close: %w", err)
 	}
+	return nil
+}
 
-	return warning, nil
+// poStatusTx returns a purchase order's status, or a not-found error if it does not
+// belong to the company.
+func (s *purchaseOrderService) poStatusTx(ctx context.Context, tx pgx.Tx, companyID, poID int) (string, error) {
+	var status string
+	if err := tx.QueryRow(ctx,
+		"SELECT status FROM purchase_orders WHERE id = $1 AND company_id = $2", poID, companyID,
+	).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("purchase order %d not found", poID)
+		}
+		return "", fmt.Errorf("fetch purchase order %d: %w", poID, err)
+	}
+	return status, nil
 }
 
-// PayVendor records payment against an INVOICED purchase order.
-// Posts DR AP / CR Bank and transitions status to PAID.
-func (s *purchaseOrderService) PayVendor(ctx context.Context, poID int,
-	bankAccountCode string, paymentDate time.Time, companyCode string, ledger *Ledger) error {
+// ── Amendments and cancellation ──────────────────────────────────────────────
+
+// AmendPO changes an APPROVED purchase order's lines and/or expected delivery date in one
+// transaction: line totals and the PO total are recomputed, the revision is incremented and
+// an AMENDED entry listing every change is added to the revision history. With
+// RequireReapproval the PO returns to DRAFT until ApprovePO is called again.
+func (s *purchaseOrderService) AmendPO(ctx context.Context, companyID, poID int, amendment PurchaseOrderAmendment) (*PurchaseOrder, error) {
+	if amendment.Lines != nil && len(amendment.Lines) == 0 {
+		return nil, fmt.Errorf("amended purchase order must have at least one line")
+	}
 
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	var companyID int
-	var status string
-	var invoiceAmount *decimal.Decimal
-	var totalBase decimal.Decimal
-	var apAccountCode string
+	var status, vendorCode string
+	var revision int
+	var expected, vendorState *string
+	var poDate time.Time
+	var exchangeRate, oldTotal decimal.Decimal
 	if err := tx.QueryRow(ctx, `
-		SELECT po.company_id, po.status, po.invoice_amount, po.total_base,
-		       COALESCE(v.ap_account_code, '2000')
+		SELECT po.status, po.revision, po.expected_delivery_date::text, po.exchange_rate, po.total_transaction,
+		       po.po_date, v.code, v.state_code
 		FROM purchase_orders po
 		JOIN vendors v ON v.id = po.vendor_id
-		WHERE po.id = $1
+		WHERE po.id = $1 AND po.company_id = $2
 		FOR UPDATE OF po`,
-		poID,
-	).Scan(&companyID, &status, &invoiceAmount, &totalBase, &apAccountCode); err != nil {
+		poID, companyID,
+	).Scan(&status, &revision, &expected, &exchangeRate, &oldTotal, &poDate, &vendorCode, &vendorState); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("purchase order %d not found", poID)
+			return nil, fmt.Errorf("purchase order %d not found", poID)
 		}
-		return fmt.Errorf("fetch purchase order %d: %w", poID, err)
+		return nil, fmt.Errorf("fetch purchase order %d: %w", poID, err)
 	}
-
-	if status != "INVOICED" {
-		return fmt.Errorf("purchase order %d cannot be paid: status is %s (must be INVOICED)", poID, status)
+	if status != "APPROVED" {
+		return nil, fmt.Errorf("purchase order %d cannot be amended: status is %s (must be APPROVED)", poID, status)
 	}
 
-	// Verify the PO's company matches the supplied companyCode.
-	var expectedCompanyID int
-	if err := tx.QueryRow(ctx,
-		"SELECT id FROM companies WHERE company_code = $1", companyCode,
-	).Scan(&expectedCompanyID); err != nil {
-		return fmt.Errorf("resolve company %s: %w", companyCode, err)
-	}
-	if companyID != expectedCompanyID {
-		return fmt.Errorf("purchase order %d does not belong to company %s", poID, companyCode)
+	var changes []string
+
+	if amendment.ExpectedDeliveryDate != "" {
+		d, err := time.Parse("2006-01-02", amendment.ExpectedDeliveryDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected delivery date %q (expected YYYY-MM-DD)", amendment.ExpectedDeliveryDate)
+		}
+		newDate := d.Format("2006-01-02")
+		if expected == nil || *expected != newDate {
+			old := "none"
+			if expected != nil {
+				old = *expected
+			}
+			if _, err := tx.Exec(ctx,
+				"UPDATE purchase_orders SET expected_delivery_date = $1 WHERE id = $2", newDate, poID,
+			); err != nil {
+				return nil, fmt.Errorf("update expected delivery date: %w", err)
+			}
+			changes = append(changes, fmt.Sprintf("expected delivery %s → %s", old, newDate))
+		}
 	}
 
-	// Use invoice amount if recorded, otherwise fall back to PO total
-	paymentAmount := totalBase
-	if invoiceAmount != nil && !invoiceAmount.IsZero() {
-		paymentAmount = *invoiceAmount
+	if amendment.Lines != nil {
+		gst := newGSTSupply(s.taxEngine, tx, companyID, poDate, "vendor "+vendorCode, vendorState)
+		lineChanges, err := amendPOLinesTx(ctx, tx, companyID, poID, exchangeRate, gst, amendment.Lines)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, lineChanges...)
 	}
-	paymentDateStr := paymentDate.Format("2006-01-02")
 
-	proposal := Proposal{
-		DocumentTypeCode:    "JE",
-		CompanyCode:         companyCode,
-		IdempotencyKey:      fmt.Sprintf("pay-vendor-po-%d", poID),
-		TransactionCurrency: "INR",
-		ExchangeRate:        "1",
-		Summary:             fmt.Sprintf("Vendor payment for PO %d", poID),
-		PostingDate:         paymentDateStr,
-		DocumentDate:        paymentDateStr,
-		Confidence:          1.0,
-		Reasoning:           fmt.Sprintf("Payment of vendor invoice for purchase order %d.", poID),
-		Lines: []ProposalLine{
-			{AccountCode: apAccountCode, IsDebit: true, Amount: paymentAmount.StringFixed(2)},
-			{AccountCode: bankAccountCode, IsDebit: false, Amount: paymentAmount.StringFixed(2)},
-		},
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("amendment of purchase order %d changes nothing", poID)
 	}
 
-	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
-		return fmt.Errorf("post payment journal entry for PO %d: %w", poID, err)
+	var newTotal decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		UPDATE purchase_orders po
+		SET total_transaction = t.total_tx + t.tax_tx,
+		    total_base = t.total_base + t.tax_tx * po.exchange_rate,
+		    tax_transaction = t.tax_tx,
+		    revision = po.revision + 1,
+		    status = CASE WHEN $2 THEN 'DRAFT' ELSE po.status END,
+		    approved_at = CASE WHEN $2 THEN NULL ELSE po.approved_at END
+		FROM (
+		    SELECT COALESCE(SUM(line_total_transaction), 0) AS total_tx,
+		           COALESCE(SUM(line_total_base), 0) AS total_base,
+		           COALESCE(SUM(tax_amount_transaction), 0) AS tax_tx
+		    FROM purchase_order_lines
+		    WHERE order_id = $1
+		) t
+		WHERE po.id = $1
+		RETURNING po.total_transaction`,
+		poID, amendment.RequireReapproval,
+	).Scan(&newTotal); err != nil {
+		return nil, fmt.Errorf("update purchase order %d: %w", poID, err)
+	}
+	if !newTotal.Equal(oldTotal) {
+		changes = append(changes, fmt.Sprintf("total %s → %s", oldTotal.StringFixed(2), newTotal.StringFixed(2)))
+	}
+	if amendment.RequireReapproval {
+		changes = append(changes, "returned to DRAFT for re-approval")
 	}
 
-	if _, err := tx.Exec(ctx,
-		"UPDATE purchase_orders SET status = 'PAID', paid_at = NOW() WHERE id = $1",
-		poID,
-	); err != nil {
-		return fmt.Errorf("update PO %d to PAID: %w", poID, err)
+	if err := recordPORevisionTx(ctx, tx, poID, revision+1, "AMENDED", changes,
+		strings.TrimSpace(amendment.Reason), amendment.AmendedBy); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit vendor payment: %w", err)
+		return nil, fmt.Errorf("commit purchase order amendment: %w", err)
 	}
-
-	return nil
+	return s.GetPO(ctx, poID)
 }
 
-// getPOForCompany fetches a PO by ID, asserting it belongs to the given companyCode.
-// Returns pgx.ErrNoRows-wrapped error (indistinguishable from not-found) if ownership fails
-// to prevent PO enumeration across companies.
-func (s *purchaseOrderService) getPOForCompany(ctx context.Context, poID int, companyCode string) (*PurchaseOrder, error) {
-	var exists bool
-	if err := s.pool.QueryRow(ctx, `
-		SELECT EXISTS(
-			SELECT 1 FROM purchase_orders po
-			JOIN companies c ON c.id = po.company_id
-			WHERE po.id = $1 AND c.company_code = $2
-		)`, poID, companyCode,
-	).Scan(&exists); err != nil {
-		return nil, fmt.Errorf("purchase order ownership check: %w", err)
+// amendPOLinesTx replaces a purchase order's lines with the amended set and returns a
+// description of each change. Existing lines keep their line numbers and tax code; new
+// lines are numbered after the highest existing one. GST is re-estimated on changed lines.
+func amendPOLinesTx(ctx context.Context, tx pgx.Tx, companyID, poID int, exchangeRate decimal.Decimal,
+	gst *gstSupply, lines []PurchaseOrderAmendLine) ([]string, error) {
+
+	type existingLine struct {
+		id          int
+		lineNumber  int
+		description string
+		quantity    decimal.Decimal
+		unitCost    decimal.Decimal
+		received    decimal.Decimal
+		taxCode     *string
 	}
-	if !exists {
-		return nil, fmt.Errorf("purchase order %d not found", poID)
-	}
-	return s.GetPO(ctx, poID)
+	rows, err := tx.Query(ctx, `
+		SELECT id, line_number, description, quantity, unit_cost, received_quantity, tax_code
+		FROM purchase_order_lines
+		WHERE order_id = $1
+		ORDER BY line_number
+		FOR UPDATE`,
+		poID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch PO lines for order %d: %w", poID, err)
+	}
+	var existing []existingLine
+	byID := make(map[int]existingLine)
+	maxLineNumber := 0
+	for rows.Next() {
+		var l existingLine
+		if err := rows.Scan(&l.id, &l.lineNumber, &l.description, &l.quantity, &l.unitCost, &l.received, &l.taxCode); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan PO line: %w", err)
+		}
+		existing = append(existing, l)
+		byID[l.id] = l
+		if l.lineNumber > maxLineNumber {
+			maxLineNumber = l.lineNumber
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetch PO lines for order %d: %w", poID, err)
+	}
+
+	var changes []string
+	kept := make(map[int]bool)
+	for i, in := range lines {
+		if !in.Quantity.IsPositive() {
+			return nil, fmt.Errorf("line %d: quantity must be positive", i+1)
+		}
+		if in.UnitCost.IsNegative() {
+			return nil, fmt.Errorf("line %d: unit cost cannot be negative", i+1)
+		}
+
+		if in.POLineID == 0 {
+			rl, err := resolvePOLineTx(ctx, tx, companyID, PurchaseOrderLineInput{
+				ProductCode:        in.ProductCode,
+				Description:        in.Description,
+				Quantity:           in.Quantity,
+				Unit:               in.Unit,
+				UnitCost:           in.UnitCost,
+				ExpenseAccountCode: in.ExpenseAccountCode,
+				TaxCode:            in.TaxCode,
+				Dimensions:         in.Dimensions,
+			}, exchangeRate)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := rl.estimateTax(ctx, gst); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			maxLineNumber++
+			if err := insertPOLineTx(ctx, tx, poID, maxLineNumber, rl); err != nil {
+				return nil, err
+			}
+			changes = append(changes, fmt.Sprintf("line %d added: %s %s × %s",
+				maxLineNumber, rl.description, rl.quantity.String(), rl.unitCost.StringFixed(2)))
+			continue
+		}
+
+		l, ok := byID[in.POLineID]
+		if !ok {
+			return nil, fmt.Errorf("PO line %d not found on purchase order %d", in.POLineID, poID)
+		}
+		if kept[l.id] {
+			return nil, fmt.Errorf("PO line %d is listed more than once", l.id)
+		}
+		kept[l.id] = true
+		if in.Quantity.LessThan(l.received) {
+			return nil, fmt.Errorf("PO line %d: quantity %s is below the %s already received",
+				l.id, in.Quantity.String(), l.received.String())
+		}
+
+		description := l.description
+		if in.Description != "" {
+			description = in.Description
+		}
+		if in.Quantity.Equal(l.quantity) && in.UnitCost.Equal(l.unitCost) && description == l.description {
+			continue
+		}
+		lineTotal := in.Quantity.Mul(in.UnitCost)
+		rl := resolvedPOLine{lineTotalTx: lineTotal, taxCode: l.taxCode}
+		if err := rl.estimateTax(ctx, gst); err != nil {
+			return nil, fmt.Errorf("PO line %d: %w", l.id, err)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE purchase_order_lines
+			SET quantity = $1, unit_cost = $2, description = $3,
+			    line_total_transaction = $4, line_total_base = $5, tax_amount_transaction = $6
+			WHERE id = $7`,
+			in.Quantity, in.UnitCost, description, lineTotal, lineTotal.Mul(exchangeRate), rl.taxAmountTx, l.id,
+		); err != nil {
+			return nil, fmt.Errorf("update PO line %d: %w", l.id, err)
+		}
+		if !in.Quantity.Equal(l.quantity) {
+			changes = append(changes, fmt.Sprintf("line %d quantity %s → %s", l.lineNumber, l.quantity.String(), in.Quantity.String()))
+		}
+		if !in.UnitCost.Equal(l.unitCost) {
+			changes = append(changes, fmt.Sprintf("line %d unit cost %s → %s", l.lineNumber, l.unitCost.StringFixed(2), in.UnitCost.StringFixed(2)))
+		}
+		if description != l.description {
+			changes = append(changes, fmt.Sprintf("line %d description %q → %q", l.lineNumber, l.description, description))
+		}
+	}
+
+	for _, l := range existing {
+		if kept[l.id] {
+			continue
+		}
+		if l.received.IsPositive() {
+			return nil, fmt.Errorf("PO line %d has received quantity and cannot be removed", l.id)
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM purchase_order_lines WHERE id = $1", l.id); err != nil {
+			return nil, fmt.Errorf("remove PO line %d: %w", l.id, err)
+		}
+		changes = append(changes, fmt.Sprintf("line %d removed: %s", l.lineNumber, l.description))
+	}
+	return changes, nil
+}
+
+// CancelPO cancels a DRAFT or APPROVED purchase order on which nothing has been received.
+func (s *purchaseOrderService) CancelPO(ctx context.Context, companyID, poID int, reason, cancelledBy string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reason = strings.TrimSpace(reason)
+	var revision int
+	err = tx.QueryRow(ctx, `
+		UPDATE purchase_orders po
+		SET status = 'CANCELLED', cancelled_at = NOW(), cancel_reason = NULLIF($1, '')
+		WHERE po.id = $2 AND po.company_id = $3 AND po.status IN ('DRAFT', 'APPROVED')
+		  AND NOT EXISTS (
+		      SELECT 1 FROM purchase_order_lines pol
+		      WHERE pol.order_id = po.id AND pol.received_quantity > 0
+		  )
+		RETURNING po.revision`,
+		reason, poID, companyID,
+	).Scan(&revision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		status, err := s.poStatusTx(ctx, tx, companyID, poID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("purchase order %d cannot be cancelled: status is %s (must be DRAFT or APPROVED with nothing received)", poID, status)
+	}
+	if err != nil {
+		return fmt.Errorf("cancel purchase order %d: %w", poID, err)
+	}
+
+	if err := recordPORevisionTx(ctx, tx, poID, revision, "CANCELLED", nil, reason, cancelledBy); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit cancellation: %w", err)
+	}
+	return nil
+}
+
+// GetPORevisions returns a purchase order's revision history, oldest first.
+func (s *purchaseOrderService) GetPORevisions(ctx context.Context, companyID, poID int) ([]PurchaseOrderRevision, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT r.id, r.po_id, r.revision, r.action, r.changes, r.reason, r.changed_by, r.changed_at
+		FROM purchase_order_revisions r
+		JOIN purchase_orders po ON po.id = r.po_id
+		WHERE r.po_id = $1 AND po.company_id = $2
+		ORDER BY r.id`,
+		poID, companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get revisions for purchase order %d: %w", poID, err)
+	}
+	defer rows.Close()
+
+	var revisions []PurchaseOrderRevision
+	for rows.Next() {
+		var r PurchaseOrderRevision
+		if err := rows.Scan(&r.ID, &r.POID, &r.Revision, &r.Action, &r.Changes, &r.Reason, &r.ChangedBy, &r.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scan purchase order revision: %w", err)
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
+
+// recordPORevisionTx appends an entry to a purchase order's revision history.
+func recordPORevisionTx(ctx context.Context, tx pgx.Tx, poID, revision int, action string,
+	changes []string, reason, changedBy string) error {
+	if changes == nil {
+		changes = []string{}
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO purchase_order_revisions (po_id, revision, action, changes, reason, changed_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))`,
+		poID, revision, action, changes, reason, changedBy,
+	); err != nil {
+		return fmt.Errorf("record purchase order %d %s: %w", poID, strings.ToLower(action), err)
+	}
+	return nil
+}
+
+// RecordVendorInvoice records the vendor's invoice against a RECEIVED purchase order and
+// three-way matches it: each invoice line is compared with its PO line and with the
+// line's received quantity.
+// companyID must match the PO's company — returns an error if they differ.
+// Creates and posts a PI document and transitions status to INVOICED. If every line is
+// within tolerance the variance is posted immediately; otherwise the PO is blocked for
+// payment and the returned warning lists the exceptions.
+func (s *purchaseOrderService) RecordVendorInvoice(ctx context.Context, companyID, poID int,
+	invoiceNumber string, invoiceDate time.Time, invoiceAmount decimal.Decimal,
+	lines []VendorInvoiceLineInput, ledger *Ledger, docService DocumentService) (string, error) {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var poCompanyID int
+	var status string
+	if err := tx.QueryRow(ctx,
+		"SELECT company_id, status FROM purchase_orders WHERE id = $1 FOR UPDATE",
+		poID,
+	).Scan(&poCompanyID, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("purchase order %d not found", poID)
+		}
+		return "", fmt.Errorf("fetch purchase order %d: %w", poID, err)
+	}
+
+	if poCompanyID != companyID {
+		return "", fmt.Errorf("purchase order %d does not belong to company %d", poID, companyID)
+	}
+
+	if status != "RECEIVED" {
+		return "", fmt.Errorf("purchase order %d cannot be invoiced: status is %s (must be RECEIVED)", poID, status)
+	}
+
+	poLines, err := s.fetchLines(ctx, poID)
+	if err != nil {
+		return "", err
+	}
+	// Match against what was kept: goods returned before the invoice are on a debit note.
+	received := make(map[int]decimal.Decimal, len(poLines))
+	for _, l := range poLines {
+		received[l.ID] = l.ReturnableQuantity()
+	}
+	tol, err := s.GetMatchTolerance(ctx, companyID)
+	if err != nil {
+		return "", err
+	}
+
+	matched, err := buildInvoiceLines(poLines, received, lines, invoiceAmount)
+	if err != nil {
+		return "", fmt.Errorf("purchase order %d: %w", poID, err)
+	}
+	// GST on each invoiced line at the invoice date, under the PO line's tax code.
+	var vendorCode string
+	var vendorState *string
+	if err := tx.QueryRow(ctx, `
+		SELECT v.code, v.state_code FROM purchase_orders po JOIN vendors v ON v.id = po.vendor_id
+		WHERE po.id = $1`,
+		poID,
+	).Scan(&vendorCode, &vendorState); err != nil {
+		return "", fmt.Errorf("fetch vendor of PO %d: %w", poID, err)
+	}
+	gst := newGSTSupply(s.taxEngine, tx, companyID, invoiceDate, "vendor "+vendorCode, vendorState)
+	taxCodes := make(map[int]*string, len(poLines))
+	for _, l := range poLines {
+		taxCodes[l.ID] = l.TaxCode
+	}
+
+	total, taxTotal := decimal.Zero, decimal.Zero
+	var allTaxes []LineTax
+	var exceptions []string
+	for i := range matched {
+		applyMatchTolerance(&matched[i], tol)
+		if code := taxCodes[matched[i].POLineID]; code != nil && matched[i].LineAmount.IsPositive() {
+			taxes, err := gst.lineTax(ctx, *code, matched[i].LineAmount)
+			if err != nil {
+				return "", fmt.Errorf("PO line %d: %w", matched[i].POLineID, err)
+			}
+			matched[i].Taxes = taxes
+			matched[i].TaxAmount = sumTax(taxes)
+			taxTotal = taxTotal.Add(matched[i].TaxAmount)
+			allTaxes = append(allTaxes, taxes...)
+		}
+		total = total.Add(matched[i].LineAmount)
+		if l := matched[i]; l.MatchStatus == "PRICE_EXCEPTION" || l.MatchStatus == "QTY_EXCEPTION" {
+			exceptions = append(exceptions, fmt.Sprintf(
+				"line %d %s: invoiced %s × %s against received %s × %s (variance %s)",
+				l.LineNumber, strings.ToLower(strings.Replace(l.MatchStatus, "_", " ", 1)),
+				l.QtyInvoiced.StringFixed(2), l.UnitPrice.StringFixed(2),
+				l.ReceivedQty.StringFixed(2), l.POUnitCost.StringFixed(2), l.VarianceAmount.StringFixed(2)))
+		}
+	}
+
+	financialYear := invoiceDate.Year()
+
+	// Create DRAFT PI document inside this transaction
+	var draftDocID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO documents (company_id, type_code, status, financial_year, branch_id)
+		VALUES ($1, 'PI', 'DRAFT', $2, NULL)
+		RETURNING id`,
+		companyID, financialYear,
+	).Scan(&draftDocID); err != nil {
+		return "", fmt.Errorf("create PI document: %w", err)
+	}
+
+	if err := docService.PostDocumentTx(ctx, tx, draftDocID); err != nil {
+		return "", fmt.Errorf("post PI document: %w", err)
+	}
+
+	var piDocNumber string
+	if err := tx.QueryRow(ctx,
+		"SELECT document_number FROM documents WHERE id = $1",
+		draftDocID,
+	).Scan(&piDocNumber); err != nil {
+		return "", fmt.Errorf("retrieve PI document number: %w", err)
+	}
+
+	for _, l := range matched {
+		var lineID int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO vendor_invoice_lines
+			    (po_id, po_line_id, qty_invoiced, unit_price, line_amount, received_qty, po_unit_cost,
+			     price_variance, qty_variance, variance_amount, match_status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id`,
+			poID, l.POLineID, l.QtyInvoiced, l.UnitPrice, l.LineAmount, l.ReceivedQty, l.POUnitCost,
+			l.PriceVariance, l.QtyVariance, l.VarianceAmount, l.MatchStatus,
+		).Scan(&lineID); err != nil {
+			return "", fmt.Errorf("insert invoice line for PO line %d: %w", l.POLineID, err)
+		}
+		if err := insertLineTaxesTx(ctx, tx, companyID, lineTaxVendorInvoiceLine, lineID, l.Taxes); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.postInvoiceTaxTx(ctx, tx, companyID, poID, invoiceNumber, invoiceDate.Format("2006-01-02"),
+		allTaxes, ledger); err != nil {
+		return "", err
+	}
+	if err := settleInvoicedReturnsTx(ctx, tx, poID); err != nil {
+		return "", err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE purchase_orders
+		SET status = 'INVOICED',
+		    invoice_number     = $1,
+		    invoice_date       = $2,
+		    invoice_amount     = $3,
+		    invoice_tax_amount = $4,
+		    pi_document_number = $5,
+		    invoiced_at        = NOW()
+		WHERE id = $6`,
+		invoiceNumber, invoiceDate.Format("2006-01-02"), total.Add(taxTotal), taxTotal, piDocNumber, poID,
+	); err != nil {
+		return "", fmt.Errorf("update PO %d to INVOICED: %w", poID, err)
+	}
+
+	var warning string
+	if len(exceptions) > 0 {
+		warning = fmt.Sprintf("payment blocked: %d line(s) outside match tolerance — %s",
+			len(exceptions), strings.Join(exceptions, "; "))
+		if _, err := tx.Exec(ctx, `
+			UPDATE purchase_orders
+			SET match_status = 'BLOCKED', payment_blocked = true, payment_block_reason = $1
+			WHERE id = $2`,
+			warning, poID,
+		); err != nil {
+			return "", fmt.Errorf("block PO %d for payment: %w", poID, err)
+		}
+	} else if err := s.postInvoiceVarianceTx(ctx, tx, companyID, poID, invoiceDate.Format("2006-01-02"),
+		poLines, matched, tol, "MATCHED", ledger); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit vendor invoice: %w", err)
+	}
+
+	return warning, nil
+}
+
+// postInvoiceTaxTx posts the input GST on a vendor invoice: DR GST_INPUT_<component> /
+// CR the vendor's AP account, in the PO currency at the PO rate. No entry without tax.
+func (s *purchaseOrderService) postInvoiceTaxTx(ctx context.Context, tx pgx.Tx, companyID, poID int,
+	invoiceNumber, postingDate string, taxes []LineTax, ledger *Ledger) error {
+
+	taxLines, err := gstProposalLines(ctx, s.ruleEngine, companyID, taxes, true)
+	if err != nil {
+		return fmt.Errorf("resolve GST accounts for PO %d: %w", poID, err)
+	}
+	if len(taxLines) == 0 {
+		return nil
+	}
+	total := sumTax(taxes)
+
+	var companyCode, currency, apAccount string
+	var exchangeRate decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		SELECT c.company_code, po.currency, po.exchange_rate, COALESCE(v.ap_account_code, '2000')
+		FROM purchase_orders po
+		JOIN companies c ON c.id = po.company_id
+		JOIN vendors v   ON v.id = po.vendor_id
+		WHERE po.id = $1`,
+		poID,
+	).Scan(&companyCode, &currency, &exchangeRate, &apAccount); err != nil {
+		return fmt.Errorf("resolve AP account for PO %d: %w", poID, err)
+	}
+
+	proposal := Proposal{
+		DocumentTypeCode:    "JE",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      fmt.Sprintf("po-%d-invoice-tax", poID),
+		TransactionCurrency: currency,
+		ExchangeRate:        exchangeRate.String(),
+		Summary:             fmt.Sprintf("Input GST on invoice %s for PO %d", invoiceNumber, poID),
+		PostingDate:         postingDate,
+		DocumentDate:        postingDate,
+		Confidence:          1.0,
+		Reasoning:           fmt.Sprintf("GST of %s charged on the vendor invoice taken as input tax credit; owed to the vendor with the invoice.", total.StringFixed(2)),
+		Lines:               append(taxLines, ProposalLine{AccountCode: apAccount, IsDebit: false, Amount: total.StringFixed(2)}),
+	}
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return fmt.Errorf("post input GST for PO %d: %w", poID, err)
+	}
+	return nil
+}
+
+// ReleasePaymentBlock accepts the variances on a payment-blocked INVOICED purchase order,
+// posts them against AP and clears the block so the PO can be paid.
+func (s *purchaseOrderService) ReleasePaymentBlock(ctx context.Context, companyID, poID int, ledger *Ledger) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var poCompanyID int
+	var status string
+	var blocked bool
+	var invoiceDate *string
+	if err := tx.QueryRow(ctx,
+		"SELECT company_id, status, payment_blocked, invoice_date::text FROM purchase_orders WHERE id = $1 FOR UPDATE",
+		poID,
+	).Scan(&poCompanyID, &status, &blocked, &invoiceDate); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("purchase order %d not found", poID)
+		}
+		return fmt.Errorf("fetch purchase order %d: %w", poID, err)
+	}
+	if poCompanyID != companyID {
+		return fmt.Errorf("purchase order %d not found", poID)
+	}
+	if status != "INVOICED" || !blocked {
+		return fmt.Errorf("purchase order %d is not blocked for payment", poID)
+	}
+
+	poLines, err := s.fetchLines(ctx, poID)
+	if err != nil {
+		return err
+	}
+	matched, err := s.fetchInvoiceLines(ctx, poID)
+	if err != nil {
+		return err
+	}
+	tol, err := s.GetMatchTolerance(ctx, companyID)
+	if err != nil {
+		return err
+	}
+
+	postingDate := time.Now().Format("2006-01-02")
+	if invoiceDate != nil {
+		postingDate = *invoiceDate
+	}
+	if err := s.postInvoiceVarianceTx(ctx, tx, companyID, poID, postingDate, poLines, matched, tol, "RELEASED", ledger); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE purchase_orders SET payment_blocked = false WHERE id = $1", poID,
+	); err != nil {
+		return fmt.Errorf("release payment block on PO %d: %w", poID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit payment block release: %w", err)
+	}
+	return nil
+}
+
+// ── Three-way match ──────────────────────────────────────────────────────────
+
+// GetMatchTolerance returns the company's three-way match tolerances, or the defaults.
+func (s *purchaseOrderService) GetMatchTolerance(ctx context.Context, companyID int) (*MatchTolerance, error) {
+	tol := &MatchTolerance{
+		PriceTolerancePct: decimal.NewFromInt(5),
+		QtyTolerancePct:   decimal.Zero,
+		AmountTolerance:   decimal.Zero,
+		VarianceTreatment: "PPV",
+	}
+	err := s.pool.QueryRow(ctx, `
+		SELECT price_tolerance_pct, qty_tolerance_pct, amount_tolerance, variance_treatment
+		FROM purchase_match_tolerances
+		WHERE company_id = $1`,
+		companyID,
+	).Scan(&tol.PriceTolerancePct, &tol.QtyTolerancePct, &tol.AmountTolerance, &tol.VarianceTreatment)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get match tolerances: %w", err)
+	}
+	return tol, nil
+}
+
+// SetMatchTolerance creates or replaces the company's three-way match tolerances.
+func (s *purchaseOrderService) SetMatchTolerance(ctx context.Context, companyID int, tol MatchTolerance) (*MatchTolerance, error) {
+	if tol.PriceTolerancePct.IsNegative() || tol.QtyTolerancePct.IsNegative() || tol.AmountTolerance.IsNegative() {
+		return nil, fmt.Errorf("match tolerances cannot be negative")
+	}
+	treatment := strings.ToUpper(strings.TrimSpace(tol.VarianceTreatment))
+	if treatment == "" {
+		treatment = "PPV"
+	}
+	if treatment != "PPV" && treatment != "INVENTORY" {
+		return nil, fmt.Errorf("variance treatment must be PPV or INVENTORY, got %q", tol.VarianceTreatment)
+	}
+
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO purchase_match_tolerances
+		    (company_id, price_tolerance_pct, qty_tolerance_pct, amount_tolerance, variance_treatment)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (company_id) DO UPDATE
+		SET price_tolerance_pct = EXCLUDED.price_tolerance_pct,
+		    qty_tolerance_pct   = EXCLUDED.qty_tolerance_pct,
+		    amount_tolerance    = EXCLUDED.amount_tolerance,
+		    variance_treatment  = EXCLUDED.variance_treatment,
+		    updated_at          = NOW()`,
+		companyID, tol.PriceTolerancePct, tol.QtyTolerancePct, tol.AmountTolerance.Round(2), treatment,
+	); err != nil {
+		return nil, fmt.Errorf("save match tolerances: %w", err)
+	}
+	return s.GetMatchTolerance(ctx, companyID)
+}
+
+// buildInvoiceLines turns the caller's invoice lines into match rows, one per PO line that
+// was received or invoiced. A received line missing from the invoice is invoiced at zero.
+// With no input lines, every received line is invoiced in full and invoiceAmount is
+// spread over them by received value (remainder on the last line).
+// With input lines, a non-zero invoiceAmount must equal the sum of the lines.
+func buildInvoiceLines(poLines []PurchaseOrderLine, received map[int]decimal.Decimal,
+	input []VendorInvoiceLineInput, invoiceAmount decimal.Decimal) ([]VendorInvoiceLine, error) {
+
+	onPO := make(map[int]bool, len(poLines))
+	for _, l := range poLines {
+		onPO[l.ID] = true
+	}
+	byLine := make(map[int]VendorInvoiceLineInput, len(input))
+	for _, in := range input {
+		if !onPO[in.POLineID] {
+			return nil, fmt.Errorf("PO line %d not found on this purchase order", in.POLineID)
+		}
+		if _, dup := byLine[in.POLineID]; dup {
+			return nil, fmt.Errorf("PO line %d appears more than once on the invoice", in.POLineID)
+		}
+		if !in.Quantity.IsPositive() {
+			return nil, fmt.Errorf("PO line %d: invoiced quantity must be positive", in.POLineID)
+		}
+		if in.UnitPrice.IsNegative() {
+			return nil, fmt.Errorf("PO line %d: unit price cannot be negative", in.POLineID)
+		}
+		byLine[in.POLineID] = in
+	}
+
+	var out []VendorInvoiceLine
+	for _, l := range poLines {
+		rec := received[l.ID]
+		in, invoiced := byLine[l.ID]
+		if !invoiced && !rec.IsPositive() {
+			continue
+		}
+		il := VendorInvoiceLine{
+			POLineID:    l.ID,
+			LineNumber:  l.LineNumber,
+			Description: l.Description,
+			OrderedQty:  l.Quantity,
+			ReceivedQty: rec,
+			POUnitCost:  l.UnitCost,
+		}
+		switch {
+		case len(input) == 0:
+			il.QtyInvoiced = rec
+			il.LineAmount = rec.Mul(l.UnitCost).Round(2) // rescaled below
+		case invoiced:
+			il.QtyInvoiced = in.Quantity
+			il.UnitPrice = in.UnitPrice
+			il.LineAmount = in.Quantity.Mul(in.UnitPrice).Round(2)
+		}
+		out = append(out, il)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("nothing has been received to invoice")
+	}
+
+	if len(input) == 0 {
+		if !invoiceAmount.IsPositive() {
+			return nil, fmt.Errorf("invoice amount must be positive")
+		}
+		receivedValue := decimal.Zero
+		for _, il := range out {
+			receivedValue = receivedValue.Add(il.LineAmount)
+		}
+		remaining := invoiceAmount.Round(2)
+		for i := range out {
+			if i == len(out)-1 {
+				out[i].LineAmount = remaining
+			} else if receivedValue.IsPositive() {
+				out[i].LineAmount = invoiceAmount.Mul(out[i].LineAmount).Div(receivedValue).Round(2)
+			}
+			remaining = remaining.Sub(out[i].LineAmount)
+			if out[i].QtyInvoiced.IsPositive() {
+				out[i].UnitPrice = out[i].LineAmount.Div(out[i].QtyInvoiced).Round(4)
+			}
+		}
+		return out, nil
+	}
+
+	if !invoiceAmount.IsZero() {
+		sum := decimal.Zero
+		for _, il := range out {
+			sum = sum.Add(il.LineAmount)
+		}
+		if !sum.Equal(invoiceAmount.Round(2)) {
+			return nil, fmt.Errorf("invoice amount %s does not equal the sum of invoice lines %s",
+				invoiceAmount.StringFixed(2), sum.StringFixed(2))
+		}
+	}
+	return out, nil
+}
+
+// applyMatchTolerance computes the line's variances and sets its match status.
+func applyMatchTolerance(l *VendorInvoiceLine, tol *MatchTolerance) {
+	hundred := decimal.NewFromInt(100)
+	l.VarianceAmount = l.LineAmount.Sub(l.ReceivedQty.Mul(l.POUnitCost).Round(2))
+	l.PriceVariance = l.LineAmount.Sub(l.QtyInvoiced.Mul(l.POUnitCost)).Round(2)
+	l.QtyVariance = l.VarianceAmount.Sub(l.PriceVariance)
+
+	withinPct := func(actual, expected, pct decimal.Decimal) bool {
+		if expected.IsZero() {
+			return actual.IsZero()
+		}
+		return actual.Sub(expected).Abs().Mul(hundred).Div(expected.Abs()).LessThanOrEqual(pct)
+	}
+
+	switch {
+	case l.VarianceAmount.IsZero():
+		l.MatchStatus = "MATCHED"
+	case l.VarianceAmount.Abs().LessThanOrEqual(tol.AmountTolerance):
+		l.MatchStatus = "WITHIN_TOLERANCE"
+	case !withinPct(l.QtyInvoiced, l.ReceivedQty, tol.QtyTolerancePct):
+		l.MatchStatus = "QTY_EXCEPTION"
+	case !withinPct(l.UnitPrice, l.POUnitCost, tol.PriceTolerancePct):
+		l.MatchStatus = "PRICE_EXCEPTION"
+	default:
+		l.MatchStatus = "WITHIN_TOLERANCE"
+	}
+}
+
+// postInvoiceVarianceTx posts the accepted invoice variance against the vendor's AP account
+// and records it on the PO with the given match status (VARIANCE replaces MATCHED when
+// there is anything to post).
+// Service/expense lines adjust their own expense account. Goods lines go to the
+// PURCHASE_PRICE_VARIANCE account, or with INVENTORY treatment onto the unit cost of the
+// stock still on hand (PRICE_VARIANCE movement), the share already shipped going to COGS.
+// The entry is in the PO currency at the PO rate; stock is revalued in base currency.
+func (s *purchaseOrderService) postInvoiceVarianceTx(ctx context.Context, tx pgx.Tx, companyID, poID int,
+	postingDate string, poLines []PurchaseOrderLine, matched []VendorInvoiceLine,
+	tol *MatchTolerance, matchStatus string, ledger *Ledger) error {
+
+	lineByID := make(map[int]PurchaseOrderLine, len(poLines))
+	for _, l := range poLines {
+		lineByID[l.ID] = l
+	}
+
+	var companyCode, currency, apAccount string
+	var exchangeRate decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		SELECT c.company_code, po.currency, po.exchange_rate, COALESCE(v.ap_account_code, '2000')
+		FROM purchase_orders po
+		JOIN companies c ON c.id = po.company_id
+		JOIN vendors v   ON v.id = po.vendor_id
+		WHERE po.id = $1`,
+		poID,
+	).Scan(&companyCode, &currency, &exchangeRate, &apAccount); err != nil {
+		return fmt.Errorf("resolve AP account for PO %d: %w", poID, err)
+	}
+
+	// Signed amounts per account and set of line dimensions: positive = debit.
+	type bookedAmount struct {
+		account    string
+		dimensions []LineDimension
+		amount     decimal.Decimal
+	}
+	var booked []bookedAmount
+	bookedIndex := map[string]int{}
+	book := func(account string, dims []LineDimension, amount decimal.Decimal) {
+		if amount.IsZero() {
+			return
+		}
+		key := account + "|" + DimensionKey(dims)
+		i, ok := bookedIndex[key]
+		if !ok {
+			i = len(booked)
+			bookedIndex[key] = i
+			booked = append(booked, bookedAmount{account: account, dimensions: dims})
+		}
+		booked[i].amount = booked[i].amount.Add(amount)
+	}
+	resolve := func(ruleType string) (string, error) {
+		account, err := s.ruleEngine.ResolveAccount(ctx, companyID, ruleType)
+		if err != nil {
+			return "", fmt.Errorf("resolve %s account: %w", ruleType, err)
+		}
+		return account, nil
+	}
+
+	total := decimal.Zero
+	for _, il := range matched {
+		if il.VarianceAmount.IsZero() {
+			continue
+		}
+		total = total.Add(il.VarianceAmount)
+		pol := lineByID[il.POLineID]
+		switch {
+		case pol.ProductID == nil && pol.ExpenseAccountCode != nil:
+			book(*pol.ExpenseAccountCode, pol.Dimensions, il.VarianceAmount)
+		case pol.ProductID != nil && tol.VarianceTreatment == "INVENTORY":
+			capitalizedBase, err := revalueReceiptsForVarianceTx(ctx, tx, companyID, pol,
+				il.VarianceAmount.Mul(exchangeRate).Round(2), postingDate)
+			if err != nil {
+				return err
+			}
+			capitalized := capitalizedBase.Div(exchangeRate).Round(2)
+			if !capitalized.IsZero() {
+				account, err := resolve("INVENTORY")
+				if err != nil {
+					return err
+				}
+				book(account, nil, capitalized)
+			}
+			if rest := il.VarianceAmount.Sub(capitalized); !rest.IsZero() {
+				account, err := resolve("COGS")
+				if err != nil {
+					return err
+				}
+				book(account, nil, rest)
+			}
+		default:
+			account, err := resolve("PURCHASE_PRICE_VARIANCE")
+			if err != nil {
+				return err
+			}
+			book(account, nil, il.VarianceAmount)
+		}
+	}
+	book(apAccount, nil, total.Neg())
+
+	var lines []ProposalLine
+	for _, b := range booked {
+		if b.amount.IsZero() {
+			continue
+		}
+		lines = append(lines, ProposalLine{AccountCode: b.account, IsDebit: b.amount.IsPositive(), Amount: b.amount.Abs().StringFixed(2), Dimensions: b.dimensions})
+	}
+
+	var docNumber *string
+	if len(lines) >= 2 {
+		if matchStatus == "MATCHED" {
+			matchStatus = "VARIANCE"
+		}
+		idempotencyKey := fmt.Sprintf("po-%d-invoice-variance", poID)
+		proposal := Proposal{
+			DocumentTypeCode:    "JE",
+			CompanyCode:         companyCode,
+			IdempotencyKey:      idempotencyKey,
+			TransactionCurrency: currency,
+			ExchangeRate:        exchangeRate.String(),
+			Summary:             fmt.Sprintf("Invoice variance for PO %d", poID),
+			PostingDate:         postingDate,
+			DocumentDate:        postingDate,
+			Confidence:          1.0,
+			Reasoning: fmt.Sprintf("Three-way match: invoice differs from received value by %s; difference posted against AP.",
+				total.StringFixed(2)),
+			Lines: lines,
+		}
+		if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+			return fmt.Errorf("post invoice variance for PO %d: %w", poID, err)
+		}
+		var ref string
+		if err := tx.QueryRow(ctx,
+			"SELECT reference_id FROM journal_entries WHERE idempotency_key = $1", idempotencyKey,
+		).Scan(&ref); err != nil {
+			return fmt.Errorf("retrieve variance document number: %w", err)
+		}
+		docNumber = &ref
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE purchase_orders
+		SET match_status = $1, variance_amount = $2, variance_document_number = $3
+		WHERE id = $4`,
+		matchStatus, total, docNumber, poID,
+	); err != nil {
+		return fmt.Errorf("record invoice variance on PO %d: %w", poID, err)
+	}
+	return nil
+}
+
+// revalueReceiptsForVarianceTx spreads a goods line's variance (in base currency) over the inventory items it
+// was received into (by received quantity) and capitalises the share still on hand:
+// unit_cost moves and a value-only PRICE_VARIANCE movement is recorded.
+// Returns the capitalised total; the caller expenses the remainder.
+func revalueReceiptsForVarianceTx(ctx context.Context, tx pgx.Tx, companyID int,
+	pol PurchaseOrderLine, variance decimal.Decimal, postingDate string) (decimal.Decimal, error) {
+
+	rows, err := tx.Query(ctx, `
+		SELECT inventory_item_id, SUM(quantity)
+		FROM inventory_movements
+		WHERE po_line_id = $1 AND movement_type = 'RECEIPT'
+		GROUP BY inventory_item_id
+		ORDER BY inventory_item_id`,
+		pol.ID,
+	)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("load receipts for PO line %d: %w", pol.ID, err)
+	}
+	type itemReceipt struct {
+		itemID int
+		qty    decimal.Decimal
+	}
+	var items []itemReceipt
+	receiptQty := decimal.Zero
+	for rows.Next() {
+		var ir itemReceipt
+		if err := rows.Scan(&ir.itemID, &ir.qty); err != nil {
+			rows.Close()
+			return decimal.Zero, fmt.Errorf("scan receipt for PO line %d: %w", pol.ID, err)
+		}
+		items = append(items, ir)
+		receiptQty = receiptQty.Add(ir.qty)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return decimal.Zero, fmt.Errorf("load receipts for PO line %d: %w", pol.ID, err)
+	}
+	if !receiptQty.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	capitalized := decimal.Zero
+	remaining := variance
+	for i, ir := range items {
+		share := remaining
+		if i < len(items)-1 {
+			share = variance.Mul(ir.qty).Div(receiptQty).Round(2)
+		}
+		remaining = remaining.Sub(share)
+
+		var onHand, unitCost decimal.Decimal
+		if err := tx.QueryRow(ctx,
+			"SELECT qty_on_hand, unit_cost FROM inventory_items WHERE id = $1 FOR UPDATE", ir.itemID,
+		).Scan(&onHand, &unitCost); err != nil {
+			return decimal.Zero, fmt.Errorf("lock inventory item: %w", err)
+		}
+		if !onHand.IsPositive() {
+			continue
+		}
+		itemCapitalized := share.Mul(decimal.Min(onHand, ir.qty)).Div(ir.qty).Round(2)
+		if itemCapitalized.IsZero() {
+			continue
+		}
+		newCost := onHand.Mul(unitCost).Add(itemCapitalized).Div(onHand)
+		if newCost.IsNegative() {
+			return decimal.Zero, fmt.Errorf("PO line %d: variance %s would make the stock unit cost negative",
+				pol.ID, variance.StringFixed(2))
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE inventory_items SET unit_cost = $1, updated_at = NOW() WHERE id = $2",
+			newCost, ir.itemID,
+		); err != nil {
+			return decimal.Zero, fmt.Errorf("update inventory item cost: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO inventory_movements
+			    (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id)
+			VALUES ($1, $2, 'PRICE_VARIANCE', 0, 0, $3, $4, $5, $6)`,
+			companyID, ir.itemID, itemCapitalized, postingDate,
+			fmt.Sprintf("Invoice price variance on PO line %d", pol.ID), pol.ID,
+		); err != nil {
+			return decimal.Zero, fmt.Errorf("insert price variance movement: %w", err)
+		}
+		capitalized = capitalized.Add(itemCapitalized)
+	}
+	return capitalized, nil
+}
+
+// fetchInvoiceLines returns the three-way match rows recorded for a purchase order.
+func (s *purchaseOrderService) fetchInvoiceLines(ctx context.Context, poID int) ([]VendorInvoiceLine, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT vil.id, vil.po_line_id, pol.line_number, pol.description, pol.quantity,
+		       vil.received_qty, vil.qty_invoiced, vil.po_unit_cost, vil.unit_price, vil.line_amount,
+		       vil.price_variance, vil.qty_variance, vil.variance_amount, vil.match_status
+		FROM vendor_invoice_lines vil
+		JOIN purchase_order_lines pol ON pol.id = vil.po_line_id
+		WHERE vil.po_id = $1
+		ORDER BY pol.line_number`,
+		poID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch invoice lines for PO %d: %w", poID, err)
+	}
+	defer rows.Close()
+
+	var lines []VendorInvoiceLine
+	var lineIDs []int
+	for rows.Next() {
+		var l VendorInvoiceLine
+		var lineID int
+		if err := rows.Scan(
+			&lineID, &l.POLineID, &l.LineNumber, &l.Description, &l.OrderedQty,
+			&l.ReceivedQty, &l.QtyInvoiced, &l.POUnitCost, &l.UnitPrice, &l.LineAmount,
+			&l.PriceVariance, &l.QtyVariance, &l.VarianceAmount, &l.MatchStatus,
+		); err != nil {
+			return nil, fmt.Errorf("scan invoice line: %w", err)
+		}
+		lines = append(lines, l)
+		lineIDs = append(lineIDs, lineID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetch invoice lines for PO %d: %w", poID, err)
+	}
+	rows.Close()
+
+	taxes, err := fetchLineTaxesQ(ctx, s.pool, lineTaxVendorInvoiceLine, lineIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		lines[i].Taxes = taxes[lineIDs[i]]
+		lines[i].TaxAmount = sumTax(lines[i].Taxes)
+	}
+	return lines, nil
+}
+
+// PayVendor records payment against an INVOICED purchase order that is not blocked for payment.
+// The vendor's open debit notes are offset first, oldest first; the rest is posted as
+// DR AP / CR Bank (no entry when debit notes cover it all). Transitions status to PAID.
+// The payment is in the PO currency at the PO rate, so it clears the AP booked on receipt;
+// debit notes are in base currency and only offset base-currency purchase orders.
+func (s *purchaseOrderService) PayVendor(ctx context.Context, poID int,
+	bankAccountCode string, paymentDate time.Time, companyCode string, ledger *Ledger) error {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var companyID, vendorID int
+	var status, currency, baseCurrency string
+	var invoiceAmount *decimal.Decimal
+	var totalTransaction, exchangeRate decimal.Decimal
+	var apAccountCode string
+	var paymentBlocked bool
+	if err := tx.QueryRow(ctx, `
+		SELECT po.company_id, po.vendor_id, po.status, po.invoice_amount, po.total_transaction,
+		       po.currency, po.exchange_rate, c.base_currency,
+		       COALESCE(v.ap_account_code, '2000'), po.payment_blocked
+		FROM purchase_orders po
+		JOIN vendors v ON v.id = po.vendor_id
+		JOIN companies c ON c.id = po.company_id
+		WHERE po.id = $1
+		FOR UPDATE OF po`,
+		poID,
+	).Scan(&companyID, &vendorID, &status, &invoiceAmount, &totalTransaction,
+		&currency, &exchangeRate, &baseCurrency, &apAccountCode, &paymentBlocked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("purchase order %d not found", poID)
+		}
+		return fmt.Errorf("fetch purchase order %d: %w", poID, err)
+	}
+
+	if status != "INVOICED" {
+		return fmt.Errorf("purchase order %d cannot be paid: status is %s (must be INVOICED)", poID, status)
+	}
+	if paymentBlocked {
+		return fmt.Errorf("purchase order %d is blocked for payment: invoice is outside match tolerance", poID)
+	}
+
+	// Verify the PO's company matches the supplied companyCode.
+	var expectedCompanyID int
+	if err := tx.QueryRow(ctx,
+		"SELECT id FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&expectedCompanyID); err != nil {
+		return fmt.Errorf("resolve company %s: %w", companyCode, err)
+	}
+	if companyID != expectedCompanyID {
+		return fmt.Errorf("purchase order %d does not belong to company %s", poID, companyCode)
+	}
+
+	// Use invoice amount if recorded, otherwise fall back to PO total
+	paymentAmount := totalTransaction
+	if invoiceAmount != nil && !invoiceAmount.IsZero() {
+		paymentAmount = *invoiceAmount
+	}
+	paymentDateStr := paymentDate.Format("2006-01-02")
+
+	// Open debit notes from purchase returns were already debited to AP; offsetting
+	// them reduces what leaves the bank.
+	offset := decimal.Zero
+	if currency == baseCurrency {
+		offset, err = offsetDebitNotesTx(ctx, tx, vendorID, poID, paymentAmount)
+		if err != nil {
+			return fmt.Errorf("offset debit notes against PO %d: %w", poID, err)
+		}
+	}
+	netPayment := paymentAmount.Sub(offset)
+
+	var tds *tdsWithholding
+	if netPayment.IsPositive() {
+		// TDS is withheld on the payment excluding the invoice's GST.
+		base, err := payableTDSBaseQ(ctx, tx, &poID, nil, nil, netPayment)
+		if err != nil {
+			return err
+		}
+		tds, err = vendorTDSTx(ctx, tx, companyID, vendorID, base, paymentDate, true)
+		if err != nil {
+			return err
+		}
+		if tds != nil && currency != baseCurrency {
+			return fmt.Errorf("purchase order %d is in %s: TDS is only withheld on %s payments", poID, currency, baseCurrency)
+		}
+	}
+
+	idempotencyKey := fmt.Sprintf("pay-vendor-po-%d", poID)
+	if netPayment.IsPositive() {
+		reasoning := fmt.Sprintf("Payment of vendor invoice for purchase order %d.", poID)
+		if offset.IsPositive() {
+			reasoning = fmt.Sprintf("Payment of vendor invoice for purchase order %d, less %s offset from open debit notes.",
+				poID, offset.StringFixed(2))
+		}
+		lines := []ProposalLine{{AccountCode: apAccountCode, IsDebit: true, Amount: netPayment.StringFixed(2)}}
+		if bank := tds.netOf(netPayment); bank.IsPositive() {
+			lines = append(lines, ProposalLine{AccountCode: bankAccountCode, IsDebit: false, Amount: bank.StringFixed(2)})
+		}
+		tdsLine, err := tds.tdsPayableLine(ctx, s.ruleEngine)
+		if err != nil {
+			return err
+		}
+		if tdsLine != nil {
+			lines = append(lines, *tdsLine)
+			reasoning += fmt.Sprintf(" TDS of %s withheld under %s.", tds.TDSAmount.StringFixed(2), tds.section.Code)
+		}
+		proposal := Proposal{
+			DocumentTypeCode:    "JE",
+			CompanyCode:         companyCode,
+			IdempotencyKey:      idempotencyKey,
+			TransactionCurrency: currency,
+			ExchangeRate:        exchangeRate.String(),
+			Summary:             fmt.Sprintf("Vendor payment for PO %d", poID),
+			PostingDate:         paymentDateStr,
+			DocumentDate:        paymentDateStr,
+			Confidence:          1.0,
+			Reasoning:           reasoning,
+			Lines:               lines,
+		}
+
+		if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+			return fmt.Errorf("post payment journal entry for PO %d: %w", poID, err)
+		}
+		if err := tds.record(ctx, tx, tdsSource{poID: &poID}, idempotencyKey); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE purchase_orders SET status = 'PAID', paid_at = NOW() WHERE id = $1",
+		poID,
+	); err != nil {
+		return fmt.Errorf("update PO %d to PAID: %w", poID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit vendor payment: %w", err)
+	}
+
+	return nil
+}
+
+// getPOForCompany fetches a PO by ID, asserting it belongs to the given companyCode.
+// Returns pgx.ErrNoRows-wrapped error (indistinguishable from not-found) if ownership fails
+// to prevent PO enumeration across companies.
+func (s *purchaseOrderService) getPOForCompany(ctx context.Context, poID int, companyCode string) (*PurchaseOrder, error) {
+	var exists bool
+	if err := s.pool.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM purchase_orders po
+			JOIN companies c ON c.id = po.company_id
+			WHERE po.id = $1 AND c.company_code = $2
+		)`, poID, companyCode,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("purchase order ownership check: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("purchase order %d not found", poID)
+	}
+	return s.GetPO(ctx, poID)
 }
 
 // GetPO returns a purchase order by its internal ID, including all lines.
@@ -551,8 +1861,13 @@ func (s *purchaseOrderService) GetPO(ctx context.Context, poID int) (*PurchaseOr
 		       po.po_number, po.status, po.po_date::text, po.expected_delivery_date::text,
 		       po.currency, po.exchange_rate, po.total_transaction, po.total_base,
 		       po.notes, po.approved_at, po.received_at,
+		       po.tax_transaction, po.invoice_tax_amount,
 		       po.invoice_number, po.invoice_date::text, po.invoice_amount,
 		       po.pi_document_number, po.invoiced_at, po.paid_at,
+		       po.match_status, po.payment_blocked, po.payment_block_reason,
+		       po.variance_amount, po.variance_document_number,
+		       po.short_closed_at, po.short_close_reason,
+		       po.revision, po.cancelled_at, po.cancel_reason,
 		       po.created_at
 		FROM purchase_orders po
 		JOIN vendors v ON v.id = po.vendor_id
@@ -563,8 +1878,13 @@ func (s *purchaseOrderService) GetPO(ctx context.Context, poID int) (*PurchaseOr
 		&po.PONumber, &po.Status, &po.PODate, &po.ExpectedDeliveryDate,
 		&po.Currency, &po.ExchangeRate, &po.TotalTransaction, &po.TotalBase,
 		&po.Notes, &po.ApprovedAt, &po.ReceivedAt,
+		&po.TaxTransaction, &po.InvoiceTaxAmount,
 		&po.InvoiceNumber, &po.InvoiceDate, &po.InvoiceAmount,
 		&po.PIDocumentNumber, &po.InvoicedAt, &po.PaidAt,
+		&po.MatchStatus, &po.PaymentBlocked, &po.PaymentBlockReason,
+		&po.VarianceAmount, &po.VarianceDocumentNumber,
+		&po.ShortClosedAt, &po.ShortCloseReason,
+		&po.Revision, &po.CancelledAt, &po.CancelReason,
 		&po.CreatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -572,12 +1892,19 @@ func (s *purchaseOrderService) GetPO(ctx context.Context, poID int) (*PurchaseOr
 		}
 		return nil, fmt.Errorf("get purchase order %d: %w", poID, err)
 	}
+	po.TaxableTransaction = po.TotalTransaction.Sub(po.TaxTransaction)
 
 	lines, err := s.fetchLines(ctx, poID)
 	if err != nil {
 		return nil, err
 	}
 	po.Lines = lines
+
+	invoiceLines, err := s.fetchInvoiceLines(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	po.InvoiceLines = invoiceLines
 	return po, nil
 }
 
@@ -588,8 +1915,13 @@ func (s *purchaseOrderService) GetPOs(ctx context.Context, companyID int, status
 		       po.po_number, po.status, po.po_date::text, po.expected_delivery_date::text,
 		       po.currency, po.exchange_rate, po.total_transaction, po.total_base,
 		       po.notes, po.approved_at, po.received_at,
+		       po.tax_transaction, po.invoice_tax_amount,
 		       po.invoice_number, po.invoice_date::text, po.invoice_amount,
 		       po.pi_document_number, po.invoiced_at, po.paid_at,
+		       po.match_status, po.payment_blocked, po.payment_block_reason,
+		       po.variance_amount, po.variance_document_number,
+		       po.short_closed_at, po.short_close_reason,
+		       po.revision, po.cancelled_at, po.cancel_reason,
 		       po.created_at
 		FROM purchase_orders po
 		JOIN vendors v ON v.id = po.vendor_id
@@ -616,12 +1948,18 @@ func (s *purchaseOrderService) GetPOs(ctx context.Context, companyID int, status
 			&po.PONumber, &po.Status, &po.PODate, &po.ExpectedDeliveryDate,
 			&po.Currency, &po.ExchangeRate, &po.TotalTransaction, &po.TotalBase,
 			&po.Notes, &po.ApprovedAt, &po.ReceivedAt,
+			&po.TaxTransaction, &po.InvoiceTaxAmount,
 			&po.InvoiceNumber, &po.InvoiceDate, &po.InvoiceAmount,
 			&po.PIDocumentNumber, &po.InvoicedAt, &po.PaidAt,
+			&po.MatchStatus, &po.PaymentBlocked, &po.PaymentBlockReason,
+			&po.VarianceAmount, &po.VarianceDocumentNumber,
+			&po.ShortClosedAt, &po.ShortCloseReason,
+			&po.Revision, &po.CancelledAt, &po.CancelReason,
 			&po.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan purchase order: %w", err)
 		}
+		po.TaxableTransaction = po.TotalTransaction.Sub(po.TaxTransaction)
 		orders = append(orders, po)
 	}
 	return orders, nil
@@ -632,9 +1970,10 @@ func (s *purchaseOrderService) fetchLines(ctx context.Context, poID int) ([]Purc
 	rows, err := s.pool.Query(ctx, `
 		SELECT pol.id, pol.order_id, pol.line_number,
 		       pol.product_id, p.code, p.name,
-		       pol.description, pol.quantity, pol.unit_cost,
+		       pol.description, pol.quantity, COALESCE(pol.uom, p.unit, ''), pol.uom_factor, pol.unit_cost,
 		       pol.line_total_transaction, pol.line_total_base,
-		       pol.expense_account_code
+		       pol.expense_account_code, pol.received_quantity, pol.returned_quantity,
+		       pol.tax_code, pol.tax_amount_transaction, pol.dimensions
 		FROM purchase_order_lines pol
 		LEFT JOIN products p ON p.id = pol.product_id
 		WHERE pol.order_id = $1
@@ -652,9 +1991,10 @@ func (s *purchaseOrderService) fetchLines(ctx context.Context, poID int) ([]Purc
 		if err := rows.Scan(
 			&l.ID, &l.OrderID, &l.LineNumber,
 			&l.ProductID, &l.ProductCode, &l.ProductName,
-			&l.Description, &l.Quantity, &l.UnitCost,
+			&l.Description, &l.Quantity, &l.Unit, &l.UnitFactor, &l.UnitCost,
 			&l.LineTotalTransaction, &l.LineTotalBase,
-			&l.ExpenseAccountCode,
+			&l.ExpenseAccountCode, &l.ReceivedQuantity, &l.ReturnedQuantity,
+			&l.TaxCode, &l.TaxAmountTransaction, &l.Dimensions,
 		); err != nil {
 			return nil, fmt.Errorf("scan PO line: %w", err)
 		}
@@ -0,0 +1,162 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPurchaseOrder_PartialReceipts(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	companyCode := "1000"
+	poDate := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// approvedPO creates and approves a PO for 100 × P001 @ 10 plus 4 days of installation.
+	approvedPO := func(t *testing.T) *core.PurchaseOrder {
+		t.Helper()
+		po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
+			{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(10)},
+			{Description: "Installation (days)", Quantity: decimal.NewFromInt(4), UnitCost: decimal.NewFromInt(250), ExpenseAccountCode: "5100"},
+		}, "")
+		if err != nil {
+			t.Fatalf("CreatePO: %v", err)
+		}
+		if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+			t.Fatalf("ApprovePO: %v", err)
+		}
+		return po
+	}
+
+	receive := func(po *core.PurchaseOrder, goodsQty, serviceQty int64) error {
+		var lines []core.ReceivedLine
+		if goodsQty > 0 {
+			lines = append(lines, core.ReceivedLine{POLineID: po.Lines[0].ID, QtyReceived: decimal.NewFromInt(goodsQty)})
+		}
+		if serviceQty > 0 {
+			lines = append(lines, core.ReceivedLine{POLineID: po.Lines[1].ID, QtyReceived: decimal.NewFromInt(serviceQty)})
+		}
+		return poService.ReceivePO(ctx, po.ID, "MAIN", companyCode, lines, "2000", ledger, docService, invSvc)
+	}
+
+	t.Run("RepeatedReceipts_UntilFullyReceived", func(t *testing.T) {
+		po := approvedPO(t)
+
+		if err := receive(po, 1, 1); err != nil {
+			t.Fatalf("first ReceivePO: %v", err)
+		}
+		got, err := poService.GetPO(ctx, po.ID)
+		if err != nil {
+			t.Fatalf("GetPO: %v", err)
+		}
+		if got.Status != "PARTIALLY_RECEIVED" || got.ReceivedAt != nil {
+			t.Fatalf("expected PARTIALLY_RECEIVED without received_at, got %s (received_at %v)", got.Status, got.ReceivedAt)
+		}
+		if out := got.Lines[0].OutstandingQuantity(); !out.Equal(decimal.NewFromInt(99)) {
+			t.Errorf("goods line outstanding: expected 99, got %s", out)
+		}
+		if out := got.Lines[1].OutstandingQuantity(); !out.Equal(decimal.NewFromInt(3)) {
+			t.Errorf("service line outstanding: expected 3, got %s", out)
+		}
+
+		// A second service receipt must post its own journal entry.
+		if err := receive(po, 60, 2); err != nil {
+			t.Fatalf("second ReceivePO: %v", err)
+		}
+		var serviceEntries int
+		if err := pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM journal_entries WHERE idempotency_key LIKE $1",
+			fmt.Sprintf("po-%d-line-%%-service-receipt-%%", po.ID),
+		).Scan(&serviceEntries); err != nil {
+			t.Fatalf("count service receipt entries: %v", err)
+		}
+		if serviceEntries != 2 {
+			t.Errorf("expected 2 service receipt entries, got %d", serviceEntries)
+		}
+
+		if err := receive(po, 40, 2); err != nil {
+			t.Fatalf("final ReceivePO: %v", err)
+		}
+		got, _ = poService.GetPO(ctx, po.ID)
+		if got.Status != "RECEIVED" || got.ReceivedAt == nil {
+			t.Errorf("expected RECEIVED with received_at, got %s (received_at %v)", got.Status, got.ReceivedAt)
+		}
+		if !got.Lines[0].ReceivedQuantity.Equal(decimal.NewFromInt(100)) {
+			t.Errorf("goods line received: expected 100, got %s", got.Lines[0].ReceivedQuantity)
+		}
+	})
+
+	t.Run("OverReceipt_Fails", func(t *testing.T) {
+		po := approvedPO(t)
+		if err := receive(po, 90, 0); err != nil {
+			t.Fatalf("ReceivePO: %v", err)
+		}
+		if err := receive(po, 11, 0); err == nil {
+			t.Error("expected error receiving beyond the ordered quantity, got nil")
+		}
+		got, _ := poService.GetPO(ctx, po.ID)
+		if !got.Lines[0].ReceivedQuantity.Equal(decimal.NewFromInt(90)) {
+			t.Errorf("received quantity must be unchanged after a rejected receipt, got %s", got.Lines[0].ReceivedQuantity)
+		}
+	})
+
+	t.Run("FailedLine_RollsBackWholeReceipt", func(t *testing.T) {
+		po := approvedPO(t)
+		// The goods line is fine; the service line is over-received, so nothing may stick.
+		if err := receive(po, 10, 5); err == nil {
+			t.Fatal("expected error receiving 5 of 4 installation days, got nil")
+		}
+		got, err := poService.GetPO(ctx, po.ID)
+		if err != nil {
+			t.Fatalf("GetPO: %v", err)
+		}
+		if got.Status != "APPROVED" || !got.Lines[0].ReceivedQuantity.IsZero() {
+			t.Errorf("expected APPROVED with nothing received, got %s with %s received", got.Status, got.Lines[0].ReceivedQuantity)
+		}
+		var movements int
+		if err := pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM inventory_movements WHERE po_line_id = $1", po.Lines[0].ID,
+		).Scan(&movements); err != nil {
+			t.Fatalf("count movements: %v", err)
+		}
+		if movements != 0 {
+			t.Errorf("expected no inventory movements for the goods line, got %d", movements)
+		}
+	})
+
+	t.Run("ShortClose_AllowsInvoicing", func(t *testing.T) {
+		po := approvedPO(t)
+
+		if err := poService.ShortClosePO(ctx, 1, po.ID, "", ""); err == nil {
+			t.Error("expected error short-closing an APPROVED PO, got nil")
+		}
+		if err := receive(po, 30, 4); err != nil {
+			t.Fatalf("ReceivePO: %v", err)
+		}
+		if err := poService.ShortClosePO(ctx, 1, po.ID, "Vendor discontinued the item", "manager"); err != nil {
+			t.Fatalf("ShortClosePO: %v", err)
+		}
+		got, _ := poService.GetPO(ctx, po.ID)
+		if got.Status != "RECEIVED" || got.ShortClosedAt == nil || got.ShortCloseReason == nil {
+			t.Fatalf("expected short-closed RECEIVED PO, got %s (short_closed_at %v)", got.Status, got.ShortClosedAt)
+		}
+		if err := receive(po, 1, 0); err == nil {
+			t.Error("expected error receiving against a short-closed PO, got nil")
+		}
+
+		// 30 × 10 + 4 × 250 received; the invoice matches what arrived.
+		warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-SC", poDate, decimal.NewFromInt(1300),
+			nil, ledger, docService)
+		if err != nil {
+			t.Fatalf("RecordVendorInvoice: %v", err)
+		}
+		if warning != "" {
+			t.Errorf("expected no warning invoicing the received quantity, got %q", warning)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPurchaseReturn_DebitNote(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy) VALUES
+		('DN', 'Debit Note', true, true, false, true, 'sequential', false)
+		ON CONFLICT (code) DO NOTHING;
+	`)
+	if err != nil {
+		t.Fatalf("seed purchase return test data: %v", err)
+	}
+
+	companyCode := "1000"
+	returnSvc := core.NewPurchaseReturnService(pool, core.NewRuleEngine(pool))
+
+	// Receive 10 × P001 @ 50 (500).
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(50)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	lineID := po.Lines[0].ID
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+		[]core.ReceivedLine{{POLineID: lineID, QtyReceived: decimal.NewFromInt(10)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+
+	ret, err := returnSvc.CreateReturn(ctx, companyCode, core.PurchaseReturnInput{
+		POID: po.ID, WarehouseCode: "MAIN", ReturnDate: "2026-04-10", Reason: "damaged",
+		Lines: []core.PurchaseReturnLineInput{{POLineID: lineID, Quantity: decimal.NewFromInt(4)}},
+	}, ledger)
+	if err != nil {
+		t.Fatalf("CreateReturn: %v", err)
+	}
+	if ret.Status != "OPEN" || !ret.Amount.Equal(decimal.NewFromInt(200)) || ret.DebitNoteNumber == nil {
+		t.Fatalf("expected OPEN 200 debit note with a number, got %s %s", ret.Status, ret.Amount)
+	}
+
+	var onHand, movementQty decimal.Decimal
+	if err := pool.QueryRow(ctx, `
+		SELECT ii.qty_on_hand,
+		       (SELECT SUM(im.quantity) FROM inventory_movements im
+		        WHERE im.inventory_item_id = ii.id AND im.movement_type = 'RECEIPT')
+		FROM inventory_items ii
+		JOIN products p ON p.id = ii.product_id
+		WHERE p.code = 'P001'`,
+	).Scan(&onHand, &movementQty); err != nil {
+		t.Fatalf("query inventory: %v", err)
+	}
+	if !onHand.Equal(decimal.NewFromInt(6)) || !movementQty.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("expected 6 on hand and net receipts of 6, got %s and %s", onHand, movementQty)
+	}
+
+	t.Run("OverReturn_Fails", func(t *testing.T) {
+		_, err := returnSvc.CreateReturn(ctx, companyCode, core.PurchaseReturnInput{
+			POID: po.ID, WarehouseCode: "MAIN",
+			Lines: []core.PurchaseReturnLineInput{{POLineID: lineID, Quantity: decimal.NewFromInt(7)}},
+		}, ledger)
+		if err == nil {
+			t.Error("expected error returning more than was received less earlier returns, got nil")
+		}
+	})
+
+	// The vendor bills all 10: the invoice is matched against the 6 kept and blocked, and
+	// the debit note is settled by the invoice rather than left to offset a payment.
+	warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-DN-1", time.Date(2026, 4, 12, 0, 0, 0, 0, time.UTC),
+		decimal.NewFromInt(500), nil, ledger, docService)
+	if err != nil {
+		t.Fatalf("RecordVendorInvoice: %v", err)
+	}
+	if warning == "" {
+		t.Error("expected a payment-block warning for an invoice of the returned goods, got none")
+	}
+	got, err := poService.GetPO(ctx, po.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	if !got.PaymentBlocked || len(got.InvoiceLines) != 1 || got.InvoiceLines[0].MatchStatus != "QTY_EXCEPTION" ||
+		!got.InvoiceLines[0].ReceivedQty.Equal(decimal.NewFromInt(6)) || !got.InvoiceLines[0].VarianceAmount.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("expected blocked QTY_EXCEPTION against 6 kept with variance 200, got blocked=%v lines=%+v",
+			got.PaymentBlocked, got.InvoiceLines)
+	}
+
+	settled, err := returnSvc.GetReturn(ctx, companyCode, ret.ID)
+	if err != nil {
+		t.Fatalf("GetReturn: %v", err)
+	}
+	if settled.Status != "APPLIED" || !settled.OpenAmount().IsZero() || len(settled.Applications) != 1 {
+		t.Errorf("expected debit note APPLIED to the invoice, got %s open %s (%d applications)",
+			settled.Status, settled.OpenAmount(), len(settled.Applications))
+	}
+}
+
+// TestPurchaseReturn_InvoiceNetOfReturn: a vendor invoice for only the quantity kept
+// matches the receipt net of the return and is paid in full, the debit note having
+// already reduced AP.
+func TestPurchaseReturn_InvoiceNetOfReturn(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy) VALUES
+		('DN', 'Debit Note', true, true, false, true, 'sequential', false)
+		ON CONFLICT (code) DO NOTHING;
+	`); err != nil {
+		t.Fatalf("seed purchase return test data: %v", err)
+	}
+
+	companyCode := "1000"
+	returnSvc := core.NewPurchaseReturnService(pool, core.NewRuleEngine(pool))
+
+	// Receive 10 × P001 @ 50 (500), return 4 (200), then invoice the 6 kept (300).
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(50)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	lineID := po.Lines[0].ID
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+		[]core.ReceivedLine{{POLineID: lineID, QtyReceived: decimal.NewFromInt(10)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+	ret, err := returnSvc.CreateReturn(ctx, companyCode, core.PurchaseReturnInput{
+		POID: po.ID, WarehouseCode: "MAIN", ReturnDate: "2026-04-10", Reason: "damaged",
+		Lines: []core.PurchaseReturnLineInput{{POLineID: lineID, Quantity: decimal.NewFromInt(4)}},
+	}, ledger)
+	if err != nil {
+		t.Fatalf("CreateReturn: %v", err)
+	}
+
+	warning, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-NET-1", time.Date(2026, 4, 12, 0, 0, 0, 0, time.UTC),
+		decimal.NewFromInt(300), nil, ledger, docService)
+	if err != nil {
+		t.Fatalf("RecordVendorInvoice: %v", err)
+	}
+	if warning != "" {
+		t.Errorf("expected no payment block, got %q", warning)
+	}
+	got, err := poService.GetPO(ctx, po.ID)
+	if err != nil {
+		t.Fatalf("GetPO: %v", err)
+	}
+	if got.PaymentBlocked || got.MatchStatus == nil || *got.MatchStatus != "MATCHED" {
+		t.Errorf("expected unblocked PO with match status MATCHED, got blocked=%v status=%v", got.PaymentBlocked, got.MatchStatus)
+	}
+	if got.VarianceDocumentNumber != nil || !got.VarianceAmount.IsZero() {
+		t.Errorf("expected no variance entry, got %v (variance %s)", got.VarianceDocumentNumber, got.VarianceAmount)
+	}
+	if len(got.InvoiceLines) != 1 || got.InvoiceLines[0].MatchStatus != "MATCHED" ||
+		!got.InvoiceLines[0].ReceivedQty.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("expected one MATCHED invoice line against 6 kept, got %+v", got.InvoiceLines)
+	}
+
+	if err := poService.PayVendor(ctx, po.ID, "1100", time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC), companyCode, ledger); err != nil {
+		t.Fatalf("PayVendor: %v", err)
+	}
+	var bank, ap, inventory decimal.Decimal
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE a.code = '1100'), 0),
+		       COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE a.code = '2000'), 0),
+		       COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE a.code = '1400'), 0)
+		FROM journal_lines jl
+		JOIN accounts a ON a.id = jl.account_id
+		WHERE a.company_id = 1`,
+	).Scan(&bank, &ap, &inventory); err != nil {
+		t.Fatalf("query balances: %v", err)
+	}
+	if !bank.Equal(decimal.NewFromInt(-300)) || !ap.IsZero() || !inventory.Equal(decimal.NewFromInt(300)) {
+		t.Errorf("expected bank -300, AP 0 and inventory 300, got %s, %s and %s", bank, ap, inventory)
+	}
+
+	settled, err := returnSvc.GetReturn(ctx, companyCode, ret.ID)
+	if err != nil {
+		t.Fatalf("GetReturn: %v", err)
+	}
+	if settled.Status != "APPLIED" || !settled.OpenAmount().IsZero() {
+		t.Errorf("expected the debit note settled by the invoice, got %s open %s", settled.Status, settled.OpenAmount())
+	}
+}
+
+func TestPurchaseReturn_ReversesInputGST(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+	seedGST(t, ctx, pool)
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1100', 'Bank', 'asset')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy) VALUES
+		('DN', 'Debit Note', true, true, false, true, 'sequential', false)
+		ON CONFLICT (code) DO NOTHING;
+
+		UPDATE vendors SET state_code = '29' WHERE company_id = 1 AND code = 'V001';
+	`); err != nil {
+		t.Fatalf("seed purchase return GST data: %v", err)
+	}
+
+	companyCode := "1000"
+	returnSvc := core.NewPurchaseReturnService(pool, core.NewRuleEngine(pool))
+
+	// Receive and invoice 10 × P001 @ 50 (500) with intra-state GST18: CGST 45 + SGST 45.
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(50), TaxCode: "GST18"},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	lineID := po.Lines[0].ID
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+		[]core.ReceivedLine{{POLineID: lineID, QtyReceived: decimal.NewFromInt(10)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+	if _, err := poService.RecordVendorInvoice(ctx, 1, po.ID, "INV-GST-DN", time.Date(2026, 4, 5, 0, 0, 0, 0, time.UTC),
+		decimal.NewFromInt(500), nil, ledger, docService); err != nil {
+		t.Fatalf("RecordVendorInvoice: %v", err)
+	}
+
+	// Return 4: goods 200 at receipt cost plus the 36 of GST claimed on them.
+	ret, err := returnSvc.CreateReturn(ctx, companyCode, core.PurchaseReturnInput{
+		POID: po.ID, WarehouseCode: "MAIN", ReturnDate: "2026-04-10", Reason: "damaged",
+		Lines: []core.PurchaseReturnLineInput{{POLineID: lineID, Quantity: decimal.NewFromInt(4)}},
+	}, ledger)
+	if err != nil {
+		t.Fatalf("CreateReturn: %v", err)
+	}
+	if !ret.Amount.Equal(decimal.NewFromInt(236)) || !ret.TaxAmount.Equal(decimal.NewFromInt(36)) {
+		t.Errorf("expected debit note 236 with GST 36, got %s with GST %s", ret.Amount, ret.TaxAmount)
+	}
+	if len(ret.Lines) != 1 || !ret.Lines[0].TaxAmount.Equal(decimal.NewFromInt(36)) {
+		t.Errorf("expected GST 36 on the returned line, got %+v", ret.Lines)
+	}
+
+	bm := gstBalances(t, ctx, ledger)
+	if bm["1510"] != "27.00" || bm["1520"] != "27.00" {
+		t.Errorf("expected input CGST/SGST 27.00 each after the reversal, got %s / %s", bm["1510"], bm["1520"])
+	}
+	if bm["2000"] != "-354.00" {
+		t.Errorf("expected AP -354.00 (590 invoice less 236 debit note), got %s", bm["2000"])
+	}
+
+	if err := poService.PayVendor(ctx, po.ID, "1100", time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC), companyCode, ledger); err != nil {
+		t.Fatalf("PayVendor: %v", err)
+	}
+	bm = gstBalances(t, ctx, ledger)
+	if bm["1100"] != "-354.00" || bm["2000"] != "0.00" {
+		t.Errorf("expected bank -354.00 and AP 0.00 after payment, got %s / %s", bm["1100"], bm["2000"])
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PurchaseReturn is goods sent back to a vendor against a received purchase order.
+// Posting it raises a debit note: the returned value is owed back by the vendor and
+// is offset against the next payments to that vendor.
+// Status: OPEN (debit note balance outstanding) → APPLIED.
+type PurchaseReturn struct {
+	ID              int
+	CompanyID       int
+	POID            int
+	PONumber        *string
+	VendorID        int
+	VendorCode      string
+	VendorName      string
+	WarehouseCode   string
+	ReturnDate      string // YYYY-MM-DD
+	Reason          *string
+	Status          string // OPEN | APPLIED
+	APAccountCode   string
+	Amount          decimal.Decimal // debit note total: goods at receipt cost plus TaxAmount
+	TaxAmount       decimal.Decimal // input GST reversed on invoiced goods
+	AppliedAmount   decimal.Decimal // offset against payments so far
+	DebitNoteNumber *string         // DN document number
+	CreatedAt       time.Time
+	Lines           []PurchaseReturnLine
+	Applications    []DebitNoteApplication
+}
+
+// OpenAmount returns the debit note balance not yet offset against a payment.
+func (r PurchaseReturn) OpenAmount() decimal.Decimal {
+	return r.Amount.Sub(r.AppliedAmount)
+}
+
+// PurchaseReturnLine is one returned PO line. Quantity and UnitCost are in the PO
+// line's unit; UnitCost is the cost the goods were received at. TaxAmount is the input
+// GST of the line's vendor invoice reversed on the quantity returned.
+type PurchaseReturnLine struct {
+	ID          int
+	POLineID    int
+	LineNumber  int
+	ProductCode string
+	Description string
+	Quantity    decimal.Decimal
+	UnitCost    decimal.Decimal
+	LineAmount  decimal.Decimal
+	TaxAmount   decimal.Decimal
+}
+
+// DebitNoteApplication records part of a debit note offset against a payment:
+// a direct purchase order payment (POID) or a posted payment run (PaymentRunID).
+type DebitNoteApplication struct {
+	ID           int
+	POID         *int
+	PaymentRunID *int
+	Amount       decimal.Decimal
+	AppliedAt    time.Time
+}
+
+// PurchaseReturnLineInput is one PO line being returned, in the line's unit.
+type PurchaseReturnLineInput struct {
+	POLineID int
+	Quantity decimal.Decimal
+}
+
+// PurchaseReturnInput holds the fields required to return goods against a purchase order.
+// An empty ReturnDate is today.
+type PurchaseReturnInput struct {
+	POID          int
+	WarehouseCode string
+	ReturnDate    string // YYYY-MM-DD, optional
+	Reason        string
+	Lines         []PurchaseReturnLineInput
+}
+
+// PurchaseReturnService records goods returned to vendors and the debit notes they raise.
+type PurchaseReturnService interface {
+	// CreateReturn returns received goods on a purchase order to the vendor. Each line may
+	// return up to its received quantity less earlier returns, from stock received on that
+	// line into the warehouse (lot-tracked products give back the line's own lots, oldest
+	// first). Writes negative RECEIPT movements at the original receipt cost and posts
+	// DR vendor AP / CR Inventory as a DN document in one transaction; lines the vendor
+	// has invoiced also reverse their input GST pro rata (CR GST_INPUT_<component>), and
+	// the debit note includes it. Status is OPEN.
+	CreateReturn(ctx context.Context, companyCode string, input PurchaseReturnInput, ledger *Ledger) (*PurchaseReturn, error)
+
+	// GetReturns returns a company's purchase returns, newest first, optionally filtered
+	// by status and/or vendor code (headers only).
+	GetReturns(ctx context.Context, companyCode, status, vendorCode string) ([]PurchaseReturn, error)
+
+	// GetReturn returns one purchase return with its lines and debit note applications.
+	GetReturn(ctx context.Context, companyCode string, returnID int) (*PurchaseReturn, error)
+}
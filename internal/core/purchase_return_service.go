@@ -0,0 +1,671 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type purchaseReturnService struct {
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+}
+
+// NewPurchaseReturnService constructs a PurchaseReturnService backed by PostgreSQL.
+func NewPurchaseReturnService(pool *pgxpool.Pool, ruleEngine RuleEngine) PurchaseReturnService {
+	return &purchaseReturnService{pool: pool, ruleEngine: ruleEngine}
+}
+
+// returnLine is one PO line being returned, with the stock it comes out of.
+type returnLine struct {
+	line        PurchaseReturnLine
+	itemID      int
+	stockQty    decimal.Decimal
+	stockCost   decimal.Decimal // receipt cost per stock unit
+	productCode string
+	lots        []lotDraw
+	taxes       []LineTax // input GST reversed, in base currency
+}
+
+// ── CreateReturn ──────────────────────────────────────────────────────────────
+
+func (s *purchaseReturnService) CreateReturn(ctx context.Context, companyCode string, input PurchaseReturnInput, ledger *Ledger) (*PurchaseReturn, error) {
+	if len(input.Lines) == 0 {
+		return nil, fmt.Errorf("at least one return line is required")
+	}
+	returnDate := input.ReturnDate
+	if returnDate == "" {
+		returnDate = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", returnDate); err != nil {
+		return nil, fmt.Errorf("invalid return date %q (expected YYYY-MM-DD)", input.ReturnDate)
+	}
+
+	var companyID int
+	var baseCurrency string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, base_currency FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID, &baseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("company %s not found", companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+
+	var warehouseID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id FROM warehouses WHERE company_id = $1 AND code = $2 AND is_active = true",
+		companyID, input.WarehouseCode,
+	).Scan(&warehouseID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("warehouse %s not found for company %s", input.WarehouseCode, companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve warehouse: %w", err)
+	}
+
+	inventoryAccount, err := s.ruleEngine.ResolveAccount(ctx, companyID, "INVENTORY")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve INVENTORY account: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status, currency, apAccount, vendorName string
+	var vendorID int
+	var poNumber *string
+	if err := tx.QueryRow(ctx, `
+		SELECT po.status, po.currency, po.vendor_id, v.name, COALESCE(v.ap_account_code, '2000'), po.po_number
+		FROM purchase_orders po
+		JOIN vendors v ON v.id = po.vendor_id
+		WHERE po.id = $1 AND po.company_id = $2
+		FOR UPDATE OF po`,
+		input.POID, companyID,
+	).Scan(&status, &currency, &vendorID, &vendorName, &apAccount, &poNumber); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("purchase order %d not found", input.POID)
+		}
+		return nil, fmt.Errorf("fetch purchase order %d: %w", input.POID, err)
+	}
+	switch status {
+	case "PARTIALLY_RECEIVED", "RECEIVED", "INVOICED", "PAID":
+	default:
+		return nil, fmt.Errorf("purchase order %d has no goods to return: status is %s", input.POID, status)
+	}
+	// Debit notes are in the base currency, which is all that PayVendor offsets them
+	// against and payment runs pay in.
+	if currency != baseCurrency {
+		return nil, fmt.Errorf("purchase order %d is in %s: purchase returns are only supported on %s purchase orders",
+			input.POID, currency, baseCurrency)
+	}
+	poRef := fmt.Sprintf("%d", input.POID)
+	if poNumber != nil {
+		poRef = *poNumber
+	}
+
+	var lines []returnLine
+	var allTaxes []LineTax
+	seen := map[int]bool{}
+	total, taxTotal := decimal.Zero, decimal.Zero
+	for _, in := range input.Lines {
+		if !in.Quantity.IsPositive() {
+			return nil, fmt.Errorf("PO line %d: return quantity must be positive", in.POLineID)
+		}
+		if seen[in.POLineID] {
+			return nil, fmt.Errorf("PO line %d appears more than once on the return", in.POLineID)
+		}
+		seen[in.POLineID] = true
+
+		rl, err := s.returnLineTx(ctx, tx, companyID, warehouseID, input.POID, in)
+		if err != nil {
+			return nil, err
+		}
+		rl.line.LineNumber = len(lines) + 1
+		lines = append(lines, rl)
+		total = total.Add(rl.line.LineAmount)
+		taxTotal = taxTotal.Add(rl.line.TaxAmount)
+		allTaxes = append(allTaxes, rl.taxes...)
+	}
+	if !total.IsPositive() {
+		return nil, fmt.Errorf("returned goods have no receipt value; nothing to debit the vendor for")
+	}
+
+	taxLines, err := gstProposalLines(ctx, s.ruleEngine, companyID, allTaxes, true)
+	if err != nil {
+		return nil, fmt.Errorf("resolve GST accounts for the return: %w", err)
+	}
+	amount := total.Add(taxTotal)
+
+	var reason *string
+	if r := strings.TrimSpace(input.Reason); r != "" {
+		reason = &r
+	}
+	var returnID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO purchase_returns
+		    (company_id, po_id, vendor_id, warehouse_id, return_date, reason, ap_account_code, amount, tax_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		companyID, input.POID, vendorID, warehouseID, returnDate, reason, apAccount, amount, taxTotal,
+	).Scan(&returnID); err != nil {
+		return nil, fmt.Errorf("insert purchase return: %w", err)
+	}
+
+	for _, rl := range lines {
+		var lineID int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO purchase_return_lines (return_id, po_line_id, quantity, unit_cost, line_amount, tax_amount)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			returnID, rl.line.POLineID, rl.line.Quantity, rl.line.UnitCost, rl.line.LineAmount, rl.line.TaxAmount,
+		).Scan(&lineID); err != nil {
+			return nil, fmt.Errorf("insert purchase return line for PO line %d: %w", rl.line.POLineID, err)
+		}
+		if err := insertLineTaxesTx(ctx, tx, companyID, lineTaxPurchaseReturnLine, lineID, rl.taxes); err != nil {
+			return nil, err
+		}
+		if err := writeReturnMovementsTx(ctx, tx, companyID, returnDate, rl); err != nil {
+			return nil, err
+		}
+	}
+
+	// DR AP for the goods and the GST on them; CR Inventory at receipt cost and CR the
+	// input tax credit taken on the vendor's invoice.
+	proposalLines := []ProposalLine{
+		{AccountCode: apAccount, IsDebit: true, Amount: amount.StringFixed(2)},
+		{AccountCode: inventoryAccount, IsDebit: false, Amount: total.StringFixed(2)},
+	}
+	for _, l := range taxLines {
+		l.IsDebit = false
+		proposalLines = append(proposalLines, l)
+	}
+	reasoning := fmt.Sprintf("Purchase return of %d line(s) against PO %s at original receipt cost.", len(lines), poRef)
+	if taxTotal.IsPositive() {
+		reasoning += fmt.Sprintf(" Input GST of %s reversed on the invoiced quantity returned.", taxTotal.StringFixed(2))
+	}
+
+	idempotencyKey := fmt.Sprintf("purchase-return-%d", returnID)
+	proposal := Proposal{
+		DocumentTypeCode:    "DN",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      idempotencyKey,
+		TransactionCurrency: baseCurrency,
+		ExchangeRate:        "1",
+		Summary:             fmt.Sprintf("Debit note: goods returned to %s (PO %s)", vendorName, poRef),
+		PostingDate:         returnDate,
+		DocumentDate:        returnDate,
+		Confidence:          1.0,
+		Reasoning:           reasoning,
+		Lines:               proposalLines,
+	}
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return nil, fmt.Errorf("post debit note journal entry: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE purchase_returns
+		SET debit_note_number = (SELECT reference_id FROM journal_entries WHERE idempotency_key = $1)
+		WHERE id = $2`,
+		idempotencyKey, returnID,
+	); err != nil {
+		return nil, fmt.Errorf("set debit note number: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit purchase return: %w", err)
+	}
+	return s.GetReturn(ctx, companyCode, returnID)
+}
+
+// returnLineTx claims the returned quantity on a PO line and takes the goods out of the
+// warehouse's stock: the inventory item is locked, its quantity reduced and its weighted
+// average cost adjusted for removing the goods at their receipt cost.
+func (s *purchaseReturnService) returnLineTx(ctx context.Context, tx pgx.Tx, companyID, warehouseID, poID int,
+	in PurchaseReturnLineInput) (returnLine, error) {
+
+	var rl returnLine
+	var productID *int
+	var productCode *string
+	var unitFactor, received, returned decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		SELECT pol.product_id, p.code, pol.description, pol.uom_factor, pol.received_quantity, pol.returned_quantity
+		FROM purchase_order_lines pol
+		LEFT JOIN products p ON p.id = pol.product_id
+		WHERE pol.id = $1 AND pol.order_id = $2
+		FOR UPDATE OF pol`,
+		in.POLineID, poID,
+	).Scan(&productID, &productCode, &rl.line.Description, &unitFactor, &received, &returned); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rl, fmt.Errorf("PO line %d not found on purchase order %d", in.POLineID, poID)
+		}
+		return rl, fmt.Errorf("fetch PO line %d: %w", in.POLineID, err)
+	}
+	if productID == nil {
+		return rl, fmt.Errorf("PO line %d is a service line; only goods can be returned", in.POLineID)
+	}
+	if returned.Add(in.Quantity).GreaterThan(received) {
+		return rl, fmt.Errorf("PO line %d: would return %s but only %s received (already returned %s)",
+			in.POLineID, returned.Add(in.Quantity).StringFixed(4), received.StringFixed(4), returned.StringFixed(4))
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE purchase_order_lines SET returned_quantity = returned_quantity + $1 WHERE id = $2",
+		in.Quantity, in.POLineID,
+	); err != nil {
+		return rl, fmt.Errorf("update returned quantity for PO line %d: %w", in.POLineID, err)
+	}
+
+	rl.productCode = *productCode
+	rl.line.POLineID = in.POLineID
+	rl.line.ProductCode = *productCode
+	rl.line.Quantity = in.Quantity
+	rl.stockQty = in.Quantity.Mul(unitFactor)
+
+	var onHand, avgCost decimal.Decimal
+	var trackingMode string
+	if err := tx.QueryRow(ctx, `
+		SELECT ii.id, ii.qty_on_hand, ii.unit_cost, p.tracking_mode
+		FROM inventory_items ii
+		JOIN products p ON p.id = ii.product_id
+		WHERE ii.company_id = $1 AND ii.product_id = $2 AND ii.warehouse_id = $3
+		FOR UPDATE OF ii`,
+		companyID, *productID, warehouseID,
+	).Scan(&rl.itemID, &onHand, &avgCost, &trackingMode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rl, fmt.Errorf("PO line %d: product %s has no stock in this warehouse", in.POLineID, rl.productCode)
+		}
+		return rl, fmt.Errorf("lock inventory item for product %s: %w", rl.productCode, err)
+	}
+
+	// Receipt cost is taken from the line's own goods receipts into this warehouse;
+	// earlier returns (negative receipts) reduce what is still returnable from it.
+	var netReceived, receivedQty, receivedValue decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(quantity), 0),
+		       COALESCE(SUM(quantity) FILTER (WHERE quantity > 0), 0),
+		       COALESCE(SUM(total_cost) FILTER (WHERE quantity > 0), 0)
+		FROM inventory_movements
+		WHERE po_line_id = $1 AND inventory_item_id = $2 AND movement_type = 'RECEIPT'`,
+		in.POLineID, rl.itemID,
+	).Scan(&netReceived, &receivedQty, &receivedValue); err != nil {
+		return rl, fmt.Errorf("load receipts for PO line %d: %w", in.POLineID, err)
+	}
+	if netReceived.LessThan(rl.stockQty) {
+		return rl, fmt.Errorf("PO line %d: only %s units received into this warehouse remain to return, need %s",
+			in.POLineID, netReceived.StringFixed(4), rl.stockQty.StringFixed(4))
+	}
+	if onHand.LessThan(rl.stockQty) {
+		return rl, fmt.Errorf("insufficient stock to return product %s: %s on hand, need %s",
+			rl.productCode, onHand.StringFixed(4), rl.stockQty.StringFixed(4))
+	}
+
+	rl.stockCost = receivedValue.Div(receivedQty)
+	rl.line.UnitCost = rl.stockCost.Mul(unitFactor)
+	rl.line.LineAmount = rl.stockQty.Mul(rl.stockCost).Round(2)
+
+	taxes, err := returnedInputTaxTx(ctx, tx, in.POLineID, in.Quantity)
+	if err != nil {
+		return rl, err
+	}
+	rl.taxes = taxes
+	rl.line.TaxAmount = sumTax(taxes)
+
+	newQty := onHand.Sub(rl.stockQty)
+	newCost := avgCost
+	if newQty.IsPositive() {
+		newCost = onHand.Mul(avgCost).Sub(rl.line.LineAmount).Div(newQty)
+		if newCost.IsNegative() {
+			return rl, fmt.Errorf("returning product %s at receipt cost %s would make the remaining stock cost negative",
+				rl.productCode, rl.stockCost.StringFixed(4))
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE inventory_items SET qty_on_hand = $1, unit_cost = $2, updated_at = NOW()
+		WHERE id = $3`,
+		newQty, newCost, rl.itemID,
+	); err != nil {
+		return rl, fmt.Errorf("update inventory item for product %s: %w", rl.productCode, err)
+	}
+
+	if trackingMode != "NONE" {
+		lots, err := drawReturnLotsTx(ctx, tx, rl.itemID, in.POLineID, rl.productCode, rl.stockQty)
+		if err != nil {
+			return rl, err
+		}
+		rl.lots = lots
+	}
+	return rl, nil
+}
+
+// returnedInputTaxTx returns the input GST to reverse when qty of a PO line goes back to
+// the vendor: the components on the line's vendor invoice, pro rata to qty and converted
+// at the PO rate. A line the vendor has not invoiced yet carries no input tax credit.
+func returnedInputTaxTx(ctx context.Context, tx pgx.Tx, poLineID int, qty decimal.Decimal) ([]LineTax, error) {
+	var invoiceLineID int
+	var invoicedQty, exchangeRate decimal.Decimal
+	err := tx.QueryRow(ctx, `
+		SELECT vil.id, vil.qty_invoiced, po.exchange_rate
+		FROM vendor_invoice_lines vil
+		JOIN purchase_orders po ON po.id = vil.po_id
+		WHERE vil.po_line_id = $1`,
+		poLineID,
+	).Scan(&invoiceLineID, &invoicedQty, &exchangeRate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch vendor invoice of PO line %d: %w", poLineID, err)
+	}
+	taxes, err := fetchLineTaxesQ(ctx, tx, lineTaxVendorInvoiceLine, []int{invoiceLineID})
+	if err != nil {
+		return nil, err
+	}
+	return ProrateGST(taxes[invoiceLineID], qty, invoicedQty, exchangeRate), nil
+}
+
+// drawReturnLotsTx takes returned goods out of the lots received on the PO line, oldest
+// receipt first. The inventory item lock held by the caller serialises lot changes.
+func drawReturnLotsTx(ctx context.Context, tx pgx.Tx, itemID, poLineID int, productCode string, qty decimal.Decimal) ([]lotDraw, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT l.id, l.lot_number, LEAST(l.qty_on_hand, SUM(im.quantity))
+		FROM inventory_movements im
+		JOIN inventory_lots l ON l.id = im.lot_id
+		WHERE im.po_line_id = $1 AND im.inventory_item_id = $2 AND im.movement_type = 'RECEIPT'
+		GROUP BY l.id, l.lot_number, l.qty_on_hand
+		HAVING LEAST(l.qty_on_hand, SUM(im.quantity)) > 0
+		ORDER BY MIN(im.id)`,
+		poLineID, itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load lots received on PO line %d: %w", poLineID, err)
+	}
+
+	var draws []lotDraw
+	remaining := qty
+	for rows.Next() && remaining.IsPositive() {
+		var d lotDraw
+		var available decimal.Decimal
+		if err := rows.Scan(&d.lotID, &d.lotNumber, &available); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan lot for product %s: %w", productCode, err)
+		}
+		d.quantity = decimal.Min(available, remaining)
+		remaining = remaining.Sub(d.quantity)
+		draws = append(draws, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate lots for product %s: %w", productCode, err)
+	}
+	if remaining.IsPositive() {
+		return nil, fmt.Errorf("lots received on PO line %d hold %s units of product %s, need %s",
+			poLineID, qty.Sub(remaining).StringFixed(4), productCode, qty.StringFixed(4))
+	}
+
+	for _, d := range draws {
+		if err := deductLotTx(ctx, tx, d); err != nil {
+			return nil, err
+		}
+	}
+	return draws, nil
+}
+
+// writeReturnMovementsTx records a returned line as negative RECEIPT movements on its PO
+// line at receipt cost: one per lot drawn, otherwise one for the line.
+func writeReturnMovementsTx(ctx context.Context, tx pgx.Tx, companyID int, returnDate string, rl returnLine) error {
+	if len(rl.lots) == 0 {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id)
+			VALUES ($1, $2, 'RECEIPT', $3, $4, $5, $6, $7, $8)`,
+			companyID, rl.itemID, rl.stockQty.Neg(), rl.stockCost, rl.line.LineAmount.Neg(), returnDate,
+			fmt.Sprintf("Purchase return: %s × %s units @ %s", rl.productCode, rl.stockQty.String(), rl.stockCost.StringFixed(4)),
+			rl.line.POLineID,
+		); err != nil {
+			return fmt.Errorf("insert return movement for product %s: %w", rl.productCode, err)
+		}
+		return nil
+	}
+	for _, d := range rl.lots {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO inventory_movements (company_id, inventory_item_id, movement_type, quantity, unit_cost, total_cost, movement_date, notes, po_line_id, lot_id)
+			VALUES ($1, $2, 'RECEIPT', $3, $4, $5, $6, $7, $8, $9)`,
+			companyID, rl.itemID, d.quantity.Neg(), rl.stockCost, d.quantity.Mul(rl.stockCost).Round(2).Neg(), returnDate,
+			fmt.Sprintf("Purchase return: %s × %s units @ %s (lot %s)", rl.productCode, d.quantity.String(), rl.stockCost.StringFixed(4), d.lotNumber),
+			rl.line.POLineID, d.lotID,
+		); err != nil {
+			return fmt.Errorf("insert return movement for product %s lot %s: %w", rl.productCode, d.lotNumber, err)
+		}
+	}
+	return nil
+}
+
+// ── Debit note offsets ────────────────────────────────────────────────────────
+
+// applyDebitNoteTx offsets amount of an OPEN debit note against a payment — a direct PO
+// payment (poID) or a payment run (runID) — and records the application. The debit note
+// moves to APPLIED once fully offset.
+func applyDebitNoteTx(ctx context.Context, tx pgx.Tx, returnID int, amount decimal.Decimal, poID, runID *int) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE purchase_returns
+		SET applied_amount = applied_amount + $1,
+		    status = CASE WHEN applied_amount + $1 >= amount THEN 'APPLIED' ELSE 'OPEN' END
+		WHERE id = $2 AND status = 'OPEN' AND applied_amount + $1 <= amount`,
+		amount, returnID,
+	)
+	if err != nil {
+		return fmt.Errorf("apply debit note %d: %w", returnID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("debit note %d has less than %s open", returnID, amount.StringFixed(2))
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO debit_note_applications (return_id, po_id, payment_run_id, amount)
+		VALUES ($1, $2, $3, $4)`,
+		returnID, poID, runID, amount,
+	); err != nil {
+		return fmt.Errorf("record debit note %d application: %w", returnID, err)
+	}
+	return nil
+}
+
+// offsetDebitNotesTx applies a vendor's open debit notes, oldest first, against a payment
+// of a purchase order, up to limit. Debit notes proposed on an unposted payment run are
+// left for that run. Returns the amount offset.
+func offsetDebitNotesTx(ctx context.Context, tx pgx.Tx, vendorID, poID int, limit decimal.Decimal) (decimal.Decimal, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT pr.id, pr.amount - pr.applied_amount
+		FROM purchase_returns pr
+		WHERE pr.vendor_id = $1 AND pr.status = 'OPEN'
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM payment_run_items pri
+		      JOIN payment_runs r ON r.id = pri.run_id
+		      WHERE pri.return_id = pr.id AND r.status IN ('DRAFT', 'APPROVED') AND NOT pri.excluded
+		  )
+		ORDER BY pr.return_date, pr.id
+		FOR UPDATE OF pr`,
+		vendorID,
+	)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("load open debit notes: %w", err)
+	}
+	type openNote struct {
+		id   int
+		open decimal.Decimal
+	}
+	var notes []openNote
+	for rows.Next() {
+		var n openNote
+		if err := rows.Scan(&n.id, &n.open); err != nil {
+			rows.Close()
+			return decimal.Zero, fmt.Errorf("scan open debit note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return decimal.Zero, fmt.Errorf("iterate open debit notes: %w", err)
+	}
+
+	offset := decimal.Zero
+	for _, n := range notes {
+		amount := decimal.Min(n.open, limit.Sub(offset))
+		if !amount.IsPositive() {
+			break
+		}
+		if err := applyDebitNoteTx(ctx, tx, n.id, amount, &poID, nil); err != nil {
+			return decimal.Zero, err
+		}
+		offset = offset.Add(amount)
+	}
+	return offset, nil
+}
+
+// settleInvoicedReturnsTx closes the open debit notes of a purchase order's returns when
+// its vendor invoice is recorded. The invoice is matched against the quantity kept, so the
+// vendor has billed net of the returned goods and the debit notes are settled by it rather
+// than offset against a later payment.
+func settleInvoicedReturnsTx(ctx context.Context, tx pgx.Tx, poID int) error {
+	rows, err := tx.Query(ctx, `
+		SELECT id, amount - applied_amount
+		FROM purchase_returns
+		WHERE po_id = $1 AND status = 'OPEN'
+		ORDER BY id
+		FOR UPDATE`,
+		poID,
+	)
+	if err != nil {
+		return fmt.Errorf("load open debit notes of PO %d: %w", poID, err)
+	}
+	type openNote struct {
+		id   int
+		open decimal.Decimal
+	}
+	var notes []openNote
+	for rows.Next() {
+		var n openNote
+		if err := rows.Scan(&n.id, &n.open); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan open debit note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate open debit notes: %w", err)
+	}
+
+	for _, n := range notes {
+		if err := applyDebitNoteTx(ctx, tx, n.id, n.open, &poID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ── Queries ───────────────────────────────────────────────────────────────────
+
+const purchaseReturnSelect = `
+	SELECT pr.id, pr.company_id, pr.po_id, po.po_number, pr.vendor_id, v.code, v.name, w.code,
+	       pr.return_date::text, pr.reason, pr.status, pr.ap_account_code, pr.amount, pr.tax_amount, pr.applied_amount,
+	       pr.debit_note_number, pr.created_at
+	FROM purchase_returns pr
+	JOIN purchase_orders po ON po.id = pr.po_id
+	JOIN vendors v          ON v.id = pr.vendor_id
+	JOIN warehouses w       ON w.id = pr.warehouse_id
+	JOIN companies c        ON c.id = pr.company_id`
+
+func scanPurchaseReturn(row pgx.Row) (PurchaseReturn, error) {
+	var r PurchaseReturn
+	err := row.Scan(&r.ID, &r.CompanyID, &r.POID, &r.PONumber, &r.VendorID, &r.VendorCode, &r.VendorName,
+		&r.WarehouseCode, &r.ReturnDate, &r.Reason, &r.Status, &r.APAccountCode, &r.Amount, &r.TaxAmount, &r.AppliedAmount,
+		&r.DebitNoteNumber, &r.CreatedAt)
+	return r, err
+}
+
+func (s *purchaseReturnService) GetReturns(ctx context.Context, companyCode, status, vendorCode string) ([]PurchaseReturn, error) {
+	rows, err := s.pool.Query(ctx, purchaseReturnSelect+`
+		WHERE c.company_code = $1 AND ($2 = '' OR pr.status = $2) AND ($3 = '' OR v.code = $3)
+		ORDER BY pr.return_date DESC, pr.id DESC`,
+		companyCode, strings.ToUpper(status), vendorCode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query purchase returns: %w", err)
+	}
+	defer rows.Close()
+
+	var returns []PurchaseReturn
+	for rows.Next() {
+		r, err := scanPurchaseReturn(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan purchase return: %w", err)
+		}
+		returns = append(returns, r)
+	}
+	return returns, rows.Err()
+}
+
+func (s *purchaseReturnService) GetReturn(ctx context.Context, companyCode string, returnID int) (*PurchaseReturn, error) {
+	r, err := scanPurchaseReturn(s.pool.QueryRow(ctx, purchaseReturnSelect+`
+		WHERE c.company_code = $1 AND pr.id = $2`, companyCode, returnID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("purchase return %d not found", returnID)
+		}
+		return nil, fmt.Errorf("fetch purchase return: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT prl.id, prl.po_line_id, COALESCE(p.code, ''), pol.description,
+		       prl.quantity, prl.unit_cost, prl.line_amount, prl.tax_amount
+		FROM purchase_return_lines prl
+		JOIN purchase_order_lines pol ON pol.id = prl.po_line_id
+		LEFT JOIN products p          ON p.id = pol.product_id
+		WHERE prl.return_id = $1
+		ORDER BY prl.id`, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("query purchase return lines: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l PurchaseReturnLine
+		if err := rows.Scan(&l.ID, &l.POLineID, &l.ProductCode, &l.Description,
+			&l.Quantity, &l.UnitCost, &l.LineAmount, &l.TaxAmount); err != nil {
+			return nil, fmt.Errorf("scan purchase return line: %w", err)
+		}
+		l.LineNumber = len(r.Lines) + 1
+		r.Lines = append(r.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate purchase return lines: %w", err)
+	}
+
+	arows, err := s.pool.Query(ctx, `
+		SELECT id, po_id, payment_run_id, amount, applied_at
+		FROM debit_note_applications
+		WHERE return_id = $1
+		ORDER BY id`, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("query debit note applications: %w", err)
+	}
+	defer arows.Close()
+	for arows.Next() {
+		var a DebitNoteApplication
+		if err := arows.Scan(&a.ID, &a.POID, &a.PaymentRunID, &a.Amount, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scan debit note application: %w", err)
+		}
+		r.Applications = append(r.Applications, a)
+	}
+	if err := arows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate debit note applications: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,208 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestReplenishment_SuggestAndRaisePOs(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	replSvc := core.NewReplenishmentService(pool)
+
+	// P002 has a policy but no preferred vendor and no purchase history.
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO products (company_id, code, name, description, unit_price, unit, revenue_account_code)
+		VALUES (1, 'P002', 'Widget B', 'Second widget', 300.00, 'unit', '4000')
+		ON CONFLICT (company_id, code) DO NOTHING;
+	`); err != nil {
+		t.Fatalf("seed P002: %v", err)
+	}
+
+	// 5 units on hand at 400 each — below the reorder point of 10.
+	if err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001",
+		decimal.NewFromInt(5), decimal.NewFromInt(400), "2026-03-01", "2000", nil, ledger, docService); err != nil {
+		t.Fatalf("ReceiveStock: %v", err)
+	}
+
+	t.Run("SetReorderPolicy_UnknownVendor_Fails", func(t *testing.T) {
+		_, err := replSvc.SetReorderPolicy(ctx, "1000", core.ReorderPolicyInput{
+			ProductCode:         "P001",
+			WarehouseCode:       "MAIN",
+			ReorderPoint:        decimal.NewFromInt(10),
+			ReorderQty:          decimal.NewFromInt(50),
+			PreferredVendorCode: "NOPE",
+		})
+		if err == nil {
+			t.Error("expected error for unknown preferred vendor, got nil")
+		}
+	})
+
+	t.Run("SetReorderPolicy_Upsert", func(t *testing.T) {
+		for _, qty := range []int64{20, 50} {
+			if _, err := replSvc.SetReorderPolicy(ctx, "1000", core.ReorderPolicyInput{
+				ProductCode:         "P001",
+				WarehouseCode:       "MAIN",
+				ReorderPoint:        decimal.NewFromInt(10),
+				ReorderQty:          decimal.NewFromInt(qty),
+				LeadTimeDays:        7,
+				PreferredVendorCode: "V001",
+			}); err != nil {
+				t.Fatalf("SetReorderPolicy P001: %v", err)
+			}
+		}
+		if _, err := replSvc.SetReorderPolicy(ctx, "1000", core.ReorderPolicyInput{
+			ProductCode:  "P002",
+			ReorderPoint: decimal.NewFromInt(5),
+			ReorderQty:   decimal.NewFromInt(10),
+		}); err != nil {
+			t.Fatalf("SetReorderPolicy P002: %v", err)
+		}
+
+		policies, err := replSvc.GetReorderPolicies(ctx, "1000")
+		if err != nil {
+			t.Fatalf("GetReorderPolicies: %v", err)
+		}
+		if len(policies) != 2 {
+			t.Fatalf("expected 2 policies after upsert, got %d", len(policies))
+		}
+		if !policies[0].ReorderQty.Equal(decimal.NewFromInt(50)) {
+			t.Errorf("expected P001 reorder qty 50 after upsert, got %s", policies[0].ReorderQty)
+		}
+		if policies[1].WarehouseCode != "MAIN" {
+			t.Errorf("expected P002 policy to default to MAIN, got %s", policies[1].WarehouseCode)
+		}
+	})
+
+	t.Run("GetSuggestions", func(t *testing.T) {
+		suggestions, err := replSvc.GetSuggestions(ctx, "1000")
+		if err != nil {
+			t.Fatalf("GetSuggestions: %v", err)
+		}
+		if len(suggestions) != 2 {
+			t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+		}
+		sg := suggestions[0]
+		if sg.ProductCode != "P001" {
+			t.Fatalf("expected first suggestion P001, got %s", sg.ProductCode)
+		}
+		if !sg.Available.Equal(decimal.NewFromInt(5)) {
+			t.Errorf("expected available 5, got %s", sg.Available)
+		}
+		if !sg.SuggestedQty.Equal(decimal.NewFromInt(50)) {
+			t.Errorf("expected suggested qty 50, got %s", sg.SuggestedQty)
+		}
+		if !sg.UnitCost.Equal(decimal.NewFromInt(400)) {
+			t.Errorf("expected unit cost 400 (weighted average), got %s", sg.UnitCost)
+		}
+		if sg.VendorCode == nil || *sg.VendorCode != "V001" {
+			t.Errorf("expected preferred vendor V001, got %v", sg.VendorCode)
+		}
+		if suggestions[1].VendorID != nil {
+			t.Errorf("expected P002 to have no vendor, got %d", *suggestions[1].VendorID)
+		}
+	})
+
+	t.Run("CreateReplenishmentPOs", func(t *testing.T) {
+		poDate := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+		run, err := replSvc.CreateReplenishmentPOs(ctx, "1000", poDate, nil, poService)
+		if err != nil {
+			t.Fatalf("CreateReplenishmentPOs: %v", err)
+		}
+		if len(run.PurchaseOrders) != 1 {
+			t.Fatalf("expected 1 PO, got %d", len(run.PurchaseOrders))
+		}
+		po := run.PurchaseOrders[0]
+		if po.Status != "DRAFT" || po.VendorCode != "V001" {
+			t.Errorf("expected DRAFT PO for V001, got %s for %s", po.Status, po.VendorCode)
+		}
+		if len(po.Lines) != 1 || !po.Lines[0].Quantity.Equal(decimal.NewFromInt(50)) {
+			t.Errorf("expected one line of 50 units, got %+v", po.Lines)
+		}
+		if len(run.Skipped) != 1 || run.Skipped[0].ProductCode != "P002" {
+			t.Errorf("expected P002 to be skipped, got %+v", run.Skipped)
+		}
+	})
+
+	t.Run("OpenPOQuantity_SuppressesRepeatSuggestion", func(t *testing.T) {
+		suggestions, err := replSvc.GetSuggestions(ctx, "1000")
+		if err != nil {
+			t.Fatalf("GetSuggestions: %v", err)
+		}
+		for _, sg := range suggestions {
+			if sg.ProductCode == "P001" {
+				t.Errorf("P001 should be covered by the open DRAFT PO, got projected %s", sg.Projected)
+			}
+		}
+	})
+}
+
+// TestReplenishment_ForeignLastPOCost: the last PO's unit cost is only reused when that PO
+// was in base currency; a USD price is not carried onto a base-currency replenishment PO.
+func TestReplenishment_ForeignLastPOCost(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	replSvc := core.NewReplenishmentService(pool)
+
+	var usdVendorID int
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO vendors (company_id, code, name, payment_terms_days, ap_account_code, currency)
+		VALUES (1, 'V-USD', 'US Supplier', 30, '2000', 'USD')
+		RETURNING id`,
+	).Scan(&usdVendorID); err != nil {
+		t.Fatalf("seed USD vendor: %v", err)
+	}
+
+	// Last purchase: 5 × P001 @ USD 5 at 80, received into MAIN at 400 each.
+	po, err := poService.CreatePO(ctx, 1, usdVendorID, "", decimal.NewFromInt(80), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		[]core.PurchaseOrderLineInput{
+			{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(5), UnitCost: decimal.NewFromInt(5)},
+		}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+	if err := poService.ReceivePO(ctx, po.ID, "MAIN", "1000",
+		[]core.ReceivedLine{{POLineID: po.Lines[0].ID, QtyReceived: decimal.NewFromInt(5)}},
+		"2000", ledger, docService, invSvc); err != nil {
+		t.Fatalf("ReceivePO: %v", err)
+	}
+
+	if _, err := replSvc.SetReorderPolicy(ctx, "1000", core.ReorderPolicyInput{
+		ProductCode:         "P001",
+		WarehouseCode:       "MAIN",
+		ReorderPoint:        decimal.NewFromInt(10),
+		ReorderQty:          decimal.NewFromInt(20),
+		PreferredVendorCode: "V001",
+	}); err != nil {
+		t.Fatalf("SetReorderPolicy: %v", err)
+	}
+
+	suggestions, err := replSvc.GetSuggestions(ctx, "1000")
+	if err != nil {
+		t.Fatalf("GetSuggestions: %v", err)
+	}
+	if len(suggestions) != 1 || !suggestions[0].UnitCost.Equal(decimal.NewFromInt(400)) {
+		t.Fatalf("expected one suggestion at the base stock cost 400, got %+v", suggestions)
+	}
+
+	run, err := replSvc.CreateReplenishmentPOs(ctx, "1000", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), nil, poService)
+	if err != nil {
+		t.Fatalf("CreateReplenishmentPOs: %v", err)
+	}
+	if len(run.PurchaseOrders) != 1 {
+		t.Fatalf("expected 1 PO, got %d", len(run.PurchaseOrders))
+	}
+	got := run.PurchaseOrders[0]
+	if got.Currency != "INR" || len(got.Lines) != 1 || !got.Lines[0].UnitCost.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("expected an INR PO line at 400, got %s %+v", got.Currency, got.Lines)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReorderPolicy holds the replenishment parameters for one product in one warehouse.
+type ReorderPolicy struct {
+	ID                  int
+	CompanyID           int
+	ProductCode         string
+	ProductName         string
+	WarehouseCode       string
+	WarehouseName       string
+	ReorderPoint        decimal.Decimal
+	ReorderQty          decimal.Decimal
+	LeadTimeDays        int
+	PreferredVendorCode *string
+	PreferredVendorName *string
+	IsActive            bool
+	UpdatedAt           time.Time
+}
+
+// ReorderPolicyInput holds the fields required to create or update a reorder policy.
+// An empty WarehouseCode resolves to the company's default warehouse.
+// An empty PreferredVendorCode clears the preferred vendor.
+type ReorderPolicyInput struct {
+	ProductCode         string
+	WarehouseCode       string
+	ReorderPoint        decimal.Decimal
+	ReorderQty          decimal.Decimal
+	LeadTimeDays        int
+	PreferredVendorCode string
+}
+
+// ReplenishmentSuggestion is one product/warehouse whose projected stock has fallen
+// to or below its reorder point.
+//
+//	Projected = Available (on hand - reserved) + OnOrder (open PO qty not yet received)
+//
+// All quantities and UnitCost are in the product's stock unit. PurchaseUnit and
+// PurchaseFactor describe the unit the PO line will be raised in.
+type ReplenishmentSuggestion struct {
+	ProductCode    string
+	ProductName    string
+	WarehouseCode  string
+	OnHand         decimal.Decimal
+	Reserved       decimal.Decimal
+	Available      decimal.Decimal
+	OnOrder        decimal.Decimal
+	Projected      decimal.Decimal
+	ReorderPoint   decimal.Decimal
+	ReorderQty     decimal.Decimal
+	SuggestedQty   decimal.Decimal
+	UnitCost       decimal.Decimal // base currency: last PO cost if that PO was in base, else weighted average stock cost
+	LineValue      decimal.Decimal // = SuggestedQty × UnitCost
+	VendorID       *int            // preferred vendor, else vendor of the latest PO; nil if unknown
+	VendorCode     *string
+	VendorName     *string
+	LeadTimeDays   int
+	ExpectedDate   string          // YYYY-MM-DD: today + LeadTimeDays
+	PurchaseUnit   string          // product purchase unit, else stock unit
+	PurchaseFactor decimal.Decimal // stock units per PurchaseUnit
+}
+
+// ReplenishmentRun is the outcome of generating DRAFT purchase orders from suggestions.
+type ReplenishmentRun struct {
+	PurchaseOrders []PurchaseOrder           // one DRAFT PO per vendor
+	Skipped        []ReplenishmentSuggestion // suggestions with no resolvable vendor
+}
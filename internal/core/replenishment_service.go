@@ -0,0 +1,376 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// ReplenishmentService maintains reorder policies and turns stock shortfalls into
+// DRAFT purchase orders.
+type ReplenishmentService interface {
+	// SetReorderPolicy creates or updates the reorder policy for a product in a warehouse.
+	SetReorderPolicy(ctx context.Context, companyCode string, input ReorderPolicyInput) (*ReorderPolicy, error)
+
+	// GetReorderPolicies returns all active reorder policies for a company.
+	GetReorderPolicies(ctx context.Context, companyCode string) ([]ReorderPolicy, error)
+
+	// GetSuggestions returns every product/warehouse whose projected stock
+	// (available + open PO quantity not yet received) is at or below its reorder point.
+	GetSuggestions(ctx context.Context, companyCode string) ([]ReplenishmentSuggestion, error)
+
+	// CreateReplenishmentPOs raises one DRAFT purchase order per vendor covering the
+	// current suggestions. productCodes, if non-empty, restricts the run to those products.
+	// Suggestions without a resolvable vendor are returned in Skipped.
+	CreateReplenishmentPOs(ctx context.Context, companyCode string, poDate time.Time, productCodes []string, poService PurchaseOrderService) (*ReplenishmentRun, error)
+}
+
+type replenishmentService struct {
+	pool *pgxpool.Pool
+}
+
+// NewReplenishmentService constructs a ReplenishmentService backed by PostgreSQL.
+func NewReplenishmentService(pool *pgxpool.Pool) ReplenishmentService {
+	return &replenishmentService{pool: pool}
+}
+
+// ── Reorder policies ──────────────────────────────────────────────────────────
+
+func (s *replenishmentService) SetReorderPolicy(ctx context.Context, companyCode string, input ReorderPolicyInput) (*ReorderPolicy, error) {
+	if input.ReorderPoint.IsNegative() || input.ReorderQty.IsNegative() {
+		return nil, fmt.Errorf("reorder point and reorder quantity must not be negative")
+	}
+	if input.LeadTimeDays < 0 {
+		return nil, fmt.Errorf("lead time days must not be negative")
+	}
+
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var productID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id FROM products WHERE company_id = $1 AND code = $2 AND is_active = true",
+		companyID, input.ProductCode,
+	).Scan(&productID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %q not found", input.ProductCode)
+		}
+		return nil, fmt.Errorf("resolve product: %w", err)
+	}
+
+	var warehouseID int
+	if input.WarehouseCode == "" {
+		err = s.pool.QueryRow(ctx,
+			"SELECT id FROM warehouses WHERE company_id = $1 AND is_active = true ORDER BY id LIMIT 1",
+			companyID,
+		).Scan(&warehouseID)
+	} else {
+		err = s.pool.QueryRow(ctx,
+			"SELECT id FROM warehouses WHERE company_id = $1 AND code = $2 AND is_active = true",
+			companyID, input.WarehouseCode,
+		).Scan(&warehouseID)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if input.WarehouseCode == "" {
+				return nil, fmt.Errorf("no active warehouse found for company %s", companyCode)
+			}
+			return nil, fmt.Errorf("warehouse %q not found", input.WarehouseCode)
+		}
+		return nil, fmt.Errorf("resolve warehouse: %w", err)
+	}
+
+	var vendorID *int
+	if input.PreferredVendorCode != "" {
+		var vid int
+		if err := s.pool.QueryRow(ctx,
+			"SELECT id FROM vendors WHERE company_id = $1 AND code = $2 AND is_active = true",
+			companyID, input.PreferredVendorCode,
+		).Scan(&vid); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("vendor %q not found", input.PreferredVendorCode)
+			}
+			return nil, fmt.Errorf("resolve vendor: %w", err)
+		}
+		vendorID = &vid
+	}
+
+	var policyID int
+	if err := s.pool.QueryRow(ctx, `
+		INSERT INTO reorder_policies (company_id, product_id, warehouse_id, reorder_point, reorder_qty,
+		                              lead_time_days, preferred_vendor_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (company_id, product_id, warehouse_id) DO UPDATE
+		SET reorder_point       = EXCLUDED.reorder_point,
+		    reorder_qty         = EXCLUDED.reorder_qty,
+		    lead_time_days      = EXCLUDED.lead_time_days,
+		    preferred_vendor_id = EXCLUDED.preferred_vendor_id,
+		    is_active           = true,
+		    updated_at          = NOW()
+		RETURNING id`,
+		companyID, productID, warehouseID, input.ReorderPoint, input.ReorderQty,
+		input.LeadTimeDays, vendorID,
+	).Scan(&policyID); err != nil {
+		return nil, fmt.Errorf("save reorder policy: %w", err)
+	}
+
+	policies, err := s.queryPolicies(ctx, "rp.id = $1", policyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("reorder policy %d not found", policyID)
+	}
+	return &policies[0], nil
+}
+
+func (s *replenishmentService) GetReorderPolicies(ctx context.Context, companyCode string) ([]ReorderPolicy, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryPolicies(ctx, "rp.company_id = $1 AND rp.is_active = true", companyID)
+}
+
+func (s *replenishmentService) queryPolicies(ctx context.Context, where string, arg int) ([]ReorderPolicy, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT rp.id, rp.company_id, p.code, p.name, w.code, w.name,
+		       rp.reorder_point, rp.reorder_qty, rp.lead_time_days,
+		       v.code, v.name, rp.is_active, rp.updated_at
+		FROM reorder_policies rp
+		JOIN products p   ON p.id = rp.product_id
+		JOIN warehouses w ON w.id = rp.warehouse_id
+		LEFT JOIN vendors v ON v.id = rp.preferred_vendor_id
+		WHERE `+where+`
+		ORDER BY p.code, w.code`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("query reorder policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []ReorderPolicy
+	for rows.Next() {
+		var rp ReorderPolicy
+		if err := rows.Scan(
+			&rp.ID, &rp.CompanyID, &rp.ProductCode, &rp.ProductName,
+			&rp.WarehouseCode, &rp.WarehouseName,
+			&rp.ReorderPoint, &rp.ReorderQty, &rp.LeadTimeDays,
+			&rp.PreferredVendorCode, &rp.PreferredVendorName,
+			&rp.IsActive, &rp.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan reorder policy: %w", err)
+		}
+		policies = append(policies, rp)
+	}
+	return policies, rows.Err()
+}
+
+// ── Suggestions ───────────────────────────────────────────────────────────────
+
+// GetSuggestions compares each active policy against current stock.
+//
+// Open PO quantity is tracked per product (PO lines carry no warehouse), so it is
+// allocated across the product's warehouses in code order: each warehouse absorbs
+// up to reorder point + reorder qty − available before the remainder moves on.
+// DRAFT POs count as on order, so running the generator twice does not duplicate POs.
+// Only the unreceived balance counts; short-closed POs (moved to RECEIVED) drop out.
+// Replenishment POs are raised in base currency, so the latest PO's unit cost is only
+// reused when that PO was in base currency too; otherwise the stock cost is suggested,
+// else the latest PO's cost converted to base currency at its rate.
+func (s *replenishmentService) GetSuggestions(ctx context.Context, companyCode string) ([]ReplenishmentSuggestion, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		WITH open_po AS (
+		    SELECT pol.product_id,
+		           SUM(GREATEST(pol.quantity - pol.received_quantity, 0) * pol.uom_factor) AS qty
+		    FROM purchase_order_lines pol
+		    JOIN purchase_orders po ON po.id = pol.order_id
+		    WHERE po.company_id = $1
+		      AND po.status IN ('DRAFT', 'APPROVED', 'PARTIALLY_RECEIVED')
+		      AND pol.product_id IS NOT NULL
+		    GROUP BY pol.product_id
+		),
+		last_po AS (
+		    SELECT DISTINCT ON (pol.product_id)
+		           pol.product_id, pol.unit_cost / pol.uom_factor AS unit_cost, po.currency, po.exchange_rate,
+		           po.vendor_id
+		    FROM purchase_order_lines pol
+		    JOIN purchase_orders po ON po.id = pol.order_id
+		    WHERE po.company_id = $1 AND pol.product_id IS NOT NULL
+		    ORDER BY pol.product_id, po.po_date DESC, po.id DESC
+		)
+		SELECT p.code, p.name, w.code,
+		       COALESCE(ii.qty_on_hand, 0), COALESCE(ii.qty_reserved, 0),
+		       COALESCE(op.qty, 0),
+		       rp.reorder_point, rp.reorder_qty, rp.lead_time_days,
+		       COALESCE(CASE WHEN lp.currency = c.base_currency THEN lp.unit_cost END,
+		                ii.unit_cost, ROUND(lp.unit_cost * lp.exchange_rate, 4), 0),
+		       v.id, v.code, v.name,
+		       COALESCE(pu.code, p.unit), COALESCE(pc.factor, 1)
+		FROM reorder_policies rp
+		JOIN companies c  ON c.id = rp.company_id
+		JOIN products p   ON p.id = rp.product_id
+		JOIN warehouses w ON w.id = rp.warehouse_id
+		LEFT JOIN units_of_measure pu
+		       ON pu.company_id = p.company_id AND pu.code = p.purchase_uom
+		LEFT JOIN product_uom_conversions pc
+		       ON pc.product_id = p.id AND pc.uom_id = pu.id
+		LEFT JOIN inventory_items ii
+		       ON ii.company_id = rp.company_id AND ii.product_id = rp.product_id AND ii.warehouse_id = rp.warehouse_id
+		LEFT JOIN open_po op ON op.product_id = rp.product_id
+		LEFT JOIN last_po lp ON lp.product_id = rp.product_id
+		LEFT JOIN vendors v
+		       ON v.id = COALESCE(rp.preferred_vendor_id, lp.vendor_id) AND v.is_active = true
+		WHERE rp.company_id = $1 AND rp.is_active = true AND p.is_active = true
+		ORDER BY p.code, w.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("query replenishment positions: %w", err)
+	}
+	defer rows.Close()
+
+	today := time.Now()
+	remainingOnOrder := map[string]decimal.Decimal{}
+	var suggestions []ReplenishmentSuggestion
+	for rows.Next() {
+		var sg ReplenishmentSuggestion
+		var productOnOrder decimal.Decimal
+		if err := rows.Scan(
+			&sg.ProductCode, &sg.ProductName, &sg.WarehouseCode,
+			&sg.OnHand, &sg.Reserved, &productOnOrder,
+			&sg.ReorderPoint, &sg.ReorderQty, &sg.LeadTimeDays,
+			&sg.UnitCost, &sg.VendorID, &sg.VendorCode, &sg.VendorName,
+			&sg.PurchaseUnit, &sg.PurchaseFactor,
+		); err != nil {
+			return nil, fmt.Errorf("scan replenishment position: %w", err)
+		}
+
+		remaining, seen := remainingOnOrder[sg.ProductCode]
+		if !seen {
+			remaining = productOnOrder
+		}
+		sg.Available = sg.OnHand.Sub(sg.Reserved)
+
+		target := sg.ReorderPoint.Add(sg.ReorderQty).Sub(sg.Available)
+		allocated := decimal.Min(remaining, decimal.Max(target, decimal.Zero))
+		remainingOnOrder[sg.ProductCode] = remaining.Sub(allocated)
+		sg.OnOrder = allocated
+		sg.Projected = sg.Available.Add(sg.OnOrder)
+
+		if sg.Projected.GreaterThan(sg.ReorderPoint) {
+			continue
+		}
+		sg.SuggestedQty = decimal.Max(sg.ReorderQty, sg.ReorderPoint.Sub(sg.Projected))
+		if !sg.SuggestedQty.IsPositive() {
+			continue
+		}
+		sg.LineValue = sg.SuggestedQty.Mul(sg.UnitCost).Round(2)
+		sg.ExpectedDate = today.AddDate(0, 0, sg.LeadTimeDays).Format("2006-01-02")
+		suggestions = append(suggestions, sg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate replenishment positions: %w", err)
+	}
+	return suggestions, nil
+}
+
+// ── PO generation ─────────────────────────────────────────────────────────────
+
+func (s *replenishmentService) CreateReplenishmentPOs(ctx context.Context, companyCode string, poDate time.Time, productCodes []string, poService PurchaseOrderService) (*ReplenishmentRun, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.GetSuggestions(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseCurrency string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT base_currency FROM companies WHERE id = $1", companyID,
+	).Scan(&baseCurrency); err != nil {
+		return nil, fmt.Errorf("resolve company currency: %w", err)
+	}
+
+	var filter map[string]bool
+	if len(productCodes) > 0 {
+		filter = make(map[string]bool, len(productCodes))
+		for _, code := range productCodes {
+			filter[strings.ToUpper(strings.TrimSpace(code))] = true
+		}
+	}
+
+	run := &ReplenishmentRun{}
+	byVendor := map[int][]ReplenishmentSuggestion{}
+	for _, sg := range suggestions {
+		if filter != nil && !filter[strings.ToUpper(sg.ProductCode)] {
+			continue
+		}
+		if sg.VendorID == nil {
+			run.Skipped = append(run.Skipped, sg)
+			continue
+		}
+		byVendor[*sg.VendorID] = append(byVendor[*sg.VendorID], sg)
+	}
+
+	vendorIDs := make([]int, 0, len(byVendor))
+	for id := range byVendor {
+		vendorIDs = append(vendorIDs, id)
+	}
+	sort.Ints(vendorIDs)
+
+	for _, vendorID := range vendorIDs {
+		group := byVendor[vendorID]
+		maxLead := 0
+		var lines []PurchaseOrderLineInput
+		for _, sg := range group {
+			// Order whole purchase units: 50 EA bought in cartons of 24 becomes 3 CTN.
+			lines = append(lines, PurchaseOrderLineInput{
+				ProductCode: sg.ProductCode,
+				Description: fmt.Sprintf("Replenishment: %s (%s)", sg.ProductName, sg.WarehouseCode),
+				Quantity:    sg.SuggestedQty.Div(sg.PurchaseFactor).Ceil(),
+				Unit:        sg.PurchaseUnit,
+				UnitCost:    sg.UnitCost.Mul(sg.PurchaseFactor).Round(2),
+			})
+			if sg.LeadTimeDays > maxLead {
+				maxLead = sg.LeadTimeDays
+			}
+		}
+		notes := fmt.Sprintf("Auto-generated replenishment order. Lead time %d days; expected by %s.",
+			maxLead, poDate.AddDate(0, 0, maxLead).Format("2006-01-02"))
+
+		// Suggested costs are in base currency, so the PO is raised in base currency.
+		po, err := poService.CreatePO(ctx, companyID, vendorID, baseCurrency, decimal.NewFromInt(1), poDate, lines, notes)
+		if err != nil {
+			return nil, fmt.Errorf("create replenishment PO for vendor %s: %w", *group[0].VendorCode, err)
+		}
+		run.PurchaseOrders = append(run.PurchaseOrders, *po)
+	}
+
+	return run, nil
+}
+
+func (s *replenishmentService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var companyID int
+	if err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company code %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company: %w", err)
+	}
+	return companyID, nil
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Report export formats accepted by ExportReport.
+const (
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+	ReportFormatPDF  = "pdf"
+	ReportFormatText = "text"
+)
+
+// IsReportFormat reports whether format is one of the export formats.
+func IsReportFormat(format string) bool {
+	switch format {
+	case ReportFormatCSV, ReportFormatXLSX, ReportFormatPDF, ReportFormatText:
+		return true
+	}
+	return false
+}
+
+// ReportFile is a report rendered for download.
+type ReportFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// ReportHeader identifies a report on every page of an export.
+type ReportHeader struct {
+	CompanyCode string
+	CompanyName string
+	Title       string
+	Period      string // e.g. "March 2026" or "As of 2026-03-31"
+	Currency    string
+	GeneratedAt time.Time // printed in the PDF footer when set
+}
+
+// ReportColumn is one column of an exported report. Width is a relative weight used to
+// size the column in XLSX and PDF.
+type ReportColumn struct {
+	Title   string
+	Numeric bool
+	Width   int
+}
+
+// ReportRow is one row of an exported report. Numeric cells hold fixed-point amounts;
+// an empty cell is left blank. Bold marks section headings and totals.
+type ReportRow struct {
+	Cells []string
+	Bold  bool
+}
+
+// ReportTable is the format-independent form of a report. Name is the file name stem.
+type ReportTable struct {
+	ReportHeader
+	Name    string
+	Columns []ReportColumn
+	Rows    []ReportRow
+}
+
+// ── Report tables ─────────────────────────────────────────────────────────────
+
+// TrialBalanceTable lays out a trial balance with debit and credit columns and totals.
+func TrialBalanceTable(h ReportHeader, accounts []AccountBalance) ReportTable {
+	h.Title = "Trial Balance"
+	t := ReportTable{
+		ReportHeader: h,
+		Name:         "trial-balance-" + h.CompanyCode,
+		Columns: []ReportColumn{
+			{Title: "Code", Width: 10}, {Title: "Account", Width: 40},
+			{Title: "Debit", Numeric: true, Width: 16}, {Title: "Credit", Numeric: true, Width: 16},
+		},
+	}
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, a := range accounts {
+		var dr, cr string
+		switch {
+		case a.Balance.IsPositive():
+			dr = a.Balance.StringFixed(2)
+			debits = debits.Add(a.Balance)
+		case a.Balance.IsNegative():
+			cr = a.Balance.Neg().StringFixed(2)
+			credits = credits.Add(a.Balance.Neg())
+		}
+		t.Rows = append(t.Rows, ReportRow{Cells: []string{a.Code, a.Name, dr, cr}})
+	}
+	t.Rows = append(t.Rows, ReportRow{Cells: []string{"", "Total", debits.StringFixed(2), credits.StringFixed(2)}, Bold: true})
+	return t
+}
+
+// PLTable lays out a P&L with revenue and expense sections and net income.
+func PLTable(h ReportHeader, r *PLReport) ReportTable {
+	h.Title = "Profit and Loss"
+	h.Period = fmt.Sprintf("%s %d", time.Month(r.Month), r.Year)
+	t := ReportTable{
+		ReportHeader: h,
+		Name:         fmt.Sprintf("pl-%s-%04d-%02d", h.CompanyCode, r.Year, r.Month),
+		Columns:      accountLineColumns(),
+	}
+	t.addSection("Revenue", r.Revenue)
+	t.addSection("Expenses", r.Expenses)
+	t.Rows = append(t.Rows, ReportRow{Cells: []string{"", "Net Income / (Loss)", r.NetIncome.StringFixed(2)}, Bold: true})
+	return t
+}
+
+// BSTable lays out a balance sheet with asset, liability and equity sections.
+func BSTable(h ReportHeader, r *BSReport) ReportTable {
+	h.Title = "Balance Sheet"
+	h.Period = "As of " + r.AsOfDate
+	t := ReportTable{
+		ReportHeader: h,
+		Name:         fmt.Sprintf("balance-sheet-%s-%s", h.CompanyCode, r.AsOfDate),
+		Columns:      accountLineColumns(),
+	}
+	t.addSection("Assets", r.Assets)
+	t.addSection("Liabilities", r.Liabilities)
+	t.addSection("Equity", r.Equity)
+	t.Rows = append(t.Rows, ReportRow{
+		Cells: []string{"", "Total Liabilities + Equity", r.TotalLiabilities.Add(r.TotalEquity).StringFixed(2)},
+		Bold:  true,
+	})
+	return t
+}
+
+// StatementTable lays out an account statement with its running balance.
+func StatementTable(h ReportHeader, accountCode, fromDate, toDate string, lines []StatementLine) ReportTable {
+	h.Title = "Account Statement — " + accountCode
+	switch {
+	case fromDate != "" && toDate != "":
+		h.Period = fromDate + " to " + toDate
+	case fromDate != "":
+		h.Period = "From " + fromDate
+	case toDate != "":
+		h.Period = "To " + toDate
+	default:
+		h.Period = "All dates"
+	}
+	t := ReportTable{
+		ReportHeader: h,
+		Name:         fmt.Sprintf("statement-%s-%s", h.CompanyCode, accountCode),
+		Columns: []ReportColumn{
+			{Title: "Date", Width: 11}, {Title: "Narration", Width: 34}, {Title: "Reference", Width: 14},
+			{Title: "Debit", Numeric: true, Width: 14}, {Title: "Credit", Numeric: true, Width: 14},
+			{Title: "Balance", Numeric: true, Width: 15},
+		},
+	}
+	for _, l := range lines {
+		t.Rows = append(t.Rows, ReportRow{Cells: []string{
+			l.PostingDate, l.Narration, l.Reference,
+			nonZeroFixed(l.Debit), nonZeroFixed(l.Credit), l.RunningBalance.StringFixed(2),
+		}})
+	}
+	return t
+}
+
+func accountLineColumns() []ReportColumn {
+	return []ReportColumn{{Title: "Code", Width: 10}, {Title: "Account", Width: 46}, {Title: "Amount", Numeric: true, Width: 18}}
+}
+
+// addSection appends a heading, one row per line and a total row.
+func (t *ReportTable) addSection(title string, lines []AccountLine) {
+	t.Rows = append(t.Rows, ReportRow{Cells: []string{"", title, ""}, Bold: true})
+	total := decimal.Zero
+	for _, l := range lines {
+		t.Rows = append(t.Rows, ReportRow{Cells: []string{l.Code, l.Name, l.Balance.StringFixed(2)}})
+		total = total.Add(l.Balance)
+	}
+	t.Rows = append(t.Rows, ReportRow{Cells: []string{"", "Total " + title, total.StringFixed(2)}, Bold: true})
+}
+
+func nonZeroFixed(d decimal.Decimal) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.StringFixed(2)
+}
+
+// ── Rendering ─────────────────────────────────────────────────────────────────
+
+// ExportReport renders t in format: ReportFormatCSV, ReportFormatXLSX, ReportFormatPDF or
+// ReportFormatText.
+func ExportReport(t ReportTable, format string) (*ReportFile, error) {
+	var (
+		content     []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case ReportFormatCSV:
+		content, err = ReportCSV(t)
+		contentType = "text/csv"
+	case ReportFormatXLSX:
+		content, err = ReportXLSX(t)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportFormatPDF:
+		content, err = ReportPDF(t)
+		contentType = "application/pdf"
+	case ReportFormatText:
+		content = ReportText(t)
+		contentType = "text/plain; charset=utf-8"
+	default:
+		return nil, fmt.Errorf("unknown report format %q: use csv, xlsx, pdf or text", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ext := format
+	if format == ReportFormatText {
+		ext = "txt"
+	}
+	return &ReportFile{Filename: t.Name + "." + ext, ContentType: contentType, Content: content}, nil
+}
+
+// ReportCSV renders the column titles and rows of t. Text cells that a spreadsheet would
+// read as a formula are prefixed with a single quote.
+func ReportCSV(t ReportTable) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	titles := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		titles[i] = c.Title
+	}
+	if err := w.Write(titles); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, row := range t.Rows {
+		rec := make([]string, len(row.Cells))
+		for i, cell := range row.Cells {
+			if i < len(t.Columns) && t.Columns[i].Numeric {
+				rec[i] = cell
+			} else {
+				rec[i] = csvSafeCell(cell)
+			}
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func csvSafeCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ReportText renders t as fixed-width text for a terminal.
+func ReportText(t ReportTable) []byte {
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = len([]rune(c.Title))
+	}
+	for _, row := range t.Rows {
+		for i, cell := range row.Cells {
+			if i < len(widths) && len([]rune(cell)) > widths[i] {
+				widths[i] = len([]rune(cell))
+			}
+		}
+	}
+	total := 0
+	for _, w := range widths {
+		total += w + 2
+	}
+
+	var b strings.Builder
+	line := func(cells []string) {
+		for i, w := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			pad := strings.Repeat(" ", w-len([]rune(cell)))
+			if t.Columns[i].Numeric {
+				b.WriteString("  " + pad + cell)
+			} else {
+				b.WriteString("  " + cell + pad)
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%s\n  %s\n", strings.Repeat("=", total), strings.ToUpper(t.Title))
+	fmt.Fprintf(&b, "  Company  : %s — %s\n", t.CompanyCode, t.CompanyName)
+	if t.Period != "" {
+		fmt.Fprintf(&b, "  Period   : %s\n", t.Period)
+	}
+	if t.Currency != "" {
+		fmt.Fprintf(&b, "  Currency : %s\n", t.Currency)
+	}
+	b.WriteString(strings.Repeat("=", total) + "\n")
+	titles := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		titles[i] = strings.ToUpper(c.Title)
+	}
+	line(titles)
+	b.WriteString(strings.Repeat("-", total) + "\n")
+	for _, row := range t.Rows {
+		line(row.Cells)
+	}
+	b.WriteString(strings.Repeat("=", total) + "\n")
+	return []byte(b.String())
+}
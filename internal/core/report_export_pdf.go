@@ -0,0 +1,227 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ── PDF ───────────────────────────────────────────────────────────────────────
+
+// A4 portrait in points, with the layout of every page.
+const (
+	pdfPageWidth   = 595.0
+	pdfPageHeight  = 842.0
+	pdfMargin      = 40.0
+	pdfFontSize    = 9.0
+	pdfRowHeight   = 13.0
+	pdfHeaderSpace = 78.0 // company, title and period lines plus column titles
+	pdfFooterSpace = 30.0
+
+	// pdfRowsPerPage is the number of table rows that fit between the header and the
+	// footer: (842 − 2×40 − 78 − 30) / 13, rounded down.
+	pdfRowsPerPage = 50
+)
+
+// helveticaWidths are the Helvetica glyph widths (1/1000 em) for ASCII 32–126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// ReportPDF renders t as an A4 PDF using the standard Helvetica fonts. Every page repeats
+// the company, title, period and column titles and is numbered "Page n of m".
+func ReportPDF(t ReportTable) ([]byte, error) {
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("report %q has no columns", t.Title)
+	}
+	pages := [][]ReportRow{nil}
+	for _, row := range t.Rows {
+		if len(pages[len(pages)-1]) == pdfRowsPerPage {
+			pages = append(pages, nil)
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], row)
+	}
+
+	// Column x positions from the relative widths.
+	weights := 0
+	for _, c := range t.Columns {
+		weights += max(c.Width, 1)
+	}
+	usable := pdfPageWidth - 2*pdfMargin
+	xs := make([]float64, len(t.Columns)+1)
+	xs[0] = pdfMargin
+	for i, c := range t.Columns {
+		xs[i+1] = xs[i] + usable*float64(max(c.Width, 1))/float64(weights)
+	}
+
+	var streams []string
+	for n, rows := range pages {
+		var s strings.Builder
+		y := pdfPageHeight - pdfMargin
+		pdfText(&s, true, 12, pdfMargin, y, t.CompanyName+" ("+t.CompanyCode+")")
+		y -= 16
+		pdfText(&s, true, 11, pdfMargin, y, t.Title)
+		y -= 14
+		sub := t.Period
+		if t.Currency != "" {
+			if sub != "" {
+				sub += " · "
+			}
+			sub += "Currency: " + t.Currency
+		}
+		pdfText(&s, false, pdfFontSize, pdfMargin, y, sub)
+		y -= 22
+		for i, c := range t.Columns {
+			pdfCell(&s, true, xs[i], xs[i+1], y, c.Title, c.Numeric)
+		}
+		y -= 5
+		fmt.Fprintf(&s, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+		y -= pdfRowHeight
+		for _, row := range rows {
+			for i, cell := range row.Cells {
+				if i < len(t.Columns) {
+					pdfCell(&s, row.Bold, xs[i], xs[i+1], y, cell, t.Columns[i].Numeric)
+				}
+			}
+			y -= pdfRowHeight
+		}
+
+		footerY := pdfMargin - 10
+		fmt.Fprintf(&s, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, footerY+12, pdfPageWidth-pdfMargin, footerY+12)
+		if !t.GeneratedAt.IsZero() {
+			pdfText(&s, false, 8, pdfMargin, footerY, "Generated "+t.GeneratedAt.Format("2006-01-02 15:04"))
+		}
+		pageLabel := fmt.Sprintf("Page %d of %d", n+1, len(pages))
+		pdfText(&s, false, 8, pdfPageWidth-pdfMargin-pdfTextWidth(pageLabel, false, 8), footerY, pageLabel)
+		streams = append(streams, s.String())
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3–4 fonts, then a page and its content per page.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, filled in below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	var kids []string
+	for _, stream := range streams {
+		pageObj := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageObj+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// pdfCell writes text into the column [x0, x1), right-aligned when numeric, truncated with
+// an ellipsis when it does not fit.
+func pdfCell(s *strings.Builder, bold bool, x0, x1, y float64, text string, numeric bool) {
+	const padding = 4.0
+	avail := x1 - x0 - padding
+	if pdfTextWidth(text, bold, pdfFontSize) > avail {
+		r := []rune(text)
+		for len(r) > 0 && pdfTextWidth(string(r)+"...", bold, pdfFontSize) > avail {
+			r = r[:len(r)-1]
+		}
+		text = string(r) + "..."
+	}
+	x := x0
+	if numeric {
+		x = x1 - padding - pdfTextWidth(text, bold, pdfFontSize)
+	}
+	pdfText(s, bold, pdfFontSize, x, y, text)
+}
+
+// pdfText writes one line of text at (x, y) in Helvetica (F1) or Helvetica-Bold (F2).
+func pdfText(s *strings.Builder, bold bool, size, x, y float64, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(s, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// pdfTextWidth approximates the width of text in points. Bold glyphs are taken as 5%
+// wider than regular ones; digits and punctuation have the same width in both.
+func pdfTextWidth(text string, bold bool, size float64) float64 {
+	units := 0
+	for _, b := range pdfWinAnsi(text) {
+		if b >= 32 && b <= 126 {
+			units += helveticaWidths[b-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * size / 1000
+	if bold {
+		w *= 1.05
+	}
+	return w
+}
+
+// pdfEscape encodes text as WinAnsi and escapes the PDF string delimiters.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, c := range pdfWinAnsi(text) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfWinAnsi maps text to WinAnsiEncoding bytes: Latin-1 passes through, common
+// punctuation is mapped, anything else becomes '?'.
+func pdfWinAnsi(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			out = append(out, byte(r))
+		case r == '—':
+			out = append(out, 0x97)
+		case r == '–':
+			out = append(out, 0x96)
+		case r == '€':
+			out = append(out, 0x80)
+		case r == '‘' || r == '’':
+			out = append(out, 0x27)
+		case r == '“' || r == '”':
+			out = append(out, '"')
+		case r == '•':
+			out = append(out, 0x95)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
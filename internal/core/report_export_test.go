@@ -0,0 +1,163 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func testHeader() core.ReportHeader {
+	return core.ReportHeader{CompanyCode: "1000", CompanyName: "Acme (India) Pvt Ltd", Currency: "INR"}
+}
+
+func TestTrialBalanceTable(t *testing.T) {
+	d := decimal.RequireFromString
+	table := core.TrialBalanceTable(testHeader(), []core.AccountBalance{
+		{Code: "1000", Name: "Cash", Balance: d("1500")},
+		{Code: "3000", Name: "Capital", Balance: d("-1000")},
+		{Code: "4000", Name: "Sales", Balance: d("-500")},
+		{Code: "5000", Name: "Unused", Balance: decimal.Zero},
+	})
+	if table.Name != "trial-balance-1000" || table.Title != "Trial Balance" {
+		t.Errorf("header: got %q / %q", table.Name, table.Title)
+	}
+	total := table.Rows[len(table.Rows)-1]
+	if !total.Bold || total.Cells[2] != "1500.00" || total.Cells[3] != "1500.00" {
+		t.Errorf("total row: got %+v", total)
+	}
+	if table.Rows[1].Cells[2] != "" || table.Rows[1].Cells[3] != "1000.00" {
+		t.Errorf("credit balance row: got %+v", table.Rows[1])
+	}
+}
+
+func TestExportReport_CSV(t *testing.T) {
+	table := core.StatementTable(testHeader(), "1000", "2026-01-01", "", []core.StatementLine{
+		{PostingDate: "2026-01-05", Narration: "=HYPERLINK(\"x\")", Reference: "JE-1",
+			Debit: decimal.NewFromInt(100), RunningBalance: decimal.NewFromInt(100)},
+		{PostingDate: "2026-01-06", Narration: "Refund", Credit: decimal.NewFromInt(250),
+			RunningBalance: decimal.NewFromInt(-150)},
+	})
+	file, err := core.ExportReport(table, core.ReportFormatCSV)
+	if err != nil {
+		t.Fatalf("ExportReport: %v", err)
+	}
+	if file.Filename != "statement-1000-1000.csv" || file.ContentType != "text/csv" {
+		t.Errorf("file: got %q %q", file.Filename, file.ContentType)
+	}
+	recs, err := csv.NewReader(bytes.NewReader(file.Content)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(recs) != 3 || recs[0][0] != "Date" {
+		t.Fatalf("records: got %v", recs)
+	}
+	if recs[1][1] != `'=HYPERLINK("x")` {
+		t.Errorf("formula not neutralised: %q", recs[1][1])
+	}
+	if recs[2][3] != "" || recs[2][4] != "250.00" || recs[2][5] != "-150.00" {
+		t.Errorf("numeric cells must stay as-is: %v", recs[2])
+	}
+	if table.Period != "From 2026-01-01" {
+		t.Errorf("period: got %q", table.Period)
+	}
+}
+
+func TestExportReport_XLSX(t *testing.T) {
+	table := core.PLTable(testHeader(), &core.PLReport{
+		CompanyCode: "1000", Year: 2026, Month: 3,
+		Revenue:   []core.AccountLine{{Code: "4000", Name: "Sales & <Services>", Balance: decimal.RequireFromString("1200.50")}},
+		Expenses:  []core.AccountLine{{Code: "5100", Name: "Rent", Balance: decimal.NewFromInt(200)}},
+		NetIncome: decimal.RequireFromString("1000.50"),
+	})
+	file, err := core.ExportReport(table, core.ReportFormatXLSX)
+	if err != nil {
+		t.Fatalf("ExportReport: %v", err)
+	}
+	if file.Filename != "pl-1000-2026-03.xlsx" {
+		t.Errorf("filename: got %q", file.Filename)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(file.Content), int64(len(file.Content)))
+	if err != nil {
+		t.Fatalf("open XLSX: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		"Acme (India) Pvt Ltd (1000)", "March 2026", "Sales &amp; &lt;Services&gt;",
+		`<c r="C8" s="2"><v>1200.5</v></c>`, // amounts are numeric cells
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet missing %q", want)
+		}
+	}
+}
+
+func TestExportReport_PDF(t *testing.T) {
+	var lines []core.AccountLine
+	for i := 0; i < 120; i++ {
+		lines = append(lines, core.AccountLine{Code: "1000", Name: "Bank (current) — branch", Balance: decimal.NewFromInt(int64(i))})
+	}
+	h := testHeader()
+	h.GeneratedAt = time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	table := core.BSTable(h, &core.BSReport{CompanyCode: "1000", AsOfDate: "2026-03-31", Assets: lines})
+	file, err := core.ExportReport(table, core.ReportFormatPDF)
+	if err != nil {
+		t.Fatalf("ExportReport: %v", err)
+	}
+	pdf := string(file.Content)
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("not a PDF file")
+	}
+	// 129 rows at 50 per page.
+	if !strings.Contains(pdf, "/Count 3") || !strings.Contains(pdf, "(Page 3 of 3)") {
+		t.Error("expected three numbered pages")
+	}
+	if strings.Count(pdf, `(Acme \(India\) Pvt Ltd \(1000\))`) != 3 {
+		t.Error("expected the escaped company header on every page")
+	}
+	if !strings.Contains(pdf, "Generated 2026-03-31 18:00") {
+		t.Error("expected the generated timestamp")
+	}
+	// Every object offset in the xref table must point at its object.
+	xref := pdf[strings.LastIndex(pdf, "\nxref\n")+1:]
+	entries := strings.Split(xref, "\n")[3:]
+	for i, e := range entries[:8] {
+		var off int
+		if _, err := fmt.Sscanf(e, "%d", &off); err != nil {
+			t.Fatalf("xref entry %q: %v", e, err)
+		}
+		if !strings.HasPrefix(pdf[off:], strconv.Itoa(i+1)+" 0 obj") {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[off:off+10])
+		}
+	}
+}
+
+func TestExportReport_UnknownFormat(t *testing.T) {
+	if _, err := core.ExportReport(core.TrialBalanceTable(testHeader(), nil), "docx"); err == nil {
+		t.Error("expected error for an unknown format")
+	}
+}
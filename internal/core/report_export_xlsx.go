@@ -0,0 +1,179 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ── XLSX (Office Open XML spreadsheet) ───────────────────────────────────────
+
+// Cell style indexes into xlsxStyles' cellXfs.
+const (
+	xlsxStyleText = iota
+	xlsxStyleBold
+	xlsxStyleAmount
+	xlsxStyleBoldAmount
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// xlsxStyles defines a regular and a bold font and the amount format #,##0.00 (built-in 4).
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="4" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>`
+
+// ReportXLSX renders t as a single-sheet workbook: the company, title, period and currency
+// in the first rows, then the column titles and the rows. Amounts are numeric cells.
+func ReportXLSX(t ReportTable) ([]byte, error) {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sheet.WriteString(`<cols>`)
+	for i, c := range t.Columns {
+		fmt.Fprintf(&sheet, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, max(c.Width, 8))
+	}
+	sheet.WriteString(`</cols><sheetData>`)
+
+	rowNum := 0
+	writeRow := func(cells []string, bold bool, numeric func(int) bool) {
+		rowNum++
+		fmt.Fprintf(&sheet, `<row r="%d">`, rowNum)
+		for i, cell := range cells {
+			if cell == "" {
+				continue
+			}
+			ref := xlsxColumn(i) + strconv.Itoa(rowNum)
+			if numeric(i) {
+				if d, err := decimal.NewFromString(cell); err == nil {
+					style := xlsxStyleAmount
+					if bold {
+						style = xlsxStyleBoldAmount
+					}
+					fmt.Fprintf(&sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, d.String())
+					continue
+				}
+			}
+			style := xlsxStyleText
+			if bold {
+				style = xlsxStyleBold
+			}
+			fmt.Fprintf(&sheet, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlEscape(cell))
+		}
+		sheet.WriteString(`</row>`)
+	}
+	text := func(int) bool { return false }
+	isNumeric := func(i int) bool { return i < len(t.Columns) && t.Columns[i].Numeric }
+
+	writeRow([]string{t.CompanyName + " (" + t.CompanyCode + ")"}, true, text)
+	writeRow([]string{t.Title}, true, text)
+	if t.Period != "" {
+		writeRow([]string{t.Period}, false, text)
+	}
+	if t.Currency != "" {
+		writeRow([]string{"Currency: " + t.Currency}, false, text)
+	}
+	rowNum++ // blank row
+	titles := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		titles[i] = c.Title
+	}
+	writeRow(titles, true, text)
+	for _, row := range t.Rows {
+		writeRow(row.Cells, row.Bold, isNumeric)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="` + xmlEscape(xlsxSheetName(t.Title)) + `" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write XLSX part %s: %w", part.name, err)
+		}
+		if _, err := f.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to write XLSX part %s: %w", part.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// xlsxColumn converts a 0-based column index to its letters: 0 → A, 26 → AA.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSheetName strips the characters Excel forbids in sheet names and truncates to 31.
+func xlsxSheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, title)
+	if r := []rune(name); len(r) > 31 {
+		name = string(r[:31])
+	}
+	if strings.TrimSpace(name) == "" {
+		return "Report"
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
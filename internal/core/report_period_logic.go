@@ -0,0 +1,166 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NewReportPeriod returns the period [from, to] labelled as a calendar year ("2026"),
+// quarter ("Q1 2026"), month ("Jan 2026") or, otherwise, its date range.
+func NewReportPeriod(from, to time.Time) ReportPeriod {
+	p := ReportPeriod{FromDate: from.Format("2006-01-02"), ToDate: to.Format("2006-01-02")}
+	months := 0
+	if from.Day() == 1 && isMonthEnd(to) {
+		months = (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+	switch {
+	case months == 12 && from.Month() == time.January:
+		p.Label = strconv.Itoa(from.Year())
+	case months == 3 && (from.Month()-1)%3 == 0:
+		p.Label = fmt.Sprintf("Q%d %d", (from.Month()-1)/3+1, from.Year())
+	case months == 1:
+		p.Label = from.Format("Jan 2006")
+	default:
+		p.Label = p.FromDate + " to " + p.ToDate
+	}
+	return p
+}
+
+// ParseReportPeriod parses a report period: "2026" (calendar year), "2026-Q1", "2026-01"
+// (month) or "2026-01-05..2026-02-04" (date range).
+func ParseReportPeriod(s string) (ReportPeriod, error) {
+	s = strings.TrimSpace(s)
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		f, err1 := time.Parse("2006-01-02", strings.TrimSpace(from))
+		t, err2 := time.Parse("2006-01-02", strings.TrimSpace(to))
+		if err1 != nil || err2 != nil {
+			return ReportPeriod{}, fmt.Errorf("invalid period %q: expected YYYY-MM-DD..YYYY-MM-DD", s)
+		}
+		if f.After(t) {
+			return ReportPeriod{}, fmt.Errorf("invalid period %q: start is after end", s)
+		}
+		return NewReportPeriod(f, t), nil
+	}
+	if year, quarter, ok := strings.Cut(strings.ToUpper(s), "-Q"); ok {
+		y, err1 := strconv.Atoi(year)
+		q, err2 := strconv.Atoi(quarter)
+		if err1 != nil || err2 != nil || q < 1 || q > 4 {
+			return ReportPeriod{}, fmt.Errorf("invalid period %q: expected YYYY-Qn", s)
+		}
+		from := time.Date(y, time.Month(3*q-2), 1, 0, 0, 0, 0, time.UTC)
+		return NewReportPeriod(from, from.AddDate(0, 3, -1)), nil
+	}
+	if m, err := time.Parse("2006-01", s); err == nil {
+		return NewReportPeriod(m, m.AddDate(0, 1, -1)), nil
+	}
+	if y, err := time.Parse("2006", s); err == nil {
+		return NewReportPeriod(y, y.AddDate(1, 0, -1)), nil
+	}
+	return ReportPeriod{}, fmt.Errorf("invalid period %q: expected YYYY, YYYY-Qn, YYYY-MM or YYYY-MM-DD..YYYY-MM-DD", s)
+}
+
+// ComparativePeriods returns the columns of a comparative report: the period itself, the
+// prior period of the same length ending the day before it starts, and the same period
+// last year. Whole-month periods compare with whole months.
+func ComparativePeriods(p ReportPeriod) ([]ReportPeriod, error) {
+	from, err := time.Parse("2006-01-02", p.FromDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period start %q", p.FromDate)
+	}
+	to, err := time.Parse("2006-01-02", p.ToDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period end %q", p.ToDate)
+	}
+
+	var prior ReportPeriod
+	if from.Day() == 1 && isMonthEnd(to) {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+		prior = NewReportPeriod(from.AddDate(0, -months, 0), from.AddDate(0, 0, -1))
+	} else {
+		days := int(to.Sub(from).Hours()/24) + 1
+		prior = NewReportPeriod(from.AddDate(0, 0, -days), from.AddDate(0, 0, -1))
+	}
+
+	lastYearTo := to.AddDate(-1, 0, 0)
+	if isMonthEnd(to) {
+		lastYearTo = time.Date(to.Year()-1, to.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	}
+	lastYear := NewReportPeriod(from.AddDate(-1, 0, 0), lastYearTo)
+	return []ReportPeriod{p, prior, lastYear}, nil
+}
+
+// TrendPeriods returns the n calendar months ending with month, oldest first.
+func TrendPeriods(month time.Time, n int) []ReportPeriod {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periods := make([]ReportPeriod, n)
+	for i := 0; i < n; i++ {
+		m := first.AddDate(0, i-n+1, 0)
+		periods[i] = NewReportPeriod(m, m.AddDate(0, 1, -1))
+	}
+	return periods
+}
+
+func isMonthEnd(d time.Time) bool {
+	return d.AddDate(0, 0, 1).Day() == 1
+}
+
+// splitByMonth splits [from, to] into the whole calendar months it covers, [monthsFrom,
+// monthsTo] as first days of months, and the part months at either end. A zero from means
+// unbounded. hasMonths is false when no whole month is covered.
+func splitByMonth(from, to time.Time) (monthsFrom, monthsTo time.Time, hasMonths bool, parts [][2]time.Time) {
+	first := from
+	if !from.IsZero() && from.Day() != 1 {
+		first = time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	last := to // last day of the last whole month
+	if !isMonthEnd(to) {
+		last = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	}
+	if !first.IsZero() && first.After(last) {
+		return time.Time{}, time.Time{}, false, [][2]time.Time{{from, to}}
+	}
+	if !from.IsZero() && from.Before(first) {
+		parts = append(parts, [2]time.Time{from, first.AddDate(0, 0, -1)})
+	}
+	if last.Before(to) {
+		parts = append(parts, [2]time.Time{last.AddDate(0, 0, 1), to})
+	}
+	return first, time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC), true, parts
+}
+
+// newColumnarLine builds a report line. With comparative set it computes each column's
+// variance from the first: Amounts[0] − Amounts[i] and, unless Amounts[i] is zero, that
+// difference as a percentage of |Amounts[i]|.
+func newColumnarLine(code, name string, amounts []decimal.Decimal, comparative bool) ColumnarLine {
+	line := ColumnarLine{Code: code, Name: name, Amounts: amounts}
+	if !comparative || len(amounts) < 2 {
+		return line
+	}
+	hundred := decimal.NewFromInt(100)
+	for _, base := range amounts[1:] {
+		variance := amounts[0].Sub(base)
+		line.Variances = append(line.Variances, variance)
+		if base.IsZero() {
+			line.VariancePcts = append(line.VariancePcts, nil)
+			continue
+		}
+		pct := variance.Div(base.Abs()).Mul(hundred).Round(2)
+		line.VariancePcts = append(line.VariancePcts, &pct)
+	}
+	return line
+}
+
+// sumColumns adds up the lines column by column.
+func sumColumns(lines []ColumnarLine, columns int) []decimal.Decimal {
+	total := make([]decimal.Decimal, columns)
+	for _, l := range lines {
+		for i, a := range l.Amounts {
+			total[i] = total[i].Add(a)
+		}
+	}
+	return total
+}
@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+)
+
+func TestParseReportPeriod(t *testing.T) {
+	cases := []struct {
+		in, label, from, to string
+	}{
+		{"2026", "2026", "2026-01-01", "2026-12-31"},
+		{"2026-q2", "Q2 2026", "2026-04-01", "2026-06-30"},
+		{"2024-02", "Feb 2024", "2024-02-01", "2024-02-29"},
+		{"2026-01-05..2026-02-04", "2026-01-05 to 2026-02-04", "2026-01-05", "2026-02-04"},
+		{"2026-04-01..2026-06-30", "Q2 2026", "2026-04-01", "2026-06-30"},
+	}
+	for _, c := range cases {
+		p, err := core.ParseReportPeriod(c.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.in, err)
+			continue
+		}
+		if p.Label != c.label || p.FromDate != c.from || p.ToDate != c.to {
+			t.Errorf("%s: got %+v, want %s %s..%s", c.in, p, c.label, c.from, c.to)
+		}
+	}
+
+	for _, bad := range []string{"", "2026-Q5", "2026-13", "2026-03-01..2026-02-01", "last month"} {
+		if _, err := core.ParseReportPeriod(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestComparativePeriods(t *testing.T) {
+	cases := []struct {
+		in              string
+		prior, lastYear string
+	}{
+		// Whole months compare with the preceding months of the same count.
+		{"2026-03", "2026-02-01..2026-02-28", "2025-03-01..2025-03-31"},
+		{"2026-Q1", "2025-10-01..2025-12-31", "2025-01-01..2025-03-31"},
+		// Month end stays month end a year earlier.
+		{"2024-02", "2024-01-01..2024-01-31", "2023-02-01..2023-02-28"},
+		// Other ranges compare with the same number of days.
+		{"2026-01-05..2026-01-14", "2025-12-26..2026-01-04", "2025-01-05..2025-01-14"},
+	}
+	for _, c := range cases {
+		p, err := core.ParseReportPeriod(c.in)
+		if err != nil {
+			t.Fatalf("%s: %v", c.in, err)
+		}
+		cols, err := core.ComparativePeriods(p)
+		if err != nil {
+			t.Fatalf("%s: %v", c.in, err)
+		}
+		if len(cols) != 3 || cols[0] != p {
+			t.Fatalf("%s: got %+v", c.in, cols)
+		}
+		if got := cols[1].FromDate + ".." + cols[1].ToDate; got != c.prior {
+			t.Errorf("%s prior: got %s, want %s", c.in, got, c.prior)
+		}
+		if got := cols[2].FromDate + ".." + cols[2].ToDate; got != c.lastYear {
+			t.Errorf("%s last year: got %s, want %s", c.in, got, c.lastYear)
+		}
+	}
+}
+
+func TestTrendPeriods(t *testing.T) {
+	cols := core.TrendPeriods(time.Date(2026, time.March, 17, 0, 0, 0, 0, time.UTC), 12)
+	if len(cols) != 12 {
+		t.Fatalf("got %d columns, want 12", len(cols))
+	}
+	if cols[0].Label != "Apr 2025" || cols[0].FromDate != "2025-04-01" {
+		t.Errorf("first column: got %+v", cols[0])
+	}
+	if cols[11].Label != "Mar 2026" || cols[11].ToDate != "2026-03-31" {
+		t.Errorf("last column: got %+v", cols[11])
+	}
+}
@@ -340,3 +340,342 @@ func TestReporting_GetAccountStatement(t *testing.T) {
 		}
 	})
 }
+
+func TestReporting_InventoryReports(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedInventoryTestData(t, ctx, pool)
+
+	invSvc := core.NewInventoryService(pool, core.NewRuleEngine(pool))
+	reporting := core.NewReportingService(pool)
+
+	// Receipts: 100 @ 200 on 5 Jan, 50 @ 260 on 10 Feb → WAC = 220.
+	if err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001",
+		decimal.NewFromInt(100), decimal.NewFromInt(200),
+		"2026-01-05", "2000", nil, ledger, docSvc); err != nil {
+		t.Fatalf("First ReceiveStock failed: %v", err)
+	}
+	if err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001",
+		decimal.NewFromInt(50), decimal.NewFromInt(260),
+		"2026-02-10", "2000", nil, ledger, docSvc); err != nil {
+		t.Fatalf("Second ReceiveStock failed: %v", err)
+	}
+
+	t.Run("Ageing buckets and slow-moving flag", func(t *testing.T) {
+		// As of 15 Mar: the 10 Feb receipt is 33 days old, the 5 Jan receipt 69 days.
+		report, err := reporting.GetStockAgeing(ctx, "1000", "2026-03-15", 60)
+		if err != nil {
+			t.Fatalf("GetStockAgeing failed: %v", err)
+		}
+		if len(report.Lines) != 1 {
+			t.Fatalf("Expected 1 ageing line, got %d", len(report.Lines))
+		}
+		l := report.Lines[0]
+		if !l.Age31To60.Equal(decimal.NewFromInt(50)) || !l.Age61To90.Equal(decimal.NewFromInt(100)) {
+			t.Errorf("Buckets: want 31-60=50, 61-90=100; got %s, %s", l.Age31To60, l.Age61To90)
+		}
+		if !l.IsSlowMoving {
+			t.Error("Expected P001 to be slow-moving (never issued, 69 days > 60)")
+		}
+
+		report, err = reporting.GetStockAgeing(ctx, "1000", "2026-03-15", 0)
+		if err != nil {
+			t.Fatalf("GetStockAgeing (default threshold) failed: %v", err)
+		}
+		if report.SlowMovingDays != 90 || report.Lines[0].IsSlowMoving {
+			t.Errorf("Default threshold: want 90 days and not slow-moving, got %d / %v",
+				report.SlowMovingDays, report.Lines[0].IsSlowMoving)
+		}
+	})
+
+	// Ship 30 units (movement dated today) at WAC 220 → COGS 6600.
+	order, err := orderSvc.CreateOrder(ctx, "1000", "C001", "INR", decimal.NewFromInt(1), "2026-02-24",
+		[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(30)}}, "")
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, invSvc); err != nil {
+		t.Fatalf("ConfirmOrder failed: %v", err)
+	}
+	if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docSvc); err != nil {
+		t.Fatalf("ShipOrder failed: %v", err)
+	}
+	if err := reporting.RefreshViews(ctx); err != nil {
+		t.Fatalf("RefreshViews failed: %v", err)
+	}
+
+	t.Run("Valuation as of a past date", func(t *testing.T) {
+		report, err := reporting.GetInventoryValuation(ctx, "1000", "2026-01-31")
+		if err != nil {
+			t.Fatalf("GetInventoryValuation failed: %v", err)
+		}
+		if !report.TotalQuantity.Equal(decimal.NewFromInt(100)) || !report.TotalValue.Equal(decimal.NewFromInt(20000)) {
+			t.Errorf("31 Jan valuation: want 100 / 20000, got %s / %s", report.TotalQuantity, report.TotalValue)
+		}
+		// Reconciled at 31 Jan, before the second receipt and the shipment were booked.
+		rec := report.Reconciliation
+		if rec.AsOfDate != "2026-01-31" || !rec.StockValue.Equal(report.TotalValue) ||
+			!rec.LedgerBalance.Equal(decimal.NewFromInt(20000)) || !rec.IsReconciled {
+			t.Errorf("31 Jan reconciliation: want stock and ledger 20000 as of 2026-01-31, got %s: %s / %s",
+				rec.AsOfDate, rec.StockValue, rec.LedgerBalance)
+		}
+	})
+
+	t.Run("Current valuation reconciles to ledger", func(t *testing.T) {
+		report, err := reporting.GetInventoryValuation(ctx, "1000", "")
+		if err != nil {
+			t.Fatalf("GetInventoryValuation failed: %v", err)
+		}
+		// 20000 + 13000 - 6600 = 26400
+		if !report.TotalValue.Equal(decimal.NewFromInt(26400)) {
+			t.Errorf("Current valuation: want 26400, got %s", report.TotalValue)
+		}
+		rec := report.Reconciliation
+		if rec.InventoryAccountCode != "1400" || !rec.IsReconciled {
+			t.Errorf("Reconciliation failed: account %s, stock %s, ledger %s",
+				rec.InventoryAccountCode, rec.StockValue, rec.LedgerBalance)
+		}
+	})
+
+	t.Run("Movement ledger running totals", func(t *testing.T) {
+		report, err := reporting.GetStockMovementLedger(ctx, "1000", "P001", "MAIN", "2026-02-01", "")
+		if err != nil {
+			t.Fatalf("GetStockMovementLedger failed: %v", err)
+		}
+		if !report.OpeningQty.Equal(decimal.NewFromInt(100)) || !report.OpeningValue.Equal(decimal.NewFromInt(20000)) {
+			t.Errorf("Opening: want 100 / 20000, got %s / %s", report.OpeningQty, report.OpeningValue)
+		}
+		// Reservation movements are excluded: receipt + shipment only.
+		if len(report.Lines) != 2 {
+			t.Fatalf("Expected 2 movement lines, got %d", len(report.Lines))
+		}
+		if report.Lines[1].MovementType != "SHIPMENT" || report.Lines[1].Reference == "" {
+			t.Errorf("Expected shipment linked to a sales order, got %s ref %q",
+				report.Lines[1].MovementType, report.Lines[1].Reference)
+		}
+		if !report.ClosingQty.Equal(decimal.NewFromInt(120)) || !report.ClosingValue.Equal(decimal.NewFromInt(26400)) {
+			t.Errorf("Closing: want 120 / 26400, got %s / %s", report.ClosingQty, report.ClosingValue)
+		}
+	})
+}
+
+func TestReporting_CashFlow(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	reporting := core.NewReportingService(pool)
+	ctx := context.Background()
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1300', 'Furniture', 'asset'),
+		(1, '1400', 'Inventory', 'asset')`); err != nil {
+		t.Fatalf("seed accounts: %v", err)
+	}
+	if _, err := reporting.GetCashFlow(ctx, "1000", "2026-01-01", "2026-01-31", ""); err == nil {
+		t.Error("expected error before any cash account is flagged")
+	}
+	if err := reporting.SetCashAccount(ctx, "1000", "4000", true); err == nil {
+		t.Error("expected error flagging a revenue account as cash")
+	}
+	if err := reporting.SetCashAccount(ctx, "1000", "1000", true); err != nil {
+		t.Fatalf("SetCashAccount: %v", err)
+	}
+	for _, m := range []struct{ account, activity, line string }{
+		{"1200", "OPERATING", "Trade receivables"},
+		{"1300", "INVESTING", "Purchase of fixed assets"},
+		{"1400", "OPERATING", "Inventories"},
+		{"2000", "OPERATING", "Trade payables"},
+		{"5000", "NON_CASH", "Cost of goods sold"},
+	} {
+		if err := reporting.SetCashFlowMapping(ctx, "1000", m.account, m.activity, m.line); err != nil {
+			t.Fatalf("SetCashFlowMapping %s: %v", m.account, err)
+		}
+	}
+	if err := reporting.SetCashFlowMapping(ctx, "1000", "1000", "OPERATING", ""); err == nil {
+		t.Error("expected error mapping a cash account")
+	}
+
+	entry := func(date string, lines ...core.ProposalLine) {
+		t.Helper()
+		if err := ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: date, DocumentDate: date, Summary: "cash flow test", Reasoning: "test",
+			Lines: lines,
+		}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	dr := func(account, amount string) core.ProposalLine {
+		return core.ProposalLine{AccountCode: account, IsDebit: true, Amount: amount}
+	}
+	cr := func(account, amount string) core.ProposalLine {
+		return core.ProposalLine{AccountCode: account, IsDebit: false, Amount: amount}
+	}
+	entry("2025-12-31", dr("1000", "2000.00"), cr("3000", "2000.00")) // opening cash
+	entry("2026-01-02", dr("1000", "10000.00"), cr("3000", "10000.00"))
+	entry("2026-01-05", dr("1400", "4000.00"), cr("2000", "4000.00"))
+	entry("2026-01-10", dr("1200", "5000.00"), cr("4000", "5000.00"))
+	entry("2026-01-10", dr("5000", "2500.00"), cr("1400", "2500.00"))
+	entry("2026-01-20", dr("1000", "3000.00"), cr("1200", "3000.00"))
+	entry("2026-01-25", dr("2000", "1000.00"), cr("1000", "1000.00"))
+	entry("2026-01-26", dr("1300", "1500.00"), cr("1000", "1500.00"))
+	entry("2026-01-31", dr("5100", "500.00"), cr("1000", "500.00"))
+
+	for _, method := range []string{"INDIRECT", "DIRECT"} {
+		t.Run(method, func(t *testing.T) {
+			r, err := reporting.GetCashFlow(ctx, "1000", "2026-01-01", "2026-01-31", method)
+			if err != nil {
+				t.Fatalf("GetCashFlow: %v", err)
+			}
+			for name, c := range map[string]struct {
+				got  decimal.Decimal
+				want string
+			}{
+				"Operating":   {r.Operating.Total, "1500.00"},
+				"Investing":   {r.Investing.Total, "-1500.00"},
+				"Financing":   {r.Financing.Total, "10000.00"},
+				"OpeningCash": {r.OpeningCash, "2000.00"},
+				"ClosingCash": {r.ClosingCash, "12000.00"},
+			} {
+				if c.got.StringFixed(2) != c.want {
+					t.Errorf("%s: got %s, want %s", name, c.got.StringFixed(2), c.want)
+				}
+			}
+			if !r.IsReconciled {
+				t.Errorf("expected opening + net change = closing: %s + %s != %s", r.OpeningCash, r.NetChange, r.ClosingCash)
+			}
+			if method == "INDIRECT" {
+				if r.NetIncome.StringFixed(2) != "2000.00" {
+					t.Errorf("NetIncome: got %s, want 2000.00", r.NetIncome.StringFixed(2))
+				}
+				if len(r.Adjustments) != 1 || r.Adjustments[0].Amount.StringFixed(2) != "2500.00" {
+					t.Errorf("expected COGS 2500 added back, got %+v", r.Adjustments)
+				}
+			}
+		})
+	}
+
+	if _, err := reporting.GetCashFlow(ctx, "1000", "2026-02-01", "2026-01-01", ""); err == nil {
+		t.Error("expected error for from date after to date")
+	}
+}
+
+func TestReporting_Columnar(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	docService := core.NewDocumentService(pool)
+	ledger := core.NewLedger(pool, docService)
+	reporting := core.NewReportingService(pool)
+	ctx := context.Background()
+
+	sale := func(date, amount string) {
+		t.Helper()
+		if err := ledger.Commit(ctx, core.Proposal{
+			DocumentTypeCode: "JE", CompanyCode: "1000",
+			IdempotencyKey: uuid.NewString(), TransactionCurrency: "INR", ExchangeRate: "1.0",
+			PostingDate: date, DocumentDate: date, Summary: "columnar test", Reasoning: "test",
+			Lines: []core.ProposalLine{
+				{AccountCode: "1000", IsDebit: true, Amount: amount},
+				{AccountCode: "4000", IsDebit: false, Amount: amount},
+			},
+		}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+	sale("2025-01-15", "800.00")
+	sale("2025-12-10", "1000.00")
+	sale("2026-01-03", "400.00")
+	sale("2026-01-20", "800.00")
+	if err := reporting.RefreshViews(ctx); err != nil {
+		t.Fatalf("RefreshViews failed: %v", err)
+	}
+
+	revenue := func(lines []core.ColumnarLine) core.ColumnarLine {
+		for _, l := range lines {
+			if l.Code == "4000" {
+				return l
+			}
+		}
+		t.Fatalf("account 4000 missing from %+v", lines)
+		return core.ColumnarLine{}
+	}
+
+	t.Run("comparative month", func(t *testing.T) {
+		p, _ := core.ParseReportPeriod("2026-01")
+		cols, _ := core.ComparativePeriods(p)
+		report, err := reporting.GetColumnarProfitAndLoss(ctx, "1000", cols, true)
+		if err != nil {
+			t.Fatalf("GetColumnarProfitAndLoss failed: %v", err)
+		}
+		line := revenue(report.Revenue.Lines)
+		for i, want := range []string{"1200.00", "1000.00", "800.00"} {
+			if line.Amounts[i].StringFixed(2) != want {
+				t.Errorf("column %d: got %s, want %s", i, line.Amounts[i].StringFixed(2), want)
+			}
+		}
+		if line.Variances[0].StringFixed(2) != "200.00" || line.VariancePcts[0].StringFixed(2) != "20.00" {
+			t.Errorf("variance vs prior: got %s / %s%%", line.Variances[0], line.VariancePcts[0])
+		}
+		if line.VariancePcts[1].StringFixed(2) != "50.00" {
+			t.Errorf("variance vs last year: got %s%%", line.VariancePcts[1])
+		}
+		if report.NetIncome.Amounts[0].StringFixed(2) != "1200.00" {
+			t.Errorf("net income: got %s", report.NetIncome.Amounts[0])
+		}
+		// Expense lines have no base amount, so no variance percentage.
+		if len(report.Expenses.Lines) == 0 || report.Expenses.Lines[0].VariancePcts[0] != nil {
+			t.Errorf("expected nil variance %% on a zero base: %+v", report.Expenses.Lines)
+		}
+	})
+
+	t.Run("part months are read from journal lines", func(t *testing.T) {
+		sale("2026-02-05", "300.00") // not yet in the view
+		p, _ := core.ParseReportPeriod("2025-12-15..2026-02-10")
+		report, err := reporting.GetColumnarProfitAndLoss(ctx, "1000", []core.ReportPeriod{p}, false)
+		if err != nil {
+			t.Fatalf("GetColumnarProfitAndLoss failed: %v", err)
+		}
+		line := revenue(report.Revenue.Lines)
+		if line.Amounts[0].StringFixed(2) != "1500.00" {
+			t.Errorf("got %s, want 1500.00 (Jan from the view, Feb 5 from journal lines)", line.Amounts[0].StringFixed(2))
+		}
+		if line.Variances != nil {
+			t.Errorf("non-comparative report should have no variances: %+v", line.Variances)
+		}
+	})
+
+	t.Run("balance sheet columns", func(t *testing.T) {
+		if err := reporting.RefreshViews(ctx); err != nil {
+			t.Fatalf("RefreshViews failed: %v", err)
+		}
+		var cols []core.ReportPeriod
+		for _, s := range []string{"2025-12", "2026-01-10..2026-01-10", "2026-02"} {
+			p, _ := core.ParseReportPeriod(s)
+			cols = append(cols, p)
+		}
+		report, err := reporting.GetColumnarBalanceSheet(ctx, "1000", cols, true)
+		if err != nil {
+			t.Fatalf("GetColumnarBalanceSheet failed: %v", err)
+		}
+		for i, want := range []string{"1800.00", "2200.00", "3300.00"} {
+			if got := report.Assets.Total.Amounts[i].StringFixed(2); got != want {
+				t.Errorf("assets column %d: got %s, want %s", i, got, want)
+			}
+		}
+		if len(report.IsBalanced) != 3 {
+			t.Errorf("IsBalanced: got %v", report.IsBalanced)
+		}
+	})
+
+	if _, err := reporting.GetColumnarProfitAndLoss(ctx, "1000", nil, false); err == nil {
+		t.Error("expected error for no periods")
+	}
+	if _, err := reporting.GetColumnarProfitAndLoss(ctx, "1000", []core.ReportPeriod{{Label: "bad", FromDate: "2026-02-01", ToDate: "2026-01-01"}}, false); err == nil {
+		t.Error("expected error for a period that starts after it ends")
+	}
+}
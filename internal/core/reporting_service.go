@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -64,9 +65,340 @@ type BSReport struct {
 	IsBalanced       bool
 }
 
+// ReportPeriod is one column of a columnar report: the date range of a P&L column, or the
+// as-of date ToDate of a balance sheet column.
+type ReportPeriod struct {
+	Label    string
+	FromDate string
+	ToDate   string
+}
+
+// ColumnarLine is one row of a columnar report, with an amount per column in the AccountLine
+// sign convention. In a comparative report Variances[i] is Amounts[0] − Amounts[i+1], and
+// VariancePcts[i] that variance as a percentage of |Amounts[i+1]| (nil when it is zero).
+type ColumnarLine struct {
+	Code         string
+	Name         string
+	Amounts      []decimal.Decimal
+	Variances    []decimal.Decimal
+	VariancePcts []*decimal.Decimal
+}
+
+// ColumnarSection is one section of a columnar report with its column totals.
+type ColumnarSection struct {
+	Title string
+	Lines []ColumnarLine
+	Total ColumnarLine
+}
+
+// ColumnarPLReport is the Profit & Loss with one column per period. Comparative is true
+// when the lines carry variances from the first column.
+type ColumnarPLReport struct {
+	CompanyCode string
+	Columns     []ReportPeriod
+	Comparative bool
+	Revenue     ColumnarSection
+	Expenses    ColumnarSection
+	NetIncome   ColumnarLine
+}
+
+// ColumnarBSReport is the Balance Sheet as of the end of each column's period.
+// IsBalanced has one entry per column, with the same meaning as BSReport.IsBalanced.
+type ColumnarBSReport struct {
+	CompanyCode string
+	Columns     []ReportPeriod
+	Comparative bool
+	Assets      ColumnarSection
+	Liabilities ColumnarSection
+	Equity      ColumnarSection
+	IsBalanced  []bool
+}
+
+// InventoryReconciliation compares the perpetual stock value held in
+// inventory_movements against the INVENTORY control account balance, both as of
+// the report's date.
+type InventoryReconciliation struct {
+	InventoryAccountCode string
+	AsOfDate             string          // YYYY-MM-DD
+	StockValue           decimal.Decimal // Σ total_cost of physical movements dated on or before AsOfDate
+	LedgerBalance        decimal.Decimal // INVENTORY account balance from entries posted on or before AsOfDate
+	Difference           decimal.Decimal // StockValue − LedgerBalance
+	IsReconciled         bool
+}
+
+// InventoryValuationLine is the stock position of one product in one warehouse.
+// UnitCost is the effective average cost (Value / Quantity) at the valuation date.
+type InventoryValuationLine struct {
+	ProductCode   string
+	ProductName   string
+	WarehouseCode string
+	WarehouseName string
+	Quantity      decimal.Decimal
+	UnitCost      decimal.Decimal
+	Value         decimal.Decimal
+}
+
+// InventoryValuationReport is the stock valuation as of a given date,
+// rebuilt from the physical movements recorded up to and including that date.
+type InventoryValuationReport struct {
+	CompanyCode    string
+	AsOfDate       string
+	Lines          []InventoryValuationLine
+	TotalQuantity  decimal.Decimal
+	TotalValue     decimal.Decimal
+	Reconciliation InventoryReconciliation
+}
+
+// StockMovementLine is a single physical movement in a product movement ledger.
+// Quantity and Value are signed (positive = stock in, negative = stock out).
+// RunningQty and RunningValue are the cumulative position after this line.
+type StockMovementLine struct {
+	MovementDate  string
+	MovementType  string
+	WarehouseCode string
+	Reference     string // sales order or PO number, when linked
+	Notes         string
+	Quantity      decimal.Decimal
+	UnitCost      decimal.Decimal
+	Value         decimal.Decimal
+	RunningQty    decimal.Decimal
+	RunningValue  decimal.Decimal
+}
+
+// StockMovementLedger is the item-level movement history for one product,
+// optionally restricted to a single warehouse and a date range.
+type StockMovementLedger struct {
+	CompanyCode    string
+	ProductCode    string
+	ProductName    string
+	WarehouseCode  string // empty = all warehouses
+	FromDate       string
+	ToDate         string
+	OpeningQty     decimal.Decimal
+	OpeningValue   decimal.Decimal
+	Lines          []StockMovementLine
+	ClosingQty     decimal.Decimal
+	ClosingValue   decimal.Decimal
+	Reconciliation InventoryReconciliation
+}
+
+// StockAgeingLine is the ageing profile of one product in one warehouse.
+// Remaining stock is assumed to come from the most recent receipts (FIFO),
+// and the quantity is split into age buckets by receipt date.
+// Quantity without a matching receipt (e.g. opening balances) is aged over 90 days.
+type StockAgeingLine struct {
+	ProductCode     string
+	ProductName     string
+	WarehouseCode   string
+	Quantity        decimal.Decimal
+	Value           decimal.Decimal
+	LastReceiptDate string // empty if never received
+	LastIssueDate   string // empty if never shipped
+	DaysSinceIssue  int    // days since last issue, or since first receipt if never shipped
+	Age0To30        decimal.Decimal
+	Age31To60       decimal.Decimal
+	Age61To90       decimal.Decimal
+	AgeOver90       decimal.Decimal
+	IsSlowMoving    bool
+}
+
+// StockAgeingReport lists on-hand stock with its age profile and flags
+// items with no issue within SlowMovingDays of AsOfDate.
+type StockAgeingReport struct {
+	CompanyCode     string
+	AsOfDate        string
+	SlowMovingDays  int
+	Lines           []StockAgeingLine
+	TotalValue      decimal.Decimal
+	SlowMovingValue decimal.Decimal
+	Reconciliation  InventoryReconciliation
+}
+
+// LotTraceEntry is one receipt or shipment of a lot, with its counterparty.
+// For receipts the counterparty is the vendor and Reference the PO number;
+// for shipments it is the customer and the sales order number.
+type LotTraceEntry struct {
+	MovementDate  string
+	MovementType  string
+	WarehouseCode string
+	LotNumber     string
+	Reference     string
+	PartyCode     string
+	PartyName     string
+	Quantity      decimal.Decimal // absolute quantity moved
+}
+
+// LotTrace is the forward trace of a lot: where it came from and which customers received it.
+// A lot number may exist in more than one warehouse; all of them are included.
+type LotTrace struct {
+	CompanyCode   string
+	ProductCode   string
+	ProductName   string
+	LotNumber     string
+	ExpiryDate    *string
+	Receipts      []LotTraceEntry
+	Shipments     []LotTraceEntry
+	TotalReceived decimal.Decimal
+	TotalShipped  decimal.Decimal
+	QtyOnHand     decimal.Decimal
+}
+
+// OrderLotTraceLine is one lot shipped on a sales order, with the receipts that supplied it.
+type OrderLotTraceLine struct {
+	ProductCode string
+	ProductName string
+	LotNumber   string
+	ExpiryDate  *string
+	ShippedDate string
+	QtyShipped  decimal.Decimal
+	Sources     []LotTraceEntry
+}
+
+// OrderLotTrace is the backward trace of a sales order: lots shipped and their supplier POs.
+// Lines for products that are not lot-tracked are omitted.
+type OrderLotTrace struct {
+	CompanyCode  string
+	OrderID      int
+	OrderNumber  string
+	CustomerCode string
+	CustomerName string
+	Lines        []OrderLotTraceLine
+}
+
+// Cash flow activities and methods. NON_CASH marks adjustments to net income in the
+// indirect method.
+const (
+	CashFlowOperating = "OPERATING"
+	CashFlowNonCash   = "NON_CASH"
+	CashFlowInvesting = "INVESTING"
+	CashFlowFinancing = "FINANCING"
+
+	CashFlowMethodIndirect = "INDIRECT"
+	CashFlowMethodDirect   = "DIRECT"
+)
+
+// CashFlowLine is one line of a cash flow statement: the accounts mapped to it and their
+// cash effect (positive = cash in). In the direct method Amount is Inflows + Outflows
+// (Outflows is negative); in the indirect method both are zero.
+type CashFlowLine struct {
+	Name     string
+	Accounts []string
+	Amount   decimal.Decimal
+	Inflows  decimal.Decimal
+	Outflows decimal.Decimal
+}
+
+// CashFlowSection is one activity of the statement. The operating Total includes
+// NetIncome and Adjustments in the indirect method.
+type CashFlowSection struct {
+	Activity string
+	Lines    []CashFlowLine
+	Total    decimal.Decimal
+}
+
+// CashFlowReport is the statement of cash flows for a date range. Cash is the total balance
+// of the accounts flagged is_cash; IsReconciled is true when OpeningCash + NetChange equals
+// ClosingCash.
+type CashFlowReport struct {
+	CompanyCode  string
+	FromDate     string
+	ToDate       string
+	Method       string          // INDIRECT | DIRECT
+	NetIncome    decimal.Decimal // indirect only
+	Adjustments  []CashFlowLine  // indirect only: non-cash items added back
+	Operating    CashFlowSection
+	Investing    CashFlowSection
+	Financing    CashFlowSection
+	NetChange    decimal.Decimal
+	OpeningCash  decimal.Decimal
+	ClosingCash  decimal.Decimal
+	CashAccounts []AccountLine // closing balance of each cash account
+	IsReconciled bool
+}
+
+// CashFlowMapping is the cash flow classification of one account. IsDefault is true when
+// the account has no cash_flow_mappings row and Activity / LineName are the defaults.
+type CashFlowMapping struct {
+	AccountCode string
+	AccountName string
+	AccountType string
+	IsCash      bool
+	Activity    string
+	LineName    string
+	IsDefault   bool
+}
+
+// Statement layout kinds and line signs.
+const (
+	StatementBalanceSheet = "BALANCE_SHEET"
+	StatementProfitLoss   = "PROFIT_AND_LOSS"
+
+	NormalBalanceDebit  = "DEBIT"
+	NormalBalanceCredit = "CREDIT"
+)
+
+// StatementLayout is a configurable financial statement format: a tree of report lines and
+// the accounts mapped to them. Mapping a group account maps its descendants, unless a
+// descendant is mapped itself.
+type StatementLayout struct {
+	Code      string
+	Name      string
+	Statement string // BALANCE_SHEET | PROFIT_AND_LOSS
+	Lines     []StatementLayoutLine
+	Accounts  []StatementLayoutAccount
+}
+
+// StatementLayoutLine is one line of a layout. NormalBalance is the sign the line is shown
+// in (CREDIT: credit-positive); IncludesProfit adds the profit not yet closed to equity to a
+// balance sheet line.
+type StatementLayoutLine struct {
+	Code           string
+	ParentCode     string
+	Label          string
+	SortOrder      int
+	NormalBalance  string
+	IncludesProfit bool
+}
+
+// StatementLayoutAccount maps an account (or group) to a layout line.
+type StatementLayoutAccount struct {
+	AccountCode string
+	LineCode    string
+}
+
+// LayoutStatementLine is one line of a statement in a layout, in tree order. Amount is the
+// line's own accounts plus its sub-lines, in the line's NormalBalance sign; Accounts are the
+// accounts mapped directly to it, in the same sign.
+type LayoutStatementLine struct {
+	Code      string
+	Label     string
+	Level     int
+	IsHeading bool // has sub-lines
+	Amount    decimal.Decimal
+	Accounts  []AccountLine
+}
+
+// LayoutStatement is a balance sheet or P&L presented in a statement layout. Profit is the
+// net profit (credit-positive) for the period, or up to ToDate for a balance sheet. For a
+// balance sheet IsBalanced is true when the debit and credit top-level lines are equal,
+// which needs every account mapped and the profit included on a line or closed to equity.
+type LayoutStatement struct {
+	CompanyCode string
+	LayoutCode  string
+	LayoutName  string
+	Statement   string
+	FromDate    string // P&L only
+	ToDate      string
+	Lines       []LayoutStatementLine
+	Unmapped    []AccountLine // accounts with a balance that map to no line, in normal sign
+	Profit      decimal.Decimal
+	IsBalanced  bool
+}
+
 // ── Interface ─────────────────────────────────────────────────────────────────
 
-// ReportingService provides read-only reporting queries over the ledger.
+// ReportingService provides read-only reporting queries over the ledger, and maintains the
+// account classification used by the cash flow statement.
 type ReportingService interface {
 	// GetAccountStatement returns all journal lines for an account within the
 	// given date range, ordered by posting_date ASC then entry id ASC.
@@ -86,17 +418,84 @@ type ReportingService interface {
 	// RefreshViews refreshes all materialized reporting views
 	// (mv_account_period_balances and mv_trial_balance).
 	RefreshViews(ctx context.Context) error
+
+	// GetColumnarProfitAndLoss returns the P&L with one column per period. Whole months are
+	// read from mv_account_period_balances and part months from journal_lines, so call
+	// RefreshViews first for postings since the last refresh. With comparative set, each
+	// line carries its variance from the first column.
+	GetColumnarProfitAndLoss(ctx context.Context, companyCode string, periods []ReportPeriod, comparative bool) (*ColumnarPLReport, error)
+
+	// GetColumnarBalanceSheet returns the Balance Sheet as of each period's ToDate, read the
+	// same way as GetColumnarProfitAndLoss.
+	GetColumnarBalanceSheet(ctx context.Context, companyCode string, periods []ReportPeriod, comparative bool) (*ColumnarBSReport, error)
+
+	// GetInventoryValuation returns quantity and value per product and warehouse
+	// as of the given date. If asOfDate is empty, today's date is used.
+	GetInventoryValuation(ctx context.Context, companyCode, asOfDate string) (*InventoryValuationReport, error)
+
+	// GetStockMovementLedger returns the physical movements for a product with
+	// opening, running, and closing quantity and value. warehouseCode, fromDate,
+	// and toDate are optional — pass empty string for no filter.
+	GetStockMovementLedger(ctx context.Context, companyCode, productCode, warehouseCode, fromDate, toDate string) (*StockMovementLedger, error)
+
+	// GetStockAgeing returns on-hand stock as of asOfDate split into age buckets,
+	// flagging items with no issue within slowMovingDays as slow-moving.
+	// If asOfDate is empty, today's date is used; slowMovingDays <= 0 defaults to 90.
+	GetStockAgeing(ctx context.Context, companyCode, asOfDate string, slowMovingDays int) (*StockAgeingReport, error)
+
+	// GetLotTrace traces a lot forward: its receipts (vendor, PO) and shipments (customer, order).
+	GetLotTrace(ctx context.Context, companyCode, productCode, lotNumber string) (*LotTrace, error)
+
+	// GetOrderLotTrace traces a sales order backward: the lots shipped on it and the
+	// vendor purchase orders those lots were received against.
+	GetOrderLotTrace(ctx context.Context, companyCode string, orderID int) (*OrderLotTrace, error)
+
+	// GetCashFlow returns the statement of cash flows for [fromDate, toDate] by the INDIRECT
+	// (default) or DIRECT method. If toDate is empty, today is used; if fromDate is empty,
+	// the first day of toDate's month.
+	GetCashFlow(ctx context.Context, companyCode, fromDate, toDate, method string) (*CashFlowReport, error)
+
+	// GetCashFlowMappings returns the cash flow classification of every account.
+	GetCashFlowMappings(ctx context.Context, companyCode string) ([]CashFlowMapping, error)
+
+	// SetCashFlowMapping maps an account to an activity and statement line. An empty
+	// lineName uses the account name; an empty activity removes the mapping.
+	SetCashFlowMapping(ctx context.Context, companyCode, accountCode, activity, lineName string) error
+
+	// SetCashAccount flags or unflags an asset account as cash or bank.
+	SetCashAccount(ctx context.Context, companyCode, accountCode string, isCash bool) error
+
+	// GetStatementLayouts returns the company's statement layouts with their lines and
+	// account mappings.
+	GetStatementLayouts(ctx context.Context, companyCode string) ([]StatementLayout, error)
+
+	// CreateStatementLayout adds an empty BALANCE_SHEET or PROFIT_AND_LOSS layout.
+	CreateStatementLayout(ctx context.Context, companyCode, code, name, statement string) error
+
+	// SetStatementLayoutLine creates or updates a layout line. An empty NormalBalance takes
+	// the parent line's; a top-level line must set it.
+	SetStatementLayoutLine(ctx context.Context, companyCode, layoutCode string, line StatementLayoutLine) error
+
+	// SetStatementLayoutAccount maps an account or group to a layout line. An empty
+	// lineCode removes the mapping.
+	SetStatementLayoutAccount(ctx context.Context, companyCode, layoutCode, accountCode, lineCode string) error
+
+	// GetLayoutStatement returns the balance sheet as of toDate, or the P&L for [fromDate,
+	// toDate], presented in a statement layout. If toDate is empty, today is used; if
+	// fromDate is empty, the first day of toDate's month.
+	GetLayoutStatement(ctx context.Context, companyCode, layoutCode, fromDate, toDate string) (*LayoutStatement, error)
 }
 
 // ── Implementation ────────────────────────────────────────────────────────────
 
 type reportingService struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
 }
 
 // NewReportingService constructs a ReportingService backed by the given pool.
 func NewReportingService(pool *pgxpool.Pool) ReportingService {
-	return &reportingService{pool: pool}
+	return &reportingService{pool: pool, ruleEngine: NewRuleEngine(pool)}
 }
 
 // resolveCompanyID looks up the integer primary key for a company code.
@@ -199,6 +598,7 @@ func (s *reportingService) GetProfitAndLoss(ctx context.Context, companyCode str
 		) s ON s.account_id = a.id
 		WHERE c.id = $1
 		  AND a.type IN ('revenue', 'expense')
+		  AND NOT a.is_group
 		ORDER BY a.type, a.code`
 
 	rows, err := s.pool.Query(ctx, q, companyID, year, month)
@@ -270,6 +670,7 @@ func (s *reportingService) GetBalanceSheet(ctx context.Context, companyCode, asO
 		) s ON s.account_id = a.id
 		WHERE c.id = $1
 		  AND a.type IN ('asset', 'liability', 'equity')
+		  AND NOT a.is_group
 		ORDER BY a.type, a.code`
 
 	rows, err := s.pool.Query(ctx, q, companyID, asOfDate)
@@ -329,3 +730,1310 @@ func (s *reportingService) RefreshViews(ctx context.Context) error {
 	}
 	return nil
 }
+
+// ── Columnar statements ───────────────────────────────────────────────────────
+
+// maxReportColumns bounds the number of periods in one columnar report.
+const maxReportColumns = 24
+
+// columnarAccount is an account listed on a columnar statement.
+type columnarAccount struct {
+	code, name, accType string
+}
+
+// columnarNets returns, for each period, the net debit (debit − credit) per account code
+// over [FromDate, ToDate], or up to ToDate when cumulative is set. Whole calendar months
+// come from mv_account_period_balances and the part months at either end from journal_lines.
+func (s *reportingService) columnarNets(ctx context.Context, companyID int, periods []ReportPeriod, cumulative bool) ([]map[string]decimal.Decimal, error) {
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("at least one period is required")
+	}
+	if len(periods) > maxReportColumns {
+		return nil, fmt.Errorf("at most %d periods are allowed, got %d", maxReportColumns, len(periods))
+	}
+
+	nets := make([]map[string]decimal.Decimal, len(periods))
+	for i, p := range periods {
+		to, err := time.Parse("2006-01-02", p.ToDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period end %q (expected YYYY-MM-DD)", p.ToDate)
+		}
+		var from time.Time
+		if !cumulative {
+			if from, err = time.Parse("2006-01-02", p.FromDate); err != nil {
+				return nil, fmt.Errorf("invalid period start %q (expected YYYY-MM-DD)", p.FromDate)
+			}
+			if from.After(to) {
+				return nil, fmt.Errorf("period %s starts after it ends", p.Label)
+			}
+		}
+
+		monthsFrom, monthsTo, hasMonths, parts := splitByMonth(from, to)
+		args := []any{companyID}
+		var branches []string
+		if hasMonths {
+			cond := "make_date(year, month, 1) <= $2::date"
+			args = append(args, monthsTo.Format("2006-01-02"))
+			if !monthsFrom.IsZero() {
+				cond += " AND make_date(year, month, 1) >= $3::date"
+				args = append(args, monthsFrom.Format("2006-01-02"))
+			}
+			branches = append(branches, `
+				SELECT account_code, net_balance AS net
+				FROM mv_account_period_balances
+				WHERE company_id = $1 AND `+cond)
+		}
+		for _, part := range parts {
+			args = append(args, part[0].Format("2006-01-02"), part[1].Format("2006-01-02"))
+			branches = append(branches, fmt.Sprintf(`
+				SELECT a.code, jl.debit_base - jl.credit_base
+				FROM journal_lines jl
+				JOIN journal_entries je ON je.id = jl.entry_id
+				JOIN accounts a ON a.id = jl.account_id
+				WHERE je.company_id = $1 AND je.posting_date BETWEEN $%d::date AND $%d::date`, len(args)-1, len(args)))
+		}
+		q := `SELECT account_code, SUM(net) FROM (` + strings.Join(branches, " UNION ALL ") + `
+			) t GROUP BY account_code`
+
+		rows, err := s.pool.Query(ctx, q, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query balances for %s: %w", p.Label, err)
+		}
+		nets[i] = map[string]decimal.Decimal{}
+		for rows.Next() {
+			var code string
+			var net decimal.Decimal
+			if err := rows.Scan(&code, &net); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan balance row: %w", err)
+			}
+			nets[i][code] = net
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("balance row iteration error: %w", err)
+		}
+	}
+	return nets, nil
+}
+
+// columnarAccounts lists the company's accounts of the given types, ordered by type and code.
+func (s *reportingService) columnarAccounts(ctx context.Context, companyID int, types ...string) ([]columnarAccount, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT code, name, type FROM accounts
+		WHERE company_id = $1 AND type = ANY($2) AND NOT is_group
+		ORDER BY type, code`, companyID, types)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []columnarAccount
+	for rows.Next() {
+		var a columnarAccount
+		if err := rows.Scan(&a.code, &a.name, &a.accType); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+// columnarSection builds a section from the accounts of one type. Credit-normal sections
+// (creditNormal) negate the net debit so that a normal balance is positive.
+func columnarSection(title, accType string, creditNormal bool, accounts []columnarAccount, nets []map[string]decimal.Decimal, comparative bool) ColumnarSection {
+	section := ColumnarSection{Title: title}
+	for _, a := range accounts {
+		if a.accType != accType {
+			continue
+		}
+		amounts := make([]decimal.Decimal, len(nets))
+		for i, n := range nets {
+			amounts[i] = n[a.code]
+			if creditNormal {
+				amounts[i] = amounts[i].Neg()
+			}
+		}
+		section.Lines = append(section.Lines, newColumnarLine(a.code, a.name, amounts, comparative))
+	}
+	section.Total = newColumnarLine("", "Total "+title, sumColumns(section.Lines, len(nets)), comparative)
+	return section
+}
+
+func (s *reportingService) GetColumnarProfitAndLoss(ctx context.Context, companyCode string, periods []ReportPeriod, comparative bool) (*ColumnarPLReport, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	nets, err := s.columnarNets(ctx, companyID, periods, false)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.columnarAccounts(ctx, companyID, "revenue", "expense")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ColumnarPLReport{
+		CompanyCode: companyCode,
+		Columns:     periods,
+		Comparative: comparative,
+		Revenue:     columnarSection("Revenue", "revenue", true, accounts, nets, comparative),
+		Expenses:    columnarSection("Expenses", "expense", false, accounts, nets, comparative),
+	}
+	net := make([]decimal.Decimal, len(periods))
+	for i := range net {
+		net[i] = report.Revenue.Total.Amounts[i].Sub(report.Expenses.Total.Amounts[i])
+	}
+	report.NetIncome = newColumnarLine("", "Net Income", net, comparative)
+	return report, nil
+}
+
+func (s *reportingService) GetColumnarBalanceSheet(ctx context.Context, companyCode string, periods []ReportPeriod, comparative bool) (*ColumnarBSReport, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	nets, err := s.columnarNets(ctx, companyID, periods, true)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.columnarAccounts(ctx, companyID, "asset", "liability", "equity")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ColumnarBSReport{
+		CompanyCode: companyCode,
+		Columns:     periods,
+		Comparative: comparative,
+		Assets:      columnarSection("Assets", "asset", false, accounts, nets, comparative),
+		Liabilities: columnarSection("Liabilities", "liability", true, accounts, nets, comparative),
+		Equity:      columnarSection("Equity", "equity", true, accounts, nets, comparative),
+	}
+	for i := range periods {
+		claims := report.Liabilities.Total.Amounts[i].Add(report.Equity.Total.Amounts[i])
+		report.IsBalanced = append(report.IsBalanced, report.Assets.Total.Amounts[i].Equal(claims))
+	}
+	return report, nil
+}
+
+// ── Inventory reports ─────────────────────────────────────────────────────────
+
+// physicalMovementTypes lists the inventory_movements types that change
+// qty_on_hand or stock value. LANDED_COST and PRICE_VARIANCE movements carry value
+// only (quantity 0).
+// Reservations only soft-lock stock and are excluded from valuation, movement
+// ledger, and ageing reports.
+const physicalMovementTypes = `('RECEIPT', 'SHIPMENT', 'ADJUSTMENT', 'LANDED_COST', 'PRICE_VARIANCE')`
+
+// stockValueAsOf returns the company's perpetual stock value from the physical
+// movements dated on or before asOf (YYYY-MM-DD).
+func (s *reportingService) stockValueAsOf(ctx context.Context, companyID int, asOf string) (decimal.Decimal, error) {
+	var value decimal.Decimal
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(total_cost), 0)
+		FROM inventory_movements
+		WHERE company_id = $1
+		  AND movement_type IN `+physicalMovementTypes+`
+		  AND movement_date <= $2::date`,
+		companyID, asOf,
+	).Scan(&value); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to compute stock value: %w", err)
+	}
+	return value, nil
+}
+
+// reconcileInventory compares stockValue, the perpetual stock value as of asOf
+// (YYYY-MM-DD), against the INVENTORY account balance from the entries posted on
+// or before asOf.
+func (s *reportingService) reconcileInventory(ctx context.Context, companyID int, stockValue decimal.Decimal, asOf string) (InventoryReconciliation, error) {
+	rec := InventoryReconciliation{AsOfDate: asOf, StockValue: stockValue}
+
+	accountCode, err := s.ruleEngine.ResolveAccount(ctx, companyID, "INVENTORY")
+	if err != nil {
+		return rec, fmt.Errorf("failed to resolve INVENTORY account: %w", err)
+	}
+	rec.InventoryAccountCode = accountCode
+
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(jl.debit_base - jl.credit_base), 0)
+		FROM journal_lines jl
+		JOIN journal_entries je ON je.id = jl.entry_id
+		JOIN accounts a         ON a.id = jl.account_id
+		WHERE je.company_id = $1
+		  AND a.code = $2
+		  AND je.posting_date <= $3::date`,
+		companyID, accountCode, asOf,
+	).Scan(&rec.LedgerBalance); err != nil {
+		return rec, fmt.Errorf("failed to read inventory account balance: %w", err)
+	}
+
+	rec.Difference = rec.StockValue.Sub(rec.LedgerBalance)
+	rec.IsReconciled = rec.Difference.Round(2).IsZero()
+	return rec, nil
+}
+
+// parseReportDate returns today's date when s is empty, otherwise parses YYYY-MM-DD.
+func parseReportDate(s string) (time.Time, error) {
+	if s == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", s)
+	}
+	return d, nil
+}
+
+// ── GetInventoryValuation ─────────────────────────────────────────────────────
+
+// GetInventoryValuation rebuilds each item's quantity and value from the
+// physical movements posted on or before asOfDate, so past positions are
+// reported at the costs actually booked at the time.
+func (s *reportingService) GetInventoryValuation(ctx context.Context, companyCode, asOfDate string) (*InventoryValuationReport, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	asOf, err := parseReportDate(asOfDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT p.code, p.name, w.code, w.name,
+		       SUM(im.quantity)   AS qty,
+		       SUM(im.total_cost) AS value
+		FROM inventory_movements im
+		JOIN inventory_items ii ON ii.id = im.inventory_item_id
+		JOIN products p         ON p.id  = ii.product_id
+		JOIN warehouses w       ON w.id  = ii.warehouse_id
+		WHERE im.company_id = $1
+		  AND im.movement_type IN `+physicalMovementTypes+`
+		  AND im.movement_date <= $2::date
+		GROUP BY p.code, p.name, w.code, w.name
+		HAVING SUM(im.quantity) <> 0 OR SUM(im.total_cost) <> 0
+		ORDER BY p.code, w.code`,
+		companyID, asOf.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory valuation: %w", err)
+	}
+	defer rows.Close()
+
+	report := &InventoryValuationReport{CompanyCode: companyCode, AsOfDate: asOf.Format("2006-01-02")}
+	for rows.Next() {
+		var l InventoryValuationLine
+		if err := rows.Scan(&l.ProductCode, &l.ProductName, &l.WarehouseCode, &l.WarehouseName,
+			&l.Quantity, &l.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan valuation row: %w", err)
+		}
+		if !l.Quantity.IsZero() {
+			l.UnitCost = l.Value.Div(l.Quantity).Round(6)
+		}
+		report.Lines = append(report.Lines, l)
+		report.TotalQuantity = report.TotalQuantity.Add(l.Quantity)
+		report.TotalValue = report.TotalValue.Add(l.Value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("valuation row iteration error: %w", err)
+	}
+
+	if report.Reconciliation, err = s.reconcileInventory(ctx, companyID, report.TotalValue, report.AsOfDate); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ── GetStockMovementLedger ────────────────────────────────────────────────────
+
+func (s *reportingService) GetStockMovementLedger(ctx context.Context, companyCode, productCode, warehouseCode, fromDate, toDate string) (*StockMovementLedger, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StockMovementLedger{
+		CompanyCode:   companyCode,
+		ProductCode:   productCode,
+		WarehouseCode: warehouseCode,
+		FromDate:      fromDate,
+		ToDate:        toDate,
+	}
+
+	var productID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, name FROM products WHERE company_id = $1 AND code = $2",
+		companyID, productCode,
+	).Scan(&productID, &report.ProductName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %s not found for company %s", productCode, companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve product: %w", err)
+	}
+
+	// Shared filter: company, product, physical movement types, optional warehouse.
+	where := `
+		WHERE im.company_id = $1
+		  AND ii.product_id = $2
+		  AND im.movement_type IN ` + physicalMovementTypes
+	args := []any{companyID, productID}
+	if warehouseCode != "" {
+		args = append(args, warehouseCode)
+		where += fmt.Sprintf(" AND w.code = $%d", len(args))
+	}
+
+	if fromDate != "" {
+		openArgs := append(append([]any{}, args...), fromDate)
+		if err := s.pool.QueryRow(ctx, `
+			SELECT COALESCE(SUM(im.quantity), 0), COALESCE(SUM(im.total_cost), 0)
+			FROM inventory_movements im
+			JOIN inventory_items ii ON ii.id = im.inventory_item_id
+			JOIN warehouses w       ON w.id  = ii.warehouse_id`+where+
+			fmt.Sprintf(" AND im.movement_date < $%d::date", len(openArgs)),
+			openArgs...,
+		).Scan(&report.OpeningQty, &report.OpeningValue); err != nil {
+			return nil, fmt.Errorf("failed to compute opening stock: %w", err)
+		}
+	}
+
+	q := `
+		SELECT im.movement_date::text,
+		       im.movement_type,
+		       w.code,
+		       COALESCE(so.order_number, po.po_number, ''),
+		       COALESCE(im.notes, ''),
+		       im.quantity,
+		       im.unit_cost,
+		       im.total_cost
+		FROM inventory_movements im
+		JOIN inventory_items ii            ON ii.id  = im.inventory_item_id
+		JOIN warehouses w                  ON w.id   = ii.warehouse_id
+		LEFT JOIN sales_orders so          ON so.id  = im.order_id
+		LEFT JOIN purchase_order_lines pol ON pol.id = im.po_line_id
+		LEFT JOIN purchase_orders po       ON po.id  = pol.order_id` + where
+	if fromDate != "" {
+		args = append(args, fromDate)
+		q += fmt.Sprintf(" AND im.movement_date >= $%d::date", len(args))
+	}
+	if toDate != "" {
+		args = append(args, toDate)
+		q += fmt.Sprintf(" AND im.movement_date <= $%d::date", len(args))
+	}
+	q += " ORDER BY im.movement_date ASC, im.id ASC"
+
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	runningQty, runningValue := report.OpeningQty, report.OpeningValue
+	for rows.Next() {
+		var l StockMovementLine
+		if err := rows.Scan(&l.MovementDate, &l.MovementType, &l.WarehouseCode, &l.Reference, &l.Notes,
+			&l.Quantity, &l.UnitCost, &l.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+		runningQty = runningQty.Add(l.Quantity)
+		runningValue = runningValue.Add(l.Value)
+		l.RunningQty = runningQty
+		l.RunningValue = runningValue
+		report.Lines = append(report.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("stock movement row iteration error: %w", err)
+	}
+
+	report.ClosingQty = runningQty
+	report.ClosingValue = runningValue
+
+	// The ledger covers one product; the reconciliation is company-wide, at the end date.
+	asOf, err := parseReportDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	stockValue, err := s.stockValueAsOf(ctx, companyID, asOf.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if report.Reconciliation, err = s.reconcileInventory(ctx, companyID, stockValue, asOf.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ── GetStockAgeing ────────────────────────────────────────────────────────────
+
+// defaultSlowMovingDays is used when GetStockAgeing is called without a threshold.
+const defaultSlowMovingDays = 90
+
+func (s *reportingService) GetStockAgeing(ctx context.Context, companyCode, asOfDate string, slowMovingDays int) (*StockAgeingReport, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	asOf, err := parseReportDate(asOfDate)
+	if err != nil {
+		return nil, err
+	}
+	if slowMovingDays <= 0 {
+		slowMovingDays = defaultSlowMovingDays
+	}
+	asOfStr := asOf.Format("2006-01-02")
+
+	// Positive on-hand positions as of the date, with first/last receipt and last issue.
+	rows, err := s.pool.Query(ctx, `
+		SELECT ii.id, p.code, p.name, w.code,
+		       SUM(im.quantity),
+		       SUM(im.total_cost),
+		       COALESCE((MAX(im.movement_date) FILTER (WHERE im.movement_type = 'RECEIPT' AND im.quantity > 0))::text, ''),
+		       COALESCE((MAX(im.movement_date) FILTER (WHERE im.movement_type = 'SHIPMENT'))::text, ''),
+		       COALESCE((MIN(im.movement_date) FILTER (WHERE im.movement_type = 'RECEIPT' AND im.quantity > 0))::text, '')
+		FROM inventory_movements im
+		JOIN inventory_items ii ON ii.id = im.inventory_item_id
+		JOIN products p         ON p.id  = ii.product_id
+		JOIN warehouses w       ON w.id  = ii.warehouse_id
+		WHERE im.company_id = $1
+		  AND im.movement_type IN `+physicalMovementTypes+`
+		  AND im.movement_date <= $2::date
+		GROUP BY ii.id, p.code, p.name, w.code
+		HAVING SUM(im.quantity) > 0
+		ORDER BY p.code, w.code`,
+		companyID, asOfStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock positions: %w", err)
+	}
+
+	type ageingItem struct {
+		itemID       int
+		firstReceipt string
+		line         StockAgeingLine
+	}
+	var items []ageingItem
+	for rows.Next() {
+		var it ageingItem
+		if err := rows.Scan(&it.itemID, &it.line.ProductCode, &it.line.ProductName, &it.line.WarehouseCode,
+			&it.line.Quantity, &it.line.Value, &it.line.LastReceiptDate, &it.line.LastIssueDate, &it.firstReceipt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stock position: %w", err)
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("stock position row iteration error: %w", err)
+	}
+
+	// Receipts newest first per item: remaining stock is matched to the latest receipts.
+	type receipt struct {
+		date time.Time
+		qty  decimal.Decimal
+	}
+	receipts := make(map[int][]receipt)
+	rrows, err := s.pool.Query(ctx, `
+		SELECT inventory_item_id, movement_date, quantity
+		FROM inventory_movements
+		WHERE company_id = $1
+		  AND movement_type = 'RECEIPT'
+		  AND quantity > 0
+		  AND movement_date <= $2::date
+		ORDER BY inventory_item_id, movement_date DESC, id DESC`,
+		companyID, asOfStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query receipts: %w", err)
+	}
+	for rrows.Next() {
+		var itemID int
+		var r receipt
+		if err := rrows.Scan(&itemID, &r.date, &r.qty); err != nil {
+			rrows.Close()
+			return nil, fmt.Errorf("failed to scan receipt: %w", err)
+		}
+		receipts[itemID] = append(receipts[itemID], r)
+	}
+	rrows.Close()
+	if err := rrows.Err(); err != nil {
+		return nil, fmt.Errorf("receipt row iteration error: %w", err)
+	}
+
+	report := &StockAgeingReport{CompanyCode: companyCode, AsOfDate: asOfStr, SlowMovingDays: slowMovingDays}
+	for _, it := range items {
+		l := it.line
+
+		remaining := l.Quantity
+		for _, r := range receipts[it.itemID] {
+			if !remaining.IsPositive() {
+				break
+			}
+			take := decimal.Min(remaining, r.qty)
+			remaining = remaining.Sub(take)
+			switch age := int(asOf.Sub(r.date).Hours() / 24); {
+			case age <= 30:
+				l.Age0To30 = l.Age0To30.Add(take)
+			case age <= 60:
+				l.Age31To60 = l.Age31To60.Add(take)
+			case age <= 90:
+				l.Age61To90 = l.Age61To90.Add(take)
+			default:
+				l.AgeOver90 = l.AgeOver90.Add(take)
+			}
+		}
+		if remaining.IsPositive() {
+			l.AgeOver90 = l.AgeOver90.Add(remaining)
+		}
+
+		// Slow-moving: measured from the last issue, or from the first receipt if never issued.
+		ref := l.LastIssueDate
+		if ref == "" {
+			ref = it.firstReceipt
+		}
+		if d, err := time.Parse("2006-01-02", ref); err == nil {
+			l.DaysSinceIssue = int(asOf.Sub(d).Hours() / 24)
+			l.IsSlowMoving = l.DaysSinceIssue > slowMovingDays
+		} else {
+			// No dated receipt or issue at all — nothing is known to have moved.
+			l.IsSlowMoving = true
+		}
+
+		report.Lines = append(report.Lines, l)
+		report.TotalValue = report.TotalValue.Add(l.Value)
+		if l.IsSlowMoving {
+			report.SlowMovingValue = report.SlowMovingValue.Add(l.Value)
+		}
+	}
+
+	// TotalValue covers positive positions only; reconcile the full stock value.
+	stockValue, err := s.stockValueAsOf(ctx, companyID, asOfStr)
+	if err != nil {
+		return nil, err
+	}
+	if report.Reconciliation, err = s.reconcileInventory(ctx, companyID, stockValue, asOfStr); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ── Lot trace ─────────────────────────────────────────────────────────────────
+
+// lotTraceSelect selects LotTraceEntry columns for lot movements, resolving the vendor
+// through the PO line on receipts and the customer through the sales order on shipments.
+const lotTraceSelect = `
+	SELECT im.movement_date::text, im.movement_type, w.code, l.lot_number,
+	       COALESCE(po.po_number, so.order_number, ''),
+	       COALESCE(v.code, c.code, ''),
+	       COALESCE(v.name, c.name, ''),
+	       ABS(im.quantity)
+	FROM inventory_movements im
+	JOIN inventory_lots l        ON l.id = im.lot_id
+	JOIN inventory_items ii      ON ii.id = l.inventory_item_id
+	JOIN warehouses w            ON w.id = ii.warehouse_id
+	LEFT JOIN purchase_order_lines pol ON pol.id = im.po_line_id
+	LEFT JOIN purchase_orders po ON po.id = pol.order_id
+	LEFT JOIN vendors v          ON v.id = po.vendor_id
+	LEFT JOIN sales_orders so    ON so.id = im.order_id
+	LEFT JOIN customers c        ON c.id = so.customer_id`
+
+func (s *reportingService) queryLotTrace(ctx context.Context, where string, args ...any) ([]LotTraceEntry, error) {
+	rows, err := s.pool.Query(ctx, lotTraceSelect+" "+where+" ORDER BY im.movement_date, im.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lot movements: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []LotTraceEntry
+	for rows.Next() {
+		var e LotTraceEntry
+		if err := rows.Scan(&e.MovementDate, &e.MovementType, &e.WarehouseCode, &e.LotNumber,
+			&e.Reference, &e.PartyCode, &e.PartyName, &e.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan lot movement: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *reportingService) GetLotTrace(ctx context.Context, companyCode, productCode, lotNumber string) (*LotTrace, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &LotTrace{CompanyCode: companyCode, ProductCode: productCode, LotNumber: lotNumber}
+
+	var productID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, name FROM products WHERE company_id = $1 AND code = $2",
+		companyID, productCode,
+	).Scan(&productID, &trace.ProductName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %s not found for company %s", productCode, companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve product: %w", err)
+	}
+
+	var lotCount int
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(l.qty_on_hand), 0), TO_CHAR(MIN(l.expiry_date), 'YYYY-MM-DD')
+		FROM inventory_lots l
+		JOIN inventory_items ii ON ii.id = l.inventory_item_id
+		WHERE ii.company_id = $1 AND ii.product_id = $2 AND l.lot_number = $3`,
+		companyID, productID, lotNumber,
+	).Scan(&lotCount, &trace.QtyOnHand, &trace.ExpiryDate); err != nil {
+		return nil, fmt.Errorf("failed to resolve lot: %w", err)
+	}
+	if lotCount == 0 {
+		return nil, fmt.Errorf("lot %s not found for product %s", lotNumber, productCode)
+	}
+
+	entries, err := s.queryLotTrace(ctx,
+		"WHERE ii.company_id = $1 AND ii.product_id = $2 AND l.lot_number = $3", companyID, productID, lotNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		switch e.MovementType {
+		case "RECEIPT":
+			trace.Receipts = append(trace.Receipts, e)
+			trace.TotalReceived = trace.TotalReceived.Add(e.Quantity)
+		case "SHIPMENT":
+			trace.Shipments = append(trace.Shipments, e)
+			trace.TotalShipped = trace.TotalShipped.Add(e.Quantity)
+		}
+	}
+	return trace, nil
+}
+
+func (s *reportingService) GetOrderLotTrace(ctx context.Context, companyCode string, orderID int) (*OrderLotTrace, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &OrderLotTrace{CompanyCode: companyCode, OrderID: orderID}
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COALESCE(so.order_number, ''), c.code, c.name
+		FROM sales_orders so
+		JOIN customers c ON c.id = so.customer_id
+		WHERE so.id = $1 AND so.company_id = $2`,
+		orderID, companyID,
+	).Scan(&trace.OrderNumber, &trace.CustomerCode, &trace.CustomerName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("order %d not found", orderID)
+		}
+		return nil, fmt.Errorf("failed to fetch order %d: %w", orderID, err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT l.id, p.code, p.name, l.lot_number, TO_CHAR(l.expiry_date, 'YYYY-MM-DD'),
+		       MIN(im.movement_date)::text, SUM(ABS(im.quantity))
+		FROM inventory_movements im
+		JOIN inventory_lots l   ON l.id = im.lot_id
+		JOIN inventory_items ii ON ii.id = l.inventory_item_id
+		JOIN products p         ON p.id = ii.product_id
+		WHERE im.order_id = $1 AND im.movement_type = 'SHIPMENT'
+		GROUP BY l.id, p.code, p.name, l.lot_number, l.expiry_date
+		ORDER BY p.code, l.lot_number`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shipped lots: %w", err)
+	}
+	var lotIDs []int
+	for rows.Next() {
+		var line OrderLotTraceLine
+		var lotID int
+		if err := rows.Scan(&lotID, &line.ProductCode, &line.ProductName, &line.LotNumber,
+			&line.ExpiryDate, &line.ShippedDate, &line.QtyShipped); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan shipped lot: %w", err)
+		}
+		lotIDs = append(lotIDs, lotID)
+		trace.Lines = append(trace.Lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shipped lots: %w", err)
+	}
+
+	for i, lotID := range lotIDs {
+		sources, err := s.queryLotTrace(ctx, "WHERE l.id = $1 AND im.movement_type = 'RECEIPT'", lotID)
+		if err != nil {
+			return nil, err
+		}
+		trace.Lines[i].Sources = sources
+	}
+	return trace, nil
+}
+
+// ── GetCashFlow ───────────────────────────────────────────────────────────────
+
+// cashFlowPeriodLines selects the company's journal lines posted in [$2, $3], and the
+// entries among them that post to a cash account.
+const cashFlowPeriodLines = `
+	WITH period_lines AS (
+	    SELECT jl.entry_id, jl.account_id, jl.debit_base - jl.credit_base AS net
+	    FROM journal_lines jl
+	    JOIN journal_entries je ON je.id = jl.entry_id
+	    WHERE je.company_id = $1 AND je.posting_date BETWEEN $2::date AND $3::date
+	),
+	cash_entries AS (
+	    SELECT DISTINCT pl.entry_id
+	    FROM period_lines pl
+	    JOIN accounts a ON a.id = pl.account_id
+	    WHERE a.is_cash
+	)`
+
+func (s *reportingService) GetCashFlow(ctx context.Context, companyCode, fromDate, toDate, method string) (*CashFlowReport, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if toDate != "" {
+		if to, err = time.Parse("2006-01-02", toDate); err != nil {
+			return nil, fmt.Errorf("invalid to date %q: expected YYYY-MM-DD", toDate)
+		}
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if fromDate != "" {
+		if from, err = time.Parse("2006-01-02", fromDate); err != nil {
+			return nil, fmt.Errorf("invalid from date %q: expected YYYY-MM-DD", fromDate)
+		}
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("from date %s is after to date %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	fromDate, toDate = from.Format("2006-01-02"), to.Format("2006-01-02")
+
+	var report *CashFlowReport
+	switch strings.ToUpper(method) {
+	case "", CashFlowMethodIndirect:
+		movements, err := s.indirectCashFlowMovements(ctx, companyID, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		report = BuildIndirectCashFlow(movements)
+	case CashFlowMethodDirect:
+		movements, err := s.directCashFlowMovements(ctx, companyID, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		report = BuildDirectCashFlow(movements)
+	default:
+		return nil, fmt.Errorf("invalid cash flow method %q: expected INDIRECT or DIRECT", method)
+	}
+	report.CompanyCode, report.FromDate, report.ToDate = companyCode, fromDate, toDate
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name,
+		       COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE je.posting_date <  $2::date), 0),
+		       COALESCE(SUM(jl.debit_base - jl.credit_base) FILTER (WHERE je.posting_date <= $3::date), 0)
+		FROM accounts a
+		LEFT JOIN journal_lines jl   ON jl.account_id = a.id
+		LEFT JOIN journal_entries je ON je.id = jl.entry_id
+		WHERE a.company_id = $1 AND a.is_cash
+		GROUP BY a.id, a.code, a.name
+		ORDER BY a.code`, companyID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cash balances: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line AccountLine
+		var opening decimal.Decimal
+		if err := rows.Scan(&line.Code, &line.Name, &opening, &line.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan cash balance: %w", err)
+		}
+		report.OpeningCash = report.OpeningCash.Add(opening)
+		report.ClosingCash = report.ClosingCash.Add(line.Balance)
+		report.CashAccounts = append(report.CashAccounts, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cash balance row iteration error: %w", err)
+	}
+	if len(report.CashAccounts) == 0 {
+		return nil, fmt.Errorf("company %s has no cash accounts: flag its cash and bank accounts first", companyCode)
+	}
+
+	report.IsReconciled = report.OpeningCash.Add(report.NetChange).Equal(report.ClosingCash)
+	return report, nil
+}
+
+// indirectCashFlowMovements returns the period movement of each non-cash account. Lines of
+// entries that post to a NON_CASH P&L account and to no cash account are grouped under that
+// P&L account (the lowest code if there are several) so they can be added back.
+func (s *reportingService) indirectCashFlowMovements(ctx context.Context, companyID int, fromDate, toDate string) ([]CashFlowMovement, error) {
+	rows, err := s.pool.Query(ctx, cashFlowPeriodLines+`,
+		non_cash_entries AS (
+		    SELECT pl.entry_id, MIN(a.code) AS account_code
+		    FROM period_lines pl
+		    JOIN accounts a           ON a.id = pl.account_id
+		    JOIN cash_flow_mappings m ON m.company_id = a.company_id AND m.account_code = a.code
+		    WHERE m.activity = 'NON_CASH' AND a.type IN ('revenue', 'expense')
+		      AND pl.entry_id NOT IN (SELECT entry_id FROM cash_entries)
+		    GROUP BY pl.entry_id
+		)
+		SELECT a.code, a.name, a.type, COALESCE(m.activity, ''), COALESCE(m.line_name, ''),
+		       COALESCE(nce.account_code, ''), SUM(pl.net)
+		FROM period_lines pl
+		JOIN accounts a                ON a.id = pl.account_id
+		LEFT JOIN cash_flow_mappings m ON m.company_id = a.company_id AND m.account_code = a.code
+		LEFT JOIN non_cash_entries nce ON nce.entry_id = pl.entry_id
+		WHERE NOT a.is_cash
+		GROUP BY a.code, a.name, a.type, m.activity, m.line_name, nce.account_code
+		ORDER BY a.code, nce.account_code NULLS FIRST`, companyID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cash flow movements: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []CashFlowMovement
+	for rows.Next() {
+		var m CashFlowMovement
+		if err := rows.Scan(&m.AccountCode, &m.AccountName, &m.AccountType, &m.Activity, &m.LineName,
+			&m.NonCashAccount, &m.Net); err != nil {
+			return nil, fmt.Errorf("failed to scan cash flow movement: %w", err)
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cash flow movement row iteration error: %w", err)
+	}
+	return movements, nil
+}
+
+// directCashFlowMovements analyses the entries that post to a cash account by
+// counter-account: each non-cash line's credit is cash received from its account and its
+// debit cash paid, so the counter-accounts of an entry add up to its cash movement.
+func (s *reportingService) directCashFlowMovements(ctx context.Context, companyID int, fromDate, toDate string) ([]CashFlowMovement, error) {
+	rows, err := s.pool.Query(ctx, cashFlowPeriodLines+`,
+		counter AS (
+		    SELECT pl.account_id, -SUM(pl.net) AS cash
+		    FROM period_lines pl
+		    JOIN cash_entries ce ON ce.entry_id = pl.entry_id
+		    JOIN accounts a      ON a.id = pl.account_id
+		    WHERE NOT a.is_cash
+		    GROUP BY pl.entry_id, pl.account_id
+		)
+		SELECT a.code, a.name, a.type, COALESCE(m.activity, ''), COALESCE(m.line_name, ''),
+		       COALESCE(SUM(c.cash) FILTER (WHERE c.cash > 0), 0),
+		       COALESCE(SUM(c.cash) FILTER (WHERE c.cash < 0), 0)
+		FROM counter c
+		JOIN accounts a                ON a.id = c.account_id
+		LEFT JOIN cash_flow_mappings m ON m.company_id = a.company_id AND m.account_code = a.code
+		GROUP BY a.code, a.name, a.type, m.activity, m.line_name
+		ORDER BY a.code`, companyID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cash receipts and payments: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []CashFlowMovement
+	for rows.Next() {
+		var m CashFlowMovement
+		if err := rows.Scan(&m.AccountCode, &m.AccountName, &m.AccountType, &m.Activity, &m.LineName,
+			&m.Inflows, &m.Outflows); err != nil {
+			return nil, fmt.Errorf("failed to scan cash receipts and payments: %w", err)
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cash receipts and payments row iteration error: %w", err)
+	}
+	return movements, nil
+}
+
+// ── Cash flow mapping ─────────────────────────────────────────────────────────
+
+func (s *reportingService) GetCashFlowMappings(ctx context.Context, companyCode string) ([]CashFlowMapping, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT a.code, a.name, a.type, a.is_cash, m.activity, m.line_name
+		FROM accounts a
+		LEFT JOIN cash_flow_mappings m ON m.company_id = a.company_id AND m.account_code = a.code
+		WHERE a.company_id = $1
+		ORDER BY a.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cash flow mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []CashFlowMapping
+	for rows.Next() {
+		var m CashFlowMapping
+		var activity, lineName *string
+		if err := rows.Scan(&m.AccountCode, &m.AccountName, &m.AccountType, &m.IsCash, &activity, &lineName); err != nil {
+			return nil, fmt.Errorf("failed to scan cash flow mapping: %w", err)
+		}
+		if activity != nil {
+			m.Activity, m.LineName = *activity, *lineName
+		} else {
+			m.Activity, m.LineName, m.IsDefault = DefaultCashFlowActivity(m.AccountType), m.AccountName, true
+		}
+		mappings = append(mappings, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cash flow mapping row iteration error: %w", err)
+	}
+	return mappings, nil
+}
+
+func (s *reportingService) SetCashFlowMapping(ctx context.Context, companyCode, accountCode, activity, lineName string) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	var name string
+	var isCash bool
+	if err := s.pool.QueryRow(ctx,
+		"SELECT name, is_cash FROM accounts WHERE company_id = $1 AND code = $2", companyID, accountCode,
+	).Scan(&name, &isCash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("account %s not found", accountCode)
+		}
+		return fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+
+	if strings.TrimSpace(activity) == "" {
+		if _, err := s.pool.Exec(ctx,
+			"DELETE FROM cash_flow_mappings WHERE company_id = $1 AND account_code = $2", companyID, accountCode,
+		); err != nil {
+			return fmt.Errorf("failed to remove cash flow mapping of %s: %w", accountCode, err)
+		}
+		return nil
+	}
+	if isCash {
+		return fmt.Errorf("account %s is a cash account: its balance is the cash the statement explains", accountCode)
+	}
+	activity, err = ParseCashFlowActivity(activity)
+	if err != nil {
+		return err
+	}
+	if lineName = strings.TrimSpace(lineName); lineName == "" {
+		lineName = name
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO cash_flow_mappings (company_id, account_code, activity, line_name)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_id, account_code)
+		DO UPDATE SET activity = EXCLUDED.activity, line_name = EXCLUDED.line_name, updated_at = NOW()`,
+		companyID, accountCode, activity, lineName,
+	); err != nil {
+		return fmt.Errorf("failed to set cash flow mapping of %s: %w", accountCode, err)
+	}
+	return nil
+}
+
+func (s *reportingService) SetCashAccount(ctx context.Context, companyCode, accountCode string, isCash bool) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	var accType string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT type FROM accounts WHERE company_id = $1 AND code = $2", companyID, accountCode,
+	).Scan(&accType); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("account %s not found", accountCode)
+		}
+		return fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+	if isCash && accType != "asset" {
+		return fmt.Errorf("account %s is a %s account: only asset accounts can be cash", accountCode, accType)
+	}
+	if _, err := s.pool.Exec(ctx,
+		"UPDATE accounts SET is_cash = $3 WHERE company_id = $1 AND code = $2", companyID, accountCode, isCash,
+	); err != nil {
+		return fmt.Errorf("failed to update account %s: %w", accountCode, err)
+	}
+	return nil
+}
+
+// ── Statement layouts ─────────────────────────────────────────────────────────
+
+// loadStatementLayout returns a layout's id with its lines and account mappings.
+func (s *reportingService) loadStatementLayout(ctx context.Context, companyID int, layoutCode string) (int, *StatementLayout, error) {
+	var layoutID int
+	layout := &StatementLayout{}
+	err := s.pool.QueryRow(ctx,
+		"SELECT id, code, name, statement FROM statement_layouts WHERE company_id = $1 AND code = $2",
+		companyID, strings.ToUpper(layoutCode),
+	).Scan(&layoutID, &layout.Code, &layout.Name, &layout.Statement)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, fmt.Errorf("statement layout %s not found", layoutCode)
+		}
+		return 0, nil, fmt.Errorf("failed to fetch statement layout %s: %w", layoutCode, err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT line_code, COALESCE(parent_line_code, ''), label, sort_order, normal_balance, includes_profit
+		FROM statement_layout_lines
+		WHERE layout_id = $1
+		ORDER BY sort_order, line_code`, layoutID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query layout lines: %w", err)
+	}
+	for rows.Next() {
+		var l StatementLayoutLine
+		if err := rows.Scan(&l.Code, &l.ParentCode, &l.Label, &l.SortOrder, &l.NormalBalance, &l.IncludesProfit); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan layout line: %w", err)
+		}
+		layout.Lines = append(layout.Lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("layout line iteration error: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT account_code, line_code FROM statement_layout_accounts
+		WHERE layout_id = $1
+		ORDER BY account_code`, layoutID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query layout accounts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m StatementLayoutAccount
+		if err := rows.Scan(&m.AccountCode, &m.LineCode); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan layout account: %w", err)
+		}
+		layout.Accounts = append(layout.Accounts, m)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("layout account iteration error: %w", err)
+	}
+	return layoutID, layout, nil
+}
+
+func (s *reportingService) GetStatementLayouts(ctx context.Context, companyCode string) ([]StatementLayout, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx,
+		"SELECT code FROM statement_layouts WHERE company_id = $1 ORDER BY statement, code", companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statement layouts: %w", err)
+	}
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan statement layout: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("statement layout iteration error: %w", err)
+	}
+
+	layouts := make([]StatementLayout, 0, len(codes))
+	for _, code := range codes {
+		_, layout, err := s.loadStatementLayout(ctx, companyID, code)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, *layout)
+	}
+	return layouts, nil
+}
+
+func (s *reportingService) CreateStatementLayout(ctx context.Context, companyCode, code, name, statement string) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	code, name = strings.ToUpper(strings.TrimSpace(code)), strings.TrimSpace(name)
+	if code == "" || name == "" {
+		return fmt.Errorf("layout code and name are required")
+	}
+	if statement, err = ParseStatementKind(statement); err != nil {
+		return err
+	}
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO statement_layouts (company_id, code, name, statement)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_id, code) DO NOTHING`,
+		companyID, code, name, statement)
+	if err != nil {
+		return fmt.Errorf("failed to create statement layout: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("statement layout %s already exists", code)
+	}
+	return nil
+}
+
+func (s *reportingService) SetStatementLayoutLine(ctx context.Context, companyCode, layoutCode string, line StatementLayoutLine) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	layoutID, layout, err := s.loadStatementLayout(ctx, companyID, layoutCode)
+	if err != nil {
+		return err
+	}
+
+	line.Code = strings.ToUpper(strings.TrimSpace(line.Code))
+	line.ParentCode = strings.ToUpper(strings.TrimSpace(line.ParentCode))
+	line.Label = strings.TrimSpace(line.Label)
+	if line.Code == "" || line.Label == "" {
+		return fmt.Errorf("line code and label are required")
+	}
+	if line.IncludesProfit && layout.Statement != StatementBalanceSheet {
+		return fmt.Errorf("only balance sheet lines can include the profit for the period")
+	}
+
+	parents := make(map[string]StatementLayoutLine, len(layout.Lines))
+	for _, l := range layout.Lines {
+		parents[l.Code] = l
+	}
+	if line.ParentCode != "" {
+		parent, ok := parents[line.ParentCode]
+		if !ok {
+			return fmt.Errorf("parent line %s not found in layout %s", line.ParentCode, layout.Code)
+		}
+		// The new parent must not sit beneath the line itself.
+		for p := line.ParentCode; p != ""; p = parents[p].ParentCode {
+			if p == line.Code {
+				return fmt.Errorf("line %s cannot be placed under itself or one of its sub-lines", line.Code)
+			}
+		}
+		if line.NormalBalance == "" {
+			line.NormalBalance = parent.NormalBalance
+		}
+	}
+	if line.NormalBalance, err = ParseNormalBalance(line.NormalBalance); err != nil {
+		return err
+	}
+
+	var parentCode *string
+	if line.ParentCode != "" {
+		parentCode = &line.ParentCode
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO statement_layout_lines (layout_id, line_code, parent_line_code, label, sort_order, normal_balance, includes_profit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (layout_id, line_code) DO UPDATE
+		SET parent_line_code = EXCLUDED.parent_line_code,
+		    label            = EXCLUDED.label,
+		    sort_order       = EXCLUDED.sort_order,
+		    normal_balance   = EXCLUDED.normal_balance,
+		    includes_profit  = EXCLUDED.includes_profit`,
+		layoutID, line.Code, parentCode, line.Label, line.SortOrder, line.NormalBalance, line.IncludesProfit,
+	); err != nil {
+		return fmt.Errorf("failed to save layout line %s: %w", line.Code, err)
+	}
+	return nil
+}
+
+func (s *reportingService) SetStatementLayoutAccount(ctx context.Context, companyCode, layoutCode, accountCode, lineCode string) error {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return err
+	}
+	layoutID, layout, err := s.loadStatementLayout(ctx, companyID, layoutCode)
+	if err != nil {
+		return err
+	}
+
+	if lineCode == "" {
+		if _, err := s.pool.Exec(ctx,
+			"DELETE FROM statement_layout_accounts WHERE layout_id = $1 AND account_code = $2",
+			layoutID, accountCode,
+		); err != nil {
+			return fmt.Errorf("failed to remove layout mapping: %w", err)
+		}
+		return nil
+	}
+
+	var accountType AccountType
+	err = s.pool.QueryRow(ctx,
+		"SELECT type FROM accounts WHERE company_id = $1 AND code = $2", companyID, accountCode,
+	).Scan(&accountType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("account %s not found", accountCode)
+		}
+		return fmt.Errorf("failed to fetch account %s: %w", accountCode, err)
+	}
+	if !inStatement(layout.Statement, accountType) {
+		return fmt.Errorf("%s account %s does not belong on a %s layout", accountType, accountCode, strings.ToLower(layout.Statement))
+	}
+	lineCode = strings.ToUpper(strings.TrimSpace(lineCode))
+	found := false
+	for _, l := range layout.Lines {
+		found = found || l.Code == lineCode
+	}
+	if !found {
+		return fmt.Errorf("line %s not found in layout %s", lineCode, layout.Code)
+	}
+
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO statement_layout_accounts (layout_id, account_code, line_code)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (layout_id, account_code) DO UPDATE SET line_code = EXCLUDED.line_code`,
+		layoutID, accountCode, lineCode,
+	); err != nil {
+		return fmt.Errorf("failed to save layout mapping: %w", err)
+	}
+	return nil
+}
+
+func (s *reportingService) GetLayoutStatement(ctx context.Context, companyCode, layoutCode, fromDate, toDate string) (*LayoutStatement, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+	_, layout, err := s.loadStatementLayout(ctx, companyID, layoutCode)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseReportDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	from := ""
+	if layout.Statement == StatementProfitLoss {
+		f := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if fromDate != "" {
+			if f, err = parseReportDate(fromDate); err != nil {
+				return nil, err
+			}
+		}
+		if f.After(to) {
+			return nil, fmt.Errorf("from date %s is after to date %s", f.Format("2006-01-02"), to.Format("2006-01-02"))
+		}
+		from = f.Format("2006-01-02")
+	}
+
+	accounts, nets, err := queryAccountNodes(ctx, s.pool, companyID, from, to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	profit := decimal.Zero
+	for _, a := range accounts {
+		if a.Type == Revenue || a.Type == Expense {
+			profit = profit.Sub(nets[a.Code])
+		}
+	}
+
+	report := BuildLayoutStatement(*layout, accounts, nets, profit)
+	report.CompanyCode = companyCode
+	report.FromDate = from
+	report.ToDate = to.Format("2006-01-02")
+	return report, nil
+}
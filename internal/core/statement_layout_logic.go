@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ParseStatementKind normalises a statement layout kind.
+func ParseStatementKind(s string) (string, error) {
+	k := strings.ToUpper(strings.TrimSpace(s))
+	switch k {
+	case StatementBalanceSheet, StatementProfitLoss:
+		return k, nil
+	}
+	return "", fmt.Errorf("invalid statement %q: expected BALANCE_SHEET or PROFIT_AND_LOSS", s)
+}
+
+// ParseNormalBalance normalises a layout line sign.
+func ParseNormalBalance(s string) (string, error) {
+	n := strings.ToUpper(strings.TrimSpace(s))
+	switch n {
+	case NormalBalanceDebit, NormalBalanceCredit:
+		return n, nil
+	}
+	return "", fmt.Errorf("invalid normal balance %q: expected DEBIT or CREDIT", s)
+}
+
+// inStatement reports whether accounts of type t belong on the statement kind.
+func inStatement(statement string, t AccountType) bool {
+	if statement == StatementProfitLoss {
+		return t == Revenue || t == Expense
+	}
+	return t == Asset || t == Liability || t == Equity
+}
+
+// signed converts a net debit amount to the sign of normalBalance.
+func signed(net decimal.Decimal, normalBalance string) decimal.Decimal {
+	if normalBalance == NormalBalanceCredit {
+		return net.Neg()
+	}
+	return net
+}
+
+// BuildLayoutStatement presents account balances in a statement layout. nets holds the net
+// debit (debit − credit) of each account for the statement's period; profit is the net
+// profit (credit-positive), added to any balance sheet line flagged IncludesProfit. Each
+// account goes to the line it is mapped to, or else the line its nearest mapped group is
+// mapped to; accounts with a balance and no line are listed as Unmapped. Company and
+// dates are left for the caller.
+func BuildLayoutStatement(layout StatementLayout, accounts []AccountNode, nets map[string]decimal.Decimal, profit decimal.Decimal) *LayoutStatement {
+	report := &LayoutStatement{
+		LayoutCode: layout.Code,
+		LayoutName: layout.Name,
+		Statement:  layout.Statement,
+		Profit:     profit,
+	}
+
+	lines := make(map[string]StatementLayoutLine, len(layout.Lines))
+	for _, l := range layout.Lines {
+		lines[l.Code] = l
+	}
+	mapping := make(map[string]string, len(layout.Accounts))
+	for _, m := range layout.Accounts {
+		if _, ok := lines[m.LineCode]; ok {
+			mapping[m.AccountCode] = m.LineCode
+		}
+	}
+	parents := make(map[string]string, len(accounts))
+	for _, a := range accounts {
+		parents[a.Code] = a.ParentCode
+	}
+
+	lineNet := map[string]decimal.Decimal{}
+	lineAccounts := map[string][]AccountLine{}
+	for _, a := range accounts {
+		net := nets[a.Code]
+		if a.IsGroup || !inStatement(layout.Statement, a.Type) || net.IsZero() {
+			continue
+		}
+		lineCode := ""
+		code := a.Code
+		for depth := 0; code != "" && depth <= len(accounts); depth++ {
+			if l, ok := mapping[code]; ok {
+				lineCode = l
+				break
+			}
+			code = parents[code]
+		}
+		if lineCode == "" {
+			normal := NormalBalanceCredit
+			if a.Type.IsDebitNormal() {
+				normal = NormalBalanceDebit
+			}
+			report.Unmapped = append(report.Unmapped, AccountLine{Code: a.Code, Name: a.Name, Balance: signed(net, normal)})
+			continue
+		}
+		lineNet[lineCode] = lineNet[lineCode].Add(net)
+		lineAccounts[lineCode] = append(lineAccounts[lineCode],
+			AccountLine{Code: a.Code, Name: a.Name, Balance: signed(net, lines[lineCode].NormalBalance)})
+	}
+	if layout.Statement == StatementBalanceSheet {
+		for _, l := range layout.Lines {
+			if l.IncludesProfit {
+				lineNet[l.Code] = lineNet[l.Code].Sub(profit)
+				lineAccounts[l.Code] = append(lineAccounts[l.Code],
+					AccountLine{Name: "Profit for the period", Balance: signed(profit.Neg(), l.NormalBalance)})
+			}
+		}
+	}
+
+	children := map[string][]StatementLayoutLine{}
+	for _, l := range layout.Lines {
+		parent := l.ParentCode
+		if _, ok := lines[parent]; !ok {
+			parent = ""
+		}
+		children[parent] = append(children[parent], l)
+	}
+	for _, c := range children {
+		sort.Slice(c, func(i, j int) bool {
+			if c[i].SortOrder != c[j].SortOrder {
+				return c[i].SortOrder < c[j].SortOrder
+			}
+			return c[i].Code < c[j].Code
+		})
+	}
+
+	// walk appends a line and its sub-lines and returns the line's rolled-up net debit.
+	var walk func(l StatementLayoutLine, level int) decimal.Decimal
+	walk = func(l StatementLayoutLine, level int) decimal.Decimal {
+		i := len(report.Lines)
+		report.Lines = append(report.Lines, LayoutStatementLine{
+			Code:      l.Code,
+			Label:     l.Label,
+			Level:     level,
+			IsHeading: len(children[l.Code]) > 0,
+			Accounts:  lineAccounts[l.Code],
+		})
+		net := lineNet[l.Code]
+		for _, c := range children[l.Code] {
+			net = net.Add(walk(c, level+1))
+		}
+		report.Lines[i].Amount = signed(net, l.NormalBalance)
+		return net
+	}
+	total := decimal.Zero
+	for _, l := range children[""] {
+		total = total.Add(walk(l, 0))
+	}
+	report.IsBalanced = layout.Statement == StatementBalanceSheet && total.IsZero()
+	return report
+}
@@ -0,0 +1,154 @@
+package core_test
+
+import (
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+// layoutAccounts is a small chart with a current-assets group holding cash and a bank
+// group, and receivables left at the top level.
+func layoutAccounts() []core.AccountNode {
+	return []core.AccountNode{
+		{Code: "1000", Name: "Cash", Type: core.Asset, ParentCode: "CA"},
+		{Code: "1100", Name: "HDFC Current", Type: core.Asset, ParentCode: "BANK"},
+		{Code: "1110", Name: "ICICI Current", Type: core.Asset, ParentCode: "BANK"},
+		{Code: "1200", Name: "Accounts Receivable", Type: core.Asset},
+		{Code: "2000", Name: "Accounts Payable", Type: core.Liability},
+		{Code: "3000", Name: "Share Capital", Type: core.Equity},
+		{Code: "4000", Name: "Sales", Type: core.Revenue},
+		{Code: "5100", Name: "Rent", Type: core.Expense},
+		{Code: "BANK", Name: "Bank Accounts", Type: core.Asset, ParentCode: "CA", IsGroup: true},
+		{Code: "CA", Name: "Current Assets", Type: core.Asset, IsGroup: true},
+	}
+}
+
+func TestBuildAccountTree(t *testing.T) {
+	accounts := layoutAccounts()
+	for i, bal := range []string{"100", "200", "50", "300"} {
+		accounts[i].Balance = decimal.RequireFromString(bal)
+	}
+	tree := core.BuildAccountTree(accounts)
+
+	var order []string
+	for _, n := range tree {
+		order = append(order, n.Code)
+	}
+	want := []string{"1200", "2000", "3000", "4000", "5100", "CA", "1000", "BANK", "1100", "1110"}
+	if len(order) != len(want) {
+		t.Fatalf("order: got %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order: got %v, want %v", order, want)
+		}
+	}
+
+	byCode := map[string]core.AccountNode{}
+	for _, n := range tree {
+		byCode[n.Code] = n
+	}
+	if n := byCode["CA"]; n.Level != 0 || n.Rollup.StringFixed(2) != "350.00" {
+		t.Errorf("CA: got level %d rollup %s, want 0 and 350.00", n.Level, n.Rollup)
+	}
+	if n := byCode["BANK"]; n.Level != 1 || n.Rollup.StringFixed(2) != "250.00" {
+		t.Errorf("BANK: got level %d rollup %s, want 1 and 250.00", n.Level, n.Rollup)
+	}
+	if n := byCode["1100"]; n.Level != 2 || !n.Rollup.Equal(n.Balance) {
+		t.Errorf("1100: got level %d rollup %s", n.Level, n.Rollup)
+	}
+}
+
+func TestBuildLayoutStatement_BalanceSheet(t *testing.T) {
+	d := decimal.RequireFromString
+	layout := core.StatementLayout{
+		Code: "BS", Statement: core.StatementBalanceSheet,
+		Lines: []core.StatementLayoutLine{
+			{Code: "A", Label: "ASSETS", SortOrder: 20, NormalBalance: "DEBIT"},
+			{Code: "A2", ParentCode: "A", Label: "Current assets", NormalBalance: "DEBIT"},
+			{Code: "A2B", ParentCode: "A2", Label: "Trade receivables", SortOrder: 10, NormalBalance: "DEBIT"},
+			{Code: "A2C", ParentCode: "A2", Label: "Cash and cash equivalents", SortOrder: 20, NormalBalance: "DEBIT"},
+			{Code: "EL", Label: "EQUITY AND LIABILITIES", SortOrder: 10, NormalBalance: "CREDIT"},
+			{Code: "EL1", ParentCode: "EL", Label: "Shareholders' funds", SortOrder: 10, NormalBalance: "CREDIT", IncludesProfit: true},
+			{Code: "EL3", ParentCode: "EL", Label: "Current liabilities", SortOrder: 20, NormalBalance: "CREDIT"},
+		},
+		Accounts: []core.StatementLayoutAccount{
+			{AccountCode: "CA", LineCode: "A2C"}, // the group maps cash and both banks…
+			{AccountCode: "1200", LineCode: "A2B"},
+			{AccountCode: "3000", LineCode: "EL1"},
+			{AccountCode: "2000", LineCode: "EL3"},
+		},
+	}
+	nets := map[string]decimal.Decimal{
+		"1000": d("100"), "1100": d("200"), "1110": d("50"), "1200": d("300"),
+		"2000": d("-150"), "3000": d("-400"), "4000": d("-250"), "5100": d("150"),
+	}
+	r := core.BuildLayoutStatement(layout, layoutAccounts(), nets, d("100"))
+
+	var codes []string
+	amounts := map[string]string{}
+	for _, l := range r.Lines {
+		codes = append(codes, l.Code)
+		amounts[l.Code] = l.Amount.StringFixed(2)
+	}
+	if len(codes) != 7 || codes[0] != "EL" || codes[3] != "A" || codes[5] != "A2B" {
+		t.Errorf("line order: got %v", codes)
+	}
+	for code, want := range map[string]string{
+		"A": "650.00", "A2C": "350.00", "A2B": "300.00",
+		"EL1": "500.00", // capital 400 + profit 100
+		"EL3": "150.00", "EL": "650.00",
+	} {
+		if amounts[code] != want {
+			t.Errorf("%s: got %s, want %s", code, amounts[code], want)
+		}
+	}
+	if !r.IsBalanced {
+		t.Error("expected the statement to balance with the profit included")
+	}
+	if len(r.Unmapped) != 0 {
+		t.Errorf("unexpected unmapped accounts: %+v", r.Unmapped)
+	}
+
+	// …unless the bank group is mapped elsewhere itself; unmapping payables unbalances it.
+	layout.Accounts = append(layout.Accounts[:3], core.StatementLayoutAccount{AccountCode: "BANK", LineCode: "A2B"})
+	r = core.BuildLayoutStatement(layout, layoutAccounts(), nets, d("100"))
+	for _, l := range r.Lines {
+		if l.Code == "A2B" && l.Amount.StringFixed(2) != "550.00" {
+			t.Errorf("A2B with banks: got %s, want 550.00", l.Amount.StringFixed(2))
+		}
+	}
+	if r.IsBalanced || len(r.Unmapped) != 1 || r.Unmapped[0].Code != "2000" || r.Unmapped[0].Balance.StringFixed(2) != "150.00" {
+		t.Errorf("expected 2000 unmapped (150.00) and unbalanced: %+v %v", r.Unmapped, r.IsBalanced)
+	}
+}
+
+func TestBuildLayoutStatement_ProfitAndLoss(t *testing.T) {
+	d := decimal.RequireFromString
+	layout := core.StatementLayout{
+		Code: "PL", Statement: core.StatementProfitLoss,
+		Lines: []core.StatementLayoutLine{
+			{Code: "I", Label: "Revenue from operations", SortOrder: 10, NormalBalance: "CREDIT"},
+			{Code: "IV", Label: "Expenses", SortOrder: 30, NormalBalance: "DEBIT"},
+			{Code: "IVF", ParentCode: "IV", Label: "Other expenses", NormalBalance: "DEBIT"},
+		},
+		Accounts: []core.StatementLayoutAccount{
+			{AccountCode: "4000", LineCode: "I"},
+			{AccountCode: "5100", LineCode: "IVF"},
+		},
+	}
+	nets := map[string]decimal.Decimal{"1000": d("100"), "4000": d("-250"), "5100": d("150")}
+	r := core.BuildLayoutStatement(layout, layoutAccounts(), nets, d("100"))
+
+	if len(r.Lines) != 3 || r.Lines[0].Amount.StringFixed(2) != "250.00" || r.Lines[1].Amount.StringFixed(2) != "150.00" {
+		t.Errorf("lines: got %+v", r.Lines)
+	}
+	if !r.Lines[1].IsHeading || r.Lines[2].Level != 1 || r.Lines[2].Accounts[0].Code != "5100" {
+		t.Errorf("expenses heading: got %+v", r.Lines[1:])
+	}
+	if r.IsBalanced || !r.Profit.Equal(d("100")) {
+		t.Errorf("P&L: got balanced %v profit %s", r.IsBalanced, r.Profit)
+	}
+}
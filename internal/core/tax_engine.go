@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type taxEngine struct {
+	pool *pgxpool.Pool
+}
+
+// NewTaxEngine constructs a TaxEngine backed by the tax_codes and tax_code_rates tables.
+func NewTaxEngine(pool *pgxpool.Pool) TaxEngine {
+	return &taxEngine{pool: pool}
+}
+
+// GetTaxCodes returns the company's active tax codes with the rates in effect on asOf.
+func (e *taxEngine) GetTaxCodes(ctx context.Context, companyCode string, asOf time.Time) ([]TaxCode, error) {
+	rows, err := e.pool.Query(ctx, `
+		SELECT tc.code, tc.description, tc.is_active,
+		       r.component, r.rate, r.effective_from::text, r.effective_to::text
+		FROM tax_codes tc
+		JOIN companies c ON c.id = tc.company_id
+		LEFT JOIN tax_code_rates r
+		       ON r.tax_code_id = tc.id
+		      AND r.effective_from <= $2 AND (r.effective_to IS NULL OR r.effective_to >= $2)
+		WHERE c.company_code = $1 AND tc.is_active
+		ORDER BY tc.code, r.component`,
+		companyCode, asOf.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query tax codes: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []TaxCode
+	for rows.Next() {
+		var tc TaxCode
+		var component, from *string
+		var rate *decimal.Decimal
+		var to *string
+		if err := rows.Scan(&tc.Code, &tc.Description, &tc.IsActive, &component, &rate, &from, &to); err != nil {
+			return nil, fmt.Errorf("scan tax code: %w", err)
+		}
+		if n := len(codes); n == 0 || codes[n-1].Code != tc.Code {
+			codes = append(codes, tc)
+		}
+		if component != nil {
+			last := &codes[len(codes)-1]
+			last.Rates = append(last.Rates, TaxRate{Component: *component, Rate: *rate, EffectiveFrom: *from, EffectiveTo: to})
+		}
+	}
+	return codes, rows.Err()
+}
+
+// SetTaxRate sets a component rate from effectiveFrom, ending the open rate it replaces.
+func (e *taxEngine) SetTaxRate(ctx context.Context, companyCode, taxCode, component string, rate decimal.Decimal, effectiveFrom time.Time) error {
+	taxCode = strings.ToUpper(strings.TrimSpace(taxCode))
+	component = strings.ToUpper(strings.TrimSpace(component))
+	if component != GSTComponentCGST && component != GSTComponentSGST && component != GSTComponentIGST {
+		return fmt.Errorf("invalid GST component %q: expected CGST, SGST or IGST", component)
+	}
+	if rate.IsNegative() {
+		return fmt.Errorf("tax rate cannot be negative")
+	}
+	from := effectiveFrom.Format("2006-01-02")
+
+	tx, err := e.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var taxCodeID int
+	if err := tx.QueryRow(ctx, `
+		SELECT tc.id
+		FROM tax_codes tc
+		JOIN companies c ON c.id = tc.company_id
+		WHERE c.company_code = $1 AND tc.code = $2
+		FOR UPDATE OF tc`,
+		companyCode, taxCode,
+	).Scan(&taxCodeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("tax code %s not found for company %s", taxCode, companyCode)
+		}
+		return fmt.Errorf("fetch tax code %s: %w", taxCode, err)
+	}
+
+	var later *string
+	if err := tx.QueryRow(ctx, `
+		SELECT MIN(effective_from)::text FROM tax_code_rates
+		WHERE tax_code_id = $1 AND component = $2 AND effective_from > $3`,
+		taxCodeID, component, from,
+	).Scan(&later); err != nil {
+		return fmt.Errorf("check later %s rates: %w", component, err)
+	}
+	if later != nil {
+		return fmt.Errorf("%s %s already has a rate effective from %s", taxCode, component, *later)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE tax_code_rates
+		SET effective_to = $3::date - 1
+		WHERE tax_code_id = $1 AND component = $2 AND effective_from < $3
+		  AND (effective_to IS NULL OR effective_to >= $3)`,
+		taxCodeID, component, from,
+	); err != nil {
+		return fmt.Errorf("end current %s rate: %w", component, err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO tax_code_rates (tax_code_id, component, rate, effective_from)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tax_code_id, component, effective_from) DO UPDATE SET rate = EXCLUDED.rate`,
+		taxCodeID, component, rate, from,
+	); err != nil {
+		return fmt.Errorf("insert %s rate: %w", component, err)
+	}
+	return tx.Commit(ctx)
+}
+
+// SetCompanyGST sets the company's GST state code and GSTIN.
+func (e *taxEngine) SetCompanyGST(ctx context.Context, companyCode, stateCode, gstin string) error {
+	state, reg, err := gstRegistration(stateCode, gstin)
+	if err != nil {
+		return err
+	}
+	tag, err := e.pool.Exec(ctx,
+		"UPDATE companies SET state_code = $2, gstin = $3 WHERE company_code = $1",
+		companyCode, state, reg,
+	)
+	if err != nil {
+		return fmt.Errorf("update GST registration of company %s: %w", companyCode, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("company %s not found", companyCode)
+	}
+	return nil
+}
+
+// ComputeTax returns the GST on taxable under taxCode at the rates in effect on date.
+func (e *taxEngine) ComputeTax(ctx context.Context, companyID int, taxCode string, taxable decimal.Decimal, date time.Time, interState bool) ([]LineTax, error) {
+	taxCode = strings.ToUpper(strings.TrimSpace(taxCode))
+	rows, err := e.pool.Query(ctx, `
+		SELECT r.component, r.rate
+		FROM tax_codes tc
+		JOIN tax_code_rates r ON r.tax_code_id = tc.id
+		WHERE tc.company_id = $1 AND tc.code = $2 AND tc.is_active
+		  AND r.effective_from <= $3 AND (r.effective_to IS NULL OR r.effective_to >= $3)`,
+		companyID, taxCode, date.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query rates of tax code %s: %w", taxCode, err)
+	}
+	defer rows.Close()
+
+	rates := map[string]decimal.Decimal{}
+	for rows.Next() {
+		var component string
+		var rate decimal.Decimal
+		if err := rows.Scan(&component, &rate); err != nil {
+			return nil, fmt.Errorf("scan tax rate: %w", err)
+		}
+		rates[component] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query rates of tax code %s: %w", taxCode, err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("tax code %s is unknown, inactive or has no rate on %s", taxCode, date.Format("2006-01-02"))
+	}
+	return ComputeGST(taxCode, rates, taxable, interState)
+}
+
+// ── Document helpers ──────────────────────────────────────────────────────────
+
+// gstSupply taxes the lines of one sales or purchase document. Whether the supply is
+// inter-state is resolved from the company's and the party's state codes on first use,
+// so documents without taxed lines need no GST registration.
+type gstSupply struct {
+	engine     TaxEngine
+	q          pgxQuerier
+	companyID  int
+	date       time.Time
+	party      string  // e.g. "customer C001", for error messages
+	partyState *string // the customer's or vendor's state code
+	resolved   bool
+	interState bool
+}
+
+func newGSTSupply(engine TaxEngine, q pgxQuerier, companyID int, date time.Time, party string, partyState *string) *gstSupply {
+	return &gstSupply{engine: engine, q: q, companyID: companyID, date: date, party: party, partyState: partyState}
+}
+
+// lineTax returns the GST components on a line's taxable amount; none when taxCode is empty.
+func (g *gstSupply) lineTax(ctx context.Context, taxCode string, taxable decimal.Decimal) ([]LineTax, error) {
+	if taxCode == "" {
+		return nil, nil
+	}
+	if !g.resolved {
+		var companyState *string
+		if err := g.q.QueryRow(ctx,
+			"SELECT state_code FROM companies WHERE id = $1", g.companyID,
+		).Scan(&companyState); err != nil {
+			return nil, fmt.Errorf("fetch company GST state: %w", err)
+		}
+		if companyState == nil {
+			return nil, fmt.Errorf("company has no GST state code: set its GST registration before charging tax")
+		}
+		if g.partyState == nil {
+			return nil, fmt.Errorf("%s has no GST state code: cannot tell an intra-state from an inter-state supply", g.party)
+		}
+		g.interState = *companyState != *g.partyState
+		g.resolved = true
+	}
+	return g.engine.ComputeTax(ctx, g.companyID, taxCode, taxable, g.date, g.interState)
+}
+
+// Source line columns of line_taxes.
+const (
+	lineTaxSalesOrderLine     = "sales_order_line_id"
+	lineTaxVendorInvoiceLine  = "vendor_invoice_line_id"
+	lineTaxVendorBillLine     = "vendor_bill_line_id"
+	lineTaxPurchaseReturnLine = "purchase_return_line_id"
+)
+
+// insertLineTaxesTx records the GST components of one document line; column is one of
+// the lineTax* source columns.
+func insertLineTaxesTx(ctx context.Context, tx pgx.Tx, companyID int, column string, lineID int, taxes []LineTax) error {
+	for _, t := range taxes {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO line_taxes (company_id, `+column+`, tax_code, component, rate, taxable_amount, tax_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			companyID, lineID, t.TaxCode, t.Component, t.Rate, t.TaxableAmount, t.TaxAmount,
+		); err != nil {
+			return fmt.Errorf("record %s on line %d: %w", t.Component, lineID, err)
+		}
+	}
+	return nil
+}
+
+// fetchLineTaxesQ returns the GST components recorded against the given source lines,
+// keyed by line ID.
+func fetchLineTaxesQ(ctx context.Context, q pgxRowQuerier, column string, lineIDs []int) (map[int][]LineTax, error) {
+	taxes := map[int][]LineTax{}
+	if len(lineIDs) == 0 {
+		return taxes, nil
+	}
+	rows, err := q.Query(ctx, `
+		SELECT `+column+`, tax_code, component, rate, taxable_amount, tax_amount
+		FROM line_taxes
+		WHERE `+column+` = ANY($1)
+		ORDER BY id`,
+		lineIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query line taxes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lineID int
+		var t LineTax
+		if err := rows.Scan(&lineID, &t.TaxCode, &t.Component, &t.Rate, &t.TaxableAmount, &t.TaxAmount); err != nil {
+			return nil, fmt.Errorf("scan line tax: %w", err)
+		}
+		taxes[lineID] = append(taxes[lineID], t)
+	}
+	return taxes, rows.Err()
+}
+
+// gstProposalLines sums taxes per component into journal lines: debits to GST_INPUT_*
+// for input tax credit, credits to GST_OUTPUT_* for tax on sales. Zero components are
+// left out.
+func gstProposalLines(ctx context.Context, ruleEngine RuleEngine, companyID int, taxes []LineTax, input bool) ([]ProposalLine, error) {
+	byComponent := map[string]decimal.Decimal{}
+	for _, t := range taxes {
+		byComponent[t.Component] = byComponent[t.Component].Add(t.TaxAmount)
+	}
+	var lines []ProposalLine
+	for _, c := range []string{GSTComponentCGST, GSTComponentSGST, GSTComponentIGST} {
+		amount := byComponent[c]
+		if !amount.IsPositive() {
+			continue
+		}
+		account, err := ruleEngine.ResolveAccount(ctx, companyID, gstRuleType(c, input))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, ProposalLine{AccountCode: account, IsDebit: input, Amount: amount.StringFixed(2)})
+	}
+	return lines, nil
+}
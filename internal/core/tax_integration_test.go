@@ -0,0 +1,273 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// seedGST registers company 1000 in Karnataka (29) and seeds the GST18 tax code with its
+// posting accounts, mirroring migration 042 for the truncated test company.
+func seedGST(t *testing.T, ctx context.Context, pool *pgxpool.Pool) core.TaxEngine {
+	t.Helper()
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '1510', 'Input CGST Credit',   'asset'),
+		(1, '1520', 'Input SGST Credit',   'asset'),
+		(1, '1530', 'Input IGST Credit',   'asset'),
+		(1, '2310', 'Output CGST Payable', 'liability'),
+		(1, '2320', 'Output SGST Payable', 'liability'),
+		(1, '2330', 'Output IGST Payable', 'liability')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO account_rules (company_id, rule_type, account_code) VALUES
+		(1, 'GST_INPUT_CGST',  '1510'),
+		(1, 'GST_INPUT_SGST',  '1520'),
+		(1, 'GST_INPUT_IGST',  '1530'),
+		(1, 'GST_OUTPUT_CGST', '2310'),
+		(1, 'GST_OUTPUT_SGST', '2320'),
+		(1, 'GST_OUTPUT_IGST', '2330')
+		ON CONFLICT DO NOTHING;
+
+		INSERT INTO tax_codes (company_id, code, description) VALUES (1, 'GST18', 'GST 18%')
+		ON CONFLICT (company_id, code) DO NOTHING;
+	`)
+	if err != nil {
+		t.Fatalf("seed GST test data: %v", err)
+	}
+
+	taxEngine := core.NewTaxEngine(pool)
+	gstFrom := time.Date(2017, 7, 1, 0, 0, 0, 0, time.UTC)
+	for component, rate := range map[string]int64{"CGST": 9, "SGST": 9, "IGST": 18} {
+		if err := taxEngine.SetTaxRate(ctx, "1000", "GST18", component, decimal.NewFromInt(rate), gstFrom); err != nil {
+			t.Fatalf("SetTaxRate %s: %v", component, err)
+		}
+	}
+	if err := taxEngine.SetCompanyGST(ctx, "1000", "", "29AAPFU0939F1ZR"); err != nil {
+		t.Fatalf("SetCompanyGST: %v", err)
+	}
+	return taxEngine
+}
+
+func gstBalances(t *testing.T, ctx context.Context, ledger *core.Ledger) map[string]string {
+	t.Helper()
+	balances, err := ledger.GetBalances(ctx, "1000")
+	if err != nil {
+		t.Fatalf("GetBalances: %v", err)
+	}
+	return balanceMap(balances)
+}
+
+func TestGST_SalesOrderIntraAndInterState(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedGST(t, ctx, pool)
+
+	// C001 is in Karnataka like the company; C002 is in Maharashtra.
+	if _, err := pool.Exec(ctx, `
+		UPDATE customers SET state_code = '29' WHERE company_id = 1 AND code = 'C001';
+		UPDATE customers SET state_code = '27' WHERE company_id = 1 AND code = 'C002';
+	`); err != nil {
+		t.Fatalf("set customer states: %v", err)
+	}
+	if _, err := orderSvc.SetProductTax(ctx, "1000", "P001", "8471", "GST18"); err != nil {
+		t.Fatalf("SetProductTax: %v", err)
+	}
+
+	invoice := func(t *testing.T, customer string) *core.SalesOrder {
+		t.Helper()
+		// 10 × Widget A @ 500 = 5000 taxable, 900 GST
+		order, err := orderSvc.CreateOrder(ctx, "1000", customer, "INR", decimal.NewFromInt(1), "2026-02-01",
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(10)}}, "GST order")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if !order.TotalTransaction.Equal(decimal.NewFromInt(5900)) {
+			t.Errorf("expected total 5900 including GST, got %s", order.TotalTransaction)
+		}
+		if !order.TaxTransaction.Equal(decimal.NewFromInt(900)) {
+			t.Errorf("expected GST 900, got %s", order.TaxTransaction)
+		}
+		if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, nil); err != nil {
+			t.Fatalf("ConfirmOrder: %v", err)
+		}
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, nil, nil, nil); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+		order, err = orderSvc.InvoiceOrder(ctx, order.ID, ledger, docSvc)
+		if err != nil {
+			t.Fatalf("InvoiceOrder: %v", err)
+		}
+		return order
+	}
+
+	t.Run("IntraState_CGSTAndSGST", func(t *testing.T) {
+		order := invoice(t, "C001")
+		if len(order.Lines) != 1 || len(order.Lines[0].Taxes) != 2 {
+			t.Fatalf("expected CGST and SGST on the line, got %+v", order.Lines)
+		}
+		bm := gstBalances(t, ctx, ledger)
+		if bm["2310"] != "-450.00" || bm["2320"] != "-450.00" {
+			t.Errorf("expected CGST/SGST payable 450 each, got %s / %s", bm["2310"], bm["2320"])
+		}
+		if bm["1200"] != "5900.00" {
+			t.Errorf("expected AR 5900.00, got %s", bm["1200"])
+		}
+	})
+
+	t.Run("InterState_IGST", func(t *testing.T) {
+		order := invoice(t, "C002")
+		if len(order.Lines[0].Taxes) != 1 || order.Lines[0].Taxes[0].Component != core.GSTComponentIGST {
+			t.Fatalf("expected IGST on the line, got %+v", order.Lines[0].Taxes)
+		}
+		bm := gstBalances(t, ctx, ledger)
+		if bm["2330"] != "-900.00" {
+			t.Errorf("expected IGST payable 900, got %s", bm["2330"])
+		}
+		if bm["4000"] != "-10000.00" {
+			t.Errorf("expected revenue net of GST -10000.00, got %s", bm["4000"])
+		}
+	})
+
+	t.Run("CustomerWithoutState_Rejected", func(t *testing.T) {
+		if _, err := pool.Exec(ctx, `UPDATE customers SET state_code = NULL WHERE company_id = 1 AND code = 'C002'`); err != nil {
+			t.Fatalf("clear customer state: %v", err)
+		}
+		if _, err := orderSvc.CreateOrder(ctx, "1000", "C002", "INR", decimal.NewFromInt(1), "2026-02-01",
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(1)}}, ""); err == nil {
+			t.Error("expected error taxing a customer without a GST state code")
+		}
+	})
+}
+
+func TestGST_VendorBillInputCredit(t *testing.T) {
+	pool, _, ledger, docService, _, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+	taxEngine := seedGST(t, ctx, pool)
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES (1, '5200', 'Office Expense', 'expense')
+		ON CONFLICT (company_id, code) DO NOTHING;
+		UPDATE vendors SET state_code = '27' WHERE company_id = 1 AND code = 'V001';
+	`); err != nil {
+		t.Fatalf("seed vendor bill GST data: %v", err)
+	}
+
+	billSvc := core.NewVendorBillService(pool, core.NewRuleEngine(pool), taxEngine)
+	bill, err := billSvc.CreateBill(ctx, "1000", core.VendorBillInput{
+		VendorCode: "V001",
+		BillNumber: "GST-1",
+		BillDate:   "2026-02-01",
+		Lines: []core.VendorBillLineInput{{
+			Description: "Office supplies", UnitCost: decimal.NewFromInt(2000),
+			ExpenseAccountCode: "5200", TaxCode: "GST18",
+		}},
+	}, ledger, docService)
+	if err != nil {
+		t.Fatalf("CreateBill: %v", err)
+	}
+	if !bill.TaxAmount.Equal(decimal.NewFromInt(360)) || !bill.TotalAmount.Equal(decimal.NewFromInt(2360)) {
+		t.Errorf("expected GST 360 and total 2360, got %s and %s", bill.TaxAmount, bill.TotalAmount)
+	}
+
+	bm := gstBalances(t, ctx, ledger)
+	if bm["1530"] != "360.00" {
+		t.Errorf("expected IGST input credit 360.00, got %s", bm["1530"])
+	}
+	if bm["2000"] != "-2360.00" {
+		t.Errorf("expected AP -2360.00, got %s", bm["2000"])
+	}
+}
+
+func TestGST_ReturnReconcilesToLedger(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedGST(t, ctx, pool)
+
+	if _, err := pool.Exec(ctx, `
+		UPDATE customers SET state_code = '27', gstin = '27AAPFU0939F1ZV' WHERE company_id = 1 AND code = 'C001';
+		UPDATE customers SET state_code = '29' WHERE company_id = 1 AND code = 'C002';
+	`); err != nil {
+		t.Fatalf("set customer GST registration: %v", err)
+	}
+	if _, err := orderSvc.SetProductTax(ctx, "1000", "P001", "8471", "GST18"); err != nil {
+		t.Fatalf("SetProductTax: %v", err)
+	}
+	// Sales invoices are dated the day they are invoiced.
+	for _, customer := range []string{"C001", "C002"} {
+		order, err := orderSvc.CreateOrder(ctx, "1000", customer, "INR", decimal.NewFromInt(1), "2026-02-01",
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(2)}}, "")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, nil); err != nil {
+			t.Fatalf("ConfirmOrder: %v", err)
+		}
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, nil, nil, nil); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+		if _, err := orderSvc.InvoiceOrder(ctx, order.ID, ledger, docSvc); err != nil {
+			t.Fatalf("InvoiceOrder: %v", err)
+		}
+	}
+
+	now := time.Now()
+	svc := core.NewGSTReturnService(pool, core.NewRuleEngine(pool))
+	ret, err := svc.GetReturn(ctx, "1000", now.Year(), int(now.Month()))
+	if err != nil {
+		t.Fatalf("GetReturn: %v", err)
+	}
+	if len(ret.GSTR1.B2B) != 1 || ret.GSTR1.B2B[0].CTIN != "27AAPFU0939F1ZV" {
+		t.Errorf("expected the registered customer under B2B, got %+v", ret.GSTR1.B2B)
+	}
+	if len(ret.GSTR1.B2CS) != 1 || ret.GSTR1.B2CS[0].SupplyType != "INTRA" {
+		t.Errorf("expected one intra-state B2CS row, got %+v", ret.GSTR1.B2CS)
+	}
+	if !decimal.Decimal(ret.GSTR3B.SupDetails.Taxable.IGST).Equal(decimal.NewFromInt(180)) {
+		t.Errorf("expected IGST 180 in GSTR-3B, got %s", decimal.Decimal(ret.GSTR3B.SupDetails.Taxable.IGST))
+	}
+	if !ret.Reconciled {
+		t.Errorf("expected return to reconcile to the ledger: %+v", ret.Reconciliation)
+	}
+	if len(ret.Issues) != 0 {
+		t.Errorf("expected no issues, got %v", ret.Issues)
+	}
+
+	// A manual journal to output CGST is not on any invoice, so the return no longer reconciles.
+	today := now.Format("2006-01-02")
+	if err := ledger.Commit(ctx, core.Proposal{
+		DocumentTypeCode: "JE", CompanyCode: "1000", IdempotencyKey: "gst-manual-cgst",
+		TransactionCurrency: "INR", ExchangeRate: "1.0", PostingDate: today, DocumentDate: today,
+		Summary: "Manual CGST adjustment", Reasoning: "GST return reconciliation test",
+		Lines: []core.ProposalLine{
+			{AccountCode: "1000", IsDebit: true, Amount: "10.00"},
+			{AccountCode: "2310", IsDebit: false, Amount: "10.00"},
+		},
+	}); err != nil {
+		t.Fatalf("Commit manual CGST: %v", err)
+	}
+	ret, err = svc.GetReturn(ctx, "1000", now.Year(), int(now.Month()))
+	if err != nil {
+		t.Fatalf("GetReturn after adjustment: %v", err)
+	}
+	if ret.Reconciled {
+		t.Error("expected a mismatch after the manual CGST posting")
+	}
+	for _, l := range ret.Reconciliation {
+		if l.Direction == "OUTPUT" && l.Component == core.GSTComponentCGST && !l.Difference.Equal(decimal.NewFromInt(-10)) {
+			t.Errorf("expected CGST difference -10, got %s", l.Difference)
+		}
+	}
+
+	if _, err := pool.Exec(ctx, `UPDATE companies SET gstin = NULL WHERE id = 1`); err != nil {
+		t.Fatalf("clear company GSTIN: %v", err)
+	}
+	if _, err := svc.GetReturn(ctx, "1000", now.Year(), int(now.Month())); err == nil {
+		t.Error("expected error building a return for a company without a GSTIN")
+	}
+}
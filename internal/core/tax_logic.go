@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// gstinPattern is the GSTIN layout: state code, PAN, entity number, 'Z', check character.
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+const gstinCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ValidateStateCode checks a two-digit GST state code: 01–38, or 97 (other territory).
+func ValidateStateCode(code string) error {
+	n, err := strconv.Atoi(code)
+	if err != nil || len(code) != 2 || n < 1 || (n > 38 && n != 97) {
+		return fmt.Errorf("invalid GST state code %q: expected two digits, 01-38 or 97", code)
+	}
+	return nil
+}
+
+// ValidateGSTIN checks a GSTIN's layout, state code and check character.
+func ValidateGSTIN(gstin string) error {
+	if !gstinPattern.MatchString(gstin) {
+		return fmt.Errorf("invalid GSTIN %q: expected 15 characters, e.g. 27AAPFU0939F1ZV", gstin)
+	}
+	if err := ValidateStateCode(gstin[:2]); err != nil {
+		return fmt.Errorf("invalid GSTIN %q: %w", gstin, err)
+	}
+	// Each of the first 14 characters is weighted 1, 2, 1, 2 …; the check character makes
+	// the sum of the base-36 digits of the products a multiple of 36.
+	sum := 0
+	for i := 0; i < 14; i++ {
+		p := strings.IndexByte(gstinCharset, gstin[i]) * (i%2 + 1)
+		sum += p/36 + p%36
+	}
+	if want := gstinCharset[(36-sum%36)%36]; gstin[14] != want {
+		return fmt.Errorf("invalid GSTIN %q: check character should be %c", gstin, want)
+	}
+	return nil
+}
+
+// gstRegistration normalises and validates a state code / GSTIN pair from master data.
+// Empty values are returned as nil. A GSTIN without a state code supplies it.
+func gstRegistration(stateCode, gstin string) (*string, *string, error) {
+	stateCode = strings.TrimSpace(stateCode)
+	gstin = strings.ToUpper(strings.TrimSpace(gstin))
+	if gstin != "" {
+		if err := ValidateGSTIN(gstin); err != nil {
+			return nil, nil, err
+		}
+		if stateCode == "" {
+			stateCode = gstin[:2]
+		} else if stateCode != gstin[:2] {
+			return nil, nil, fmt.Errorf("state code %s does not match GSTIN %s", stateCode, gstin)
+		}
+	}
+	if stateCode != "" {
+		if err := ValidateStateCode(stateCode); err != nil {
+			return nil, nil, err
+		}
+	}
+	toPtr := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	return toPtr(stateCode), toPtr(gstin), nil
+}
+
+// ComputeGST applies a tax code's component rates (percentages keyed by component) to
+// taxable: CGST and SGST for an intra-state supply, IGST for an inter-state one. Each
+// component is rounded to 2 decimal places; zero-rated components are still returned.
+func ComputeGST(taxCode string, rates map[string]decimal.Decimal, taxable decimal.Decimal, interState bool) ([]LineTax, error) {
+	components := []string{GSTComponentCGST, GSTComponentSGST}
+	if interState {
+		components = []string{GSTComponentIGST}
+	}
+	hundred := decimal.NewFromInt(100)
+	taxes := make([]LineTax, 0, len(components))
+	for _, c := range components {
+		rate, ok := rates[c]
+		if !ok {
+			return nil, fmt.Errorf("tax code %s has no %s rate", taxCode, c)
+		}
+		taxes = append(taxes, LineTax{
+			TaxCode:       taxCode,
+			Component:     c,
+			Rate:          rate,
+			TaxableAmount: taxable,
+			TaxAmount:     taxable.Mul(rate).Div(hundred).Round(2),
+		})
+	}
+	return taxes, nil
+}
+
+// ProrateGST scales a line's GST components to part of its quantity (qty of lineQty) and
+// converts them at rate, for reversing the tax on goods returned after invoicing. Each
+// component is rounded to 2 decimal places; qty above lineQty is capped at the whole line.
+func ProrateGST(taxes []LineTax, qty, lineQty, rate decimal.Decimal) []LineTax {
+	if !lineQty.IsPositive() {
+		return nil
+	}
+	share := decimal.Min(qty, lineQty).Div(lineQty)
+	prorated := make([]LineTax, 0, len(taxes))
+	for _, t := range taxes {
+		t.TaxableAmount = t.TaxableAmount.Mul(share).Mul(rate).Round(2)
+		t.TaxAmount = t.TaxAmount.Mul(share).Mul(rate).Round(2)
+		prorated = append(prorated, t)
+	}
+	return prorated
+}
+
+// sumTax returns the total tax of a line's components.
+func sumTax(taxes []LineTax) decimal.Decimal {
+	total := decimal.Zero
+	for _, t := range taxes {
+		total = total.Add(t.TaxAmount)
+	}
+	return total
+}
+
+// gstRuleType returns the account rule a GST component posts to: GST_INPUT_<component>
+// for input tax credit, GST_OUTPUT_<component> for tax payable on sales.
+func gstRuleType(component string, input bool) string {
+	if input {
+		return "GST_INPUT_" + component
+	}
+	return "GST_OUTPUT_" + component
+}
@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"testing"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestComputeGST(t *testing.T) {
+	rates := map[string]decimal.Decimal{
+		core.GSTComponentCGST: decimal.NewFromInt(9),
+		core.GSTComponentSGST: decimal.NewFromInt(9),
+		core.GSTComponentIGST: decimal.NewFromInt(18),
+	}
+
+	t.Run("IntraState_SplitsCGSTAndSGST", func(t *testing.T) {
+		taxes, err := core.ComputeGST("GST18", rates, decimal.RequireFromString("1000.55"), false)
+		if err != nil {
+			t.Fatalf("ComputeGST: %v", err)
+		}
+		if len(taxes) != 2 || taxes[0].Component != core.GSTComponentCGST || taxes[1].Component != core.GSTComponentSGST {
+			t.Fatalf("expected CGST and SGST, got %+v", taxes)
+		}
+		for _, tax := range taxes {
+			if !tax.TaxAmount.Equal(decimal.RequireFromString("90.05")) {
+				t.Errorf("%s: expected 90.05, got %s", tax.Component, tax.TaxAmount)
+			}
+		}
+	})
+
+	t.Run("InterState_ChargesIGST", func(t *testing.T) {
+		taxes, err := core.ComputeGST("GST18", rates, decimal.NewFromInt(1000), true)
+		if err != nil {
+			t.Fatalf("ComputeGST: %v", err)
+		}
+		if len(taxes) != 1 || taxes[0].Component != core.GSTComponentIGST {
+			t.Fatalf("expected IGST only, got %+v", taxes)
+		}
+		if !taxes[0].TaxAmount.Equal(decimal.NewFromInt(180)) {
+			t.Errorf("expected 180, got %s", taxes[0].TaxAmount)
+		}
+	})
+
+	t.Run("MissingComponent_Rejected", func(t *testing.T) {
+		if _, err := core.ComputeGST("GST18", map[string]decimal.Decimal{core.GSTComponentIGST: decimal.NewFromInt(18)},
+			decimal.NewFromInt(1000), false); err == nil {
+			t.Error("expected error for a tax code without CGST/SGST rates")
+		}
+	})
+}
+
+func TestValidateGSTIN(t *testing.T) {
+	cases := []struct {
+		gstin string
+		valid bool
+	}{
+		{"27AAPFU0939F1ZV", true},
+		{"27AAPFU0939F1ZW", false}, // wrong check character
+		{"27AAPFU0939F1Z", false},  // too short
+		{"99AAPFU0939F1ZV", false}, // unknown state
+		{"27aapfu0939f1zv", false}, // lower case
+	}
+	for _, c := range cases {
+		err := core.ValidateGSTIN(c.gstin)
+		if c.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", c.gstin, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected invalid", c.gstin)
+		}
+	}
+}
+
+func TestValidateStateCode(t *testing.T) {
+	for _, code := range []string{"01", "27", "38", "97"} {
+		if err := core.ValidateStateCode(code); err != nil {
+			t.Errorf("%s: expected valid, got %v", code, err)
+		}
+	}
+	for _, code := range []string{"", "0", "00", "39", "96", "270", "AB"} {
+		if err := core.ValidateStateCode(code); err == nil {
+			t.Errorf("%q: expected invalid", code)
+		}
+	}
+}
+
+func TestProrateGST(t *testing.T) {
+	taxes := []core.LineTax{
+		{TaxCode: "GST18", Component: core.GSTComponentCGST, Rate: decimal.NewFromInt(9), TaxableAmount: decimal.NewFromInt(500), TaxAmount: decimal.NewFromInt(45)},
+		{TaxCode: "GST18", Component: core.GSTComponentSGST, Rate: decimal.NewFromInt(9), TaxableAmount: decimal.NewFromInt(500), TaxAmount: decimal.NewFromInt(45)},
+	}
+	got := core.ProrateGST(taxes, decimal.NewFromInt(3), decimal.NewFromInt(10), decimal.NewFromInt(1))
+	if len(got) != 2 || got[0].TaxAmount.StringFixed(2) != "13.50" || got[1].TaxableAmount.StringFixed(2) != "150.00" {
+		t.Errorf("3 of 10: got %+v", got)
+	}
+	if taxes[0].TaxAmount.StringFixed(2) != "45.00" {
+		t.Errorf("input taxes modified: %+v", taxes[0])
+	}
+
+	// Converted at the PO rate; a quantity above the line is capped at the whole line.
+	got = core.ProrateGST(taxes[:1], decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.RequireFromString("1.5"))
+	if got[0].TaxAmount.StringFixed(2) != "67.50" {
+		t.Errorf("capped and converted: got %s, want 67.50", got[0].TaxAmount)
+	}
+	if core.ProrateGST(taxes, decimal.NewFromInt(1), decimal.Zero, decimal.NewFromInt(1)) != nil {
+		t.Error("expected nothing for a line with no quantity")
+	}
+}
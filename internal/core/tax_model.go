@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// GST components. An intra-state supply is taxed CGST + SGST, an inter-state supply IGST.
+const (
+	GSTComponentCGST = "CGST"
+	GSTComponentSGST = "SGST"
+	GSTComponentIGST = "IGST"
+)
+
+// TaxCode is a company's GST code (e.g. GST18) with the component rates in effect on the
+// date it was read for.
+type TaxCode struct {
+	Code        string
+	Description string
+	IsActive    bool
+	Rates       []TaxRate
+}
+
+// TaxRate is one component rate of a tax code over a date range. Rate is a percentage.
+type TaxRate struct {
+	Component     string // CGST | SGST | IGST
+	Rate          decimal.Decimal
+	EffectiveFrom string  // YYYY-MM-DD
+	EffectiveTo   *string // YYYY-MM-DD; nil = open-ended
+}
+
+// LineTax is one GST component charged on a document line.
+type LineTax struct {
+	TaxCode       string
+	Component     string // CGST | SGST | IGST
+	Rate          decimal.Decimal
+	TaxableAmount decimal.Decimal
+	TaxAmount     decimal.Decimal
+}
+
+// TaxEngine computes GST on sales and purchase lines from tax codes and their dated
+// component rates, and maintains the company's GST registration.
+// Posting accounts are resolved through RuleEngine: GST_OUTPUT_<component> for tax on
+// sales, GST_INPUT_<component> for input tax credit on purchases.
+type TaxEngine interface {
+	// GetTaxCodes returns the company's active tax codes, ordered by code, each with the
+	// rates in effect on asOf.
+	GetTaxCodes(ctx context.Context, companyCode string, asOf time.Time) ([]TaxCode, error)
+
+	// SetTaxRate sets a component rate of a tax code from effectiveFrom onwards. The rate it
+	// replaces ends the day before; earlier documents keep the rate of their date.
+	SetTaxRate(ctx context.Context, companyCode, taxCode, component string, rate decimal.Decimal, effectiveFrom time.Time) error
+
+	// SetCompanyGST sets the company's GST state code and GSTIN. An empty stateCode is taken
+	// from the GSTIN; when both are given they must agree.
+	SetCompanyGST(ctx context.Context, companyCode, stateCode, gstin string) error
+
+	// ComputeTax returns the GST on taxable under taxCode at the rates in effect on date:
+	// CGST and SGST for an intra-state supply, IGST for an inter-state one. Each component
+	// is rounded to 2 decimal places.
+	ComputeTax(ctx context.Context, companyID int, taxCode string, taxable decimal.Decimal, date time.Time, interState bool) ([]LineTax, error)
+}
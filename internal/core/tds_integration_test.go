@@ -0,0 +1,259 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTDS_WithheldOnVendorBillPayments(t *testing.T) {
+	pool, _, ledger, docService, _, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '2200', 'TDS Payable',            'liability'),
+		(1, '5200', 'Subcontracting Expense', 'expense')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO account_rules (company_id, rule_type, account_code)
+		VALUES (1, 'TDS_PAYABLE', '2200')
+		ON CONFLICT DO NOTHING;
+
+		INSERT INTO tds_sections (code, section, description, rate, single_payment_threshold, annual_threshold)
+		VALUES ('194C-OTH', '194C', 'Payments to contractors — others', 2, 30000, 100000)
+		ON CONFLICT (code) DO NOTHING;
+	`)
+	if err != nil {
+		t.Fatalf("seed TDS test data: %v", err)
+	}
+
+	companyCode := "1000"
+	ruleEngine := core.NewRuleEngine(pool)
+	vendorSvc := core.NewVendorService(pool)
+	billSvc := core.NewVendorBillService(pool, ruleEngine, core.NewTaxEngine(pool))
+	tdsSvc := core.NewTDSService(pool)
+
+	v, err := vendorSvc.GetVendorByCode(ctx, 1, "V001")
+	if err != nil {
+		t.Fatalf("GetVendorByCode: %v", err)
+	}
+	if _, err := vendorSvc.UpdateVendor(ctx, 1, "V001", v.Version, core.VendorInput{
+		Name: v.Name, APAccountCode: "2000", PaymentTermsDays: 30, PAN: "AAACT1234F", TDSSectionCode: "194C-OTH",
+	}); err != nil {
+		t.Fatalf("UpdateVendor: %v", err)
+	}
+
+	accountBalance := func(t *testing.T, code string) decimal.Decimal {
+		t.Helper()
+		var bal decimal.Decimal
+		if err := pool.QueryRow(ctx, `
+			SELECT COALESCE(SUM(jl.debit_base - jl.credit_base), 0)
+			FROM journal_lines jl
+			JOIN accounts a ON a.id = jl.account_id
+			WHERE a.company_id = 1 AND a.code = $1`, code,
+		).Scan(&bal); err != nil {
+			t.Fatalf("balance of %s: %v", code, err)
+		}
+		return bal
+	}
+	payBill := func(t *testing.T, n int, amount int64, paid time.Time) {
+		t.Helper()
+		bill, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+			VendorCode: "V001",
+			BillNumber: fmt.Sprintf("SUB-%d", n),
+			BillDate:   paid.Format("2006-01-02"),
+			Lines:      []core.VendorBillLineInput{{Description: "Subcontracted work", UnitCost: decimal.NewFromInt(amount), ExpenseAccountCode: "5200"}},
+		}, ledger, docService)
+		if err != nil {
+			t.Fatalf("CreateBill: %v", err)
+		}
+		if err := billSvc.PayBill(ctx, companyCode, bill.ID, "1000", paid, ledger); err != nil {
+			t.Fatalf("PayBill: %v", err)
+		}
+	}
+
+	t.Run("InvalidPAN_Rejected", func(t *testing.T) {
+		cur, err := vendorSvc.GetVendorByCode(ctx, 1, "V001")
+		if err != nil {
+			t.Fatalf("GetVendorByCode: %v", err)
+		}
+		if _, err := vendorSvc.UpdateVendor(ctx, 1, "V001", cur.Version, core.VendorInput{
+			Name: cur.Name, PAN: "12345", TDSSectionCode: "194C-OTH",
+		}); err == nil {
+			t.Error("expected error for a malformed PAN, got nil")
+		}
+	})
+
+	// 25,000 is below both 194C thresholds: paid in full.
+	payBill(t, 1, 25000, time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC))
+	if got := accountBalance(t, "2200"); !got.IsZero() {
+		t.Errorf("expected no TDS on the first payment, got TDS payable %s", got)
+	}
+
+	// 40,000 exceeds the 30,000 single-payment threshold: 2% withheld.
+	payBill(t, 2, 40000, time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC))
+	if got := accountBalance(t, "2200"); !got.Equal(decimal.NewFromInt(-800)) {
+		t.Errorf("expected TDS payable -800, got %s", got)
+	}
+	if got := accountBalance(t, "1000"); !got.Equal(decimal.NewFromInt(-64200)) {
+		t.Errorf("expected bank -64200 (25000 + 39200), got %s", got)
+	}
+
+	// 50,000 takes the year to 115,000, over the annual threshold: TDS also catches up on
+	// the untaxed 25,000 from the first payment — 2% of 75,000.
+	payBill(t, 3, 50000, time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC))
+	if got := accountBalance(t, "2200"); !got.Equal(decimal.NewFromInt(-2300)) {
+		t.Errorf("expected TDS payable -2300, got %s", got)
+	}
+	if got := accountBalance(t, "2000"); !got.IsZero() {
+		t.Errorf("AP should be cleared in full, got %s", got)
+	}
+
+	status, err := tdsSvc.GetVendorTDSStatus(ctx, companyCode, "V001", time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetVendorTDSStatus: %v", err)
+	}
+	if status.FinancialYear != "2026-27" || status.Payments != 3 ||
+		!status.CumulativePaid.Equal(decimal.NewFromInt(115000)) || !status.TDSDeducted.Equal(decimal.NewFromInt(2300)) ||
+		!status.AnnualThresholdCrossed {
+		t.Errorf("unexpected status: FY %s, %d payments, paid %s, TDS %s, crossed %t",
+			status.FinancialYear, status.Payments, status.CumulativePaid, status.TDSDeducted, status.AnnualThresholdCrossed)
+	}
+
+	t.Run("QuarterlyRegister", func(t *testing.T) {
+		q1, err := tdsSvc.GetRegister(ctx, companyCode, 2026, 1)
+		if err != nil {
+			t.Fatalf("GetRegister Q1: %v", err)
+		}
+		if len(q1.Deductions) != 1 || !q1.TotalTDS.Equal(decimal.NewFromInt(800)) {
+			t.Errorf("Q1: expected one deduction of 800, got %d totalling %s", len(q1.Deductions), q1.TotalTDS)
+		}
+		q2, err := tdsSvc.GetRegister(ctx, companyCode, 2026, 2)
+		if err != nil {
+			t.Fatalf("GetRegister Q2: %v", err)
+		}
+		if len(q2.Sections) != 1 || q2.Sections[0].SectionCode != "194C-OTH" || !q2.TotalTDS.Equal(decimal.NewFromInt(1500)) {
+			t.Errorf("Q2: expected 1500 under 194C-OTH, got %v", q2.Sections)
+		}
+		if len(q2.Deductions) == 1 && q2.Deductions[0].JournalDocumentNumber == nil {
+			t.Error("expected the deduction to reference its payment entry")
+		}
+	})
+
+	t.Run("NewFinancialYear_ResetsCumulative", func(t *testing.T) {
+		// 25,000 in April 2027 is below threshold again.
+		payBill(t, 4, 25000, time.Date(2027, 4, 5, 0, 0, 0, 0, time.UTC))
+		if got := accountBalance(t, "2200"); !got.Equal(decimal.NewFromInt(-2300)) {
+			t.Errorf("expected no new TDS in FY 2027-28, got TDS payable %s", got)
+		}
+	})
+
+	t.Run("MergedVendor_CumulativeIncludesDuplicate", func(t *testing.T) {
+		// A duplicate of V001 paid 20,000 in FY 2027-28; after the merge its deduction
+		// counts toward V001's cumulative for the year.
+		if _, err := vendorSvc.CreateVendor(ctx, 1, core.VendorInput{
+			Code: "V001-DUP", Name: "Test Supplier (dup)", APAccountCode: "2000", PaymentTermsDays: 30,
+			PAN: "AAACT1234F", TDSSectionCode: "194C-OTH",
+		}); err != nil {
+			t.Fatalf("CreateVendor: %v", err)
+		}
+		bill, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+			VendorCode: "V001-DUP",
+			BillNumber: "SUB-DUP-1",
+			BillDate:   "2027-05-10",
+			Lines:      []core.VendorBillLineInput{{Description: "Subcontracted work", UnitCost: decimal.NewFromInt(20000), ExpenseAccountCode: "5200"}},
+		}, ledger, docService)
+		if err != nil {
+			t.Fatalf("CreateBill: %v", err)
+		}
+		if err := billSvc.PayBill(ctx, companyCode, bill.ID, "1000", time.Date(2027, 5, 10, 0, 0, 0, 0, time.UTC), ledger); err != nil {
+			t.Fatalf("PayBill: %v", err)
+		}
+
+		result, err := vendorSvc.MergeVendors(ctx, 1, "V001-DUP", "V001")
+		if err != nil {
+			t.Fatalf("MergeVendors: %v", err)
+		}
+		if result.Repointed["tds_deductions"] != 1 {
+			t.Errorf("expected 1 TDS deduction repointed, got %v", result.Repointed)
+		}
+		status, err := tdsSvc.GetVendorTDSStatus(ctx, companyCode, "V001", time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("GetVendorTDSStatus: %v", err)
+		}
+		if status.Payments != 2 || !status.CumulativePaid.Equal(decimal.NewFromInt(45000)) {
+			t.Errorf("expected 2 payments totalling 45000 for V001 in FY 2027-28, got %d totalling %s",
+				status.Payments, status.CumulativePaid)
+		}
+	})
+}
+
+func TestTDS_ComputedOnValueExcludingGST(t *testing.T) {
+	pool, _, ledger, docService, _, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+	taxEngine := seedGST(t, ctx, pool)
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '2200', 'TDS Payable',            'liability'),
+		(1, '5200', 'Subcontracting Expense', 'expense')
+		ON CONFLICT (company_id, code) DO NOTHING;
+
+		INSERT INTO account_rules (company_id, rule_type, account_code)
+		VALUES (1, 'TDS_PAYABLE', '2200')
+		ON CONFLICT DO NOTHING;
+
+		INSERT INTO tds_sections (code, section, description, rate, single_payment_threshold, annual_threshold)
+		VALUES ('194C-OTH', '194C', 'Payments to contractors — others', 2, 30000, 100000)
+		ON CONFLICT (code) DO NOTHING;
+
+		UPDATE vendors SET state_code = '29', pan = 'AAACT1234F', tds_section_code = '194C-OTH'
+		WHERE company_id = 1 AND code = 'V001';
+	`)
+	if err != nil {
+		t.Fatalf("seed TDS test data: %v", err)
+	}
+
+	billSvc := core.NewVendorBillService(pool, core.NewRuleEngine(pool), taxEngine)
+	// 40,000 of subcontracted work plus 18% GST: the vendor is owed 47,200.
+	bill, err := billSvc.CreateBill(ctx, "1000", core.VendorBillInput{
+		VendorCode: "V001",
+		BillNumber: "SUB-GST-1",
+		BillDate:   "2026-05-10",
+		Lines: []core.VendorBillLineInput{{
+			Description: "Subcontracted work", UnitCost: decimal.NewFromInt(40000), ExpenseAccountCode: "5200", TaxCode: "GST18",
+		}},
+	}, ledger, docService)
+	if err != nil {
+		t.Fatalf("CreateBill: %v", err)
+	}
+	if !bill.TotalAmount.Equal(decimal.NewFromInt(47200)) {
+		t.Fatalf("expected bill total 47200 including GST, got %s", bill.TotalAmount)
+	}
+	if err := billSvc.PayBill(ctx, "1000", bill.ID, "1000", time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC), ledger); err != nil {
+		t.Fatalf("PayBill: %v", err)
+	}
+
+	// 2% of the 40,000 taxable value, not of the 47,200 payable.
+	bm := gstBalances(t, ctx, ledger)
+	if bm["2200"] != "-800.00" {
+		t.Errorf("expected TDS payable -800.00, got %s", bm["2200"])
+	}
+	if bm["1000"] != "-46400.00" {
+		t.Errorf("expected bank -46400.00 (47200 less 800 TDS), got %s", bm["1000"])
+	}
+	var gross decimal.Decimal
+	if err := pool.QueryRow(ctx,
+		"SELECT gross_amount FROM tds_deductions WHERE bill_id = $1", bill.ID,
+	).Scan(&gross); err != nil {
+		t.Fatalf("fetch TDS deduction: %v", err)
+	}
+	if !gross.Equal(decimal.NewFromInt(40000)) {
+		t.Errorf("expected the deduction recorded on 40000 excluding GST, got %s", gross)
+	}
+}
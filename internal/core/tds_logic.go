@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FinancialYear returns the year the Indian financial year containing t starts in:
+// 1 April 2026 to 31 March 2027 is financial year 2026.
+func FinancialYear(t time.Time) int {
+	if t.Month() < time.April {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// FinancialYearLabel formats a financial year as "2026-27".
+func FinancialYearLabel(fy int) string {
+	return fmt.Sprintf("%d-%02d", fy, (fy+1)%100)
+}
+
+// FinancialQuarter returns the first and last day of a quarter (1–4) of the financial year
+// starting in fy: Q1 is April–June, Q4 is January–March of the following year.
+func FinancialQuarter(fy, quarter int) (from, to time.Time, err error) {
+	if quarter < 1 || quarter > 4 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid quarter %d (expected 1-4)", quarter)
+	}
+	from = time.Date(fy, time.April, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 3*(quarter-1), 0)
+	to = from.AddDate(0, 3, -1)
+	return from, to, nil
+}
+
+// ExcludeGST returns the part of a payment of amount against a document that is not GST:
+// amount less the document's tax in proportion to amount's share of the document total.
+// TDS is computed on this pre-GST value.
+func ExcludeGST(amount, documentTotal, documentTax decimal.Decimal) decimal.Decimal {
+	if !documentTax.IsPositive() || !documentTotal.IsPositive() {
+		return amount
+	}
+	return amount.Sub(amount.Mul(documentTax).Div(documentTotal).Round(2))
+}
+
+// ComputeTDS works out the withholding on a payment of gross (excluding GST) to a vendor
+// under section.
+// priorGross and priorTaxable are the vendor's payments earlier in the financial year and
+// the part of them TDS was already computed on.
+//
+// No TDS is withheld until a threshold is crossed: the payment alone exceeds the
+// single-payment threshold, or the year's payments including this one exceed the annual
+// threshold. Crossing the annual threshold also brings the earlier untaxed payments of the
+// year into the taxable amount. The withholding never exceeds the payment itself.
+func ComputeTDS(section TDSSection, hasPAN bool, priorGross, priorTaxable, gross decimal.Decimal) TDSComputation {
+	c := TDSComputation{GrossAmount: gross, TaxableAmount: decimal.Zero, Rate: section.Rate, TDSAmount: decimal.Zero}
+	if !hasPAN && section.NoPANRate.GreaterThan(c.Rate) {
+		c.Rate = section.NoPANRate
+	}
+	if !gross.IsPositive() {
+		return c
+	}
+
+	singleCrossed := section.SinglePaymentThreshold.IsPositive() && gross.GreaterThan(section.SinglePaymentThreshold)
+	annualCrossed := section.AnnualThreshold.IsPositive() && priorGross.Add(gross).GreaterThan(section.AnnualThreshold)
+	if !singleCrossed && !annualCrossed {
+		return c
+	}
+
+	c.ThresholdCrossed = true
+	c.TaxableAmount = gross
+	if annualCrossed {
+		if catchUp := priorGross.Sub(priorTaxable); catchUp.IsPositive() {
+			c.TaxableAmount = c.TaxableAmount.Add(catchUp)
+		}
+	}
+	c.TDSAmount = c.TaxableAmount.Mul(c.Rate).Div(decimal.NewFromInt(100)).Round(2)
+	if c.TDSAmount.GreaterThan(gross) {
+		c.TDSAmount = gross
+	}
+	return c
+}
@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestComputeTDS(t *testing.T) {
+	contractors := core.TDSSection{
+		Code: "194C-OTH", Rate: decimal.NewFromInt(2), NoPANRate: decimal.NewFromInt(20),
+		SinglePaymentThreshold: decimal.NewFromInt(30000), AnnualThreshold: decimal.NewFromInt(100000),
+	}
+	professional := core.TDSSection{
+		Code: "194J-PROF", Rate: decimal.NewFromInt(10), NoPANRate: decimal.NewFromInt(20),
+		AnnualThreshold: decimal.NewFromInt(50000),
+	}
+	d := func(v int64) decimal.Decimal { return decimal.NewFromInt(v) }
+
+	tests := []struct {
+		name         string
+		section      core.TDSSection
+		hasPAN       bool
+		priorGross   decimal.Decimal
+		priorTaxable decimal.Decimal
+		gross        decimal.Decimal
+		wantTaxable  string
+		wantTDS      string
+	}{
+		{"below both thresholds", contractors, true, d(0), d(0), d(25000), "0", "0"},
+		{"single payment over threshold", contractors, true, d(0), d(0), d(40000), "40000", "800"},
+		{"small payment after a large one", contractors, true, d(40000), d(40000), d(20000), "0", "0"},
+		{"annual threshold catches up earlier payments", contractors, true, d(80000), d(40000), d(25000), "65000", "1300"},
+		{"after annual threshold every payment is taxed", contractors, true, d(105000), d(105000), d(5000), "5000", "100"},
+		{"no PAN uses the higher rate", contractors, false, d(0), d(0), d(40000), "40000", "8000"},
+		{"annual-only section below threshold", professional, true, d(30000), d(0), d(15000), "0", "0"},
+		{"annual-only section crossed", professional, true, d(30000), d(0), d(25000), "55000", "5500"},
+		{"withholding capped at the payment", professional, false, d(60000), d(0), d(1000), "61000", "1000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := core.ComputeTDS(tc.section, tc.hasPAN, tc.priorGross, tc.priorTaxable, tc.gross)
+			if !got.TaxableAmount.Equal(decimal.RequireFromString(tc.wantTaxable)) {
+				t.Errorf("taxable: want %s, got %s", tc.wantTaxable, got.TaxableAmount)
+			}
+			if !got.TDSAmount.Equal(decimal.RequireFromString(tc.wantTDS)) {
+				t.Errorf("TDS: want %s, got %s", tc.wantTDS, got.TDSAmount)
+			}
+		})
+	}
+}
+
+func TestExcludeGST(t *testing.T) {
+	d := func(v string) decimal.Decimal { return decimal.RequireFromString(v) }
+	tests := []struct {
+		name               string
+		amount, total, tax string
+		want               string
+	}{
+		{"full payment", "47200", "47200", "7200", "40000"},
+		{"part payment in proportion", "23600", "47200", "7200", "20000"},
+		{"no GST", "5000", "5000", "0", "5000"},
+		{"no document total", "5000", "0", "0", "5000"},
+	}
+	for _, tc := range tests {
+		if got := core.ExcludeGST(d(tc.amount), d(tc.total), d(tc.tax)); !got.Equal(d(tc.want)) {
+			t.Errorf("%s: want %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestFinancialYearAndQuarter(t *testing.T) {
+	if fy := core.FinancialYear(time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)); fy != 2026 {
+		t.Errorf("31 Mar 2027: want FY 2026, got %d", fy)
+	}
+	if fy := core.FinancialYear(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)); fy != 2026 {
+		t.Errorf("1 Apr 2026: want FY 2026, got %d", fy)
+	}
+	if label := core.FinancialYearLabel(2099); label != "2099-00" {
+		t.Errorf("want label 2099-00, got %s", label)
+	}
+
+	from, to, err := core.FinancialQuarter(2026, 4)
+	if err != nil {
+		t.Fatalf("FinancialQuarter: %v", err)
+	}
+	if from.Format("2006-01-02") != "2027-01-01" || to.Format("2006-01-02") != "2027-03-31" {
+		t.Errorf("Q4 FY 2026: want 2027-01-01..2027-03-31, got %s..%s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	if _, _, err := core.FinancialQuarter(2026, 5); err == nil {
+		t.Error("expected error for quarter 5, got nil")
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TDSSection is a withholding code under the Indian Income Tax Act (e.g. 194C-OTH for
+// contractors other than individuals). Rates are percentages; a zero threshold means the
+// section has no threshold of that kind.
+type TDSSection struct {
+	Code                   string
+	Section                string // statutory section, e.g. 194C
+	Description            string
+	Rate                   decimal.Decimal
+	NoPANRate              decimal.Decimal // applied when the vendor has no PAN (section 206AA)
+	SinglePaymentThreshold decimal.Decimal
+	AnnualThreshold        decimal.Decimal
+	IsActive               bool
+}
+
+// TDSComputation is the withholding on one vendor payment, given the vendor's payments
+// earlier in the same financial year.
+type TDSComputation struct {
+	GrossAmount      decimal.Decimal // payment before TDS, excluding GST
+	TaxableAmount    decimal.Decimal // amount TDS is computed on, including any catch-up
+	Rate             decimal.Decimal // percentage applied
+	TDSAmount        decimal.Decimal // withheld from the payment
+	ThresholdCrossed bool
+}
+
+// TDSDeduction is one payment to a TDS vendor as recorded in tds_deductions.
+// Payments below threshold are recorded with a zero TDSAmount.
+type TDSDeduction struct {
+	ID                    int
+	VendorID              int
+	VendorCode            string
+	VendorName            string
+	PAN                   *string
+	SectionCode           string
+	Section               string
+	FinancialYear         int    // year the financial year starts in (2026 = FY 2026-27)
+	PaymentDate           string // YYYY-MM-DD
+	GrossAmount           decimal.Decimal
+	TaxableAmount         decimal.Decimal
+	Rate                  decimal.Decimal
+	TDSAmount             decimal.Decimal
+	POID                  *int
+	BillID                *int
+	RunID                 *int
+	JournalDocumentNumber *string
+	CreatedAt             time.Time
+}
+
+// TDSVendorStatus is a vendor's cumulative position against its section's thresholds for
+// one financial year.
+type TDSVendorStatus struct {
+	VendorCode             string
+	VendorName             string
+	PAN                    *string
+	SectionCode            string
+	FinancialYear          string          // e.g. 2026-27
+	Rate                   decimal.Decimal // rate that applies to the vendor (no-PAN rate if PAN is missing)
+	SinglePaymentThreshold decimal.Decimal
+	AnnualThreshold        decimal.Decimal
+	CumulativePaid         decimal.Decimal // gross payments so far this financial year
+	CumulativeTaxable      decimal.Decimal
+	TDSDeducted            decimal.Decimal
+	AnnualThresholdCrossed bool
+	Payments               int
+}
+
+// TDSSectionTotal summarises one section in a TDS register.
+type TDSSectionTotal struct {
+	SectionCode string
+	Section     string
+	Deductions  int
+	GrossAmount decimal.Decimal
+	TDSAmount   decimal.Decimal
+}
+
+// TDSRegister lists the TDS withheld in one quarter of a financial year, the basis of the
+// quarterly TDS return (Form 26Q).
+type TDSRegister struct {
+	CompanyCode   string
+	FinancialYear string // e.g. 2026-27
+	Quarter       int    // 1 = Apr–Jun … 4 = Jan–Mar
+	FromDate      string
+	ToDate        string
+	Deductions    []TDSDeduction
+	Sections      []TDSSectionTotal
+	TotalGross    decimal.Decimal
+	TotalTDS      decimal.Decimal
+}
+
+// TDSService exposes TDS sections, vendor threshold status and the quarterly register.
+// Withholding itself happens inside the vendor payment services.
+type TDSService interface {
+	// GetSections returns the active TDS sections, ordered by code.
+	GetSections(ctx context.Context) ([]TDSSection, error)
+
+	// GetVendorTDSStatus returns a vendor's cumulative payments and TDS for the financial
+	// year containing asOf. Fails if the vendor has no TDS section.
+	GetVendorTDSStatus(ctx context.Context, companyCode, vendorCode string, asOf time.Time) (*TDSVendorStatus, error)
+
+	// GetRegister returns the deductions (TDS > 0) made in a quarter of the financial year
+	// starting in fyStartYear, with per-section totals.
+	GetRegister(ctx context.Context, companyCode string, fyStartYear, quarter int) (*TDSRegister, error)
+}
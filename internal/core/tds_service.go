@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type tdsService struct {
+	pool *pgxpool.Pool
+}
+
+// NewTDSService constructs a TDSService backed by PostgreSQL.
+func NewTDSService(pool *pgxpool.Pool) TDSService {
+	return &tdsService{pool: pool}
+}
+
+const tdsSectionColumns = `code, section, description, rate, no_pan_rate, single_payment_threshold, annual_threshold, is_active`
+
+func scanTDSSection(row pgx.Row) (TDSSection, error) {
+	var s TDSSection
+	err := row.Scan(&s.Code, &s.Section, &s.Description, &s.Rate, &s.NoPANRate,
+		&s.SinglePaymentThreshold, &s.AnnualThreshold, &s.IsActive)
+	return s, err
+}
+
+// ── Withholding ───────────────────────────────────────────────────────────────
+
+// tdsWithholding is the TDS on one vendor payment, computed by vendorTDSTx and written to
+// tds_deductions by record once the payment entry is posted.
+type tdsWithholding struct {
+	companyID     int
+	vendorID      int
+	section       TDSSection
+	pan           *string
+	financialYear int
+	paymentDate   time.Time
+	TDSComputation
+}
+
+// tdsSource identifies the document a withholding was made on; exactly one ID is set.
+type tdsSource struct {
+	poID   *int
+	billID *int
+	runID  *int
+}
+
+// vendorTDSTx computes the TDS on a payment to a vendor on paymentDate; base is the
+// payment excluding GST (see payableTDSBaseQ). It returns nil when the vendor has no TDS
+// section. With lock set the vendor row is locked, so concurrent payments to the same
+// vendor see each other's cumulative totals.
+func vendorTDSTx(ctx context.Context, q pgxQuerier, companyID, vendorID int, base decimal.Decimal, paymentDate time.Time, lock bool) (*tdsWithholding, error) {
+	query := "SELECT code, pan, tds_section_code FROM vendors WHERE id = $1 AND company_id = $2"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	var vendorCode string
+	var pan, sectionCode *string
+	if err := q.QueryRow(ctx, query, vendorID, companyID).Scan(&vendorCode, &pan, &sectionCode); err != nil {
+		return nil, fmt.Errorf("fetch TDS details for vendor %d: %w", vendorID, err)
+	}
+	if sectionCode == nil {
+		return nil, nil
+	}
+
+	section, err := scanTDSSection(q.QueryRow(ctx,
+		"SELECT "+tdsSectionColumns+" FROM tds_sections WHERE code = $1", *sectionCode))
+	if err != nil {
+		return nil, fmt.Errorf("fetch TDS section %s for vendor %s: %w", *sectionCode, vendorCode, err)
+	}
+
+	fy := FinancialYear(paymentDate)
+	var priorGross, priorTaxable decimal.Decimal
+	if err := q.QueryRow(ctx, `
+		SELECT COALESCE(SUM(gross_amount), 0), COALESCE(SUM(taxable_amount), 0)
+		FROM tds_deductions
+		WHERE vendor_id = $1 AND section_code = $2 AND financial_year = $3`,
+		vendorID, section.Code, fy,
+	).Scan(&priorGross, &priorTaxable); err != nil {
+		return nil, fmt.Errorf("fetch TDS cumulative for vendor %s: %w", vendorCode, err)
+	}
+
+	hasPAN := pan != nil && *pan != ""
+	return &tdsWithholding{
+		companyID:      companyID,
+		vendorID:       vendorID,
+		section:        section,
+		pan:            pan,
+		financialYear:  fy,
+		paymentDate:    paymentDate,
+		TDSComputation: ComputeTDS(section, hasPAN, priorGross, priorTaxable, base),
+	}, nil
+}
+
+// payableTDSBaseQ returns the part of amount, paid against a PO invoice, vendor bill or
+// debit note, that TDS is computed on: amount excluding the document's GST (see ExcludeGST).
+// Exactly one of the IDs is set.
+func payableTDSBaseQ(ctx context.Context, q pgxQuerier, poID, billID, returnID *int, amount decimal.Decimal) (decimal.Decimal, error) {
+	var total, tax decimal.Decimal
+	switch {
+	case poID != nil:
+		if err := q.QueryRow(ctx,
+			"SELECT COALESCE(invoice_amount, 0), COALESCE(invoice_tax_amount, 0) FROM purchase_orders WHERE id = $1", *poID,
+		).Scan(&total, &tax); err != nil {
+			return decimal.Zero, fmt.Errorf("fetch GST of purchase order %d: %w", *poID, err)
+		}
+	case billID != nil:
+		if err := q.QueryRow(ctx,
+			"SELECT total_amount, tax_amount FROM vendor_bills WHERE id = $1", *billID,
+		).Scan(&total, &tax); err != nil {
+			return decimal.Zero, fmt.Errorf("fetch GST of vendor bill %d: %w", *billID, err)
+		}
+	default:
+		if err := q.QueryRow(ctx,
+			"SELECT amount, tax_amount FROM purchase_returns WHERE id = $1", *returnID,
+		).Scan(&total, &tax); err != nil {
+			return decimal.Zero, fmt.Errorf("fetch GST of debit note %d: %w", *returnID, err)
+		}
+	}
+	return ExcludeGST(amount, total, tax), nil
+}
+
+// tdsPayableLine returns the credit line for the withheld amount, resolving the
+// TDS_PAYABLE account only when something is withheld.
+func (w *tdsWithholding) tdsPayableLine(ctx context.Context, ruleEngine RuleEngine) (*ProposalLine, error) {
+	if w == nil || !w.TDSAmount.IsPositive() {
+		return nil, nil
+	}
+	account, err := ruleEngine.ResolveAccount(ctx, w.companyID, "TDS_PAYABLE")
+	if err != nil {
+		return nil, err
+	}
+	return &ProposalLine{AccountCode: account, IsDebit: false, Amount: w.TDSAmount.StringFixed(2)}, nil
+}
+
+// netOf returns what reaches the vendor's bank account out of gross.
+func (w *tdsWithholding) netOf(gross decimal.Decimal) decimal.Decimal {
+	if w == nil {
+		return gross
+	}
+	return gross.Sub(w.TDSAmount)
+}
+
+// record writes the withholding to tds_deductions, linked to the payment entry posted under
+// idempotencyKey.
+func (w *tdsWithholding) record(ctx context.Context, tx pgx.Tx, src tdsSource, idempotencyKey string) error {
+	if w == nil || !w.GrossAmount.IsPositive() {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO tds_deductions (company_id, vendor_id, section_code, pan, financial_year, payment_date,
+		                            gross_amount, taxable_amount, rate, tds_amount, po_id, bill_id, run_id,
+		                            journal_document_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+		        (SELECT reference_id FROM journal_entries WHERE idempotency_key = $14))`,
+		w.companyID, w.vendorID, w.section.Code, w.pan, w.financialYear, w.paymentDate,
+		w.GrossAmount, w.TaxableAmount, w.Rate, w.TDSAmount, src.poID, src.billID, src.runID,
+		idempotencyKey,
+	); err != nil {
+		return fmt.Errorf("record TDS deduction: %w", err)
+	}
+	return nil
+}
+
+// ── Queries ───────────────────────────────────────────────────────────────────
+
+// GetSections returns the active TDS sections, ordered by code.
+func (s *tdsService) GetSections(ctx context.Context) ([]TDSSection, error) {
+	rows, err := s.pool.Query(ctx, "SELECT "+tdsSectionColumns+" FROM tds_sections WHERE is_active ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("get TDS sections: %w", err)
+	}
+	defer rows.Close()
+
+	var sections []TDSSection
+	for rows.Next() {
+		sec, err := scanTDSSection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan TDS section: %w", err)
+		}
+		sections = append(sections, sec)
+	}
+	return sections, rows.Err()
+}
+
+// GetVendorTDSStatus returns a vendor's cumulative TDS position for the financial year
+// containing asOf.
+func (s *tdsService) GetVendorTDSStatus(ctx context.Context, companyCode, vendorCode string, asOf time.Time) (*TDSVendorStatus, error) {
+	var vendorID int
+	var pan, sectionCode *string
+	st := &TDSVendorStatus{}
+	if err := s.pool.QueryRow(ctx, `
+		SELECT v.id, v.code, v.name, v.pan, v.tds_section_code
+		FROM vendors v
+		JOIN companies c ON c.id = v.company_id
+		WHERE c.company_code = $1 AND v.code = $2`,
+		companyCode, strings.ToUpper(strings.TrimSpace(vendorCode)),
+	).Scan(&vendorID, &st.VendorCode, &st.VendorName, &pan, &sectionCode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("vendor %s not found", vendorCode)
+		}
+		return nil, fmt.Errorf("fetch vendor %s: %w", vendorCode, err)
+	}
+	if sectionCode == nil {
+		return nil, fmt.Errorf("vendor %s has no TDS section", st.VendorCode)
+	}
+	st.PAN = pan
+	st.SectionCode = *sectionCode
+
+	section, err := scanTDSSection(s.pool.QueryRow(ctx,
+		"SELECT "+tdsSectionColumns+" FROM tds_sections WHERE code = $1", *sectionCode))
+	if err != nil {
+		return nil, fmt.Errorf("fetch TDS section %s: %w", *sectionCode, err)
+	}
+	st.Rate = section.Rate
+	if (pan == nil || *pan == "") && section.NoPANRate.GreaterThan(st.Rate) {
+		st.Rate = section.NoPANRate
+	}
+	st.SinglePaymentThreshold = section.SinglePaymentThreshold
+	st.AnnualThreshold = section.AnnualThreshold
+
+	fy := FinancialYear(asOf)
+	st.FinancialYear = FinancialYearLabel(fy)
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(gross_amount), 0), COALESCE(SUM(taxable_amount), 0), COALESCE(SUM(tds_amount), 0)
+		FROM tds_deductions
+		WHERE vendor_id = $1 AND section_code = $2 AND financial_year = $3`,
+		vendorID, section.Code, fy,
+	).Scan(&st.Payments, &st.CumulativePaid, &st.CumulativeTaxable, &st.TDSDeducted); err != nil {
+		return nil, fmt.Errorf("fetch TDS cumulative for vendor %s: %w", st.VendorCode, err)
+	}
+	st.AnnualThresholdCrossed = section.AnnualThreshold.IsPositive() && st.CumulativePaid.GreaterThan(section.AnnualThreshold)
+	return st, nil
+}
+
+// GetRegister returns the TDS withheld in one quarter of a financial year.
+func (s *tdsService) GetRegister(ctx context.Context, companyCode string, fyStartYear, quarter int) (*TDSRegister, error) {
+	from, to, err := FinancialQuarter(fyStartYear, quarter)
+	if err != nil {
+		return nil, err
+	}
+	var companyID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID); err != nil {
+		return nil, fmt.Errorf("company %s not found: %w", companyCode, err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT td.id, td.vendor_id, v.code, v.name, td.pan, td.section_code, ts.section,
+		       td.financial_year, td.payment_date::text, td.gross_amount, td.taxable_amount,
+		       td.rate, td.tds_amount, td.po_id, td.bill_id, td.run_id,
+		       td.journal_document_number, td.created_at
+		FROM tds_deductions td
+		JOIN vendors v       ON v.id = td.vendor_id
+		JOIN tds_sections ts ON ts.code = td.section_code
+		WHERE td.company_id = $1 AND td.payment_date BETWEEN $2 AND $3 AND td.tds_amount > 0
+		ORDER BY td.section_code, td.payment_date, td.id`,
+		companyID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query TDS register: %w", err)
+	}
+	defer rows.Close()
+
+	register := &TDSRegister{
+		CompanyCode:   companyCode,
+		FinancialYear: FinancialYearLabel(fyStartYear),
+		Quarter:       quarter,
+		FromDate:      from.Format("2006-01-02"),
+		ToDate:        to.Format("2006-01-02"),
+	}
+	for rows.Next() {
+		var d TDSDeduction
+		if err := rows.Scan(&d.ID, &d.VendorID, &d.VendorCode, &d.VendorName, &d.PAN, &d.SectionCode, &d.Section,
+			&d.FinancialYear, &d.PaymentDate, &d.GrossAmount, &d.TaxableAmount,
+			&d.Rate, &d.TDSAmount, &d.POID, &d.BillID, &d.RunID,
+			&d.JournalDocumentNumber, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan TDS deduction: %w", err)
+		}
+		register.Deductions = append(register.Deductions, d)
+
+		// Rows arrive ordered by section code.
+		n := len(register.Sections)
+		if n == 0 || register.Sections[n-1].SectionCode != d.SectionCode {
+			register.Sections = append(register.Sections, TDSSectionTotal{SectionCode: d.SectionCode, Section: d.Section})
+			n++
+		}
+		total := &register.Sections[n-1]
+		total.Deductions++
+		total.GrossAmount = total.GrossAmount.Add(d.GrossAmount)
+		total.TDSAmount = total.TDSAmount.Add(d.TDSAmount)
+		register.TotalGross = register.TotalGross.Add(d.GrossAmount)
+		register.TotalTDS = register.TotalTDS.Add(d.TDSAmount)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate TDS register: %w", err)
+	}
+	return register, nil
+}
@@ -0,0 +1,185 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestUoM_PurchaseConversion(t *testing.T) {
+	pool, poService, ledger, docService, invSvc, vendorID, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	uomSvc := core.NewUoMService(pool)
+	companyCode := "1000"
+	poDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, u := range []struct{ code, name string }{{"CTN", "Carton"}, {"KG", "Kilogram"}} {
+		if _, err := uomSvc.CreateUoM(ctx, companyCode, u.code, u.name); err != nil {
+			t.Fatalf("CreateUoM %s: %v", u.code, err)
+		}
+	}
+
+	t.Run("CreateUoM_Duplicate_Fails", func(t *testing.T) {
+		if _, err := uomSvc.CreateUoM(ctx, companyCode, "ctn", ""); err == nil {
+			t.Error("expected error creating duplicate unit, got nil")
+		}
+	})
+
+	t.Run("SetConversion_StockUnit_Fails", func(t *testing.T) {
+		if _, err := uomSvc.CreateUoM(ctx, companyCode, "unit", "Unit"); err != nil {
+			t.Fatalf("CreateUoM unit: %v", err)
+		}
+		if _, err := uomSvc.SetConversion(ctx, companyCode, "P001", "unit", decimal.NewFromInt(2)); err == nil {
+			t.Error("expected error setting a factor on the stock unit, got nil")
+		}
+	})
+
+	if _, err := uomSvc.SetConversion(ctx, companyCode, "P001", "CTN", decimal.NewFromInt(24)); err != nil {
+		t.Fatalf("SetConversion: %v", err)
+	}
+
+	t.Run("SetDefaultUoMs_Incompatible_Fails", func(t *testing.T) {
+		if _, err := uomSvc.SetDefaultUoMs(ctx, companyCode, "P001", "KG", ""); err == nil {
+			t.Error("expected error defaulting to a unit with no conversion, got nil")
+		}
+	})
+
+	t.Run("CreatePO_IncompatibleUnit_Fails", func(t *testing.T) {
+		_, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
+			{ProductCode: "P001", Description: "Widget A", Quantity: decimal.NewFromInt(5), Unit: "KG", UnitCost: decimal.NewFromInt(100)},
+		}, "")
+		if err == nil {
+			t.Error("expected error for a unit with no conversion, got nil")
+		}
+	})
+
+	units, err := uomSvc.SetDefaultUoMs(ctx, companyCode, "P001", "ctn", "")
+	if err != nil {
+		t.Fatalf("SetDefaultUoMs: %v", err)
+	}
+	if units.PurchaseUoM != "CTN" || units.SalesUoM != "unit" {
+		t.Errorf("expected purchase CTN / sales unit, got %s / %s", units.PurchaseUoM, units.SalesUoM)
+	}
+	if len(units.Conversions) != 2 || !units.Conversions[1].Factor.Equal(decimal.NewFromInt(24)) {
+		t.Errorf("expected stock unit plus CTN×24, got %+v", units.Conversions)
+	}
+
+	// 2 cartons at 4,800 each; the line takes the product's default purchase unit.
+	po, err := poService.CreatePO(ctx, 1, vendorID, "", decimal.Zero, poDate, []core.PurchaseOrderLineInput{
+		{ProductCode: "P001", Description: "Widget A cartons", Quantity: decimal.NewFromInt(2), UnitCost: decimal.NewFromInt(4800)},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePO: %v", err)
+	}
+	line := po.Lines[0]
+	if line.Unit != "CTN" || !line.UnitFactor.Equal(decimal.NewFromInt(24)) {
+		t.Fatalf("expected line in CTN with factor 24, got %s × %s", line.Unit, line.UnitFactor)
+	}
+	if !po.TotalTransaction.Equal(decimal.NewFromInt(9600)) {
+		t.Errorf("expected PO total 9600, got %s", po.TotalTransaction)
+	}
+	if err := poService.ApprovePO(ctx, 1, po.ID, docService); err != nil {
+		t.Fatalf("ApprovePO: %v", err)
+	}
+
+	t.Run("ReceivePO_ConvertsToStockUnit", func(t *testing.T) {
+		if err := poService.ReceivePO(ctx, po.ID, "MAIN", companyCode,
+			[]core.ReceivedLine{{POLineID: line.ID, QtyReceived: decimal.NewFromInt(1)}},
+			"2000", ledger, docService, invSvc); err != nil {
+			t.Fatalf("ReceivePO: %v", err)
+		}
+
+		stockLevels, err := invSvc.GetStockLevels(ctx, companyCode)
+		if err != nil {
+			t.Fatalf("GetStockLevels: %v", err)
+		}
+		for _, sl := range stockLevels {
+			if sl.ProductCode != "P001" {
+				continue
+			}
+			if !sl.OnHand.Equal(decimal.NewFromInt(24)) {
+				t.Errorf("expected 24 on hand after one carton, got %s", sl.OnHand)
+			}
+			if !sl.UnitCost.Equal(decimal.NewFromInt(200)) {
+				t.Errorf("expected unit cost 200 per stock unit, got %s", sl.UnitCost)
+			}
+		}
+	})
+}
+
+func TestUoM_SalesConversion(t *testing.T) {
+	pool, orderSvc, ledger, docSvc, ctx := setupOrderTestDB(t)
+	defer pool.Close()
+	seedInventoryTestData(t, ctx, pool)
+
+	invSvc := core.NewInventoryService(pool, core.NewRuleEngine(pool))
+	uomSvc := core.NewUoMService(pool)
+
+	if _, err := uomSvc.CreateUoM(ctx, "1000", "CTN", "Carton"); err != nil {
+		t.Fatalf("CreateUoM: %v", err)
+	}
+	if _, err := uomSvc.SetConversion(ctx, "1000", "P001", "CTN", decimal.NewFromInt(24)); err != nil {
+		t.Fatalf("SetConversion: %v", err)
+	}
+	if err := invSvc.ReceiveStock(ctx, "1000", "MAIN", "P001",
+		decimal.NewFromInt(30), decimal.NewFromInt(200), "2026-03-01", "2000", nil, ledger, docSvc); err != nil {
+		t.Fatalf("ReceiveStock: %v", err)
+	}
+
+	t.Run("CreateOrder_IncompatibleUnit_Fails", func(t *testing.T) {
+		_, err := orderSvc.CreateOrder(ctx, "1000", "C001", "INR", decimal.NewFromInt(1), "2026-03-02",
+			[]core.OrderLineInput{{ProductCode: "P003", Quantity: decimal.NewFromInt(1), Unit: "CTN"}}, "")
+		if err == nil {
+			t.Error("expected error ordering P003 in cartons (no conversion), got nil")
+		}
+	})
+
+	order, err := orderSvc.CreateOrder(ctx, "1000", "C001", "INR", decimal.NewFromInt(1), "2026-03-02",
+		[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(1), Unit: "ctn"}}, "")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	l := order.Lines[0]
+	if l.Unit != "CTN" || !l.StockQuantity.Equal(decimal.NewFromInt(24)) {
+		t.Errorf("expected 1 CTN = 24 stock units, got %s %s = %s", l.Quantity, l.Unit, l.StockQuantity)
+	}
+	// Catalogue price 500 per unit scales to 12,000 per carton.
+	if !l.UnitPrice.Equal(decimal.NewFromInt(12000)) {
+		t.Errorf("expected carton price 12000, got %s", l.UnitPrice)
+	}
+
+	if _, err := orderSvc.ConfirmOrder(ctx, order.ID, docSvc, invSvc); err != nil {
+		t.Fatalf("ConfirmOrder: %v", err)
+	}
+
+	t.Run("Reservation_InStockUnits", func(t *testing.T) {
+		// 30 on hand, 24 reserved: a second carton cannot be confirmed.
+		second, err := orderSvc.CreateOrder(ctx, "1000", "C002", "INR", decimal.NewFromInt(1), "2026-03-02",
+			[]core.OrderLineInput{{ProductCode: "P001", Quantity: decimal.NewFromInt(1), Unit: "CTN"}}, "")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if _, err := orderSvc.ConfirmOrder(ctx, second.ID, docSvc, invSvc); err == nil {
+			t.Error("expected insufficient stock confirming a second carton, got nil")
+		}
+	})
+
+	t.Run("Ship_DeductsStockUnits", func(t *testing.T) {
+		if _, err := orderSvc.ShipOrder(ctx, order.ID, invSvc, ledger, docSvc); err != nil {
+			t.Fatalf("ShipOrder: %v", err)
+		}
+		stockLevels, err := invSvc.GetStockLevels(ctx, "1000")
+		if err != nil {
+			t.Fatalf("GetStockLevels: %v", err)
+		}
+		for _, sl := range stockLevels {
+			if sl.ProductCode == "P001" && !sl.OnHand.Equal(decimal.NewFromInt(6)) {
+				t.Errorf("expected 6 on hand after shipping one carton, got %s", sl.OnHand)
+			}
+		}
+	})
+}
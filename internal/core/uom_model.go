@@ -0,0 +1,38 @@
+package core
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// UnitOfMeasure is a company-scoped unit master record (e.g. EA, CTN, KG).
+type UnitOfMeasure struct {
+	ID        int       `json:"id"`
+	CompanyID int       `json:"company_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UoMConversion states how many stock units make up one of UoMCode for a product.
+// The stock unit itself is always listed with Factor 1.
+type UoMConversion struct {
+	ProductCode string          `json:"product_code"`
+	StockUnit   string          `json:"stock_unit"`
+	UoMCode     string          `json:"uom_code"`
+	UoMName     string          `json:"uom_name"`
+	Factor      decimal.Decimal `json:"factor"`
+	IsStockUnit bool            `json:"is_stock_unit"`
+}
+
+// ProductUnits is a product's stock unit, default line units, and all conversions.
+type ProductUnits struct {
+	ProductCode string          `json:"product_code"`
+	ProductName string          `json:"product_name"`
+	StockUnit   string          `json:"stock_unit"`
+	PurchaseUoM string          `json:"purchase_uom"` // defaults to StockUnit
+	SalesUoM    string          `json:"sales_uom"`    // defaults to StockUnit
+	Conversions []UoMConversion `json:"conversions"`
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// UoMService maintains the unit-of-measure master and per-product conversion factors.
+// Stock is always held in the product's stock unit (products.unit); PO and sales
+// order lines may use any unit with a conversion to it.
+type UoMService interface {
+	// CreateUoM adds a unit to the company's master. Codes are stored upper-case.
+	CreateUoM(ctx context.Context, companyCode, code, name string) (*UnitOfMeasure, error)
+
+	// GetUoMs returns all active units for a company.
+	GetUoMs(ctx context.Context, companyCode string) ([]UnitOfMeasure, error)
+
+	// SetConversion creates or updates the number of stock units in one uomCode for a product.
+	SetConversion(ctx context.Context, companyCode, productCode, uomCode string, factor decimal.Decimal) (*ProductUnits, error)
+
+	// SetDefaultUoMs sets the units PO and sales order lines use when none is given.
+	// An empty value resets that default to the stock unit.
+	SetDefaultUoMs(ctx context.Context, companyCode, productCode, purchaseUoM, salesUoM string) (*ProductUnits, error)
+
+	// GetProductUnits returns a product's stock unit, default line units, and conversions.
+	GetProductUnits(ctx context.Context, companyCode, productCode string) (*ProductUnits, error)
+}
+
+type uomService struct {
+	pool *pgxpool.Pool
+}
+
+// NewUoMService constructs a UoMService backed by PostgreSQL.
+func NewUoMService(pool *pgxpool.Pool) UoMService {
+	return &uomService{pool: pool}
+}
+
+// ── Unit master ───────────────────────────────────────────────────────────────
+
+func (s *uomService) CreateUoM(ctx context.Context, companyCode, code, name string) (*UnitOfMeasure, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("unit code is required")
+	}
+	if name == "" {
+		name = code
+	}
+
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock units registered from products keep their original case, so compare case-insensitively.
+	var exists bool
+	if err := s.pool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM units_of_measure WHERE company_id = $1 AND UPPER(code) = $2)",
+		companyID, code,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check unit of measure: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("unit %s already exists", code)
+	}
+
+	var u UnitOfMeasure
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO units_of_measure (company_id, code, name)
+		VALUES ($1, $2, $3)
+		RETURNING id, company_id, code, name, is_active, created_at`,
+		companyID, code, name,
+	).Scan(&u.ID, &u.CompanyID, &u.Code, &u.Name, &u.IsActive, &u.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("create unit of measure: %w", err)
+	}
+	return &u, nil
+}
+
+func (s *uomService) GetUoMs(ctx context.Context, companyCode string) ([]UnitOfMeasure, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, company_id, code, name, is_active, created_at
+		FROM units_of_measure
+		WHERE company_id = $1 AND is_active = true
+		ORDER BY code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("query units of measure: %w", err)
+	}
+	defer rows.Close()
+
+	var units []UnitOfMeasure
+	for rows.Next() {
+		var u UnitOfMeasure
+		if err := rows.Scan(&u.ID, &u.CompanyID, &u.Code, &u.Name, &u.IsActive, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan unit of measure: %w", err)
+		}
+		units = append(units, u)
+	}
+	return units, rows.Err()
+}
+
+// ── Product conversions ───────────────────────────────────────────────────────
+
+func (s *uomService) SetConversion(ctx context.Context, companyCode, productCode, uomCode string, factor decimal.Decimal) (*ProductUnits, error) {
+	if !factor.IsPositive() {
+		return nil, fmt.Errorf("conversion factor must be positive, got %s", factor)
+	}
+
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var productID int
+	var stockUnit string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, unit FROM products WHERE company_id = $1 AND code = $2",
+		companyID, productCode,
+	).Scan(&productID, &stockUnit); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %q not found", productCode)
+		}
+		return nil, fmt.Errorf("resolve product: %w", err)
+	}
+
+	var uomID int
+	var code string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, code FROM units_of_measure WHERE company_id = $1 AND UPPER(code) = UPPER($2) AND is_active = true",
+		companyID, strings.TrimSpace(uomCode),
+	).Scan(&uomID, &code); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("unit %q not found", uomCode)
+		}
+		return nil, fmt.Errorf("resolve unit: %w", err)
+	}
+	if strings.EqualFold(code, stockUnit) {
+		return nil, fmt.Errorf("%s is the stock unit of product %s; its factor is always 1", code, productCode)
+	}
+
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO product_uom_conversions (product_id, uom_id, factor)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (product_id, uom_id) DO UPDATE
+		SET factor = EXCLUDED.factor, updated_at = NOW()`,
+		productID, uomID, factor,
+	); err != nil {
+		return nil, fmt.Errorf("save conversion: %w", err)
+	}
+
+	return s.GetProductUnits(ctx, companyCode, productCode)
+}
+
+func (s *uomService) SetDefaultUoMs(ctx context.Context, companyCode, productCode, purchaseUoM, salesUoM string) (*ProductUnits, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var productID int
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id FROM products WHERE company_id = $1 AND code = $2",
+		companyID, productCode,
+	).Scan(&productID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %q not found", productCode)
+		}
+		return nil, fmt.Errorf("resolve product: %w", err)
+	}
+
+	// Each default must be convertible, so resolving it now rejects unknown units.
+	var defaults [2]*string
+	for i, u := range []string{purchaseUoM, salesUoM} {
+		if strings.TrimSpace(u) == "" {
+			continue
+		}
+		code, _, err := resolveLineUoM(ctx, s.pool, productID, u)
+		if err != nil {
+			return nil, err
+		}
+		defaults[i] = &code
+	}
+
+	if _, err := s.pool.Exec(ctx,
+		"UPDATE products SET purchase_uom = $1, sales_uom = $2 WHERE id = $3",
+		defaults[0], defaults[1], productID,
+	); err != nil {
+		return nil, fmt.Errorf("update default units: %w", err)
+	}
+
+	return s.GetProductUnits(ctx, companyCode, productCode)
+}
+
+func (s *uomService) GetProductUnits(ctx context.Context, companyCode, productCode string) (*ProductUnits, error) {
+	companyID, err := s.resolveCompanyID(ctx, companyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var pu ProductUnits
+	var productID int
+	if err := s.pool.QueryRow(ctx, `
+		SELECT id, code, name, unit, COALESCE(purchase_uom, unit), COALESCE(sales_uom, unit)
+		FROM products
+		WHERE company_id = $1 AND code = $2`,
+		companyID, productCode,
+	).Scan(&productID, &pu.ProductCode, &pu.ProductName, &pu.StockUnit, &pu.PurchaseUoM, &pu.SalesUoM); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %q not found", productCode)
+		}
+		return nil, fmt.Errorf("fetch product: %w", err)
+	}
+
+	pu.Conversions = []UoMConversion{{
+		ProductCode: pu.ProductCode,
+		StockUnit:   pu.StockUnit,
+		UoMCode:     pu.StockUnit,
+		UoMName:     pu.StockUnit,
+		Factor:      decimal.NewFromInt(1),
+		IsStockUnit: true,
+	}}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT u.code, u.name, c.factor
+		FROM product_uom_conversions c
+		JOIN units_of_measure u ON u.id = c.uom_id
+		WHERE c.product_id = $1
+		ORDER BY c.factor, u.code`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("query conversions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := UoMConversion{ProductCode: pu.ProductCode, StockUnit: pu.StockUnit}
+		if err := rows.Scan(&c.UoMCode, &c.UoMName, &c.Factor); err != nil {
+			return nil, fmt.Errorf("scan conversion: %w", err)
+		}
+		pu.Conversions = append(pu.Conversions, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate conversions: %w", err)
+	}
+	return &pu, nil
+}
+
+func (s *uomService) resolveCompanyID(ctx context.Context, companyCode string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, "SELECT id FROM companies WHERE company_code = $1", companyCode).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("company %s not found", companyCode)
+		}
+		return 0, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+	return id, nil
+}
+
+// ── Line conversion ───────────────────────────────────────────────────────────
+
+// resolveLineUoM validates uomCode for a product and returns its canonical code and
+// the number of stock units in one of it. An empty uomCode or the stock unit itself
+// resolves to the stock unit with factor 1. Any other unit must have a conversion
+// for the product; units without one are incompatible and rejected.
+func resolveLineUoM(ctx context.Context, q pgxQuerier, productID int, uomCode string) (string, decimal.Decimal, error) {
+	one := decimal.NewFromInt(1)
+	uomCode = strings.TrimSpace(uomCode)
+
+	var productCode, stockUnit string
+	if err := q.QueryRow(ctx,
+		"SELECT code, unit FROM products WHERE id = $1", productID,
+	).Scan(&productCode, &stockUnit); err != nil {
+		return "", decimal.Zero, fmt.Errorf("resolve product %d unit: %w", productID, err)
+	}
+	if uomCode == "" || strings.EqualFold(uomCode, stockUnit) {
+		return stockUnit, one, nil
+	}
+
+	var code string
+	var factor decimal.Decimal
+	err := q.QueryRow(ctx, `
+		SELECT u.code, c.factor
+		FROM product_uom_conversions c
+		JOIN units_of_measure u ON u.id = c.uom_id
+		WHERE c.product_id = $1 AND UPPER(u.code) = UPPER($2) AND u.is_active = true`,
+		productID, uomCode,
+	).Scan(&code, &factor)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", decimal.Zero, fmt.Errorf("unit %s is not compatible with product %s: no conversion to stock unit %s",
+				uomCode, productCode, stockUnit)
+		}
+		return "", decimal.Zero, fmt.Errorf("resolve unit %s for product %s: %w", uomCode, productCode, err)
+	}
+	return code, factor, nil
+}
@@ -0,0 +1,120 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"accounting-agent/internal/core"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestVendorBill_CreateAndPay(t *testing.T) {
+	pool, _, ledger, docService, _, _, ctx := setupReceivePOTestDB(t)
+	defer pool.Close()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO accounts (company_id, code, name, type) VALUES
+		(1, '5200', 'Utilities Expense', 'expense')
+		ON CONFLICT (company_id, code) DO NOTHING;
+	`)
+	if err != nil {
+		t.Fatalf("seed vendor bill test data: %v", err)
+	}
+
+	companyCode := "1000"
+	billSvc := core.NewVendorBillService(pool, core.NewRuleEngine(pool), core.NewTaxEngine(pool))
+
+	// accountBalance returns debits minus credits posted to an account.
+	accountBalance := func(t *testing.T, code string) decimal.Decimal {
+		t.Helper()
+		var bal decimal.Decimal
+		if err := pool.QueryRow(ctx, `
+			SELECT COALESCE(SUM(jl.debit_base - jl.credit_base), 0)
+			FROM journal_lines jl
+			JOIN accounts a ON a.id = jl.account_id
+			WHERE a.company_id = 1 AND a.code = $1`, code,
+		).Scan(&bal); err != nil {
+			t.Fatalf("balance of %s: %v", code, err)
+		}
+		return bal
+	}
+
+	bill, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+		VendorCode: "V001",
+		BillNumber: "ELEC-2026-04",
+		BillDate:   "2026-04-05",
+		Lines: []core.VendorBillLineInput{
+			{Description: "Electricity April", UnitCost: decimal.NewFromInt(1800), ExpenseAccountCode: "5200"},
+			{Description: "Courier charges", Quantity: decimal.NewFromInt(3), UnitCost: decimal.NewFromInt(100), ExpenseAccountCode: "5100"},
+		},
+	}, ledger, docService)
+	if err != nil {
+		t.Fatalf("CreateBill: %v", err)
+	}
+
+	// V001 has 30-day terms.
+	if bill.DueDate != "2026-05-05" {
+		t.Errorf("expected due date 2026-05-05, got %s", bill.DueDate)
+	}
+	if bill.Status != "POSTED" || bill.PIDocumentNumber == nil || bill.JournalDocumentNumber == nil {
+		t.Fatalf("expected POSTED bill with PI and journal numbers, got %s (PI %v, JE %v)",
+			bill.Status, bill.PIDocumentNumber, bill.JournalDocumentNumber)
+	}
+	if !bill.TotalAmount.Equal(decimal.NewFromInt(2100)) {
+		t.Errorf("expected total 2100, got %s", bill.TotalAmount)
+	}
+	if got := accountBalance(t, "5200"); !got.Equal(decimal.NewFromInt(1800)) {
+		t.Errorf("utilities expense: expected 1800, got %s", got)
+	}
+	if got := accountBalance(t, "2000"); !got.Equal(decimal.NewFromInt(-2100)) {
+		t.Errorf("AP: expected -2100, got %s", got)
+	}
+
+	t.Run("DuplicateBillNumber_Fails", func(t *testing.T) {
+		_, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+			VendorCode: "V001",
+			BillNumber: "ELEC-2026-04",
+			BillDate:   "2026-04-06",
+			Lines:      []core.VendorBillLineInput{{Description: "Duplicate", UnitCost: decimal.NewFromInt(10), ExpenseAccountCode: "5200"}},
+		}, ledger, docService)
+		if err == nil {
+			t.Error("expected error recording the same bill number twice, got nil")
+		}
+	})
+
+	t.Run("MissingExpenseAccount_Fails", func(t *testing.T) {
+		// V001 has no default expense account.
+		_, err := billSvc.CreateBill(ctx, companyCode, core.VendorBillInput{
+			VendorCode: "V001",
+			BillNumber: "MISC-1",
+			BillDate:   "2026-04-06",
+			Lines:      []core.VendorBillLineInput{{Description: "Sundry", UnitCost: decimal.NewFromInt(10)}},
+		}, ledger, docService)
+		if err == nil {
+			t.Error("expected error for a line without an expense account, got nil")
+		}
+	})
+
+	t.Run("Pay", func(t *testing.T) {
+		if err := billSvc.PayBill(ctx, companyCode, bill.ID, "1000", time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), ledger); err != nil {
+			t.Fatalf("PayBill: %v", err)
+		}
+		got, err := billSvc.GetBill(ctx, companyCode, bill.ID)
+		if err != nil {
+			t.Fatalf("GetBill: %v", err)
+		}
+		if got.Status != "PAID" || got.PaidAt == nil {
+			t.Errorf("expected PAID with paid_at, got %s (paid_at %v)", got.Status, got.PaidAt)
+		}
+		if len(got.Lines) != 2 {
+			t.Errorf("expected 2 lines, got %d", len(got.Lines))
+		}
+		if bal := accountBalance(t, "2000"); !bal.IsZero() {
+			t.Errorf("AP should be cleared after payment, got %s", bal)
+		}
+		if err := billSvc.PayBill(ctx, companyCode, bill.ID, "1000", time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), ledger); err == nil {
+			t.Error("expected error paying a PAID bill, got nil")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// VendorBill is a vendor invoice entered without a purchase order (utilities,
+// professional fees, ad-hoc purchases). It is posted to the GL and AP on entry.
+type VendorBill struct {
+	ID                    int
+	CompanyID             int
+	VendorID              int
+	VendorCode            string
+	VendorName            string
+	BillNumber            string // vendor's invoice reference
+	BillDate              string // YYYY-MM-DD
+	DueDate               string // YYYY-MM-DD
+	Status                string // POSTED | PAID
+	APAccountCode         string
+	TotalAmount           decimal.Decimal // TaxableAmount + TaxAmount
+	TaxableAmount         decimal.Decimal
+	TaxAmount             decimal.Decimal // input GST
+	PIDocumentNumber      *string
+	JournalDocumentNumber *string
+	Notes                 *string
+	CreatedAt             time.Time
+	PaidAt                *time.Time
+	Lines                 []VendorBillLine
+}
+
+// VendorBillLine is one expense line on a vendor bill.
+// ProductCode is informational; bills do not move stock.
+type VendorBillLine struct {
+	ID                 int
+	LineNumber         int
+	ProductCode        *string
+	Description        string
+	Quantity           decimal.Decimal
+	UnitCost           decimal.Decimal
+	LineAmount         decimal.Decimal // excluding GST
+	ExpenseAccountCode string
+	TaxCode            *string
+	TaxAmount          decimal.Decimal
+	Taxes              []LineTax
+}
+
+// VendorBillLineInput is one line on a new vendor bill.
+// ExpenseAccountCode defaults to the vendor's default expense account; Description
+// defaults to the product name when ProductCode is set. TaxCode defaults to the
+// product's tax code; a line without one carries no GST.
+type VendorBillLineInput struct {
+	ProductCode        string
+	Description        string
+	Quantity           decimal.Decimal
+	UnitCost           decimal.Decimal
+	ExpenseAccountCode string
+	TaxCode            string
+}
+
+// VendorBillInput holds the fields required to post a vendor bill.
+// An empty DueDate is BillDate plus the vendor's payment terms.
+type VendorBillInput struct {
+	VendorCode string
+	BillNumber string
+	BillDate   string // YYYY-MM-DD
+	DueDate    string // YYYY-MM-DD, optional
+	Notes      string
+	Lines      []VendorBillLineInput
+}
+
+// VendorBillService records and pays vendor bills that have no purchase order.
+type VendorBillService interface {
+	// CreateBill validates the bill, assigns a PI document number and posts
+	// DR expense (per line) / DR input GST (per component) / CR vendor AP in one
+	// transaction. GST is computed at the bill date. Status is POSTED.
+	CreateBill(ctx context.Context, companyCode string, input VendorBillInput, ledger *Ledger, docService DocumentService) (*VendorBill, error)
+
+	// GetBills returns a company's vendor bills, newest first, optionally filtered by status (headers only).
+	GetBills(ctx context.Context, companyCode, status string) ([]VendorBill, error)
+
+	// GetBill returns one vendor bill with its lines.
+	GetBill(ctx context.Context, companyCode string, billID int) (*VendorBill, error)
+
+	// PayBill records payment of a POSTED bill: DR AP / CR bankAccountCode, status → PAID.
+	// TDS is withheld as in PurchaseOrderService.PayVendor and credited to TDS_PAYABLE.
+	PayBill(ctx context.Context, companyCode string, billID int, bankAccountCode string, paymentDate time.Time, ledger *Ledger) error
+}
@@ -0,0 +1,464 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type vendorBillService struct {
+	pool       *pgxpool.Pool
+	ruleEngine RuleEngine
+	taxEngine  TaxEngine
+}
+
+// NewVendorBillService constructs a VendorBillService backed by PostgreSQL.
+func NewVendorBillService(pool *pgxpool.Pool, ruleEngine RuleEngine, taxEngine TaxEngine) VendorBillService {
+	return &vendorBillService{pool: pool, ruleEngine: ruleEngine, taxEngine: taxEngine}
+}
+
+// ── CreateBill ────────────────────────────────────────────────────────────────
+
+func (s *vendorBillService) CreateBill(ctx context.Context, companyCode string, input VendorBillInput, ledger *Ledger, docService DocumentService) (*VendorBill, error) {
+	billNumber := strings.TrimSpace(input.BillNumber)
+	if billNumber == "" {
+		return nil, fmt.Errorf("bill number is required")
+	}
+	billDate, err := time.Parse("2006-01-02", input.BillDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bill date %q (expected YYYY-MM-DD)", input.BillDate)
+	}
+	if len(input.Lines) == 0 {
+		return nil, fmt.Errorf("vendor bill must have at least one line")
+	}
+
+	var companyID int
+	var baseCurrency string
+	if err := s.pool.QueryRow(ctx,
+		"SELECT id, base_currency FROM companies WHERE company_code = $1", companyCode,
+	).Scan(&companyID, &baseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("company %s not found", companyCode)
+		}
+		return nil, fmt.Errorf("failed to resolve company %s: %w", companyCode, err)
+	}
+
+	var vendorID, termsDays int
+	var vendorName, apAccount string
+	var defaultExpense, vendorState *string
+	var active bool
+	if err := s.pool.QueryRow(ctx, `
+		SELECT id, name, COALESCE(ap_account_code, '2000'), default_expense_account_code,
+		       payment_terms_days, state_code, is_active
+		FROM vendors
+		WHERE company_id = $1 AND code = $2`,
+		companyID, input.VendorCode,
+	).Scan(&vendorID, &vendorName, &apAccount, &defaultExpense, &termsDays, &vendorState, &active); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("vendor %s not found", input.VendorCode)
+		}
+		return nil, fmt.Errorf("resolve vendor: %w", err)
+	}
+	if !active {
+		return nil, fmt.Errorf("vendor %s is inactive", input.VendorCode)
+	}
+
+	dueDate := billDate.AddDate(0, 0, termsDays)
+	if input.DueDate != "" {
+		dueDate, err = time.Parse("2006-01-02", input.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date %q (expected YYYY-MM-DD)", input.DueDate)
+		}
+		if dueDate.Before(billDate) {
+			return nil, fmt.Errorf("due date %s is before bill date %s", input.DueDate, input.BillDate)
+		}
+	}
+
+	type billLine struct {
+		productID *int
+		line      VendorBillLine
+	}
+	gst := newGSTSupply(s.taxEngine, s.pool, companyID, billDate, "vendor "+input.VendorCode, vendorState)
+	lines := make([]billLine, len(input.Lines))
+	total, taxTotal := decimal.Zero, decimal.Zero
+	var allTaxes []LineTax
+	for i, in := range input.Lines {
+		bl := billLine{line: VendorBillLine{
+			LineNumber:         i + 1,
+			Description:        strings.TrimSpace(in.Description),
+			Quantity:           in.Quantity,
+			UnitCost:           in.UnitCost,
+			ExpenseAccountCode: strings.TrimSpace(in.ExpenseAccountCode),
+		}}
+		if bl.line.Quantity.IsZero() {
+			bl.line.Quantity = decimal.NewFromInt(1)
+		}
+		if bl.line.Quantity.IsNegative() {
+			return nil, fmt.Errorf("line %d: quantity must be positive", i+1)
+		}
+		if bl.line.UnitCost.IsNegative() {
+			return nil, fmt.Errorf("line %d: unit cost must not be negative", i+1)
+		}
+		taxCode := strings.ToUpper(strings.TrimSpace(in.TaxCode))
+		if in.ProductCode != "" {
+			var id int
+			var name string
+			var productTax *string
+			if err := s.pool.QueryRow(ctx,
+				"SELECT id, name, tax_code FROM products WHERE company_id = $1 AND code = $2",
+				companyID, in.ProductCode,
+			).Scan(&id, &name, &productTax); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil, fmt.Errorf("line %d: product %s not found", i+1, in.ProductCode)
+				}
+				return nil, fmt.Errorf("line %d: resolve product: %w", i+1, err)
+			}
+			bl.productID = &id
+			code := in.ProductCode
+			bl.line.ProductCode = &code
+			if bl.line.Description == "" {
+				bl.line.Description = name
+			}
+			if taxCode == "" && productTax != nil {
+				taxCode = *productTax
+			}
+		}
+		if bl.line.Description == "" {
+			return nil, fmt.Errorf("line %d: description is required", i+1)
+		}
+		if bl.line.ExpenseAccountCode == "" {
+			if defaultExpense == nil || *defaultExpense == "" {
+				return nil, fmt.Errorf("line %d: expense account code is required (vendor %s has no default expense account)", i+1, input.VendorCode)
+			}
+			bl.line.ExpenseAccountCode = *defaultExpense
+		}
+		bl.line.LineAmount = bl.line.Quantity.Mul(bl.line.UnitCost).Round(2)
+		if taxCode != "" {
+			taxes, err := gst.lineTax(ctx, taxCode, bl.line.LineAmount)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			bl.line.TaxCode = &taxCode
+			bl.line.Taxes = taxes
+			bl.line.TaxAmount = sumTax(taxes)
+			taxTotal = taxTotal.Add(bl.line.TaxAmount)
+			allTaxes = append(allTaxes, taxes...)
+		}
+		total = total.Add(bl.line.LineAmount)
+		lines[i] = bl
+	}
+	if !total.IsPositive() {
+		return nil, fmt.Errorf("vendor bill total must be positive")
+	}
+	gstLines, err := gstProposalLines(ctx, s.ruleEngine, companyID, allTaxes, true)
+	if err != nil {
+		return nil, fmt.Errorf("resolve GST accounts: %w", err)
+	}
+	total = total.Add(taxTotal)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM vendor_bills WHERE company_id = $1 AND vendor_id = $2 AND bill_number = $3)",
+		companyID, vendorID, billNumber,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check duplicate bill: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("bill %s from vendor %s has already been recorded", billNumber, input.VendorCode)
+	}
+
+	var notes *string
+	if n := strings.TrimSpace(input.Notes); n != "" {
+		notes = &n
+	}
+	var billID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO vendor_bills
+		    (company_id, vendor_id, bill_number, bill_date, due_date, ap_account_code, total_amount, tax_amount, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		companyID, vendorID, billNumber, input.BillDate, dueDate.Format("2006-01-02"), apAccount, total, taxTotal, notes,
+	).Scan(&billID); err != nil {
+		return nil, fmt.Errorf("insert vendor bill: %w", err)
+	}
+
+	// Debit lines are summed per expense account, in first-seen order.
+	var accountOrder []string
+	byAccount := map[string]decimal.Decimal{}
+	for _, bl := range lines {
+		l := bl.line
+		var lineID int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO vendor_bill_lines
+			    (bill_id, line_number, product_id, description, quantity, unit_cost, line_amount, expense_account_code,
+			     tax_code, tax_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id`,
+			billID, l.LineNumber, bl.productID, l.Description, l.Quantity, l.UnitCost, l.LineAmount, l.ExpenseAccountCode,
+			l.TaxCode, l.TaxAmount,
+		).Scan(&lineID); err != nil {
+			return nil, fmt.Errorf("insert vendor bill line %d: %w", l.LineNumber, err)
+		}
+		if err := insertLineTaxesTx(ctx, tx, companyID, lineTaxVendorBillLine, lineID, l.Taxes); err != nil {
+			return nil, err
+		}
+		if _, ok := byAccount[l.ExpenseAccountCode]; !ok {
+			accountOrder = append(accountOrder, l.ExpenseAccountCode)
+		}
+		byAccount[l.ExpenseAccountCode] = byAccount[l.ExpenseAccountCode].Add(l.LineAmount)
+	}
+
+	// PI number from the same sequence as PO invoices.
+	var draftDocID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO documents (company_id, type_code, status, financial_year, branch_id)
+		VALUES ($1, 'PI', 'DRAFT', $2, NULL)
+		RETURNING id`,
+		companyID, billDate.Year(),
+	).Scan(&draftDocID); err != nil {
+		return nil, fmt.Errorf("create PI document: %w", err)
+	}
+	if err := docService.PostDocumentTx(ctx, tx, draftDocID); err != nil {
+		return nil, fmt.Errorf("post PI document: %w", err)
+	}
+	var piDocNumber string
+	if err := tx.QueryRow(ctx,
+		"SELECT document_number FROM documents WHERE id = $1", draftDocID,
+	).Scan(&piDocNumber); err != nil {
+		return nil, fmt.Errorf("retrieve PI document number: %w", err)
+	}
+
+	proposalLines := make([]ProposalLine, 0, len(accountOrder)+len(gstLines)+1)
+	for _, acc := range accountOrder {
+		proposalLines = append(proposalLines, ProposalLine{AccountCode: acc, IsDebit: true, Amount: byAccount[acc].StringFixed(2)})
+	}
+	proposalLines = append(proposalLines, gstLines...)
+	proposalLines = append(proposalLines, ProposalLine{AccountCode: apAccount, IsDebit: false, Amount: total.StringFixed(2)})
+
+	reasoning := fmt.Sprintf("Vendor bill without purchase order, due %s.", dueDate.Format("2006-01-02"))
+	if taxTotal.IsPositive() {
+		reasoning += fmt.Sprintf(" Input GST of %s taken as credit.", taxTotal.StringFixed(2))
+	}
+
+	idempotencyKey := fmt.Sprintf("vendor-bill-%d", billID)
+	proposal := Proposal{
+		DocumentTypeCode:    "JE",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      idempotencyKey,
+		TransactionCurrency: baseCurrency,
+		ExchangeRate:        "1",
+		Summary:             fmt.Sprintf("Vendor bill %s from %s (%s)", billNumber, vendorName, piDocNumber),
+		PostingDate:         input.BillDate,
+		DocumentDate:        input.BillDate,
+		Confidence:          1.0,
+		Reasoning:           reasoning,
+		Lines:               proposalLines,
+	}
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return nil, fmt.Errorf("post vendor bill journal entry: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE vendor_bills
+		SET pi_document_number = $1,
+		    journal_document_number = (SELECT reference_id FROM journal_entries WHERE idempotency_key = $2)
+		WHERE id = $3`,
+		piDocNumber, idempotencyKey, billID,
+	); err != nil {
+		return nil, fmt.Errorf("set vendor bill document numbers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit vendor bill: %w", err)
+	}
+
+	return s.GetBill(ctx, companyCode, billID)
+}
+
+// ── PayBill ───────────────────────────────────────────────────────────────────
+
+func (s *vendorBillService) PayBill(ctx context.Context, companyCode string, billID int, bankAccountCode string, paymentDate time.Time, ledger *Ledger) error {
+	if bankAccountCode == "" {
+		return fmt.Errorf("bank account code is required")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var companyID, vendorID int
+	var status, billNumber, apAccount, baseCurrency string
+	var total, taxTotal decimal.Decimal
+	if err := tx.QueryRow(ctx, `
+		SELECT vb.company_id, vb.vendor_id, vb.status, vb.bill_number, vb.ap_account_code, vb.total_amount, vb.tax_amount,
+		       c.base_currency
+		FROM vendor_bills vb
+		JOIN companies c ON c.id = vb.company_id
+		WHERE vb.id = $1 AND c.company_code = $2
+		FOR UPDATE OF vb`,
+		billID, companyCode,
+	).Scan(&companyID, &vendorID, &status, &billNumber, &apAccount, &total, &taxTotal, &baseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("vendor bill %d not found", billID)
+		}
+		return fmt.Errorf("fetch vendor bill %d: %w", billID, err)
+	}
+	if status != "POSTED" {
+		return fmt.Errorf("vendor bill %d cannot be paid: status is %s (must be POSTED)", billID, status)
+	}
+
+	// TDS is withheld on the bill excluding GST.
+	tds, err := vendorTDSTx(ctx, tx, companyID, vendorID, total.Sub(taxTotal), paymentDate, true)
+	if err != nil {
+		return err
+	}
+	reasoning := fmt.Sprintf("Payment of vendor bill %d.", billID)
+	lines := []ProposalLine{{AccountCode: apAccount, IsDebit: true, Amount: total.StringFixed(2)}}
+	if bank := tds.netOf(total); bank.IsPositive() {
+		lines = append(lines, ProposalLine{AccountCode: bankAccountCode, IsDebit: false, Amount: bank.StringFixed(2)})
+	}
+	tdsLine, err := tds.tdsPayableLine(ctx, s.ruleEngine)
+	if err != nil {
+		return err
+	}
+	if tdsLine != nil {
+		lines = append(lines, *tdsLine)
+		reasoning += fmt.Sprintf(" TDS of %s withheld under %s.", tds.TDSAmount.StringFixed(2), tds.section.Code)
+	}
+
+	paymentDateStr := paymentDate.Format("2006-01-02")
+	idempotencyKey := fmt.Sprintf("pay-vendor-bill-%d", billID)
+	proposal := Proposal{
+		DocumentTypeCode:    "JE",
+		CompanyCode:         companyCode,
+		IdempotencyKey:      idempotencyKey,
+		TransactionCurrency: baseCurrency,
+		ExchangeRate:        "1",
+		Summary:             fmt.Sprintf("Vendor payment for bill %s", billNumber),
+		PostingDate:         paymentDateStr,
+		DocumentDate:        paymentDateStr,
+		Confidence:          1.0,
+		Reasoning:           reasoning,
+		Lines:               lines,
+	}
+	if err := ledger.CommitInTx(ctx, tx, proposal); err != nil {
+		return fmt.Errorf("post payment journal entry for vendor bill %d: %w", billID, err)
+	}
+	if err := tds.record(ctx, tx, tdsSource{billID: &billID}, idempotencyKey); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE vendor_bills SET status = 'PAID', paid_at = NOW() WHERE id = $1", billID,
+	); err != nil {
+		return fmt.Errorf("update vendor bill %d to PAID: %w", billID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit vendor bill payment: %w", err)
+	}
+	return nil
+}
+
+// ── Queries ───────────────────────────────────────────────────────────────────
+
+const vendorBillSelect = `
+	SELECT vb.id, vb.company_id, vb.vendor_id, v.code, v.name, vb.bill_number,
+	       vb.bill_date::text, vb.due_date::text, vb.status, vb.ap_account_code, vb.total_amount, vb.tax_amount,
+	       vb.pi_document_number, vb.journal_document_number, vb.notes, vb.created_at, vb.paid_at
+	FROM vendor_bills vb
+	JOIN vendors v   ON v.id = vb.vendor_id
+	JOIN companies c ON c.id = vb.company_id`
+
+func scanVendorBill(row pgx.Row) (VendorBill, error) {
+	var b VendorBill
+	err := row.Scan(&b.ID, &b.CompanyID, &b.VendorID, &b.VendorCode, &b.VendorName, &b.BillNumber,
+		&b.BillDate, &b.DueDate, &b.Status, &b.APAccountCode, &b.TotalAmount, &b.TaxAmount,
+		&b.PIDocumentNumber, &b.JournalDocumentNumber, &b.Notes, &b.CreatedAt, &b.PaidAt)
+	b.TaxableAmount = b.TotalAmount.Sub(b.TaxAmount)
+	return b, err
+}
+
+func (s *vendorBillService) GetBills(ctx context.Context, companyCode, status string) ([]VendorBill, error) {
+	rows, err := s.pool.Query(ctx, vendorBillSelect+`
+		WHERE c.company_code = $1 AND ($2 = '' OR vb.status = $2)
+		ORDER BY vb.bill_date DESC, vb.id DESC`,
+		companyCode, strings.ToUpper(status),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query vendor bills: %w", err)
+	}
+	defer rows.Close()
+
+	var bills []VendorBill
+	for rows.Next() {
+		b, err := scanVendorBill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan vendor bill: %w", err)
+		}
+		bills = append(bills, b)
+	}
+	return bills, rows.Err()
+}
+
+func (s *vendorBillService) GetBill(ctx context.Context, companyCode string, billID int) (*VendorBill, error) {
+	b, err := scanVendorBill(s.pool.QueryRow(ctx, vendorBillSelect+`
+		WHERE c.company_code = $1 AND vb.id = $2`, companyCode, billID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("vendor bill %d not found", billID)
+		}
+		return nil, fmt.Errorf("fetch vendor bill: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT vbl.id, vbl.line_number, p.code, vbl.description, vbl.quantity, vbl.unit_cost,
+		       vbl.line_amount, vbl.expense_account_code, vbl.tax_code, vbl.tax_amount
+		FROM vendor_bill_lines vbl
+		LEFT JOIN products p ON p.id = vbl.product_id
+		WHERE vbl.bill_id = $1
+		ORDER BY vbl.line_number`, billID)
+	if err != nil {
+		return nil, fmt.Errorf("query vendor bill lines: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l VendorBillLine
+		if err := rows.Scan(&l.ID, &l.LineNumber, &l.ProductCode, &l.Description, &l.Quantity, &l.UnitCost,
+			&l.LineAmount, &l.ExpenseAccountCode, &l.TaxCode, &l.TaxAmount); err != nil {
+			return nil, fmt.Errorf("scan vendor bill line: %w", err)
+		}
+		b.Lines = append(b.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vendor bill lines: %w", err)
+	}
+	rows.Close()
+
+	lineIDs := make([]int, len(b.Lines))
+	for i, l := range b.Lines {
+		lineIDs[i] = l.ID
+	}
+	taxes, err := fetchLineTaxesQ(ctx, s.pool, lineTaxVendorBillLine, lineIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range b.Lines {
+		b.Lines[i].Taxes = taxes[b.Lines[i].ID]
+	}
+	return &b, nil
+}
@@ -7,32 +7,52 @@ import (
 
 // Vendor represents a supplier or service provider in the accounts payable system.
 type Vendor struct {
-	ID                       int
-	CompanyID                int
-	Code                     string
-	Name                     string
-	ContactPerson            *string
-	Email                    *string
-	Phone                    *string
-	Address                  *string
-	PaymentTermsDays         int
-	APAccountCode            string
+	ID                        int
+	CompanyID                 int
+	Code                      string
+	Name                      string
+	ContactPerson             *string
+	Email                     *string
+	Phone                     *string
+	Address                   *string
+	PaymentTermsDays          int
+	APAccountCode             string
 	DefaultExpenseAccountCode *string
-	IsActive                 bool
-	CreatedAt                time.Time
+	BankAccountName           *string
+	BankAccountNumber         *string
+	BankCode                  *string // BIC or local clearing code (e.g. IFSC)
+	Currency                  *string // default purchasing currency; nil = company base currency
+	PAN                       *string // Indian permanent account number
+	TDSSectionCode            *string // TDS withheld on payments under this section; nil = no TDS
+	StateCode                 *string // GST state code; equal to the company's = intra-state purchases
+	GSTIN                     *string // GST registration number
+	IsActive                  bool
+	Version                   int  // optimistic-concurrency token; incremented by every update
+	MergedIntoID              *int // set when the vendor was merged into another vendor
+	CreatedAt                 time.Time
+	UpdatedAt                 *time.Time
 }
 
-// VendorInput holds the fields required to create a new vendor.
+// VendorInput holds the fields required to create a new vendor. UpdateVendor takes the
+// same fields; Code is ignored there because a vendor's code cannot change.
 type VendorInput struct {
-	Code                     string
-	Name                     string
-	ContactPerson            string
-	Email                    string
-	Phone                    string
-	Address                  string
-	PaymentTermsDays         int
-	APAccountCode            string
+	Code                      string
+	Name                      string
+	ContactPerson             string
+	Email                     string
+	Phone                     string
+	Address                   string
+	PaymentTermsDays          int
+	APAccountCode             string
 	DefaultExpenseAccountCode string
+	BankAccountName           string
+	BankAccountNumber         string
+	BankCode                  string
+	Currency                  string // optional default purchasing currency (ISO 4217)
+	PAN                       string // optional; 10 characters, e.g. AAACT1234F
+	TDSSectionCode            string // optional TDS section code, e.g. 194C-OTH
+	StateCode                 string // optional two-digit GST state code; taken from GSTIN when empty
+	GSTIN                     string // optional GST registration number, e.g. 27AAPFU0939F1ZV
 }
 
 // VendorService provides vendor master data operations.
@@ -45,4 +65,17 @@ type VendorService interface {
 
 	// GetVendorByCode returns a specific vendor by its code, scoped to the company.
 	GetVendorByCode(ctx context.Context, companyID int, code string) (*Vendor, error)
+
+	// UpdateVendor replaces a vendor's editable fields. version must be the vendor's current
+	// version; otherwise ErrVersionConflict is returned and nothing is changed.
+	UpdateVendor(ctx context.Context, companyID int, code string, version int, input VendorInput) (*Vendor, error)
+
+	// SetVendorActive deactivates or reactivates a vendor. Inactive vendors are rejected on
+	// new purchase orders and vendor bills; existing documents are unaffected.
+	SetVendorActive(ctx context.Context, companyID int, code string, active bool) (*Vendor, error)
+
+	// MergeVendors repoints the duplicate vendor's purchase orders, bills, returns, landed
+	// cost vouchers, reorder policies, unposted payment run items and TDS deductions to the
+	// survivor, then deactivates the duplicate and records the merge on it.
+	MergeVendors(ctx context.Context, companyID int, duplicateCode, survivorCode string) (*MergeResult, error)
 }
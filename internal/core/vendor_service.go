@@ -2,8 +2,12 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,8 +20,30 @@ func NewVendorService(pool *pgxpool.Pool) VendorService {
 	return &vendorService{pool: pool}
 }
 
-// CreateVendor inserts a new vendor record for the given company.
-func (s *vendorService) CreateVendor(ctx context.Context, companyID int, input VendorInput) (*Vendor, error) {
+// vendorColumns is the column list scanned by scanVendor.
+const vendorColumns = `id, company_id, code, name, contact_person, email, phone, address,
+	payment_terms_days, ap_account_code, default_expense_account_code,
+	bank_account_name, bank_account_number, bank_code, currency, pan, tds_section_code,
+	state_code, gstin, is_active, version, merged_into_id, created_at, updated_at`
+
+func scanVendor(row pgx.Row) (Vendor, error) {
+	var v Vendor
+	err := row.Scan(
+		&v.ID, &v.CompanyID, &v.Code, &v.Name,
+		&v.ContactPerson, &v.Email, &v.Phone, &v.Address,
+		&v.PaymentTermsDays, &v.APAccountCode, &v.DefaultExpenseAccountCode,
+		&v.BankAccountName, &v.BankAccountNumber, &v.BankCode, &v.Currency, &v.PAN, &v.TDSSectionCode,
+		&v.StateCode, &v.GSTIN, &v.IsActive, &v.Version, &v.MergedIntoID, &v.CreatedAt, &v.UpdatedAt,
+	)
+	return v, err
+}
+
+// panPattern matches an Indian permanent account number.
+var panPattern = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+
+// vendorFields applies the create/update defaults to input and returns the column values
+// in vendorColumns order, from name onwards.
+func vendorFields(input VendorInput) ([]any, error) {
 	apAccountCode := input.APAccountCode
 	if apAccountCode == "" {
 		apAccountCode = "2000"
@@ -27,9 +53,19 @@ func (s *vendorService) CreateVendor(ctx context.Context, companyID int, input V
 		paymentTerms = 30
 	}
 
-	var expenseCode *string
-	if input.DefaultExpenseAccountCode != "" {
-		expenseCode = &input.DefaultExpenseAccountCode
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if currency != "" && len(currency) != 3 {
+		return nil, fmt.Errorf("invalid currency %q: expected a 3-letter ISO code", input.Currency)
+	}
+
+	pan := strings.ToUpper(strings.TrimSpace(input.PAN))
+	if pan != "" && !panPattern.MatchString(pan) {
+		return nil, fmt.Errorf("invalid PAN %q: expected 5 letters, 4 digits and a letter", input.PAN)
+	}
+	tdsSection := strings.ToUpper(strings.TrimSpace(input.TDSSectionCode))
+	stateCode, gstin, err := gstRegistration(input.StateCode, input.GSTIN)
+	if err != nil {
+		return nil, err
 	}
 
 	toPtr := func(s string) *string {
@@ -39,32 +75,43 @@ func (s *vendorService) CreateVendor(ctx context.Context, companyID int, input V
 		return &s
 	}
 
-	v := &Vendor{}
-	err := s.pool.QueryRow(ctx, `
+	return []any{
+		input.Name, toPtr(input.ContactPerson), toPtr(input.Email), toPtr(input.Phone), toPtr(input.Address),
+		paymentTerms, apAccountCode, toPtr(input.DefaultExpenseAccountCode),
+		toPtr(input.BankAccountName), toPtr(input.BankAccountNumber), toPtr(input.BankCode), toPtr(currency),
+		toPtr(pan), toPtr(tdsSection), stateCode, gstin,
+	}, nil
+}
+
+// CreateVendor inserts a new vendor record for the given company.
+func (s *vendorService) CreateVendor(ctx context.Context, companyID int, input VendorInput) (*Vendor, error) {
+	fields, err := vendorFields(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTDSSection(ctx, input.TDSSectionCode); err != nil {
+		return nil, err
+	}
+
+	v, err := scanVendor(s.pool.QueryRow(ctx, `
 		INSERT INTO vendors (company_id, code, name, contact_person, email, phone, address,
-		                     payment_terms_days, ap_account_code, default_expense_account_code)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, company_id, code, name, contact_person, email, phone, address,
-		          payment_terms_days, ap_account_code, default_expense_account_code, is_active, created_at`,
-		companyID, input.Code, input.Name, toPtr(input.ContactPerson), toPtr(input.Email),
-		toPtr(input.Phone), toPtr(input.Address), paymentTerms, apAccountCode, expenseCode,
-	).Scan(
-		&v.ID, &v.CompanyID, &v.Code, &v.Name,
-		&v.ContactPerson, &v.Email, &v.Phone, &v.Address,
-		&v.PaymentTermsDays, &v.APAccountCode, &v.DefaultExpenseAccountCode,
-		&v.IsActive, &v.CreatedAt,
-	)
+		                     payment_terms_days, ap_account_code, default_expense_account_code,
+		                     bank_account_name, bank_account_number, bank_code, currency, pan, tds_section_code,
+		                     state_code, gstin)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING `+vendorColumns,
+		append([]any{companyID, input.Code}, fields...)...,
+	))
 	if err != nil {
 		return nil, fmt.Errorf("create vendor %q: %w", input.Code, err)
 	}
-	return v, nil
+	return &v, nil
 }
 
 // GetVendors returns all active vendors for a company, ordered by code.
 func (s *vendorService) GetVendors(ctx context.Context, companyID int) ([]Vendor, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, company_id, code, name, contact_person, email, phone, address,
-		       payment_terms_days, ap_account_code, default_expense_account_code, is_active, created_at
+		SELECT `+vendorColumns+`
 		FROM vendors
 		WHERE company_id = $1 AND is_active = true
 		ORDER BY code`,
@@ -77,13 +124,8 @@ func (s *vendorService) GetVendors(ctx context.Context, companyID int) ([]Vendor
 
 	var vendors []Vendor
 	for rows.Next() {
-		var v Vendor
-		if err := rows.Scan(
-			&v.ID, &v.CompanyID, &v.Code, &v.Name,
-			&v.ContactPerson, &v.Email, &v.Phone, &v.Address,
-			&v.PaymentTermsDays, &v.APAccountCode, &v.DefaultExpenseAccountCode,
-			&v.IsActive, &v.CreatedAt,
-		); err != nil {
+		v, err := scanVendor(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan vendor: %w", err)
 		}
 		vendors = append(vendors, v)
@@ -93,21 +135,229 @@ func (s *vendorService) GetVendors(ctx context.Context, companyID int) ([]Vendor
 
 // GetVendorByCode returns a vendor by code, scoped to the company.
 func (s *vendorService) GetVendorByCode(ctx context.Context, companyID int, code string) (*Vendor, error) {
-	v := &Vendor{}
-	err := s.pool.QueryRow(ctx, `
-		SELECT id, company_id, code, name, contact_person, email, phone, address,
-		       payment_terms_days, ap_account_code, default_expense_account_code, is_active, created_at
+	v, err := scanVendor(s.pool.QueryRow(ctx, `
+		SELECT `+vendorColumns+`
 		FROM vendors
 		WHERE company_id = $1 AND code = $2`,
 		companyID, code,
-	).Scan(
-		&v.ID, &v.CompanyID, &v.Code, &v.Name,
-		&v.ContactPerson, &v.Email, &v.Phone, &v.Address,
-		&v.PaymentTermsDays, &v.APAccountCode, &v.DefaultExpenseAccountCode,
-		&v.IsActive, &v.CreatedAt,
-	)
+	))
 	if err != nil {
 		return nil, fmt.Errorf("vendor %q not found: %w", code, err)
 	}
-	return v, nil
+	return &v, nil
+}
+
+// ── Maintenance ───────────────────────────────────────────────────────────────
+
+// UpdateVendor replaces a vendor's editable fields if version is still current.
+func (s *vendorService) UpdateVendor(ctx context.Context, companyID int, code string, version int, input VendorInput) (*Vendor, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, fmt.Errorf("vendor name is required")
+	}
+	fields, err := vendorFields(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTDSSection(ctx, input.TDSSectionCode); err != nil {
+		return nil, err
+	}
+
+	v, err := scanVendor(s.pool.QueryRow(ctx, `
+		UPDATE vendors
+		SET name = $4, contact_person = $5, email = $6, phone = $7, address = $8,
+		    payment_terms_days = $9, ap_account_code = $10, default_expense_account_code = $11,
+		    bank_account_name = $12, bank_account_number = $13, bank_code = $14, currency = $15,
+		    pan = $16, tds_section_code = $17, state_code = $18, gstin = $19, version = version + 1, updated_at = NOW()
+		WHERE company_id = $1 AND code = $2 AND version = $3
+		RETURNING `+vendorColumns,
+		append([]any{companyID, code, version}, fields...)...,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, s.versionMismatch(ctx, companyID, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update vendor %q: %w", code, err)
+	}
+	return &v, nil
+}
+
+// checkTDSSection rejects an unknown or inactive TDS section code; empty means no TDS.
+func (s *vendorService) checkTDSSection(ctx context.Context, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil
+	}
+	var exists bool
+	if err := s.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM tds_sections WHERE code = $1 AND is_active)", code,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check TDS section %s: %w", code, err)
+	}
+	if !exists {
+		return fmt.Errorf("unknown TDS section %q", code)
+	}
+	return nil
+}
+
+// versionMismatch explains why a versioned update matched no row: the vendor is either
+// missing or was changed since the caller read it.
+func (s *vendorService) versionMismatch(ctx context.Context, companyID int, code string) error {
+	var exists bool
+	if err := s.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM vendors WHERE company_id = $1 AND code = $2)", companyID, code,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check vendor %q: %w", code, err)
+	}
+	if !exists {
+		return fmt.Errorf("vendor %q not found", code)
+	}
+	return fmt.Errorf("update vendor %q: %w", code, ErrVersionConflict)
+}
+
+// SetVendorActive deactivates or reactivates a vendor. A merged vendor stays inactive.
+func (s *vendorService) SetVendorActive(ctx context.Context, companyID int, code string, active bool) (*Vendor, error) {
+	current, err := s.GetVendorByCode(ctx, companyID, code)
+	if err != nil {
+		return nil, err
+	}
+	if active && current.MergedIntoID != nil {
+		return nil, fmt.Errorf("vendor %s was merged into another vendor and cannot be reactivated", code)
+	}
+	if current.IsActive == active {
+		return current, nil
+	}
+
+	v, err := scanVendor(s.pool.QueryRow(ctx, `
+		UPDATE vendors
+		SET is_active = $3, version = version + 1, updated_at = NOW()
+		WHERE company_id = $1 AND code = $2
+		RETURNING `+vendorColumns,
+		companyID, code, active,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("set vendor %q active=%t: %w", code, active, err)
+	}
+	return &v, nil
+}
+
+// vendorMergeTables lists every vendor reference repointed by MergeVendors.
+var vendorMergeTables = []struct{ table, column string }{
+	{"purchase_orders", "vendor_id"},
+	{"vendor_bills", "vendor_id"},
+	{"purchase_returns", "vendor_id"},
+	{"landed_cost_vouchers", "vendor_id"},
+	{"reorder_policies", "preferred_vendor_id"},
+	{"payment_runs", "vendor_id"},
+	{"payment_run_items", "vendor_id"},
+	{"tds_deductions", "vendor_id"},
+}
+
+// MergeVendors moves every document of the duplicate vendor to the survivor in one
+// transaction and leaves the duplicate inactive with merged_into_id set.
+// Both vendors must post to the same AP account so open items still clear against the
+// account they were credited to.
+func (s *vendorService) MergeVendors(ctx context.Context, companyID int, duplicateCode, survivorCode string) (*MergeResult, error) {
+	if duplicateCode == "" || survivorCode == "" {
+		return nil, fmt.Errorf("duplicate and survivor vendor codes are required")
+	}
+	if duplicateCode == survivorCode {
+		return nil, fmt.Errorf("cannot merge vendor %s into itself", duplicateCode)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+vendorColumns+`
+		FROM vendors
+		WHERE company_id = $1 AND code IN ($2, $3)
+		ORDER BY id
+		FOR UPDATE`,
+		companyID, duplicateCode, survivorCode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("lock vendors: %w", err)
+	}
+	var duplicate, survivor *Vendor
+	for rows.Next() {
+		v, err := scanVendor(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan vendor: %w", err)
+		}
+		if v.Code == duplicateCode {
+			duplicate = &v
+		} else {
+			survivor = &v
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lock vendors: %w", err)
+	}
+	if duplicate == nil {
+		return nil, fmt.Errorf("vendor %q not found", duplicateCode)
+	}
+	if survivor == nil {
+		return nil, fmt.Errorf("vendor %q not found", survivorCode)
+	}
+	if duplicate.MergedIntoID != nil {
+		return nil, fmt.Errorf("vendor %s has already been merged", duplicateCode)
+	}
+	if !survivor.IsActive {
+		return nil, fmt.Errorf("surviving vendor %s is inactive", survivorCode)
+	}
+	if duplicate.APAccountCode != survivor.APAccountCode {
+		return nil, fmt.Errorf("vendor %s posts to AP account %s but %s posts to %s; align the AP accounts before merging",
+			duplicateCode, duplicate.APAccountCode, survivorCode, survivor.APAccountCode)
+	}
+
+	// Bill numbers are unique per vendor; the same number on both would collide after the merge.
+	var clash string
+	err = tx.QueryRow(ctx, `
+		SELECT d.bill_number
+		FROM vendor_bills d
+		JOIN vendor_bills s ON s.company_id = d.company_id AND s.bill_number = d.bill_number
+		WHERE d.vendor_id = $1 AND s.vendor_id = $2
+		LIMIT 1`,
+		duplicate.ID, survivor.ID,
+	).Scan(&clash)
+	if err == nil {
+		return nil, fmt.Errorf("bill %s is recorded against both %s and %s; resolve the duplicate bill before merging",
+			clash, duplicateCode, survivorCode)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("check bill numbers: %w", err)
+	}
+
+	result := &MergeResult{DuplicateCode: duplicateCode, SurvivorCode: survivorCode, Repointed: map[string]int{}}
+	for _, ref := range vendorMergeTables {
+		tag, err := tx.Exec(ctx,
+			fmt.Sprintf("UPDATE %s SET %s = $2 WHERE %s = $1", ref.table, ref.column, ref.column),
+			duplicate.ID, survivor.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("repoint %s: %w", ref.table, err)
+		}
+		if n := int(tag.RowsAffected()); n > 0 {
+			result.Repointed[ref.table] = n
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE vendors
+		SET is_active = false, merged_into_id = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1`,
+		duplicate.ID, survivor.ID,
+	); err != nil {
+		return nil, fmt.Errorf("deactivate vendor %s: %w", duplicateCode, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit vendor merge: %w", err)
+	}
+	return result, nil
 }
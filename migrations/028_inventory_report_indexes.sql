@@ -0,0 +1,8 @@
+-- Migration 028: Indexes for inventory valuation, movement ledger, and ageing reports.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_company_date
+    ON inventory_movements(company_id, movement_date);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_item_date
+    ON inventory_movements(inventory_item_id, movement_date, id);
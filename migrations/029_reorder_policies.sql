@@ -0,0 +1,23 @@
+-- Migration 029: Reorder policies for stock replenishment.
+-- One row per (company, product, warehouse). A product is suggested for replenishment
+-- when available stock (qty_on_hand - qty_reserved) plus open PO quantity not yet
+-- received falls to or below reorder_point.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS reorder_policies (
+    id                  SERIAL PRIMARY KEY,
+    company_id          INT            NOT NULL REFERENCES companies(id),
+    product_id          INT            NOT NULL REFERENCES products(id),
+    warehouse_id        INT            NOT NULL REFERENCES warehouses(id),
+    reorder_point       NUMERIC(14,4)  NOT NULL DEFAULT 0,
+    reorder_qty         NUMERIC(14,4)  NOT NULL DEFAULT 0,
+    lead_time_days      INT            NOT NULL DEFAULT 0,
+    preferred_vendor_id INT            NULL REFERENCES vendors(id),
+    is_active           BOOLEAN        NOT NULL DEFAULT true,
+    created_at          TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_reorder_policies_cpw UNIQUE (company_id, product_id, warehouse_id),
+    CONSTRAINT chk_reorder_policies_nonneg CHECK (reorder_point >= 0 AND reorder_qty >= 0 AND lead_time_days >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reorder_policies_company ON reorder_policies(company_id) WHERE is_active = true;
@@ -0,0 +1,41 @@
+-- Migration 030: Lot / serial number tracking with expiry dates.
+-- products.tracking_mode:
+--   NONE   — stock is tracked at (product, warehouse) level only (default)
+--   LOT    — every receipt carries a lot number; one lot may hold many units
+--   SERIAL — every unit carries its own serial number (lot of quantity 1)
+-- products.lot_pick_strategy selects lots on shipment when no explicit pick is given:
+--   FEFO — first expiry, first out (lots without expiry last)
+--   FIFO — first received, first out
+-- inventory_items remains the authoritative running total; inventory_lots splits it by lot.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS tracking_mode     VARCHAR(10) NOT NULL DEFAULT 'NONE'
+        CHECK (tracking_mode IN ('NONE', 'LOT', 'SERIAL')),
+    ADD COLUMN IF NOT EXISTS lot_pick_strategy VARCHAR(10) NOT NULL DEFAULT 'FEFO'
+        CHECK (lot_pick_strategy IN ('FEFO', 'FIFO'));
+
+-- ── Inventory Lots ────────────────────────────────────────────────────────────
+-- One row per (inventory_item, lot_number). For SERIAL products lot_number is the serial.
+
+CREATE TABLE IF NOT EXISTS inventory_lots (
+    id                SERIAL PRIMARY KEY,
+    company_id        INT            NOT NULL REFERENCES companies(id),
+    inventory_item_id INT            NOT NULL REFERENCES inventory_items(id),
+    lot_number        VARCHAR(60)    NOT NULL,
+    expiry_date       DATE           NULL,
+    received_date     DATE           NOT NULL,
+    qty_on_hand       NUMERIC(14,4)  NOT NULL DEFAULT 0,
+    unit_cost         NUMERIC(15,6)  NOT NULL DEFAULT 0,
+    created_at        TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_inventory_lots_item_lot UNIQUE (inventory_item_id, lot_number),
+    CONSTRAINT chk_inventory_lots_qty CHECK (qty_on_hand >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_lots_company_lot ON inventory_lots(company_id, lot_number);
+CREATE INDEX IF NOT EXISTS idx_inventory_lots_item_expiry ON inventory_lots(inventory_item_id, expiry_date) WHERE qty_on_hand > 0;
+
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS lot_id INT NULL REFERENCES inventory_lots(id);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_lot ON inventory_movements(lot_id) WHERE lot_id IS NOT NULL;
@@ -0,0 +1,48 @@
+-- Migration 031: Units of measure and per-product conversion factors.
+-- products.unit remains the stock unit: inventory_items quantities and unit costs are
+-- always held in it. Any other unit used on a PO or sales order line must have a row in
+-- product_uom_conversions giving the number of stock units in one of that unit
+-- (e.g. CTN → 24 for a product stocked in EA).
+-- purchase_order_lines / sales_order_lines store the line unit and the factor that was
+-- in force when the line was created, so later master-data changes do not alter history.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS units_of_measure (
+    id         SERIAL PRIMARY KEY,
+    company_id INT          NOT NULL REFERENCES companies(id),
+    code       VARCHAR(20)  NOT NULL,
+    name       TEXT         NOT NULL,
+    is_active  BOOLEAN      NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_units_of_measure_company_code UNIQUE (company_id, code)
+);
+
+-- Every stock unit already in use becomes a master record.
+INSERT INTO units_of_measure (company_id, code, name)
+SELECT DISTINCT company_id, unit, unit FROM products
+ON CONFLICT (company_id, code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS product_uom_conversions (
+    id         SERIAL PRIMARY KEY,
+    product_id INT            NOT NULL REFERENCES products(id),
+    uom_id     INT            NOT NULL REFERENCES units_of_measure(id),
+    factor     NUMERIC(18,6)  NOT NULL,
+    created_at TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_product_uom_conversions UNIQUE (product_id, uom_id),
+    CONSTRAINT chk_product_uom_conversions_factor CHECK (factor > 0)
+);
+
+-- Default line units; NULL means the stock unit.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS purchase_uom VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS sales_uom    VARCHAR(20) NULL;
+
+-- uom NULL means the product's stock unit (factor 1).
+ALTER TABLE purchase_order_lines
+    ADD COLUMN IF NOT EXISTS uom        VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS uom_factor NUMERIC(18,6) NOT NULL DEFAULT 1;
+
+ALTER TABLE sales_order_lines
+    ADD COLUMN IF NOT EXISTS uom        VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS uom_factor NUMERIC(18,6) NOT NULL DEFAULT 1;
@@ -0,0 +1,58 @@
+-- Migration 032: Landed cost vouchers.
+-- A landed cost voucher books freight, customs duty, insurance and similar charges for
+-- one or more goods receipts (RECEIPT movements linked to a PO line via po_line_id) and
+-- spreads them over those receipts by value, quantity or weight.
+-- For each receipt the share still in stock is capitalised: inventory_items.unit_cost is
+-- raised and a LANDED_COST movement (quantity 0, total_cost = capitalised amount) keeps
+-- the movement-based valuation in step with the INVENTORY account. The share already
+-- shipped is expensed to COGS.
+-- products.unit_weight (per stock unit) is the basis for WEIGHT allocation.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS unit_weight NUMERIC(14,4) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS landed_cost_vouchers (
+    id                  SERIAL PRIMARY KEY,
+    company_id          INT            NOT NULL REFERENCES companies(id),
+    voucher_number      VARCHAR(50)    NULL,
+    vendor_id           INT            NULL REFERENCES vendors(id),
+    voucher_date        DATE           NOT NULL,
+    allocation_method   VARCHAR(10)    NOT NULL
+        CHECK (allocation_method IN ('VALUE', 'QUANTITY', 'WEIGHT')),
+    credit_account_code VARCHAR(20)    NOT NULL,
+    total_amount        NUMERIC(14,2)  NOT NULL,
+    capitalized_amount  NUMERIC(14,2)  NOT NULL DEFAULT 0,
+    expensed_amount     NUMERIC(14,2)  NOT NULL DEFAULT 0,
+    notes               TEXT           NULL,
+    created_at          TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_landed_cost_vouchers_company ON landed_cost_vouchers(company_id, voucher_date);
+
+CREATE TABLE IF NOT EXISTS landed_cost_charges (
+    id          SERIAL PRIMARY KEY,
+    voucher_id  INT            NOT NULL REFERENCES landed_cost_vouchers(id) ON DELETE CASCADE,
+    description TEXT           NOT NULL,
+    amount      NUMERIC(14,2)  NOT NULL,
+    CONSTRAINT chk_landed_cost_charges_amount CHECK (amount > 0)
+);
+
+-- One row per receipt movement the voucher was spread over.
+-- allocated_amount = capitalized_amount + expensed_amount.
+CREATE TABLE IF NOT EXISTS landed_cost_allocations (
+    id                 SERIAL PRIMARY KEY,
+    voucher_id         INT            NOT NULL REFERENCES landed_cost_vouchers(id) ON DELETE CASCADE,
+    movement_id        INT            NOT NULL REFERENCES inventory_movements(id),
+    basis              NUMERIC(18,4)  NOT NULL,
+    allocated_amount   NUMERIC(14,2)  NOT NULL,
+    capitalized_amount NUMERIC(14,2)  NOT NULL,
+    expensed_amount    NUMERIC(14,2)  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_landed_cost_allocations_voucher  ON landed_cost_allocations(voucher_id);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_allocations_movement ON landed_cost_allocations(movement_id);
+
+INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy)
+VALUES ('LC', 'Landed Cost Voucher', true, true, false, true, 'sequential', false)
+ON CONFLICT (code) DO NOTHING;
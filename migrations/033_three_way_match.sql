@@ -0,0 +1,64 @@
+-- Migration 033: Three-way match on vendor invoices.
+-- Vendor invoices are entered line by line against PO lines and matched to the quantity
+-- actually received (RECEIPT movements linked via po_line_id). Per-company tolerances
+-- decide whether a price or quantity difference is accepted:
+--   * a line passes when its price and quantity differences are within the percentage
+--     tolerances, or when the absolute line variance does not exceed amount_tolerance;
+--   * any failing line blocks the PO for payment until a FINANCE_MANAGER releases it.
+-- Accepted differences are posted against AP (which carries received quantity at PO cost)
+-- to PURCHASE_PRICE_VARIANCE, or back onto inventory cost when variance_treatment is
+-- INVENTORY. PRICE_VARIANCE movements (quantity 0) keep stock valuation in step.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS purchase_match_tolerances (
+    company_id          INT            PRIMARY KEY REFERENCES companies(id),
+    price_tolerance_pct NUMERIC(6,2)   NOT NULL DEFAULT 5,
+    qty_tolerance_pct   NUMERIC(6,2)   NOT NULL DEFAULT 0,
+    amount_tolerance    NUMERIC(14,2)  NOT NULL DEFAULT 0,
+    variance_treatment  VARCHAR(10)    NOT NULL DEFAULT 'PPV'
+        CHECK (variance_treatment IN ('PPV', 'INVENTORY')),
+    updated_at          TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_purchase_match_tolerances_nonneg
+        CHECK (price_tolerance_pct >= 0 AND qty_tolerance_pct >= 0 AND amount_tolerance >= 0)
+);
+
+ALTER TABLE purchase_orders
+    ADD COLUMN IF NOT EXISTS match_status             VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS payment_blocked          BOOLEAN       NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS payment_block_reason     TEXT          NULL,
+    ADD COLUMN IF NOT EXISTS variance_amount          NUMERIC(14,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS variance_document_number VARCHAR(50)   NULL;
+
+-- One row per invoiced PO line. received_qty and po_unit_cost are snapshots taken at match time.
+-- variance_amount = line_amount - received_qty × po_unit_cost
+--                 = price_variance (qty_invoiced × price difference) + qty_variance.
+CREATE TABLE IF NOT EXISTS vendor_invoice_lines (
+    id              SERIAL PRIMARY KEY,
+    po_id           INT            NOT NULL REFERENCES purchase_orders(id),
+    po_line_id      INT            NOT NULL REFERENCES purchase_order_lines(id),
+    qty_invoiced    NUMERIC(14,4)  NOT NULL,
+    unit_price      NUMERIC(14,4)  NOT NULL,
+    line_amount     NUMERIC(14,2)  NOT NULL,
+    received_qty    NUMERIC(14,4)  NOT NULL,
+    po_unit_cost    NUMERIC(14,4)  NOT NULL,
+    price_variance  NUMERIC(14,2)  NOT NULL,
+    qty_variance    NUMERIC(14,2)  NOT NULL,
+    variance_amount NUMERIC(14,2)  NOT NULL,
+    match_status    VARCHAR(20)    NOT NULL
+        CHECK (match_status IN ('MATCHED', 'WITHIN_TOLERANCE', 'PRICE_EXCEPTION', 'QTY_EXCEPTION')),
+    CONSTRAINT uq_vendor_invoice_lines_po_line UNIQUE (po_line_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_invoice_lines_po ON vendor_invoice_lines(po_id);
+
+INSERT INTO accounts (company_id, code, name, type)
+SELECT c.id, '5400', 'Purchase Price Variance', 'expense'
+FROM companies c
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, code) DO NOTHING;
+
+INSERT INTO account_rules (company_id, rule_type, account_code)
+SELECT c.id, 'PURCHASE_PRICE_VARIANCE', '5400'
+FROM companies c
+WHERE c.company_code = '1000'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,35 @@
+-- Migration 034: Partial goods receipts.
+-- purchase_order_lines.received_quantity tracks the cumulative quantity received per line,
+-- in the line's unit, for goods and service lines alike. A PO stays PARTIALLY_RECEIVED
+-- until every line is fully received, or until it is short-closed: the undelivered balance
+-- is cancelled and the PO moves to RECEIVED with short_closed_at set.
+-- Existing goods lines are backfilled from their RECEIPT movements; service lines on POs
+-- already past APPROVED are taken as received in full.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE purchase_order_lines
+    ADD COLUMN IF NOT EXISTS received_quantity NUMERIC(14,4) NOT NULL DEFAULT 0;
+
+ALTER TABLE purchase_orders
+    ADD COLUMN IF NOT EXISTS short_closed_at    TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS short_close_reason TEXT        NULL;
+
+UPDATE purchase_order_lines pol
+SET received_quantity = rcv.qty / pol.uom_factor
+FROM (
+    SELECT po_line_id, SUM(quantity) AS qty
+    FROM inventory_movements
+    WHERE movement_type = 'RECEIPT' AND po_line_id IS NOT NULL
+    GROUP BY po_line_id
+) rcv
+WHERE rcv.po_line_id = pol.id
+  AND pol.product_id IS NOT NULL
+  AND pol.received_quantity = 0;
+
+UPDATE purchase_order_lines pol
+SET received_quantity = pol.quantity
+FROM purchase_orders po
+WHERE po.id = pol.order_id
+  AND pol.product_id IS NULL
+  AND pol.received_quantity = 0
+  AND po.status IN ('RECEIVED', 'INVOICED', 'PAID');
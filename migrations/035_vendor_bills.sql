@@ -0,0 +1,43 @@
+-- Migration 035: Direct vendor bills.
+-- Vendor bills record costs that never go through a purchase order (utilities, professional
+-- fees, ad-hoc purchases). Each bill posts DR expense per line / CR vendor AP on entry and
+-- takes a PI document number from the same sequence as PO invoices. Lines may reference a
+-- product for reporting; stock is only received through purchase orders.
+-- due_date defaults to bill_date + vendors.payment_terms_days.
+-- Status: POSTED (open in AP) → PAID.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS vendor_bills (
+    id                      SERIAL PRIMARY KEY,
+    company_id              INT            NOT NULL REFERENCES companies(id),
+    vendor_id               INT            NOT NULL REFERENCES vendors(id),
+    bill_number             VARCHAR(100)   NOT NULL,
+    bill_date               DATE           NOT NULL,
+    due_date                DATE           NOT NULL,
+    status                  VARCHAR(20)    NOT NULL DEFAULT 'POSTED'
+        CHECK (status IN ('POSTED', 'PAID')),
+    ap_account_code         VARCHAR(20)    NOT NULL,
+    total_amount            NUMERIC(14,2)  NOT NULL,
+    pi_document_number      VARCHAR(30)    NULL,
+    journal_document_number VARCHAR(50)    NULL,
+    notes                   TEXT           NULL,
+    created_at              TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    paid_at                 TIMESTAMPTZ    NULL,
+    CONSTRAINT uq_vendor_bills_vendor_number UNIQUE (company_id, vendor_id, bill_number),
+    CONSTRAINT chk_vendor_bills_due_date CHECK (due_date >= bill_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_bills_company_status ON vendor_bills(company_id, status);
+
+CREATE TABLE IF NOT EXISTS vendor_bill_lines (
+    id                   SERIAL PRIMARY KEY,
+    bill_id              INT            NOT NULL REFERENCES vendor_bills(id),
+    line_number          INT            NOT NULL,
+    product_id           INT            NULL REFERENCES products(id),
+    description          TEXT           NOT NULL,
+    quantity             NUMERIC(14,4)  NOT NULL CHECK (quantity > 0),
+    unit_cost            NUMERIC(14,4)  NOT NULL CHECK (unit_cost >= 0),
+    line_amount          NUMERIC(14,2)  NOT NULL,
+    expense_account_code VARCHAR(20)    NOT NULL,
+    CONSTRAINT uq_vendor_bill_lines_number UNIQUE (bill_id, line_number)
+);
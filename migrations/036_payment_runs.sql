@@ -0,0 +1,58 @@
+-- Migration 036: Batch vendor payment runs.
+-- A payment run proposes every open AP item (INVOICED purchase orders and POSTED vendor
+-- bills) due on or before due_by, optionally limited to one vendor and/or one document
+-- currency. Items already proposed on another unposted run are left out.
+-- Status: DRAFT (review; items can be excluded) → APPROVED (FINANCE_MANAGER) → POSTED.
+-- Posting writes one journal entry per vendor (DR vendor AP / CR bank) and marks the
+-- paid documents PAID. Approved and posted runs can be exported as ISO 20022 pain.001
+-- XML or CSV; the bank file needs the vendors' and the house bank's account details.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE vendors
+    ADD COLUMN IF NOT EXISTS bank_account_name   VARCHAR(140) NULL,
+    ADD COLUMN IF NOT EXISTS bank_account_number VARCHAR(34)  NULL,
+    ADD COLUMN IF NOT EXISTS bank_code           VARCHAR(20)  NULL;
+
+-- House bank details for bank GL accounts (the debtor side of a payment file).
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS bank_account_number VARCHAR(34) NULL,
+    ADD COLUMN IF NOT EXISTS bank_code           VARCHAR(20) NULL;
+
+CREATE TABLE IF NOT EXISTS payment_runs (
+    id                SERIAL PRIMARY KEY,
+    company_id        INT            NOT NULL REFERENCES companies(id),
+    status            VARCHAR(20)    NOT NULL DEFAULT 'DRAFT'
+        CHECK (status IN ('DRAFT', 'APPROVED', 'POSTED')),
+    due_by            DATE           NOT NULL,
+    payment_date      DATE           NOT NULL,
+    bank_account_code VARCHAR(20)    NOT NULL,
+    vendor_id         INT            NULL REFERENCES vendors(id),
+    currency          VARCHAR(3)     NULL,
+    total_amount      NUMERIC(14,2)  NOT NULL DEFAULT 0,
+    created_by        VARCHAR(100)   NULL,
+    approved_by       VARCHAR(100)   NULL,
+    approved_at       TIMESTAMPTZ    NULL,
+    posted_at         TIMESTAMPTZ    NULL,
+    created_at        TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_runs_company_status ON payment_runs(company_id, status);
+
+-- One row per proposed AP item. Exactly one of po_id / bill_id is set.
+-- journal_document_number is the vendor's payment entry, filled in when the run posts.
+CREATE TABLE IF NOT EXISTS payment_run_items (
+    id                      SERIAL PRIMARY KEY,
+    run_id                  INT            NOT NULL REFERENCES payment_runs(id),
+    vendor_id               INT            NOT NULL REFERENCES vendors(id),
+    po_id                   INT            NULL REFERENCES purchase_orders(id),
+    bill_id                 INT            NULL REFERENCES vendor_bills(id),
+    document_reference      VARCHAR(100)   NOT NULL,
+    due_date                DATE           NOT NULL,
+    currency                VARCHAR(3)     NOT NULL,
+    amount                  NUMERIC(14,2)  NOT NULL,
+    excluded                BOOLEAN        NOT NULL DEFAULT false,
+    journal_document_number VARCHAR(50)    NULL,
+    CONSTRAINT chk_payment_run_items_source CHECK ((po_id IS NULL) <> (bill_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_run_items_run ON payment_run_items(run_id);
@@ -0,0 +1,73 @@
+-- Migration 037: Purchase returns and vendor debit notes.
+-- A purchase return sends received goods back to the vendor against a purchase order.
+-- Each returned line writes a negative RECEIPT movement (linked to the PO line) at the
+-- cost the goods were received at, and the return posts DR vendor AP / CR Inventory
+-- under a DN (debit note) document number.
+-- The debit note stays OPEN until its amount has been offset against later payments to
+-- the vendor (PayVendor or a payment run); each offset is recorded in
+-- debit_note_applications.
+-- Status: OPEN → APPLIED.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE purchase_order_lines
+    ADD COLUMN IF NOT EXISTS returned_quantity NUMERIC(14,4) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS purchase_returns (
+    id                SERIAL PRIMARY KEY,
+    company_id        INT            NOT NULL REFERENCES companies(id),
+    po_id             INT            NOT NULL REFERENCES purchase_orders(id),
+    vendor_id         INT            NOT NULL REFERENCES vendors(id),
+    warehouse_id      INT            NOT NULL REFERENCES warehouses(id),
+    return_date       DATE           NOT NULL,
+    reason            TEXT           NULL,
+    status            VARCHAR(20)    NOT NULL DEFAULT 'OPEN'
+        CHECK (status IN ('OPEN', 'APPLIED')),
+    ap_account_code   VARCHAR(20)    NOT NULL,
+    amount            NUMERIC(14,2)  NOT NULL CHECK (amount > 0),
+    applied_amount    NUMERIC(14,2)  NOT NULL DEFAULT 0,
+    debit_note_number VARCHAR(50)    NULL,
+    created_at        TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_purchase_returns_applied CHECK (applied_amount >= 0 AND applied_amount <= amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_company_status ON purchase_returns(company_id, status);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_vendor_status  ON purchase_returns(vendor_id, status);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_po             ON purchase_returns(po_id);
+
+-- quantity and unit_cost are in the PO line's unit; unit_cost is the receipt cost.
+CREATE TABLE IF NOT EXISTS purchase_return_lines (
+    id          SERIAL PRIMARY KEY,
+    return_id   INT            NOT NULL REFERENCES purchase_returns(id),
+    po_line_id  INT            NOT NULL REFERENCES purchase_order_lines(id),
+    quantity    NUMERIC(14,4)  NOT NULL CHECK (quantity > 0),
+    unit_cost   NUMERIC(14,4)  NOT NULL,
+    line_amount NUMERIC(14,2)  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_return_lines_return ON purchase_return_lines(return_id);
+
+-- One row per offset of a debit note against a payment: either a direct PO payment
+-- (po_id) or a posted payment run (payment_run_id).
+CREATE TABLE IF NOT EXISTS debit_note_applications (
+    id             SERIAL PRIMARY KEY,
+    return_id      INT            NOT NULL REFERENCES purchase_returns(id),
+    po_id          INT            NULL REFERENCES purchase_orders(id),
+    payment_run_id INT            NULL REFERENCES payment_runs(id),
+    amount         NUMERIC(14,2)  NOT NULL CHECK (amount > 0),
+    applied_at     TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_debit_note_applications_target CHECK ((po_id IS NULL) <> (payment_run_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_debit_note_applications_return ON debit_note_applications(return_id);
+
+-- Open debit notes are proposed on payment runs as negative items.
+ALTER TABLE payment_run_items
+    ADD COLUMN IF NOT EXISTS return_id INT NULL REFERENCES purchase_returns(id);
+
+ALTER TABLE payment_run_items DROP CONSTRAINT IF EXISTS chk_payment_run_items_source;
+ALTER TABLE payment_run_items
+    ADD CONSTRAINT chk_payment_run_items_source CHECK (num_nonnulls(po_id, bill_id, return_id) = 1);
+
+INSERT INTO document_types (code, name, affects_inventory, affects_gl, affects_ar, affects_ap, numbering_strategy, resets_every_fy)
+VALUES ('DN', 'Debit Note', true, true, false, true, 'sequential', false)
+ON CONFLICT (code) DO NOTHING;
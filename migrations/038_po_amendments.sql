@@ -0,0 +1,45 @@
+-- Migration 038: Purchase order amendments, cancellation and revision history.
+-- An APPROVED purchase order can be amended (quantities, prices, lines, expected delivery
+-- date). Each amendment increments purchase_orders.revision; an amendment may require
+-- re-approval, which returns the PO to DRAFT while keeping its PO number.
+-- DRAFT and APPROVED purchase orders (nothing received) can be cancelled.
+-- purchase_order_revisions is the audit trail: one row per lifecycle event with the
+-- revision it produced, the field-level changes, the reason and the user.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE purchase_orders
+    ADD COLUMN IF NOT EXISTS revision      INT         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cancelled_at  TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT        NULL;
+
+CREATE TABLE IF NOT EXISTS purchase_order_revisions (
+    id         SERIAL PRIMARY KEY,
+    po_id      INT          NOT NULL REFERENCES purchase_orders(id),
+    revision   INT          NOT NULL,
+    action     VARCHAR(20)  NOT NULL
+        CHECK (action IN ('CREATED', 'APPROVED', 'AMENDED', 'CANCELLED', 'CLOSED')),
+    changes    TEXT[]       NOT NULL DEFAULT '{}',
+    reason     TEXT         NULL,
+    changed_by VARCHAR(100) NULL,
+    changed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_revisions_po ON purchase_order_revisions(po_id, id);
+
+-- Backfill history for purchase orders created before this migration.
+INSERT INTO purchase_order_revisions (po_id, revision, action, changed_at)
+SELECT po.id, 0, 'CREATED', po.created_at
+FROM purchase_orders po
+WHERE NOT EXISTS (SELECT 1 FROM purchase_order_revisions r WHERE r.po_id = po.id);
+
+INSERT INTO purchase_order_revisions (po_id, revision, action, changed_at)
+SELECT po.id, 0, 'APPROVED', po.approved_at
+FROM purchase_orders po
+WHERE po.approved_at IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM purchase_order_revisions r WHERE r.po_id = po.id AND r.action = 'APPROVED');
+
+INSERT INTO purchase_order_revisions (po_id, revision, action, reason, changed_at)
+SELECT po.id, 0, 'CLOSED', po.short_close_reason, po.short_closed_at
+FROM purchase_orders po
+WHERE po.short_closed_at IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM purchase_order_revisions r WHERE r.po_id = po.id AND r.action = 'CLOSED');
@@ -0,0 +1,19 @@
+-- Migration 039: Foreign-currency purchase orders.
+-- vendors.currency is the vendor's default purchasing currency (NULL = company base
+-- currency). A purchase order is raised in that currency unless another is given, at an
+-- exchange rate to the base currency fixed on the PO. Receipts, service-line receipts,
+-- invoice variances and payments post in the PO currency at that rate; inventory is
+-- valued in base currency.
+-- Purchase orders created before this migration were stored with currency 'INR' and
+-- rate 1 regardless of the company; they are corrected to the company base currency.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE vendors
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NULL;
+
+UPDATE purchase_orders po
+SET currency = c.base_currency
+FROM companies c
+WHERE c.id = po.company_id
+  AND po.exchange_rate = 1
+  AND po.currency <> c.base_currency;
@@ -0,0 +1,19 @@
+-- Migration 040: Vendor and customer master data maintenance.
+-- Both masters carry a version number for optimistic concurrency: an update must name the
+-- version it was based on and fails if another user changed the record in the meantime.
+-- Customers gain soft deactivation (vendors already have is_active); inactive masters are
+-- rejected on new sales orders and purchase orders but keep their history.
+-- merged_into_id records a merge: the duplicate's documents were repointed to the survivor
+-- and the duplicate was deactivated.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS is_active      BOOLEAN     NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS version        INT         NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_at     TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS merged_into_id INT         NULL REFERENCES customers(id);
+
+ALTER TABLE vendors
+    ADD COLUMN IF NOT EXISTS version        INT         NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_at     TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS merged_into_id INT         NULL REFERENCES vendors(id);
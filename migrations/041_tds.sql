@@ -0,0 +1,75 @@
+-- Migration 041: TDS (tax deducted at source) on vendor payments.
+-- tds_sections holds the statutory withholding codes. A vendor with a tds_section_code has
+-- TDS withheld when it is paid (PayVendor, vendor bill payments and payment runs): the
+-- payment posts DR AP (full) / CR bank (net) / CR TDS_PAYABLE (withheld).
+-- Thresholds are checked per vendor and section over the Indian financial year (April to
+-- March): single_payment_threshold against the payment alone, annual_threshold against the
+-- year's payments so far plus this one (0 = no such threshold). When the annual threshold
+-- is crossed, TDS is also withheld on the earlier payments of the year that were below it.
+-- Vendors without a PAN are withheld at no_pan_rate (section 206AA).
+-- tds_deductions records every payment to a TDS vendor, including those below threshold
+-- (tds_amount 0), and drives the fiscal-year cumulative totals and the quarterly register.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS tds_sections (
+    code                     VARCHAR(20)   PRIMARY KEY,
+    section                  VARCHAR(10)   NOT NULL,
+    description              TEXT          NOT NULL,
+    rate                     NUMERIC(6,3)  NOT NULL,
+    no_pan_rate              NUMERIC(6,3)  NOT NULL DEFAULT 20,
+    single_payment_threshold NUMERIC(14,2) NOT NULL DEFAULT 0,
+    annual_threshold         NUMERIC(14,2) NOT NULL DEFAULT 0,
+    is_active                BOOLEAN       NOT NULL DEFAULT true,
+    CONSTRAINT chk_tds_sections_nonneg
+        CHECK (rate >= 0 AND no_pan_rate >= 0 AND single_payment_threshold >= 0 AND annual_threshold >= 0)
+);
+
+INSERT INTO tds_sections (code, section, description, rate, single_payment_threshold, annual_threshold) VALUES
+    ('194C-IND',  '194C', 'Payments to contractors — individual / HUF',        1,     30000, 100000),
+    ('194C-OTH',  '194C', 'Payments to contractors — others',                  2,     30000, 100000),
+    ('194J-PROF', '194J', 'Fees for professional services',                    10,    0,     50000),
+    ('194J-TECH', '194J', 'Fees for technical services, call centre, royalty', 2,     0,     50000),
+    ('194H',      '194H', 'Commission or brokerage',                           2,     0,     20000)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE vendors
+    ADD COLUMN IF NOT EXISTS pan              VARCHAR(10) NULL,
+    ADD COLUMN IF NOT EXISTS tds_section_code VARCHAR(20) NULL REFERENCES tds_sections(code);
+
+-- financial_year is the calendar year the financial year starts in (2026 = FY 2026-27).
+-- taxable_amount is what TDS was computed on: the payment, plus earlier untaxed payments of
+-- the year when the annual threshold was crossed.
+CREATE TABLE IF NOT EXISTS tds_deductions (
+    id                      SERIAL PRIMARY KEY,
+    company_id              INT            NOT NULL REFERENCES companies(id),
+    vendor_id               INT            NOT NULL REFERENCES vendors(id),
+    section_code            VARCHAR(20)    NOT NULL REFERENCES tds_sections(code),
+    pan                     VARCHAR(10)    NULL,
+    financial_year          INT            NOT NULL,
+    payment_date            DATE           NOT NULL,
+    gross_amount            NUMERIC(14,2)  NOT NULL,
+    taxable_amount          NUMERIC(14,2)  NOT NULL,
+    rate                    NUMERIC(6,3)   NOT NULL,
+    tds_amount              NUMERIC(14,2)  NOT NULL,
+    po_id                   INT            NULL REFERENCES purchase_orders(id),
+    bill_id                 INT            NULL REFERENCES vendor_bills(id),
+    run_id                  INT            NULL REFERENCES payment_runs(id),
+    journal_document_number VARCHAR(50)    NULL,
+    created_at              TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tds_deductions_amounts CHECK (gross_amount > 0 AND tds_amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tds_deductions_vendor_fy ON tds_deductions(vendor_id, section_code, financial_year);
+CREATE INDEX IF NOT EXISTS idx_tds_deductions_company_date ON tds_deductions(company_id, payment_date);
+
+INSERT INTO accounts (company_id, code, name, type)
+SELECT c.id, '2200', 'TDS Payable', 'liability'
+FROM companies c
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, code) DO NOTHING;
+
+INSERT INTO account_rules (company_id, rule_type, account_code)
+SELECT c.id, 'TDS_PAYABLE', '2200'
+FROM companies c
+WHERE c.company_code = '1000'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,147 @@
+-- Migration 042: GST tax engine for sales orders, purchase orders and vendor bills.
+-- tax_codes are per company (GST5, GST18 …); tax_code_rates holds each code's component
+-- rates with effective dates, so a rate change is a new row rather than an edit.
+-- A supply is intra-state when the company's state_code equals the customer's (sales) or
+-- vendor's (purchases): CGST + SGST are charged. Otherwise it is inter-state: IGST.
+-- Products carry an HSN/SAC code and a default tax code; document lines may override it.
+-- line_taxes holds the component breakdown of each taxed line: sales order lines (computed
+-- at order creation, posted at InvoiceOrder), vendor invoice lines against a PO (computed
+-- and posted at RecordVendorInvoice) and vendor bill lines (CreateBill). PO lines only
+-- carry an estimate in tax_amount_transaction until the vendor invoices them. Output tax is
+-- credited to GST_OUTPUT_<component> and input tax credit debited to GST_INPUT_<component>.
+-- Document totals include tax; tax_transaction / tax_amount hold the tax part.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE companies
+    ADD COLUMN IF NOT EXISTS state_code VARCHAR(2)  NULL,
+    ADD COLUMN IF NOT EXISTS gstin      VARCHAR(15) NULL;
+
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS state_code VARCHAR(2)  NULL,
+    ADD COLUMN IF NOT EXISTS gstin      VARCHAR(15) NULL;
+
+ALTER TABLE vendors
+    ADD COLUMN IF NOT EXISTS state_code VARCHAR(2)  NULL,
+    ADD COLUMN IF NOT EXISTS gstin      VARCHAR(15) NULL;
+
+CREATE TABLE IF NOT EXISTS tax_codes (
+    id          SERIAL       PRIMARY KEY,
+    company_id  INT          NOT NULL REFERENCES companies(id),
+    code        VARCHAR(20)  NOT NULL,
+    description TEXT         NOT NULL,
+    is_active   BOOLEAN      NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_tax_codes_company_code UNIQUE (company_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS tax_code_rates (
+    id             SERIAL        PRIMARY KEY,
+    tax_code_id    INT           NOT NULL REFERENCES tax_codes(id),
+    component      VARCHAR(10)   NOT NULL CHECK (component IN ('CGST', 'SGST', 'IGST')),
+    rate           NUMERIC(6,3)  NOT NULL CHECK (rate >= 0),
+    effective_from DATE          NOT NULL,
+    effective_to   DATE          NULL,
+    CONSTRAINT uq_tax_code_rates_from UNIQUE (tax_code_id, component, effective_from),
+    CONSTRAINT chk_tax_code_rates_period CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS hsn_code VARCHAR(8)  NULL,
+    ADD COLUMN IF NOT EXISTS tax_code VARCHAR(20) NULL;
+
+ALTER TABLE sales_orders
+    ADD COLUMN IF NOT EXISTS tax_transaction NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE sales_order_lines
+    ADD COLUMN IF NOT EXISTS tax_code               VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS tax_amount_transaction NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE purchase_orders
+    ADD COLUMN IF NOT EXISTS tax_transaction    NUMERIC(14,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS invoice_tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE purchase_order_lines
+    ADD COLUMN IF NOT EXISTS tax_code               VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS tax_amount_transaction NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE vendor_bills
+    ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE vendor_bill_lines
+    ADD COLUMN IF NOT EXISTS tax_code   VARCHAR(20)   NULL,
+    ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS line_taxes (
+    id                     SERIAL        PRIMARY KEY,
+    company_id             INT           NOT NULL REFERENCES companies(id),
+    sales_order_line_id    INT           NULL REFERENCES sales_order_lines(id),
+    vendor_invoice_line_id INT           NULL REFERENCES vendor_invoice_lines(id),
+    vendor_bill_line_id    INT           NULL REFERENCES vendor_bill_lines(id),
+    tax_code               VARCHAR(20)   NOT NULL,
+    component              VARCHAR(10)   NOT NULL CHECK (component IN ('CGST', 'SGST', 'IGST')),
+    rate                   NUMERIC(6,3)  NOT NULL,
+    taxable_amount         NUMERIC(14,2) NOT NULL,
+    tax_amount             NUMERIC(14,2) NOT NULL,
+    CONSTRAINT chk_line_taxes_one_source
+        CHECK (num_nonnulls(sales_order_line_id, vendor_invoice_line_id, vendor_bill_line_id) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_line_taxes_sales_line   ON line_taxes(sales_order_line_id);
+CREATE INDEX IF NOT EXISTS idx_line_taxes_invoice_line ON line_taxes(vendor_invoice_line_id);
+CREATE INDEX IF NOT EXISTS idx_line_taxes_bill_line    ON line_taxes(vendor_bill_line_id);
+
+-- Standard GST slabs for the demo company, effective from GST's introduction.
+INSERT INTO tax_codes (company_id, code, description)
+SELECT c.id, t.code, t.description
+FROM companies c
+CROSS JOIN (VALUES
+    ('GST0',  'GST nil-rated'),
+    ('GST5',  'GST 5%'),
+    ('GST12', 'GST 12%'),
+    ('GST18', 'GST 18%'),
+    ('GST28', 'GST 28%')
+) AS t(code, description)
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, code) DO NOTHING;
+
+INSERT INTO tax_code_rates (tax_code_id, component, rate, effective_from)
+SELECT tc.id, r.component, r.rate, DATE '2017-07-01'
+FROM tax_codes tc
+JOIN companies c ON c.id = tc.company_id
+JOIN (VALUES
+    ('GST0',  'CGST', 0),   ('GST0',  'SGST', 0),   ('GST0',  'IGST', 0),
+    ('GST5',  'CGST', 2.5), ('GST5',  'SGST', 2.5), ('GST5',  'IGST', 5),
+    ('GST12', 'CGST', 6),   ('GST12', 'SGST', 6),   ('GST12', 'IGST', 12),
+    ('GST18', 'CGST', 9),   ('GST18', 'SGST', 9),   ('GST18', 'IGST', 18),
+    ('GST28', 'CGST', 14),  ('GST28', 'SGST', 14),  ('GST28', 'IGST', 28)
+) AS r(code, component, rate) ON r.code = tc.code
+WHERE c.company_code = '1000'
+ON CONFLICT (tax_code_id, component, effective_from) DO NOTHING;
+
+INSERT INTO accounts (company_id, code, name, type)
+SELECT c.id, a.code, a.name, a.type
+FROM companies c
+CROSS JOIN (VALUES
+    ('1510', 'Input CGST Credit',   'asset'),
+    ('1520', 'Input SGST Credit',   'asset'),
+    ('1530', 'Input IGST Credit',   'asset'),
+    ('2310', 'Output CGST Payable', 'liability'),
+    ('2320', 'Output SGST Payable', 'liability'),
+    ('2330', 'Output IGST Payable', 'liability')
+) AS a(code, name, type)
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, code) DO NOTHING;
+
+INSERT INTO account_rules (company_id, rule_type, account_code)
+SELECT c.id, r.rule_type, r.account_code
+FROM companies c
+CROSS JOIN (VALUES
+    ('GST_INPUT_CGST',  '1510'),
+    ('GST_INPUT_SGST',  '1520'),
+    ('GST_INPUT_IGST',  '1530'),
+    ('GST_OUTPUT_CGST', '2310'),
+    ('GST_OUTPUT_SGST', '2320'),
+    ('GST_OUTPUT_IGST', '2330')
+) AS r(rule_type, account_code)
+WHERE c.company_code = '1000'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,36 @@
+-- Migration 043: e-invoicing (IRN) for B2B sales invoices.
+-- A company whose aggregate turnover is above the notified threshold sets einvoice_enabled;
+-- its invoices to customers with a GSTIN must then be registered with the Invoice
+-- Registration Portal (IRP). The INV-01 payload is built from the INVOICED sales order and
+-- the IRP returns the IRN, acknowledgement number and signed invoice / QR code, stored here.
+-- companies.address is the seller address on the payload; customers already carry one.
+-- An e-invoice is cancelled only through the IRN cancel flow (within 24 hours of the
+-- acknowledgement): that cancels the IRN, reverses the invoice journal entry and cancels
+-- the order. Reversing the invoice entry directly is refused while the IRN is active.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE companies
+    ADD COLUMN IF NOT EXISTS address          TEXT    NULL,
+    ADD COLUMN IF NOT EXISTS einvoice_enabled BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS einvoices (
+    id                 SERIAL       PRIMARY KEY,
+    company_id         INT          NOT NULL REFERENCES companies(id),
+    order_id           INT          NOT NULL REFERENCES sales_orders(id),
+    irn                VARCHAR(64)  NOT NULL,
+    ack_number         VARCHAR(20)  NOT NULL,
+    ack_date           TIMESTAMPTZ  NOT NULL,
+    signed_invoice     TEXT         NOT NULL,
+    signed_qr_code     TEXT         NOT NULL,
+    payload            JSONB        NOT NULL,
+    status             VARCHAR(10)  NOT NULL DEFAULT 'GENERATED'
+        CHECK (status IN ('GENERATED', 'CANCELLED')),
+    cancel_reason_code SMALLINT     NULL CHECK (cancel_reason_code BETWEEN 1 AND 4),
+    cancel_remark      TEXT         NULL,
+    cancelled_at       TIMESTAMPTZ  NULL,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_einvoices_irn UNIQUE (irn)
+);
+
+-- One live IRN per order; a cancelled one stays for the audit trail.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_einvoices_active_order ON einvoices(order_id) WHERE status = 'GENERATED';
@@ -0,0 +1,58 @@
+-- Migration 044: Statement of cash flows.
+-- accounts.is_cash flags the cash and bank accounts whose balances make up "cash": the
+-- statement reconciles their opening balance plus the net change to their closing balance.
+-- cash_flow_mappings classifies accounts into the statement's activities, with the line
+-- each account is reported on (several accounts may share a line):
+--   OPERATING — working capital (receivables, inventories, payables, taxes)
+--   NON_CASH  — adjustments to net income. On a P&L account (depreciation, COGS) the
+--               balance-sheet side of its entries that do not touch cash is added back
+--               instead of being shown as a working capital change; on a balance-sheet
+--               account (accumulated depreciation) its movement is the adjustment.
+--   INVESTING — fixed assets and investments
+--   FINANCING — borrowings and equity
+-- Unmapped accounts default to OPERATING, except equity accounts, which default to FINANCING.
+-- The direct method analyses cash account lines by counter-account using the same mapping.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS is_cash BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE accounts a SET is_cash = true
+FROM companies c
+WHERE c.id = a.company_id AND c.company_code = '1000' AND a.code IN ('1000', '1100') AND NOT a.is_cash;
+
+-- House bank accounts used by payment runs are cash accounts too.
+UPDATE accounts SET is_cash = true WHERE bank_account_number IS NOT NULL AND NOT is_cash;
+
+CREATE TABLE IF NOT EXISTS cash_flow_mappings (
+    id           SERIAL       PRIMARY KEY,
+    company_id   INT          NOT NULL REFERENCES companies(id),
+    account_code VARCHAR(20)  NOT NULL,
+    activity     VARCHAR(20)  NOT NULL
+        CHECK (activity IN ('OPERATING', 'NON_CASH', 'INVESTING', 'FINANCING')),
+    line_name    TEXT         NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_cash_flow_mappings_company_account UNIQUE (company_id, account_code)
+);
+
+INSERT INTO cash_flow_mappings (company_id, account_code, activity, line_name)
+SELECT c.id, m.account_code, m.activity, m.line_name
+FROM companies c
+CROSS JOIN (VALUES
+    ('1200', 'OPERATING', 'Trade receivables'),
+    ('1300', 'INVESTING', 'Purchase of property, plant and equipment'),
+    ('1400', 'OPERATING', 'Inventories'),
+    ('1510', 'OPERATING', 'GST input tax credit'),
+    ('1520', 'OPERATING', 'GST input tax credit'),
+    ('1530', 'OPERATING', 'GST input tax credit'),
+    ('2000', 'OPERATING', 'Trade payables'),
+    ('2100', 'FINANCING', 'Short-term borrowings'),
+    ('2200', 'OPERATING', 'Duties and taxes payable'),
+    ('2310', 'OPERATING', 'Duties and taxes payable'),
+    ('2320', 'OPERATING', 'Duties and taxes payable'),
+    ('2330', 'OPERATING', 'Duties and taxes payable'),
+    ('3000', 'FINANCING', 'Capital contributed'),
+    ('5000', 'NON_CASH',  'Cost of goods sold (inventory consumed)')
+) AS m(account_code, activity, line_name)
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, account_code) DO NOTHING;
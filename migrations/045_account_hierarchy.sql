@@ -0,0 +1,138 @@
+-- Migration 045: Account hierarchy and statement layouts.
+-- accounts.is_group marks a group (header) account: it has sub-accounts instead of
+-- postings, and its balance is the rollup of its descendants. Only leaf accounts can be
+-- posted to. parent_id places an account under a group of the same company and type.
+-- statement_layouts are configurable financial statement formats (e.g. Schedule III for
+-- Indian companies). Each layout has a tree of report lines; statement_layout_accounts maps
+-- accounts to lines — mapping a group maps all its descendants unless one is mapped itself.
+-- A line's normal_balance sets its sign (DEBIT: debit-positive, CREDIT: credit-positive);
+-- includes_profit adds the cumulative profit not yet closed to equity to a balance sheet line.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS is_group  BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES accounts(id);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_parent ON accounts(parent_id);
+
+CREATE TABLE IF NOT EXISTS statement_layouts (
+    id          SERIAL       PRIMARY KEY,
+    company_id  INT          NOT NULL REFERENCES companies(id),
+    code        VARCHAR(20)  NOT NULL,
+    name        TEXT         NOT NULL,
+    statement   VARCHAR(20)  NOT NULL CHECK (statement IN ('BALANCE_SHEET', 'PROFIT_AND_LOSS')),
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_statement_layouts_company_code UNIQUE (company_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS statement_layout_lines (
+    id               SERIAL       PRIMARY KEY,
+    layout_id        INT          NOT NULL REFERENCES statement_layouts(id) ON DELETE CASCADE,
+    line_code        VARCHAR(20)  NOT NULL,
+    parent_line_code VARCHAR(20),
+    label            TEXT         NOT NULL,
+    sort_order       INT          NOT NULL DEFAULT 0,
+    normal_balance   VARCHAR(6)   NOT NULL CHECK (normal_balance IN ('DEBIT', 'CREDIT')),
+    includes_profit  BOOLEAN      NOT NULL DEFAULT false,
+    CONSTRAINT uq_statement_layout_lines UNIQUE (layout_id, line_code)
+);
+
+CREATE TABLE IF NOT EXISTS statement_layout_accounts (
+    id           SERIAL       PRIMARY KEY,
+    layout_id    INT          NOT NULL REFERENCES statement_layouts(id) ON DELETE CASCADE,
+    account_code VARCHAR(20)  NOT NULL,
+    line_code    VARCHAR(20)  NOT NULL,
+    CONSTRAINT uq_statement_layout_accounts UNIQUE (layout_id, account_code)
+);
+
+-- Schedule III (Companies Act, 2013) layouts for company 1000.
+INSERT INTO statement_layouts (company_id, code, name, statement)
+SELECT c.id, l.code, l.name, l.statement
+FROM companies c
+CROSS JOIN (VALUES
+    ('SCH3-BS', 'Schedule III Balance Sheet',                'BALANCE_SHEET'),
+    ('SCH3-PL', 'Schedule III Statement of Profit and Loss', 'PROFIT_AND_LOSS')
+) AS l(code, name, statement)
+WHERE c.company_code = '1000'
+ON CONFLICT (company_id, code) DO NOTHING;
+
+INSERT INTO statement_layout_lines (layout_id, line_code, parent_line_code, label, sort_order, normal_balance, includes_profit)
+SELECT sl.id, l.line_code, l.parent_line_code, l.label, l.sort_order, l.normal_balance, l.includes_profit
+FROM statement_layouts sl
+JOIN companies c ON c.id = sl.company_id
+CROSS JOIN (VALUES
+    ('EL',   NULL,  'EQUITY AND LIABILITIES',        10, 'CREDIT', false),
+    ('EL1',  'EL',  'Shareholders'' funds',          10, 'CREDIT', false),
+    ('EL1A', 'EL1', 'Share capital',                 10, 'CREDIT', false),
+    ('EL1B', 'EL1', 'Reserves and surplus',          20, 'CREDIT', true),
+    ('EL2',  'EL',  'Non-current liabilities',       20, 'CREDIT', false),
+    ('EL2A', 'EL2', 'Long-term borrowings',          10, 'CREDIT', false),
+    ('EL2B', 'EL2', 'Long-term provisions',          20, 'CREDIT', false),
+    ('EL3',  'EL',  'Current liabilities',           30, 'CREDIT', false),
+    ('EL3A', 'EL3', 'Short-term borrowings',         10, 'CREDIT', false),
+    ('EL3B', 'EL3', 'Trade payables',                20, 'CREDIT', false),
+    ('EL3C', 'EL3', 'Other current liabilities',     30, 'CREDIT', false),
+    ('EL3D', 'EL3', 'Short-term provisions',         40, 'CREDIT', false),
+    ('A',    NULL,  'ASSETS',                        20, 'DEBIT',  false),
+    ('A1',   'A',   'Non-current assets',            10, 'DEBIT',  false),
+    ('A1A',  'A1',  'Property, plant and equipment', 10, 'DEBIT',  false),
+    ('A1B',  'A1',  'Non-current investments',       20, 'DEBIT',  false),
+    ('A2',   'A',   'Current assets',                20, 'DEBIT',  false),
+    ('A2A',  'A2',  'Inventories',                   10, 'DEBIT',  false),
+    ('A2B',  'A2',  'Trade receivables',             20, 'DEBIT',  false),
+    ('A2C',  'A2',  'Cash and cash equivalents',     30, 'DEBIT',  false),
+    ('A2D',  'A2',  'Short-term loans and advances', 40, 'DEBIT',  false),
+    ('A2E',  'A2',  'Other current assets',          50, 'DEBIT',  false)
+) AS l(line_code, parent_line_code, label, sort_order, normal_balance, includes_profit)
+WHERE c.company_code = '1000' AND sl.code = 'SCH3-BS'
+ON CONFLICT (layout_id, line_code) DO NOTHING;
+
+INSERT INTO statement_layout_lines (layout_id, line_code, parent_line_code, label, sort_order, normal_balance)
+SELECT sl.id, l.line_code, l.parent_line_code, l.label, l.sort_order, l.normal_balance
+FROM statement_layouts sl
+JOIN companies c ON c.id = sl.company_id
+CROSS JOIN (VALUES
+    ('I',   NULL, 'Revenue from operations',               10, 'CREDIT'),
+    ('II',  NULL, 'Other income',                          20, 'CREDIT'),
+    ('IV',  NULL, 'Expenses',                              30, 'DEBIT'),
+    ('IVA', 'IV', 'Cost of materials consumed',            10, 'DEBIT'),
+    ('IVB', 'IV', 'Purchases of stock-in-trade',           20, 'DEBIT'),
+    ('IVC', 'IV', 'Employee benefits expense',             30, 'DEBIT'),
+    ('IVD', 'IV', 'Finance costs',                         40, 'DEBIT'),
+    ('IVE', 'IV', 'Depreciation and amortisation expense', 50, 'DEBIT'),
+    ('IVF', 'IV', 'Other expenses',                        60, 'DEBIT')
+) AS l(line_code, parent_line_code, label, sort_order, normal_balance)
+WHERE c.company_code = '1000' AND sl.code = 'SCH3-PL'
+ON CONFLICT (layout_id, line_code) DO NOTHING;
+
+INSERT INTO statement_layout_accounts (layout_id, account_code, line_code)
+SELECT sl.id, m.account_code, m.line_code
+FROM statement_layouts sl
+JOIN companies c ON c.id = sl.company_id
+CROSS JOIN (VALUES
+    ('SCH3-BS', '3000', 'EL1A'),
+    ('SCH3-BS', '3100', 'EL1B'),
+    ('SCH3-BS', '2100', 'EL3A'),
+    ('SCH3-BS', '2000', 'EL3B'),
+    ('SCH3-BS', '2200', 'EL3C'),
+    ('SCH3-BS', '2310', 'EL3C'),
+    ('SCH3-BS', '2320', 'EL3C'),
+    ('SCH3-BS', '2330', 'EL3C'),
+    ('SCH3-BS', '1300', 'A1A'),
+    ('SCH3-BS', '1400', 'A2A'),
+    ('SCH3-BS', '1200', 'A2B'),
+    ('SCH3-BS', '1000', 'A2C'),
+    ('SCH3-BS', '1100', 'A2C'),
+    ('SCH3-BS', '1510', 'A2E'),
+    ('SCH3-BS', '1520', 'A2E'),
+    ('SCH3-BS', '1530', 'A2E'),
+    ('SCH3-PL', '4000', 'I'),
+    ('SCH3-PL', '4100', 'I'),
+    ('SCH3-PL', '5000', 'IVB'),
+    ('SCH3-PL', '5400', 'IVB'),
+    ('SCH3-PL', '5200', 'IVC'),
+    ('SCH3-PL', '5100', 'IVF'),
+    ('SCH3-PL', '5300', 'IVF')
+) AS m(layout_code, account_code, line_code)
+WHERE c.company_code = '1000' AND sl.code = m.layout_code
+ON CONFLICT (layout_id, account_code) DO NOTHING;
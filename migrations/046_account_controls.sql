@@ -0,0 +1,24 @@
+-- Migration 046: Account maintenance and posting controls.
+-- accounts.is_active = false retires an account: it cannot be posted to and is hidden from
+-- the AI prompt, but its history stays in the ledger and in reports.
+-- Posting controls, enforced by the ledger on every journal line:
+--   posting_blocked        — no postings at all, manual or system
+--   manual_posting_blocked — control accounts: only document workflows (orders, receipts,
+--                            vendor bills, payment runs) may post; manual and AI-proposed
+--                            journal entries are refused and the AI never sees the account
+--   narration_required     — entries touching the account must carry a narration
+-- The AR, AP and INVENTORY rule accounts are marked as control accounts.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS is_active              BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS posting_blocked        BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS manual_posting_blocked BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS narration_required     BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE accounts a SET manual_posting_blocked = true
+FROM account_rules r
+WHERE r.company_id = a.company_id
+  AND r.account_code = a.code
+  AND r.rule_type IN ('AR', 'AP', 'INVENTORY')
+  AND NOT a.manual_posting_blocked;
@@ -0,0 +1,10 @@
+-- Migration 047: Journal register, day book and general ledger.
+-- Indexes for listing a company's entries by date, finding an entry's lines and an
+-- account's lines, and following reversal links. journal_entries.created_by_user_id
+-- (migration 018) records who posted a manual entry; document workflows leave it NULL.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_company_date ON journal_entries(company_id, posting_date, id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reversed     ON journal_entries(reversed_entry_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_entry          ON journal_lines(entry_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account        ON journal_lines(account_id);
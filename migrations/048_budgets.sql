@@ -0,0 +1,48 @@
+-- Migration 048: Cost centers and budgets.
+-- cost_centers are a company's responsibility units (departments, sites). Budget amounts
+-- may be set per cost center; the budget-vs-actual report rolls them up to the account.
+-- budget_versions are named budgets (e.g. ORIGINAL, REVISED) for one Indian financial
+-- year (financial_year 2026 = April 2026 to March 2027). variance_threshold_pct is the
+-- default tolerance of the budget-vs-actual report: an account is over or under budget
+-- when its actual differs from the budget by more than that percentage.
+-- budget_lines hold one amount per account, optional cost center and calendar month, in
+-- the account's normal sign (debit-positive for expenses and assets, credit-positive for
+-- revenue, liabilities and equity).
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS cost_centers (
+    id          SERIAL       PRIMARY KEY,
+    company_id  INT          NOT NULL REFERENCES companies(id),
+    code        VARCHAR(20)  NOT NULL,
+    name        TEXT         NOT NULL,
+    is_active   BOOLEAN      NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_cost_centers_company_code UNIQUE (company_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS budget_versions (
+    id                     SERIAL        PRIMARY KEY,
+    company_id             INT           NOT NULL REFERENCES companies(id),
+    code                   VARCHAR(20)   NOT NULL,
+    name                   TEXT          NOT NULL,
+    financial_year         INT           NOT NULL,
+    variance_threshold_pct NUMERIC(6,3)  NOT NULL DEFAULT 10,
+    created_by_user_id     INT           NULL REFERENCES users(id),
+    created_at             TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_budget_versions_company_code UNIQUE (company_id, code),
+    CONSTRAINT chk_budget_versions_threshold CHECK (variance_threshold_pct >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS budget_lines (
+    id             SERIAL         PRIMARY KEY,
+    budget_id      INT            NOT NULL REFERENCES budget_versions(id) ON DELETE CASCADE,
+    account_id     INT            NOT NULL REFERENCES accounts(id),
+    cost_center_id INT            NULL REFERENCES cost_centers(id),
+    year           INT            NOT NULL,
+    month          INT            NOT NULL CHECK (month BETWEEN 1 AND 12),
+    amount         NUMERIC(14,2)  NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_budget_lines
+    ON budget_lines (budget_id, account_id, COALESCE(cost_center_id, 0), year, month);
+CREATE INDEX IF NOT EXISTS idx_budget_lines_account ON budget_lines (account_id, year, month);
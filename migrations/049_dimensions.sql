@@ -0,0 +1,93 @@
+-- Migration 049: Analytic dimensions on journal lines.
+-- dimensions are a company's reporting axes: the system dimensions COST_CENTER, PROJECT
+-- and BRANCH (is_system, created for every company) plus user-defined ones (e.g. REGION).
+-- dimension_values are the master data of each dimension (e.g. COST_CENTER MKT, PROJECT P-101).
+-- journal_line_dimensions tag a journal line with at most one value per dimension; the
+-- P&L and trial balance filter and break down by them.
+-- account_dimension_rules list the dimensions every posting to an account must carry;
+-- the ledger refuses lines that miss one.
+-- sales_order_lines.dimensions and purchase_order_lines.dimensions hold the line's
+-- dimension codes as [{"dimension": "PROJECT", "value": "P-101"}]; invoicing an order and
+-- receiving a service PO line carry them onto the revenue and expense journal lines.
+-- The cost centers of migration 048 become the values of the COST_CENTER dimension and
+-- budget_lines.cost_center_id now references dimension_values.
+-- Idempotent: uses IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS dimensions (
+    id          SERIAL       PRIMARY KEY,
+    company_id  INT          NOT NULL REFERENCES companies(id),
+    code        VARCHAR(20)  NOT NULL,
+    name        TEXT         NOT NULL,
+    is_system   BOOLEAN      NOT NULL DEFAULT false,
+    is_active   BOOLEAN      NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_dimensions_company_code UNIQUE (company_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS dimension_values (
+    id            SERIAL       PRIMARY KEY,
+    dimension_id  INT          NOT NULL REFERENCES dimensions(id),
+    code          VARCHAR(20)  NOT NULL,
+    name          TEXT         NOT NULL,
+    is_active     BOOLEAN      NOT NULL DEFAULT true,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_dimension_values_code UNIQUE (dimension_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS account_dimension_rules (
+    account_id    INT  NOT NULL REFERENCES accounts(id),
+    dimension_id  INT  NOT NULL REFERENCES dimensions(id),
+    PRIMARY KEY (account_id, dimension_id)
+);
+
+CREATE TABLE IF NOT EXISTS journal_line_dimensions (
+    journal_line_id  INT  NOT NULL REFERENCES journal_lines(id) ON DELETE CASCADE,
+    dimension_id     INT  NOT NULL REFERENCES dimensions(id),
+    value_id         INT  NOT NULL REFERENCES dimension_values(id),
+    PRIMARY KEY (journal_line_id, dimension_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_line_dimensions_value ON journal_line_dimensions (value_id);
+
+ALTER TABLE sales_order_lines    ADD COLUMN IF NOT EXISTS dimensions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS dimensions JSONB NOT NULL DEFAULT '[]';
+
+INSERT INTO dimensions (company_id, code, name, is_system)
+SELECT c.id, d.code, d.name, true
+FROM companies c
+CROSS JOIN (VALUES
+    ('COST_CENTER', 'Cost Center'),
+    ('PROJECT',     'Project'),
+    ('BRANCH',      'Branch')
+) AS d(code, name)
+ON CONFLICT (company_id, code) DO NOTHING;
+
+-- Move the cost centers into the COST_CENTER dimension. Budget lines are repointed in two
+-- steps (through negative ids) so that no intermediate row collides on uq_budget_lines.
+DO $$
+BEGIN
+    IF to_regclass('cost_centers') IS NOT NULL THEN
+        INSERT INTO dimension_values (dimension_id, code, name, is_active, created_at)
+        SELECT d.id, cc.code, cc.name, cc.is_active, cc.created_at
+        FROM cost_centers cc
+        JOIN dimensions d ON d.company_id = cc.company_id AND d.code = 'COST_CENTER'
+        ON CONFLICT (dimension_id, code) DO NOTHING;
+
+        ALTER TABLE budget_lines DROP CONSTRAINT IF EXISTS budget_lines_cost_center_id_fkey;
+
+        UPDATE budget_lines bl SET cost_center_id = -dv.id
+        FROM cost_centers cc
+        JOIN dimensions d        ON d.company_id = cc.company_id AND d.code = 'COST_CENTER'
+        JOIN dimension_values dv ON dv.dimension_id = d.id AND dv.code = cc.code
+        WHERE bl.cost_center_id = cc.id;
+
+        UPDATE budget_lines SET cost_center_id = -cost_center_id WHERE cost_center_id < 0;
+
+        DROP TABLE cost_centers;
+    END IF;
+END $$;
+
+ALTER TABLE budget_lines DROP CONSTRAINT IF EXISTS budget_lines_cost_center_id_fkey;
+ALTER TABLE budget_lines
+    ADD CONSTRAINT budget_lines_cost_center_id_fkey
+    FOREIGN KEY (cost_center_id) REFERENCES dimension_values(id);
@@ -0,0 +1,15 @@
+-- Migration 050: Re-invoicing after an e-invoice cancellation.
+-- Cancelling an IRN reverses the invoice entry and moves the order back to SHIPPED (the
+-- goods are still gone, so the shipment's COGS and stock postings stand); the order can
+-- then be invoiced again under a new invoice number and registered for a new IRN.
+-- einvoices.invoice_document_id is the sales invoice the IRN was registered for, so a
+-- cancelled e-invoice keeps its invoice number once the order is re-invoiced.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE einvoices
+    ADD COLUMN IF NOT EXISTS invoice_document_id INT NULL REFERENCES documents(id);
+
+UPDATE einvoices ei
+SET invoice_document_id = so.invoice_document_id
+FROM sales_orders so
+WHERE so.id = ei.order_id AND ei.invoice_document_id IS NULL;
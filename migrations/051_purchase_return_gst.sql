@@ -0,0 +1,24 @@
+-- Migration 051: Input GST reversal on purchase returns.
+-- Returning goods from a PO line the vendor has invoiced reverses the input tax credit
+-- taken on that invoice line, pro rata to the quantity returned: the debit note posts
+-- DR vendor AP (goods at receipt cost + GST) / CR Inventory / CR GST_INPUT_<component>.
+-- line_taxes gains purchase_return_line_id for the components reversed on each returned
+-- line (in base currency); purchase_return_lines.tax_amount and purchase_returns.tax_amount
+-- hold the GST part, which is included in the debit note amount.
+-- Idempotent: uses IF NOT EXISTS.
+
+ALTER TABLE purchase_return_lines
+    ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE purchase_returns
+    ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE line_taxes
+    ADD COLUMN IF NOT EXISTS purchase_return_line_id INT NULL REFERENCES purchase_return_lines(id);
+
+ALTER TABLE line_taxes DROP CONSTRAINT IF EXISTS chk_line_taxes_one_source;
+ALTER TABLE line_taxes
+    ADD CONSTRAINT chk_line_taxes_one_source
+        CHECK (num_nonnulls(sales_order_line_id, vendor_invoice_line_id, vendor_bill_line_id, purchase_return_line_id) = 1);
+
+CREATE INDEX IF NOT EXISTS idx_line_taxes_return_line ON line_taxes(purchase_return_line_id);
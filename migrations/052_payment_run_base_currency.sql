@@ -0,0 +1,7 @@
+-- Migration 052: Payment runs pay in the company base currency only.
+-- Foreign-currency purchase orders are never proposed on a run, so the run-level
+-- currency filter is dropped; payment_run_items.currency still records each document's
+-- currency.
+-- Idempotent: uses IF EXISTS.
+
+ALTER TABLE payment_runs DROP COLUMN IF EXISTS currency;
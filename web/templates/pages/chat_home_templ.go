@@ -46,7 +46,7 @@ func ChatHome(d layouts.AppLayoutData) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"flex-1 flex flex-col overflow-hidden\" x-data=\"chatHome()\" x-init=\"init()\"><!-- Message thread (scrollable) --><div class=\"flex-1 overflow-y-auto bg-gradient-to-b from-indigo-50 via-slate-50 to-blue-50\" id=\"chat-thread\"><!-- Welcome state — shown when no messages yet --><div class=\"flex flex-col px-6 pt-8 pb-4 max-w-3xl mx-auto w-full\" x-show=\"messages.length === 0\"><h1 class=\"text-xl font-semibold text-slate-800 mb-1\">Hi, I'm your AI accounting assistant</h1><p class=\"text-sm text-slate-500 mb-6 max-w-lg\">Describe a business event in plain English and I'll propose the accounting entry for you to review and post. I can also pull up reports like trial balance, P&amp;L, and balance sheet on request. For other reports, use the <span class=\"font-medium text-slate-700\">Reports</span> section in the left-hand navigation.</p><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- Accounting Entries --><div class=\"bg-blue-100 border border-blue-200 rounded-xl p-4\"><div class=\"flex items-center gap-2 mb-1\"><span class=\"text-base\">📝</span><h2 class=\"text-sm font-semibold text-slate-900\">Accounting Entries</h2></div><p class=\"text-xs text-slate-700 mb-3\">Journal entries, sales invoices, purchase invoices. Click an example to try:</p><div class=\"space-y-2\"><button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Rent accrued for Rs 1000 — debit rent expense, credit accounts payable')\">\"Rent accrued for ₹1,000 to accounts payable\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Paid utilities expense for Rs 1000 from cash account')\">\"Paid utilities expense for ₹1,000 from cash account\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Customer paid Rs 25000 against outstanding invoice')\">\"Customer paid ₹25,000 against outstanding invoice\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Purchase invoice from vendor for office supplies Rs 5000')\">\"Purchase invoice from vendor for office supplies ₹5,000\"</button></div></div><!-- Reports --><div class=\"bg-blue-100 border border-blue-200 rounded-xl p-4\"><div class=\"flex items-center gap-2 mb-1\"><span class=\"text-base\">📊</span><h2 class=\"text-sm font-semibold text-slate-900\">Reports</h2></div><p class=\"text-xs text-slate-700 mb-3\">Ask for account balances directly in chat:</p><div class=\"space-y-2 mb-4\"><button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('What is the current balance of accounts receivable?')\">\"What is the balance of accounts receivable?\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('What is the current AP balance?')\">\"What is the current AP balance?\"</button></div><div class=\"border-t border-slate-100 pt-3\"><p class=\"text-xs text-slate-700 mb-2\">Full financial statements are in the <span class=\"font-medium text-slate-800\">Reports</span> section:</p><div class=\"flex flex-wrap gap-1.5\"><a href=\"/reports/trial-balance\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Trial Balance</a> <a href=\"/reports/pl\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">P&amp;L Report</a> <a href=\"/reports/balance-sheet\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Balance Sheet</a> <a href=\"/reports/statement\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Account Statement</a></div></div></div></div></div><!-- Message list --><div class=\"px-4 py-4 space-y-3 max-w-3xl mx-auto\" x-show=\"messages.length > 0\"><template x-for=\"(msg, idx) in messages\" :key=\"idx\"><div><!-- User bubble --><template x-if=\"msg.role === 'user'\"><div class=\"flex justify-end\"><div class=\"max-w-[75%] bg-gradient-to-br from-slate-900 to-slate-800 text-white rounded-2xl rounded-tr-sm px-4 py-3 text-sm leading-relaxed\" x-text=\"msg.text\"></div></div></template><!-- AI text bubble --><template x-if=\"msg.role === 'ai' && msg.type === 'text'\"><div class=\"flex justify-start\"><div class=\"max-w-[75%] bg-white border border-gray-100 shadow-sm text-slate-800 rounded-2xl rounded-tl-sm px-4 py-3 text-sm leading-relaxed chat-md\" x-html=\"msg.html || msg.text\"></div></div></template><!-- Action card (write tool proposal) --><template x-if=\"msg.role === 'ai' && msg.type === 'action_card'\"><div class=\"border border-amber-200 bg-amber-50 rounded-2xl p-4 max-w-sm\"><div class=\"flex items-center gap-2 mb-2\"><span class=\"text-base\">🔧</span> <span class=\"text-sm font-semibold text-amber-900\" x-text=\"toolLabel(msg.tool)\"></span></div><pre class=\"text-xs text-amber-700 bg-amber-100 rounded-lg p-2 overflow-auto max-h-40 mb-3\" x-text=\"JSON.stringify(msg.args, null, 2)\"></pre><div x-show=\"msg.status === undefined || msg.status === 'pending'\" class=\"flex gap-2\"><button class=\"flex-1 px-3 py-1.5 bg-amber-600 text-white text-sm font-medium rounded-lg hover:bg-amber-700 transition-colors\" x-on:click=\"confirmAction(msg, 'confirm')\">✓ Confirm</button> <button class=\"px-3 py-1.5 border border-amber-300 text-amber-700 text-sm rounded-lg hover:bg-amber-100 transition-colors\" x-on:click=\"confirmAction(msg, 'cancel')\">✕ Cancel</button></div><div x-show=\"msg.status === 'confirmed'\" class=\"text-sm text-green-700 font-medium\">✓ <span x-text=\"msg.resultText\"></span></div><div x-show=\"msg.status === 'cancelled'\" class=\"text-sm text-slate-500\">Cancelled.</div><div x-show=\"msg.status === 'error'\" class=\"text-sm text-red-600\">⚠ <span x-text=\"msg.resultText\"></span></div></div></template><!-- Journal entry proposal card --><template x-if=\"msg.role === 'ai' && msg.type === 'proposal'\"><div class=\"border border-blue-200 bg-blue-50 rounded-2xl p-4 max-w-lg\"><!-- Header: icon + title + doc type / company badges --><div class=\"flex items-center justify-between mb-3\"><div class=\"flex items-center gap-2\"><span class=\"text-base\">🧾</span> <span class=\"text-sm font-semibold text-blue-900\">Journal Entry Proposal</span></div><div class=\"flex gap-1\"><span class=\"text-xs font-mono bg-blue-200 text-blue-800 px-2 py-0.5 rounded\" x-text=\"msg.proposal && msg.proposal.document_type_code\"></span> <span class=\"text-xs font-mono bg-slate-200 text-slate-700 px-2 py-0.5 rounded\" x-text=\"msg.proposal && msg.proposal.company_code\"></span></div></div><!-- Summary --><div class=\"text-sm text-slate-800 font-medium mb-2\" x-text=\"msg.proposal && msg.proposal.summary\"></div><!-- Metadata grid --><div class=\"grid grid-cols-2 gap-x-4 gap-y-1 text-xs mb-2\"><div class=\"flex gap-1\"><span class=\"text-slate-500\">Posting</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal && msg.proposal.posting_date\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Doc date</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal && msg.proposal.document_date\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Currency</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal ? msg.proposal.transaction_currency + ' @ ' + msg.proposal.exchange_rate : ''\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Confidence</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal ? (msg.proposal.confidence * 100).toFixed(0) + '%' : ''\"></span></div></div><!-- Reasoning --><div class=\"text-xs text-blue-700 italic mb-3\" x-text=\"msg.proposal && msg.proposal.reasoning\"></div><!-- Journal lines table --><div class=\"bg-white border border-blue-100 rounded-lg overflow-hidden mb-3\"><table class=\"w-full text-xs\"><thead><tr class=\"bg-blue-50 border-b border-blue-100\"><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium w-10\">Type</th><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium w-16\">Account</th><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium\">Description</th><th class=\"text-right px-3 py-1.5 text-slate-500 font-medium\">Amount</th></tr></thead> <tbody><template x-for=\"(line, li) in (msg.proposal && msg.proposal.lines || [])\"><tr class=\"border-b border-blue-50 last:border-0\"><td class=\"px-3 py-1.5\"><span class=\"font-mono font-semibold\" :class=\"line.is_debit ? 'text-emerald-700' : 'text-rose-600'\" x-text=\"line.is_debit ? 'DR' : 'CR'\"></span></td><td class=\"px-3 py-1.5 font-mono text-slate-700 w-16\" x-text=\"line.account_code\"></td><td class=\"px-3 py-1.5 text-slate-600 text-xs\" x-text=\"line.account_name || '—'\"></td><td class=\"px-3 py-1.5 font-mono text-right text-slate-800\" x-text=\"line.amount + ' ' + (msg.proposal && msg.proposal.transaction_currency)\"></td></tr></template></tbody></table></div><!-- Actions --><div x-show=\"msg.status === undefined || msg.status === 'pending'\" class=\"flex gap-2\"><button class=\"flex-1 px-3 py-1.5 border border-blue-300 text-slate-800 hover:text-slate-900 text-sm font-medium rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"confirmAction(msg, 'confirm')\">✓ Post Entry</button> <button class=\"px-3 py-1.5 border border-blue-300 text-blue-700 text-sm rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"amendAction(msg)\">✎ Amend</button> <button class=\"px-3 py-1.5 border border-blue-300 text-blue-700 text-sm rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"confirmAction(msg, 'cancel')\">✕ Cancel</button></div><div x-show=\"msg.status === 'confirmed'\" class=\"text-sm text-green-700 font-medium\">✓ Journal entry posted.</div><div x-show=\"msg.status === 'cancelled'\" class=\"text-sm text-slate-500\">Cancelled.</div><div x-show=\"msg.status === 'error'\" class=\"text-sm text-red-600\">⚠ <span x-text=\"msg.resultText\"></span></div></div></template></div></template><!-- Typing indicator --><div x-show=\"sending\" class=\"flex justify-start\"><div class=\"bg-white border border-gray-100 shadow-sm rounded-2xl rounded-tl-sm px-4 py-3 flex items-center gap-1.5\"><div class=\"typing-dots flex gap-1\"><span></span><span></span><span></span></div></div></div></div></div><!-- Input bar (sticky bottom) --><div class=\"bg-white border-t border-gray-200 px-4 py-3 flex-shrink-0\"><!-- Attachment chips --><div class=\"flex flex-wrap gap-2 mb-2\" x-show=\"attachments.length > 0\"><template x-for=\"(att, idx) in attachments\" :key=\"att.id\"><div class=\"flex items-center gap-1.5 px-2 py-1 bg-blue-100 rounded-lg text-xs text-slate-700\"><span>📎</span> <span x-text=\"att.name\" class=\"max-w-24 truncate\"></span> <button class=\"text-slate-500 hover:text-slate-900\" x-on:click=\"removeAttachment(idx)\">✕</button></div></template></div><div class=\"flex gap-2 items-end max-w-3xl mx-auto\"><!-- Paperclip button --><button class=\"p-2 text-slate-900 hover:text-slate-700 hover:bg-slate-100 rounded-lg transition-colors flex-shrink-0\" x-on:click=\"$refs.fileInput.click()\" title=\"Attach image\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15.172 7l-6.586 6.586a2 2 0 102.828 2.828l6.414-6.586a4 4 0 00-5.656-5.656l-6.415 6.585a6 6 0 108.486 8.486L20.5 13\"></path></svg></button> <input type=\"file\" x-ref=\"fileInput\" accept=\"image/jpeg,image/png,image/webp\" multiple class=\"hidden\" x-on:change=\"handleFileSelect($event)\"><!-- Text input --><textarea x-model=\"input\" rows=\"1\" placeholder=\"Ask anything… Type your message and press Ctrl+Enter or click the send button to submit.\" class=\"flex-1 text-sm bg-yellow-50 border-2 border-blue-400 text-slate-900 placeholder-slate-400 rounded-xl px-3 py-2 resize-none focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500 max-h-32\" autofocus x-on:keydown.ctrl.enter.prevent=\"sendMessage()\" x-on:input=\"autoResize($event.target)\"></textarea><!-- Send button --><button class=\"p-2 bg-slate-900 text-white rounded-xl hover:bg-slate-700 transition-colors flex-shrink-0 disabled:opacity-40\" x-on:click=\"sendMessage()\" x-bind:disabled=\"sending || input.trim() === ''\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 19l9 2-9-18-9 18 9-2zm0 0v-8\"></path></svg></button></div></div></div><script>\n\t\tfunction chatHome() {\n\t\t\tconst STORAGE_KEY = 'chat_history';\n\t\t\tconst COMPANY_CODE = document.body.dataset.companyCode || '';\n\n\t\t\treturn {\n\t\t\t\tmessages: [],\n\t\t\t\tinput: '',\n\t\t\t\tsending: false,\n\t\t\t\tattachments: [],  // {id, name, type}\n\n\t\t\t\tinit() {\n\t\t\t\t\t// Clear history when the user clicks \"New Chat\" (/?new=1)\n\t\t\t\t\tif (new URLSearchParams(window.location.search).has('new')) {\n\t\t\t\t\t\tsessionStorage.removeItem('chat_history');\n\t\t\t\t\t\thistory.replaceState({}, '', '/');\n\t\t\t\t\t}\n\t\t\t\t\tthis.loadHistory();\n\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t},\n\n\t\t\t\tloadHistory() {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst raw = sessionStorage.getItem(STORAGE_KEY);\n\t\t\t\t\t\tif (raw) this.messages = JSON.parse(raw);\n\t\t\t\t\t} catch(e) { this.messages = []; }\n\t\t\t\t},\n\n\t\t\t\tsaveHistory() {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tsessionStorage.setItem(STORAGE_KEY, JSON.stringify(this.messages));\n\t\t\t\t\t} catch(e) {}\n\t\t\t\t},\n\n\t\t\t\tscrollToBottom() {\n\t\t\t\t\tconst thread = document.getElementById('chat-thread');\n\t\t\t\t\tif (thread) thread.scrollTop = thread.scrollHeight;\n\t\t\t\t},\n\n\t\t\t\tautoResize(el) {\n\t\t\t\t\tel.style.height = 'auto';\n\t\t\t\t\tel.style.height = Math.min(el.scrollHeight, 128) + 'px';\n\t\t\t\t},\n\n\t\t\t\tquickSend(text) {\n\t\t\t\t\tthis.input = text;\n\t\t\t\t\tthis.sendMessage();\n\t\t\t\t},\n\n\t\t\t\ttoolLabel(tool) {\n\t\t\t\t\tconst labels = {\n\t\t\t\t\t\t'approve_po': 'Approve Purchase Order',\n\t\t\t\t\t\t'create_vendor': 'Create Vendor',\n\t\t\t\t\t\t'create_purchase_order': 'Create Purchase Order',\n\t\t\t\t\t\t'receive_po': 'Receive Goods Against PO',\n\t\t\t\t\t\t'record_vendor_invoice': 'Record Vendor Invoice',\n\t\t\t\t\t\t'pay_vendor': 'Pay Vendor',\n\t\t\t\t\t};\n\t\t\t\t\treturn labels[tool] || tool;\n\t\t\t\t},\n\n\t\t\t\tasync handleFileSelect(event) {\n\t\t\t\t\tconst files = Array.from(event.target.files || []);\n\t\t\t\t\tevent.target.value = '';\n\t\t\t\t\tfor (const file of files) {\n\t\t\t\t\t\tconst formData = new FormData();\n\t\t\t\t\t\tformData.append('file', file);\n\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\tconst resp = await fetch('/chat/upload', { method: 'POST', body: formData });\n\t\t\t\t\t\t\tif (resp.ok) {\n\t\t\t\t\t\t\t\tconst results = await resp.json();\n\t\t\t\t\t\t\t\tfor (const r of (Array.isArray(results) ? results : [results])) {\n\t\t\t\t\t\t\t\t\tthis.attachments.push({ id: r.attachment_id, name: r.filename, type: r.file_type });\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t} catch(e) { console.error('Upload failed:', e); }\n\t\t\t\t\t}\n\t\t\t\t},\n\n\t\t\t\tremoveAttachment(idx) {\n\t\t\t\t\tthis.attachments.splice(idx, 1);\n\t\t\t\t},\n\n\t\t\t\tasync sendMessage() {\n\t\t\t\t\tconst text = this.input.trim();\n\t\t\t\t\tif (!text || this.sending) return;\n\n\t\t\t\t\tthis.messages.push({ role: 'user', type: 'text', text });\n\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\tthis.input = '';\n\t\t\t\t\tthis.sending = true;\n\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\n\t\t\t\t\tconst attachmentIDs = this.attachments.map(a => a.id);\n\t\t\t\t\tthis.attachments = [];\n\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst resp = await fetch('/chat', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\t\t\tbody: JSON.stringify({ text, company_code: COMPANY_CODE, attachment_ids: attachmentIDs }),\n\t\t\t\t\t\t});\n\n\t\t\t\t\t\tif (!resp.ok) {\n\t\t\t\t\t\t\tlet errMsg = `Server error (${resp.status})`;\n\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\tconst errBody = await resp.json();\n\t\t\t\t\t\t\t\terrMsg = errBody.message || errBody.error || errMsg;\n\t\t\t\t\t\t\t} catch (_) {}\n\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ ' + errMsg });\n\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\treturn;\n\t\t\t\t\t\t}\n\n\t\t\t\t\t\tconst reader = resp.body.getReader();\n\t\t\t\t\t\tconst decoder = new TextDecoder();\n\t\t\t\t\t\tlet buf = '';\n\t\t\t\t\t\tlet aiMsg = null;\n\t\t\t\t\t\tlet anyResponse = false;\n\n\t\t\t\t\t\twhile (true) {\n\t\t\t\t\t\t\tconst { done, value } = await reader.read();\n\t\t\t\t\t\t\tif (done) break;\n\t\t\t\t\t\t\tbuf += decoder.decode(value, { stream: true });\n\t\t\t\t\t\t\tconst parts = buf.split('\\n\\n');\n\t\t\t\t\t\t\tbuf = parts.pop() || '';\n\t\t\t\t\t\t\tfor (const part of parts) {\n\t\t\t\t\t\t\t\tlet event = 'message', data = '';\n\t\t\t\t\t\t\t\tfor (const line of part.split('\\n')) {\n\t\t\t\t\t\t\t\t\tif (line.startsWith('event: ')) event = line.slice(7).trim();\n\t\t\t\t\t\t\t\t\telse if (line.startsWith('data: ')) data = line.slice(6);\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\tif (!data) continue;\n\t\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\t\tconst d = JSON.parse(data);\n\t\t\t\t\t\t\t\t\tif (event === 'answer') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tif (!aiMsg) {\n\t\t\t\t\t\t\t\t\t\t\tconst raw = d.text || '';\n\t\t\t\t\t\t\t\t\t\t\taiMsg = { role: 'ai', type: 'text', text: raw, html: marked.parse(raw) };\n\t\t\t\t\t\t\t\t\t\t\tthis.messages.push(aiMsg);\n\t\t\t\t\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\t\t\t\t\taiMsg.text = (aiMsg.text || '') + (d.text || '');\n\t\t\t\t\t\t\t\t\t\t\taiMsg.html = marked.parse(aiMsg.text);\n\t\t\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'clarification') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '❓ ' + (d.question || '') });\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'action_card') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({\n\t\t\t\t\t\t\t\t\t\t\trole: 'ai', type: 'action_card',\n\t\t\t\t\t\t\t\t\t\t\ttoken: d.token, tool: d.tool, args: d.args,\n\t\t\t\t\t\t\t\t\t\t\tstatus: 'pending',\n\t\t\t\t\t\t\t\t\t\t});\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'proposal') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({\n\t\t\t\t\t\t\t\t\t\t\trole: 'ai', type: 'proposal',\n\t\t\t\t\t\t\t\t\t\t\ttoken: d.token, proposal: d.proposal,\n\t\t\t\t\t\t\t\t\t\t\tstatus: 'pending',\n\t\t\t\t\t\t\t\t\t\t});\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'error') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ ' + (d.message || 'Error') });\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\t} catch(e) { console.error('SSE parse error:', e); }\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t}\n\t\t\t\t\tif (!anyResponse) {\n\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: 'No response received. Please try again.', html: 'No response received. Please try again.' });\n\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t}\n\t\t\t\t\t} catch(err) {\n\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ Connection error: ' + err.message });\n\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t} finally {\n\t\t\t\t\t\tthis.sending = false;\n\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t}\n\t\t\t\t},\n\n\t\t\t\tasync confirmAction(msg, action) {\n\t\t\t\t\tmsg.status = action === 'confirm' ? 'confirming' : 'cancelling';\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst resp = await fetch('/chat/confirm', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\t\t\tbody: JSON.stringify({ token: msg.token, action }),\n\t\t\t\t\t\t});\n\t\t\t\t\t\tconst data = await resp.json();\n\t\t\t\t\t\tif (action === 'cancel') {\n\t\t\t\t\t\t\tmsg.status = 'cancelled';\n\t\t\t\t\t\t} else if (resp.ok && data.ok) {\n\t\t\t\t\t\t\tmsg.status = 'confirmed';\n\t\t\t\t\t\t\tconst result = data.result;\n\t\t\t\t\t\t\tmsg.resultText = data.message || (result && result.message) || 'Done.';\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\tmsg.status = 'error';\n\t\t\t\t\t\t\tmsg.resultText = data.error || 'Failed.';\n\t\t\t\t\t\t}\n\t\t\t\t\t} catch(e) {\n\t\t\t\t\t\tmsg.status = 'error';\n\t\t\t\t\t\tmsg.resultText = 'Network error.';\n\t\t\t\t\t}\n\t\t\t\t\tthis.saveHistory();\n\t\t\t\t},\n\n\t\t\t\tamendAction(msg) {\n\t\t\t\t\tthis.input = (msg.proposal && msg.proposal.summary)\n\t\t\t\t\t\t? 'Please revise: ' + msg.proposal.summary\n\t\t\t\t\t\t: '';\n\t\t\t\t\tthis.confirmAction(msg, 'cancel');\n\t\t\t\t\tthis.$nextTick(() => {\n\t\t\t\t\t\tconst ta = document.querySelector('textarea');\n\t\t\t\t\t\tif (ta) ta.focus();\n\t\t\t\t\t});\n\t\t\t\t},\n\t\t\t};\n\t\t}\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"flex-1 flex flex-col overflow-hidden\" x-data=\"chatHome()\" x-init=\"init()\"><!-- Message thread (scrollable) --><div class=\"flex-1 overflow-y-auto bg-gradient-to-b from-indigo-50 via-slate-50 to-blue-50\" id=\"chat-thread\"><!-- Welcome state — shown when no messages yet --><div class=\"flex flex-col px-6 pt-8 pb-4 max-w-3xl mx-auto w-full\" x-show=\"messages.length === 0\"><h1 class=\"text-xl font-semibold text-slate-800 mb-1\">Hi, I'm your AI accounting assistant</h1><p class=\"text-sm text-slate-500 mb-6 max-w-lg\">Describe a business event in plain English and I'll propose the accounting entry for you to review and post. I can also pull up reports like trial balance, P&amp;L, and balance sheet on request. For other reports, use the <span class=\"font-medium text-slate-700\">Reports</span> section in the left-hand navigation.</p><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- Accounting Entries --><div class=\"bg-blue-100 border border-blue-200 rounded-xl p-4\"><div class=\"flex items-center gap-2 mb-1\"><span class=\"text-base\">📝</span><h2 class=\"text-sm font-semibold text-slate-900\">Accounting Entries</h2></div><p class=\"text-xs text-slate-700 mb-3\">Journal entries, sales invoices, purchase invoices. Click an example to try:</p><div class=\"space-y-2\"><button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Rent accrued for Rs 1000 — debit rent expense, credit accounts payable')\">\"Rent accrued for ₹1,000 to accounts payable\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Paid utilities expense for Rs 1000 from cash account')\">\"Paid utilities expense for ₹1,000 from cash account\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Customer paid Rs 25000 against outstanding invoice')\">\"Customer paid ₹25,000 against outstanding invoice\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('Purchase invoice from vendor for office supplies Rs 5000')\">\"Purchase invoice from vendor for office supplies ₹5,000\"</button></div></div><!-- Reports --><div class=\"bg-blue-100 border border-blue-200 rounded-xl p-4\"><div class=\"flex items-center gap-2 mb-1\"><span class=\"text-base\">📊</span><h2 class=\"text-sm font-semibold text-slate-900\">Reports</h2></div><p class=\"text-xs text-slate-700 mb-3\">Ask for account balances directly in chat:</p><div class=\"space-y-2 mb-4\"><button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('What is the current balance of accounts receivable?')\">\"What is the balance of accounts receivable?\"</button> <button class=\"w-full text-left text-xs bg-white hover:bg-blue-50 border border-blue-200 hover:border-blue-400 text-slate-900 rounded-lg px-3 py-2 transition-colors\" x-on:click=\"quickSend('What is the current AP balance?')\">\"What is the current AP balance?\"</button></div><div class=\"border-t border-slate-100 pt-3\"><p class=\"text-xs text-slate-700 mb-2\">Full financial statements are in the <span class=\"font-medium text-slate-800\">Reports</span> section:</p><div class=\"flex flex-wrap gap-1.5\"><a href=\"/reports/trial-balance\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Trial Balance</a> <a href=\"/reports/pl\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">P&amp;L Report</a> <a href=\"/reports/balance-sheet\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Balance Sheet</a> <a href=\"/reports/statement\" class=\"text-xs px-2 py-1 bg-white hover:bg-blue-50 text-slate-900 border border-blue-200 rounded-md transition-colors\">Account Statement</a></div></div></div></div></div><!-- Message list --><div class=\"px-4 py-4 space-y-3 max-w-3xl mx-auto\" x-show=\"messages.length > 0\"><template x-for=\"(msg, idx) in messages\" :key=\"idx\"><div><!-- User bubble --><template x-if=\"msg.role === 'user'\"><div class=\"flex justify-end\"><div class=\"max-w-[75%] bg-gradient-to-br from-slate-900 to-slate-800 text-white rounded-2xl rounded-tr-sm px-4 py-3 text-sm leading-relaxed\" x-text=\"msg.text\"></div></div></template><!-- AI text bubble --><template x-if=\"msg.role === 'ai' && msg.type === 'text'\"><div class=\"flex justify-start\"><div class=\"max-w-[75%] bg-white border border-gray-100 shadow-sm text-slate-800 rounded-2xl rounded-tl-sm px-4 py-3 text-sm leading-relaxed chat-md\" x-html=\"msg.html || msg.text\"></div></div></template><!-- Action card (write tool proposal) --><template x-if=\"msg.role === 'ai' && msg.type === 'action_card'\"><div class=\"border border-amber-200 bg-amber-50 rounded-2xl p-4 max-w-sm\"><div class=\"flex items-center gap-2 mb-2\"><span class=\"text-base\">🔧</span> <span class=\"text-sm font-semibold text-amber-900\" x-text=\"toolLabel(msg.tool)\"></span></div><pre class=\"text-xs text-amber-700 bg-amber-100 rounded-lg p-2 overflow-auto max-h-40 mb-3\" x-text=\"JSON.stringify(msg.args, null, 2)\"></pre><div x-show=\"msg.status === undefined || msg.status === 'pending'\" class=\"flex gap-2\"><button class=\"flex-1 px-3 py-1.5 bg-amber-600 text-white text-sm font-medium rounded-lg hover:bg-amber-700 transition-colors\" x-on:click=\"confirmAction(msg, 'confirm')\">✓ Confirm</button> <button class=\"px-3 py-1.5 border border-amber-300 text-amber-700 text-sm rounded-lg hover:bg-amber-100 transition-colors\" x-on:click=\"confirmAction(msg, 'cancel')\">✕ Cancel</button></div><div x-show=\"msg.status === 'confirmed'\" class=\"text-sm text-green-700 font-medium\">✓ <span x-text=\"msg.resultText\"></span></div><div x-show=\"msg.status === 'cancelled'\" class=\"text-sm text-slate-500\">Cancelled.</div><div x-show=\"msg.status === 'error'\" class=\"text-sm text-red-600\">⚠ <span x-text=\"msg.resultText\"></span></div></div></template><!-- Journal entry proposal card --><template x-if=\"msg.role === 'ai' && msg.type === 'proposal'\"><div class=\"border border-blue-200 bg-blue-50 rounded-2xl p-4 max-w-lg\"><!-- Header: icon + title + doc type / company badges --><div class=\"flex items-center justify-between mb-3\"><div class=\"flex items-center gap-2\"><span class=\"text-base\">🧾</span> <span class=\"text-sm font-semibold text-blue-900\">Journal Entry Proposal</span></div><div class=\"flex gap-1\"><span class=\"text-xs font-mono bg-blue-200 text-blue-800 px-2 py-0.5 rounded\" x-text=\"msg.proposal && msg.proposal.document_type_code\"></span> <span class=\"text-xs font-mono bg-slate-200 text-slate-700 px-2 py-0.5 rounded\" x-text=\"msg.proposal && msg.proposal.company_code\"></span></div></div><!-- Summary --><div class=\"text-sm text-slate-800 font-medium mb-2\" x-text=\"msg.proposal && msg.proposal.summary\"></div><!-- Metadata grid --><div class=\"grid grid-cols-2 gap-x-4 gap-y-1 text-xs mb-2\"><div class=\"flex gap-1\"><span class=\"text-slate-500\">Posting</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal && msg.proposal.posting_date\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Doc date</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal && msg.proposal.document_date\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Currency</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal ? msg.proposal.transaction_currency + ' @ ' + msg.proposal.exchange_rate : ''\"></span></div><div class=\"flex gap-1\"><span class=\"text-slate-500\">Confidence</span><span class=\"font-mono text-slate-700\" x-text=\"msg.proposal ? (msg.proposal.confidence * 100).toFixed(0) + '%' : ''\"></span></div></div><!-- Reasoning --><div class=\"text-xs text-blue-700 italic mb-3\" x-text=\"msg.proposal && msg.proposal.reasoning\"></div><!-- Journal lines table --><div class=\"bg-white border border-blue-100 rounded-lg overflow-hidden mb-3\"><table class=\"w-full text-xs\"><thead><tr class=\"bg-blue-50 border-b border-blue-100\"><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium w-10\">Type</th><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium w-16\">Account</th><th class=\"text-left px-3 py-1.5 text-slate-500 font-medium\">Description</th><th class=\"text-right px-3 py-1.5 text-slate-500 font-medium\">Amount</th></tr></thead> <tbody><template x-for=\"(line, li) in (msg.proposal && msg.proposal.lines || [])\"><tr class=\"border-b border-blue-50 last:border-0\"><td class=\"px-3 py-1.5\"><span class=\"font-mono font-semibold\" :class=\"line.is_debit ? 'text-emerald-700' : 'text-rose-600'\" x-text=\"line.is_debit ? 'DR' : 'CR'\"></span></td><td class=\"px-3 py-1.5 font-mono text-slate-700 w-16\" x-text=\"line.account_code\"></td><td class=\"px-3 py-1.5 text-slate-600 text-xs\" x-text=\"line.account_name || '—'\"></td><td class=\"px-3 py-1.5 font-mono text-right text-slate-800\" x-text=\"line.amount + ' ' + (msg.proposal && msg.proposal.transaction_currency)\"></td></tr></template></tbody></table></div><!-- Actions --><div x-show=\"msg.status === undefined || msg.status === 'pending'\" class=\"flex gap-2\"><button class=\"flex-1 px-3 py-1.5 border border-blue-300 text-slate-800 hover:text-slate-900 text-sm font-medium rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"confirmAction(msg, 'confirm')\">✓ Post Entry</button> <button class=\"px-3 py-1.5 border border-blue-300 text-blue-700 text-sm rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"amendAction(msg)\">✎ Amend</button> <button class=\"px-3 py-1.5 border border-blue-300 text-blue-700 text-sm rounded-lg hover:bg-blue-100 transition-colors\" x-on:click=\"confirmAction(msg, 'cancel')\">✕ Cancel</button></div><div x-show=\"msg.status === 'confirmed'\" class=\"text-sm text-green-700 font-medium\">✓ Journal entry posted.</div><div x-show=\"msg.status === 'cancelled'\" class=\"text-sm text-slate-500\">Cancelled.</div><div x-show=\"msg.status === 'error'\" class=\"text-sm text-red-600\">⚠ <span x-text=\"msg.resultText\"></span></div></div></template></div></template><!-- Typing indicator --><div x-show=\"sending\" class=\"flex justify-start\"><div class=\"bg-white border border-gray-100 shadow-sm rounded-2xl rounded-tl-sm px-4 py-3 flex items-center gap-1.5\"><div class=\"typing-dots flex gap-1\"><span></span><span></span><span></span></div></div></div></div></div><!-- Input bar (sticky bottom) --><div class=\"bg-white border-t border-gray-200 px-4 py-3 flex-shrink-0\"><!-- Attachment chips --><div class=\"flex flex-wrap gap-2 mb-2\" x-show=\"attachments.length > 0\"><template x-for=\"(att, idx) in attachments\" :key=\"att.id\"><div class=\"flex items-center gap-1.5 px-2 py-1 bg-blue-100 rounded-lg text-xs text-slate-700\"><span>📎</span> <span x-text=\"att.name\" class=\"max-w-24 truncate\"></span> <button class=\"text-slate-500 hover:text-slate-900\" x-on:click=\"removeAttachment(idx)\">✕</button></div></template></div><div class=\"flex gap-2 items-end max-w-3xl mx-auto\"><!-- Paperclip button --><button class=\"p-2 text-slate-900 hover:text-slate-700 hover:bg-slate-100 rounded-lg transition-colors flex-shrink-0\" x-on:click=\"$refs.fileInput.click()\" title=\"Attach image\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15.172 7l-6.586 6.586a2 2 0 102.828 2.828l6.414-6.586a4 4 0 00-5.656-5.656l-6.415 6.585a6 6 0 108.486 8.486L20.5 13\"></path></svg></button> <input type=\"file\" x-ref=\"fileInput\" accept=\"image/jpeg,image/png,image/webp\" multiple class=\"hidden\" x-on:change=\"handleFileSelect($event)\"><!-- Text input --><textarea x-model=\"input\" rows=\"1\" placeholder=\"Ask anything… Type your message and press Ctrl+Enter or click the send button to submit.\" class=\"flex-1 text-sm bg-yellow-50 border-2 border-blue-400 text-slate-900 placeholder-slate-400 rounded-xl px-3 py-2 resize-none focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500 max-h-32\" autofocus x-on:keydown.ctrl.enter.prevent=\"sendMessage()\" x-on:input=\"autoResize($event.target)\"></textarea><!-- Send button --><button class=\"p-2 bg-slate-900 text-white rounded-xl hover:bg-slate-700 transition-colors flex-shrink-0 disabled:opacity-40\" x-on:click=\"sendMessage()\" x-bind:disabled=\"sending || input.trim() === ''\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 19l9 2-9-18-9 18 9-2zm0 0v-8\"></path></svg></button></div></div></div><script>\n\t\tfunction chatHome() {\n\t\t\tconst STORAGE_KEY = 'chat_history';\n\t\t\tconst COMPANY_CODE = document.body.dataset.companyCode || '';\n\n\t\t\treturn {\n\t\t\t\tmessages: [],\n\t\t\t\tinput: '',\n\t\t\t\tsending: false,\n\t\t\t\tattachments: [],  // {id, name, type}\n\n\t\t\t\tinit() {\n\t\t\t\t\t// Clear history when the user clicks \"New Chat\" (/?new=1)\n\t\t\t\t\tif (new URLSearchParams(window.location.search).has('new')) {\n\t\t\t\t\t\tsessionStorage.removeItem('chat_history');\n\t\t\t\t\t\thistory.replaceState({}, '', '/');\n\t\t\t\t\t}\n\t\t\t\t\tthis.loadHistory();\n\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t},\n\n\t\t\t\tloadHistory() {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst raw = sessionStorage.getItem(STORAGE_KEY);\n\t\t\t\t\t\tif (raw) this.messages = JSON.parse(raw);\n\t\t\t\t\t} catch(e) { this.messages = []; }\n\t\t\t\t},\n\n\t\t\t\tsaveHistory() {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tsessionStorage.setItem(STORAGE_KEY, JSON.stringify(this.messages));\n\t\t\t\t\t} catch(e) {}\n\t\t\t\t},\n\n\t\t\t\tscrollToBottom() {\n\t\t\t\t\tconst thread = document.getElementById('chat-thread');\n\t\t\t\t\tif (thread) thread.scrollTop = thread.scrollHeight;\n\t\t\t\t},\n\n\t\t\t\tautoResize(el) {\n\t\t\t\t\tel.style.height = 'auto';\n\t\t\t\t\tel.style.height = Math.min(el.scrollHeight, 128) + 'px';\n\t\t\t\t},\n\n\t\t\t\tquickSend(text) {\n\t\t\t\t\tthis.input = text;\n\t\t\t\t\tthis.sendMessage();\n\t\t\t\t},\n\n\t\t\t\ttoolLabel(tool) {\n\t\t\t\t\tconst labels = {\n\t\t\t\t\t\t'approve_po': 'Approve Purchase Order',\n\t\t\t\t\t\t'create_vendor': 'Create Vendor',\n\t\t\t\t\t\t'update_vendor': 'Update Vendor',\n\t\t\t\t\t\t'set_vendor_active': 'Change Vendor Status',\n\t\t\t\t\t\t'merge_vendors': 'Merge Vendors',\n\t\t\t\t\t\t'create_customer': 'Create Customer',\n\t\t\t\t\t\t'update_customer': 'Update Customer',\n\t\t\t\t\t\t'set_customer_active': 'Change Customer Status',\n\t\t\t\t\t\t'merge_customers': 'Merge Customers',\n\t\t\t\t\t\t'create_purchase_order': 'Create Purchase Order',\n\t\t\t\t\t\t'receive_po': 'Receive Goods Against PO',\n\t\t\t\t\t\t'short_close_po': 'Short-Close PO',\n\t\t\t\t\t\t'amend_po': 'Amend PO',\n\t\t\t\t\t\t'cancel_po': 'Cancel PO',\n\t\t\t\t\t\t'record_vendor_invoice': 'Record Vendor Invoice',\n\t\t\t\t\t\t'pay_vendor': 'Pay Vendor',\n\t\t\t\t\t\t'create_vendor_bill': 'Record Vendor Bill',\n\t\t\t\t\t\t'pay_vendor_bill': 'Pay Vendor Bill',\n\t\t\t\t\t\t'create_payment_run': 'Create Payment Run',\n\t\t\t\t\t\t'create_purchase_return': 'Create Purchase Return',\n\t\t\t\t\t\t'create_replenishment_pos': 'Raise Replenishment POs',\n\t\t\t\t\t\t'create_landed_cost_voucher': 'Post Landed Cost Voucher',\n\t\t\t\t\t};\n\t\t\t\t\treturn labels[tool] || tool;\n\t\t\t\t},\n\n\t\t\t\tasync handleFileSelect(event) {\n\t\t\t\t\tconst files = Array.from(event.target.files || []);\n\t\t\t\t\tevent.target.value = '';\n\t\t\t\t\tfor (const file of files) {\n\t\t\t\t\t\tconst formData = new FormData();\n\t\t\t\t\t\tformData.append('file', file);\n\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\tconst resp = await fetch('/chat/upload', { method: 'POST', body: formData });\n\t\t\t\t\t\t\tif (resp.ok) {\n\t\t\t\t\t\t\t\tconst results = await resp.json();\n\t\t\t\t\t\t\t\tfor (const r of (Array.isArray(results) ? results : [results])) {\n\t\t\t\t\t\t\t\t\tthis.attachments.push({ id: r.attachment_id, name: r.filename, type: r.file_type });\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t} catch(e) { console.error('Upload failed:', e); }\n\t\t\t\t\t}\n\t\t\t\t},\n\n\t\t\t\tremoveAttachment(idx) {\n\t\t\t\t\tthis.attachments.splice(idx, 1);\n\t\t\t\t},\n\n\t\t\t\tasync sendMessage() {\n\t\t\t\t\tconst text = this.input.trim();\n\t\t\t\t\tif (!text || this.sending) return;\n\n\t\t\t\t\tthis.messages.push({ role: 'user', type: 'text', text });\n\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\tthis.input = '';\n\t\t\t\t\tthis.sending = true;\n\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\n\t\t\t\t\tconst attachmentIDs = this.attachments.map(a => a.id);\n\t\t\t\t\tthis.attachments = [];\n\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst resp = await fetch('/chat', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\t\t\tbody: JSON.stringify({ text, company_code: COMPANY_CODE, attachment_ids: attachmentIDs }),\n\t\t\t\t\t\t});\n\n\t\t\t\t\t\tif (!resp.ok) {\n\t\t\t\t\t\t\tlet errMsg = `Server error (${resp.status})`;\n\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\tconst errBody = await resp.json();\n\t\t\t\t\t\t\t\terrMsg = errBody.message || errBody.error || errMsg;\n\t\t\t\t\t\t\t} catch (_) {}\n\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ ' + errMsg });\n\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\treturn;\n\t\t\t\t\t\t}\n\n\t\t\t\t\t\tconst reader = resp.body.getReader();\n\t\t\t\t\t\tconst decoder = new TextDecoder();\n\t\t\t\t\t\tlet buf = '';\n\t\t\t\t\t\tlet aiMsg = null;\n\t\t\t\t\t\tlet anyResponse = false;\n\n\t\t\t\t\t\twhile (true) {\n\t\t\t\t\t\t\tconst { done, value } = await reader.read();\n\t\t\t\t\t\t\tif (done) break;\n\t\t\t\t\t\t\tbuf += decoder.decode(value, { stream: true });\n\t\t\t\t\t\t\tconst parts = buf.split('\\n\\n');\n\t\t\t\t\t\t\tbuf = parts.pop() || '';\n\t\t\t\t\t\t\tfor (const part of parts) {\n\t\t\t\t\t\t\t\tlet event = 'message', data = '';\n\t\t\t\t\t\t\t\tfor (const line of part.split('\\n')) {\n\t\t\t\t\t\t\t\t\tif (line.startsWith('event: ')) event = line.slice(7).trim();\n\t\t\t\t\t\t\t\t\telse if (line.startsWith('data: ')) data = line.slice(6);\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\tif (!data) continue;\n\t\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\t\tconst d = JSON.parse(data);\n\t\t\t\t\t\t\t\t\tif (event === 'answer') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tif (!aiMsg) {\n\t\t\t\t\t\t\t\t\t\t\tconst raw = d.text || '';\n\t\t\t\t\t\t\t\t\t\t\taiMsg = { role: 'ai', type: 'text', text: raw, html: marked.parse(raw) };\n\t\t\t\t\t\t\t\t\t\t\tthis.messages.push(aiMsg);\n\t\t\t\t\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\t\t\t\t\taiMsg.text = (aiMsg.text || '') + (d.text || '');\n\t\t\t\t\t\t\t\t\t\t\taiMsg.html = marked.parse(aiMsg.text);\n\t\t\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'clarification') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '❓ ' + (d.question || '') });\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'action_card') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({\n\t\t\t\t\t\t\t\t\t\t\trole: 'ai', type: 'action_card',\n\t\t\t\t\t\t\t\t\t\t\ttoken: d.token, tool: d.tool, args: d.args,\n\t\t\t\t\t\t\t\t\t\t\tstatus: 'pending',\n\t\t\t\t\t\t\t\t\t\t});\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'proposal') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({\n\t\t\t\t\t\t\t\t\t\t\trole: 'ai', type: 'proposal',\n\t\t\t\t\t\t\t\t\t\t\ttoken: d.token, proposal: d.proposal,\n\t\t\t\t\t\t\t\t\t\t\tstatus: 'pending',\n\t\t\t\t\t\t\t\t\t\t});\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t} else if (event === 'error') {\n\t\t\t\t\t\t\t\t\t\tanyResponse = true;\n\t\t\t\t\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ ' + (d.message || 'Error') });\n\t\t\t\t\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t\t} catch(e) { console.error('SSE parse error:', e); }\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t}\n\t\t\t\t\tif (!anyResponse) {\n\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: 'No response received. Please try again.', html: 'No response received. Please try again.' });\n\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t}\n\t\t\t\t\t} catch(err) {\n\t\t\t\t\t\tthis.messages.push({ role: 'ai', type: 'text', text: '⚠ Connection error: ' + err.message });\n\t\t\t\t\t\tthis.saveHistory();\n\t\t\t\t\t} finally {\n\t\t\t\t\t\tthis.sending = false;\n\t\t\t\t\t\tthis.$nextTick(() => this.scrollToBottom());\n\t\t\t\t\t}\n\t\t\t\t},\n\n\t\t\t\tasync confirmAction(msg, action) {\n\t\t\t\t\tmsg.status = action === 'confirm' ? 'confirming' : 'cancelling';\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst resp = await fetch('/chat/confirm', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\t\t\tbody: JSON.stringify({ token: msg.token, action }),\n\t\t\t\t\t\t});\n\t\t\t\t\t\tconst data = await resp.json();\n\t\t\t\t\t\tif (action === 'cancel') {\n\t\t\t\t\t\t\tmsg.status = 'cancelled';\n\t\t\t\t\t\t} else if (resp.ok && data.ok) {\n\t\t\t\t\t\t\tmsg.status = 'confirmed';\n\t\t\t\t\t\t\tconst result = data.result;\n\t\t\t\t\t\t\tmsg.resultText = data.message || (result && result.message) || 'Done.';\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\tmsg.status = 'error';\n\t\t\t\t\t\t\tmsg.resultText = data.error || 'Failed.';\n\t\t\t\t\t\t}\n\t\t\t\t\t} catch(e) {\n\t\t\t\t\t\tmsg.status = 'error';\n\t\t\t\t\t\tmsg.resultText = 'Network error.';\n\t\t\t\t\t}\n\t\t\t\t\tthis.saveHistory();\n\t\t\t\t},\n\n\t\t\t\tamendAction(msg) {\n\t\t\t\t\tthis.input = (msg.proposal && msg.proposal.summary)\n\t\t\t\t\t\t? 'Please revise: ' + msg.proposal.summary\n\t\t\t\t\t\t: '';\n\t\t\t\t\tthis.confirmAction(msg, 'cancel');\n\t\t\t\t\tthis.$nextTick(() => {\n\t\t\t\t\t\tconst ta = document.querySelector('textarea');\n\t\t\t\t\t\tif (ta) ta.focus();\n\t\t\t\t\t});\n\t\t\t\t},\n\t\t\t};\n\t\t}\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
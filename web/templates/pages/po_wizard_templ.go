@@ -66,51 +66,110 @@ func POWizard(d layouts.AppLayoutData, vendors *app.VendorsResult, products *app
 				return templ_7745c5c3_Err
 			}
 			for _, v := range vendors.Vendors {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<option value=\"")
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				var templ_7745c5c3_Var4 string
-				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
-				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 43, Col: 31}
-				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\">")
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				var templ_7745c5c3_Var5 string
-				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
-				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 43, Col: 42}
-				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " — ")
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				var templ_7745c5c3_Var6 string
-				templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(v.Name)
-				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 43, Col: 57}
-				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</option>")
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
+				if v.Currency != nil {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<option value=\"")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var4 string
+					templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 44, Col: 32}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var5 string
+					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 44, Col: 43}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " — ")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var6 string
+					templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(v.Name)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 44, Col: 58}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " (")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var7 string
+					templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(*v.Currency)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 44, Col: 75}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, ")</option>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				} else {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<option value=\"")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var8 string
+					templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 46, Col: 32}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var9 string
+					templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(v.Code)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 46, Col: 43}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, " — ")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var10 string
+					templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(v.Name)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/po_wizard.templ`, Line: 46, Col: 58}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</option>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</select></div><!-- PO date --><div><label for=\"po_date\" class=\"block text-xs font-medium text-slate-600 mb-1\">PO Date</label> <input id=\"po_date\" type=\"date\" name=\"po_date\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Notes --><div class=\"sm:col-span-2\"><label for=\"notes\" class=\"block text-xs font-medium text-slate-600 mb-1\">Notes</label> <input id=\"notes\" type=\"text\" name=\"notes\" placeholder=\"Optional notes…\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div><!-- Line items --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-3\"><div class=\"flex items-center justify-between border-b border-gray-100 pb-3\"><h2 class=\"font-semibold text-slate-700 text-sm\">PO Lines</h2><button type=\"button\" x-on:click=\"addLine()\" class=\"px-3 py-1 text-xs font-medium bg-slate-100 hover:bg-slate-200 text-slate-700 rounded-lg transition-colors\">+ Add Line</button></div><div class=\"space-y-4\"><template x-for=\"(line, idx) in lines\" :key=\"idx\"><div class=\"border border-gray-100 rounded-xl p-3 space-y-2 bg-gray-50\"><!-- Line type toggle --><div class=\"flex items-center gap-2\"><span class=\"text-xs font-medium text-slate-500\">Type:</span> <label class=\"flex items-center gap-1 text-xs cursor-pointer\"><input type=\"radio\" :name=\"'line_type[' + idx + ']'\" value=\"goods\" x-model=\"line.type\" class=\"accent-slate-700\"> <span>Goods</span></label> <label class=\"flex items-center gap-1 text-xs cursor-pointer\"><input type=\"radio\" :name=\"'line_type[' + idx + ']'\" value=\"service\" x-model=\"line.type\" class=\"accent-slate-700\"> <span>Service/Expense</span></label> <button type=\"button\" x-show=\"lines.length > 1\" x-on:click=\"removeLine(idx)\" class=\"ml-auto text-slate-400 hover:text-red-500 transition-colors text-lg leading-none\" title=\"Remove line\">×</button></div><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-2\"><!-- Goods: product select --><div x-show=\"line.type === 'goods'\"><label class=\"block text-xs text-slate-500 mb-1\">Product *</label> <select :name=\"'line_product_code[' + idx + ']'\" x-model=\"line.productCode\" x-on:change=\"onProductChange(idx)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"><option value=\"\">Select product…</option><template x-for=\"p in products\" :key=\"p.code\"><option :value=\"p.code\" x-text=\"p.code + ' — ' + p.name\"></option></template></select></div><!-- Service: description --><div x-show=\"line.type === 'service'\"><label class=\"block text-xs text-slate-500 mb-1\">Description *</label> <input type=\"text\" :name=\"'line_description[' + idx + ']'\" x-model=\"line.description\" placeholder=\"Service description\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Service: expense account --><div x-show=\"line.type === 'service'\"><label class=\"block text-xs text-slate-500 mb-1\">Expense Account</label> <input type=\"text\" :name=\"'line_expense_account[' + idx + ']'\" x-model=\"line.expenseAccount\" placeholder=\"e.g. 6100\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm font-mono text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div><!-- Goods description (optional) --><div x-show=\"line.type === 'goods'\"><label class=\"block text-xs text-slate-500 mb-1\">Description (optional)</label> <input type=\"text\" :name=\"'line_description[' + idx + ']'\" x-model=\"line.description\" placeholder=\"Override product name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Qty + cost + total --><div class=\"flex gap-2 items-end\"><div class=\"w-24 flex-shrink-0\"><label class=\"block text-xs text-slate-500 mb-1\">Quantity *</label> <input type=\"number\" :name=\"'line_quantity[' + idx + ']'\" x-model=\"line.quantity\" placeholder=\"Qty\" min=\"0.01\" step=\"0.01\" required class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div class=\"w-32 flex-shrink-0\"><label class=\"block text-xs text-slate-500 mb-1\">Unit Cost *</label> <input type=\"number\" :name=\"'line_unit_cost[' + idx + ']'\" x-model=\"line.unitCost\" placeholder=\"Cost\" min=\"0\" step=\"0.01\" required class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div class=\"flex-1 hidden sm:block\"><label class=\"block text-xs text-slate-500 mb-1\">Line Total</label><div class=\"border border-gray-100 bg-white rounded-lg px-3 py-2 text-sm font-mono text-right text-slate-700\"><span x-text=\"lineTotal(line)\"></span></div></div></div></div></template></div><!-- PO total --><div class=\"pt-3 border-t border-gray-100 text-right\"><span class=\"text-sm text-slate-500 mr-3\">PO Total</span> <span class=\"font-bold font-mono text-slate-900 text-base\" x-text=\"poTotal()\"></span></div></div><!-- Submit --><div class=\"flex items-center justify-end gap-3\"><a href=\"/purchases/orders\" class=\"px-4 py-2 text-sm text-slate-600 hover:text-slate-900 transition-colors\">Cancel</a> <button type=\"submit\" class=\"px-5 py-2 text-sm font-medium bg-slate-800 hover:bg-slate-700 text-white rounded-lg transition-colors\">Create Draft PO</button></div></form></div><script>\r\n\t\t\tfunction poWizard(products) {\r\n\t\t\t\treturn {\r\n\t\t\t\t\tproducts: products,\r\n\t\t\t\t\tlines: [{ type: 'goods', productCode: '', description: '', quantity: '', unitCost: '', expenseAccount: '' }],\r\n\t\t\t\t\taddLine() {\r\n\t\t\t\t\t\tthis.lines.push({ type: 'goods', productCode: '', description: '', quantity: '', unitCost: '', expenseAccount: '' });\r\n\t\t\t\t\t},\r\n\t\t\t\t\tremoveLine(idx) {\r\n\t\t\t\t\t\tthis.lines.splice(idx, 1);\r\n\t\t\t\t\t},\r\n\t\t\t\t\tonProductChange(idx) {\r\n\t\t\t\t\t\tconst p = this.products.find(p => p.code === this.lines[idx].productCode);\r\n\t\t\t\t\t\tif (p && !this.lines[idx].unitCost) {\r\n\t\t\t\t\t\t\tthis.lines[idx].unitCost = p.unitCost;\r\n\t\t\t\t\t\t}\r\n\t\t\t\t\t},\r\n\t\t\t\t\tlineTotal(line) {\r\n\t\t\t\t\t\tconst qty = parseFloat(line.quantity) || 0;\r\n\t\t\t\t\t\tconst cost = parseFloat(line.unitCost) || 0;\r\n\t\t\t\t\t\treturn (qty * cost).toFixed(2);\r\n\t\t\t\t\t},\r\n\t\t\t\t\tpoTotal() {\r\n\t\t\t\t\t\treturn this.lines.reduce((sum, l) => {\r\n\t\t\t\t\t\t\treturn sum + (parseFloat(l.quantity) || 0) * (parseFloat(l.unitCost) || 0);\r\n\t\t\t\t\t\t}, 0).toFixed(2);\r\n\t\t\t\t\t}\r\n\t\t\t\t};\r\n\t\t\t}\r\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</select></div><!-- PO date --><div><label for=\"po_date\" class=\"block text-xs font-medium text-slate-600 mb-1\">PO Date</label> <input id=\"po_date\" type=\"date\" name=\"po_date\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Currency --><div><label for=\"currency\" class=\"block text-xs font-medium text-slate-600 mb-1\">Currency</label> <input id=\"currency\" type=\"text\" name=\"currency\" maxlength=\"3\" placeholder=\"Vendor default\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 uppercase focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Exchange rate --><div><label for=\"exchange_rate\" class=\"block text-xs font-medium text-slate-600 mb-1\">Exchange Rate</label> <input id=\"exchange_rate\" type=\"number\" name=\"exchange_rate\" step=\"0.000001\" min=\"0\" placeholder=\"Base per unit — foreign currency only\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Notes --><div class=\"sm:col-span-2\"><label for=\"notes\" class=\"block text-xs font-medium text-slate-600 mb-1\">Notes</label> <input id=\"notes\" type=\"text\" name=\"notes\" placeholder=\"Optional notes…\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div><!-- Line items --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-3\"><div class=\"flex items-center justify-between border-b border-gray-100 pb-3\"><h2 class=\"font-semibold text-slate-700 text-sm\">PO Lines</h2><button type=\"button\" x-on:click=\"addLine()\" class=\"px-3 py-1 text-xs font-medium bg-slate-100 hover:bg-slate-200 text-slate-700 rounded-lg transition-colors\">+ Add Line</button></div><div class=\"space-y-4\"><template x-for=\"(line, idx) in lines\" :key=\"idx\"><div class=\"border border-gray-100 rounded-xl p-3 space-y-2 bg-gray-50\"><!-- Line type toggle --><div class=\"flex items-center gap-2\"><span class=\"text-xs font-medium text-slate-500\">Type:</span> <label class=\"flex items-center gap-1 text-xs cursor-pointer\"><input type=\"radio\" :name=\"'line_type[' + idx + ']'\" value=\"goods\" x-model=\"line.type\" class=\"accent-slate-700\"> <span>Goods</span></label> <label class=\"flex items-center gap-1 text-xs cursor-pointer\"><input type=\"radio\" :name=\"'line_type[' + idx + ']'\" value=\"service\" x-model=\"line.type\" class=\"accent-slate-700\"> <span>Service/Expense</span></label> <button type=\"button\" x-show=\"lines.length > 1\" x-on:click=\"removeLine(idx)\" class=\"ml-auto text-slate-400 hover:text-red-500 transition-colors text-lg leading-none\" title=\"Remove line\">×</button></div><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-2\"><!-- Goods: product select --><div x-show=\"line.type === 'goods'\"><label class=\"block text-xs text-slate-500 mb-1\">Product *</label> <select :name=\"'line_product_code[' + idx + ']'\" x-model=\"line.productCode\" x-on:change=\"onProductChange(idx)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"><option value=\"\">Select product…</option><template x-for=\"p in products\" :key=\"p.code\"><option :value=\"p.code\" x-text=\"p.code + ' — ' + p.name\"></option></template></select></div><!-- Service: description --><div x-show=\"line.type === 'service'\"><label class=\"block text-xs text-slate-500 mb-1\">Description *</label> <input type=\"text\" :name=\"'line_description[' + idx + ']'\" x-model=\"line.description\" placeholder=\"Service description\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Service: expense account --><div x-show=\"line.type === 'service'\"><label class=\"block text-xs text-slate-500 mb-1\">Expense Account</label> <input type=\"text\" :name=\"'line_expense_account[' + idx + ']'\" x-model=\"line.expenseAccount\" placeholder=\"e.g. 6100\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm font-mono text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div><!-- Goods description (optional) --><div x-show=\"line.type === 'goods'\"><label class=\"block text-xs text-slate-500 mb-1\">Description (optional)</label> <input type=\"text\" :name=\"'line_description[' + idx + ']'\" x-model=\"line.description\" placeholder=\"Override product name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Qty + cost + total --><div class=\"flex gap-2 items-end\"><div class=\"w-24 flex-shrink-0\"><label class=\"block text-xs text-slate-500 mb-1\">Quantity *</label> <input type=\"number\" :name=\"'line_quantity[' + idx + ']'\" x-model=\"line.quantity\" placeholder=\"Qty\" min=\"0.01\" step=\"0.01\" required class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div class=\"w-32 flex-shrink-0\"><label class=\"block text-xs text-slate-500 mb-1\">Unit Cost *</label> <input type=\"number\" :name=\"'line_unit_cost[' + idx + ']'\" x-model=\"line.unitCost\" placeholder=\"Cost\" min=\"0\" step=\"0.01\" required class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div class=\"flex-1 hidden sm:block\"><label class=\"block text-xs text-slate-500 mb-1\">Line Total</label><div class=\"border border-gray-100 bg-white rounded-lg px-3 py-2 text-sm font-mono text-right text-slate-700\"><span x-text=\"lineTotal(line)\"></span></div></div></div></div></template></div><!-- PO total --><div class=\"pt-3 border-t border-gray-100 text-right\"><span class=\"text-sm text-slate-500 mr-3\">PO Total</span> <span class=\"font-bold font-mono text-slate-900 text-base\" x-text=\"poTotal()\"></span></div></div><!-- Submit --><div class=\"flex items-center justify-end gap-3\"><a href=\"/purchases/orders\" class=\"px-4 py-2 text-sm text-slate-600 hover:text-slate-900 transition-colors\">Cancel</a> <button type=\"submit\" class=\"px-5 py-2 text-sm font-medium bg-slate-800 hover:bg-slate-700 text-white rounded-lg transition-colors\">Create Draft PO</button></div></form></div><script>\n\t\t\tfunction poWizard(products) {\n\t\t\t\treturn {\n\t\t\t\t\tproducts: products,\n\t\t\t\t\tlines: [{ type: 'goods', productCode: '', description: '', quantity: '', unitCost: '', expenseAccount: '' }],\n\t\t\t\t\taddLine() {\n\t\t\t\t\t\tthis.lines.push({ type: 'goods', productCode: '', description: '', quantity: '', unitCost: '', expenseAccount: '' });\n\t\t\t\t\t},\n\t\t\t\t\tremoveLine(idx) {\n\t\t\t\t\t\tthis.lines.splice(idx, 1);\n\t\t\t\t\t},\n\t\t\t\t\tonProductChange(idx) {\n\t\t\t\t\t\tconst p = this.products.find(p => p.code === this.lines[idx].productCode);\n\t\t\t\t\t\tif (p && !this.lines[idx].unitCost) {\n\t\t\t\t\t\t\tthis.lines[idx].unitCost = p.unitCost;\n\t\t\t\t\t\t}\n\t\t\t\t\t},\n\t\t\t\t\tlineTotal(line) {\n\t\t\t\t\t\tconst qty = parseFloat(line.quantity) || 0;\n\t\t\t\t\t\tconst cost = parseFloat(line.unitCost) || 0;\n\t\t\t\t\t\treturn (qty * cost).toFixed(2);\n\t\t\t\t\t},\n\t\t\t\t\tpoTotal() {\n\t\t\t\t\t\treturn this.lines.reduce((sum, l) => {\n\t\t\t\t\t\t\treturn sum + (parseFloat(l.quantity) || 0) * (parseFloat(l.unitCost) || 0);\n\t\t\t\t\t\t}, 0).toFixed(2);\n\t\t\t\t\t}\n\t\t\t\t};\n\t\t\t}\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
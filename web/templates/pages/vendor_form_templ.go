@@ -8,7 +8,11 @@ package pages
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "accounting-agent/web/templates/layouts"
+import (
+	"accounting-agent/internal/core"
+	"accounting-agent/web/templates/layouts"
+	"fmt"
+)
 
 // VendorForm renders the create vendor form.
 func VendorForm(d layouts.AppLayoutData) templ.Component {
@@ -44,7 +48,15 @@ func VendorForm(d layouts.AppLayoutData) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-2xl space-y-5\"><!-- Back link --><a href=\"/purchases/vendors\" class=\"inline-flex items-center gap-1 text-sm text-slate-500 hover:text-slate-800 transition-colors\">← Vendors</a><div><h1 class=\"text-2xl font-bold text-slate-900\">New Vendor</h1><p class=\"text-sm text-slate-500 mt-0.5\">Create a new vendor record for accounts payable.</p></div><!-- Form --><form action=\"/purchases/vendors/new\" method=\"POST\" class=\"space-y-5\"><!-- Basic info --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Vendor Details</h2><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- Code --><div><label for=\"code\" class=\"block text-xs font-medium text-slate-600 mb-1\">Code *</label> <input id=\"code\" type=\"text\" name=\"code\" required placeholder=\"e.g. V004\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Name --><div><label for=\"name\" class=\"block text-xs font-medium text-slate-600 mb-1\">Name *</label> <input id=\"name\" type=\"text\" name=\"name\" required placeholder=\"Vendor company name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Contact person --><div><label for=\"contact_person\" class=\"block text-xs font-medium text-slate-600 mb-1\">Contact Person</label> <input id=\"contact_person\" type=\"text\" name=\"contact_person\" placeholder=\"Full name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Email --><div><label for=\"email\" class=\"block text-xs font-medium text-slate-600 mb-1\">Email</label> <input id=\"email\" type=\"email\" name=\"email\" placeholder=\"vendor@example.com\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Phone --><div><label for=\"phone\" class=\"block text-xs font-medium text-slate-600 mb-1\">Phone</label> <input id=\"phone\" type=\"text\" name=\"phone\" placeholder=\"+91 98765 43210\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Payment terms --><div><label for=\"payment_terms_days\" class=\"block text-xs font-medium text-slate-600 mb-1\">Payment Terms (days)</label> <input id=\"payment_terms_days\" type=\"number\" name=\"payment_terms_days\" value=\"30\" min=\"0\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div><!-- Address --><div><label for=\"address\" class=\"block text-xs font-medium text-slate-600 mb-1\">Address</label> <textarea id=\"address\" name=\"address\" rows=\"2\" placeholder=\"Street, City, State, PIN\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400 resize-none\"></textarea></div></div><!-- Accounting --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Accounting Defaults</h2><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- AP account --><div><label for=\"ap_account_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">AP Account Code</label> <input id=\"ap_account_code\" type=\"text\" name=\"ap_account_code\" value=\"2000\" placeholder=\"2000\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Default expense account --><div><label for=\"default_expense_account_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">Default Expense Account</label> <input id=\"default_expense_account_code\" type=\"text\" name=\"default_expense_account_code\" placeholder=\"e.g. 6100 (optional)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div><!-- Submit --><div class=\"flex items-center justify-end gap-3\"><a href=\"/purchases/vendors\" class=\"px-4 py-2 text-sm text-slate-600 hover:text-slate-900 transition-colors\">Cancel</a> <button type=\"submit\" class=\"px-5 py-2 text-sm font-medium bg-slate-800 hover:bg-slate-700 text-white rounded-lg transition-colors\">Create Vendor</button></div></form></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-2xl space-y-5\"><!-- Back link --><a href=\"/purchases/vendors\" class=\"inline-flex items-center gap-1 text-sm text-slate-500 hover:text-slate-800 transition-colors\">← Vendors</a><div><h1 class=\"text-2xl font-bold text-slate-900\">New Vendor</h1><p class=\"text-sm text-slate-500 mt-0.5\">Create a new vendor record for accounts payable.</p></div><!-- Form --><form action=\"/purchases/vendors/new\" method=\"POST\" class=\"space-y-5\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = vendorFields(vendorFormFrom(nil)).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<!-- Submit --><div class=\"flex items-center justify-end gap-3\"><a href=\"/purchases/vendors\" class=\"px-4 py-2 text-sm text-slate-600 hover:text-slate-900 transition-colors\">Cancel</a> <button type=\"submit\" class=\"px-5 py-2 text-sm font-medium bg-slate-800 hover:bg-slate-700 text-white rounded-lg transition-colors\">Create Vendor</button></div></form></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -58,4 +70,290 @@ func VendorForm(d layouts.AppLayoutData) templ.Component {
 	})
 }
 
+// vendorFormData holds the string values shown in the vendor fields.
+type vendorFormData struct {
+	Code                      string
+	Name                      string
+	ContactPerson             string
+	Email                     string
+	Phone                     string
+	Address                   string
+	PaymentTermsDays          string
+	APAccountCode             string
+	DefaultExpenseAccountCode string
+	BankAccountName           string
+	BankAccountNumber         string
+	BankCode                  string
+	Currency                  string
+	PAN                       string
+	TDSSectionCode            string
+}
+
+// vendorFormFrom fills the vendor fields from an existing vendor, or with the create
+// defaults when v is nil.
+func vendorFormFrom(v *core.Vendor) vendorFormData {
+	if v == nil {
+		return vendorFormData{PaymentTermsDays: "30", APAccountCode: "2000"}
+	}
+	str := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return *p
+	}
+	return vendorFormData{
+		Code:                      v.Code,
+		Name:                      v.Name,
+		ContactPerson:             str(v.ContactPerson),
+		Email:                     str(v.Email),
+		Phone:                     str(v.Phone),
+		Address:                   str(v.Address),
+		PaymentTermsDays:          fmt.Sprintf("%d", v.PaymentTermsDays),
+		APAccountCode:             v.APAccountCode,
+		DefaultExpenseAccountCode: str(v.DefaultExpenseAccountCode),
+		BankAccountName:           str(v.BankAccountName),
+		BankAccountNumber:         str(v.BankAccountNumber),
+		BankCode:                  str(v.BankCode),
+		Currency:                  str(v.Currency),
+		PAN:                       str(v.PAN),
+		TDSSectionCode:            str(v.TDSSectionCode),
+	}
+}
+
+// vendorFields renders the editable vendor sections shared by the create and detail pages.
+// The code is read-only once the vendor exists.
+func vendorFields(f vendorFormData) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var3 == nil {
+			templ_7745c5c3_Var3 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<!-- Basic info --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Vendor Details</h2><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- Code --><div><label for=\"code\" class=\"block text-xs font-medium text-slate-600 mb-1\">Code *</label> <input id=\"code\" type=\"text\" name=\"code\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(f.Code)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 103, Col: 19}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\" required")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if f.Code != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " readonly")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " placeholder=\"e.g. V004\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Name --><div><label for=\"name\" class=\"block text-xs font-medium text-slate-600 mb-1\">Name *</label> <input id=\"name\" type=\"text\" name=\"name\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(f.Name)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 117, Col: 19}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\" required placeholder=\"Vendor company name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Contact person --><div><label for=\"contact_person\" class=\"block text-xs font-medium text-slate-600 mb-1\">Contact Person</label> <input id=\"contact_person\" type=\"text\" name=\"contact_person\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(f.ContactPerson)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 130, Col: 28}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\" placeholder=\"Full name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Email --><div><label for=\"email\" class=\"block text-xs font-medium text-slate-600 mb-1\">Email</label> <input id=\"email\" type=\"email\" name=\"email\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var7 string
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(f.Email)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 142, Col: 20}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\" placeholder=\"vendor@example.com\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Phone --><div><label for=\"phone\" class=\"block text-xs font-medium text-slate-600 mb-1\">Phone</label> <input id=\"phone\" type=\"text\" name=\"phone\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var8 string
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(f.Phone)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 154, Col: 20}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\" placeholder=\"+91 98765 43210\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Payment terms --><div><label for=\"payment_terms_days\" class=\"block text-xs font-medium text-slate-600 mb-1\">Payment Terms (days)</label> <input id=\"payment_terms_days\" type=\"number\" name=\"payment_terms_days\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var9 string
+		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(f.PaymentTermsDays)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 166, Col: 31}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\" min=\"0\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div><!-- Address --><div><label for=\"address\" class=\"block text-xs font-medium text-slate-600 mb-1\">Address</label> <textarea id=\"address\" name=\"address\" rows=\"2\" placeholder=\"Street, City, State, PIN\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400 resize-none\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var10 string
+		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(f.Address)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 181, Col: 15}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</textarea></div></div><!-- Accounting --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Accounting Defaults</h2><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><!-- AP account --><div><label for=\"ap_account_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">AP Account Code</label> <input id=\"ap_account_code\" type=\"text\" name=\"ap_account_code\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var11 string
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(f.APAccountCode)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 195, Col: 28}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\" placeholder=\"2000\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Default expense account --><div><label for=\"default_expense_account_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">Default Expense Account</label> <input id=\"default_expense_account_code\" type=\"text\" name=\"default_expense_account_code\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var12 string
+		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(f.DefaultExpenseAccountCode)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 207, Col: 40}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\" placeholder=\"e.g. 6100 (optional)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><!-- Purchasing currency --><div><label for=\"currency\" class=\"block text-xs font-medium text-slate-600 mb-1\">Purchasing Currency</label> <input id=\"currency\" type=\"text\" name=\"currency\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var13 string
+		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(f.Currency)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 219, Col: 23}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\" maxlength=\"3\" placeholder=\"Base currency (e.g. USD)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 uppercase focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div><!-- Tax --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Tax (TDS)</h2><p class=\"text-xs text-slate-500\">With a TDS section set, TDS is withheld from payments once the section's thresholds are crossed. Without a PAN the higher no-PAN rate applies.</p><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><div><label for=\"pan\" class=\"block text-xs font-medium text-slate-600 mb-1\">PAN</label> <input id=\"pan\" type=\"text\" name=\"pan\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var14 string
+		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(f.PAN)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 238, Col: 18}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\" maxlength=\"10\" placeholder=\"AAACT1234F\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 uppercase focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div><label for=\"tds_section_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">TDS Section</label> <input id=\"tds_section_code\" type=\"text\" name=\"tds_section_code\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var15 string
+		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(f.TDSSectionCode)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 250, Col: 29}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\" placeholder=\"e.g. 194C-OTH, 194J-PROF (blank = no TDS)\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 uppercase focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div><!-- Bank details --><div class=\"bg-white rounded-xl border border-gray-200 p-6 space-y-4\"><h2 class=\"font-semibold text-slate-700 text-sm border-b border-gray-100 pb-3\">Bank Details</h2><p class=\"text-xs text-slate-500\">Used in payment run bank files. Optional until the vendor is paid through a payment run.</p><div class=\"grid grid-cols-1 sm:grid-cols-2 gap-4\"><div><label for=\"bank_account_name\" class=\"block text-xs font-medium text-slate-600 mb-1\">Account Name</label> <input id=\"bank_account_name\" type=\"text\" name=\"bank_account_name\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var16 string
+		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(f.BankAccountName)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 268, Col: 30}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\" placeholder=\"Beneficiary name\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div><label for=\"bank_account_number\" class=\"block text-xs font-medium text-slate-600 mb-1\">Account Number / IBAN</label> <input id=\"bank_account_number\" type=\"text\" name=\"bank_account_number\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var17 string
+		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(f.BankAccountNumber)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 279, Col: 32}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\" placeholder=\"e.g. 50100012345678\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div><div><label for=\"bank_code\" class=\"block text-xs font-medium text-slate-600 mb-1\">BIC / IFSC</label> <input id=\"bank_code\" type=\"text\" name=\"bank_code\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var18 string
+		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(f.BankCode)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/vendor_form.templ`, Line: 290, Col: 23}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" placeholder=\"e.g. HDFC0001234\" class=\"w-full border border-gray-200 rounded-lg px-3 py-2 text-sm text-slate-800 focus:outline-none focus:ring-2 focus:ring-slate-400\"></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate